@@ -21,9 +21,12 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"github.com/devtron-labs/devtron/api/apiToken"
+	"github.com/devtron-labs/devtron/api/auditLog"
 	"github.com/devtron-labs/devtron/api/util"
 	"github.com/devtron-labs/devtron/client/telemetry"
 	"github.com/devtron-labs/devtron/otel"
+	auditLog2 "github.com/devtron-labs/devtron/pkg/auditLog"
 	"log"
 	"net/http"
 	"os"
@@ -56,6 +59,9 @@ type App struct {
 	sessionManager2    *authMiddleware.SessionManager
 	OtelTracingService *otel.OtelTracingServiceImpl
 	loggingMiddleware  util.LoggingMiddleware
+	apiTokenMiddleware apiToken.ApiTokenMiddleware
+	auditLogMiddleware auditLog.AuditLogMiddleware
+	auditLogService    auditLog2.AuditLogService
 }
 
 func NewApp(router *router.MuxRouter,
@@ -67,6 +73,9 @@ func NewApp(router *router.MuxRouter,
 	sessionManager2 *authMiddleware.SessionManager,
 	posthogClient *telemetry.PosthogClient,
 	loggingMiddleware util.LoggingMiddleware,
+	apiTokenMiddleware apiToken.ApiTokenMiddleware,
+	auditLogMiddleware auditLog.AuditLogMiddleware,
+	auditLogService auditLog2.AuditLogService,
 ) *App {
 	//check argo connection
 	//todo - check argo-cd version on acd integration installation
@@ -82,6 +91,9 @@ func NewApp(router *router.MuxRouter,
 		posthogClient:      posthogClient,
 		OtelTracingService: otel.NewOtelTracingServiceImpl(Logger),
 		loggingMiddleware:  loggingMiddleware,
+		apiTokenMiddleware: apiTokenMiddleware,
+		auditLogMiddleware: auditLogMiddleware,
+		auditLogService:    auditLogService,
 	}
 	return app
 }
@@ -97,8 +109,10 @@ func (app *App) Start() {
 	app.MuxRouter.Init()
 	//authEnforcer := casbin2.Create()
 
-	server := &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: authMiddleware.Authorizer(app.sessionManager2, user.WhitelistChecker)(app.MuxRouter.Router)}
+	server := &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: user.ScimTokenHandler(authMiddleware.Authorizer(app.sessionManager2, user.WhitelistChecker)(app.MuxRouter.Router))}
+	app.MuxRouter.Router.Use(app.apiTokenMiddleware.ApiTokenMiddleware)
 	app.MuxRouter.Router.Use(app.loggingMiddleware.LoggingMiddleware)
+	app.MuxRouter.Router.Use(app.auditLogMiddleware.AuditLogMiddleware)
 	app.MuxRouter.Router.Use(middleware.PrometheusMiddleware)
 	if tracerProvider != nil {
 		app.MuxRouter.Router.Use(otelmux.Middleware(otel.OTEL_ORCHESTRASTOR_SERVICE_NAME))
@@ -141,6 +155,9 @@ func (app *App) Stop() {
 		app.Logger.Errorw("error in mux router shutdown", "err", err)
 	}
 
+	app.Logger.Infow("writing queued audit events")
+	app.auditLogService.Stop()
+
 	app.OtelTracingService.Shutdown()
 
 	app.Logger.Infow("closing db connection")
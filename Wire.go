@@ -29,6 +29,7 @@ import (
 	appStoreDeployment "github.com/devtron-labs/devtron/api/appStore/deployment"
 	appStoreDiscover "github.com/devtron-labs/devtron/api/appStore/discover"
 	appStoreValues "github.com/devtron-labs/devtron/api/appStore/values"
+	"github.com/devtron-labs/devtron/api/auditLog"
 	chartRepo "github.com/devtron-labs/devtron/api/chartRepo"
 	"github.com/devtron-labs/devtron/api/cluster"
 	"github.com/devtron-labs/devtron/api/connector"
@@ -106,9 +107,19 @@ import (
 	repository7 "github.com/devtron-labs/devtron/pkg/kubernetesResourceAuditLogs/repository"
 	"github.com/devtron-labs/devtron/pkg/notifier"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/devtron-labs/devtron/pkg/pipeline/artifactOutput"
+	repository15 "github.com/devtron-labs/devtron/pkg/pipeline/artifactOutput/repository"
+	"github.com/devtron-labs/devtron/pkg/pipeline/buildCache"
+	repository13 "github.com/devtron-labs/devtron/pkg/pipeline/buildCache/repository"
+	"github.com/devtron-labs/devtron/pkg/pipeline/buildMatrix"
+	repository12 "github.com/devtron-labs/devtron/pkg/pipeline/buildMatrix/repository"
 	history3 "github.com/devtron-labs/devtron/pkg/pipeline/history"
 	repository3 "github.com/devtron-labs/devtron/pkg/pipeline/history/repository"
 	repository5 "github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	"github.com/devtron-labs/devtron/pkg/pipeline/stepStatus"
+	repository14 "github.com/devtron-labs/devtron/pkg/pipeline/stepStatus/repository"
+	"github.com/devtron-labs/devtron/pkg/pipeline/testReport"
+	repository11 "github.com/devtron-labs/devtron/pkg/pipeline/testReport/repository"
 	"github.com/devtron-labs/devtron/pkg/plugin"
 	repository6 "github.com/devtron-labs/devtron/pkg/plugin/repository"
 	"github.com/devtron-labs/devtron/pkg/projectManagementService/jira"
@@ -138,6 +149,8 @@ func InitializeApp() (*App, error) {
 		AuthWireSet,
 		util4.NewK8sUtil,
 		user.UserWireSet,
+		user.ScimWireSet,
+		auditLog.AuditLogWireSet,
 		sso.SsoConfigWireSet,
 		cluster.ClusterWireSet,
 		dashboard.DashboardWireSet,
@@ -594,6 +607,36 @@ func InitializeApp() (*App, error) {
 		wire.Bind(new(router.TestSuitRouter), new(*router.TestSuitRouterImpl)),
 		restHandler.NewTestSuitRestHandlerImpl,
 		wire.Bind(new(restHandler.TestSuitRestHandler), new(*restHandler.TestSuitRestHandlerImpl)),
+		restHandler.NewTestReportRestHandlerImpl,
+		wire.Bind(new(restHandler.TestReportRestHandler), new(*restHandler.TestReportRestHandlerImpl)),
+		testReport.NewTestReportServiceImpl,
+		wire.Bind(new(testReport.TestReportService), new(*testReport.TestReportServiceImpl)),
+		repository11.NewTestReportRepositoryImpl,
+		wire.Bind(new(repository11.TestReportRepository), new(*repository11.TestReportRepositoryImpl)),
+
+		restHandler.NewBuildMatrixRestHandlerImpl,
+		wire.Bind(new(restHandler.BuildMatrixRestHandler), new(*restHandler.BuildMatrixRestHandlerImpl)),
+		buildMatrix.NewBuildMatrixServiceImpl,
+		wire.Bind(new(buildMatrix.BuildMatrixService), new(*buildMatrix.BuildMatrixServiceImpl)),
+		buildMatrix.NewManifestListClientImpl,
+		wire.Bind(new(buildMatrix.ManifestListClient), new(*buildMatrix.ManifestListClientImpl)),
+		repository12.NewBuildMatrixRepositoryImpl,
+		wire.Bind(new(repository12.BuildMatrixRepository), new(*repository12.BuildMatrixRepositoryImpl)),
+
+		restHandler.NewBuildCacheRestHandlerImpl,
+		wire.Bind(new(restHandler.BuildCacheRestHandler), new(*restHandler.BuildCacheRestHandlerImpl)),
+		buildCache.NewBuildCacheServiceImpl,
+		wire.Bind(new(buildCache.BuildCacheService), new(*buildCache.BuildCacheServiceImpl)),
+		repository13.NewBuildCacheRepositoryImpl,
+		wire.Bind(new(repository13.BuildCacheRepository), new(*repository13.BuildCacheRepositoryImpl)),
+		stepStatus.NewStepStatusServiceImpl,
+		wire.Bind(new(stepStatus.StepStatusService), new(*stepStatus.StepStatusServiceImpl)),
+		repository14.NewStepStatusRepositoryImpl,
+		wire.Bind(new(repository14.StepStatusRepository), new(*repository14.StepStatusRepositoryImpl)),
+		artifactOutput.NewArtifactOutputServiceImpl,
+		wire.Bind(new(artifactOutput.ArtifactOutputService), new(*artifactOutput.ArtifactOutputServiceImpl)),
+		repository15.NewArtifactOutputRepositoryImpl,
+		wire.Bind(new(repository15.ArtifactOutputRepository), new(*repository15.ArtifactOutputRepositoryImpl)),
 
 		router.NewImageScanRouterImpl,
 		wire.Bind(new(router.ImageScanRouter), new(*router.ImageScanRouterImpl)),
@@ -634,6 +677,12 @@ func InitializeApp() (*App, error) {
 		router.NewGitOpsConfigRouterImpl,
 		wire.Bind(new(router.GitOpsConfigRouter), new(*router.GitOpsConfigRouterImpl)),
 		restHandler.NewGitOpsConfigRestHandlerImpl,
+		app.NewGitOpsRepoMigrationServiceImpl,
+		wire.Bind(new(app.GitOpsRepoMigrationService), new(*app.GitOpsRepoMigrationServiceImpl)),
+		app.NewGitOpsDriftServiceImpl,
+		wire.Bind(new(app.GitOpsDriftService), new(*app.GitOpsDriftServiceImpl)),
+		restHandler.NewGitOpsDriftRestHandlerImpl,
+		wire.Bind(new(restHandler.GitOpsDriftRestHandler), new(*restHandler.GitOpsDriftRestHandlerImpl)),
 		wire.Bind(new(restHandler.GitOpsConfigRestHandler), new(*restHandler.GitOpsConfigRestHandlerImpl)),
 		gitops.NewGitOpsConfigServiceImpl,
 		wire.Bind(new(gitops.GitOpsConfigService), new(*gitops.GitOpsConfigServiceImpl)),
@@ -814,6 +863,11 @@ func InitializeApp() (*App, error) {
 		cron.NewCiTriggerCronImpl,
 		wire.Bind(new(cron.CiTriggerCron), new(*cron.CiTriggerCronImpl)),
 
+		cron.NewGitOpsPullRequestCronImpl,
+		wire.Bind(new(cron.GitOpsPullRequestCron), new(*cron.GitOpsPullRequestCronImpl)),
+		cron.NewGitOpsDriftDetectionCronImpl,
+		wire.Bind(new(cron.GitOpsDriftDetectionCron), new(*cron.GitOpsDriftDetectionCronImpl)),
+
 		restHandler.NewPipelineStatusTimelineRestHandlerImpl,
 		wire.Bind(new(restHandler.PipelineStatusTimelineRestHandler), new(*restHandler.PipelineStatusTimelineRestHandlerImpl)),
 
@@ -830,6 +884,10 @@ func InitializeApp() (*App, error) {
 		wire.Bind(new(repository.UserAttributesRepository), new(*repository.UserAttributesRepositoryImpl)),
 		pipelineConfig.NewPipelineStatusTimelineRepositoryImpl,
 		wire.Bind(new(pipelineConfig.PipelineStatusTimelineRepository), new(*pipelineConfig.PipelineStatusTimelineRepositoryImpl)),
+		pipelineConfig.NewGitOpsPullRequestRepositoryImpl,
+		wire.Bind(new(pipelineConfig.GitOpsPullRequestRepository), new(*pipelineConfig.GitOpsPullRequestRepositoryImpl)),
+		pipelineConfig.NewGitOpsDriftReportRepositoryImpl,
+		wire.Bind(new(pipelineConfig.GitOpsDriftReportRepository), new(*pipelineConfig.GitOpsDriftReportRepositoryImpl)),
 		wire.Bind(new(pipeline.DeploymentConfigService), new(*pipeline.DeploymentConfigServiceImpl)),
 		pipeline.NewDeploymentConfigServiceImpl,
 		pipelineConfig.NewCiTemplateOverrideRepositoryImpl,
@@ -869,6 +927,10 @@ func InitializeApp() (*App, error) {
 
 		kubernetesResourceAuditLogs.Newk8sResourceHistoryServiceImpl,
 		wire.Bind(new(kubernetesResourceAuditLogs.K8sResourceHistoryService), new(*kubernetesResourceAuditLogs.K8sResourceHistoryServiceImpl)),
+		repository7.NewK8sResourceChangeRepositoryImpl,
+		wire.Bind(new(repository7.K8sResourceChangeRepository), new(*repository7.K8sResourceChangeRepositoryImpl)),
+		kubernetesResourceAuditLogs.NewK8sResourceChangeServiceImpl,
+		wire.Bind(new(kubernetesResourceAuditLogs.K8sResourceChangeService), new(*kubernetesResourceAuditLogs.K8sResourceChangeServiceImpl)),
 
 		router.NewResourceGroupingRouterImpl,
 		wire.Bind(new(router.ResourceGroupingRouter), new(*router.ResourceGroupingRouterImpl)),
@@ -0,0 +1,60 @@
+package apiToken
+
+import (
+	"github.com/caarlos0/env/v6"
+	authMiddleware "github.com/devtron-labs/authenticator/middleware"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/apiToken"
+	"github.com/devtron-labs/devtron/util"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type ApiTokenMiddleware interface {
+	ApiTokenMiddleware(next http.Handler) http.Handler
+}
+
+type ApiTokenMiddlewareConfig struct {
+	// TrustedProxyCount is the number of proxies (e.g. load balancer and ingress) in front of orchestrator which append to
+	// X-Forwarded-For, the client ip checked against allowed CIDRs of an api-token is taken from the hop added by the outermost one
+	TrustedProxyCount int `env:"API_TOKEN_TRUSTED_PROXY_COUNT" envDefault:"0"`
+}
+
+type ApiTokenMiddlewareImpl struct {
+	logger                *zap.SugaredLogger
+	apiTokenAccessService apiToken.ApiTokenAccessService
+	config                *ApiTokenMiddlewareConfig
+}
+
+func NewApiTokenMiddlewareImpl(logger *zap.SugaredLogger, apiTokenAccessService apiToken.ApiTokenAccessService) (*ApiTokenMiddlewareImpl, error) {
+	config := &ApiTokenMiddlewareConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing api token middleware config", "err", err)
+		return nil, err
+	}
+	return &ApiTokenMiddlewareImpl{
+		logger:                logger,
+		apiTokenAccessService: apiTokenAccessService,
+		config:                config,
+	}, nil
+}
+
+// ApiTokenMiddleware rejects api-tokens which are rotated out or used from a source ip outside their allowed CIDRs
+func (impl ApiTokenMiddlewareImpl) ApiTokenMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get(authMiddleware.ApiTokenHeaderKey)
+		if len(token) == 0 {
+			token = r.Header.Get("token")
+		}
+		if len(token) > 0 {
+			err := impl.apiTokenAccessService.ValidateApiTokenAccess(token, util.GetClientIPBehindTrustedProxies(r, impl.config.TrustedProxyCount))
+			if err != nil {
+				impl.logger.Warnw("api-token access denied", "urlPath", r.URL.Path, "err", err)
+				common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -38,6 +38,7 @@ type ApiTokenRestHandler interface {
 	CreateApiToken(w http.ResponseWriter, r *http.Request)
 	UpdateApiToken(w http.ResponseWriter, r *http.Request)
 	DeleteApiToken(w http.ResponseWriter, r *http.Request)
+	RotateApiToken(w http.ResponseWriter, r *http.Request)
 	GetAllApiTokensForWebhook(w http.ResponseWriter, r *http.Request)
 }
 
@@ -121,7 +122,7 @@ func (impl ApiTokenRestHandlerImpl) CreateApiToken(w http.ResponseWriter, r *htt
 	}
 
 	// service call
-	res, err := impl.apiTokenService.CreateApiToken(request, userId, impl.checkManagerAuth)
+	res, err := impl.apiTokenService.CreateApiToken(r.Context(), request, userId, impl.checkManagerAuth)
 	if err != nil {
 		impl.logger.Errorw("service err, CreateApiToken", "err", err, "payload", request)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
@@ -171,7 +172,7 @@ func (impl ApiTokenRestHandlerImpl) UpdateApiToken(w http.ResponseWriter, r *htt
 		return
 	}
 
-	res, err := impl.apiTokenService.UpdateApiToken(apiTokenId, request, userId)
+	res, err := impl.apiTokenService.UpdateApiToken(r.Context(), apiTokenId, request, userId)
 	if err != nil {
 		impl.logger.Errorw("service err, UpdateApiToken", "err", err, "apiTokenId", apiTokenId, "request", request)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
@@ -203,7 +204,7 @@ func (impl ApiTokenRestHandlerImpl) DeleteApiToken(w http.ResponseWriter, r *htt
 		return
 	}
 
-	res, err := impl.apiTokenService.DeleteApiToken(apiTokenId, userId)
+	res, err := impl.apiTokenService.DeleteApiToken(r.Context(), apiTokenId, userId)
 	if err != nil {
 		impl.logger.Errorw("service err, DeleteApiToken", "err", err, "apiTokenId", apiTokenId)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
@@ -212,6 +213,48 @@ func (impl ApiTokenRestHandlerImpl) DeleteApiToken(w http.ResponseWriter, r *htt
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
 
+func (impl ApiTokenRestHandlerImpl) RotateApiToken(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+
+	// handle super-admin RBAC
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	// get api-token Id
+	vars := mux.Vars(r)
+	apiTokenId, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		impl.logger.Errorw("request err in getting apiTokenId in RotateApiToken", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	// decode request
+	decoder := json.NewDecoder(r.Body)
+	var request *openapi.RotateApiTokenRequest
+	err = decoder.Decode(&request)
+	if err != nil {
+		impl.logger.Errorw("err in decoding request, RotateApiToken", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	res, err := impl.apiTokenService.RotateApiToken(r.Context(), apiTokenId, request, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, RotateApiToken", "err", err, "apiTokenId", apiTokenId, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
 func (handler ApiTokenRestHandlerImpl) checkManagerAuth(resource, token, object string) bool {
 	if ok := handler.enforcer.Enforce(token, resource, casbin.ActionUpdate, strings.ToLower(object)); !ok {
 		return false
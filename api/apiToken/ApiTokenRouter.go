@@ -21,5 +21,6 @@ func (impl ApiTokenRouterImpl) InitApiTokenRouter(configRouter *mux.Router) {
 	configRouter.Path("").HandlerFunc(impl.apiTokenRestHandler.CreateApiToken).Methods("POST")
 	configRouter.Path("/{id}").HandlerFunc(impl.apiTokenRestHandler.UpdateApiToken).Methods("PUT")
 	configRouter.Path("/{id}").HandlerFunc(impl.apiTokenRestHandler.DeleteApiToken).Methods("DELETE")
+	configRouter.Path("/{id}/rotate").HandlerFunc(impl.apiTokenRestHandler.RotateApiToken).Methods("POST")
 	configRouter.Path("/webhook").HandlerFunc(impl.apiTokenRestHandler.GetAllApiTokensForWebhook).Methods("GET")
 }
@@ -2,6 +2,7 @@ package apiToken
 
 import (
 	"github.com/devtron-labs/devtron/pkg/apiToken"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
 	"github.com/google/wire"
 )
 
@@ -10,8 +11,13 @@ var ApiTokenWireSet = wire.NewSet(
 	wire.Bind(new(apiToken.ApiTokenRepository), new(*apiToken.ApiTokenRepositoryImpl)),
 	apiToken.NewApiTokenServiceImpl,
 	wire.Bind(new(apiToken.ApiTokenService), new(*apiToken.ApiTokenServiceImpl)),
+	apiToken.NewApiTokenAccessServiceImpl,
+	wire.Bind(new(apiToken.ApiTokenAccessService), new(*apiToken.ApiTokenAccessServiceImpl)),
+	wire.Bind(new(casbin.ApiTokenScopeProvider), new(*apiToken.ApiTokenAccessServiceImpl)),
 	NewApiTokenRestHandlerImpl,
 	wire.Bind(new(ApiTokenRestHandler), new(*ApiTokenRestHandlerImpl)),
 	NewApiTokenRouterImpl,
 	wire.Bind(new(ApiTokenRouter), new(*ApiTokenRouterImpl)),
+	NewApiTokenMiddlewareImpl,
+	wire.Bind(new(ApiTokenMiddleware), new(*ApiTokenMiddlewareImpl)),
 )
@@ -0,0 +1,80 @@
+package auditLog
+
+import (
+	"github.com/caarlos0/env/v6"
+	authJwt "github.com/devtron-labs/authenticator/jwt"
+	authMiddleware "github.com/devtron-labs/authenticator/middleware"
+	"github.com/devtron-labs/devtron/api/apiToken"
+	"github.com/devtron-labs/devtron/internal/middleware"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/util"
+	"github.com/golang-jwt/jwt/v4"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type AuditLogMiddleware interface {
+	AuditLogMiddleware(next http.Handler) http.Handler
+}
+
+type AuditLogMiddlewareImpl struct {
+	auditLogService auditLog.AuditLogService
+	// proxyConfig has the count of trusted proxies, the source ip is resolved like it is for api-token CIDR checks
+	proxyConfig *apiToken.ApiTokenMiddlewareConfig
+}
+
+func NewAuditLogMiddlewareImpl(logger *zap.SugaredLogger, auditLogService auditLog.AuditLogService) (*AuditLogMiddlewareImpl, error) {
+	proxyConfig := &apiToken.ApiTokenMiddlewareConfig{}
+	err := env.Parse(proxyConfig)
+	if err != nil {
+		logger.Errorw("error in parsing trusted proxy config for audit log middleware", "err", err)
+		return nil, err
+	}
+	return &AuditLogMiddlewareImpl{
+		auditLogService: auditLogService,
+		proxyConfig:     proxyConfig,
+	}, nil
+}
+
+// AuditLogMiddleware records every mutating request in the audit log, the request is passed to handlers in its
+// context so that events recorded by services with that context get the source ip and url of the request
+func (impl AuditLogMiddlewareImpl) AuditLogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost && r.Method != http.MethodPut && r.Method != http.MethodPatch && r.Method != http.MethodDelete {
+			next.ServeHTTP(w, r)
+			return
+		}
+		token := r.Header.Get(authMiddleware.ApiTokenHeaderKey)
+		if len(token) == 0 {
+			token = r.Header.Get("token")
+		}
+		var emailId string
+		if len(token) > 0 && !user.WhitelistChecker(r.URL.Path) {
+			emailId = getEmailFromVerifiedToken(token)
+		}
+		requestInfo := &auditLog.RequestInfo{
+			EmailId:    emailId,
+			SourceIp:   util.GetClientIPBehindTrustedProxies(r, impl.proxyConfig.TrustedProxyCount),
+			HttpMethod: r.Method,
+			UrlPath:    r.URL.Path,
+		}
+		d := middleware.NewDelegator(w, nil)
+		next.ServeHTTP(d, r.WithContext(auditLog.WithRequestInfo(r.Context(), requestInfo)))
+		impl.auditLogService.RecordRequest(requestInfo, d.Status())
+	})
+}
+
+// getEmailFromVerifiedToken reads the email of a token which the auth middleware wrapping the router has already
+// verified, so it is not verified again for every mutating request
+func getEmailFromVerifiedToken(token string) string {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return ""
+	}
+	email := authJwt.GetField(claims, "email")
+	if sub := authJwt.GetField(claims, "sub"); email == "" && (sub == "admin" || sub == "admin:login") {
+		email = "admin"
+	}
+	return email
+}
@@ -0,0 +1,42 @@
+package auditLog
+
+import (
+	"github.com/devtron-labs/devtron/api/apiToken"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type auditLogServiceStub struct {
+	auditLog.AuditLogService
+	requests []*auditLog.RequestInfo
+}
+
+func (impl *auditLogServiceStub) RecordRequest(requestInfo *auditLog.RequestInfo, status int) {
+	impl.requests = append(impl.requests, requestInfo)
+}
+
+func TestAuditLogMiddleware(t *testing.T) {
+	auditLogService := &auditLogServiceStub{}
+	impl := &AuditLogMiddlewareImpl{auditLogService: auditLogService, proxyConfig: &apiToken.ApiTokenMiddlewareConfig{}}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"email": "admin@example.com"}).SignedString([]byte("secret"))
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPut, "/orchestrator/app", nil)
+	request.RemoteAddr = "203.0.113.7:41000"
+	request.Header.Set("X-Forwarded-For", "10.0.0.1")
+	request.Header.Set("token", token)
+	served := false
+	impl.AuditLogMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served = true
+	})).ServeHTTP(httptest.NewRecorder(), request)
+
+	assert.True(t, served)
+	assert.Len(t, auditLogService.requests, 1)
+	// no proxy is trusted, the forwarded address sent by the client is not used
+	assert.Equal(t, "203.0.113.7", auditLogService.requests[0].SourceIp)
+	assert.Equal(t, "admin@example.com", auditLogService.requests[0].EmailId)
+}
@@ -0,0 +1,202 @@
+package auditLog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type AuditLogRestHandler interface {
+	GetAuditEvents(w http.ResponseWriter, r *http.Request)
+	ExportAuditEvents(w http.ResponseWriter, r *http.Request)
+	GetAuditLogSinkStatus(w http.ResponseWriter, r *http.Request)
+}
+
+type AuditLogRestHandlerImpl struct {
+	logger                *zap.SugaredLogger
+	auditLogService       auditLog.AuditLogService
+	auditLogStreamService auditLog.AuditLogStreamService
+	userService           user.UserService
+	enforcer              casbin.Enforcer
+}
+
+func NewAuditLogRestHandlerImpl(logger *zap.SugaredLogger, auditLogService auditLog.AuditLogService,
+	auditLogStreamService auditLog.AuditLogStreamService, userService user.UserService,
+	enforcer casbin.Enforcer) *AuditLogRestHandlerImpl {
+	return &AuditLogRestHandlerImpl{
+		logger:                logger,
+		auditLogService:       auditLogService,
+		auditLogStreamService: auditLogStreamService,
+		userService:           userService,
+		enforcer:              enforcer,
+	}
+}
+
+func (handler AuditLogRestHandlerImpl) GetAuditEvents(w http.ResponseWriter, r *http.Request) {
+	if !handler.authorize(w, r) {
+		return
+	}
+	filter, err := getAuditEventFilter(r)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.auditLogService.GetEvents(filter)
+	if err != nil {
+		handler.logger.Errorw("service err, GetAuditEvents", "err", err, "filter", filter)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler AuditLogRestHandlerImpl) ExportAuditEvents(w http.ResponseWriter, r *http.Request) {
+	if !handler.authorize(w, r) {
+		return
+	}
+	filter, err := getAuditEventFilter(r)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if len(format) == 0 {
+		format = auditLog.ExportFormatJson
+	}
+	if format != auditLog.ExportFormatJson && format != auditLog.ExportFormatCsv {
+		common.WriteJsonResp(w, fmt.Errorf("unsupported export format '%s'", format), nil, http.StatusBadRequest)
+		return
+	}
+	events, err := handler.auditLogService.ExportEvents(filter)
+	if err != nil {
+		handler.logger.Errorw("service err, ExportAuditEvents", "err", err, "filter", filter)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=audit-log-%s.%s", time.Now().Format("20060102150405"), format))
+	if format == auditLog.ExportFormatCsv {
+		w.Header().Set("Content-Type", "text/csv")
+		err = writeAuditEventsCsv(w, events)
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(events)
+	}
+	if err != nil {
+		handler.logger.Errorw("error in writing audit log export", "err", err, "format", format)
+	}
+}
+
+func (handler AuditLogRestHandlerImpl) GetAuditLogSinkStatus(w http.ResponseWriter, r *http.Request) {
+	if !handler.authorize(w, r) {
+		return
+	}
+	common.WriteJsonResp(w, nil, handler.auditLogStreamService.GetSinkStatus(), http.StatusOK)
+}
+
+func (handler AuditLogRestHandlerImpl) authorize(w http.ResponseWriter, r *http.Request) bool {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return false
+	}
+	// audit log is only available to super-admins
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func getAuditEventFilter(r *http.Request) (*auditLog.AuditEventFilter, error) {
+	v := r.URL.Query()
+	filter := &auditLog.AuditEventFilter{
+		Action:       v.Get("action"),
+		ResourceType: v.Get("resourceType"),
+		ResourceId:   v.Get("resourceId"),
+		EmailId:      v.Get("emailId"),
+	}
+	var err error
+	if userId := v.Get("userId"); len(userId) > 0 {
+		var id int64
+		id, err = strconv.ParseInt(userId, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid userId '%s'", userId)
+		}
+		filter.UserId = int32(id)
+	}
+	if apiTokenId := v.Get("apiTokenId"); len(apiTokenId) > 0 {
+		filter.ApiTokenId, err = strconv.Atoi(apiTokenId)
+		if err != nil {
+			return nil, fmt.Errorf("invalid apiTokenId '%s'", apiTokenId)
+		}
+	}
+	if from := v.Get("from"); len(from) > 0 {
+		filter.From, err = time.Parse(time.RFC3339, from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from '%s', expected RFC3339 time", from)
+		}
+	}
+	if to := v.Get("to"); len(to) > 0 {
+		filter.To, err = time.Parse(time.RFC3339, to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to '%s', expected RFC3339 time", to)
+		}
+	}
+	if offset, err := strconv.Atoi(v.Get("offset")); err == nil && offset > 0 {
+		filter.Offset = offset
+	}
+	if size, err := strconv.Atoi(v.Get("size")); err == nil {
+		filter.Size = size
+	}
+	return filter, nil
+}
+
+func writeAuditEventsCsv(w http.ResponseWriter, events []*auditLog.AuditEventDto) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write([]string{"id", "createdOn", "action", "resourceType", "resourceId", "resourceName", "userId", "emailId",
+		"apiTokenId", "sourceIp", "httpMethod", "urlPath", "responseCode", "diff"})
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		var diff string
+		if len(event.Diff) > 0 {
+			diffJson, err := json.Marshal(event.Diff)
+			if err != nil {
+				return err
+			}
+			diff = string(diffJson)
+		}
+		err = writer.Write([]string{
+			strconv.Itoa(event.Id),
+			event.CreatedOn.Format(time.RFC3339),
+			event.Action,
+			event.ResourceType,
+			event.ResourceId,
+			event.ResourceName,
+			strconv.Itoa(int(event.UserId)),
+			event.EmailId,
+			strconv.Itoa(event.ApiTokenId),
+			event.SourceIp,
+			event.HttpMethod,
+			event.UrlPath,
+			strconv.Itoa(event.ResponseCode),
+			diff,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,23 @@
+package auditLog
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type AuditLogRouter interface {
+	InitAuditLogRouter(auditLogRouter *mux.Router)
+}
+
+type AuditLogRouterImpl struct {
+	auditLogRestHandler AuditLogRestHandler
+}
+
+func NewAuditLogRouterImpl(auditLogRestHandler AuditLogRestHandler) *AuditLogRouterImpl {
+	return &AuditLogRouterImpl{auditLogRestHandler: auditLogRestHandler}
+}
+
+func (router AuditLogRouterImpl) InitAuditLogRouter(auditLogRouter *mux.Router) {
+	auditLogRouter.Path("").HandlerFunc(router.auditLogRestHandler.GetAuditEvents).Methods("GET")
+	auditLogRouter.Path("/export").HandlerFunc(router.auditLogRestHandler.ExportAuditEvents).Methods("GET")
+	auditLogRouter.Path("/sinks").HandlerFunc(router.auditLogRestHandler.GetAuditLogSinkStatus).Methods("GET")
+}
@@ -0,0 +1,24 @@
+package auditLog
+
+import (
+	"github.com/devtron-labs/devtron/pkg/auditLog"
+	"github.com/devtron-labs/devtron/pkg/auditLog/repository"
+	"github.com/google/wire"
+)
+
+var AuditLogWireSet = wire.NewSet(
+	repository.NewAuditEventRepositoryImpl,
+	wire.Bind(new(repository.AuditEventRepository), new(*repository.AuditEventRepositoryImpl)),
+	auditLog.NewAuditLogServiceImpl,
+	wire.Bind(new(auditLog.AuditLogService), new(*auditLog.AuditLogServiceImpl)),
+	repository.NewAuditEventSinkCursorRepositoryImpl,
+	wire.Bind(new(repository.AuditEventSinkCursorRepository), new(*repository.AuditEventSinkCursorRepositoryImpl)),
+	auditLog.NewAuditLogStreamServiceImpl,
+	wire.Bind(new(auditLog.AuditLogStreamService), new(*auditLog.AuditLogStreamServiceImpl)),
+	NewAuditLogRestHandlerImpl,
+	wire.Bind(new(AuditLogRestHandler), new(*AuditLogRestHandlerImpl)),
+	NewAuditLogRouterImpl,
+	wire.Bind(new(AuditLogRouter), new(*AuditLogRouterImpl)),
+	NewAuditLogMiddlewareImpl,
+	wire.Bind(new(AuditLogMiddleware), new(*AuditLogMiddlewareImpl)),
+)
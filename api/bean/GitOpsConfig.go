@@ -12,6 +12,12 @@ type GitOpsConfigDto struct {
 	AzureProjectName     string `json:"azureProjectName"`
 	BitBucketWorkspaceId string `json:"bitBucketWorkspaceId"`
 	BitBucketProjectKey  string `json:"bitBucketProjectKey"`
+	GiteaOrgId           string `json:"giteaOrgId"`
+	// CommitSigningType is GPG or SSH to sign gitops commits, signing key and passphrase are write only
+	CommitSigningType          string `json:"commitSigningType"`
+	CommitSigningKey           string `json:"commitSigningKey,omitempty"`
+	CommitSigningKeyPassphrase string `json:"commitSigningKeyPassphrase,omitempty"`
+	CommitSigningKeyConfigured bool   `json:"commitSigningKeyConfigured"`
 
 	GitRepoName string `json:"gitRepoName"`
 	UserEmailId string `json:"userEmailId"`
@@ -31,4 +37,5 @@ type GitRepoRequestDto struct {
 	AzureProjectName     string `json:"azureProjectName"`
 	BitBucketWorkspaceId string `json:"bitBucketWorkspaceId"`
 	BitBucketProjectKey  string `json:"bitBucketProjectKey"`
+	GiteaOrgId           string `json:"giteaOrgId"`
 }
@@ -30,6 +30,8 @@ import (
 
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/pkg/cluster"
+	"github.com/devtron-labs/devtron/pkg/clusterHealth"
+	"github.com/devtron-labs/devtron/pkg/clusterHealth/bean"
 	delete2 "github.com/devtron-labs/devtron/pkg/delete"
 	"github.com/devtron-labs/devtron/pkg/user"
 	"github.com/devtron-labs/devtron/pkg/user/casbin"
@@ -57,6 +59,8 @@ type ClusterRestHandler interface {
 	GetClusterNamespaces(w http.ResponseWriter, r *http.Request)
 	GetAllClusterNamespaces(w http.ResponseWriter, r *http.Request)
 	FindAllForClusterPermission(w http.ResponseWriter, r *http.Request)
+	GetClusterHealth(w http.ResponseWriter, r *http.Request)
+	GetClusterHealthHistory(w http.ResponseWriter, r *http.Request)
 }
 
 type ClusterRestHandlerImpl struct {
@@ -71,6 +75,7 @@ type ClusterRestHandlerImpl struct {
 	argoUserService           argo.ArgoUserService
 	environmentService        cluster.EnvironmentService
 	clusterRbacService        cluster.ClusterRbacService
+	clusterHealthService      clusterHealth.ClusterHealthService
 }
 
 func NewClusterRestHandlerImpl(clusterService cluster.ClusterService,
@@ -83,7 +88,8 @@ func NewClusterRestHandlerImpl(clusterService cluster.ClusterService,
 	deleteService delete2.DeleteService,
 	argoUserService argo.ArgoUserService,
 	environmentService cluster.EnvironmentService,
-	clusterRbacService cluster.ClusterRbacService) *ClusterRestHandlerImpl {
+	clusterRbacService cluster.ClusterRbacService,
+	clusterHealthService clusterHealth.ClusterHealthService) *ClusterRestHandlerImpl {
 	return &ClusterRestHandlerImpl{
 		clusterService:            clusterService,
 		clusterNoteService:        clusterNoteService,
@@ -96,6 +102,7 @@ func NewClusterRestHandlerImpl(clusterService cluster.ClusterService,
 		argoUserService:           argoUserService,
 		environmentService:        environmentService,
 		clusterRbacService:        clusterRbacService,
+		clusterHealthService:      clusterHealthService,
 	}
 }
 
@@ -573,7 +580,7 @@ func (impl ClusterRestHandlerImpl) DeleteCluster(w http.ResponseWriter, r *http.
 		return
 	}
 	//RBAC enforcer Ends
-	err = impl.deleteService.DeleteCluster(&bean, userId)
+	err = impl.deleteService.DeleteCluster(r.Context(), &bean, userId)
 	if err != nil {
 		impl.logger.Errorw("error in deleting cluster", "err", err, "id", bean.Id, "name", bean.ClusterName)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
@@ -661,3 +668,70 @@ func (impl ClusterRestHandlerImpl) FindAllForClusterPermission(w http.ResponseWr
 	}
 	common.WriteJsonResp(w, err, clusterList, http.StatusOK)
 }
+
+func (impl ClusterRestHandlerImpl) GetClusterHealth(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("token")
+	healths, err := impl.clusterHealthService.GetLatestHealth()
+	if err != nil {
+		impl.logger.Errorw("service err, GetClusterHealth", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+
+	// RBAC enforcer applying
+	result := make([]*bean.ClusterHealth, 0, len(healths))
+	for _, health := range healths {
+		if ok := impl.enforcer.Enforce(token, casbin.ResourceCluster, casbin.ActionGet, strings.ToLower(health.ClusterName)); ok {
+			result = append(result, health)
+		}
+	}
+	//RBAC enforcer Ends
+
+	common.WriteJsonResp(w, nil, result, http.StatusOK)
+}
+
+// GetClusterHealthHistory returns the health checks of the cluster between from and to, the last 24 hours by default
+func (impl ClusterRestHandlerImpl) GetClusterHealthHistory(w http.ResponseWriter, r *http.Request) {
+	clusterId, err := strconv.Atoi(mux.Vars(r)["clusterId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	to := time.Now()
+	v := r.URL.Query()
+	if toParam := v.Get("to"); toParam != "" {
+		if to, err = time.Parse(time.RFC3339, toParam); err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	from := to.Add(-24 * time.Hour)
+	if fromParam := v.Get("from"); fromParam != "" {
+		if from, err = time.Parse(time.RFC3339, fromParam); err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	clusterBean, err := impl.clusterService.FindByIdWithoutConfig(clusterId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetClusterHealthHistory", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceCluster, casbin.ActionGet, strings.ToLower(clusterBean.ClusterName)); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+
+	history, err := impl.clusterHealthService.GetHealthHistory(clusterId, from, to)
+	if err != nil {
+		impl.logger.Errorw("service err, GetClusterHealthHistory", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, history, http.StatusOK)
+}
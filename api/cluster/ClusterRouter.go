@@ -89,4 +89,12 @@ func (impl ClusterRouterImpl) InitClusterRouter(clusterRouter *mux.Router) {
 	clusterRouter.Path("/auth-list").
 		Methods("GET").
 		HandlerFunc(impl.clusterRestHandler.FindAllForClusterPermission)
+
+	clusterRouter.Path("/health").
+		Methods("GET").
+		HandlerFunc(impl.clusterRestHandler.GetClusterHealth)
+
+	clusterRouter.Path("/health/{clusterId}").
+		Methods("GET").
+		HandlerFunc(impl.clusterRestHandler.GetClusterHealthHistory)
 }
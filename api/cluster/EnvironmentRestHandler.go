@@ -20,6 +20,8 @@ package cluster
 import (
 	"context"
 	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/environmentPolicy"
+	bean2 "github.com/devtron-labs/devtron/pkg/environmentPolicy/bean"
 	"github.com/devtron-labs/devtron/pkg/k8s"
 	k8s2 "github.com/devtron-labs/devtron/util/k8s"
 	"net/http"
@@ -55,6 +57,10 @@ type EnvironmentRestHandler interface {
 	GetEnvironmentConnection(w http.ResponseWriter, r *http.Request)
 	DeleteEnvironment(w http.ResponseWriter, r *http.Request)
 	GetCombinedEnvironmentListForDropDownByClusterIds(w http.ResponseWriter, r *http.Request)
+	GetNamespacePolicy(w http.ResponseWriter, r *http.Request)
+	SaveNamespacePolicy(w http.ResponseWriter, r *http.Request)
+	GetNamespacePolicyDrift(w http.ResponseWriter, r *http.Request)
+	AcknowledgeNamespacePolicyDrift(w http.ResponseWriter, r *http.Request)
 }
 
 type EnvironmentRestHandlerImpl struct {
@@ -67,6 +73,7 @@ type EnvironmentRestHandlerImpl struct {
 	deleteService                     delete2.DeleteService
 	k8sUtil                           *k8s2.K8sUtil
 	cfg                               *bean.Config
+	namespacePolicyService            environmentPolicy.NamespacePolicyService
 }
 
 type ClusterReachableResponse struct {
@@ -74,7 +81,8 @@ type ClusterReachableResponse struct {
 	ClusterName      string `json:"clusterName"`
 }
 
-func NewEnvironmentRestHandlerImpl(svc request.EnvironmentService, logger *zap.SugaredLogger, userService user.UserService, validator *validator.Validate, enforcer casbin.Enforcer, deleteService delete2.DeleteService, k8sUtil *k8s2.K8sUtil, k8sCommonService k8s.K8sCommonService) *EnvironmentRestHandlerImpl {
+func NewEnvironmentRestHandlerImpl(svc request.EnvironmentService, logger *zap.SugaredLogger, userService user.UserService, validator *validator.Validate, enforcer casbin.Enforcer, deleteService delete2.DeleteService, k8sUtil *k8s2.K8sUtil, k8sCommonService k8s.K8sCommonService,
+	namespacePolicyService environmentPolicy.NamespacePolicyService) *EnvironmentRestHandlerImpl {
 	cfg := &bean.Config{}
 	err := env.Parse(cfg)
 	if err != nil {
@@ -92,6 +100,7 @@ func NewEnvironmentRestHandlerImpl(svc request.EnvironmentService, logger *zap.S
 		cfg:                               cfg,
 		k8sUtil:                           k8sUtil,
 		k8sCommonService:                  k8sCommonService,
+		namespacePolicyService:            namespacePolicyService,
 	}
 }
 
@@ -117,6 +126,14 @@ func (impl EnvironmentRestHandlerImpl) Create(w http.ResponseWriter, r *http.Req
 		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
 		return
 	}
+	if bean.NamespacePolicy != nil {
+		err = impl.namespacePolicyService.ValidatePolicy(bean.NamespacePolicy)
+		if err != nil {
+			impl.logger.Errorw("validation err, Create", "err", err, "payload", bean)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
 
 	// RBAC enforcer applying
 	token := r.Header.Get("token")
@@ -132,7 +149,18 @@ func (impl EnvironmentRestHandlerImpl) Create(w http.ResponseWriter, r *http.Req
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
-	common.WriteJsonResp(w, err, res, http.StatusOK)
+	if bean.NamespacePolicy != nil {
+		bean.NamespacePolicy.EnvironmentId = res.Id
+		_, err = impl.namespacePolicyService.SavePolicy(r.Context(), bean.NamespacePolicy, userId)
+		if err != nil {
+			// the environment is saved, the policy can be saved again from the namespace policy of the environment
+			impl.logger.Errorw("service err, Create", "err", err, "payload", bean)
+			res.NamespacePolicyError = err.Error()
+		} else {
+			res.NamespacePolicy = bean.NamespacePolicy
+		}
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
 }
 
 func (impl EnvironmentRestHandlerImpl) Get(w http.ResponseWriter, r *http.Request) {
@@ -253,6 +281,14 @@ func (impl EnvironmentRestHandlerImpl) Update(w http.ResponseWriter, r *http.Req
 		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
 		return
 	}
+	if bean.NamespacePolicy != nil {
+		err = impl.namespacePolicyService.ValidatePolicy(bean.NamespacePolicy)
+		if err != nil {
+			impl.logger.Errorw("validation err, Update", "err", err, "payload", bean)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
 
 	// RBAC enforcer applying
 	token := r.Header.Get("token")
@@ -273,6 +309,25 @@ func (impl EnvironmentRestHandlerImpl) Update(w http.ResponseWriter, r *http.Req
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	if bean.NamespacePolicy != nil {
+		bean.NamespacePolicy.EnvironmentId = res.Id
+		_, err = impl.namespacePolicyService.SavePolicy(r.Context(), bean.NamespacePolicy, userId)
+		if err != nil {
+			// the environment is saved, the policy can be saved again from the namespace policy of the environment
+			impl.logger.Errorw("service err, Update", "err", err, "payload", bean)
+			res.NamespacePolicyError = err.Error()
+		} else {
+			res.NamespacePolicy = bean.NamespacePolicy
+		}
+	} else {
+		// the saved policy is re-asserted on the namespace of the updated environment
+		go func(environmentId int) {
+			_, err := impl.namespacePolicyService.ReconcileEnvironment(environmentId)
+			if err != nil {
+				impl.logger.Errorw("error in reconciling namespace policy", "err", err, "environmentId", environmentId)
+			}
+		}(res.Id)
+	}
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
 
@@ -541,3 +596,116 @@ func (impl EnvironmentRestHandlerImpl) GetEnvironmentConnection(w http.ResponseW
 	impl.environmentClusterMappingsService.HandleErrorInClusterConnections([]*request.ClusterBean{clusterBean}, mapObj, true)
 	common.WriteJsonResp(w, nil, responseObj, http.StatusOK)
 }
+
+func (impl EnvironmentRestHandlerImpl) GetNamespacePolicy(w http.ResponseWriter, r *http.Request) {
+	envId, err := strconv.Atoi(mux.Vars(r)["envId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	environment, err := impl.environmentClusterMappingsService.FindById(envId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetNamespacePolicy", "err", err, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobalEnvironment, casbin.ActionGet, strings.ToLower(environment.EnvironmentIdentifier)); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	status, err := impl.namespacePolicyService.GetPolicyStatus(envId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetNamespacePolicy", "err", err, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, status, http.StatusOK)
+}
+
+func (impl EnvironmentRestHandlerImpl) SaveNamespacePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	envId, err := strconv.Atoi(mux.Vars(r)["envId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var policy bean2.NamespacePolicy
+	err = json.NewDecoder(r.Body).Decode(&policy)
+	if err != nil {
+		impl.logger.Errorw("request err, SaveNamespacePolicy", "err", err, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	policy.EnvironmentId = envId
+	environment, err := impl.environmentClusterMappingsService.FindById(envId)
+	if err != nil {
+		impl.logger.Errorw("service err, SaveNamespacePolicy", "err", err, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobalEnvironment, casbin.ActionUpdate, strings.ToLower(environment.EnvironmentIdentifier)); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	status, err := impl.namespacePolicyService.SavePolicy(r.Context(), &policy, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, SaveNamespacePolicy", "err", err, "payload", policy)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, status, http.StatusOK)
+}
+
+func (impl EnvironmentRestHandlerImpl) AcknowledgeNamespacePolicyDrift(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	envId, err := strconv.Atoi(mux.Vars(r)["envId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	environment, err := impl.environmentClusterMappingsService.FindById(envId)
+	if err != nil {
+		impl.logger.Errorw("service err, AcknowledgeNamespacePolicyDrift", "err", err, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobalEnvironment, casbin.ActionUpdate, strings.ToLower(environment.EnvironmentIdentifier)); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	status, err := impl.namespacePolicyService.AcknowledgeDrift(envId, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, AcknowledgeNamespacePolicyDrift", "err", err, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, status, http.StatusOK)
+}
+
+func (impl EnvironmentRestHandlerImpl) GetNamespacePolicyDrift(w http.ResponseWriter, r *http.Request) {
+	statuses, err := impl.namespacePolicyService.GetDriftedPolicies()
+	if err != nil {
+		impl.logger.Errorw("service err, GetNamespacePolicyDrift", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	result := make([]*bean2.NamespacePolicyStatus, 0, len(statuses))
+	for _, status := range statuses {
+		if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobalEnvironment, casbin.ActionGet, strings.ToLower(status.EnvironmentIdentifier)); ok {
+			result = append(result, status)
+		}
+	}
+	common.WriteJsonResp(w, nil, result, http.StatusOK)
+}
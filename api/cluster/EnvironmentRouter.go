@@ -74,4 +74,16 @@ func (impl EnvironmentRouterImpl) InitEnvironmentClusterMappingsRouter(environme
 	environmentClusterMappingsRouter.Path("/{envId}/connection").
 		Methods("GET").
 		HandlerFunc(impl.environmentClusterMappingsRestHandler.GetEnvironmentConnection)
+	environmentClusterMappingsRouter.Path("/namespace-policy/drift").
+		Methods("GET").
+		HandlerFunc(impl.environmentClusterMappingsRestHandler.GetNamespacePolicyDrift)
+	environmentClusterMappingsRouter.Path("/{envId}/namespace-policy").
+		Methods("GET").
+		HandlerFunc(impl.environmentClusterMappingsRestHandler.GetNamespacePolicy)
+	environmentClusterMappingsRouter.Path("/{envId}/namespace-policy").
+		Methods("PUT").
+		HandlerFunc(impl.environmentClusterMappingsRestHandler.SaveNamespacePolicy)
+	environmentClusterMappingsRouter.Path("/{envId}/namespace-policy/drift/acknowledge").
+		Methods("PUT").
+		HandlerFunc(impl.environmentClusterMappingsRestHandler.AcknowledgeNamespacePolicyDrift)
 }
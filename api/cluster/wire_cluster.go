@@ -3,6 +3,10 @@ package cluster
 import (
 	"github.com/devtron-labs/devtron/pkg/cluster"
 	"github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/clusterHealth"
+	repository3 "github.com/devtron-labs/devtron/pkg/clusterHealth/repository"
+	"github.com/devtron-labs/devtron/pkg/environmentPolicy"
+	repository4 "github.com/devtron-labs/devtron/pkg/environmentPolicy/repository"
 	"github.com/devtron-labs/devtron/pkg/genericNotes"
 	repository2 "github.com/devtron-labs/devtron/pkg/genericNotes/repository"
 	"github.com/google/wire"
@@ -31,6 +35,10 @@ var ClusterWireSet = wire.NewSet(
 	wire.Bind(new(genericNotes.GenericNoteService), new(*genericNotes.GenericNoteServiceImpl)),
 	cluster.NewClusterDescriptionServiceImpl,
 	wire.Bind(new(cluster.ClusterDescriptionService), new(*cluster.ClusterDescriptionServiceImpl)),
+	repository3.NewClusterHealthRepositoryImpl,
+	wire.Bind(new(repository3.ClusterHealthRepository), new(*repository3.ClusterHealthRepositoryImpl)),
+	clusterHealth.NewClusterHealthServiceImplExtended,
+	wire.Bind(new(clusterHealth.ClusterHealthService), new(*clusterHealth.ClusterHealthServiceImplExtended)),
 
 	NewClusterRestHandlerImpl,
 	wire.Bind(new(ClusterRestHandler), new(*ClusterRestHandlerImpl)),
@@ -41,6 +49,10 @@ var ClusterWireSet = wire.NewSet(
 	wire.Bind(new(repository.EnvironmentRepository), new(*repository.EnvironmentRepositoryImpl)),
 	cluster.NewEnvironmentServiceImpl,
 	wire.Bind(new(cluster.EnvironmentService), new(*cluster.EnvironmentServiceImpl)),
+	repository4.NewNamespacePolicyRepositoryImpl,
+	wire.Bind(new(repository4.NamespacePolicyRepository), new(*repository4.NamespacePolicyRepositoryImpl)),
+	environmentPolicy.NewNamespacePolicyServiceImpl,
+	wire.Bind(new(environmentPolicy.NamespacePolicyService), new(*environmentPolicy.NamespacePolicyServiceImpl)),
 	NewEnvironmentRestHandlerImpl,
 	wire.Bind(new(EnvironmentRestHandler), new(*EnvironmentRestHandlerImpl)),
 	NewEnvironmentRouterImpl,
@@ -68,6 +80,10 @@ var ClusterWireSetEa = wire.NewSet(
 	wire.Bind(new(genericNotes.GenericNoteService), new(*genericNotes.GenericNoteServiceImpl)),
 	cluster.NewClusterDescriptionServiceImpl,
 	wire.Bind(new(cluster.ClusterDescriptionService), new(*cluster.ClusterDescriptionServiceImpl)),
+	repository3.NewClusterHealthRepositoryImpl,
+	wire.Bind(new(repository3.ClusterHealthRepository), new(*repository3.ClusterHealthRepositoryImpl)),
+	clusterHealth.NewClusterHealthServiceImpl,
+	wire.Bind(new(clusterHealth.ClusterHealthService), new(*clusterHealth.ClusterHealthServiceImpl)),
 
 	NewClusterRestHandlerImpl,
 	wire.Bind(new(ClusterRestHandler), new(*ClusterRestHandlerImpl)),
@@ -77,6 +93,10 @@ var ClusterWireSetEa = wire.NewSet(
 	wire.Bind(new(repository.EnvironmentRepository), new(*repository.EnvironmentRepositoryImpl)),
 	cluster.NewEnvironmentServiceImpl,
 	wire.Bind(new(cluster.EnvironmentService), new(*cluster.EnvironmentServiceImpl)),
+	repository4.NewNamespacePolicyRepositoryImpl,
+	wire.Bind(new(repository4.NamespacePolicyRepository), new(*repository4.NamespacePolicyRepositoryImpl)),
+	environmentPolicy.NewNamespacePolicyServiceImpl,
+	wire.Bind(new(environmentPolicy.NamespacePolicyService), new(*environmentPolicy.NamespacePolicyServiceImpl)),
 	NewEnvironmentRestHandlerImpl,
 	wire.Bind(new(EnvironmentRestHandler), new(*EnvironmentRestHandlerImpl)),
 	NewEnvironmentRouterImpl,
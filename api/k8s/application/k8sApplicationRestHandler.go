@@ -11,9 +11,11 @@ import (
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	util2 "github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/cluster"
+	clusterRepository "github.com/devtron-labs/devtron/pkg/cluster/repository"
 	"github.com/devtron-labs/devtron/pkg/k8s"
 	application2 "github.com/devtron-labs/devtron/pkg/k8s/application"
 	bean2 "github.com/devtron-labs/devtron/pkg/k8s/application/bean"
+	"github.com/devtron-labs/devtron/pkg/kubernetesResourceAuditLogs"
 	"github.com/devtron-labs/devtron/pkg/terminal"
 	"github.com/devtron-labs/devtron/pkg/user"
 	"github.com/devtron-labs/devtron/pkg/user/casbin"
@@ -25,11 +27,13 @@ import (
 	errors2 "github.com/juju/errors"
 	"go.uber.org/zap"
 	"gopkg.in/go-playground/validator.v9"
+	"io"
 	errors3 "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type K8sApplicationRestHandler interface {
@@ -49,6 +53,18 @@ type K8sApplicationRestHandler interface {
 	CreateEphemeralContainer(w http.ResponseWriter, r *http.Request)
 	DeleteEphemeralContainer(w http.ResponseWriter, r *http.Request)
 	GetAllApiResourceGVKWithoutAuthorization(w http.ResponseWriter, r *http.Request)
+	GetTerminalSessionRecordings(w http.ResponseWriter, r *http.Request)
+	GetTerminalSessionRecording(w http.ResponseWriter, r *http.Request)
+	DownloadTerminalSessionRecording(w http.ResponseWriter, r *http.Request)
+	GetTerminalCommandPolicies(w http.ResponseWriter, r *http.Request)
+	GetTerminalCommandPolicy(w http.ResponseWriter, r *http.Request)
+	SaveTerminalCommandPolicy(w http.ResponseWriter, r *http.Request)
+	DeleteTerminalCommandPolicy(w http.ResponseWriter, r *http.Request)
+	GetTerminalCommandViolations(w http.ResponseWriter, r *http.Request)
+	GetActiveTerminalSessions(w http.ResponseWriter, r *http.Request)
+	TerminateTerminalSession(w http.ResponseWriter, r *http.Request)
+	SearchResources(w http.ResponseWriter, r *http.Request)
+	GetResourceChanges(w http.ResponseWriter, r *http.Request)
 }
 
 type K8sApplicationRestHandlerImpl struct {
@@ -63,9 +79,13 @@ type K8sApplicationRestHandlerImpl struct {
 	helmAppService         client.HelmAppService
 	userService            user.UserService
 	k8sCommonService       k8s.K8sCommonService
+	recordingService       terminal.TerminalSessionRecordingService
+	commandPolicyService   terminal.TerminalCommandPolicyService
+	resourceSearchService  application2.K8sResourceSearchService
+	resourceChangeService  kubernetesResourceAuditLogs.K8sResourceChangeService
 }
 
-func NewK8sApplicationRestHandlerImpl(logger *zap.SugaredLogger, k8sApplicationService application2.K8sApplicationService, pump connector.Pump, terminalSessionHandler terminal.TerminalSessionHandler, enforcer casbin.Enforcer, enforcerUtilHelm rbac.EnforcerUtilHelm, enforcerUtil rbac.EnforcerUtil, helmAppService client.HelmAppService, userService user.UserService, k8sCommonService k8s.K8sCommonService, validator *validator.Validate) *K8sApplicationRestHandlerImpl {
+func NewK8sApplicationRestHandlerImpl(logger *zap.SugaredLogger, k8sApplicationService application2.K8sApplicationService, pump connector.Pump, terminalSessionHandler terminal.TerminalSessionHandler, enforcer casbin.Enforcer, enforcerUtilHelm rbac.EnforcerUtilHelm, enforcerUtil rbac.EnforcerUtil, helmAppService client.HelmAppService, userService user.UserService, k8sCommonService k8s.K8sCommonService, validator *validator.Validate, recordingService terminal.TerminalSessionRecordingService, commandPolicyService terminal.TerminalCommandPolicyService, resourceSearchService application2.K8sResourceSearchService, resourceChangeService kubernetesResourceAuditLogs.K8sResourceChangeService) *K8sApplicationRestHandlerImpl {
 	return &K8sApplicationRestHandlerImpl{
 		logger:                 logger,
 		k8sApplicationService:  k8sApplicationService,
@@ -78,6 +98,10 @@ func NewK8sApplicationRestHandlerImpl(logger *zap.SugaredLogger, k8sApplicationS
 		helmAppService:         helmAppService,
 		userService:            userService,
 		k8sCommonService:       k8sCommonService,
+		recordingService:       recordingService,
+		commandPolicyService:   commandPolicyService,
+		resourceSearchService:  resourceSearchService,
+		resourceChangeService:  resourceChangeService,
 	}
 }
 
@@ -831,6 +855,70 @@ func (handler *K8sApplicationRestHandlerImpl) GetResourceList(w http.ResponseWri
 	common.WriteJsonResp(w, nil, response, http.StatusOK)
 }
 
+func (handler *K8sApplicationRestHandlerImpl) SearchResources(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	token := r.Header.Get("token")
+	var request bean2.ResourceSearchRequest
+	err = decoder.Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("error in decoding request body", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	request.IsSuperAdmin = handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*")
+	response, err := handler.resourceSearchService.SearchResources(r.Context(), token, &request, handler.verifyRbacForCluster)
+	if err != nil {
+		handler.logger.Errorw("error in searching resources", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, response, http.StatusOK)
+}
+
+// GetResourceChanges returns the changes recorded by the change watcher in the namespace of the cluster between from
+// and to, the last 24 hours by default
+func (handler *K8sApplicationRestHandlerImpl) GetResourceChanges(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("token")
+	v := r.URL.Query()
+	request := &kubernetesResourceAuditLogs.ResourceChangeRequest{
+		Namespace: v.Get("namespace"),
+		Kind:      v.Get("kind"),
+		Name:      v.Get("name"),
+		To:        time.Now(),
+	}
+	var err error
+	if request.ClusterId, err = strconv.Atoi(v.Get("clusterId")); err != nil || len(request.Namespace) == 0 {
+		common.WriteJsonResp(w, errors.New("clusterId and namespace are required"), nil, http.StatusBadRequest)
+		return
+	}
+	if to := v.Get("to"); to != "" {
+		if request.To, err = time.Parse(time.RFC3339, to); err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	request.From = request.To.Add(-24 * time.Hour)
+	if from := v.Get("from"); from != "" {
+		if request.From, err = time.Parse(time.RFC3339, from); err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	changes, err := handler.resourceChangeService.GetResourceChanges(request, handler.getRbacCallbackForResource(token, casbin.ActionGet))
+	if err != nil {
+		handler.logger.Errorw("error in getting resource changes", "err", err, "clusterId", request.ClusterId, "namespace", request.Namespace)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, changes, http.StatusOK)
+}
+
 func (handler *K8sApplicationRestHandlerImpl) ApplyResources(w http.ResponseWriter, r *http.Request) {
 	decoder := json.NewDecoder(r.Body)
 	var request util3.ApplyResourcesRequest
@@ -991,3 +1079,270 @@ func (handler *K8sApplicationRestHandlerImpl) handleEphemeralRBAC(podName, names
 	}
 	return resourceRequestBean
 }
+
+func (handler *K8sApplicationRestHandlerImpl) GetTerminalSessionRecordings(w http.ResponseWriter, r *http.Request) {
+	if _, ok := common.AuthorizeSuperAdmin(w, r, handler.userService, handler.enforcer, casbin.ActionGet); !ok {
+		return
+	}
+	filter := &clusterRepository.TerminalSessionRecordingFilter{}
+	var err error
+	v := r.URL.Query()
+	if clusterId := v.Get("clusterId"); clusterId != "" {
+		if filter.ClusterId, err = strconv.Atoi(clusterId); err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	if userId := v.Get("userId"); userId != "" {
+		id, err := strconv.Atoi(userId)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+		filter.UserId = int32(id)
+	}
+	if from := v.Get("from"); from != "" {
+		if filter.From, err = time.Parse(time.RFC3339, from); err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	if to := v.Get("to"); to != "" {
+		if filter.To, err = time.Parse(time.RFC3339, to); err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	if offset := v.Get("offset"); offset != "" {
+		if filter.Offset, err = strconv.Atoi(offset); err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	if size := v.Get("size"); size != "" {
+		if filter.Size, err = strconv.Atoi(size); err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	res, err := handler.recordingService.GetRecordings(filter)
+	if err != nil {
+		handler.logger.Errorw("service err, GetTerminalSessionRecordings", "err", err, "filter", filter)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *K8sApplicationRestHandlerImpl) GetTerminalSessionRecording(w http.ResponseWriter, r *http.Request) {
+	if _, ok := common.AuthorizeSuperAdmin(w, r, handler.userService, handler.enforcer, casbin.ActionGet); !ok {
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.recordingService.GetRecording(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetTerminalSessionRecording", "err", err, "id", id)
+		if util2.IsErrNoRows(err) {
+			common.WriteJsonResp(w, err, nil, http.StatusNotFound)
+		} else {
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// DownloadTerminalSessionRecording streams the asciicast file of a recording, it can be replayed with any asciicast player
+func (handler *K8sApplicationRestHandlerImpl) DownloadTerminalSessionRecording(w http.ResponseWriter, r *http.Request) {
+	if _, ok := common.AuthorizeSuperAdmin(w, r, handler.userService, handler.enforcer, casbin.ActionGet); !ok {
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	file, cleanUp, err := handler.recordingService.DownloadRecording(id)
+	if err != nil {
+		handler.logger.Errorw("service err, DownloadTerminalSessionRecording", "err", err, "id", id)
+		if util2.IsErrNoRows(err) {
+			common.WriteJsonResp(w, err, nil, http.StatusNotFound)
+		} else {
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+	defer cleanUp()
+	w.Header().Set("Content-Disposition", "attachment; filename="+strconv.Itoa(id)+".cast")
+	w.Header().Set("Content-Type", "application/x-asciicast")
+	_, err = io.Copy(w, file)
+	if err != nil {
+		handler.logger.Errorw("service err, DownloadTerminalSessionRecording", "err", err, "id", id)
+	}
+}
+
+func (handler *K8sApplicationRestHandlerImpl) GetTerminalCommandPolicies(w http.ResponseWriter, r *http.Request) {
+	if _, ok := common.AuthorizeSuperAdmin(w, r, handler.userService, handler.enforcer, casbin.ActionGet); !ok {
+		return
+	}
+	res, err := handler.commandPolicyService.GetPolicies()
+	if err != nil {
+		handler.logger.Errorw("service err, GetTerminalCommandPolicies", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *K8sApplicationRestHandlerImpl) GetTerminalCommandPolicy(w http.ResponseWriter, r *http.Request) {
+	if _, ok := common.AuthorizeSuperAdmin(w, r, handler.userService, handler.enforcer, casbin.ActionGet); !ok {
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.commandPolicyService.GetPolicy(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetTerminalCommandPolicy", "err", err, "id", id)
+		if util2.IsErrNoRows(err) {
+			common.WriteJsonResp(w, err, nil, http.StatusNotFound)
+		} else {
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *K8sApplicationRestHandlerImpl) SaveTerminalCommandPolicy(w http.ResponseWriter, r *http.Request) {
+	userId, ok := common.AuthorizeSuperAdmin(w, r, handler.userService, handler.enforcer, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var request terminal.TerminalCommandPolicyDto
+	err := decoder.Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("error in decoding request body", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if err = handler.validator.Struct(request); err != nil {
+		handler.logger.Errorw("invalid request payload", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	var res *terminal.TerminalCommandPolicyDto
+	if r.Method == http.MethodPut {
+		res, err = handler.commandPolicyService.UpdatePolicy(&request)
+	} else {
+		res, err = handler.commandPolicyService.CreatePolicy(&request)
+	}
+	if err != nil {
+		handler.logger.Errorw("service err, SaveTerminalCommandPolicy", "err", err, "payload", request)
+		if util2.IsErrNoRows(err) {
+			common.WriteJsonResp(w, err, nil, http.StatusNotFound)
+		} else {
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *K8sApplicationRestHandlerImpl) DeleteTerminalCommandPolicy(w http.ResponseWriter, r *http.Request) {
+	userId, ok := common.AuthorizeSuperAdmin(w, r, handler.userService, handler.enforcer, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.commandPolicyService.DeletePolicy(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteTerminalCommandPolicy", "err", err, "id", id)
+		if util2.IsErrNoRows(err) {
+			common.WriteJsonResp(w, err, nil, http.StatusNotFound)
+		} else {
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+	common.WriteJsonResp(w, nil, id, http.StatusOK)
+}
+
+func (handler *K8sApplicationRestHandlerImpl) GetTerminalCommandViolations(w http.ResponseWriter, r *http.Request) {
+	if _, ok := common.AuthorizeSuperAdmin(w, r, handler.userService, handler.enforcer, casbin.ActionGet); !ok {
+		return
+	}
+	filter := &clusterRepository.TerminalCommandViolationFilter{}
+	var err error
+	v := r.URL.Query()
+	if clusterId := v.Get("clusterId"); clusterId != "" {
+		if filter.ClusterId, err = strconv.Atoi(clusterId); err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	if userId := v.Get("userId"); userId != "" {
+		id, err := strconv.Atoi(userId)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+		filter.UserId = int32(id)
+	}
+	if offset := v.Get("offset"); offset != "" {
+		if filter.Offset, err = strconv.Atoi(offset); err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	if size := v.Get("size"); size != "" {
+		if filter.Size, err = strconv.Atoi(size); err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	res, err := handler.commandPolicyService.GetViolations(filter)
+	if err != nil {
+		handler.logger.Errorw("service err, GetTerminalCommandViolations", "err", err, "filter", filter)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *K8sApplicationRestHandlerImpl) GetActiveTerminalSessions(w http.ResponseWriter, r *http.Request) {
+	if _, ok := common.AuthorizeSuperAdmin(w, r, handler.userService, handler.enforcer, casbin.ActionGet); !ok {
+		return
+	}
+	res := handler.terminalSessionHandler.GetActiveSessions()
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *K8sApplicationRestHandlerImpl) TerminateTerminalSession(w http.ResponseWriter, r *http.Request) {
+	userId, ok := common.AuthorizeSuperAdmin(w, r, handler.userService, handler.enforcer, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	sessionId := mux.Vars(r)["sessionId"]
+	reason := r.URL.Query().Get("reason")
+	handler.logger.Infow("request received to terminate terminal session", "sessionId", sessionId, "userId", userId, "reason", reason)
+	err := handler.terminalSessionHandler.TerminateSession(sessionId, reason)
+	if err != nil {
+		handler.logger.Errorw("service err, TerminateTerminalSession", "err", err, "sessionId", sessionId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, sessionId, http.StatusOK)
+}
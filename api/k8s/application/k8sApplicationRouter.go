@@ -28,6 +28,12 @@ func (impl *K8sApplicationRouterImpl) InitK8sApplicationRouter(k8sAppRouter *mux
 	k8sAppRouter.Path("/resource").
 		HandlerFunc(impl.k8sApplicationRestHandler.GetResource).Methods("POST")
 
+	k8sAppRouter.Path("/resource/search").
+		HandlerFunc(impl.k8sApplicationRestHandler.SearchResources).Methods("POST")
+
+	k8sAppRouter.Path("/resource/changes").
+		HandlerFunc(impl.k8sApplicationRestHandler.GetResourceChanges).Methods("GET")
+
 	k8sAppRouter.Path("/resource/create").
 		HandlerFunc(impl.k8sApplicationRestHandler.CreateResource).Methods("POST")
 
@@ -53,6 +59,29 @@ func (impl *K8sApplicationRouterImpl) InitK8sApplicationRouter(k8sAppRouter *mux
 		HandlerFunc(impl.k8sApplicationRestHandler.GetTerminalSession).Methods("GET")
 	k8sAppRouter.PathPrefix("/pod/exec/sockjs/ws").Handler(terminal.CreateAttachHandler("/pod/exec/sockjs/ws"))
 
+	k8sAppRouter.Path("/terminal/recordings").
+		HandlerFunc(impl.k8sApplicationRestHandler.GetTerminalSessionRecordings).Methods("GET")
+	k8sAppRouter.Path("/terminal/recordings/{id}").
+		HandlerFunc(impl.k8sApplicationRestHandler.GetTerminalSessionRecording).Methods("GET")
+	k8sAppRouter.Path("/terminal/recordings/{id}/cast").
+		HandlerFunc(impl.k8sApplicationRestHandler.DownloadTerminalSessionRecording).Methods("GET")
+
+	k8sAppRouter.Path("/terminal/policies").
+		HandlerFunc(impl.k8sApplicationRestHandler.GetTerminalCommandPolicies).Methods("GET")
+	k8sAppRouter.Path("/terminal/policies").
+		HandlerFunc(impl.k8sApplicationRestHandler.SaveTerminalCommandPolicy).Methods("POST", "PUT")
+	k8sAppRouter.Path("/terminal/policies/{id}").
+		HandlerFunc(impl.k8sApplicationRestHandler.GetTerminalCommandPolicy).Methods("GET")
+	k8sAppRouter.Path("/terminal/policies/{id}").
+		HandlerFunc(impl.k8sApplicationRestHandler.DeleteTerminalCommandPolicy).Methods("DELETE")
+	k8sAppRouter.Path("/terminal/violations").
+		HandlerFunc(impl.k8sApplicationRestHandler.GetTerminalCommandViolations).Methods("GET")
+
+	k8sAppRouter.Path("/terminal/sessions").
+		HandlerFunc(impl.k8sApplicationRestHandler.GetActiveTerminalSessions).Methods("GET")
+	k8sAppRouter.Path("/terminal/sessions/{sessionId}").
+		HandlerFunc(impl.k8sApplicationRestHandler.TerminateTerminalSession).Methods("DELETE")
+
 	/*k8sAppRouter.Path("/pod/exec/sockjs/ws/").
 	Handler(terminal.CreateAttachHandler("/api/v1/applications/pod/exec/sockjs/ws/"))*/
 
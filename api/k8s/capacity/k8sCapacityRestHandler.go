@@ -1,18 +1,22 @@
 package capacity
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/pkg/cluster"
 	"github.com/devtron-labs/devtron/pkg/k8s/capacity"
 	"github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
 	"github.com/devtron-labs/devtron/pkg/user"
 	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 type K8sCapacityRestHandler interface {
@@ -26,6 +30,17 @@ type K8sCapacityRestHandler interface {
 	CordonOrUnCordonNode(w http.ResponseWriter, r *http.Request)
 	DrainNode(w http.ResponseWriter, r *http.Request)
 	EditNodeTaints(w http.ResponseWriter, r *http.Request)
+	GetCapacityHistory(w http.ResponseWriter, r *http.Request)
+	GetRightSizingRecommendations(w http.ResponseWriter, r *http.Request)
+	GetNodeGroupPricing(w http.ResponseWriter, r *http.Request)
+	SaveNodeGroupPricing(w http.ResponseWriter, r *http.Request)
+	UpdateNodeGroupPricing(w http.ResponseWriter, r *http.Request)
+	DeleteNodeGroupPricing(w http.ResponseWriter, r *http.Request)
+	GetCostReport(w http.ResponseWriter, r *http.Request)
+	CreateNodeMaintenanceJob(w http.ResponseWriter, r *http.Request)
+	GetNodeMaintenanceJobs(w http.ResponseWriter, r *http.Request)
+	GetNodeMaintenanceJob(w http.ResponseWriter, r *http.Request)
+	UpdateNodeMaintenanceJobStatus(w http.ResponseWriter, r *http.Request)
 }
 type K8sCapacityRestHandlerImpl struct {
 	logger             *zap.SugaredLogger
@@ -35,6 +50,10 @@ type K8sCapacityRestHandlerImpl struct {
 	clusterService     cluster.ClusterService
 	environmentService cluster.EnvironmentService
 	clusterRbacService cluster.ClusterRbacService
+	historyService     capacity.K8sCapacityHistoryService
+	enforcerUtil       rbac.EnforcerUtil
+	costService        capacity.K8sCostAllocationService
+	maintenanceService capacity.K8sNodeMaintenanceService
 }
 
 func NewK8sCapacityRestHandlerImpl(logger *zap.SugaredLogger,
@@ -42,7 +61,11 @@ func NewK8sCapacityRestHandlerImpl(logger *zap.SugaredLogger,
 	enforcer casbin.Enforcer,
 	clusterService cluster.ClusterService,
 	environmentService cluster.EnvironmentService,
-	clusterRbacService cluster.ClusterRbacService) *K8sCapacityRestHandlerImpl {
+	clusterRbacService cluster.ClusterRbacService,
+	historyService capacity.K8sCapacityHistoryService,
+	enforcerUtil rbac.EnforcerUtil,
+	costService capacity.K8sCostAllocationService,
+	maintenanceService capacity.K8sNodeMaintenanceService) *K8sCapacityRestHandlerImpl {
 	return &K8sCapacityRestHandlerImpl{
 		logger:             logger,
 		k8sCapacityService: k8sCapacityService,
@@ -51,6 +74,10 @@ func NewK8sCapacityRestHandlerImpl(logger *zap.SugaredLogger,
 		clusterService:     clusterService,
 		environmentService: environmentService,
 		clusterRbacService: clusterRbacService,
+		historyService:     historyService,
+		enforcerUtil:       enforcerUtil,
+		costService:        costService,
+		maintenanceService: maintenanceService,
 	}
 }
 
@@ -404,3 +431,346 @@ func (handler *K8sCapacityRestHandlerImpl) EditNodeTaints(w http.ResponseWriter,
 	}
 	common.WriteJsonResp(w, nil, resp, http.StatusOK)
 }
+
+func (handler *K8sCapacityRestHandlerImpl) GetCapacityHistory(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	clusterId, err := strconv.Atoi(mux.Vars(r)["clusterId"])
+	if err != nil {
+		handler.logger.Errorw("request err, GetCapacityHistory", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	v := r.URL.Query()
+	to := time.Now()
+	from := to.AddDate(0, 0, -1)
+	if fromParam := v.Get("from"); fromParam != "" {
+		if from, err = time.Parse(time.RFC3339, fromParam); err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	if toParam := v.Get("to"); toParam != "" {
+		if to, err = time.Parse(time.RFC3339, toParam); err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	cluster, err := handler.clusterService.FindById(clusterId)
+	if err != nil {
+		handler.logger.Errorw("error in getting cluster by id", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	authenticated, err := handler.clusterRbacService.CheckAuthorization(cluster.ClusterName, cluster.Id, token, userId, false)
+	if err != nil {
+		handler.logger.Errorw("error in checking rbac for cluster", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !authenticated {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	history, err := handler.historyService.GetCapacityHistory(clusterId, v.Get("nodeName"), from, to)
+	if err != nil {
+		handler.logger.Errorw("error in getting capacity history", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, history, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) GetRightSizingRecommendations(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	v := r.URL.Query()
+	appId, err := strconv.Atoi(v.Get("appId"))
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	envId, err := strconv.Atoi(v.Get("envId"))
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	lookbackDays := 0
+	if lookbackDaysParam := v.Get("lookbackDays"); lookbackDaysParam != "" {
+		if lookbackDays, err = strconv.Atoi(lookbackDaysParam); err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	envObject := handler.enforcerUtil.GetEnvRBACNameByAppId(appId, envId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionGet, envObject); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	recommendations, err := handler.historyService.GetRightSizingRecommendations(appId, envId, lookbackDays)
+	if err != nil {
+		handler.logger.Errorw("error in getting right sizing recommendations", "err", err, "appId", appId, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, recommendations, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) GetNodeGroupPricing(w http.ResponseWriter, r *http.Request) {
+	if _, ok := common.AuthorizeSuperAdmin(w, r, handler.userService, handler.enforcer, casbin.ActionGet); !ok {
+		return
+	}
+	pricing, err := handler.costService.GetAllPricing()
+	if err != nil {
+		handler.logger.Errorw("error in getting node group pricing", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, pricing, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) SaveNodeGroupPricing(w http.ResponseWriter, r *http.Request) {
+	userId, ok := common.AuthorizeSuperAdmin(w, r, handler.userService, handler.enforcer, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	var pricing bean.NodeGroupPricing
+	err := json.NewDecoder(r.Body).Decode(&pricing)
+	if err != nil {
+		handler.logger.Errorw("error in decoding request", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	resp, err := handler.costService.SavePricing(&pricing, userId)
+	if err != nil {
+		handler.logger.Errorw("error in saving node group pricing", "err", err, "req", pricing)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) UpdateNodeGroupPricing(w http.ResponseWriter, r *http.Request) {
+	userId, ok := common.AuthorizeSuperAdmin(w, r, handler.userService, handler.enforcer, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	var pricing bean.NodeGroupPricing
+	err := json.NewDecoder(r.Body).Decode(&pricing)
+	if err != nil {
+		handler.logger.Errorw("error in decoding request", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	resp, err := handler.costService.UpdatePricing(&pricing, userId)
+	if err != nil {
+		handler.logger.Errorw("error in updating node group pricing", "err", err, "req", pricing)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) DeleteNodeGroupPricing(w http.ResponseWriter, r *http.Request) {
+	userId, ok := common.AuthorizeSuperAdmin(w, r, handler.userService, handler.enforcer, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.costService.DeletePricing(id, userId)
+	if err != nil {
+		handler.logger.Errorw("error in deleting node group pricing", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, id, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) GetCostReport(w http.ResponseWriter, r *http.Request) {
+	if _, ok := common.AuthorizeSuperAdmin(w, r, handler.userService, handler.enforcer, casbin.ActionGet); !ok {
+		return
+	}
+	v := r.URL.Query()
+	period := v.Get("period")
+	if len(period) == 0 {
+		period = bean.CostPeriodMonthly
+	}
+	groupBy := v.Get("groupBy")
+	if len(groupBy) == 0 {
+		groupBy = bean.CostGroupByTeam
+	}
+	format := v.Get("format")
+	if len(format) == 0 {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		common.WriteJsonResp(w, fmt.Errorf("unsupported report format '%s'", format), nil, http.StatusBadRequest)
+		return
+	}
+	var err error
+	to := time.Now()
+	from := to.AddDate(0, -1, 0)
+	if fromParam := v.Get("from"); fromParam != "" {
+		if from, err = time.Parse(time.RFC3339, fromParam); err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	if toParam := v.Get("to"); toParam != "" {
+		if to, err = time.Parse(time.RFC3339, toParam); err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	report, err := handler.costService.GetCostReport(from, to, period, groupBy)
+	if err != nil {
+		handler.logger.Errorw("error in getting cost report", "err", err, "period", period, "groupBy", groupBy)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if format == "json" {
+		common.WriteJsonResp(w, nil, report, http.StatusOK)
+		return
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=cost-%s-by-%s-%s.csv", period, groupBy, time.Now().Format("20060102150405")))
+	w.Header().Set("Content-Type", "text/csv")
+	err = writeCostReportCsv(w, report, groupBy)
+	if err != nil {
+		handler.logger.Errorw("error in writing cost report", "err", err)
+	}
+}
+
+func (handler *K8sCapacityRestHandlerImpl) CreateNodeMaintenanceJob(w http.ResponseWriter, r *http.Request) {
+	userId, ok := common.AuthorizeSuperAdmin(w, r, handler.userService, handler.enforcer, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	var request bean.NodeMaintenanceRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("error in decoding request body", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	job, err := handler.maintenanceService.CreateMaintenanceJob(r.Context(), &request, userId)
+	if err != nil {
+		handler.logger.Errorw("error in creating node maintenance job", "err", err, "req", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, job, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) GetNodeMaintenanceJobs(w http.ResponseWriter, r *http.Request) {
+	if _, ok := common.AuthorizeSuperAdmin(w, r, handler.userService, handler.enforcer, casbin.ActionGet); !ok {
+		return
+	}
+	clusterId, err := strconv.Atoi(r.URL.Query().Get("clusterId"))
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	jobs, err := handler.maintenanceService.GetMaintenanceJobs(clusterId)
+	if err != nil {
+		handler.logger.Errorw("error in getting node maintenance jobs", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, jobs, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) GetNodeMaintenanceJob(w http.ResponseWriter, r *http.Request) {
+	if _, ok := common.AuthorizeSuperAdmin(w, r, handler.userService, handler.enforcer, casbin.ActionGet); !ok {
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	job, err := handler.maintenanceService.GetMaintenanceJob(id)
+	if err != nil {
+		handler.logger.Errorw("error in getting node maintenance job", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, job, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) UpdateNodeMaintenanceJobStatus(w http.ResponseWriter, r *http.Request) {
+	userId, ok := common.AuthorizeSuperAdmin(w, r, handler.userService, handler.enforcer, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	job, err := handler.maintenanceService.UpdateMaintenanceJobStatus(id, vars["action"], userId)
+	if err != nil {
+		handler.logger.Errorw("error in updating node maintenance job", "err", err, "id", id, "action", vars["action"])
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, job, http.StatusOK)
+}
+
+func writeCostReportCsv(w http.ResponseWriter, report []*bean.CostReportRow, groupBy string) error {
+	writer := csv.NewWriter(w)
+	var header []string
+	switch groupBy {
+	case bean.CostGroupByTeam:
+		header = []string{"period", "team"}
+	case bean.CostGroupByApp:
+		header = []string{"period", "team", "app"}
+	case bean.CostGroupByEnvironment:
+		header = []string{"period", "environment"}
+	}
+	err := writer.Write(append(header, "cpuCost", "memoryCost", "gpuCost", "totalCost"))
+	if err != nil {
+		return err
+	}
+	formatCost := func(cost float64) string {
+		return strconv.FormatFloat(cost, 'f', 2, 64)
+	}
+	for _, row := range report {
+		var record []string
+		switch groupBy {
+		case bean.CostGroupByTeam:
+			record = []string{row.Period, row.TeamName}
+		case bean.CostGroupByApp:
+			record = []string{row.Period, row.TeamName, row.AppName}
+		case bean.CostGroupByEnvironment:
+			record = []string{row.Period, row.EnvName}
+		}
+		err = writer.Write(append(record, formatCost(row.CpuCost), formatCost(row.MemoryCost), formatCost(row.GpuCost), formatCost(row.TotalCost)))
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
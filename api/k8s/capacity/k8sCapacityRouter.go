@@ -27,6 +27,39 @@ func (impl *K8sCapacityRouterImpl) InitK8sCapacityRouter(k8sCapacityRouter *mux.
 	k8sCapacityRouter.Path("/cluster/{clusterId}").
 		HandlerFunc(impl.k8sCapacityRestHandler.GetClusterDetail).Methods("GET")
 
+	k8sCapacityRouter.Path("/cluster/{clusterId}/history").
+		HandlerFunc(impl.k8sCapacityRestHandler.GetCapacityHistory).Methods("GET")
+
+	k8sCapacityRouter.Path("/recommendation").
+		HandlerFunc(impl.k8sCapacityRestHandler.GetRightSizingRecommendations).Methods("GET")
+
+	k8sCapacityRouter.Path("/pricing").
+		HandlerFunc(impl.k8sCapacityRestHandler.GetNodeGroupPricing).Methods("GET")
+
+	k8sCapacityRouter.Path("/pricing").
+		HandlerFunc(impl.k8sCapacityRestHandler.SaveNodeGroupPricing).Methods("POST")
+
+	k8sCapacityRouter.Path("/pricing").
+		HandlerFunc(impl.k8sCapacityRestHandler.UpdateNodeGroupPricing).Methods("PUT")
+
+	k8sCapacityRouter.Path("/pricing/{id}").
+		HandlerFunc(impl.k8sCapacityRestHandler.DeleteNodeGroupPricing).Methods("DELETE")
+
+	k8sCapacityRouter.Path("/cost").
+		HandlerFunc(impl.k8sCapacityRestHandler.GetCostReport).Methods("GET")
+
+	k8sCapacityRouter.Path("/node/maintenance").
+		HandlerFunc(impl.k8sCapacityRestHandler.CreateNodeMaintenanceJob).Methods("POST")
+
+	k8sCapacityRouter.Path("/node/maintenance").
+		HandlerFunc(impl.k8sCapacityRestHandler.GetNodeMaintenanceJobs).Methods("GET")
+
+	k8sCapacityRouter.Path("/node/maintenance/{id}").
+		HandlerFunc(impl.k8sCapacityRestHandler.GetNodeMaintenanceJob).Methods("GET")
+
+	k8sCapacityRouter.Path("/node/maintenance/{id}/{action}").
+		HandlerFunc(impl.k8sCapacityRestHandler.UpdateNodeMaintenanceJobStatus).Methods("PUT")
+
 	k8sCapacityRouter.Path("/node/list").
 		HandlerFunc(impl.k8sCapacityRestHandler.GetNodeList).Methods("GET")
 
@@ -8,6 +8,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/k8s"
 	application2 "github.com/devtron-labs/devtron/pkg/k8s/application"
 	capacity2 "github.com/devtron-labs/devtron/pkg/k8s/capacity"
+	capacityRepository "github.com/devtron-labs/devtron/pkg/k8s/capacity/repository"
 	"github.com/devtron-labs/devtron/pkg/k8s/informer"
 	"github.com/devtron-labs/devtron/pkg/terminal"
 	"github.com/google/wire"
@@ -16,6 +17,8 @@ import (
 var K8sApplicationWireSet = wire.NewSet(
 	application2.NewK8sApplicationServiceImpl,
 	wire.Bind(new(application2.K8sApplicationService), new(*application2.K8sApplicationServiceImpl)),
+	application2.NewK8sResourceSearchServiceImpl,
+	wire.Bind(new(application2.K8sResourceSearchService), new(*application2.K8sResourceSearchServiceImpl)),
 	k8s.NewK8sCommonServiceImpl,
 	wire.Bind(new(k8s.K8sCommonService), new(*k8s.K8sCommonServiceImpl)),
 	application.NewK8sApplicationRouterImpl,
@@ -26,6 +29,14 @@ var K8sApplicationWireSet = wire.NewSet(
 	wire.Bind(new(clusterRepository.EphemeralContainersRepository), new(*clusterRepository.EphemeralContainersRepositoryImpl)),
 	cluster.NewEphemeralContainerServiceImpl,
 	wire.Bind(new(cluster.EphemeralContainerService), new(*cluster.EphemeralContainerServiceImpl)),
+	clusterRepository.NewTerminalSessionRecordingRepositoryImpl,
+	wire.Bind(new(clusterRepository.TerminalSessionRecordingRepository), new(*clusterRepository.TerminalSessionRecordingRepositoryImpl)),
+	terminal.NewTerminalSessionRecordingServiceImpl,
+	wire.Bind(new(terminal.TerminalSessionRecordingService), new(*terminal.TerminalSessionRecordingServiceImpl)),
+	clusterRepository.NewTerminalCommandPolicyRepositoryImpl,
+	wire.Bind(new(clusterRepository.TerminalCommandPolicyRepository), new(*clusterRepository.TerminalCommandPolicyRepositoryImpl)),
+	terminal.NewTerminalCommandPolicyServiceImpl,
+	wire.Bind(new(terminal.TerminalCommandPolicyService), new(*terminal.TerminalCommandPolicyServiceImpl)),
 	terminal.NewTerminalSessionHandlerImpl,
 	wire.Bind(new(terminal.TerminalSessionHandler), new(*terminal.TerminalSessionHandlerImpl)),
 	capacity.NewK8sCapacityRouterImpl,
@@ -34,6 +45,18 @@ var K8sApplicationWireSet = wire.NewSet(
 	wire.Bind(new(capacity.K8sCapacityRestHandler), new(*capacity.K8sCapacityRestHandlerImpl)),
 	capacity2.NewK8sCapacityServiceImpl,
 	wire.Bind(new(capacity2.K8sCapacityService), new(*capacity2.K8sCapacityServiceImpl)),
+	capacityRepository.NewCapacitySnapshotRepositoryImpl,
+	wire.Bind(new(capacityRepository.CapacitySnapshotRepository), new(*capacityRepository.CapacitySnapshotRepositoryImpl)),
+	capacityRepository.NewCapacityCostRepositoryImpl,
+	wire.Bind(new(capacityRepository.CapacityCostRepository), new(*capacityRepository.CapacityCostRepositoryImpl)),
+	capacity2.NewK8sCostAllocationServiceImpl,
+	wire.Bind(new(capacity2.K8sCostAllocationService), new(*capacity2.K8sCostAllocationServiceImpl)),
+	capacityRepository.NewNodeMaintenanceRepositoryImpl,
+	wire.Bind(new(capacityRepository.NodeMaintenanceRepository), new(*capacityRepository.NodeMaintenanceRepositoryImpl)),
+	capacity2.NewK8sNodeMaintenanceServiceImpl,
+	wire.Bind(new(capacity2.K8sNodeMaintenanceService), new(*capacity2.K8sNodeMaintenanceServiceImpl)),
+	capacity2.NewK8sCapacityHistoryServiceImpl,
+	wire.Bind(new(capacity2.K8sCapacityHistoryService), new(*capacity2.K8sCapacityHistoryServiceImpl)),
 	informer.NewGlobalMapClusterNamespace,
 	informer.NewK8sInformerFactoryImpl,
 	wire.Bind(new(informer.K8sInformerFactory), new(*informer.K8sInformerFactoryImpl)),
@@ -36,6 +36,10 @@ type ApiToken struct {
 	LastUsedByIp *string `json:"lastUsedByIp,omitempty"`
 	// token last updatedAt
 	UpdatedAt *string `json:"updatedAt,omitempty"`
+	// Scopes the api-token is restricted to, empty means all permissions of the api-token user
+	Scopes *[]ApiTokenScope `json:"scopes,omitempty"`
+	// Source CIDRs the api-token can be used from, empty means any
+	AllowedCidrs *[]string `json:"allowedCidrs,omitempty"`
 }
 
 // NewApiToken instantiates a new ApiToken object
@@ -375,6 +379,70 @@ func (o *ApiToken) SetUpdatedAt(v string) {
 	o.UpdatedAt = &v
 }
 
+// GetScopes returns the Scopes field value if set, zero value otherwise.
+func (o *ApiToken) GetScopes() []ApiTokenScope {
+	if o == nil || o.Scopes == nil {
+		var ret []ApiTokenScope
+		return ret
+	}
+	return *o.Scopes
+}
+
+// GetScopesOk returns a tuple with the Scopes field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ApiToken) GetScopesOk() (*[]ApiTokenScope, bool) {
+	if o == nil || o.Scopes == nil {
+		return nil, false
+	}
+	return o.Scopes, true
+}
+
+// HasScopes returns a boolean if a field has been set.
+func (o *ApiToken) HasScopes() bool {
+	if o != nil && o.Scopes != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetScopes gets a reference to the given []ApiTokenScope and assigns it to the Scopes field.
+func (o *ApiToken) SetScopes(v []ApiTokenScope) {
+	o.Scopes = &v
+}
+
+// GetAllowedCidrs returns the AllowedCidrs field value if set, zero value otherwise.
+func (o *ApiToken) GetAllowedCidrs() []string {
+	if o == nil || o.AllowedCidrs == nil {
+		var ret []string
+		return ret
+	}
+	return *o.AllowedCidrs
+}
+
+// GetAllowedCidrsOk returns a tuple with the AllowedCidrs field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ApiToken) GetAllowedCidrsOk() (*[]string, bool) {
+	if o == nil || o.AllowedCidrs == nil {
+		return nil, false
+	}
+	return o.AllowedCidrs, true
+}
+
+// HasAllowedCidrs returns a boolean if a field has been set.
+func (o *ApiToken) HasAllowedCidrs() bool {
+	if o != nil && o.AllowedCidrs != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetAllowedCidrs gets a reference to the given []string and assigns it to the AllowedCidrs field.
+func (o *ApiToken) SetAllowedCidrs(v []string) {
+	o.AllowedCidrs = &v
+}
+
 func (o ApiToken) MarshalJSON() ([]byte, error) {
 	toSerialize := map[string]interface{}{}
 	if o.Id != nil {
@@ -407,6 +475,12 @@ func (o ApiToken) MarshalJSON() ([]byte, error) {
 	if o.UpdatedAt != nil {
 		toSerialize["updatedAt"] = o.UpdatedAt
 	}
+	if o.Scopes != nil {
+		toSerialize["scopes"] = o.Scopes
+	}
+	if o.AllowedCidrs != nil {
+		toSerialize["allowedCidrs"] = o.AllowedCidrs
+	}
 	return json.Marshal(toSerialize)
 }
 
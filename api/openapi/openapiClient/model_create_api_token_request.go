@@ -23,6 +23,10 @@ type CreateApiTokenRequest struct {
 	Description *string `json:"description,omitempty,notnull" validate:"required"`
 	// Expiration time of api-token in milliseconds
 	ExpireAtInMs *int64 `json:"expireAtInMs,omitempty"`
+	// Scopes the api-token is restricted to, empty means all permissions of the api-token user
+	Scopes *[]ApiTokenScope `json:"scopes,omitempty"`
+	// Source CIDRs the api-token can be used from, empty means any
+	AllowedCidrs *[]string `json:"allowedCidrs,omitempty"`
 }
 
 // NewCreateApiTokenRequest instantiates a new CreateApiTokenRequest object
@@ -138,6 +142,70 @@ func (o *CreateApiTokenRequest) SetExpireAtInMs(v int64) {
 	o.ExpireAtInMs = &v
 }
 
+// GetScopes returns the Scopes field value if set, zero value otherwise.
+func (o *CreateApiTokenRequest) GetScopes() []ApiTokenScope {
+	if o == nil || o.Scopes == nil {
+		var ret []ApiTokenScope
+		return ret
+	}
+	return *o.Scopes
+}
+
+// GetScopesOk returns a tuple with the Scopes field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *CreateApiTokenRequest) GetScopesOk() (*[]ApiTokenScope, bool) {
+	if o == nil || o.Scopes == nil {
+		return nil, false
+	}
+	return o.Scopes, true
+}
+
+// HasScopes returns a boolean if a field has been set.
+func (o *CreateApiTokenRequest) HasScopes() bool {
+	if o != nil && o.Scopes != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetScopes gets a reference to the given []ApiTokenScope and assigns it to the Scopes field.
+func (o *CreateApiTokenRequest) SetScopes(v []ApiTokenScope) {
+	o.Scopes = &v
+}
+
+// GetAllowedCidrs returns the AllowedCidrs field value if set, zero value otherwise.
+func (o *CreateApiTokenRequest) GetAllowedCidrs() []string {
+	if o == nil || o.AllowedCidrs == nil {
+		var ret []string
+		return ret
+	}
+	return *o.AllowedCidrs
+}
+
+// GetAllowedCidrsOk returns a tuple with the AllowedCidrs field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *CreateApiTokenRequest) GetAllowedCidrsOk() (*[]string, bool) {
+	if o == nil || o.AllowedCidrs == nil {
+		return nil, false
+	}
+	return o.AllowedCidrs, true
+}
+
+// HasAllowedCidrs returns a boolean if a field has been set.
+func (o *CreateApiTokenRequest) HasAllowedCidrs() bool {
+	if o != nil && o.AllowedCidrs != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetAllowedCidrs gets a reference to the given []string and assigns it to the AllowedCidrs field.
+func (o *CreateApiTokenRequest) SetAllowedCidrs(v []string) {
+	o.AllowedCidrs = &v
+}
+
 func (o CreateApiTokenRequest) MarshalJSON() ([]byte, error) {
 	toSerialize := map[string]interface{}{}
 	if o.Name != nil {
@@ -149,6 +217,12 @@ func (o CreateApiTokenRequest) MarshalJSON() ([]byte, error) {
 	if o.ExpireAtInMs != nil {
 		toSerialize["expireAtInMs"] = o.ExpireAtInMs
 	}
+	if o.Scopes != nil {
+		toSerialize["scopes"] = o.Scopes
+	}
+	if o.AllowedCidrs != nil {
+		toSerialize["allowedCidrs"] = o.AllowedCidrs
+	}
 	return json.Marshal(toSerialize)
 }
 
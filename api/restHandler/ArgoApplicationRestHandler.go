@@ -701,7 +701,7 @@ func (impl ArgoApplicationRestHandlerImpl) DeleteResource(w http.ResponseWriter,
 	recv, err := impl.client.DeleteResource(ctx, query)
 
 	if err == nil {
-		ResourceHistoryErr := impl.K8sResourceHistoryService.SaveArgoCdAppsResourceDeleteHistory(query, id, eId, userId)
+		ResourceHistoryErr := impl.K8sResourceHistoryService.SaveArgoCdAppsResourceDeleteHistory(r.Context(), query, id, eId, userId)
 		if ResourceHistoryErr != nil {
 			impl.logger.Errorw("error in saving audit logs of delete resource request for argo cd apps", "err", ResourceHistoryErr)
 		}
@@ -0,0 +1,124 @@
+package restHandler
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/pipeline/buildCache"
+	"github.com/devtron-labs/devtron/pkg/pipeline/buildCache/bean"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type BuildCacheRestHandler interface {
+	GetBuildCacheConfig(w http.ResponseWriter, r *http.Request)
+	SaveBuildCacheConfig(w http.ResponseWriter, r *http.Request)
+	GetBuildCaches(w http.ResponseWriter, r *http.Request)
+	PurgeBuildCaches(w http.ResponseWriter, r *http.Request)
+}
+
+type BuildCacheRestHandlerImpl struct {
+	ciPipelineAuthorizer
+	validator         *validator.Validate
+	buildCacheService buildCache.BuildCacheService
+}
+
+func NewBuildCacheRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	validator *validator.Validate, enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil,
+	buildCacheService buildCache.BuildCacheService, ciPipelineRepository pipelineConfig.CiPipelineRepository) *BuildCacheRestHandlerImpl {
+	return &BuildCacheRestHandlerImpl{
+		ciPipelineAuthorizer: newCiPipelineAuthorizer(logger, userService, enforcer, enforcerUtil, ciPipelineRepository),
+		validator:            validator,
+		buildCacheService:    buildCacheService,
+	}
+}
+
+func (handler *BuildCacheRestHandlerImpl) GetBuildCacheConfig(w http.ResponseWriter, r *http.Request) {
+	ciPipeline, ok := handler.authorizeCiPipeline(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	config, err := handler.buildCacheService.GetConfig(ciPipeline.Id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetBuildCacheConfig", "err", err, "pipelineId", ciPipeline.Id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, config, http.StatusOK)
+}
+
+func (handler *BuildCacheRestHandlerImpl) SaveBuildCacheConfig(w http.ResponseWriter, r *http.Request) {
+	ciPipeline, ok := handler.authorizeCiPipeline(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var config bean.BuildCacheConfig
+	err = json.NewDecoder(r.Body).Decode(&config)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveBuildCacheConfig", "err", err, "payload", config)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	config.CiPipelineId = ciPipeline.Id
+	err = handler.validator.Struct(config)
+	if err != nil {
+		handler.logger.Errorw("validation err, SaveBuildCacheConfig", "err", err, "payload", config)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.buildCacheService.SaveConfig(&config, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, SaveBuildCacheConfig", "err", err, "payload", config)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *BuildCacheRestHandlerImpl) GetBuildCaches(w http.ResponseWriter, r *http.Request) {
+	ciPipeline, ok := handler.authorizeCiPipeline(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	caches, err := handler.buildCacheService.GetCaches(ciPipeline.Id, ciPipeline.AppId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetBuildCaches", "err", err, "pipelineId", ciPipeline.Id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, caches, http.StatusOK)
+}
+
+func (handler *BuildCacheRestHandlerImpl) PurgeBuildCaches(w http.ResponseWriter, r *http.Request) {
+	ciPipeline, ok := handler.authorizeCiPipeline(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	cacheId := 0
+	if value, ok := mux.Vars(r)["cacheId"]; ok {
+		var err error
+		cacheId, err = strconv.Atoi(value)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	result, err := handler.buildCacheService.PurgeCaches(ciPipeline.Id, ciPipeline.AppId, cacheId)
+	if err != nil {
+		handler.logger.Errorw("service err, PurgeBuildCaches", "err", err, "pipelineId", ciPipeline.Id, "cacheId", cacheId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, result, http.StatusOK)
+}
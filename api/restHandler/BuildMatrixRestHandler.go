@@ -0,0 +1,133 @@
+package restHandler
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/pipeline/buildMatrix"
+	"github.com/devtron-labs/devtron/pkg/pipeline/buildMatrix/bean"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type BuildMatrixRestHandler interface {
+	GetBuildMatrix(w http.ResponseWriter, r *http.Request)
+	SaveBuildMatrix(w http.ResponseWriter, r *http.Request)
+	GetMatrixRuns(w http.ResponseWriter, r *http.Request)
+	GetMatrixRun(w http.ResponseWriter, r *http.Request)
+}
+
+type BuildMatrixRestHandlerImpl struct {
+	ciPipelineAuthorizer
+	validator          *validator.Validate
+	buildMatrixService buildMatrix.BuildMatrixService
+}
+
+func NewBuildMatrixRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	validator *validator.Validate, enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil,
+	buildMatrixService buildMatrix.BuildMatrixService, ciPipelineRepository pipelineConfig.CiPipelineRepository) *BuildMatrixRestHandlerImpl {
+	return &BuildMatrixRestHandlerImpl{
+		ciPipelineAuthorizer: newCiPipelineAuthorizer(logger, userService, enforcer, enforcerUtil, ciPipelineRepository),
+		validator:            validator,
+		buildMatrixService:   buildMatrixService,
+	}
+}
+
+func (handler *BuildMatrixRestHandlerImpl) GetBuildMatrix(w http.ResponseWriter, r *http.Request) {
+	ciPipeline, ok := handler.authorizeCiPipeline(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	matrix, err := handler.buildMatrixService.GetMatrix(ciPipeline.Id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetBuildMatrix", "err", err, "pipelineId", ciPipeline.Id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if matrix == nil {
+		matrix = &bean.BuildMatrix{CiPipelineId: ciPipeline.Id, OutputType: bean.OutputTypeMultipleArtifacts}
+	}
+	common.WriteJsonResp(w, nil, matrix, http.StatusOK)
+}
+
+func (handler *BuildMatrixRestHandlerImpl) SaveBuildMatrix(w http.ResponseWriter, r *http.Request) {
+	ciPipeline, ok := handler.authorizeCiPipeline(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var matrix bean.BuildMatrix
+	err = json.NewDecoder(r.Body).Decode(&matrix)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveBuildMatrix", "err", err, "payload", matrix)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	matrix.CiPipelineId = ciPipeline.Id
+	err = handler.validator.Struct(matrix)
+	if err != nil {
+		handler.logger.Errorw("validation err, SaveBuildMatrix", "err", err, "payload", matrix)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.buildMatrixService.SaveMatrix(&matrix, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, SaveBuildMatrix", "err", err, "payload", matrix)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *BuildMatrixRestHandlerImpl) GetMatrixRuns(w http.ResponseWriter, r *http.Request) {
+	ciPipeline, ok := handler.authorizeCiPipeline(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	offset, err := getIntQueryParam(r, "offset")
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	size, err := getIntQueryParam(r, "size")
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	runs, err := handler.buildMatrixService.GetRuns(ciPipeline.Id, offset, size)
+	if err != nil {
+		handler.logger.Errorw("service err, GetMatrixRuns", "err", err, "pipelineId", ciPipeline.Id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, runs, http.StatusOK)
+}
+
+func (handler *BuildMatrixRestHandlerImpl) GetMatrixRun(w http.ResponseWriter, r *http.Request) {
+	ciPipeline, ok := handler.authorizeCiPipeline(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	runId, err := strconv.Atoi(mux.Vars(r)["runId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	run, err := handler.buildMatrixService.GetRun(ciPipeline.Id, runId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetMatrixRun", "err", err, "pipelineId", ciPipeline.Id, "runId", runId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, run, http.StatusOK)
+}
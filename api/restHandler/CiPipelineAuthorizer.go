@@ -0,0 +1,62 @@
+package restHandler
+
+import (
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+// ciPipelineAuthorizer is shared by the handlers of the ci pipeline scoped apis addressed by the pipelineId path variable
+type ciPipelineAuthorizer struct {
+	logger               *zap.SugaredLogger
+	userService          user.UserService
+	enforcer             casbin.Enforcer
+	enforcerUtil         rbac.EnforcerUtil
+	ciPipelineRepository pipelineConfig.CiPipelineRepository
+}
+
+func newCiPipelineAuthorizer(logger *zap.SugaredLogger, userService user.UserService, enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil, ciPipelineRepository pipelineConfig.CiPipelineRepository) ciPipelineAuthorizer {
+	return ciPipelineAuthorizer{
+		logger:               logger,
+		userService:          userService,
+		enforcer:             enforcer,
+		enforcerUtil:         enforcerUtil,
+		ciPipelineRepository: ciPipelineRepository,
+	}
+}
+
+// authorizeCiPipeline checks the access of the user to the app of the ci pipeline of the request, the response is
+// written when the request is not authorized
+func (impl ciPipelineAuthorizer) authorizeCiPipeline(w http.ResponseWriter, r *http.Request, action string) (*pipelineConfig.CiPipeline, bool) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return nil, false
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	ciPipeline, err := impl.ciPipelineRepository.FindById(pipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in getting ci pipeline", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return nil, false
+	}
+	token := r.Header.Get("token")
+	object := impl.enforcerUtil.GetAppRBACNameByAppId(ciPipeline.AppId)
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, action, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return nil, false
+	}
+	return ciPipeline, true
+}
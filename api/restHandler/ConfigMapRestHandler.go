@@ -118,7 +118,7 @@ func (handler ConfigMapRestHandlerImpl) CMGlobalAddUpdate(w http.ResponseWriter,
 	}
 	//RBAC END
 
-	res, err := handler.configMapService.CMGlobalAddUpdate(&configMapRequest)
+	res, err := handler.configMapService.CMGlobalAddUpdate(&configMapRequest, r.Context())
 	if err != nil {
 		handler.Logger.Errorw("service err, CMGlobalAddUpdate", "err", err, "payload", configMapRequest)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
@@ -158,7 +158,7 @@ func (handler ConfigMapRestHandlerImpl) CMEnvironmentAddUpdate(w http.ResponseWr
 	}
 	//RBAC END
 
-	res, err := handler.configMapService.CMEnvironmentAddUpdate(&configMapRequest)
+	res, err := handler.configMapService.CMEnvironmentAddUpdate(&configMapRequest, r.Context())
 	if err != nil {
 		handler.Logger.Errorw("service err, CMEnvironmentAddUpdate", "err", err, "payload", configMapRequest)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
@@ -342,7 +342,7 @@ func (handler ConfigMapRestHandlerImpl) CSGlobalAddUpdate(w http.ResponseWriter,
 	}
 	//RBAC END
 
-	res, err := handler.configMapService.CSGlobalAddUpdate(&configMapRequest)
+	res, err := handler.configMapService.CSGlobalAddUpdate(&configMapRequest, r.Context())
 	if err != nil {
 		handler.Logger.Errorw("service err, CSGlobalAddUpdate", "err", err, "payload", configMapRequest)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
@@ -383,7 +383,7 @@ func (handler ConfigMapRestHandlerImpl) CSEnvironmentAddUpdate(w http.ResponseWr
 	}
 	//RBAC END
 
-	res, err := handler.configMapService.CSEnvironmentAddUpdate(&configMapRequest)
+	res, err := handler.configMapService.CSEnvironmentAddUpdate(&configMapRequest, r.Context())
 	if err != nil {
 		handler.Logger.Errorw("service err, CSEnvironmentAddUpdate", "err", err, "payload", configMapRequest)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
@@ -493,7 +493,7 @@ func (handler ConfigMapRestHandlerImpl) CMGlobalDelete(w http.ResponseWriter, r
 	}
 	//RBAC END
 
-	res, err := handler.configMapService.CMGlobalDelete(name, id, userId)
+	res, err := handler.configMapService.CMGlobalDelete(name, id, userId, r.Context())
 	if err != nil {
 		handler.Logger.Errorw("service err, CMGlobalDelete", "err", err, "appId", appId, "id", id, "name", name)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
@@ -544,7 +544,7 @@ func (handler ConfigMapRestHandlerImpl) CMEnvironmentDelete(w http.ResponseWrite
 	}
 	//RBAC END
 
-	res, err := handler.configMapService.CMEnvironmentDelete(name, id, userId)
+	res, err := handler.configMapService.CMEnvironmentDelete(name, id, userId, r.Context())
 	if err != nil {
 		handler.Logger.Errorw("service err, CMEnvironmentDelete", "err", err, "appId", appId, "envId", envId, "id", id)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
@@ -584,7 +584,7 @@ func (handler ConfigMapRestHandlerImpl) CSGlobalDelete(w http.ResponseWriter, r
 	}
 	//RBAC END
 
-	res, err := handler.configMapService.CSGlobalDelete(name, id, userId)
+	res, err := handler.configMapService.CSGlobalDelete(name, id, userId, r.Context())
 	if err != nil {
 		handler.Logger.Errorw("service err, CSGlobalDelete", "err", err, "appId", appId, "id", id, "name", name)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
@@ -635,7 +635,7 @@ func (handler ConfigMapRestHandlerImpl) CSEnvironmentDelete(w http.ResponseWrite
 	}
 	//RBAC END
 
-	res, err := handler.configMapService.CSEnvironmentDelete(name, id, userId)
+	res, err := handler.configMapService.CSEnvironmentDelete(name, id, userId, r.Context())
 	if err != nil {
 		handler.Logger.Errorw("service err, CSEnvironmentDelete", "err", err, "appId", appId, "envId", envId, "id", id)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
@@ -314,7 +314,7 @@ func (handler CoreAppRestHandlerImpl) CreateApp(w http.ResponseWriter, r *http.R
 	// validate payload ends
 
 	//creating blank app starts
-	createBlankAppResp, err, statusCode := handler.createBlankApp(createAppRequest.Metadata, userId)
+	createBlankAppResp, err, statusCode := handler.createBlankApp(r.Context(), createAppRequest.Metadata, userId)
 	if err != nil {
 		common.WriteJsonResp(w, err, nil, statusCode)
 		return
@@ -373,7 +373,7 @@ func (handler CoreAppRestHandlerImpl) CreateApp(w http.ResponseWriter, r *http.R
 
 	//creating global configMaps starts
 	if createAppRequest.GlobalConfigMaps != nil {
-		err, statusCode = handler.createGlobalConfigMaps(appId, userId, createAppRequest.GlobalConfigMaps)
+		err, statusCode = handler.createGlobalConfigMaps(ctx, appId, userId, createAppRequest.GlobalConfigMaps)
 		if err != nil {
 			errResp = multierror.Append(errResp, err)
 			errInAppDelete := handler.deleteApp(ctx, appId, userId)
@@ -388,7 +388,7 @@ func (handler CoreAppRestHandlerImpl) CreateApp(w http.ResponseWriter, r *http.R
 
 	//creating global secrets starts
 	if createAppRequest.GlobalSecrets != nil {
-		err, statusCode = handler.createGlobalSecrets(appId, userId, createAppRequest.GlobalSecrets)
+		err, statusCode = handler.createGlobalSecrets(ctx, appId, userId, createAppRequest.GlobalSecrets)
 		if err != nil {
 			errResp = multierror.Append(errResp, err)
 			errInAppDelete := handler.deleteApp(ctx, appId, userId)
@@ -1131,7 +1131,7 @@ func (handler CoreAppRestHandlerImpl) buildEnvironmentOverride(appId int, enviro
 //Create App related methods starts
 
 // create a blank app with metadata
-func (handler CoreAppRestHandlerImpl) createBlankApp(appMetadata *appBean.AppMetadata, userId int32) (*bean.CreateAppDTO, error, int) {
+func (handler CoreAppRestHandlerImpl) createBlankApp(ctx context.Context, appMetadata *appBean.AppMetadata, userId int32) (*bean.CreateAppDTO, error, int) {
 	handler.logger.Infow("Create App - creating blank app", "appMetadata", appMetadata)
 
 	//validating app metadata
@@ -1166,7 +1166,7 @@ func (handler CoreAppRestHandlerImpl) createBlankApp(appMetadata *appBean.AppMet
 	}
 	createAppRequest.AppLabels = appLabels
 
-	createAppResp, err := handler.pipelineBuilder.CreateApp(createAppRequest)
+	createAppResp, err := handler.pipelineBuilder.CreateApp(createAppRequest, ctx)
 	if err != nil {
 		handler.logger.Errorw("service err, CreateApp in CreateBlankApp", "err", err, "CreateApp", createAppRequest)
 		return nil, err, http.StatusInternalServerError
@@ -1226,7 +1226,7 @@ func (handler CoreAppRestHandlerImpl) deleteApp(ctx context.Context, appId int,
 				Action:     bean.DELETE,
 				CiPipeline: ciPipeline,
 			}
-			_, err := handler.pipelineBuilder.PatchCiPipeline(ciPipelineDeleteRequest)
+			_, err := handler.pipelineBuilder.PatchCiPipeline(ciPipelineDeleteRequest, ctx)
 			if err != nil {
 				handler.logger.Errorw("err in deleting ci pipeline in DeleteApp", "err", err, "payload", ciPipelineDeleteRequest)
 				return err
@@ -1246,7 +1246,7 @@ func (handler CoreAppRestHandlerImpl) deleteApp(ctx context.Context, appId int,
 	}
 
 	// delete app
-	err = handler.pipelineBuilder.DeleteApp(appId, userId)
+	err = handler.pipelineBuilder.DeleteApp(appId, userId, ctx)
 	if err != nil {
 		handler.logger.Errorw("service error, DeleteApp", "err", err, "appId", appId)
 		return err
@@ -1400,7 +1400,7 @@ func (handler CoreAppRestHandlerImpl) createDeploymentTemplate(ctx context.Conte
 }
 
 // create global CMs
-func (handler CoreAppRestHandlerImpl) createGlobalConfigMaps(appId int, userId int32, configMaps []*appBean.ConfigMap) (error, int) {
+func (handler CoreAppRestHandlerImpl) createGlobalConfigMaps(ctx context.Context, appId int, userId int32, configMaps []*appBean.ConfigMap) (error, int) {
 	handler.logger.Infow("Create App - creating global configMap", "appId", appId)
 
 	var appLevelId int
@@ -1450,7 +1450,7 @@ func (handler CoreAppRestHandlerImpl) createGlobalConfigMaps(appId int, userId i
 			ConfigData: configMapDataRequest,
 		}
 		//using same var for every request, since appId and userID are same
-		_, err = handler.configMapService.CMGlobalAddUpdate(configMapRequest)
+		_, err = handler.configMapService.CMGlobalAddUpdate(configMapRequest, ctx)
 		if err != nil {
 			handler.logger.Errorw("service err, CMGlobalAddUpdate in CreateGlobalConfigMap", "err", err, "appId", appId, "configMapRequest", configMapRequest)
 			return err, http.StatusInternalServerError
@@ -1462,7 +1462,7 @@ func (handler CoreAppRestHandlerImpl) createGlobalConfigMaps(appId int, userId i
 }
 
 // create global secrets
-func (handler CoreAppRestHandlerImpl) createGlobalSecrets(appId int, userId int32, secrets []*appBean.Secret) (error, int) {
+func (handler CoreAppRestHandlerImpl) createGlobalSecrets(ctx context.Context, appId int, userId int32, secrets []*appBean.Secret) (error, int) {
 	handler.logger.Infow("Create App - creating global secrets", "appId", appId)
 
 	var appLevelId int
@@ -1527,7 +1527,7 @@ func (handler CoreAppRestHandlerImpl) createGlobalSecrets(appId int, userId int3
 			ConfigData: secretDataRequest,
 		}
 		//using same var for every request, since appId and userID are same
-		_, err := handler.configMapService.CSGlobalAddUpdate(secretRequest)
+		_, err := handler.configMapService.CSGlobalAddUpdate(secretRequest, ctx)
 		if err != nil {
 			handler.logger.Errorw("service err, CSGlobalAddUpdate in CreateGlobalSecret", "err", err, "appId", appId)
 			return err, http.StatusInternalServerError
@@ -1550,7 +1550,7 @@ func (handler CoreAppRestHandlerImpl) createWorkflows(ctx context.Context, appId
 		//Creating workflow ends
 
 		//Creating CI pipeline starts
-		ciPipelineId, err := handler.createCiPipeline(appId, userId, workflowId, workflow.CiPipeline)
+		ciPipelineId, err := handler.createCiPipeline(ctx, appId, userId, workflowId, workflow.CiPipeline)
 		if err != nil {
 			handler.logger.Errorw("err in saving ci pipelines", err, "appId", appId)
 			return err, http.StatusInternalServerError
@@ -1589,7 +1589,7 @@ func (handler CoreAppRestHandlerImpl) createWorkflowInDb(workflowName string, ap
 	return savedAppWf.Id, nil
 }
 
-func (handler CoreAppRestHandlerImpl) createCiPipeline(appId int, userId int32, workflowId int, ciPipelineData *appBean.CiPipelineDetails) (int, error) {
+func (handler CoreAppRestHandlerImpl) createCiPipeline(ctx context.Context, appId int, userId int32, workflowId int, ciPipelineData *appBean.CiPipelineDetails) (int, error) {
 
 	// if ci pipeline is of external type, then throw error as we are not supporting it as of now
 	if ciPipelineData.ParentCiPipeline == 0 && ciPipelineData.ParentAppId == 0 && ciPipelineData.IsExternal {
@@ -1659,7 +1659,7 @@ func (handler CoreAppRestHandlerImpl) createCiPipeline(appId int, userId int32,
 	}
 
 	// service call
-	res, err := handler.pipelineBuilder.PatchCiPipeline(ciPipelineRequest)
+	res, err := handler.pipelineBuilder.PatchCiPipeline(ciPipelineRequest, ctx)
 	if err != nil {
 		handler.logger.Errorw("service err, PatchCiPipelines", "err", err, "appId", appId)
 		return 0, err
@@ -1765,14 +1765,14 @@ func (handler CoreAppRestHandlerImpl) createEnvOverrides(ctx context.Context, ap
 		}
 
 		//creating configMap override
-		err = handler.createEnvCM(appId, userId, envId, envOverrideValues.ConfigMaps)
+		err = handler.createEnvCM(ctx, appId, userId, envId, envOverrideValues.ConfigMaps)
 		if err != nil {
 			handler.logger.Errorw("err in creating config map for env override", "appId", appId, "envName", envName)
 			return err, http.StatusInternalServerError
 		}
 
 		//creating secrets override
-		err = handler.createEnvSecret(appId, userId, envModel.Id, envOverrideValues.Secrets)
+		err = handler.createEnvSecret(ctx, appId, userId, envModel.Id, envOverrideValues.Secrets)
 		if err != nil {
 			handler.logger.Errorw("err in creating secret for env override", "appId", appId, "envName", envName)
 			return err, http.StatusInternalServerError
@@ -1876,7 +1876,7 @@ func (handler CoreAppRestHandlerImpl) createEnvDeploymentTemplate(appId int, use
 }
 
 // create CM overrides
-func (handler CoreAppRestHandlerImpl) createEnvCM(appId int, userId int32, envId int, CmOverrides []*appBean.ConfigMap) error {
+func (handler CoreAppRestHandlerImpl) createEnvCM(ctx context.Context, appId int, userId int32, envId int, CmOverrides []*appBean.ConfigMap) error {
 	handler.logger.Infow("Create App - creating CM override", "appId", appId, "envId", envId)
 
 	var envLevelId int
@@ -1926,7 +1926,7 @@ func (handler CoreAppRestHandlerImpl) createEnvCM(appId int, userId int32, envId
 			ConfigData:    configDataRequest,
 		}
 
-		_, err = handler.configMapService.CMEnvironmentAddUpdate(cmEnvRequest)
+		_, err = handler.configMapService.CMEnvironmentAddUpdate(cmEnvRequest, ctx)
 		if err != nil {
 			handler.logger.Errorw("service err, CMEnvironmentAddUpdate in CreateEnvCM", "err", err, "payload", cmEnvRequest)
 			return err
@@ -1937,7 +1937,7 @@ func (handler CoreAppRestHandlerImpl) createEnvCM(appId int, userId int32, envId
 }
 
 // create secret overrides
-func (handler CoreAppRestHandlerImpl) createEnvSecret(appId int, userId int32, envId int, secretOverrides []*appBean.Secret) error {
+func (handler CoreAppRestHandlerImpl) createEnvSecret(ctx context.Context, appId int, userId int32, envId int, secretOverrides []*appBean.Secret) error {
 	handler.logger.Infow("Create App - creating secret overrides", "appId", appId)
 
 	var envLevelId int
@@ -1987,7 +1987,7 @@ func (handler CoreAppRestHandlerImpl) createEnvSecret(appId int, userId int32, e
 			Id:            envLevelId,
 			ConfigData:    secretDataRequest,
 		}
-		_, err = handler.configMapService.CSEnvironmentAddUpdate(secretEnvRequest)
+		_, err = handler.configMapService.CSEnvironmentAddUpdate(secretEnvRequest, ctx)
 		if err != nil {
 			handler.logger.Errorw("service err, CSEnvironmentAddUpdate", "err", err, "appId", appId, "envId", envId)
 			return err
@@ -23,6 +23,7 @@ import (
 	bean2 "github.com/devtron-labs/devtron/api/bean"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/pkg/app"
 	"github.com/devtron-labs/devtron/pkg/gitops"
 	"github.com/devtron-labs/devtron/pkg/team"
 	"github.com/devtron-labs/devtron/pkg/user"
@@ -42,30 +43,34 @@ type GitOpsConfigRestHandler interface {
 	GetGitOpsConfigByProvider(w http.ResponseWriter, r *http.Request)
 	GitOpsConfigured(w http.ResponseWriter, r *http.Request)
 	GitOpsValidator(w http.ResponseWriter, r *http.Request)
+	MigrateAppsToMonorepo(w http.ResponseWriter, r *http.Request)
 }
 
 type GitOpsConfigRestHandlerImpl struct {
-	logger              *zap.SugaredLogger
-	gitOpsConfigService gitops.GitOpsConfigService
-	userAuthService     user.UserService
-	validator           *validator.Validate
-	enforcer            casbin.Enforcer
-	teamService         team.TeamService
-	gitOpsRepository    repository.GitOpsConfigRepository
+	logger                     *zap.SugaredLogger
+	gitOpsConfigService        gitops.GitOpsConfigService
+	userAuthService            user.UserService
+	validator                  *validator.Validate
+	enforcer                   casbin.Enforcer
+	teamService                team.TeamService
+	gitOpsRepository           repository.GitOpsConfigRepository
+	gitOpsRepoMigrationService app.GitOpsRepoMigrationService
 }
 
 func NewGitOpsConfigRestHandlerImpl(
 	logger *zap.SugaredLogger,
 	gitOpsConfigService gitops.GitOpsConfigService, userAuthService user.UserService,
-	validator *validator.Validate, enforcer casbin.Enforcer, teamService team.TeamService, gitOpsRepository repository.GitOpsConfigRepository) *GitOpsConfigRestHandlerImpl {
+	validator *validator.Validate, enforcer casbin.Enforcer, teamService team.TeamService, gitOpsRepository repository.GitOpsConfigRepository,
+	gitOpsRepoMigrationService app.GitOpsRepoMigrationService) *GitOpsConfigRestHandlerImpl {
 	return &GitOpsConfigRestHandlerImpl{
-		logger:              logger,
-		gitOpsConfigService: gitOpsConfigService,
-		userAuthService:     userAuthService,
-		validator:           validator,
-		enforcer:            enforcer,
-		teamService:         teamService,
-		gitOpsRepository:    gitOpsRepository,
+		logger:                     logger,
+		gitOpsConfigService:        gitOpsConfigService,
+		userAuthService:            userAuthService,
+		validator:                  validator,
+		enforcer:                   enforcer,
+		teamService:                teamService,
+		gitOpsRepository:           gitOpsRepository,
+		gitOpsRepoMigrationService: gitOpsRepoMigrationService,
 	}
 }
 
@@ -301,3 +306,40 @@ func (impl GitOpsConfigRestHandlerImpl) GitOpsValidator(w http.ResponseWriter, r
 	detailedErrorGitOpsConfigResponse := impl.gitOpsConfigService.GitOpsValidateDryRun(&bean)
 	common.WriteJsonResp(w, nil, detailedErrorGitOpsConfigResponse, http.StatusOK)
 }
+
+func (impl GitOpsConfigRestHandlerImpl) MigrateAppsToMonorepo(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+	var request app.GitOpsMonorepoMigrationRequest
+	err = decoder.Decode(&request)
+	if err != nil {
+		impl.logger.Errorw("request err, MigrateAppsToMonorepo", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	err = impl.validator.Struct(request)
+	if err != nil {
+		impl.logger.Errorw("validation err, MigrateAppsToMonorepo", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := impl.gitOpsRepoMigrationService.MigrateAppsToMonorepo(&request)
+	if err != nil {
+		impl.logger.Errorw("service err, MigrateAppsToMonorepo", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
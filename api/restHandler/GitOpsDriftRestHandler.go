@@ -0,0 +1,154 @@
+package restHandler
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/app"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+type GitOpsDriftRestHandler interface {
+	GetDriftReports(w http.ResponseWriter, r *http.Request)
+	GetDriftReport(w http.ResponseWriter, r *http.Request)
+	DetectDrift(w http.ResponseWriter, r *http.Request)
+	ReassertDevtronState(w http.ResponseWriter, r *http.Request)
+}
+
+type GitOpsDriftRestHandlerImpl struct {
+	logger             *zap.SugaredLogger
+	userAuthService    user.UserService
+	enforcer           casbin.Enforcer
+	enforcerUtil       rbac.EnforcerUtil
+	gitOpsDriftService app.GitOpsDriftService
+}
+
+func NewGitOpsDriftRestHandlerImpl(logger *zap.SugaredLogger, userAuthService user.UserService, enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil, gitOpsDriftService app.GitOpsDriftService) *GitOpsDriftRestHandlerImpl {
+	return &GitOpsDriftRestHandlerImpl{
+		logger:             logger,
+		userAuthService:    userAuthService,
+		enforcer:           enforcer,
+		enforcerUtil:       enforcerUtil,
+		gitOpsDriftService: gitOpsDriftService,
+	}
+}
+
+func (handler GitOpsDriftRestHandlerImpl) GetDriftReports(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, err := strconv.Atoi(r.URL.Query().Get("appId"))
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	//RBAC START
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//RBAC END
+	res, err := handler.gitOpsDriftService.GetDriftReports(appId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetDriftReports", "err", err, "appId", appId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler GitOpsDriftRestHandlerImpl) GetDriftReport(w http.ResponseWriter, r *http.Request) {
+	appId, envId, ok := handler.authorize(w, r, false)
+	if !ok {
+		return
+	}
+	res, err := handler.gitOpsDriftService.GetDriftReport(appId, envId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetDriftReport", "err", err, "appId", appId, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// DetectDrift runs a detection on demand, it clones the gitops repo and queries argo cd so it needs the same access as
+// re-asserting the state
+func (handler GitOpsDriftRestHandlerImpl) DetectDrift(w http.ResponseWriter, r *http.Request) {
+	appId, envId, ok := handler.authorize(w, r, true)
+	if !ok {
+		return
+	}
+	res, err := handler.gitOpsDriftService.DetectDrift(appId, envId)
+	if err != nil {
+		handler.logger.Errorw("service err, DetectDrift", "err", err, "appId", appId, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler GitOpsDriftRestHandlerImpl) ReassertDevtronState(w http.ResponseWriter, r *http.Request) {
+	appId, envId, ok := handler.authorize(w, r, true)
+	if !ok {
+		return
+	}
+	userId, _ := handler.userAuthService.GetLoggedInUser(r)
+	res, err := handler.gitOpsDriftService.ReassertDevtronState(appId, envId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, ReassertDevtronState", "err", err, "appId", appId, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// authorize reads app and env from the path and checks app view access, or trigger access on app and env when the
+// request acts on the deployment
+func (handler GitOpsDriftRestHandlerImpl) authorize(w http.ResponseWriter, r *http.Request, trigger bool) (appId, envId int, ok bool) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, 0, false
+	}
+	vars := mux.Vars(r)
+	appId, err = strconv.Atoi(vars["appId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return 0, 0, false
+	}
+	envId, err = strconv.Atoi(vars["envId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return 0, 0, false
+	}
+	//RBAC START
+	token := r.Header.Get("token")
+	appAction := casbin.ActionGet
+	if trigger {
+		appAction = casbin.ActionTrigger
+	}
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, appAction, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return 0, 0, false
+	}
+	if trigger {
+		object = handler.enforcerUtil.GetEnvRBACNameByAppId(appId, envId)
+		if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionTrigger, object); !ok {
+			common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+			return 0, 0, false
+		}
+	}
+	//RBAC END
+	return appId, envId, true
+}
@@ -121,7 +121,7 @@ func (impl PolicyRestHandlerImpl) SavePolicy(w http.ResponseWriter, r *http.Requ
 	}
 	//AUTH
 
-	res, err := impl.policyService.SavePolicy(req, userId)
+	res, err := impl.policyService.SavePolicy(r.Context(), req, userId)
 	if err != nil {
 		impl.logger.Errorw("service err, SavePolicy", "err", err, "payload", req)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
@@ -191,7 +191,7 @@ func (impl PolicyRestHandlerImpl) UpdatePolicy(w http.ResponseWriter, r *http.Re
 	}
 	//AUTH
 
-	res, err := impl.policyService.UpdatePolicy(req, userId)
+	res, err := impl.policyService.UpdatePolicy(r.Context(), req, userId)
 	if err != nil {
 		impl.logger.Errorw("service err, UpdatePolicy", "err", err, "payload", req)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
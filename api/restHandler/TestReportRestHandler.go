@@ -0,0 +1,263 @@
+package restHandler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/pipeline/testReport"
+	"github.com/devtron-labs/devtron/pkg/pipeline/testReport/bean"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type TestReportRestHandler interface {
+	GetCiRunReport(w http.ResponseWriter, r *http.Request)
+	GetCdRunReport(w http.ResponseWriter, r *http.Request)
+	GetCiFlakyTests(w http.ResponseWriter, r *http.Request)
+	GetCdFlakyTests(w http.ResponseWriter, r *http.Request)
+	GetCiSlowestTests(w http.ResponseWriter, r *http.Request)
+	GetCdSlowestTests(w http.ResponseWriter, r *http.Request)
+	GetCiPipelineConfig(w http.ResponseWriter, r *http.Request)
+	SaveCiPipelineConfig(w http.ResponseWriter, r *http.Request)
+}
+
+type TestReportRestHandlerImpl struct {
+	ciPipelineAuthorizer
+	validator          *validator.Validate
+	testReportService  testReport.TestReportService
+	pipelineRepository pipelineConfig.PipelineRepository
+}
+
+func NewTestReportRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	validator *validator.Validate, enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil,
+	testReportService testReport.TestReportService, ciPipelineRepository pipelineConfig.CiPipelineRepository,
+	pipelineRepository pipelineConfig.PipelineRepository) *TestReportRestHandlerImpl {
+	return &TestReportRestHandlerImpl{
+		ciPipelineAuthorizer: newCiPipelineAuthorizer(logger, userService, enforcer, enforcerUtil, ciPipelineRepository),
+		validator:            validator,
+		testReportService:    testReportService,
+		pipelineRepository:   pipelineRepository,
+	}
+}
+
+func (handler *TestReportRestHandlerImpl) GetCiRunReport(w http.ResponseWriter, r *http.Request) {
+	ciPipeline, ok := handler.authorizeCiPipeline(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	workflowId, err := strconv.Atoi(mux.Vars(r)["workflowId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	handler.writeRunReport(w, bean.WorkflowTypeCi, ciPipeline.Id, workflowId)
+}
+
+func (handler *TestReportRestHandlerImpl) GetCdRunReport(w http.ResponseWriter, r *http.Request) {
+	pipelineId, ok := handler.authorizeCdPipeline(w, r)
+	if !ok {
+		return
+	}
+	stage, err := getCdStage(r)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	wfrId, err := strconv.Atoi(mux.Vars(r)["wfrId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	handler.writeRunReport(w, stage, pipelineId, wfrId)
+}
+
+func (handler *TestReportRestHandlerImpl) writeRunReport(w http.ResponseWriter, workflowType string, pipelineId int, workflowId int) {
+	report, err := handler.testReportService.GetRunReport(workflowType, workflowId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetRunReport", "err", err, "workflowType", workflowType, "workflowId", workflowId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if len(report.Suites) > 0 && report.PipelineId != pipelineId {
+		common.WriteJsonResp(w, errors.New("invalid request, wf not in pipeline"), nil, http.StatusBadRequest)
+		return
+	}
+	report.PipelineId = pipelineId
+	common.WriteJsonResp(w, nil, report, http.StatusOK)
+}
+
+func (handler *TestReportRestHandlerImpl) GetCiFlakyTests(w http.ResponseWriter, r *http.Request) {
+	ciPipeline, ok := handler.authorizeCiPipeline(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	handler.writeFlakyTests(w, r, bean.WorkflowTypeCi, ciPipeline.Id)
+}
+
+func (handler *TestReportRestHandlerImpl) GetCdFlakyTests(w http.ResponseWriter, r *http.Request) {
+	pipelineId, ok := handler.authorizeCdPipeline(w, r)
+	if !ok {
+		return
+	}
+	stage, err := getCdStage(r)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	handler.writeFlakyTests(w, r, stage, pipelineId)
+}
+
+func (handler *TestReportRestHandlerImpl) writeFlakyTests(w http.ResponseWriter, r *http.Request, workflowType string, pipelineId int) {
+	runs, err := getIntQueryParam(r, "runs")
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	flakyTests, err := handler.testReportService.GetFlakyTests(workflowType, pipelineId, runs)
+	if err != nil {
+		handler.logger.Errorw("service err, GetFlakyTests", "err", err, "workflowType", workflowType, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, flakyTests, http.StatusOK)
+}
+
+func (handler *TestReportRestHandlerImpl) GetCiSlowestTests(w http.ResponseWriter, r *http.Request) {
+	ciPipeline, ok := handler.authorizeCiPipeline(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	handler.writeSlowestTests(w, r, bean.WorkflowTypeCi, ciPipeline.Id)
+}
+
+func (handler *TestReportRestHandlerImpl) GetCdSlowestTests(w http.ResponseWriter, r *http.Request) {
+	pipelineId, ok := handler.authorizeCdPipeline(w, r)
+	if !ok {
+		return
+	}
+	stage, err := getCdStage(r)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	handler.writeSlowestTests(w, r, stage, pipelineId)
+}
+
+func (handler *TestReportRestHandlerImpl) writeSlowestTests(w http.ResponseWriter, r *http.Request, workflowType string, pipelineId int) {
+	runs, err := getIntQueryParam(r, "runs")
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	limit, err := getIntQueryParam(r, "limit")
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	slowTests, err := handler.testReportService.GetSlowestTests(workflowType, pipelineId, runs, limit)
+	if err != nil {
+		handler.logger.Errorw("service err, GetSlowestTests", "err", err, "workflowType", workflowType, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, slowTests, http.StatusOK)
+}
+
+func (handler *TestReportRestHandlerImpl) GetCiPipelineConfig(w http.ResponseWriter, r *http.Request) {
+	ciPipeline, ok := handler.authorizeCiPipeline(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	config, err := handler.testReportService.GetCiPipelineConfig(ciPipeline.Id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetCiPipelineConfig", "err", err, "pipelineId", ciPipeline.Id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if config == nil {
+		config = &bean.TestReportConfig{CiPipelineId: ciPipeline.Id}
+	}
+	common.WriteJsonResp(w, nil, config, http.StatusOK)
+}
+
+func (handler *TestReportRestHandlerImpl) SaveCiPipelineConfig(w http.ResponseWriter, r *http.Request) {
+	ciPipeline, ok := handler.authorizeCiPipeline(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var config bean.TestReportConfig
+	err = json.NewDecoder(r.Body).Decode(&config)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveCiPipelineConfig", "err", err, "payload", config)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	config.CiPipelineId = ciPipeline.Id
+	err = handler.validator.Struct(config)
+	if err != nil {
+		handler.logger.Errorw("validation err, SaveCiPipelineConfig", "err", err, "payload", config)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.testReportService.SaveCiPipelineConfig(&config, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, SaveCiPipelineConfig", "err", err, "payload", config)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *TestReportRestHandlerImpl) authorizeCdPipeline(w http.ResponseWriter, r *http.Request) (int, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, false
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return 0, false
+	}
+	cdPipeline, err := handler.pipelineRepository.FindById(pipelineId)
+	if err != nil {
+		handler.logger.Errorw("error in getting cd pipeline", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return 0, false
+	}
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(cdPipeline.AppId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return 0, false
+	}
+	return pipelineId, true
+}
+
+func getCdStage(r *http.Request) (string, error) {
+	stage := r.URL.Query().Get("stage")
+	if stage != bean.WorkflowTypePreCd && stage != bean.WorkflowTypePostCd {
+		return "", errors.New("stage must be PRE or POST")
+	}
+	return stage, nil
+}
+
+func getIntQueryParam(r *http.Request, name string) (int, error) {
+	value := r.URL.Query().Get(name)
+	if len(value) == 0 {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
@@ -436,7 +436,7 @@ func (handler PipelineConfigRestHandlerImpl) PatchCiPipelines(w http.ResponseWri
 			},
 		}
 	}
-	createResp, err := handler.pipelineBuilder.PatchCiPipeline(&patchRequest)
+	createResp, err := handler.pipelineBuilder.PatchCiPipeline(&patchRequest, r.Context())
 	if err != nil {
 		handler.Logger.Errorw("service err, PatchCiPipelines", "err", err, "PatchCiPipelines", patchRequest)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
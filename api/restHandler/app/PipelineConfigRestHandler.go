@@ -231,7 +231,7 @@ func (handler PipelineConfigRestHandlerImpl) DeleteApp(w http.ResponseWriter, r
 			return
 		}
 	}
-	err = handler.pipelineBuilder.DeleteApp(appId, userId)
+	err = handler.pipelineBuilder.DeleteApp(appId, userId, r.Context())
 	if err != nil {
 		handler.Logger.Errorw("service error, delete app", "err", err, "appId", appId)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
@@ -367,7 +367,7 @@ func (handler PipelineConfigRestHandlerImpl) CreateApp(w http.ResponseWriter, r
 	var createResp *bean.CreateAppDTO
 	err = nil
 	if createRequest.TemplateId == 0 {
-		createResp, err = handler.pipelineBuilder.CreateApp(&createRequest)
+		createResp, err = handler.pipelineBuilder.CreateApp(&createRequest, r.Context())
 	} else {
 		ctx, cancel := context.WithCancel(r.Context())
 		if cn, ok := w.(http.CloseNotifier); ok {
@@ -0,0 +1,24 @@
+package common
+
+import (
+	"errors"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"net/http"
+)
+
+// AuthorizeSuperAdmin checks that the logged in user is a super admin, it is used by the apis spanning all teams of a
+// cluster. The response is written and false is returned when the request is not authorized.
+func AuthorizeSuperAdmin(w http.ResponseWriter, r *http.Request, userService user.UserService, enforcer casbin.Enforcer, action string) (int32, bool) {
+	userId, err := userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, false
+	}
+	token := r.Header.Get("token")
+	if ok := enforcer.Enforce(token, casbin.ResourceGlobal, action, "*"); !ok {
+		WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return 0, false
+	}
+	return userId, true
+}
@@ -27,10 +27,12 @@ type GitOpsConfigRouter interface {
 }
 type GitOpsConfigRouterImpl struct {
 	gitOpsConfigRestHandler restHandler.GitOpsConfigRestHandler
+	gitOpsDriftRestHandler  restHandler.GitOpsDriftRestHandler
 }
 
-func NewGitOpsConfigRouterImpl(gitOpsConfigRestHandler restHandler.GitOpsConfigRestHandler) *GitOpsConfigRouterImpl {
-	return &GitOpsConfigRouterImpl{gitOpsConfigRestHandler: gitOpsConfigRestHandler}
+func NewGitOpsConfigRouterImpl(gitOpsConfigRestHandler restHandler.GitOpsConfigRestHandler,
+	gitOpsDriftRestHandler restHandler.GitOpsDriftRestHandler) *GitOpsConfigRouterImpl {
+	return &GitOpsConfigRouterImpl{gitOpsConfigRestHandler: gitOpsConfigRestHandler, gitOpsDriftRestHandler: gitOpsDriftRestHandler}
 }
 func (impl GitOpsConfigRouterImpl) InitGitOpsConfigRouter(configRouter *mux.Router) {
 	configRouter.Path("/config").
@@ -54,4 +56,19 @@ func (impl GitOpsConfigRouterImpl) InitGitOpsConfigRouter(configRouter *mux.Rout
 	configRouter.Path("/validate").
 		HandlerFunc(impl.gitOpsConfigRestHandler.GitOpsValidator).
 		Methods("POST")
+	configRouter.Path("/monorepo/migrate").
+		HandlerFunc(impl.gitOpsConfigRestHandler.MigrateAppsToMonorepo).
+		Methods("POST")
+	configRouter.Path("/drift").
+		HandlerFunc(impl.gitOpsDriftRestHandler.GetDriftReports).Queries("appId", "{appId}").
+		Methods("GET")
+	configRouter.Path("/drift/{appId}/{envId}").
+		HandlerFunc(impl.gitOpsDriftRestHandler.GetDriftReport).
+		Methods("GET")
+	configRouter.Path("/drift/{appId}/{envId}/detect").
+		HandlerFunc(impl.gitOpsDriftRestHandler.DetectDrift).
+		Methods("POST")
+	configRouter.Path("/drift/{appId}/{envId}/reassert").
+		HandlerFunc(impl.gitOpsDriftRestHandler.ReassertDevtronState).
+		Methods("POST")
 }
@@ -32,19 +32,25 @@ type PipelineConfigRouterImpl struct {
 	webhookDataRestHandler            restHandler.WebhookDataRestHandler
 	pipelineHistoryRestHandler        restHandler.PipelineHistoryRestHandler
 	pipelineStatusTimelineRestHandler restHandler.PipelineStatusTimelineRestHandler
+	buildMatrixRestHandler            restHandler.BuildMatrixRestHandler
+	buildCacheRestHandler             restHandler.BuildCacheRestHandler
 }
 
 func NewPipelineRouterImpl(restHandler app.PipelineConfigRestHandler,
 	appWorkflowRestHandler restHandler.AppWorkflowRestHandler,
 	webhookDataRestHandler restHandler.WebhookDataRestHandler,
 	pipelineHistoryRestHandler restHandler.PipelineHistoryRestHandler,
-	pipelineStatusTimelineRestHandler restHandler.PipelineStatusTimelineRestHandler) *PipelineConfigRouterImpl {
+	pipelineStatusTimelineRestHandler restHandler.PipelineStatusTimelineRestHandler,
+	buildMatrixRestHandler restHandler.BuildMatrixRestHandler,
+	buildCacheRestHandler restHandler.BuildCacheRestHandler) *PipelineConfigRouterImpl {
 	return &PipelineConfigRouterImpl{
 		restHandler:                       restHandler,
 		appWorkflowRestHandler:            appWorkflowRestHandler,
 		webhookDataRestHandler:            webhookDataRestHandler,
 		pipelineHistoryRestHandler:        pipelineHistoryRestHandler,
 		pipelineStatusTimelineRestHandler: pipelineStatusTimelineRestHandler,
+		buildMatrixRestHandler:            buildMatrixRestHandler,
+		buildCacheRestHandler:             buildCacheRestHandler,
 	}
 }
 
@@ -115,6 +121,17 @@ func (router PipelineConfigRouterImpl) initPipelineConfigRouter(configRouter *mu
 	configRouter.Path("/ci-pipeline/{pipelineId}/workflow/{workflowId}/logs").HandlerFunc(router.restHandler.GetBuildLogs).Methods("GET")
 	configRouter.Path("/ci-pipeline/{pipelineId}/workflows").HandlerFunc(router.restHandler.GetBuildHistory).Methods("GET")
 	configRouter.Path("/ci-pipeline/{pipelineId}/workflow/{workflowId}").HandlerFunc(router.restHandler.CancelWorkflow).Methods("DELETE")
+
+	configRouter.Path("/ci-pipeline/{pipelineId}/build-matrix").HandlerFunc(router.buildMatrixRestHandler.GetBuildMatrix).Methods("GET")
+	configRouter.Path("/ci-pipeline/{pipelineId}/build-matrix").HandlerFunc(router.buildMatrixRestHandler.SaveBuildMatrix).Methods("PUT")
+	configRouter.Path("/ci-pipeline/{pipelineId}/matrix-runs").HandlerFunc(router.buildMatrixRestHandler.GetMatrixRuns).Methods("GET")
+	configRouter.Path("/ci-pipeline/{pipelineId}/matrix-run/{runId}").HandlerFunc(router.buildMatrixRestHandler.GetMatrixRun).Methods("GET")
+
+	configRouter.Path("/ci-pipeline/{pipelineId}/build-cache").HandlerFunc(router.buildCacheRestHandler.GetBuildCacheConfig).Methods("GET")
+	configRouter.Path("/ci-pipeline/{pipelineId}/build-cache").HandlerFunc(router.buildCacheRestHandler.SaveBuildCacheConfig).Methods("PUT")
+	configRouter.Path("/ci-pipeline/{pipelineId}/build-cache/caches").HandlerFunc(router.buildCacheRestHandler.GetBuildCaches).Methods("GET")
+	configRouter.Path("/ci-pipeline/{pipelineId}/build-cache/caches").HandlerFunc(router.buildCacheRestHandler.PurgeBuildCaches).Methods("DELETE")
+	configRouter.Path("/ci-pipeline/{pipelineId}/build-cache/caches/{cacheId}").HandlerFunc(router.buildCacheRestHandler.PurgeBuildCaches).Methods("DELETE")
 	configRouter.Path("/cd-pipeline/{pipelineId}/workflowRunner/{workflowRunnerId}").HandlerFunc(router.restHandler.CancelStage).Methods("DELETE")
 
 	configRouter.Path("/{appId}/autocomplete/environment").HandlerFunc(router.restHandler.EnvironmentListAutocomplete).Methods("GET")
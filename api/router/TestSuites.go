@@ -26,11 +26,13 @@ type TestSuitRouter interface {
 	InitTestSuitRouter(gocdRouter *mux.Router)
 }
 type TestSuitRouterImpl struct {
-	testSuitRouter restHandler.TestSuitRestHandler
+	testSuitRouter        restHandler.TestSuitRestHandler
+	testReportRestHandler restHandler.TestReportRestHandler
 }
 
-func NewTestSuitRouterImpl(testSuitRouter restHandler.TestSuitRestHandler) *TestSuitRouterImpl {
-	return &TestSuitRouterImpl{testSuitRouter: testSuitRouter}
+func NewTestSuitRouterImpl(testSuitRouter restHandler.TestSuitRestHandler,
+	testReportRestHandler restHandler.TestReportRestHandler) *TestSuitRouterImpl {
+	return &TestSuitRouterImpl{testSuitRouter: testSuitRouter, testReportRestHandler: testReportRestHandler}
 }
 
 func (impl TestSuitRouterImpl) InitTestSuitRouter(configRouter *mux.Router) {
@@ -42,4 +44,13 @@ func (impl TestSuitRouterImpl) InitTestSuitRouter(configRouter *mux.Router) {
 	configRouter.Path("/cases/{pipelineId}").HandlerFunc(impl.testSuitRouter.GetTestCaseByID).Methods("GET")
 	configRouter.Path("/trigger/{pipelineId}").HandlerFunc(impl.testSuitRouter.RedirectTriggerForApp).Methods("GET")
 	configRouter.Path("/trigger/{pipelineId}/{triggerId}").HandlerFunc(impl.testSuitRouter.RedirectTriggerForEnv).Methods("GET")
+
+	configRouter.Path("/ci-pipeline/{pipelineId}/workflow/{workflowId}").HandlerFunc(impl.testReportRestHandler.GetCiRunReport).Methods("GET")
+	configRouter.Path("/ci-pipeline/{pipelineId}/flaky-tests").HandlerFunc(impl.testReportRestHandler.GetCiFlakyTests).Methods("GET")
+	configRouter.Path("/ci-pipeline/{pipelineId}/slowest-tests").HandlerFunc(impl.testReportRestHandler.GetCiSlowestTests).Methods("GET")
+	configRouter.Path("/ci-pipeline/{pipelineId}/config").HandlerFunc(impl.testReportRestHandler.GetCiPipelineConfig).Methods("GET")
+	configRouter.Path("/ci-pipeline/{pipelineId}/config").HandlerFunc(impl.testReportRestHandler.SaveCiPipelineConfig).Methods("PUT")
+	configRouter.Path("/cd-pipeline/{pipelineId}/workflow-runner/{wfrId}").HandlerFunc(impl.testReportRestHandler.GetCdRunReport).Methods("GET")
+	configRouter.Path("/cd-pipeline/{pipelineId}/flaky-tests").HandlerFunc(impl.testReportRestHandler.GetCdFlakyTests).Methods("GET")
+	configRouter.Path("/cd-pipeline/{pipelineId}/slowest-tests").HandlerFunc(impl.testReportRestHandler.GetCdSlowestTests).Methods("GET")
 }
@@ -26,7 +26,9 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
+	artifactOutputBean "github.com/devtron-labs/devtron/pkg/pipeline/artifactOutput/bean"
 	bean2 "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	stepStatusBean "github.com/devtron-labs/devtron/pkg/pipeline/stepStatus/bean"
 	"github.com/devtron-labs/devtron/util"
 	"go.uber.org/zap"
 	"time"
@@ -61,20 +63,22 @@ type ImageDetailsFromCR struct {
 }
 
 type CiCompleteEvent struct {
-	CiProjectDetails   []bean2.CiProjectDetails `json:"ciProjectDetails"`
-	DockerImage        string                   `json:"dockerImage" validate:"required,image-validator"`
-	Digest             string                   `json:"digest"`
-	PipelineId         int                      `json:"pipelineId"`
-	WorkflowId         *int                     `json:"workflowId"`
-	TriggeredBy        int32                    `json:"triggeredBy"`
-	PipelineName       string                   `json:"pipelineName"`
-	DataSource         string                   `json:"dataSource"`
-	MaterialType       string                   `json:"materialType"`
-	Metrics            util.CIMetrics           `json:"metrics"`
-	AppName            string                   `json:"appName"`
-	IsArtifactUploaded bool                     `json:"isArtifactUploaded"`
-	FailureReason      string                   `json:"failureReason"`
-	ImageDetailsFromCR *ImageDetailsFromCR      `json:"imageDetailsFromCR"`
+	CiProjectDetails   []bean2.CiProjectDetails             `json:"ciProjectDetails"`
+	DockerImage        string                               `json:"dockerImage" validate:"required,image-validator"`
+	Digest             string                               `json:"digest"`
+	PipelineId         int                                  `json:"pipelineId"`
+	WorkflowId         *int                                 `json:"workflowId"`
+	TriggeredBy        int32                                `json:"triggeredBy"`
+	PipelineName       string                               `json:"pipelineName"`
+	DataSource         string                               `json:"dataSource"`
+	MaterialType       string                               `json:"materialType"`
+	Metrics            util.CIMetrics                       `json:"metrics"`
+	AppName            string                               `json:"appName"`
+	IsArtifactUploaded bool                                 `json:"isArtifactUploaded"`
+	FailureReason      string                               `json:"failureReason"`
+	ImageDetailsFromCR *ImageDetailsFromCR                  `json:"imageDetailsFromCR"`
+	StepStatuses       []*stepStatusBean.StepStatus         `json:"stepStatuses"`
+	ArtifactOutputs    []*artifactOutputBean.ArtifactOutput `json:"artifactOutputs"`
 }
 
 func NewCiEventHandlerImpl(logger *zap.SugaredLogger, pubsubClient *pubsub.PubSubClientServiceImpl, webhookService pipeline.WebhookService, ciEventConfig *CiEventConfig) *CiEventHandlerImpl {
@@ -215,6 +219,8 @@ func (impl *CiEventHandlerImpl) BuildCiArtifactRequest(event CiCompleteEvent) (*
 		UserId:             event.TriggeredBy,
 		WorkflowId:         event.WorkflowId,
 		IsArtifactUploaded: event.IsArtifactUploaded,
+		StepStatuses:       event.StepStatuses,
+		ArtifactOutputs:    event.ArtifactOutputs,
 	}
 	return request, nil
 }
@@ -231,6 +237,8 @@ func (impl *CiEventHandlerImpl) BuildCIArtifactRequestForImageFromCR(imageDetail
 		UserId:             event.TriggeredBy,
 		WorkflowId:         event.WorkflowId,
 		IsArtifactUploaded: event.IsArtifactUploaded,
+		StepStatuses:       event.StepStatuses,
+		ArtifactOutputs:    event.ArtifactOutputs,
 	}
 	return request, nil
 }
@@ -301,6 +309,8 @@ func (impl *CiEventHandlerImpl) BuildCiArtifactRequestForWebhook(event CiComplet
 		UserId:             event.TriggeredBy,
 		WorkflowId:         event.WorkflowId,
 		IsArtifactUploaded: event.IsArtifactUploaded,
+		StepStatuses:       event.StepStatuses,
+		ArtifactOutputs:    event.ArtifactOutputs,
 	}
 	return request, nil
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/devtron-labs/devtron/api/apiToken"
 	"github.com/devtron-labs/devtron/api/appStore"
 	appStoreDeployment "github.com/devtron-labs/devtron/api/appStore/deployment"
+	"github.com/devtron-labs/devtron/api/auditLog"
 	"github.com/devtron-labs/devtron/api/chartRepo"
 	"github.com/devtron-labs/devtron/api/cluster"
 	"github.com/devtron-labs/devtron/api/dashboardEvent"
@@ -122,6 +123,10 @@ type MuxRouter struct {
 	rbacRoleRouter                     user.RbacRoleRouter
 	scopedVariableRouter               ScopedVariableRouter
 	ciTriggerCron                      cron.CiTriggerCron
+	scimRouter                         user.ScimRouter
+	auditLogRouter                     auditLog.AuditLogRouter
+	gitOpsPullRequestCron              cron.GitOpsPullRequestCron
+	gitOpsDriftDetectionCron           cron.GitOpsDriftDetectionCron
 }
 
 func NewMuxRouter(logger *zap.SugaredLogger, HelmRouter PipelineTriggerRouter, PipelineConfigRouter PipelineConfigRouter,
@@ -153,7 +158,11 @@ func NewMuxRouter(logger *zap.SugaredLogger, HelmRouter PipelineTriggerRouter, P
 	jobRouter JobRouter, ciStatusUpdateCron cron.CiStatusUpdateCron, resourceGroupingRouter ResourceGroupingRouter,
 	rbacRoleRouter user.RbacRoleRouter,
 	scopedVariableRouter ScopedVariableRouter,
-	ciTriggerCron cron.CiTriggerCron) *MuxRouter {
+	ciTriggerCron cron.CiTriggerCron,
+	scimRouter user.ScimRouter,
+	auditLogRouter auditLog.AuditLogRouter,
+	gitOpsPullRequestCron cron.GitOpsPullRequestCron,
+	gitOpsDriftDetectionCron cron.GitOpsDriftDetectionCron) *MuxRouter {
 	r := &MuxRouter{
 		Router:                             mux.NewRouter(),
 		HelmRouter:                         HelmRouter,
@@ -225,6 +234,10 @@ func NewMuxRouter(logger *zap.SugaredLogger, HelmRouter PipelineTriggerRouter, P
 		rbacRoleRouter:                     rbacRoleRouter,
 		scopedVariableRouter:               scopedVariableRouter,
 		ciTriggerCron:                      ciTriggerCron,
+		scimRouter:                         scimRouter,
+		auditLogRouter:                     auditLogRouter,
+		gitOpsPullRequestCron:              gitOpsPullRequestCron,
+		gitOpsDriftDetectionCron:           gitOpsDriftDetectionCron,
 	}
 	return r
 }
@@ -435,4 +448,10 @@ func (r MuxRouter) Init() {
 
 	rbacRoleRouter := r.Router.PathPrefix("/orchestrator/rbac/role").Subrouter()
 	r.rbacRoleRouter.InitRbacRoleRouter(rbacRoleRouter)
+
+	scimRouter := r.Router.PathPrefix("/orchestrator/scim/v2").Subrouter()
+	r.scimRouter.InitScimRouter(scimRouter)
+
+	auditLogRouter := r.Router.PathPrefix("/orchestrator/audit-log").Subrouter()
+	r.auditLogRouter.InitAuditLogRouter(auditLogRouter)
 }
@@ -0,0 +1,352 @@
+package user
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/pkg/user/scim"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const scimContentType = "application/scim+json"
+
+type ScimRestHandler interface {
+	ListUsers(w http.ResponseWriter, r *http.Request)
+	GetUser(w http.ResponseWriter, r *http.Request)
+	CreateUser(w http.ResponseWriter, r *http.Request)
+	ReplaceUser(w http.ResponseWriter, r *http.Request)
+	PatchUser(w http.ResponseWriter, r *http.Request)
+	DeleteUser(w http.ResponseWriter, r *http.Request)
+	ListGroups(w http.ResponseWriter, r *http.Request)
+	GetGroup(w http.ResponseWriter, r *http.Request)
+	CreateGroup(w http.ResponseWriter, r *http.Request)
+	ReplaceGroup(w http.ResponseWriter, r *http.Request)
+	PatchGroup(w http.ResponseWriter, r *http.Request)
+	DeleteGroup(w http.ResponseWriter, r *http.Request)
+	GetServiceProviderConfig(w http.ResponseWriter, r *http.Request)
+}
+
+type ScimRestHandlerImpl struct {
+	logger      *zap.SugaredLogger
+	scimService scim.ScimService
+	userService user.UserService
+	enforcer    casbin.Enforcer
+}
+
+func NewScimRestHandlerImpl(logger *zap.SugaredLogger, scimService scim.ScimService, userService user.UserService,
+	enforcer casbin.Enforcer) *ScimRestHandlerImpl {
+	return &ScimRestHandlerImpl{
+		logger:      logger,
+		scimService: scimService,
+		userService: userService,
+		enforcer:    enforcer,
+	}
+}
+
+func (handler ScimRestHandlerImpl) ListUsers(w http.ResponseWriter, r *http.Request) {
+	_, _, ok := handler.authorize(w, r)
+	if !ok {
+		return
+	}
+	res, err := handler.scimService.ListUsers(getScimListRequest(r))
+	if err != nil {
+		handler.logger.Errorw("service err, ListUsers", "err", err)
+	}
+	writeScimResponse(w, err, res, http.StatusOK)
+}
+
+func (handler ScimRestHandlerImpl) GetUser(w http.ResponseWriter, r *http.Request) {
+	_, _, ok := handler.authorize(w, r)
+	if !ok {
+		return
+	}
+	id, ok := getScimResourceId(w, r)
+	if !ok {
+		return
+	}
+	res, err := handler.scimService.GetUser(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetUser", "err", err, "id", id)
+	}
+	writeScimResponse(w, err, res, http.StatusOK)
+}
+
+func (handler ScimRestHandlerImpl) CreateUser(w http.ResponseWriter, r *http.Request) {
+	userId, token, ok := handler.authorize(w, r)
+	if !ok {
+		return
+	}
+	var request scim.User
+	if !decodeScimRequest(w, r, &request) {
+		return
+	}
+	handler.logger.Infow("request payload, scim CreateUser", "request", request)
+	res, err := handler.scimService.CreateUser(r.Context(), &request, userId, token, handler.checkManagerAuth)
+	if err != nil {
+		handler.logger.Errorw("service err, CreateUser", "err", err, "payload", request)
+	}
+	writeScimResponse(w, err, res, http.StatusCreated)
+}
+
+func (handler ScimRestHandlerImpl) ReplaceUser(w http.ResponseWriter, r *http.Request) {
+	userId, token, ok := handler.authorize(w, r)
+	if !ok {
+		return
+	}
+	id, ok := getScimResourceId(w, r)
+	if !ok {
+		return
+	}
+	var request scim.User
+	if !decodeScimRequest(w, r, &request) {
+		return
+	}
+	handler.logger.Infow("request payload, scim ReplaceUser", "id", id, "request", request)
+	res, err := handler.scimService.ReplaceUser(r.Context(), id, &request, userId, token, handler.checkManagerAuth)
+	if err != nil {
+		handler.logger.Errorw("service err, ReplaceUser", "err", err, "id", id, "payload", request)
+	}
+	writeScimResponse(w, err, res, http.StatusOK)
+}
+
+func (handler ScimRestHandlerImpl) PatchUser(w http.ResponseWriter, r *http.Request) {
+	userId, token, ok := handler.authorize(w, r)
+	if !ok {
+		return
+	}
+	id, ok := getScimResourceId(w, r)
+	if !ok {
+		return
+	}
+	var request scim.PatchRequest
+	if !decodeScimRequest(w, r, &request) {
+		return
+	}
+	handler.logger.Infow("request payload, scim PatchUser", "id", id, "request", request)
+	res, err := handler.scimService.PatchUser(r.Context(), id, &request, userId, token, handler.checkManagerAuth)
+	if err != nil {
+		handler.logger.Errorw("service err, PatchUser", "err", err, "id", id, "payload", request)
+	}
+	writeScimResponse(w, err, res, http.StatusOK)
+}
+
+func (handler ScimRestHandlerImpl) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	userId, _, ok := handler.authorize(w, r)
+	if !ok {
+		return
+	}
+	id, ok := getScimResourceId(w, r)
+	if !ok {
+		return
+	}
+	handler.logger.Infow("request payload, scim DeleteUser", "id", id)
+	err := handler.scimService.DeleteUser(r.Context(), id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteUser", "err", err, "id", id)
+	}
+	writeScimResponse(w, err, nil, http.StatusNoContent)
+}
+
+func (handler ScimRestHandlerImpl) ListGroups(w http.ResponseWriter, r *http.Request) {
+	_, _, ok := handler.authorize(w, r)
+	if !ok {
+		return
+	}
+	res, err := handler.scimService.ListGroups(getScimListRequest(r))
+	if err != nil {
+		handler.logger.Errorw("service err, ListGroups", "err", err)
+	}
+	writeScimResponse(w, err, res, http.StatusOK)
+}
+
+func (handler ScimRestHandlerImpl) GetGroup(w http.ResponseWriter, r *http.Request) {
+	_, _, ok := handler.authorize(w, r)
+	if !ok {
+		return
+	}
+	id, ok := getScimResourceId(w, r)
+	if !ok {
+		return
+	}
+	listRequest := getScimListRequest(r)
+	res, err := handler.scimService.GetGroup(id, strings.Contains(strings.ToLower(listRequest.ExcludedAttributes), "members"))
+	if err != nil {
+		handler.logger.Errorw("service err, GetGroup", "err", err, "id", id)
+	}
+	writeScimResponse(w, err, res, http.StatusOK)
+}
+
+func (handler ScimRestHandlerImpl) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	userId, token, ok := handler.authorize(w, r)
+	if !ok {
+		return
+	}
+	var request scim.Group
+	if !decodeScimRequest(w, r, &request) {
+		return
+	}
+	handler.logger.Infow("request payload, scim CreateGroup", "request", request)
+	res, err := handler.scimService.CreateGroup(r.Context(), &request, userId, token, handler.checkManagerAuth)
+	if err != nil {
+		handler.logger.Errorw("service err, CreateGroup", "err", err, "payload", request)
+	}
+	writeScimResponse(w, err, res, http.StatusCreated)
+}
+
+func (handler ScimRestHandlerImpl) ReplaceGroup(w http.ResponseWriter, r *http.Request) {
+	userId, token, ok := handler.authorize(w, r)
+	if !ok {
+		return
+	}
+	id, ok := getScimResourceId(w, r)
+	if !ok {
+		return
+	}
+	var request scim.Group
+	if !decodeScimRequest(w, r, &request) {
+		return
+	}
+	handler.logger.Infow("request payload, scim ReplaceGroup", "id", id, "request", request)
+	res, err := handler.scimService.ReplaceGroup(r.Context(), id, &request, userId, token, handler.checkManagerAuth)
+	if err != nil {
+		handler.logger.Errorw("service err, ReplaceGroup", "err", err, "id", id, "payload", request)
+	}
+	writeScimResponse(w, err, res, http.StatusOK)
+}
+
+func (handler ScimRestHandlerImpl) PatchGroup(w http.ResponseWriter, r *http.Request) {
+	userId, token, ok := handler.authorize(w, r)
+	if !ok {
+		return
+	}
+	id, ok := getScimResourceId(w, r)
+	if !ok {
+		return
+	}
+	var request scim.PatchRequest
+	if !decodeScimRequest(w, r, &request) {
+		return
+	}
+	handler.logger.Infow("request payload, scim PatchGroup", "id", id, "request", request)
+	res, err := handler.scimService.PatchGroup(r.Context(), id, &request, userId, token, handler.checkManagerAuth)
+	if err != nil {
+		handler.logger.Errorw("service err, PatchGroup", "err", err, "id", id, "payload", request)
+	}
+	writeScimResponse(w, err, res, http.StatusOK)
+}
+
+func (handler ScimRestHandlerImpl) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	userId, _, ok := handler.authorize(w, r)
+	if !ok {
+		return
+	}
+	id, ok := getScimResourceId(w, r)
+	if !ok {
+		return
+	}
+	handler.logger.Infow("request payload, scim DeleteGroup", "id", id)
+	err := handler.scimService.DeleteGroup(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteGroup", "err", err, "id", id)
+	}
+	writeScimResponse(w, err, nil, http.StatusNoContent)
+}
+
+func (handler ScimRestHandlerImpl) GetServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	_, _, ok := handler.authorize(w, r)
+	if !ok {
+		return
+	}
+	writeScimResponse(w, nil, handler.scimService.GetServiceProviderConfig(), http.StatusOK)
+}
+
+// authorize resolves the user of the token verified by the auth middleware, only super-admin tokens are accepted
+func (handler ScimRestHandlerImpl) authorize(w http.ResponseWriter, r *http.Request) (int32, string, bool) {
+	token := r.Header.Get("token")
+	if len(token) == 0 {
+		writeScimResponse(w, scim.NewError(http.StatusUnauthorized, "", "missing bearer token"), nil, 0)
+		return 0, "", false
+	}
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		writeScimResponse(w, scim.NewError(http.StatusUnauthorized, "", "unauthorized user"), nil, 0)
+		return 0, "", false
+	}
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		writeScimResponse(w, scim.NewError(http.StatusForbidden, "", "super-admin access is required for scim provisioning"), nil, 0)
+		return 0, "", false
+	}
+	return userId, token, true
+}
+
+func (handler ScimRestHandlerImpl) checkManagerAuth(resource, token, object string) bool {
+	if ok := handler.enforcer.Enforce(token, resource, casbin.ActionUpdate, strings.ToLower(object)); !ok {
+		return false
+	}
+	return true
+}
+
+func getScimListRequest(r *http.Request) *scim.ListRequest {
+	v := r.URL.Query()
+	request := &scim.ListRequest{
+		Filter:             v.Get("filter"),
+		StartIndex:         1,
+		Count:              scim.DefaultPageSize,
+		ExcludedAttributes: v.Get("excludedAttributes"),
+	}
+	if startIndex, err := strconv.Atoi(v.Get("startIndex")); err == nil {
+		request.StartIndex = startIndex
+	}
+	if count, err := strconv.Atoi(v.Get("count")); err == nil {
+		request.Count = count
+	}
+	return request
+}
+
+func getScimResourceId(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	vars := mux.Vars(r)
+	id, err := strconv.ParseInt(vars["id"], 10, 32)
+	if err != nil {
+		writeScimResponse(w, scim.NewNotFoundError("resource", vars["id"]), nil, 0)
+		return 0, false
+	}
+	return int32(id), true
+}
+
+func decodeScimRequest(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(request)
+	if err != nil {
+		writeScimResponse(w, scim.NewError(http.StatusBadRequest, scim.ScimTypeInvalidValue, "invalid request body: %s", err.Error()), nil, 0)
+		return false
+	}
+	return true
+}
+
+// writeScimResponse writes the resource without the devtron response envelope, errors are translated into scim errors
+func writeScimResponse(w http.ResponseWriter, err error, result interface{}, status int) {
+	w.Header().Set("Content-Type", scimContentType)
+	if err != nil {
+		scimErr, ok := err.(*scim.Error)
+		if !ok {
+			code := http.StatusInternalServerError
+			if apiErr, ok := err.(*util.ApiError); ok && apiErr.HttpStatusCode > 0 {
+				code = apiErr.HttpStatusCode
+			}
+			scimErr = scim.NewError(code, "", err.Error())
+		}
+		result = scimErr
+		status = scimErr.HttpStatusCode()
+	}
+	w.WriteHeader(status)
+	if result == nil {
+		return
+	}
+	_ = json.NewEncoder(w).Encode(result)
+}
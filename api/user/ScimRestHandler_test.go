@@ -0,0 +1,37 @@
+package user
+
+import (
+	"github.com/devtron-labs/authenticator/middleware"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScimUnauthenticatedRequest(t *testing.T) {
+	logger, _ := util.NewSugardLogger()
+	handler := NewScimRestHandlerImpl(logger, nil, nil, nil)
+	router := mux.NewRouter()
+	NewScimRouterImpl(handler).InitScimRouter(router.PathPrefix("/orchestrator/scim/v2").Subrouter())
+
+	// the scim routes are not whitelisted, the auth middleware rejects a request without a token
+	server := user.ScimTokenHandler(middleware.Authorizer(nil, user.WhitelistChecker)(router))
+	for _, path := range []string{"/orchestrator/scim/v2/Users", "/orchestrator/scim/v2/Groups/1", "/orchestrator/scim/v2/ServiceProviderConfig"} {
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code, path)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ListUsers(recorder, httptest.NewRequest(http.MethodGet, "/orchestrator/scim/v2/Users", nil))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	// the bearer token of an identity provider is passed to the auth middleware as the token header
+	request := httptest.NewRequest(http.MethodGet, "/orchestrator/scim/v2/Users", nil)
+	request.Header.Set("Authorization", "Bearer provisioning-token")
+	user.ScimTokenHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(httptest.NewRecorder(), request)
+	assert.Equal(t, "provisioning-token", request.Header.Get("token"))
+}
@@ -175,7 +175,7 @@ func (handler UserRestHandlerImpl) CreateUser(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	res, err := handler.userService.CreateUser(&userInfo, token, handler.CheckManagerAuth)
+	res, err := handler.userService.CreateUser(r.Context(), &userInfo, token, handler.CheckManagerAuth)
 	if err != nil {
 		handler.logger.Errorw("service err, CreateUser", "err", err, "payload", userInfo)
 		if _, ok := err.(*util.ApiError); ok {
@@ -224,7 +224,7 @@ func (handler UserRestHandlerImpl) UpdateUser(w http.ResponseWriter, r *http.Req
 		userInfo.EmailId = "admin"
 	}
 
-	res, rolesChanged, groupsModified, restrictedGroups, err := handler.userService.UpdateUser(&userInfo, token, handler.CheckManagerAuth)
+	res, rolesChanged, groupsModified, restrictedGroups, err := handler.userService.UpdateUser(r.Context(), &userInfo, token, handler.CheckManagerAuth)
 
 	if err != nil {
 		handler.logger.Errorw("service err, UpdateUser", "err", err, "payload", userInfo)
@@ -458,7 +458,7 @@ func (handler UserRestHandlerImpl) DeleteUser(w http.ResponseWriter, r *http.Req
 	}
 	//RBAC enforcer Ends
 
-	res, err := handler.userService.DeleteUser(user)
+	res, err := handler.userService.DeleteUser(r.Context(), user)
 	if err != nil {
 		handler.logger.Errorw("service err, DeleteUser", "err", err, "id", id)
 		common.WriteJsonResp(w, err, "", http.StatusInternalServerError)
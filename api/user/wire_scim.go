@@ -0,0 +1,17 @@
+package user
+
+import (
+	"github.com/devtron-labs/devtron/pkg/user/scim"
+	"github.com/google/wire"
+)
+
+var ScimWireSet = wire.NewSet(
+	NewScimRouterImpl,
+	wire.Bind(new(ScimRouter), new(*ScimRouterImpl)),
+	NewScimRestHandlerImpl,
+	wire.Bind(new(ScimRestHandler), new(*ScimRestHandlerImpl)),
+	scim.NewScimServiceImpl,
+	wire.Bind(new(scim.ScimService), new(*scim.ScimServiceImpl)),
+	scim.NewScimExternalIdRepositoryImpl,
+	wire.Bind(new(scim.ScimExternalIdRepository), new(*scim.ScimExternalIdRepositoryImpl)),
+)
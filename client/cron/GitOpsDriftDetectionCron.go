@@ -0,0 +1,53 @@
+package cron
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/pkg/app"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type GitOpsDriftDetectionCron interface {
+	DetectDrift()
+}
+
+type GitOpsDriftDetectionCronConfig struct {
+	GitOpsDriftDetectionCronTime int `env:"GITOPS_DRIFT_DETECTION_CRON_TIME" envDefault:"30"`
+}
+
+// GitOpsDriftDetectionCronImpl periodically compares gitops repos and argo cd applications with the state last
+// released by devtron and records a drift report per pipeline
+type GitOpsDriftDetectionCronImpl struct {
+	logger             *zap.SugaredLogger
+	cron               *cron.Cron
+	gitOpsDriftService app.GitOpsDriftService
+}
+
+func NewGitOpsDriftDetectionCronImpl(logger *zap.SugaredLogger, gitOpsDriftService app.GitOpsDriftService) (*GitOpsDriftDetectionCronImpl, error) {
+	cfg := &GitOpsDriftDetectionCronConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		logger.Errorw("error in parsing gitops drift detection cron config", "err", err)
+		return nil, err
+	}
+	cronLogger := &CronLoggerImpl{logger: logger}
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cronLogger)))
+	cron.Start()
+	impl := &GitOpsDriftDetectionCronImpl{
+		logger:             logger,
+		cron:               cron,
+		gitOpsDriftService: gitOpsDriftService,
+	}
+	_, err = cron.AddFunc(fmt.Sprintf("@every %dm", cfg.GitOpsDriftDetectionCronTime), impl.DetectDrift)
+	if err != nil {
+		logger.Errorw("error in starting gitops drift detection cron job", "err", err)
+		return nil, err
+	}
+	return impl, nil
+}
+
+func (impl *GitOpsDriftDetectionCronImpl) DetectDrift() {
+	impl.gitOpsDriftService.DetectDriftForAllPipelines()
+}
@@ -0,0 +1,199 @@
+package cron
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	bean2 "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	util2 "github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/app"
+	"github.com/devtron-labs/devtron/pkg/app/status"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/util"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"time"
+)
+
+type GitOpsPullRequestCron interface {
+	SyncOpenPullRequests()
+}
+
+type GitOpsPullRequestCronConfig struct {
+	GitOpsPullRequestCronTime int `env:"GITOPS_PULL_REQUEST_CRON_TIME" envDefault:"1"`
+}
+
+// GitOpsPullRequestCronImpl follows the pull requests raised for gitops commits, a merged pull request records the
+// merge commit against its release and deploys it, a pull request closed without merge fails its deployment
+type GitOpsPullRequestCronImpl struct {
+	logger                        *zap.SugaredLogger
+	cron                          *cron.Cron
+	appService                    app.AppService
+	gitFactory                    *util2.GitFactory
+	gitOpsConfigRepository        repository.GitOpsConfigRepository
+	gitOpsPullRequestRepository   pipelineConfig.GitOpsPullRequestRepository
+	pipelineOverrideRepository    chartConfig.PipelineOverrideRepository
+	cdWorkflowRepository          pipelineConfig.CdWorkflowRepository
+	pipelineStatusTimelineService status.PipelineStatusTimelineService
+}
+
+func NewGitOpsPullRequestCronImpl(logger *zap.SugaredLogger, appService app.AppService, gitFactory *util2.GitFactory,
+	gitOpsConfigRepository repository.GitOpsConfigRepository, gitOpsPullRequestRepository pipelineConfig.GitOpsPullRequestRepository,
+	pipelineOverrideRepository chartConfig.PipelineOverrideRepository, cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	pipelineStatusTimelineService status.PipelineStatusTimelineService) (*GitOpsPullRequestCronImpl, error) {
+	cfg := &GitOpsPullRequestCronConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		logger.Errorw("error in parsing gitops pull request cron config", "err", err)
+		return nil, err
+	}
+	cronLogger := &CronLoggerImpl{logger: logger}
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cronLogger)))
+	cron.Start()
+	impl := &GitOpsPullRequestCronImpl{
+		logger:                        logger,
+		cron:                          cron,
+		appService:                    appService,
+		gitFactory:                    gitFactory,
+		gitOpsConfigRepository:        gitOpsConfigRepository,
+		gitOpsPullRequestRepository:   gitOpsPullRequestRepository,
+		pipelineOverrideRepository:    pipelineOverrideRepository,
+		cdWorkflowRepository:          cdWorkflowRepository,
+		pipelineStatusTimelineService: pipelineStatusTimelineService,
+	}
+	_, err = cron.AddFunc(fmt.Sprintf("@every %dm", cfg.GitOpsPullRequestCronTime), impl.SyncOpenPullRequests)
+	if err != nil {
+		logger.Errorw("error in starting gitops pull request cron job", "err", err)
+		return nil, err
+	}
+	return impl, nil
+}
+
+func (impl *GitOpsPullRequestCronImpl) SyncOpenPullRequests() {
+	pullRequests, err := impl.gitOpsPullRequestRepository.FindByStatus(pipelineConfig.GITOPS_PULL_REQUEST_OPEN)
+	if err != nil || len(pullRequests) == 0 {
+		return
+	}
+	gitOpsConfigBitbucket, err := impl.gitOpsConfigRepository.GetGitOpsConfigByProvider(util2.BITBUCKET_PROVIDER)
+	if err != nil {
+		if err == pg.ErrNoRows {
+			gitOpsConfigBitbucket.BitBucketWorkspaceId = ""
+		} else {
+			impl.logger.Errorw("error in getting bitbucket gitops config", "err", err)
+			return
+		}
+	}
+	gitOpsConfig := &bean2.GitOpsConfigDto{BitBucketWorkspaceId: gitOpsConfigBitbucket.BitBucketWorkspaceId}
+	for _, pullRequest := range pullRequests {
+		err = impl.syncPullRequest(pullRequest, gitOpsConfig)
+		if err != nil {
+			impl.logger.Errorw("error in syncing gitops pull request", "err", err, "pullRequestUrl", pullRequest.PullRequestUrl)
+		}
+	}
+}
+
+func (impl *GitOpsPullRequestCronImpl) syncPullRequest(pullRequest *pipelineConfig.GitOpsPullRequest, gitOpsConfig *bean2.GitOpsConfigDto) error {
+	cdWfr, err := impl.cdWorkflowRepository.FindWorkflowRunnerById(pullRequest.CdWorkflowRunnerId)
+	if err != nil {
+		return err
+	}
+	if util.IsTerminalStatus(cdWfr.Status) {
+		// deployment was superseded by a later trigger, merging this pull request must not deploy an older release
+		return impl.updatePullRequestStatus(pullRequest, pipelineConfig.GITOPS_PULL_REQUEST_SUPERSEDED, "")
+	}
+	pullRequestDto, err := impl.gitFactory.Client.GetPullRequest(pullRequest.GitRepoName, pullRequest.PullRequestId, gitOpsConfig)
+	if err != nil {
+		return err
+	}
+	switch pullRequestDto.State {
+	case util2.PULL_REQUEST_MERGED:
+		return impl.handleMergedPullRequest(pullRequest, pullRequestDto)
+	case util2.PULL_REQUEST_CLOSED:
+		err = impl.updatePullRequestStatus(pullRequest, pipelineConfig.GITOPS_PULL_REQUEST_CLOSED, "")
+		if err != nil {
+			return err
+		}
+		return impl.markDeploymentFailed(cdWfr, pipelineConfig.TIMELINE_STATUS_GIT_COMMIT_FAILED,
+			fmt.Sprintf("Git commit failed - pull request %s closed without merge", pullRequest.PullRequestUrl))
+	}
+	return nil
+}
+
+func (impl *GitOpsPullRequestCronImpl) handleMergedPullRequest(pullRequest *pipelineConfig.GitOpsPullRequest, pullRequestDto *util2.PullRequestDto) error {
+	// marked merged before deploying so that a failed deployment is not retried with every run
+	err := impl.updatePullRequestStatus(pullRequest, pipelineConfig.GITOPS_PULL_REQUEST_MERGED, pullRequestDto.MergeCommitHash)
+	if err != nil {
+		return err
+	}
+	cdWfr, err := impl.cdWorkflowRepository.FindWorkflowRunnerById(pullRequest.CdWorkflowRunnerId)
+	if err != nil {
+		return err
+	}
+	commitTime := pullRequestDto.MergedOn
+	if commitTime.IsZero() {
+		commitTime = time.Now()
+	}
+	err = impl.pipelineOverrideRepository.Update(&chartConfig.PipelineOverride{
+		Id:         pullRequest.PipelineOverrideId,
+		GitHash:    pullRequestDto.MergeCommitHash,
+		CommitTime: commitTime,
+		AuditLog:   sql.AuditLog{UpdatedOn: time.Now(), UpdatedBy: 1},
+	})
+	if err != nil {
+		impl.logger.Errorw("error in updating git hash of pipeline override", "err", err, "pipelineOverrideId", pullRequest.PipelineOverrideId)
+		return impl.markDeploymentFailed(cdWfr, pipelineConfig.TIMELINE_STATUS_GIT_COMMIT_FAILED, fmt.Sprintf("Git commit failed - %v", err))
+	}
+	impl.saveTimeline(cdWfr.Id, pipelineConfig.TIMELINE_STATUS_GIT_COMMIT, fmt.Sprintf("Pull request %s merged, git commit done successfully.", pullRequest.PullRequestUrl))
+	err = impl.appService.DeployArgocdAppForPipelineOverride(pullRequest.PipelineOverrideId, 1)
+	if err != nil {
+		impl.logger.Errorw("error in deploying merged gitops pull request", "err", err, "pipelineOverrideId", pullRequest.PipelineOverrideId)
+		return impl.markDeploymentFailed(cdWfr, pipelineConfig.TIMELINE_STATUS_DEPLOYMENT_FAILED, fmt.Sprintf("Deployment failed - %v", err))
+	}
+	return nil
+}
+
+func (impl *GitOpsPullRequestCronImpl) updatePullRequestStatus(pullRequest *pipelineConfig.GitOpsPullRequest, status pipelineConfig.GitOpsPullRequestStatus, mergeCommitHash string) error {
+	pullRequest.Status = status
+	pullRequest.MergeCommitHash = mergeCommitHash
+	pullRequest.UpdatedOn = time.Now()
+	pullRequest.UpdatedBy = 1
+	return impl.gitOpsPullRequestRepository.Update(pullRequest)
+}
+
+func (impl *GitOpsPullRequestCronImpl) markDeploymentFailed(cdWfr *pipelineConfig.CdWorkflowRunner, timelineStatus pipelineConfig.TimelineStatus, message string) error {
+	cdWfr.Status = pipelineConfig.WorkflowFailed
+	cdWfr.Message = message
+	cdWfr.FinishedOn = time.Now()
+	cdWfr.UpdatedOn = time.Now()
+	cdWfr.UpdatedBy = 1
+	err := impl.cdWorkflowRepository.UpdateWorkFlowRunner(cdWfr)
+	if err != nil {
+		impl.logger.Errorw("error in updating cd workflow runner", "err", err, "cdWfrId", cdWfr.Id)
+		return err
+	}
+	impl.saveTimeline(cdWfr.Id, timelineStatus, message)
+	return nil
+}
+
+func (impl *GitOpsPullRequestCronImpl) saveTimeline(cdWfrId int, timelineStatus pipelineConfig.TimelineStatus, statusDetail string) {
+	timeline := &pipelineConfig.PipelineStatusTimeline{
+		CdWorkflowRunnerId: cdWfrId,
+		Status:             timelineStatus,
+		StatusDetail:       statusDetail,
+		StatusTime:         time.Now(),
+		AuditLog: sql.AuditLog{
+			CreatedBy: 1,
+			CreatedOn: time.Now(),
+			UpdatedBy: 1,
+			UpdatedOn: time.Now(),
+		},
+	}
+	err := impl.pipelineStatusTimelineService.SaveTimeline(timeline, nil, false)
+	if err != nil {
+		impl.logger.Errorw("error in saving timeline", "err", err, "timeline", timeline)
+	}
+}
@@ -90,6 +90,7 @@ type Payload struct {
 	BuildHistoryLink      string               `json:"buildHistoryLink"`
 	MaterialTriggerInfo   *MaterialTriggerInfo `json:"material"`
 	FailureReason         string               `json:"failureReason"`
+	ClusterName           string               `json:"clusterName,omitempty"`
 }
 
 type CiPipelineMaterialResponse struct {
@@ -3,8 +3,11 @@ package main
 import (
 	"fmt"
 	authMiddleware "github.com/devtron-labs/authenticator/middleware"
+	"github.com/devtron-labs/devtron/api/apiToken"
+	"github.com/devtron-labs/devtron/api/auditLog"
 	"github.com/devtron-labs/devtron/client/telemetry"
 	"github.com/devtron-labs/devtron/internal/middleware"
+	auditLog2 "github.com/devtron-labs/devtron/pkg/auditLog"
 	"github.com/devtron-labs/devtron/pkg/user"
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
@@ -13,13 +16,16 @@ import (
 )
 
 type App struct {
-	db             *pg.DB
-	sessionManager *authMiddleware.SessionManager
-	MuxRouter      *MuxRouter
-	Logger         *zap.SugaredLogger
-	server         *http.Server
-	telemetry      telemetry.TelemetryEventClient
-	posthogClient  *telemetry.PosthogClient
+	db                 *pg.DB
+	sessionManager     *authMiddleware.SessionManager
+	MuxRouter          *MuxRouter
+	Logger             *zap.SugaredLogger
+	server             *http.Server
+	telemetry          telemetry.TelemetryEventClient
+	posthogClient      *telemetry.PosthogClient
+	apiTokenMiddleware apiToken.ApiTokenMiddleware
+	auditLogMiddleware auditLog.AuditLogMiddleware
+	auditLogService    auditLog2.AuditLogService
 }
 
 func NewApp(db *pg.DB,
@@ -27,14 +33,20 @@ func NewApp(db *pg.DB,
 	MuxRouter *MuxRouter,
 	telemetry telemetry.TelemetryEventClient,
 	posthogClient *telemetry.PosthogClient,
-	Logger *zap.SugaredLogger) *App {
+	Logger *zap.SugaredLogger,
+	apiTokenMiddleware apiToken.ApiTokenMiddleware,
+	auditLogMiddleware auditLog.AuditLogMiddleware,
+	auditLogService auditLog2.AuditLogService) *App {
 	return &App{
-		db:             db,
-		sessionManager: sessionManager,
-		MuxRouter:      MuxRouter,
-		Logger:         Logger,
-		telemetry:      telemetry,
-		posthogClient:  posthogClient,
+		db:                 db,
+		sessionManager:     sessionManager,
+		MuxRouter:          MuxRouter,
+		Logger:             Logger,
+		telemetry:          telemetry,
+		posthogClient:      posthogClient,
+		apiTokenMiddleware: apiTokenMiddleware,
+		auditLogMiddleware: auditLogMiddleware,
+		auditLogService:    auditLogService,
 	}
 }
 func (app *App) Start() {
@@ -51,7 +63,9 @@ func (app *App) Start() {
 	if err != nil {
 		app.Logger.Warnw("telemetry installation success event failed", "err", err)
 	}
-	server := &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: authMiddleware.Authorizer(app.sessionManager, user.WhitelistChecker)(app.MuxRouter.Router)}
+	server := &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: user.ScimTokenHandler(authMiddleware.Authorizer(app.sessionManager, user.WhitelistChecker)(app.MuxRouter.Router))}
+	app.MuxRouter.Router.Use(app.apiTokenMiddleware.ApiTokenMiddleware)
+	app.MuxRouter.Router.Use(app.auditLogMiddleware.AuditLogMiddleware)
 	app.MuxRouter.Router.Use(middleware.PrometheusMiddleware)
 	app.server = server
 
@@ -69,4 +83,6 @@ func (app *App) Stop() {
 		app.Logger.Info("flushing messages of posthog")
 		posthogCl.Close()
 	}
+	app.Logger.Infow("writing queued audit events")
+	app.auditLogService.Stop()
 }
@@ -7,6 +7,7 @@ import (
 	appStoreDeployment "github.com/devtron-labs/devtron/api/appStore/deployment"
 	appStoreDiscover "github.com/devtron-labs/devtron/api/appStore/discover"
 	appStoreValues "github.com/devtron-labs/devtron/api/appStore/values"
+	"github.com/devtron-labs/devtron/api/auditLog"
 	"github.com/devtron-labs/devtron/api/chartRepo"
 	"github.com/devtron-labs/devtron/api/cluster"
 	"github.com/devtron-labs/devtron/api/dashboardEvent"
@@ -63,6 +64,8 @@ type MuxRouter struct {
 	attributesRouter         router.AttributesRouter
 	appRouter                router.AppRouter
 	rbacRoleRouter           user.RbacRoleRouter
+	scimRouter               user.ScimRouter
+	auditLogRouter           auditLog.AuditLogRouter
 }
 
 func NewMuxRouter(
@@ -95,6 +98,8 @@ func NewMuxRouter(
 	attributesRouter router.AttributesRouter,
 	appRouter router.AppRouter,
 	rbacRoleRouter user.RbacRoleRouter,
+	scimRouter user.ScimRouter,
+	auditLogRouter auditLog.AuditLogRouter,
 ) *MuxRouter {
 	r := &MuxRouter{
 		Router:                   mux.NewRouter(),
@@ -128,6 +133,8 @@ func NewMuxRouter(
 		attributesRouter:         attributesRouter,
 		appRouter:                appRouter,
 		rbacRoleRouter:           rbacRoleRouter,
+		scimRouter:               scimRouter,
+		auditLogRouter:           auditLogRouter,
 	}
 	return r
 }
@@ -260,4 +267,10 @@ func (r *MuxRouter) Init() {
 
 	attributeRouter := r.Router.PathPrefix("/orchestrator/attributes").Subrouter()
 	r.attributesRouter.InitAttributesRouter(attributeRouter)
+
+	scimRouter := r.Router.PathPrefix("/orchestrator/scim/v2").Subrouter()
+	r.scimRouter.InitScimRouter(scimRouter)
+
+	auditLogRouter := r.Router.PathPrefix("/orchestrator/audit-log").Subrouter()
+	r.auditLogRouter.InitAuditLogRouter(auditLogRouter)
 }
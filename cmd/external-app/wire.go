@@ -11,6 +11,7 @@ import (
 	appStoreDeployment "github.com/devtron-labs/devtron/api/appStore/deployment"
 	appStoreDiscover "github.com/devtron-labs/devtron/api/appStore/discover"
 	appStoreValues "github.com/devtron-labs/devtron/api/appStore/values"
+	"github.com/devtron-labs/devtron/api/auditLog"
 	chartRepo "github.com/devtron-labs/devtron/api/chartRepo"
 	"github.com/devtron-labs/devtron/api/cluster"
 	"github.com/devtron-labs/devtron/api/connector"
@@ -62,6 +63,8 @@ func InitializeApp() (*App, error) {
 
 		sql.PgSqlWireSet,
 		user.UserWireSet,
+		user.ScimWireSet,
+		auditLog.AuditLogWireSet,
 		sso.SsoConfigWireSet,
 		AuthWireSet,
 		util4.NewK8sUtil,
@@ -174,6 +177,10 @@ func InitializeApp() (*App, error) {
 
 		kubernetesResourceAuditLogs.Newk8sResourceHistoryServiceImpl,
 		wire.Bind(new(kubernetesResourceAuditLogs.K8sResourceHistoryService), new(*kubernetesResourceAuditLogs.K8sResourceHistoryServiceImpl)),
+		repository2.NewK8sResourceChangeRepositoryImpl,
+		wire.Bind(new(repository2.K8sResourceChangeRepository), new(*repository2.K8sResourceChangeRepositoryImpl)),
+		kubernetesResourceAuditLogs.NewK8sResourceChangeServiceImpl,
+		wire.Bind(new(kubernetesResourceAuditLogs.K8sResourceChangeService), new(*kubernetesResourceAuditLogs.K8sResourceChangeServiceImpl)),
 
 		util.NewChartTemplateServiceImpl,
 		wire.Bind(new(util.ChartTemplateService), new(*util.ChartTemplateServiceImpl)),
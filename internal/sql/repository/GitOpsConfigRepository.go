@@ -53,7 +53,12 @@ type GitOpsConfig struct {
 	Active               bool     `sql:"active,notnull"`
 	BitBucketWorkspaceId string   `sql:"bitbucket_workspace_id"`
 	BitBucketProjectKey  string   `sql:"bitbucket_project_key"`
+	GiteaOrgId           string   `sql:"gitea_org_id"`
 	EmailId              string   `sql:"email_id"`
+	// CommitSigningType is GPG or SSH when gitops commits are signed, signing key and passphrase are encrypted
+	CommitSigningType          string `sql:"commit_signing_type"`
+	CommitSigningKey           string `sql:"commit_signing_key"`
+	CommitSigningKeyPassphrase string `sql:"commit_signing_key_passphrase"`
 	sql.AuditLog
 }
 
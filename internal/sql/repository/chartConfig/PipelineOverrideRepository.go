@@ -69,6 +69,7 @@ type PipelineOverrideRepository interface {
 	FetchHelmTypePipelineOverridesForStatusUpdate() (pipelines []*PipelineOverride, err error)
 	FindLatestByAppIdAndEnvId(appId, environmentId int, deploymentAppType string) (pipelineOverrides *PipelineOverride, err error)
 	FindLatestByCdWorkflowId(cdWorkflowId int) (pipelineOverride *PipelineOverride, err error)
+	FindLatestCommittedByPipelineId(pipelineId int) (pipelineOverride *PipelineOverride, err error)
 }
 
 type PipelineOverrideRepositoryImpl struct {
@@ -261,3 +262,14 @@ func (impl PipelineOverrideRepositoryImpl) FindLatestByCdWorkflowId(cdWorkflowId
 		Select()
 	return &override, err
 }
+
+// FindLatestCommittedByPipelineId returns the latest release of the pipeline whose values reached the gitops repo
+func (impl PipelineOverrideRepositoryImpl) FindLatestCommittedByPipelineId(pipelineId int) (*PipelineOverride, error) {
+	var override PipelineOverride
+	err := impl.dbConnection.Model(&override).
+		Where("pipeline_id = ?", pipelineId).
+		Where("git_hash IS NOT NULL AND git_hash <> ''").
+		Order("id DESC").Limit(1).
+		Select()
+	return &override, err
+}
@@ -0,0 +1,99 @@
+package pipelineConfig
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type GitOpsDriftStatus = string
+
+const (
+	GITOPS_DRIFT_STATUS_IN_SYNC GitOpsDriftStatus = "IN_SYNC"
+	// GITOPS_DRIFT_STATUS_DRIFTED is set when values in the gitops repo differ from the values last committed by devtron,
+	// or when the live state of the argo cd application differs from the repo
+	GITOPS_DRIFT_STATUS_DRIFTED GitOpsDriftStatus = "DRIFTED"
+	// GITOPS_DRIFT_STATUS_UNKNOWN is set when drift could not be checked, message has the reason
+	GITOPS_DRIFT_STATUS_UNKNOWN GitOpsDriftStatus = "UNKNOWN"
+)
+
+// GitOpsDriftReport is the result of the last drift check of a gitops pipeline, a pipeline has a single report
+type GitOpsDriftReport struct {
+	tableName           struct{}          `sql:"gitops_drift_report" pg:",discard_unknown_columns"`
+	Id                  int               `sql:"id,pk"`
+	PipelineId          int               `sql:"pipeline_id"`
+	AppId               int               `sql:"app_id"`
+	EnvironmentId       int               `sql:"environment_id"`
+	PipelineOverrideId  int               `sql:"pipeline_override_id"`
+	DevtronCommitHash   string            `sql:"devtron_commit_hash"`
+	GitHeadCommitHash   string            `sql:"git_head_commit_hash"`
+	GitHeadCommitAuthor string            `sql:"git_head_commit_author"`
+	GitHeadCommitTime   time.Time         `sql:"git_head_commit_time,type:timestamptz"`
+	GitDriftStatus      GitOpsDriftStatus `sql:"git_drift_status"`
+	LiveDriftStatus     GitOpsDriftStatus `sql:"live_drift_status"`
+	LiveSyncRevision    string            `sql:"live_sync_revision"`
+	OutOfSyncResources  string            `sql:"out_of_sync_resources"` //json array of resources out of sync in argo cd
+	Message             string            `sql:"message"`
+	DetectedOn          time.Time         `sql:"detected_on,type:timestamptz"`
+	sql.AuditLog
+}
+
+type GitOpsDriftReportRepository interface {
+	Save(report *GitOpsDriftReport) error
+	Update(report *GitOpsDriftReport) error
+	FindByPipelineId(pipelineId int) (*GitOpsDriftReport, error)
+	FindByAppId(appId int) ([]*GitOpsDriftReport, error)
+}
+
+type GitOpsDriftReportRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewGitOpsDriftReportRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *GitOpsDriftReportRepositoryImpl {
+	return &GitOpsDriftReportRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *GitOpsDriftReportRepositoryImpl) Save(report *GitOpsDriftReport) error {
+	err := impl.dbConnection.Insert(report)
+	if err != nil {
+		impl.logger.Errorw("error in saving gitops drift report", "err", err, "pipelineId", report.PipelineId)
+		return err
+	}
+	return nil
+}
+
+func (impl *GitOpsDriftReportRepositoryImpl) Update(report *GitOpsDriftReport) error {
+	err := impl.dbConnection.Update(report)
+	if err != nil {
+		impl.logger.Errorw("error in updating gitops drift report", "err", err, "pipelineId", report.PipelineId)
+		return err
+	}
+	return nil
+}
+
+func (impl *GitOpsDriftReportRepositoryImpl) FindByPipelineId(pipelineId int) (*GitOpsDriftReport, error) {
+	report := &GitOpsDriftReport{}
+	err := impl.dbConnection.Model(report).
+		Where("pipeline_id = ?", pipelineId).Select()
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (impl *GitOpsDriftReportRepositoryImpl) FindByAppId(appId int) ([]*GitOpsDriftReport, error) {
+	var reports []*GitOpsDriftReport
+	err := impl.dbConnection.Model(&reports).
+		Where("app_id = ?", appId).
+		Order("environment_id ASC").Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting gitops drift reports", "err", err, "appId", appId)
+		return nil, err
+	}
+	return reports, nil
+}
@@ -0,0 +1,91 @@
+package pipelineConfig
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type GitOpsPullRequestStatus = string
+
+const (
+	GITOPS_PULL_REQUEST_OPEN       GitOpsPullRequestStatus = "OPEN"
+	GITOPS_PULL_REQUEST_MERGED     GitOpsPullRequestStatus = "MERGED"
+	GITOPS_PULL_REQUEST_CLOSED     GitOpsPullRequestStatus = "CLOSED"
+	GITOPS_PULL_REQUEST_SUPERSEDED GitOpsPullRequestStatus = "SUPERSEDED"
+)
+
+// GitOpsPullRequest tracks the pull request raised for a deployment of an environment with pull request based gitops
+type GitOpsPullRequest struct {
+	tableName          struct{}                `sql:"gitops_pull_request" pg:",discard_unknown_columns"`
+	Id                 int                     `sql:"id,pk"`
+	CdWorkflowRunnerId int                     `sql:"cd_workflow_runner_id"`
+	PipelineOverrideId int                     `sql:"pipeline_override_id"`
+	GitRepoName        string                  `sql:"git_repo_name"`
+	PullRequestId      string                  `sql:"pull_request_id"`
+	PullRequestUrl     string                  `sql:"pull_request_url"`
+	SourceBranch       string                  `sql:"source_branch"`
+	TargetBranch       string                  `sql:"target_branch"`
+	Status             GitOpsPullRequestStatus `sql:"status"`
+	MergeCommitHash    string                  `sql:"merge_commit_hash"`
+	sql.AuditLog
+}
+
+type GitOpsPullRequestRepository interface {
+	Save(pullRequest *GitOpsPullRequest) error
+	Update(pullRequest *GitOpsPullRequest) error
+	FindByStatus(status GitOpsPullRequestStatus) ([]*GitOpsPullRequest, error)
+	FindByCdWorkflowRunnerId(cdWorkflowRunnerId int) (*GitOpsPullRequest, error)
+}
+
+type GitOpsPullRequestRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewGitOpsPullRequestRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *GitOpsPullRequestRepositoryImpl {
+	return &GitOpsPullRequestRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *GitOpsPullRequestRepositoryImpl) Save(pullRequest *GitOpsPullRequest) error {
+	err := impl.dbConnection.Insert(pullRequest)
+	if err != nil {
+		impl.logger.Errorw("error in saving gitops pull request", "err", err, "pullRequest", pullRequest)
+		return err
+	}
+	return nil
+}
+
+func (impl *GitOpsPullRequestRepositoryImpl) Update(pullRequest *GitOpsPullRequest) error {
+	err := impl.dbConnection.Update(pullRequest)
+	if err != nil {
+		impl.logger.Errorw("error in updating gitops pull request", "err", err, "pullRequest", pullRequest)
+		return err
+	}
+	return nil
+}
+
+func (impl *GitOpsPullRequestRepositoryImpl) FindByStatus(status GitOpsPullRequestStatus) ([]*GitOpsPullRequest, error) {
+	var pullRequests []*GitOpsPullRequest
+	err := impl.dbConnection.Model(&pullRequests).
+		Where("status = ?", status).
+		Order("id ASC").Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting gitops pull requests by status", "err", err, "status", status)
+		return nil, err
+	}
+	return pullRequests, nil
+}
+
+func (impl *GitOpsPullRequestRepositoryImpl) FindByCdWorkflowRunnerId(cdWorkflowRunnerId int) (*GitOpsPullRequest, error) {
+	pullRequest := &GitOpsPullRequest{}
+	err := impl.dbConnection.Model(pullRequest).
+		Where("cd_workflow_runner_id = ?", cdWorkflowRunnerId).Select()
+	if err != nil {
+		return nil, err
+	}
+	return pullRequest, nil
+}
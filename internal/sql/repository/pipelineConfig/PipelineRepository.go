@@ -244,6 +244,7 @@ func (impl PipelineRepositoryImpl) FindActiveByAppIdAndEnvironmentId(appId int,
 
 func (impl PipelineRepositoryImpl) FindActiveByAppIdAndEnvironmentIdV2() (pipelines []*Pipeline, err error) {
 	err = impl.dbConnection.Model(&pipelines).
+		Column("pipeline.*", "Environment").
 		Where("deleted = ?", false).
 		Select()
 	return pipelines, err
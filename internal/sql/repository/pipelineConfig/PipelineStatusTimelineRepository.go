@@ -15,6 +15,7 @@ const (
 	TIMELINE_STATUS_DEPLOYMENT_INITIATED   TimelineStatus = "DEPLOYMENT_INITIATED"
 	TIMELINE_STATUS_GIT_COMMIT             TimelineStatus = "GIT_COMMIT"
 	TIMELINE_STATUS_GIT_COMMIT_FAILED      TimelineStatus = "GIT_COMMIT_FAILED"
+	TIMELINE_STATUS_GIT_PULL_REQUEST_OPEN  TimelineStatus = "GIT_PULL_REQUEST_OPEN"
 	TIMELINE_STATUS_KUBECTL_APPLY_STARTED  TimelineStatus = "KUBECTL_APPLY_STARTED"
 	TIMELINE_STATUS_KUBECTL_APPLY_SYNCED   TimelineStatus = "KUBECTL_APPLY_SYNCED"
 	TIMELINE_STATUS_APP_HEALTHY            TimelineStatus = "HEALTHY"
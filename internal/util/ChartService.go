@@ -54,6 +54,12 @@ const PIPELINE_DEPLOYMENT_TYPE_ACD string = "argo_cd"
 const PIPELINE_DEPLOYMENT_TYPE_HELM string = "helm"
 const PIPELINE_DEPLOYMENT_TYPE_MANIFEST_DOWNLOAD string = "manifest_download"
 
+const (
+	GITOPS_REPO_LAYOUT_REPO_PER_APP      = "REPO_PER_APP"
+	GITOPS_REPO_LAYOUT_MONOREPO          = "MONOREPO"
+	GITOPS_REPO_LAYOUT_MONOREPO_PER_TEAM = "MONOREPO_PER_TEAM"
+)
+
 type ChartCreateRequest struct {
 	ChartMetaData *chart.Metadata
 	ChartPath     string
@@ -76,8 +82,11 @@ type ChartTemplateService interface {
 	GetUserEmailIdAndNameForGitOpsCommit(userId int32) (emailId, name string)
 	GetGitOpsRepoName(appName string) string
 	GetGitOpsRepoNameFromUrl(gitRepoUrl string) string
-	CreateGitRepositoryForApp(gitOpsRepoName, baseTemplateName, version string, userId int32) (chartGitAttribute *ChartGitAttribute, err error)
-	PushChartToGitRepo(gitOpsRepoName, referenceTemplate, version, tempReferenceTemplateDir string, repoUrl string, userId int32) (err error)
+	IsGitOpsMonorepoLayout() bool
+	GetGitOpsRepoNameAndDirectory(appName, teamName string) (gitOpsRepoName string, gitRepoDirectory string)
+	CreateGitRepositoryForApp(gitOpsRepoName, gitRepoDirectory, baseTemplateName, version string, userId int32) (chartGitAttribute *ChartGitAttribute, err error)
+	PushChartToGitRepo(gitOpsRepoName, chartLocation, tempReferenceTemplateDir string, repoUrl string, userId int32) (err error)
+	CopyGitRepoToDirectory(sourceRepoUrl, targetRepoUrl, directory string, userId int32) error
 	GetByteArrayRefChart(chartMetaData *chart.Metadata, referenceTemplatePath string) ([]byte, error)
 	CreateReadmeInGitRepo(gitOpsRepoName string, userId int32) error
 	UpdateGitRepoUrlInCharts(appId int, chartGitAttribute *ChartGitAttribute, userId int32) error
@@ -245,9 +254,11 @@ func (impl ChartTemplateServiceImpl) BuildChartProxyForHelmApps(chartCreateReque
 
 type ChartGitAttribute struct {
 	RepoUrl, ChartLocation string
+	// GitRepoDirectory is the directory of the app within a shared gitops repository, empty if the repository holds only the app
+	GitRepoDirectory string
 }
 
-func (impl ChartTemplateServiceImpl) CreateGitRepositoryForApp(gitOpsRepoName, baseTemplateName, version string, userId int32) (chartGitAttribute *ChartGitAttribute, err error) {
+func (impl ChartTemplateServiceImpl) CreateGitRepositoryForApp(gitOpsRepoName, gitRepoDirectory, baseTemplateName, version string, userId int32) (chartGitAttribute *ChartGitAttribute, err error) {
 	//baseTemplateName  replace whitespace
 	space := regexp.MustCompile(`\s+`)
 	gitOpsRepoName = space.ReplaceAllString(gitOpsRepoName, "-")
@@ -277,10 +288,10 @@ func (impl ChartTemplateServiceImpl) CreateGitRepositoryForApp(gitOpsRepoName, b
 			return nil, err
 		}
 	}
-	return &ChartGitAttribute{RepoUrl: repoUrl, ChartLocation: filepath.Join(baseTemplateName, version)}, nil
+	return &ChartGitAttribute{RepoUrl: repoUrl, ChartLocation: filepath.Join(gitRepoDirectory, baseTemplateName, version), GitRepoDirectory: gitRepoDirectory}, nil
 }
 
-func (impl ChartTemplateServiceImpl) PushChartToGitRepo(gitOpsRepoName, referenceTemplate, version, tempReferenceTemplateDir string, repoUrl string, userId int32) (err error) {
+func (impl ChartTemplateServiceImpl) PushChartToGitRepo(gitOpsRepoName, chartLocation, tempReferenceTemplateDir string, repoUrl string, userId int32) (err error) {
 	chartDir := fmt.Sprintf("%s-%s", gitOpsRepoName, impl.GetDir())
 	clonedDir := impl.gitFactory.GitService.GetCloneDirectory(chartDir)
 	if _, err := os.Stat(clonedDir); os.IsNotExist(err) {
@@ -297,7 +308,7 @@ func (impl ChartTemplateServiceImpl) PushChartToGitRepo(gitOpsRepoName, referenc
 		}
 	}
 
-	dir := filepath.Join(clonedDir, referenceTemplate, version)
+	dir := filepath.Join(clonedDir, chartLocation)
 	pushChartToGit := true
 
 	//if chart already exists don't overrides it by reference template
@@ -664,6 +675,77 @@ func (impl ChartTemplateServiceImpl) GetGitOpsRepoNameFromUrl(gitRepoUrl string)
 	return gitRepoUrl
 }
 
+func (impl ChartTemplateServiceImpl) IsGitOpsMonorepoLayout() bool {
+	return impl.globalEnvVariables.GitOpsRepoLayout == GITOPS_REPO_LAYOUT_MONOREPO ||
+		impl.globalEnvVariables.GitOpsRepoLayout == GITOPS_REPO_LAYOUT_MONOREPO_PER_TEAM
+}
+
+// GetGitOpsRepoNameAndDirectory returns the repository and the directory within it where the app's charts are committed,
+// directory is empty when every app has its own repository. Apps of a team repository are nested under the team's directory.
+func (impl ChartTemplateServiceImpl) GetGitOpsRepoNameAndDirectory(appName, teamName string) (gitOpsRepoName string, gitRepoDirectory string) {
+	switch impl.globalEnvVariables.GitOpsRepoLayout {
+	case GITOPS_REPO_LAYOUT_MONOREPO:
+		return impl.globalEnvVariables.GitOpsMonorepoName, appName
+	case GITOPS_REPO_LAYOUT_MONOREPO_PER_TEAM:
+		return fmt.Sprintf("%s-%s", impl.globalEnvVariables.GitOpsMonorepoName, teamName), filepath.Join(teamName, appName)
+	default:
+		return impl.GetGitOpsRepoName(appName), ""
+	}
+}
+
+// GetEnvChartLocation returns the directory an environment's chart and values are committed to, in a repository shared
+// by apps every environment has its own directory within the app's directory, e.g. <team>/<app>/<env>/<chart>/<version>
+func GetEnvChartLocation(chartLocation, gitRepoDirectory, envName string) string {
+	if len(gitRepoDirectory) == 0 {
+		return chartLocation
+	}
+	relativeLocation, err := filepath.Rel(gitRepoDirectory, chartLocation)
+	if err != nil || strings.HasPrefix(relativeLocation, "..") {
+		return chartLocation
+	}
+	return filepath.Join(gitRepoDirectory, envName, relativeLocation)
+}
+
+// CopyGitRepoToDirectory commits the content of a gitops repository into a directory of another repository, the source
+// repository is left as it is
+func (impl ChartTemplateServiceImpl) CopyGitRepoToDirectory(sourceRepoUrl, targetRepoUrl, directory string, userId int32) error {
+	dir := impl.GetDir()
+	sourceDir, err := impl.gitFactory.GitService.Clone(sourceRepoUrl, fmt.Sprintf("%s-%s", impl.GetGitOpsRepoNameFromUrl(sourceRepoUrl), dir))
+	if err != nil {
+		impl.logger.Errorw("error in cloning repo", "url", sourceRepoUrl, "err", err)
+		return err
+	}
+	defer impl.CleanDir(sourceDir)
+	targetDir, err := impl.gitFactory.GitService.Clone(targetRepoUrl, fmt.Sprintf("%s-%s", impl.GetGitOpsRepoNameFromUrl(targetRepoUrl), dir))
+	if err != nil {
+		impl.logger.Errorw("error in cloning repo", "url", targetRepoUrl, "err", err)
+		return err
+	}
+	defer impl.CleanDir(targetDir)
+	entries, err := ioutil.ReadDir(sourceDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Name() == ".git" {
+			continue
+		}
+		err = dirCopy.Copy(filepath.Join(sourceDir, entry.Name()), filepath.Join(targetDir, directory, entry.Name()))
+		if err != nil {
+			impl.logger.Errorw("error copying dir", "from", sourceDir, "to", targetDir, "err", err)
+			return err
+		}
+	}
+	userEmailId, userName := impl.GetUserEmailIdAndNameForGitOpsCommit(userId)
+	commit, err := impl.gitFactory.GitService.CommitAndPushAllChanges(targetDir, fmt.Sprintf("move %s from %s", directory, sourceRepoUrl), userName, userEmailId)
+	if err != nil {
+		impl.logger.Errorw("error in pushing git", "url", targetRepoUrl, "err", err)
+		return err
+	}
+	impl.logger.Infow("gitops repo copied", "from", sourceRepoUrl, "to", targetRepoUrl, "directory", directory, "commit", commit)
+	return nil
+}
+
 // GetByteArrayRefChart this method will be used for getting byte array from reference chart to store in db
 func (impl ChartTemplateServiceImpl) GetByteArrayRefChart(chartMetaData *chart.Metadata, referenceTemplatePath string) ([]byte, error) {
 	chartMetaData.ApiVersion = "v1" // ensure always v1
@@ -726,6 +808,7 @@ func (impl ChartTemplateServiceImpl) UpdateGitRepoUrlInCharts(appId int, chartGi
 		if len(ch.GitRepoUrl) == 0 {
 			ch.GitRepoUrl = chartGitAttribute.RepoUrl
 			ch.ChartLocation = chartGitAttribute.ChartLocation
+			ch.GitRepoDirectory = chartGitAttribute.GitRepoDirectory
 			ch.UpdatedOn = time.Now()
 			ch.UpdatedBy = userId
 			err = impl.chartRepository.Update(ch)
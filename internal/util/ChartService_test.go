@@ -114,3 +114,30 @@ func TestChartTemplateService(t *testing.T) {
 	})
 
 }
+
+func TestGetGitOpsRepoNameAndDirectory(t *testing.T) {
+	impl := ChartTemplateServiceImpl{globalEnvVariables: &util.GlobalEnvVariables{GitOpsRepoPrefix: "devtron", GitOpsMonorepoName: "gitops"}}
+
+	impl.globalEnvVariables.GitOpsRepoLayout = GITOPS_REPO_LAYOUT_REPO_PER_APP
+	repoName, directory := impl.GetGitOpsRepoNameAndDirectory("sample-app", "dev")
+	assert.False(t, impl.IsGitOpsMonorepoLayout())
+	assert.Equal(t, "devtron-sample-app", repoName)
+	assert.Equal(t, "", directory)
+
+	impl.globalEnvVariables.GitOpsRepoLayout = GITOPS_REPO_LAYOUT_MONOREPO
+	repoName, directory = impl.GetGitOpsRepoNameAndDirectory("sample-app", "dev")
+	assert.True(t, impl.IsGitOpsMonorepoLayout())
+	assert.Equal(t, "gitops", repoName)
+	assert.Equal(t, "sample-app", directory)
+
+	impl.globalEnvVariables.GitOpsRepoLayout = GITOPS_REPO_LAYOUT_MONOREPO_PER_TEAM
+	repoName, directory = impl.GetGitOpsRepoNameAndDirectory("sample-app", "dev")
+	assert.Equal(t, "gitops-dev", repoName)
+	assert.Equal(t, "dev/sample-app", directory)
+}
+
+func TestGetEnvChartLocation(t *testing.T) {
+	assert.Equal(t, "reference-chart_4-18-0/4.18.0", GetEnvChartLocation("reference-chart_4-18-0/4.18.0", "", "prod"))
+	assert.Equal(t, "dev/sample-app/prod/reference-chart_4-18-0/4.18.0", GetEnvChartLocation("dev/sample-app/reference-chart_4-18-0/4.18.0", "dev/sample-app", "prod"))
+	assert.Equal(t, "sample-app/qa/reference-chart_4-18-0/4.18.0", GetEnvChartLocation("sample-app/reference-chart_4-18-0/4.18.0", "sample-app", "qa"))
+}
@@ -73,6 +73,51 @@ func (impl *GitCliUtil) ListBranch(rootDir string, username string, password str
 	return output, errMsg, err
 }
 
+// LastCommitForPath prints hash, author and commit time of the last commit on the checked out branch which changed path,
+// separated by tabs
+func (impl *GitCliUtil) LastCommitForPath(rootDir string, path string) (response, errMsg string, err error) {
+	start := time.Now()
+	defer func() {
+		util.TriggerGitOpsMetrics("LastCommitForPath", "GitCli", start, err)
+	}()
+	impl.logger.Debugw("git log ", "location", rootDir, "path", path)
+	cmd := exec.Command("git", "-C", rootDir, "log", "-1", "--format=%H%x09%an%x09%cI", "--", path)
+	output, errMsg, err := impl.runCommand(cmd)
+	impl.logger.Debugw("log output", "root", rootDir, "opt", output, "errMsg", errMsg, "error", err)
+	return output, errMsg, err
+}
+
+// CheckoutNewBranch checks out branch, creating it from the current branch when it does not exist on origin
+func (impl *GitCliUtil) CheckoutNewBranch(rootDir string, branch string) (response, errMsg string, err error) {
+	start := time.Now()
+	defer func() {
+		util.TriggerGitOpsMetrics("CheckoutNewBranch", "GitCli", start, err)
+	}()
+	impl.logger.Debugw("git checkout new branch ", "location", rootDir, "branch", branch)
+	output, errMsg, err := impl.runCommand(exec.Command("git", "-C", rootDir, "checkout", branch))
+	if err != nil {
+		output, errMsg, err = impl.runCommand(exec.Command("git", "-C", rootDir, "checkout", "-b", branch))
+	}
+	impl.logger.Debugw("checkout output", "root", rootDir, "opt", output, "errMsg", errMsg, "error", err)
+	return output, errMsg, err
+}
+
+// CommitWithSshSignature commits the staged changes signed with the ssh private key at signingKeyPath
+func (impl *GitCliUtil) CommitWithSshSignature(rootDir, commitMsg, name, emailId, signingKeyPath string) (response, errMsg string, err error) {
+	start := time.Now()
+	defer func() {
+		util.TriggerGitOpsMetrics("CommitWithSshSignature", "GitCli", start, err)
+	}()
+	impl.logger.Debugw("git commit ", "location", rootDir)
+	cmd := exec.Command("git", "-C", rootDir,
+		"-c", "user.name="+name, "-c", "user.email="+emailId,
+		"-c", "gpg.format=ssh", "-c", "user.signingkey="+signingKeyPath,
+		"commit", "-S", "-m", commitMsg)
+	output, errMsg, err := impl.runCommand(cmd)
+	impl.logger.Debugw("commit output", "root", rootDir, "opt", output, "errMsg", errMsg, "error", err)
+	return output, errMsg, err
+}
+
 func (impl *GitCliUtil) runCommandWithCred(cmd *exec.Cmd, userName, password string) (response, errMsg string, err error) {
 	cmd.Env = append(os.Environ(),
 		fmt.Sprintf("GIT_ASKPASS=%s", GIT_ASK_PASS),
@@ -0,0 +1,123 @@
+package util
+
+import (
+	"fmt"
+	bean2 "github.com/devtron-labs/devtron/api/bean"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/ssh"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	COMMIT_SIGNING_TYPE_GPG = "GPG"
+	COMMIT_SIGNING_TYPE_SSH = "SSH"
+)
+
+// ValidateCommitSigningKey checks that key is a private key of signingType which can be used without a prompt,
+// ssh keys must not have a passphrase as git signs through ssh-keygen
+func ValidateCommitSigningKey(signingType, key, passphrase string) error {
+	switch signingType {
+	case "":
+		return nil
+	case COMMIT_SIGNING_TYPE_GPG:
+		_, err := getGpgSigningEntity(key, passphrase)
+		return err
+	case COMMIT_SIGNING_TYPE_SSH:
+		if len(key) == 0 {
+			return fmt.Errorf("commit signing key is required")
+		}
+		_, err := ssh.ParsePrivateKey([]byte(key))
+		if _, ok := err.(*ssh.PassphraseMissingError); ok {
+			return fmt.Errorf("ssh commit signing key must not have a passphrase")
+		} else if err != nil {
+			return fmt.Errorf("invalid ssh commit signing key: %v", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported commit signing type %s, supported types are %s and %s", signingType, COMMIT_SIGNING_TYPE_GPG, COMMIT_SIGNING_TYPE_SSH)
+	}
+}
+
+// getGpgSigningEntity reads the first private key of an armored gpg key ring and decrypts it with passphrase
+func getGpgSigningEntity(key, passphrase string) (*openpgp.Entity, error) {
+	if len(key) == 0 {
+		return nil, fmt.Errorf("commit signing key is required")
+	}
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gpg commit signing key: %v", err)
+	}
+	var entity *openpgp.Entity
+	for _, e := range entities {
+		if e.PrivateKey != nil {
+			entity = e
+			break
+		}
+	}
+	if entity == nil {
+		return nil, fmt.Errorf("gpg commit signing key has no private key")
+	}
+	if entity.PrivateKey.Encrypted {
+		err = entity.PrivateKey.Decrypt([]byte(passphrase))
+		if err != nil {
+			return nil, fmt.Errorf("could not decrypt gpg commit signing key: %v", err)
+		}
+	}
+	for _, subkey := range entity.Subkeys {
+		if subkey.PrivateKey != nil && subkey.PrivateKey.Encrypted {
+			err = subkey.PrivateKey.Decrypt([]byte(passphrase))
+			if err != nil {
+				return nil, fmt.Errorf("could not decrypt gpg commit signing subkey: %v", err)
+			}
+		}
+	}
+	return entity, nil
+}
+
+// signingGitClient commits values through a signed git push instead of the provider's api, provider api commits can
+// not carry devtron's signature
+type signingGitClient struct {
+	GitClient
+	gitService GitService
+}
+
+func (impl signingGitClient) CommitValues(config *ChartConfig, gitOpsConfig *bean2.GitOpsConfigDto) (commitHash string, commitTime time.Time, err error) {
+	repoConfig := *gitOpsConfig
+	repoConfig.GitRepoName = config.ChartRepoName
+	repoUrl, err := impl.GitClient.GetRepoUrl(&repoConfig)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if len(repoUrl) == 0 {
+		return "", time.Time{}, fmt.Errorf("%s :repo not found", config.ChartRepoName)
+	}
+	clonedDir, err := impl.gitService.Clone(repoUrl, fmt.Sprintf("%s-signed-%d", config.ChartRepoName, time.Now().UnixNano()))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer os.RemoveAll(clonedDir)
+	if config.GetBranch() != GITOPS_DEFAULT_BRANCH {
+		err = impl.gitService.CheckoutBranch(clonedDir, config.GetBranch())
+		if err != nil {
+			return "", time.Time{}, err
+		}
+	}
+	dir := filepath.Join(clonedDir, config.ChartLocation)
+	err = os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, config.FileName), []byte(config.FileContent), 0600)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	commitHash, err = impl.gitService.CommitAndPushAllChanges(clonedDir, config.ReleaseMessage, config.UserName, config.UserEmailId)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return commitHash, time.Now(), nil
+}
@@ -0,0 +1,58 @@
+package util
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"testing"
+)
+
+func TestValidateCommitSigningKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshKey := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}))
+
+	entity, err := openpgp.NewEntity("devtron bot", "", "devtron-bot@devtron.ai", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKeyBuf := &bytes.Buffer{}
+	armorWriter, err := armor.Encode(gpgKeyBuf, openpgp.PrivateKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = entity.SerializePrivate(armorWriter, nil); err != nil {
+		t.Fatal(err)
+	}
+	armorWriter.Close()
+	gpgKey := gpgKeyBuf.String()
+
+	tests := []struct {
+		name        string
+		signingType string
+		key         string
+		wantErr     bool
+	}{
+		{name: "signing disabled", signingType: "", key: "", wantErr: false},
+		{name: "valid ssh key", signingType: COMMIT_SIGNING_TYPE_SSH, key: sshKey, wantErr: false},
+		{name: "valid gpg key", signingType: COMMIT_SIGNING_TYPE_GPG, key: gpgKey, wantErr: false},
+		{name: "gpg key as ssh key", signingType: COMMIT_SIGNING_TYPE_SSH, key: gpgKey, wantErr: true},
+		{name: "ssh key as gpg key", signingType: COMMIT_SIGNING_TYPE_GPG, key: sshKey, wantErr: true},
+		{name: "missing key", signingType: COMMIT_SIGNING_TYPE_GPG, key: "", wantErr: true},
+		{name: "unsupported type", signingType: "X509", key: sshKey, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCommitSigningKey(tt.signingType, tt.key, "")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCommitSigningKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
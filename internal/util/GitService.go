@@ -23,7 +23,9 @@ import (
 	"github.com/devtron-labs/devtron/util"
 	"io/ioutil"
 	"net/url"
+	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	bean2 "github.com/devtron-labs/devtron/api/bean"
@@ -31,6 +33,7 @@ import (
 	"github.com/go-pg/pg"
 	"github.com/xanzy/go-gitlab"
 	"go.uber.org/zap"
+	"golang.org/x/crypto/openpgp"
 	"gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing/object"
 	"gopkg.in/src-d/go-git.v4/plumbing/transport/http"
@@ -47,8 +50,16 @@ const (
 	GITHUB_PROVIDER       = "GITHUB"
 	AZURE_DEVOPS_PROVIDER = "AZURE_DEVOPS"
 	BITBUCKET_PROVIDER    = "BITBUCKET_CLOUD"
+	GITEA_PROVIDER        = "GITEA"
 	GITHUB_API_V3         = "api/v3"
 	GITHUB_HOST           = "github.com"
+	GITOPS_DEFAULT_BRANCH = "master"
+)
+
+const (
+	PULL_REQUEST_OPEN   = "OPEN"
+	PULL_REQUEST_MERGED = "MERGED"
+	PULL_REQUEST_CLOSED = "CLOSED"
 )
 
 type GitClient interface {
@@ -58,6 +69,8 @@ type GitClient interface {
 	DeleteRepository(config *bean2.GitOpsConfigDto) error
 	CreateReadme(config *bean2.GitOpsConfigDto) (string, error)
 	GetCommits(repoName, projectName string) ([]*GitCommitDto, error)
+	CreatePullRequest(config *PullRequestConfig, gitOpsConfig *bean2.GitOpsConfigDto) (*PullRequestDto, error)
+	GetPullRequest(repoName string, pullRequestId string, gitOpsConfig *bean2.GitOpsConfigDto) (*PullRequestDto, error)
 }
 
 type GitFactory struct {
@@ -147,17 +160,21 @@ func (factory *GitFactory) NewClientForValidation(gitOpsConfig *bean2.GitOpsConf
 		util.TriggerGitOpsMetrics("NewClientForValidation", "GitService", start, err)
 	}()
 	cfg := &GitConfig{
-		GitlabGroupId:        gitOpsConfig.GitLabGroupId,
-		GitToken:             gitOpsConfig.Token,
-		GitUserName:          gitOpsConfig.Username,
-		GitWorkingDir:        GIT_WORKING_DIR,
-		GithubOrganization:   gitOpsConfig.GitHubOrgId,
-		GitProvider:          gitOpsConfig.Provider,
-		GitHost:              gitOpsConfig.Host,
-		AzureToken:           gitOpsConfig.Token,
-		AzureProject:         gitOpsConfig.AzureProjectName,
-		BitbucketWorkspaceId: gitOpsConfig.BitBucketWorkspaceId,
-		BitbucketProjectKey:  gitOpsConfig.BitBucketProjectKey,
+		GitlabGroupId:              gitOpsConfig.GitLabGroupId,
+		GitToken:                   gitOpsConfig.Token,
+		GitUserName:                gitOpsConfig.Username,
+		GitWorkingDir:              GIT_WORKING_DIR,
+		GithubOrganization:         gitOpsConfig.GitHubOrgId,
+		GitProvider:                gitOpsConfig.Provider,
+		GitHost:                    gitOpsConfig.Host,
+		AzureToken:                 gitOpsConfig.Token,
+		AzureProject:               gitOpsConfig.AzureProjectName,
+		BitbucketWorkspaceId:       gitOpsConfig.BitBucketWorkspaceId,
+		BitbucketProjectKey:        gitOpsConfig.BitBucketProjectKey,
+		GiteaOrganization:          gitOpsConfig.GiteaOrgId,
+		CommitSigningType:          gitOpsConfig.CommitSigningType,
+		CommitSigningKey:           gitOpsConfig.CommitSigningKey,
+		CommitSigningKeyPassphrase: gitOpsConfig.CommitSigningKeyPassphrase,
 	}
 	gitService := NewGitServiceImpl(cfg, logger, factory.gitCliUtil)
 	//factory.GitService = GitService
@@ -204,6 +221,11 @@ type GitConfig struct {
 	AzureProject         string
 	BitbucketWorkspaceId string
 	BitbucketProjectKey  string
+	GiteaOrganization    string
+	// CommitSigningType is GPG or SSH when commits are signed with CommitSigningKey, decrypted
+	CommitSigningType          string
+	CommitSigningKey           string
+	CommitSigningKeyPassphrase string
 }
 
 func GetGitConfig(gitOpsRepository repository.GitOpsConfigRepository) (*GitConfig, error) {
@@ -233,11 +255,33 @@ func GetGitConfig(gitOpsRepository repository.GitOpsConfigRepository) (*GitConfi
 		AzureProject:         gitOpsConfig.AzureProject,
 		BitbucketWorkspaceId: gitOpsConfig.BitBucketWorkspaceId,
 		BitbucketProjectKey:  gitOpsConfig.BitBucketProjectKey,
+		GiteaOrganization:    gitOpsConfig.GiteaOrgId,
+	}
+	if len(gitOpsConfig.CommitSigningType) > 0 {
+		cfg.CommitSigningType = gitOpsConfig.CommitSigningType
+		cfg.CommitSigningKey, err = util.DecryptSecret(gitOpsConfig.CommitSigningKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not decrypt gitops commit signing key: %v", err)
+		}
+		if len(gitOpsConfig.CommitSigningKeyPassphrase) > 0 {
+			cfg.CommitSigningKeyPassphrase, err = util.DecryptSecret(gitOpsConfig.CommitSigningKeyPassphrase)
+			if err != nil {
+				return nil, fmt.Errorf("could not decrypt gitops commit signing key passphrase: %v", err)
+			}
+		}
 	}
 	return cfg, err
 }
 
 func NewGitOpsClient(config *GitConfig, logger *zap.SugaredLogger, gitService GitService, gitOpsConfigRepository repository.GitOpsConfigRepository) (GitClient, error) {
+	client, err := newProviderGitOpsClient(config, logger, gitService, gitOpsConfigRepository)
+	if err != nil || client == nil || len(config.CommitSigningType) == 0 {
+		return client, err
+	}
+	return signingGitClient{GitClient: client, gitService: gitService}, nil
+}
+
+func newProviderGitOpsClient(config *GitConfig, logger *zap.SugaredLogger, gitService GitService, gitOpsConfigRepository repository.GitOpsConfigRepository) (GitClient, error) {
 	if config.GitProvider == GITLAB_PROVIDER {
 		gitLabClient, err := NewGitLabClient(config, logger, gitService)
 		return gitLabClient, err
@@ -250,6 +294,9 @@ func NewGitOpsClient(config *GitConfig, logger *zap.SugaredLogger, gitService Gi
 	} else if config.GitProvider == BITBUCKET_PROVIDER {
 		gitBitbucketClient := NewGitBitbucketClient(config.GitUserName, config.GitToken, config.GitHost, logger, gitService, gitOpsConfigRepository)
 		return gitBitbucketClient, nil
+	} else if config.GitProvider == GITEA_PROVIDER {
+		gitGiteaClient, err := NewGitGiteaClient(config.GitHost, config.GitToken, config.GiteaOrganization, logger, gitService, gitOpsConfigRepository)
+		return gitGiteaClient, err
 	} else {
 		logger.Errorw("no gitops config provided, gitops will not work ")
 		return nil, nil
@@ -265,6 +312,31 @@ type ChartConfig struct {
 	ChartRepoName  string
 	UserName       string
 	UserEmailId    string
+	Branch         string //branch to commit on, created from the default branch if missing
+}
+
+func (config *ChartConfig) GetBranch() string {
+	if len(config.Branch) == 0 {
+		return GITOPS_DEFAULT_BRANCH
+	}
+	return config.Branch
+}
+
+// PullRequestConfig describes a pull request of gitops commits on SourceBranch into TargetBranch
+type PullRequestConfig struct {
+	ChartRepoName string
+	SourceBranch  string
+	TargetBranch  string
+	Title         string
+	Description   string
+}
+
+type PullRequestDto struct {
+	Id              string
+	Url             string
+	State           string
+	MergeCommitHash string
+	MergedOn        time.Time
 }
 
 // -------------------- go-git integration -------------------
@@ -276,6 +348,8 @@ type GitService interface {
 
 	GetCloneDirectory(targetDir string) (clonedDir string)
 	Pull(repoRoot string) (err error)
+	GetLastCommitForPath(repoRoot, path string) (*GitCommitDto, error)
+	CheckoutBranch(repoRoot, branch string) error
 }
 type GitServiceImpl struct {
 	Auth       *http.BasicAuth
@@ -335,8 +409,26 @@ func (impl GitServiceImpl) CommitAndPushAllChanges(repoRoot, commitMsg, name, em
 	if err != nil {
 		return "", err
 	}
+	if impl.config.CommitSigningType == COMMIT_SIGNING_TYPE_SSH {
+		commitHash, err = impl.commitWithSshSignature(repo, repoRoot, commitMsg, name, emailId)
+		if err != nil {
+			return "", err
+		}
+		err = repo.Push(&git.PushOptions{
+			Auth: impl.Auth,
+		})
+		return commitHash, err
+	}
+	var signKey *openpgp.Entity
+	if impl.config.CommitSigningType == COMMIT_SIGNING_TYPE_GPG {
+		signKey, err = getGpgSigningEntity(impl.config.CommitSigningKey, impl.config.CommitSigningKeyPassphrase)
+		if err != nil {
+			return "", err
+		}
+	}
 	//--  commit
 	commit, err := workTree.Commit(commitMsg, &git.CommitOptions{
+		SignKey: signKey,
 		Author: &object.Signature{
 			Name:  name,
 			Email: emailId,
@@ -359,6 +451,34 @@ func (impl GitServiceImpl) CommitAndPushAllChanges(repoRoot, commitMsg, name, em
 	return commit.String(), err
 }
 
+// commitWithSshSignature commits through git cli as go-git can only sign with gpg keys, the key is written to a
+// temporary file for ssh-keygen which git uses to sign
+func (impl GitServiceImpl) commitWithSshSignature(repo *git.Repository, repoRoot, commitMsg, name, emailId string) (string, error) {
+	keyFile, err := ioutil.TempFile("", "gitops-signing-key-")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(keyFile.Name())
+	_, err = keyFile.WriteString(impl.config.CommitSigningKey)
+	if closeErr := keyFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+	_, errMsg, err := impl.gitCliUtil.CommitWithSshSignature(repoRoot, commitMsg, name, emailId, keyFile.Name())
+	if err != nil {
+		impl.logger.Errorw("error in signed commit", "repo", repoRoot, "errMsg", errMsg, "err", err)
+		return "", fmt.Errorf("error in signed commit: %s", errMsg)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+	impl.logger.Debugw("git hash", "repo", repoRoot, "hash", head.Hash().String())
+	return head.Hash().String(), nil
+}
+
 func (impl GitServiceImpl) getRepoAndWorktree(repoRoot string) (*git.Repository, *git.Worktree, error) {
 	var err error
 	start := time.Now()
@@ -432,3 +552,33 @@ func (impl GitServiceImpl) Pull(repoRoot string) (err error) {
 	}
 	return err
 }
+
+// GetLastCommitForPath returns the last commit of a cloned repo which changed path, nil if path was never committed
+func (impl GitServiceImpl) GetLastCommitForPath(repoRoot, path string) (*GitCommitDto, error) {
+	response, errMsg, err := impl.gitCliUtil.LastCommitForPath(repoRoot, path)
+	if err != nil {
+		impl.logger.Errorw("error in getting last commit for path", "repoRoot", repoRoot, "path", path, "errMsg", errMsg, "err", err)
+		return nil, err
+	}
+	if len(response) == 0 {
+		return nil, nil
+	}
+	fields := strings.Split(response, "\t")
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("unexpected git log output %q", response)
+	}
+	commitTime, err := time.Parse(time.RFC3339, fields[2])
+	if err != nil {
+		return nil, err
+	}
+	return &GitCommitDto{CommitHash: fields[0], AuthorName: fields[1], CommitTime: commitTime}, nil
+}
+
+func (impl GitServiceImpl) CheckoutBranch(repoRoot, branch string) error {
+	_, errMsg, err := impl.gitCliUtil.CheckoutNewBranch(repoRoot, branch)
+	if err != nil {
+		impl.logger.Errorw("error in checking out branch", "repoRoot", repoRoot, "branch", branch, "errMsg", errMsg, "err", err)
+		return fmt.Errorf("error in checking out branch %s: %s", branch, errMsg)
+	}
+	return nil
+}
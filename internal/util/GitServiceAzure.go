@@ -9,9 +9,12 @@ import (
 	"github.com/microsoft/azure-devops-go-api/azuredevops/git"
 	"go.uber.org/zap"
 	"path/filepath"
+	"strconv"
 	"time"
 )
 
+const azureEmptyObjectId = "0000000000000000000000000000000000000000"
+
 type GitAzureClient struct {
 	client                 *git.Client
 	logger                 *zap.SugaredLogger
@@ -146,21 +149,30 @@ func (impl GitAzureClient) CreateReadme(config *bean2.GitOpsConfigDto) (string,
 }
 
 func (impl GitAzureClient) CommitValues(config *ChartConfig, gitOpsConfig *bean2.GitOpsConfigDto) (commitHash string, commitTime time.Time, err error) {
-	branch := "master"
-	branchfull := "refs/heads/master"
+	branch := config.GetBranch()
+	branchfull := "refs/heads/" + branch
 	path := filepath.Join(config.ChartLocation, config.FileName)
 	ctx := context.Background()
 	newFile := true
-	oldObjId := "0000000000000000000000000000000000000000" //default commit hash
+	oldObjId := azureEmptyObjectId //default commit hash
 	// check if file exists and current hash
 	// if file does not exists get hash from branch
 	// if branch doesn't exists use default hash
 	clientAzure := *impl.client
-	fc, err := clientAzure.GetItem(ctx, git.GetItemArgs{
+	getItemArgs := git.GetItemArgs{
 		RepositoryId: &config.ChartRepoName,
 		Path:         &path,
 		Project:      &impl.project,
-	})
+	}
+	if branch != GITOPS_DEFAULT_BRANCH {
+		err = impl.createBranchIfNotExists(ctx, config.ChartRepoName, branch)
+		if err != nil {
+			impl.logger.Errorw("error in creating branch azure devops", "err", err, "repo", config.ChartRepoName, "branch", branch)
+			return "", time.Time{}, err
+		}
+		getItemArgs.VersionDescriptor = &git.GitVersionDescriptor{Version: &branch, VersionType: &git.GitVersionTypeValues.Branch}
+	}
+	fc, err := clientAzure.GetItem(ctx, getItemArgs)
 	if err != nil {
 		notFoundStatus := 404
 		if e, ok := err.(azuredevops.WrappedError); ok && *e.StatusCode == notFoundStatus {
@@ -321,3 +333,102 @@ func (impl GitAzureClient) GetCommits(repoName, projectName string) ([]*GitCommi
 	}
 	return gitCommitsDto, nil
 }
+
+func (impl GitAzureClient) createBranchIfNotExists(ctx context.Context, repoName string, branch string) error {
+	clientAzure := *impl.client
+	_, err := clientAzure.GetBranch(ctx, git.GetBranchArgs{Project: &impl.project, Name: &branch, RepositoryId: &repoName})
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(azuredevops.WrappedError); !ok || *e.StatusCode >= 500 {
+		return err
+	}
+	defaultBranch := GITOPS_DEFAULT_BRANCH
+	baseBranch, err := clientAzure.GetBranch(ctx, git.GetBranchArgs{Project: &impl.project, Name: &defaultBranch, RepositoryId: &repoName})
+	if err != nil {
+		return err
+	}
+	refName := "refs/heads/" + branch
+	oldObjectId := azureEmptyObjectId
+	results, err := clientAzure.UpdateRefs(ctx, git.UpdateRefsArgs{
+		RefUpdates:   &[]git.GitRefUpdate{{Name: &refName, OldObjectId: &oldObjectId, NewObjectId: baseBranch.Commit.CommitId}},
+		RepositoryId: &repoName,
+		Project:      &impl.project,
+	})
+	if err != nil {
+		return err
+	}
+	for _, result := range *results {
+		if result.Success != nil && !*result.Success {
+			return fmt.Errorf("unable to create branch %s, status %v", branch, *result.UpdateStatus)
+		}
+	}
+	return nil
+}
+
+func (impl GitAzureClient) CreatePullRequest(config *PullRequestConfig, gitOpsConfig *bean2.GitOpsConfigDto) (*PullRequestDto, error) {
+	clientAzure := *impl.client
+	sourceRefName := "refs/heads/" + config.SourceBranch
+	targetRefName := "refs/heads/" + config.TargetBranch
+	pullRequest, err := clientAzure.CreatePullRequest(context.Background(), git.CreatePullRequestArgs{
+		GitPullRequestToCreate: &git.GitPullRequest{
+			SourceRefName: &sourceRefName,
+			TargetRefName: &targetRefName,
+			Title:         &config.Title,
+			Description:   &config.Description,
+		},
+		RepositoryId: &config.ChartRepoName,
+		Project:      &impl.project,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in creating pull request azure devops", "err", err, "config", config)
+		return nil, err
+	}
+	return azurePullRequestDto(pullRequest), nil
+}
+
+func (impl GitAzureClient) GetPullRequest(repoName string, pullRequestId string, gitOpsConfig *bean2.GitOpsConfigDto) (*PullRequestDto, error) {
+	id, err := strconv.Atoi(pullRequestId)
+	if err != nil {
+		return nil, err
+	}
+	clientAzure := *impl.client
+	pullRequest, err := clientAzure.GetPullRequest(context.Background(), git.GetPullRequestArgs{
+		RepositoryId:  &repoName,
+		PullRequestId: &id,
+		Project:       &impl.project,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in getting pull request azure devops", "err", err, "repoName", repoName, "pullRequestId", pullRequestId)
+		return nil, err
+	}
+	return azurePullRequestDto(pullRequest), nil
+}
+
+func azurePullRequestDto(pullRequest *git.GitPullRequest) *PullRequestDto {
+	pullRequestDto := &PullRequestDto{
+		State: PULL_REQUEST_OPEN,
+	}
+	if pullRequest.PullRequestId != nil {
+		pullRequestDto.Id = strconv.Itoa(*pullRequest.PullRequestId)
+	}
+	if pullRequest.Repository != nil && pullRequest.Repository.WebUrl != nil {
+		pullRequestDto.Url = fmt.Sprintf("%s/pullrequest/%s", *pullRequest.Repository.WebUrl, pullRequestDto.Id)
+	}
+	if pullRequest.Status == nil {
+		return pullRequestDto
+	}
+	switch *pullRequest.Status {
+	case git.PullRequestStatusValues.Completed:
+		pullRequestDto.State = PULL_REQUEST_MERGED
+		if pullRequest.LastMergeCommit != nil && pullRequest.LastMergeCommit.CommitId != nil {
+			pullRequestDto.MergeCommitHash = *pullRequest.LastMergeCommit.CommitId
+		}
+		if pullRequest.ClosedDate != nil {
+			pullRequestDto.MergedOn = pullRequest.ClosedDate.Time
+		}
+	case git.PullRequestStatusValues.Abandoned:
+		pullRequestDto.State = PULL_REQUEST_CLOSED
+	}
+	return pullRequestDto
+}
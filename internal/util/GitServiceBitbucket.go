@@ -1,6 +1,7 @@
 package util
 
 import (
+	"encoding/json"
 	"fmt"
 	bean2 "github.com/devtron-labs/devtron/api/bean"
 	"github.com/devtron-labs/devtron/internal/sql/repository"
@@ -21,6 +22,20 @@ const (
 	BITBUCKET_COMMIT_TIME_LAYOUT   = "2001-01-01T10:00:00+00:00"
 )
 
+type bitbucketPullRequest struct {
+	Id          int    `json:"id"`
+	State       string `json:"state"`
+	UpdatedOn   string `json:"updated_on"`
+	MergeCommit *struct {
+		Hash string `json:"hash"`
+	} `json:"merge_commit"`
+	Links struct {
+		Html struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+}
+
 type GitBitbucketClient struct {
 	client                 *bitbucket.Client
 	logger                 *zap.SugaredLogger
@@ -228,7 +243,7 @@ func (impl GitBitbucketClient) CommitValues(config *ChartConfig, gitOpsConfig *b
 		FilePath: bitbucketCommitFilePath,
 		FileName: fileName,
 		Message:  config.ReleaseMessage,
-		Branch:   "master",
+		Branch:   config.GetBranch(),
 		Author:   authorBitbucket,
 	}
 	err = impl.client.Repositories.Repository.WriteFileBlob(repoWriteOptions)
@@ -239,7 +254,7 @@ func (impl GitBitbucketClient) CommitValues(config *ChartConfig, gitOpsConfig *b
 	commitOptions := &bitbucket.CommitsOptions{
 		RepoSlug:    config.ChartRepoName,
 		Owner:       gitOpsConfig.BitBucketWorkspaceId,
-		Branchortag: "master",
+		Branchortag: config.GetBranch(),
 	}
 	commits, err := impl.client.Repositories.Commits.GetCommits(commitOptions)
 	if err != nil {
@@ -300,3 +315,86 @@ func (impl GitBitbucketClient) GetCommits(repoName, projectName string) ([]*GitC
 	}
 	return gitCommitsDto, nil
 }
+
+func (impl GitBitbucketClient) CreatePullRequest(config *PullRequestConfig, gitOpsConfig *bean2.GitOpsConfigDto) (*PullRequestDto, error) {
+	response, err := impl.client.Repositories.PullRequests.Create(&bitbucket.PullRequestsOptions{
+		Owner:             gitOpsConfig.BitBucketWorkspaceId,
+		RepoSlug:          config.ChartRepoName,
+		Title:             config.Title,
+		Description:       config.Description,
+		SourceBranch:      config.SourceBranch,
+		DestinationBranch: config.TargetBranch,
+		CloseSourceBranch: true,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in creating pull request bitbucket", "err", err, "config", config)
+		return nil, err
+	}
+	return bitbucketPullRequestDto(response)
+}
+
+func (impl GitBitbucketClient) GetPullRequest(repoName string, pullRequestId string, gitOpsConfig *bean2.GitOpsConfigDto) (*PullRequestDto, error) {
+	response, err := impl.client.Repositories.PullRequests.Get(&bitbucket.PullRequestsOptions{
+		ID:       pullRequestId,
+		Owner:    gitOpsConfig.BitBucketWorkspaceId,
+		RepoSlug: repoName,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in getting pull request bitbucket", "err", err, "repoName", repoName, "pullRequestId", pullRequestId)
+		return nil, err
+	}
+	pullRequestDto, err := bitbucketPullRequestDto(response)
+	if err != nil || len(pullRequestDto.MergeCommitHash) == 0 {
+		return pullRequestDto, err
+	}
+	//pull request response carries the abbreviated merge commit hash, resolving it to the full hash synced by argocd
+	commit, err := impl.client.Repositories.Commits.GetCommit(&bitbucket.CommitsOptions{
+		Owner:    gitOpsConfig.BitBucketWorkspaceId,
+		RepoSlug: repoName,
+		Revision: pullRequestDto.MergeCommitHash,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in getting merge commit bitbucket", "err", err, "repoName", repoName, "commit", pullRequestDto.MergeCommitHash)
+		return nil, err
+	}
+	if commitMap, ok := commit.(map[string]interface{}); ok {
+		if hash, ok := commitMap["hash"].(string); ok {
+			pullRequestDto.MergeCommitHash = hash
+		}
+		if date, ok := commitMap["date"].(string); ok {
+			if commitTime, err := time.Parse(time.RFC3339, date); err == nil {
+				pullRequestDto.MergedOn = commitTime
+			}
+		}
+	}
+	return pullRequestDto, nil
+}
+
+// bitbucketPullRequestDto converts the untyped pull request response of the bitbucket client
+func bitbucketPullRequestDto(response interface{}) (*PullRequestDto, error) {
+	responseJson, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+	pullRequest := &bitbucketPullRequest{}
+	err = json.Unmarshal(responseJson, pullRequest)
+	if err != nil {
+		return nil, err
+	}
+	pullRequestDto := &PullRequestDto{
+		Id:    fmt.Sprintf("%d", pullRequest.Id),
+		Url:   pullRequest.Links.Html.Href,
+		State: PULL_REQUEST_OPEN,
+	}
+	switch pullRequest.State {
+	case "MERGED":
+		pullRequestDto.State = PULL_REQUEST_MERGED
+		if pullRequest.MergeCommit != nil {
+			pullRequestDto.MergeCommitHash = pullRequest.MergeCommit.Hash
+		}
+		pullRequestDto.MergedOn, _ = time.Parse(time.RFC3339, pullRequest.UpdatedOn)
+	case "DECLINED", "SUPERSEDED":
+		pullRequestDto.State = PULL_REQUEST_CLOSED
+	}
+	return pullRequestDto, nil
+}
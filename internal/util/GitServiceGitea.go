@@ -0,0 +1,457 @@
+package util
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	bean2 "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"go.uber.org/zap"
+	"io"
+	http2 "net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	GITEA_API_V1         = "api/v1"
+	GITEA_DEFAULT_BRANCH = "master"
+	giteaRequestTimeout  = 60 * time.Second
+)
+
+// GiteaErrorResponse is returned for non 2xx responses of the gitea api
+type GiteaErrorResponse struct {
+	StatusCode int
+	Message    string `json:"message"`
+}
+
+func (err *GiteaErrorResponse) Error() string {
+	return fmt.Sprintf("gitea api responded with status %d: %s", err.StatusCode, err.Message)
+}
+
+type giteaRepository struct {
+	Name     string `json:"name"`
+	CloneUrl string `json:"clone_url"`
+}
+
+type giteaCreateRepoOption struct {
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Private       bool   `json:"private"`
+	AutoInit      bool   `json:"auto_init"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+type giteaIdentity struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type giteaCommitDates struct {
+	Author    time.Time `json:"author"`
+	Committer time.Time `json:"committer"`
+}
+
+type giteaFileOptions struct {
+	Content   string            `json:"content"`
+	Message   string            `json:"message"`
+	Branch    string            `json:"branch"`
+	NewBranch string            `json:"new_branch,omitempty"`
+	SHA       string            `json:"sha,omitempty"`
+	Author    *giteaIdentity    `json:"author"`
+	Committer *giteaIdentity    `json:"committer"`
+	Dates     *giteaCommitDates `json:"dates"`
+}
+
+type giteaContents struct {
+	SHA string `json:"sha"`
+}
+
+type giteaCommitUser struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+type giteaFileResponse struct {
+	Commit struct {
+		SHA       string           `json:"sha"`
+		Author    *giteaCommitUser `json:"author"`
+		Committer *giteaCommitUser `json:"committer"`
+	} `json:"commit"`
+}
+
+type giteaCommit struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		Author *giteaCommitUser `json:"author"`
+	} `json:"commit"`
+}
+
+type giteaCreatePullRequestOption struct {
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type giteaPullRequest struct {
+	Number         int        `json:"number"`
+	HtmlUrl        string     `json:"html_url"`
+	State          string     `json:"state"`
+	Merged         bool       `json:"merged"`
+	MergeCommitSha string     `json:"merge_commit_sha"`
+	MergedAt       *time.Time `json:"merged_at"`
+}
+
+// GitGiteaClient implements GitClient over the gitea api, forgejo is served by the same api
+type GitGiteaClient struct {
+	httpClient             *http2.Client
+	baseUrl                string
+	token                  string
+	org                    string
+	logger                 *zap.SugaredLogger
+	gitService             GitService
+	gitOpsConfigRepository repository.GitOpsConfigRepository
+}
+
+func NewGitGiteaClient(host string, token string, org string, logger *zap.SugaredLogger, gitService GitService,
+	gitOpsConfigRepository repository.GitOpsConfigRepository) (GitGiteaClient, error) {
+	hostUrl, err := url.Parse(host)
+	if err != nil || len(hostUrl.Host) == 0 {
+		logger.Errorw("error in creating gitea client", "host", host, "err", err)
+		return GitGiteaClient{}, fmt.Errorf("invalid gitea host '%s'", host)
+	}
+	hostUrl.Path = path.Join(hostUrl.Path, GITEA_API_V1)
+	return GitGiteaClient{
+		httpClient:             &http2.Client{Timeout: giteaRequestTimeout},
+		baseUrl:                hostUrl.String(),
+		token:                  token,
+		org:                    org,
+		logger:                 logger,
+		gitService:             gitService,
+		gitOpsConfigRepository: gitOpsConfigRepository,
+	}, nil
+}
+
+func (impl GitGiteaClient) DeleteRepository(config *bean2.GitOpsConfigDto) error {
+	err := impl.doRequest(http2.MethodDelete, impl.repoPath(config.GitRepoName), nil, nil)
+	if err != nil {
+		impl.logger.Errorw("repo deletion failed for gitea", "repo", config.GitRepoName, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl GitGiteaClient) CreateRepository(config *bean2.GitOpsConfigDto) (url string, isNew bool, detailedErrorGitOpsConfigActions DetailedErrorGitOpsConfigActions) {
+	detailedErrorGitOpsConfigActions.StageErrorMap = make(map[string]error)
+	url, err := impl.GetRepoUrl(config)
+	if err == nil {
+		detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, GetRepoUrlStage)
+		return url, false, detailedErrorGitOpsConfigActions
+	} else if !isGiteaNotFound(err) {
+		impl.logger.Errorw("error in creating gitea repo", "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[GetRepoUrlStage] = err
+		return "", false, detailedErrorGitOpsConfigActions
+	}
+	repo := &giteaRepository{}
+	err = impl.doRequest(http2.MethodPost, impl.orgReposPath(), &giteaCreateRepoOption{
+		Name:          config.GitRepoName,
+		Description:   config.Description,
+		Private:       true,
+		AutoInit:      true,
+		DefaultBranch: GITEA_DEFAULT_BRANCH,
+	}, repo)
+	if err != nil {
+		impl.logger.Errorw("error in creating gitea repo, ", "repo", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CreateRepoStage] = err
+		return "", true, detailedErrorGitOpsConfigActions
+	}
+	impl.logger.Infow("gitea repo created ", "r", repo.CloneUrl)
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, CreateRepoStage)
+
+	validated, err := impl.ensureProjectAvailabilityOnHttp(config)
+	if err != nil {
+		impl.logger.Errorw("error in ensuring project availability gitea", "project", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CloneHttpStage] = err
+		return repo.CloneUrl, true, detailedErrorGitOpsConfigActions
+	}
+	if !validated {
+		detailedErrorGitOpsConfigActions.StageErrorMap[CloneHttpStage] = fmt.Errorf("unable to validate project:%s in given time", config.GitRepoName)
+		return "", true, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, CloneHttpStage)
+
+	_, err = impl.CreateReadme(config)
+	if err != nil {
+		impl.logger.Errorw("error in creating readme gitea", "project", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CreateReadmeStage] = err
+		return repo.CloneUrl, true, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, CreateReadmeStage)
+
+	validated, err = impl.ensureProjectAvailabilityOnSsh(config.GitRepoName, repo.CloneUrl)
+	if err != nil {
+		impl.logger.Errorw("error in ensuring project availability gitea", "project", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CloneSshStage] = err
+		return repo.CloneUrl, true, detailedErrorGitOpsConfigActions
+	}
+	if !validated {
+		detailedErrorGitOpsConfigActions.StageErrorMap[CloneSshStage] = fmt.Errorf("unable to validate project:%s in given time", config.GitRepoName)
+		return "", true, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, CloneSshStage)
+	return repo.CloneUrl, true, detailedErrorGitOpsConfigActions
+}
+
+func (impl GitGiteaClient) CreateReadme(config *bean2.GitOpsConfigDto) (string, error) {
+	cfg := &ChartConfig{
+		ChartName:      config.GitRepoName,
+		ChartLocation:  "",
+		FileName:       "README.md",
+		FileContent:    "@devtron",
+		ReleaseMessage: "readme",
+		ChartRepoName:  config.GitRepoName,
+		UserName:       config.Username,
+		UserEmailId:    config.UserEmailId,
+	}
+	hash, _, err := impl.CommitValues(cfg, config)
+	if err != nil {
+		impl.logger.Errorw("error in creating readme gitea", "repo", config.GitRepoName, "err", err)
+	}
+	return hash, err
+}
+
+func (impl GitGiteaClient) CommitValues(config *ChartConfig, gitOpsConfig *bean2.GitOpsConfigDto) (commitHash string, commitTime time.Time, err error) {
+	filePath := filepath.Join(config.ChartLocation, config.FileName)
+	contentsPath := fmt.Sprintf("%s/contents/%s", impl.repoPath(config.ChartRepoName), escapeFilePath(filePath))
+	// a missing branch is created from the default branch along with the commit
+	branch := config.GetBranch()
+	newBranch := ""
+	if branch != GITEA_DEFAULT_BRANCH {
+		err = impl.doRequest(http2.MethodGet, fmt.Sprintf("%s/branches/%s", impl.repoPath(config.ChartRepoName), url.PathEscape(branch)), nil, nil)
+		if err != nil {
+			if !isGiteaNotFound(err) {
+				impl.logger.Errorw("error in fetching branch gitea", "err", err, "repo", config.ChartRepoName, "branch", branch)
+				return "", time.Time{}, err
+			}
+			newBranch = branch
+			branch = GITEA_DEFAULT_BRANCH
+		}
+	}
+	existingFile := &giteaContents{}
+	err = impl.doRequest(http2.MethodGet, contentsPath+"?ref="+url.QueryEscape(branch), nil, existingFile)
+	newFile := false
+	if err != nil {
+		if !isGiteaNotFound(err) {
+			impl.logger.Errorw("error in fetching file gitea", "err", err, "config", config)
+			return "", time.Time{}, err
+		}
+		newFile = true
+	}
+	timeNow := time.Now()
+	identity := &giteaIdentity{Name: config.UserName, Email: config.UserEmailId}
+	options := &giteaFileOptions{
+		Content:   base64.StdEncoding.EncodeToString([]byte(config.FileContent)),
+		Message:   config.ReleaseMessage,
+		Branch:    branch,
+		NewBranch: newBranch,
+		Author:    identity,
+		Committer: identity,
+		Dates:     &giteaCommitDates{Author: timeNow, Committer: timeNow},
+	}
+	method := http2.MethodPost
+	if !newFile {
+		method = http2.MethodPut
+		options.SHA = existingFile.SHA
+	}
+	fileResponse := &giteaFileResponse{}
+	err = impl.doRequest(method, contentsPath, options, fileResponse)
+	if err != nil {
+		impl.logger.Errorw("error in commit gitea", "err", err, "config", config)
+		return "", time.Time{}, err
+	}
+	commitTime = timeNow
+	if fileResponse.Commit.Author != nil && !fileResponse.Commit.Author.Date.IsZero() {
+		commitTime = fileResponse.Commit.Author.Date
+	}
+	return fileResponse.Commit.SHA, commitTime, nil
+}
+
+func (impl GitGiteaClient) GetRepoUrl(config *bean2.GitOpsConfigDto) (repoUrl string, err error) {
+	repo := &giteaRepository{}
+	err = impl.doRequest(http2.MethodGet, impl.repoPath(config.GitRepoName), nil, repo)
+	if err != nil {
+		return "", err
+	}
+	return repo.CloneUrl, nil
+}
+
+func (impl GitGiteaClient) GetCommits(repoName, projectName string) ([]*GitCommitDto, error) {
+	var giteaCommits []*giteaCommit
+	err := impl.doRequest(http2.MethodGet, impl.repoPath(repoName)+"/commits?sha="+GITEA_DEFAULT_BRANCH, nil, &giteaCommits)
+	if err != nil {
+		impl.logger.Errorw("error in getting commits", "err", err, "repoName", repoName)
+		return nil, err
+	}
+	var gitCommitsDto []*GitCommitDto
+	for _, commit := range giteaCommits {
+		gitCommitDto := &GitCommitDto{
+			CommitHash: commit.SHA,
+		}
+		if commit.Commit.Author != nil {
+			gitCommitDto.AuthorName = commit.Commit.Author.Name
+			gitCommitDto.CommitTime = commit.Commit.Author.Date
+		}
+		gitCommitsDto = append(gitCommitsDto, gitCommitDto)
+	}
+	return gitCommitsDto, nil
+}
+
+func (impl GitGiteaClient) CreatePullRequest(config *PullRequestConfig, gitOpsConfig *bean2.GitOpsConfigDto) (*PullRequestDto, error) {
+	pullRequest := &giteaPullRequest{}
+	err := impl.doRequest(http2.MethodPost, impl.repoPath(config.ChartRepoName)+"/pulls", &giteaCreatePullRequestOption{
+		Head:  config.SourceBranch,
+		Base:  config.TargetBranch,
+		Title: config.Title,
+		Body:  config.Description,
+	}, pullRequest)
+	if err != nil {
+		impl.logger.Errorw("error in creating pull request gitea", "err", err, "config", config)
+		return nil, err
+	}
+	return giteaPullRequestDto(pullRequest), nil
+}
+
+func (impl GitGiteaClient) GetPullRequest(repoName string, pullRequestId string, gitOpsConfig *bean2.GitOpsConfigDto) (*PullRequestDto, error) {
+	pullRequest := &giteaPullRequest{}
+	err := impl.doRequest(http2.MethodGet, fmt.Sprintf("%s/pulls/%s", impl.repoPath(repoName), url.PathEscape(pullRequestId)), nil, pullRequest)
+	if err != nil {
+		impl.logger.Errorw("error in getting pull request gitea", "err", err, "repoName", repoName, "pullRequestId", pullRequestId)
+		return nil, err
+	}
+	return giteaPullRequestDto(pullRequest), nil
+}
+
+func giteaPullRequestDto(pullRequest *giteaPullRequest) *PullRequestDto {
+	pullRequestDto := &PullRequestDto{
+		Id:    strconv.Itoa(pullRequest.Number),
+		Url:   pullRequest.HtmlUrl,
+		State: PULL_REQUEST_OPEN,
+	}
+	if pullRequest.Merged {
+		pullRequestDto.State = PULL_REQUEST_MERGED
+		pullRequestDto.MergeCommitHash = pullRequest.MergeCommitSha
+		if pullRequest.MergedAt != nil {
+			pullRequestDto.MergedOn = *pullRequest.MergedAt
+		}
+	} else if pullRequest.State == "closed" {
+		pullRequestDto.State = PULL_REQUEST_CLOSED
+	}
+	return pullRequestDto
+}
+
+func (impl GitGiteaClient) ensureProjectAvailabilityOnHttp(config *bean2.GitOpsConfigDto) (bool, error) {
+	count := 0
+	for count < 3 {
+		count = count + 1
+		_, err := impl.GetRepoUrl(config)
+		if err == nil {
+			return true, nil
+		}
+		if !isGiteaNotFound(err) {
+			impl.logger.Errorw("error in validating repo gitea", "project", config.GitRepoName, "err", err)
+			return false, err
+		} else {
+			impl.logger.Errorw("error in validating repo gitea", "project", config.GitRepoName, "err", err)
+		}
+		time.Sleep(10 * time.Second)
+	}
+	return false, nil
+}
+
+func (impl GitGiteaClient) ensureProjectAvailabilityOnSsh(projectName string, repoUrl string) (bool, error) {
+	count := 0
+	for count < 3 {
+		count = count + 1
+		_, err := impl.gitService.Clone(repoUrl, fmt.Sprintf("/ensure-clone/%s", projectName))
+		if err == nil {
+			impl.logger.Infow("gitea ensureProjectAvailability clone passed", "try count", count, "repoUrl", repoUrl)
+			return true, nil
+		}
+		impl.logger.Errorw("gitea ensureProjectAvailability clone failed", "try count", count, "err", err)
+		time.Sleep(10 * time.Second)
+	}
+	return false, nil
+}
+
+func (impl GitGiteaClient) orgReposPath() string {
+	return fmt.Sprintf("/orgs/%s/repos", url.PathEscape(impl.org))
+}
+
+func (impl GitGiteaClient) repoPath(repoName string) string {
+	return fmt.Sprintf("/repos/%s/%s", url.PathEscape(impl.org), url.PathEscape(repoName))
+}
+
+// doRequest calls the gitea api, request is sent as json when not nil and the response is decoded into response when not nil
+func (impl GitGiteaClient) doRequest(method string, apiPath string, request interface{}, response interface{}) error {
+	var body io.Reader
+	if request != nil {
+		requestJson, err := json.Marshal(request)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(requestJson)
+	}
+	req, err := http2.NewRequest(method, impl.baseUrl+apiPath, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if request != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if len(impl.token) > 0 {
+		req.Header.Set("Authorization", "token "+impl.token)
+	}
+	resp, err := impl.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		errorResponse := &GiteaErrorResponse{}
+		_ = json.Unmarshal(respBody, errorResponse)
+		errorResponse.StatusCode = resp.StatusCode
+		if len(errorResponse.Message) == 0 {
+			errorResponse.Message = http2.StatusText(resp.StatusCode)
+		}
+		return errorResponse
+	}
+	if response != nil && len(respBody) > 0 {
+		return json.Unmarshal(respBody, response)
+	}
+	return nil
+}
+
+func isGiteaNotFound(err error) bool {
+	errorResponse, ok := err.(*GiteaErrorResponse)
+	return ok && errorResponse.StatusCode == http2.StatusNotFound
+}
+
+func escapeFilePath(filePath string) string {
+	segments := strings.Split(filepath.ToSlash(filePath), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
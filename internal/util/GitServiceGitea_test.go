@@ -0,0 +1,143 @@
+package util
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGitGiteaClient(t *testing.T) {
+	logger, err := NewSugardLogger()
+	assert.Nil(t, err)
+	files := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/repos/devtron/sample":
+			_ = json.NewEncoder(w).Encode(map[string]string{"name": "sample", "clone_url": "http://gitea.local/devtron/sample.git"})
+		case r.URL.Path == "/api/v1/repos/devtron/sample/contents/charts/values.yaml":
+			sha, exists := files[r.URL.Path]
+			if r.Method == http.MethodGet {
+				if !exists {
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"message":"file not found"}`))
+					return
+				}
+				_ = json.NewEncoder(w).Encode(map[string]string{"sha": sha})
+				return
+			}
+			options := &giteaFileOptions{}
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(options))
+			if exists {
+				assert.Equal(t, http.MethodPut, r.Method)
+				assert.Equal(t, sha, options.SHA)
+			} else {
+				assert.Equal(t, http.MethodPost, r.Method)
+			}
+			content, _ := base64.StdEncoding.DecodeString(options.Content)
+			assert.Equal(t, "replicaCount: 2", string(content))
+			files[r.URL.Path] = "blob-" + r.Method
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"commit": map[string]interface{}{
+				"sha":    "commit-" + r.Method,
+				"author": map[string]string{"name": options.Author.Name, "date": "2023-01-02T03:04:05Z"},
+			}})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/repos/devtron/sample/commits":
+			_, _ = w.Write([]byte(`[{"sha":"commit-PUT","commit":{"author":{"name":"admin","date":"2023-01-02T03:04:05Z"}}}]`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/repos/devtron/sample":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewGitGiteaClient(server.URL, "secret", "devtron", logger, nil, nil)
+	assert.Nil(t, err)
+
+	repoUrl, err := client.GetRepoUrl(&bean.GitOpsConfigDto{GitRepoName: "sample"})
+	assert.Nil(t, err)
+	assert.Equal(t, "http://gitea.local/devtron/sample.git", repoUrl)
+	_, err = client.GetRepoUrl(&bean.GitOpsConfigDto{GitRepoName: "missing"})
+	assert.True(t, isGiteaNotFound(err))
+
+	chartConfig := &ChartConfig{ChartLocation: "charts", FileName: "values.yaml", FileContent: "replicaCount: 2",
+		ChartRepoName: "sample", UserName: "admin", UserEmailId: "admin@devtron.ai", ReleaseMessage: "update"}
+	commitHash, _, err := client.CommitValues(chartConfig, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "commit-POST", commitHash)
+	commitHash, commitTime, err := client.CommitValues(chartConfig, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "commit-PUT", commitHash)
+	assert.Equal(t, time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC), commitTime.UTC())
+
+	commits, err := client.GetCommits("sample", "")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(commits))
+	assert.Equal(t, "admin", commits[0].AuthorName)
+
+	assert.Nil(t, client.DeleteRepository(&bean.GitOpsConfigDto{GitRepoName: "sample"}))
+}
+
+func TestGitGiteaClientPullRequest(t *testing.T) {
+	logger, err := NewSugardLogger()
+	assert.Nil(t, err)
+	merged := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/repos/devtron/sample/branches/release-1":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"branch not found"}`))
+		case r.URL.Path == "/api/v1/repos/devtron/sample/contents/values.yaml":
+			if r.Method == http.MethodGet {
+				assert.Equal(t, "master", r.URL.Query().Get("ref"))
+				_ = json.NewEncoder(w).Encode(map[string]string{"sha": "blob"})
+				return
+			}
+			options := &giteaFileOptions{}
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(options))
+			assert.Equal(t, "master", options.Branch)
+			assert.Equal(t, "release-1", options.NewBranch)
+			_, _ = w.Write([]byte(`{"commit":{"sha":"branch-commit"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/repos/devtron/sample/pulls":
+			options := &giteaCreatePullRequestOption{}
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(options))
+			assert.Equal(t, "release-1", options.Head)
+			assert.Equal(t, "master", options.Base)
+			_, _ = w.Write([]byte(`{"number":3,"html_url":"http://gitea.local/devtron/sample/pulls/3","state":"open"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/repos/devtron/sample/pulls/3":
+			if merged {
+				_, _ = w.Write([]byte(`{"number":3,"state":"closed","merged":true,"merge_commit_sha":"merge-commit","merged_at":"2023-01-02T03:04:05Z"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"number":3,"state":"open"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewGitGiteaClient(server.URL, "secret", "devtron", logger, nil, nil)
+	assert.Nil(t, err)
+	commitHash, _, err := client.CommitValues(&ChartConfig{FileName: "values.yaml", ChartRepoName: "sample", Branch: "release-1"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "branch-commit", commitHash)
+
+	pullRequest, err := client.CreatePullRequest(&PullRequestConfig{ChartRepoName: "sample", SourceBranch: "release-1", TargetBranch: GITOPS_DEFAULT_BRANCH}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "3", pullRequest.Id)
+	assert.Equal(t, PULL_REQUEST_OPEN, pullRequest.State)
+	assert.Equal(t, "http://gitea.local/devtron/sample/pulls/3", pullRequest.Url)
+
+	merged = true
+	pullRequest, err = client.GetPullRequest("sample", "3", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, PULL_REQUEST_MERGED, pullRequest.State)
+	assert.Equal(t, "merge-commit", pullRequest.MergeCommitHash)
+	assert.Equal(t, time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC), pullRequest.MergedOn.UTC())
+}
@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"path"
 	"path/filepath"
+	"strconv"
 	"time"
 )
 
@@ -156,9 +157,16 @@ func (impl GitHubClient) CreateReadme(config *bean2.GitOpsConfigDto) (string, er
 }
 
 func (impl GitHubClient) CommitValues(config *ChartConfig, gitOpsConfig *bean2.GitOpsConfigDto) (commitHash string, commitTime time.Time, err error) {
-	branch := "master"
+	branch := config.GetBranch()
 	path := filepath.Join(config.ChartLocation, config.FileName)
 	ctx := context.Background()
+	if branch != GITOPS_DEFAULT_BRANCH {
+		err = impl.createBranchIfNotExists(ctx, config.ChartRepoName, branch)
+		if err != nil {
+			impl.logger.Errorw("error in creating branch github", "err", err, "repo", config.ChartRepoName, "branch", branch)
+			return "", time.Time{}, err
+		}
+	}
 	newFile := false
 	fc, _, _, err := impl.client.Repositories.GetContents(ctx, impl.org, config.ChartRepoName, path, &github.RepositoryContentGetOptions{Ref: branch})
 	if err != nil {
@@ -263,3 +271,67 @@ func (impl GitHubClient) GetCommits(repoName, projectName string) ([]*GitCommitD
 	}
 	return gitCommitsDto, nil
 }
+
+func (impl GitHubClient) createBranchIfNotExists(ctx context.Context, repoName string, branch string) error {
+	_, _, err := impl.client.Repositories.GetBranch(ctx, impl.org, repoName, branch)
+	if err == nil {
+		return nil
+	}
+	responseErr, ok := err.(*github.ErrorResponse)
+	if !ok || responseErr.Response.StatusCode != 404 {
+		return err
+	}
+	baseBranch, _, err := impl.client.Repositories.GetBranch(ctx, impl.org, repoName, GITOPS_DEFAULT_BRANCH)
+	if err != nil {
+		return err
+	}
+	ref := "refs/heads/" + branch
+	_, _, err = impl.client.Git.CreateRef(ctx, impl.org, repoName, &github.Reference{
+		Ref:    &ref,
+		Object: &github.GitObject{SHA: baseBranch.Commit.SHA},
+	})
+	return err
+}
+
+func (impl GitHubClient) CreatePullRequest(config *PullRequestConfig, gitOpsConfig *bean2.GitOpsConfigDto) (*PullRequestDto, error) {
+	pullRequest, _, err := impl.client.PullRequests.Create(context.Background(), impl.org, config.ChartRepoName, &github.NewPullRequest{
+		Title: &config.Title,
+		Head:  &config.SourceBranch,
+		Base:  &config.TargetBranch,
+		Body:  &config.Description,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in creating pull request github", "err", err, "config", config)
+		return nil, err
+	}
+	return githubPullRequestDto(pullRequest), nil
+}
+
+func (impl GitHubClient) GetPullRequest(repoName string, pullRequestId string, gitOpsConfig *bean2.GitOpsConfigDto) (*PullRequestDto, error) {
+	number, err := strconv.Atoi(pullRequestId)
+	if err != nil {
+		return nil, err
+	}
+	pullRequest, _, err := impl.client.PullRequests.Get(context.Background(), impl.org, repoName, number)
+	if err != nil {
+		impl.logger.Errorw("error in getting pull request github", "err", err, "repoName", repoName, "pullRequestId", pullRequestId)
+		return nil, err
+	}
+	return githubPullRequestDto(pullRequest), nil
+}
+
+func githubPullRequestDto(pullRequest *github.PullRequest) *PullRequestDto {
+	pullRequestDto := &PullRequestDto{
+		Id:    strconv.Itoa(pullRequest.GetNumber()),
+		Url:   pullRequest.GetHTMLURL(),
+		State: PULL_REQUEST_OPEN,
+	}
+	if pullRequest.GetMerged() {
+		pullRequestDto.State = PULL_REQUEST_MERGED
+		pullRequestDto.MergeCommitHash = pullRequest.GetMergeCommitSHA()
+		pullRequestDto.MergedOn = pullRequest.GetMergedAt()
+	} else if pullRequest.GetState() == "closed" {
+		pullRequestDto.State = PULL_REQUEST_CLOSED
+	}
+	return pullRequestDto
+}
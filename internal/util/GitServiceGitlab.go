@@ -247,9 +247,23 @@ func (impl GitLabClient) checkIfFileExists(projectName, ref, file string) (exist
 }
 
 func (impl GitLabClient) CommitValues(config *ChartConfig, gitOpsConfig *bean2.GitOpsConfigDto) (commitHash string, commitTime time.Time, err error) {
-	branch := "master"
+	branch := config.GetBranch()
 	path := filepath.Join(config.ChartLocation, config.FileName)
-	exists, err := impl.checkIfFileExists(config.ChartRepoName, branch, path)
+	// a missing branch is created from the default branch by the commit itself
+	var startBranch *string
+	fileRef := branch
+	if branch != GITOPS_DEFAULT_BRANCH {
+		_, res, err := impl.client.Branches.GetBranch(fmt.Sprintf("%s/%s", impl.config.GitlabGroupPath, config.ChartRepoName), branch)
+		if err != nil {
+			if res == nil || res.StatusCode != 404 {
+				impl.logger.Errorw("error in getting branch gitlab", "err", err, "repo", config.ChartRepoName, "branch", branch)
+				return "", time.Time{}, err
+			}
+			startBranch = gitlab.String(GITOPS_DEFAULT_BRANCH)
+			fileRef = GITOPS_DEFAULT_BRANCH
+		}
+	}
+	exists, err := impl.checkIfFileExists(config.ChartRepoName, fileRef, path)
 	var fileAction gitlab.FileActionValue
 	if exists {
 		fileAction = gitlab.FileUpdate
@@ -258,6 +272,7 @@ func (impl GitLabClient) CommitValues(config *ChartConfig, gitOpsConfig *bean2.G
 	}
 	actions := &gitlab.CreateCommitOptions{
 		Branch:        &branch,
+		StartBranch:   startBranch,
 		CommitMessage: gitlab.String(config.ReleaseMessage),
 		Actions:       []*gitlab.CommitActionOptions{{Action: &fileAction, FilePath: &path, Content: &config.FileContent}},
 		AuthorEmail:   &config.UserEmailId,
@@ -292,3 +307,57 @@ func (impl GitLabClient) GetCommits(repoName, projectName string) ([]*GitCommitD
 	}
 	return gitCommitsDto, nil
 }
+
+func (impl GitLabClient) CreatePullRequest(config *PullRequestConfig, gitOpsConfig *bean2.GitOpsConfigDto) (*PullRequestDto, error) {
+	mergeRequest, _, err := impl.client.MergeRequests.CreateMergeRequest(fmt.Sprintf("%s/%s", impl.config.GitlabGroupPath, config.ChartRepoName), &gitlab.CreateMergeRequestOptions{
+		Title:              &config.Title,
+		Description:        &config.Description,
+		SourceBranch:       &config.SourceBranch,
+		TargetBranch:       &config.TargetBranch,
+		RemoveSourceBranch: gitlab.Bool(true),
+	})
+	if err != nil {
+		impl.logger.Errorw("error in creating merge request gitlab", "err", err, "config", config)
+		return nil, err
+	}
+	return gitlabPullRequestDto(mergeRequest), nil
+}
+
+func (impl GitLabClient) GetPullRequest(repoName string, pullRequestId string, gitOpsConfig *bean2.GitOpsConfigDto) (*PullRequestDto, error) {
+	mergeRequestIid, err := strconv.Atoi(pullRequestId)
+	if err != nil {
+		return nil, err
+	}
+	mergeRequest, _, err := impl.client.MergeRequests.GetMergeRequest(fmt.Sprintf("%s/%s", impl.config.GitlabGroupPath, repoName), mergeRequestIid, nil)
+	if err != nil {
+		impl.logger.Errorw("error in getting merge request gitlab", "err", err, "repoName", repoName, "pullRequestId", pullRequestId)
+		return nil, err
+	}
+	return gitlabPullRequestDto(mergeRequest), nil
+}
+
+func gitlabPullRequestDto(mergeRequest *gitlab.MergeRequest) *PullRequestDto {
+	pullRequestDto := &PullRequestDto{
+		Id:    strconv.Itoa(mergeRequest.IID),
+		Url:   mergeRequest.WebURL,
+		State: PULL_REQUEST_OPEN,
+	}
+	switch mergeRequest.State {
+	case "merged":
+		pullRequestDto.State = PULL_REQUEST_MERGED
+		pullRequestDto.MergeCommitHash = mergeRequest.MergeCommitSHA
+		// fast-forward merges do not create a merge commit
+		if len(pullRequestDto.MergeCommitHash) == 0 {
+			pullRequestDto.MergeCommitHash = mergeRequest.SquashCommitSHA
+		}
+		if len(pullRequestDto.MergeCommitHash) == 0 {
+			pullRequestDto.MergeCommitHash = mergeRequest.SHA
+		}
+		if mergeRequest.MergedAt != nil {
+			pullRequestDto.MergedOn = *mergeRequest.MergedAt
+		}
+	case "closed":
+		pullRequestDto.State = PULL_REQUEST_CLOSED
+	}
+	return pullRequestDto
+}
@@ -0,0 +1,246 @@
+package apiToken
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/authenticator/middleware"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/golang-jwt/jwt/v4"
+	"go.uber.org/zap"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ApiTokenAccessService enforces the restrictions of an api-token which are not covered by casbin roles of its user,
+// i.e. scopes, source CIDRs and revocation of rotated tokens. It also keeps track of last usage of the token.
+type ApiTokenAccessService interface {
+	casbin.ApiTokenScopeProvider
+	ValidateApiTokenAccess(token string, clientIp string) error
+	InvalidateCache()
+}
+
+const (
+	apiTokenCacheRefreshInterval = 30 * time.Second
+	apiTokenCacheMinReloadGap    = 5 * time.Second
+	apiTokenLastUsedUpdateGap    = time.Minute
+)
+
+type apiTokenAccessData struct {
+	id                        int
+	token                     string
+	previousToken             string
+	previousTokenExpireAtInMs int64
+	scopes                    []*casbin.ApiTokenScope
+	allowedCidrs              []*net.IPNet
+	lastUsedUpdatedAt         time.Time
+}
+
+type ApiTokenAccessServiceImpl struct {
+	logger             *zap.SugaredLogger
+	apiTokenRepository ApiTokenRepository
+	lock               *sync.RWMutex
+	// apiTokens is keyed by lower-cased api-token name
+	apiTokens  map[string]*apiTokenAccessData
+	loadedAt   time.Time
+	invalidate bool
+}
+
+func NewApiTokenAccessServiceImpl(logger *zap.SugaredLogger, apiTokenRepository ApiTokenRepository) *ApiTokenAccessServiceImpl {
+	return &ApiTokenAccessServiceImpl{
+		logger:             logger,
+		apiTokenRepository: apiTokenRepository,
+		lock:               &sync.RWMutex{},
+		apiTokens:          make(map[string]*apiTokenAccessData),
+	}
+}
+
+func (impl *ApiTokenAccessServiceImpl) GetApiTokenScopes(emailId string) ([]*casbin.ApiTokenScope, bool) {
+	name, isApiTokenUser := getApiTokenNameFromEmail(emailId)
+	if !isApiTokenUser {
+		return nil, false
+	}
+	accessData := impl.getAccessData(name, false)
+	if accessData == nil {
+		// token is deleted or could not be loaded, nothing is allowed
+		return nil, true
+	}
+	return accessData.scopes, len(accessData.scopes) > 0
+}
+
+func (impl *ApiTokenAccessServiceImpl) ValidateApiTokenAccess(token string, clientIp string) error {
+	claims := &ApiTokenCustomClaims{}
+	_, _, err := jwt.NewParser().ParseUnverified(token, claims)
+	if err != nil || claims.Issuer != middleware.ApiTokenClaimIssuer {
+		// not an api-token, verified by the authenticator
+		return nil
+	}
+	name, isApiTokenUser := getApiTokenNameFromEmail(claims.Email)
+	if !isApiTokenUser {
+		return nil
+	}
+	accessData := impl.getAccessData(name, false)
+	if accessData == nil || !accessData.acceptsToken(token) {
+		// token might have been created or rotated on another instance
+		accessData = impl.getAccessData(name, true)
+	}
+	if accessData == nil || !accessData.acceptsToken(token) {
+		return errors.New("api-token has been revoked or rotated")
+	}
+
+	ip := parseClientIp(clientIp)
+	if len(accessData.allowedCidrs) > 0 {
+		allowed := false
+		for _, cidr := range accessData.allowedCidrs {
+			if ip != nil && cidr.Contains(ip) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("api-token is not allowed from ip '%s'", clientIp)
+		}
+	}
+	impl.updateLastUsed(accessData, clientIp)
+	return nil
+}
+
+func (impl *ApiTokenAccessServiceImpl) InvalidateCache() {
+	impl.lock.Lock()
+	defer impl.lock.Unlock()
+	impl.invalidate = true
+}
+
+func (data *apiTokenAccessData) acceptsToken(token string) bool {
+	if data.token == token {
+		return true
+	}
+	return len(data.previousToken) > 0 && data.previousToken == token && time.Now().UnixMilli() < data.previousTokenExpireAtInMs
+}
+
+func (impl *ApiTokenAccessServiceImpl) getAccessData(name string, forceReload bool) *apiTokenAccessData {
+	impl.lock.RLock()
+	loadedAt := impl.loadedAt
+	stale := impl.invalidate || time.Since(loadedAt) > apiTokenCacheRefreshInterval || (forceReload && time.Since(loadedAt) > apiTokenCacheMinReloadGap)
+	accessData := impl.apiTokens[name]
+	impl.lock.RUnlock()
+	if !stale {
+		return accessData
+	}
+	impl.reload(loadedAt)
+	impl.lock.RLock()
+	defer impl.lock.RUnlock()
+	return impl.apiTokens[name]
+}
+
+func (impl *ApiTokenAccessServiceImpl) reload(loadedAt time.Time) {
+	impl.lock.Lock()
+	defer impl.lock.Unlock()
+	if !impl.invalidate && impl.loadedAt.After(loadedAt) {
+		// reloaded by a concurrent request
+		return
+	}
+	apiTokens, err := impl.apiTokenRepository.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error while loading active api tokens, using previously loaded data", "err", err)
+		return
+	}
+	accessDataMap := make(map[string]*apiTokenAccessData, len(apiTokens))
+	for _, apiToken := range apiTokens {
+		accessData := &apiTokenAccessData{
+			id:                        apiToken.Id,
+			token:                     apiToken.Token,
+			previousToken:             apiToken.PreviousToken,
+			previousTokenExpireAtInMs: apiToken.PreviousTokenExpireAtInMs,
+		}
+		name := strings.ToLower(apiToken.Name)
+		if existing, ok := impl.apiTokens[name]; ok && existing.id == apiToken.Id {
+			accessData.lastUsedUpdatedAt = existing.lastUsedUpdatedAt
+		}
+		accessData.scopes, err = ParseApiTokenScopes(apiToken.Scopes)
+		if err == nil {
+			accessData.allowedCidrs, err = ParseAllowedCidrs(apiToken.AllowedCidrs)
+		}
+		if err != nil {
+			// a token with corrupt restrictions must not fall back to full user access
+			impl.logger.Errorw("error in parsing api token restrictions, skipping token", "apiTokenId", apiToken.Id, "err", err)
+			continue
+		}
+		accessDataMap[name] = accessData
+	}
+	impl.apiTokens = accessDataMap
+	impl.loadedAt = time.Now()
+	impl.invalidate = false
+}
+
+func (impl *ApiTokenAccessServiceImpl) updateLastUsed(accessData *apiTokenAccessData, clientIp string) {
+	now := time.Now()
+	impl.lock.Lock()
+	if now.Sub(accessData.lastUsedUpdatedAt) < apiTokenLastUsedUpdateGap {
+		impl.lock.Unlock()
+		return
+	}
+	accessData.lastUsedUpdatedAt = now
+	impl.lock.Unlock()
+	go func() {
+		err := impl.apiTokenRepository.UpdateLastUsed(accessData.id, now, clientIp)
+		if err != nil {
+			impl.logger.Errorw("error while updating last used of api token", "apiTokenId", accessData.id, "err", err)
+		}
+	}()
+}
+
+func getApiTokenNameFromEmail(emailId string) (string, bool) {
+	if len(emailId) <= len(API_TOKEN_USER_EMAIL_PREFIX) || !strings.EqualFold(emailId[:len(API_TOKEN_USER_EMAIL_PREFIX)], API_TOKEN_USER_EMAIL_PREFIX) {
+		return "", false
+	}
+	return strings.ToLower(emailId[len(API_TOKEN_USER_EMAIL_PREFIX):]), true
+}
+
+// parseClientIp parses the ip resolved by util.GetClientIPBehindTrustedProxies, a port is tolerated
+func parseClientIp(clientIp string) net.IP {
+	clientIp = strings.TrimSpace(clientIp)
+	if host, _, err := net.SplitHostPort(clientIp); err == nil {
+		clientIp = host
+	}
+	return net.ParseIP(clientIp)
+}
+
+func ParseApiTokenScopes(scopes string) ([]*casbin.ApiTokenScope, error) {
+	if len(scopes) == 0 {
+		return nil, nil
+	}
+	var parsedScopes []*casbin.ApiTokenScope
+	err := json.Unmarshal([]byte(scopes), &parsedScopes)
+	return parsedScopes, err
+}
+
+// ParseAllowedCidrs parses comma separated CIDRs, a plain ip is treated as a single address CIDR
+func ParseAllowedCidrs(allowedCidrs string) ([]*net.IPNet, error) {
+	var cidrs []*net.IPNet
+	for _, cidr := range strings.Split(allowedCidrs, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if len(cidr) == 0 {
+			continue
+		}
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid ip '%s'", cidr)
+			}
+			if ip.To4() != nil {
+				cidr = cidr + "/32"
+			} else {
+				cidr = cidr + "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cidr '%s'", cidr)
+		}
+		cidrs = append(cidrs, ipNet)
+	}
+	return cidrs, nil
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/user/repository"
 	"github.com/go-pg/pg"
 	"github.com/go-pg/pg/orm"
+	"time"
 )
 
 type ApiToken struct {
@@ -32,7 +33,16 @@ type ApiToken struct {
 	Description  string   `sql:"description, notnull"`
 	ExpireAtInMs int64    `sql:"expire_at_in_ms"`
 	Token        string   `sql:"token, notnull"`
-	User         *repository.UserModel
+	// Scopes is the json of []*casbin.ApiTokenScope, empty means the token has all the permissions of its user
+	Scopes string `sql:"scopes"`
+	// AllowedCidrs is a comma separated list of source CIDRs the token can be used from, empty means any
+	AllowedCidrs string    `sql:"allowed_cidrs"`
+	LastUsedAt   time.Time `sql:"last_used_at"`
+	LastUsedByIp string    `sql:"last_used_by_ip"`
+	// PreviousToken stays valid till PreviousTokenExpireAtInMs after the token is rotated
+	PreviousToken             string `sql:"previous_token"`
+	PreviousTokenExpireAtInMs int64  `sql:"previous_token_expire_at_in_ms"`
+	User                      *repository.UserModel
 	sql.AuditLog
 }
 
@@ -42,6 +52,7 @@ type ApiTokenRepository interface {
 	FindAllActive() ([]*ApiToken, error)
 	FindActiveById(id int) (*ApiToken, error)
 	FindByName(name string) (*ApiToken, error)
+	UpdateLastUsed(id int, lastUsedAt time.Time, lastUsedByIp string) error
 }
 
 type ApiTokenRepositoryImpl struct {
@@ -91,3 +102,12 @@ func (impl ApiTokenRepositoryImpl) FindByName(name string) (*ApiToken, error) {
 		Select()
 	return apiToken, err
 }
+
+func (impl ApiTokenRepositoryImpl) UpdateLastUsed(id int, lastUsedAt time.Time, lastUsedByIp string) error {
+	_, err := impl.dbConnection.Model((*ApiToken)(nil)).
+		Set("last_used_at = ?", lastUsedAt).
+		Set("last_used_by_ip = ?", lastUsedByIp).
+		Where("id = ?", id).
+		Update()
+	return err
+}
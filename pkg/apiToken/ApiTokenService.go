@@ -18,15 +18,20 @@
 package apiToken
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/devtron-labs/authenticator/middleware"
 	"github.com/devtron-labs/devtron/api/bean"
 	openapi "github.com/devtron-labs/devtron/api/openapi/openapiClient"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
 	"github.com/go-pg/pg"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"regexp"
 	"strconv"
@@ -36,9 +41,10 @@ import (
 
 type ApiTokenService interface {
 	GetAllActiveApiTokens() ([]*openapi.ApiToken, error)
-	CreateApiToken(request *openapi.CreateApiTokenRequest, createdBy int32, managerAuth func(resource, token, object string) bool) (*openapi.CreateApiTokenResponse, error)
-	UpdateApiToken(apiTokenId int, request *openapi.UpdateApiTokenRequest, updatedBy int32) (*openapi.UpdateApiTokenResponse, error)
-	DeleteApiToken(apiTokenId int, deletedBy int32) (*openapi.ActionResponse, error)
+	CreateApiToken(ctx context.Context, request *openapi.CreateApiTokenRequest, createdBy int32, managerAuth func(resource, token, object string) bool) (*openapi.CreateApiTokenResponse, error)
+	UpdateApiToken(ctx context.Context, apiTokenId int, request *openapi.UpdateApiTokenRequest, updatedBy int32) (*openapi.UpdateApiTokenResponse, error)
+	DeleteApiToken(ctx context.Context, apiTokenId int, deletedBy int32) (*openapi.ActionResponse, error)
+	RotateApiToken(ctx context.Context, apiTokenId int, request *openapi.RotateApiTokenRequest, updatedBy int32) (*openapi.RotateApiTokenResponse, error)
 	GetAllApiTokensForWebhook(projectName string, environmentName string, appName string, auth func(token string, projectObject string, envObject string) bool) ([]*openapi.ApiToken, error)
 }
 
@@ -48,16 +54,20 @@ type ApiTokenServiceImpl struct {
 	userService           user.UserService
 	userAuditService      user.UserAuditService
 	apiTokenRepository    ApiTokenRepository
+	apiTokenAccessService ApiTokenAccessService
+	auditLogService       auditLog.AuditLogService
 }
 
 func NewApiTokenServiceImpl(logger *zap.SugaredLogger, apiTokenSecretService ApiTokenSecretService, userService user.UserService, userAuditService user.UserAuditService,
-	apiTokenRepository ApiTokenRepository) *ApiTokenServiceImpl {
+	apiTokenRepository ApiTokenRepository, apiTokenAccessService ApiTokenAccessService, auditLogService auditLog.AuditLogService) *ApiTokenServiceImpl {
 	return &ApiTokenServiceImpl{
 		logger:                logger,
 		apiTokenSecretService: apiTokenSecretService,
 		userService:           userService,
 		userAuditService:      userAuditService,
 		apiTokenRepository:    apiTokenRepository,
+		apiTokenAccessService: apiTokenAccessService,
+		auditLogService:       auditLogService,
 	}
 }
 
@@ -126,12 +136,6 @@ func (impl ApiTokenServiceImpl) GetAllActiveApiTokens() ([]*openapi.ApiToken, er
 	var apiTokens []*openapi.ApiToken
 	for _, apiTokenFromDb := range apiTokensFromDb {
 		userId := apiTokenFromDb.User.Id
-		latestAuditLog, err := impl.userAuditService.GetLatestByUserId(userId)
-		if err != nil {
-			impl.logger.Errorw("error while getting latest audit log", "error", err)
-			return nil, err
-		}
-
 		apiTokenIdI32 := int32(apiTokenFromDb.Id)
 		updatedAtStr := apiTokenFromDb.UpdatedOn.String()
 		apiToken := &openapi.ApiToken{
@@ -144,10 +148,27 @@ func (impl ApiTokenServiceImpl) GetAllActiveApiTokens() ([]*openapi.ApiToken, er
 			Token:          &apiTokenFromDb.Token,
 			UpdatedAt:      &updatedAtStr,
 		}
-		if latestAuditLog != nil {
-			lastUsedAtStr := latestAuditLog.CreatedOn.String()
+		if !apiTokenFromDb.LastUsedAt.IsZero() {
+			lastUsedAtStr := apiTokenFromDb.LastUsedAt.String()
 			apiToken.LastUsedAt = &lastUsedAtStr
-			apiToken.LastUsedByIp = &latestAuditLog.ClientIp
+			apiToken.LastUsedByIp = &apiTokenFromDb.LastUsedByIp
+		} else {
+			// tokens not used since usage tracking on api-token was added
+			latestAuditLog, err := impl.userAuditService.GetLatestByUserId(userId)
+			if err != nil {
+				impl.logger.Errorw("error while getting latest audit log", "error", err)
+				return nil, err
+			}
+			if latestAuditLog != nil {
+				lastUsedAtStr := latestAuditLog.CreatedOn.String()
+				apiToken.LastUsedAt = &lastUsedAtStr
+				apiToken.LastUsedByIp = &latestAuditLog.ClientIp
+			}
+		}
+		err = setApiTokenRestrictions(apiToken, apiTokenFromDb)
+		if err != nil {
+			impl.logger.Errorw("error while parsing api token restrictions", "apiTokenId", apiTokenFromDb.Id, "error", err)
+			return nil, err
 		}
 		apiTokens = append(apiTokens, apiToken)
 	}
@@ -155,7 +176,7 @@ func (impl ApiTokenServiceImpl) GetAllActiveApiTokens() ([]*openapi.ApiToken, er
 	return apiTokens, nil
 }
 
-func (impl ApiTokenServiceImpl) CreateApiToken(request *openapi.CreateApiTokenRequest, createdBy int32, managerAuth func(resource, token string, object string) bool) (*openapi.CreateApiTokenResponse, error) {
+func (impl ApiTokenServiceImpl) CreateApiToken(ctx context.Context, request *openapi.CreateApiTokenRequest, createdBy int32, managerAuth func(resource, token string, object string) bool) (*openapi.CreateApiTokenResponse, error) {
 	impl.logger.Infow("Creating API token", "request", request, "createdBy", createdBy)
 
 	name := request.GetName()
@@ -180,6 +201,11 @@ func (impl ApiTokenServiceImpl) CreateApiToken(request *openapi.CreateApiTokenRe
 
 	impl.logger.Info(fmt.Sprintf("apiTokenExists : %s", strconv.FormatBool(apiTokenExists)))
 
+	scopes, allowedCidrs, err := getApiTokenRestrictions(request.GetScopes(), request.GetAllowedCidrs())
+	if err != nil {
+		return nil, err
+	}
+
 	// step-2 - Build email
 	email := fmt.Sprintf("%s%s", API_TOKEN_USER_EMAIL_PREFIX, name)
 
@@ -195,7 +221,7 @@ func (impl ApiTokenServiceImpl) CreateApiToken(request *openapi.CreateApiTokenRe
 		EmailId:  email,
 		UserType: bean.USER_TYPE_API_TOKEN,
 	}
-	createUserResponse, err := impl.userService.CreateUser(&createUserRequest, token, managerAuth)
+	createUserResponse, err := impl.userService.CreateUser(ctx, &createUserRequest, token, managerAuth)
 	if err != nil {
 		impl.logger.Errorw("error while creating user for api-token", "email", email, "error", err)
 		return nil, err
@@ -213,6 +239,8 @@ func (impl ApiTokenServiceImpl) CreateApiToken(request *openapi.CreateApiTokenRe
 		Description:  *request.Description,
 		ExpireAtInMs: *request.ExpireAtInMs,
 		Token:        token,
+		Scopes:       scopes,
+		AllowedCidrs: allowedCidrs,
 		AuditLog:     sql.AuditLog{UpdatedOn: time.Now()},
 	}
 	if apiTokenExists {
@@ -230,6 +258,8 @@ func (impl ApiTokenServiceImpl) CreateApiToken(request *openapi.CreateApiTokenRe
 		impl.logger.Errorw("error while saving api-token into DB", "error", err)
 		return nil, err
 	}
+	impl.apiTokenAccessService.InvalidateCache()
+	impl.recordAuditEvent(ctx, auditLog.ActionCreate, createdBy, nil, apiTokenSaveRequest)
 
 	success := true
 	return &openapi.CreateApiTokenResponse{
@@ -240,7 +270,7 @@ func (impl ApiTokenServiceImpl) CreateApiToken(request *openapi.CreateApiTokenRe
 	}, nil
 }
 
-func (impl ApiTokenServiceImpl) UpdateApiToken(apiTokenId int, request *openapi.UpdateApiTokenRequest, updatedBy int32) (*openapi.UpdateApiTokenResponse, error) {
+func (impl ApiTokenServiceImpl) UpdateApiToken(ctx context.Context, apiTokenId int, request *openapi.UpdateApiTokenRequest, updatedBy int32) (*openapi.UpdateApiTokenResponse, error) {
 	impl.logger.Infow("Updating API token", "request", request, "updatedBy", updatedBy, "apiTokenId", apiTokenId)
 
 	// step-1 - check if the api-token exists, if not exists - throw error
@@ -253,7 +283,14 @@ func (impl ApiTokenServiceImpl) UpdateApiToken(apiTokenId int, request *openapi.
 		return nil, errors.New(fmt.Sprintf("api-token corresponds to apiTokenId '%d' is not found", apiTokenId))
 	}
 
-	// step-2 - If expires_at is not same, then token needs to be generated again
+	// step-2 - validate restrictions, scopes and CIDRs which are not part of the request are kept as they are
+	scopes, allowedCidrs, err := getApiTokenRestrictions(request.GetScopes(), request.GetAllowedCidrs())
+	if err != nil {
+		impl.logger.Errorw("invalid api-token restrictions", "apiTokenId", apiTokenId, "err", err)
+		return nil, err
+	}
+
+	// step-3 - If expires_at is not same, then token needs to be generated again
 	if *request.ExpireAtInMs != apiToken.ExpireAtInMs {
 		// regenerate token
 		token, err := impl.createApiJwtToken(apiToken.User.EmailId, *request.ExpireAtInMs)
@@ -263,9 +300,16 @@ func (impl ApiTokenServiceImpl) UpdateApiToken(apiTokenId int, request *openapi.
 		apiToken.Token = token
 	}
 
-	// step-3 - update in DB
+	// step-4 - update in DB
+	existingAuditState := getApiTokenAuditState(apiToken)
 	apiToken.Description = *request.Description
 	apiToken.ExpireAtInMs = *request.ExpireAtInMs
+	if request.HasScopes() {
+		apiToken.Scopes = scopes
+	}
+	if request.HasAllowedCidrs() {
+		apiToken.AllowedCidrs = allowedCidrs
+	}
 	apiToken.UpdatedBy = updatedBy
 	apiToken.UpdatedOn = time.Now()
 	err = impl.apiTokenRepository.Update(apiToken)
@@ -273,6 +317,8 @@ func (impl ApiTokenServiceImpl) UpdateApiToken(apiTokenId int, request *openapi.
 		impl.logger.Errorw("error while updating api-token", "apiTokenId", apiTokenId, "error", err)
 		return nil, err
 	}
+	impl.apiTokenAccessService.InvalidateCache()
+	impl.recordAuditEvent(ctx, auditLog.ActionUpdate, updatedBy, existingAuditState, apiToken)
 
 	success := true
 	return &openapi.UpdateApiTokenResponse{
@@ -281,7 +327,7 @@ func (impl ApiTokenServiceImpl) UpdateApiToken(apiTokenId int, request *openapi.
 	}, nil
 }
 
-func (impl ApiTokenServiceImpl) DeleteApiToken(apiTokenId int, deletedBy int32) (*openapi.ActionResponse, error) {
+func (impl ApiTokenServiceImpl) DeleteApiToken(ctx context.Context, apiTokenId int, deletedBy int32) (*openapi.ActionResponse, error) {
 	impl.logger.Infow("Deleting API token", "deletedBy", deletedBy, "apiTokenId", apiTokenId)
 
 	// step-1 - check if the api-token exists, if not exists - throw error
@@ -294,6 +340,7 @@ func (impl ApiTokenServiceImpl) DeleteApiToken(apiTokenId int, deletedBy int32)
 		return nil, errors.New(fmt.Sprintf("api-token corresponds to apiTokenId '%d' is not found", apiTokenId))
 	}
 
+	existingAuditState := getApiTokenAuditState(apiToken)
 	apiToken.ExpireAtInMs = time.Now().UnixMilli()
 	err = impl.apiTokenRepository.Update(apiToken)
 	if err != nil && err != pg.ErrNoRows {
@@ -306,7 +353,7 @@ func (impl ApiTokenServiceImpl) DeleteApiToken(apiTokenId int, deletedBy int32)
 		Id:     apiToken.UserId,
 		UserId: deletedBy,
 	}
-	success, err := impl.userService.DeleteUser(&deleteUserRequest)
+	success, err := impl.userService.DeleteUser(ctx, &deleteUserRequest)
 	if err != nil {
 		impl.logger.Errorw("error while inactivating user for", "apiTokenId", apiTokenId, "userId", apiToken.UserId, "error", err)
 		return nil, err
@@ -314,6 +361,15 @@ func (impl ApiTokenServiceImpl) DeleteApiToken(apiTokenId int, deletedBy int32)
 	if !success {
 		return nil, errors.New(fmt.Sprintf("Couldn't in-activate user corresponds to apiTokenId '%d'", apiTokenId))
 	}
+	impl.apiTokenAccessService.InvalidateCache()
+	impl.auditLogService.RecordEvent(ctx, &auditLog.AuditEventRequest{
+		Action:       auditLog.ActionDelete,
+		ResourceType: auditLog.ResourceTypeApiToken,
+		ResourceId:   strconv.Itoa(apiToken.Id),
+		ResourceName: apiToken.Name,
+		UserId:       deletedBy,
+		Before:       existingAuditState,
+	})
 
 	return &openapi.ActionResponse{
 		Success: &success,
@@ -321,6 +377,133 @@ func (impl ApiTokenServiceImpl) DeleteApiToken(apiTokenId int, deletedBy int32)
 
 }
 
+func (impl ApiTokenServiceImpl) RotateApiToken(ctx context.Context, apiTokenId int, request *openapi.RotateApiTokenRequest, updatedBy int32) (*openapi.RotateApiTokenResponse, error) {
+	impl.logger.Infow("Rotating API token", "request", request, "updatedBy", updatedBy, "apiTokenId", apiTokenId)
+
+	gracePeriodInSecs := request.GetGracePeriodInSecs()
+	if gracePeriodInSecs < 0 {
+		return nil, errors.New("gracePeriodInSecs cannot be negative")
+	}
+
+	// step-1 - check if the api-token exists, if not exists - throw error
+	apiToken, err := impl.apiTokenRepository.FindActiveById(apiTokenId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while getting api token by id", "apiTokenId", apiTokenId, "error", err)
+		return nil, err
+	}
+	if apiToken == nil || apiToken.Id == 0 {
+		return nil, errors.New(fmt.Sprintf("api-token corresponds to apiTokenId '%d' is not found", apiTokenId))
+	}
+
+	existingAuditState := getApiTokenAuditState(apiToken)
+
+	// step-2 - issue new token, current token stays valid till the grace period ends
+	token, err := impl.createApiJwtToken(apiToken.User.EmailId, apiToken.ExpireAtInMs)
+	if err != nil {
+		return nil, err
+	}
+	previousTokenExpireAtInMs := time.Now().Add(time.Duration(gracePeriodInSecs) * time.Second).UnixMilli()
+	if apiToken.ExpireAtInMs > 0 && apiToken.ExpireAtInMs < previousTokenExpireAtInMs {
+		previousTokenExpireAtInMs = apiToken.ExpireAtInMs
+	}
+	apiToken.PreviousToken = apiToken.Token
+	apiToken.PreviousTokenExpireAtInMs = previousTokenExpireAtInMs
+	apiToken.Token = token
+
+	// step-3 - update in DB
+	apiToken.UpdatedBy = updatedBy
+	apiToken.UpdatedOn = time.Now()
+	err = impl.apiTokenRepository.Update(apiToken)
+	if err != nil {
+		impl.logger.Errorw("error while rotating api-token", "apiTokenId", apiTokenId, "error", err)
+		return nil, err
+	}
+	impl.apiTokenAccessService.InvalidateCache()
+	impl.recordAuditEvent(ctx, auditLog.ActionRotate, updatedBy, existingAuditState, apiToken)
+
+	success := true
+	return &openapi.RotateApiTokenResponse{
+		Success:                   &success,
+		Token:                     &token,
+		PreviousTokenExpireAtInMs: &previousTokenExpireAtInMs,
+	}, nil
+}
+
+func (impl ApiTokenServiceImpl) recordAuditEvent(ctx context.Context, action string, userId int32, before map[string]interface{}, apiToken *ApiToken) {
+	impl.auditLogService.RecordEvent(ctx, &auditLog.AuditEventRequest{
+		Action:       action,
+		ResourceType: auditLog.ResourceTypeApiToken,
+		ResourceId:   strconv.Itoa(apiToken.Id),
+		ResourceName: apiToken.Name,
+		UserId:       userId,
+		Before:       before,
+		After:        getApiTokenAuditState(apiToken),
+	})
+}
+
+// getApiTokenAuditState is the state of api-token recorded in audit log, token values are left out
+func getApiTokenAuditState(apiToken *ApiToken) map[string]interface{} {
+	return map[string]interface{}{
+		"name":                      apiToken.Name,
+		"description":               apiToken.Description,
+		"expireAtInMs":              apiToken.ExpireAtInMs,
+		"scopes":                    apiToken.Scopes,
+		"allowedCidrs":              apiToken.AllowedCidrs,
+		"previousTokenExpireAtInMs": apiToken.PreviousTokenExpireAtInMs,
+	}
+}
+
+// getApiTokenRestrictions validates scopes and allowed CIDRs of a request and returns them in the form stored in DB
+func getApiTokenRestrictions(requestScopes []openapi.ApiTokenScope, requestAllowedCidrs []string) (string, string, error) {
+	var scopes string
+	if len(requestScopes) > 0 {
+		apiTokenScopes := make([]*casbin.ApiTokenScope, 0, len(requestScopes))
+		for _, requestScope := range requestScopes {
+			scope := &casbin.ApiTokenScope{
+				Resource: requestScope.GetResource(),
+				Actions:  requestScope.GetActions(),
+				Object:   requestScope.GetObject(),
+			}
+			if len(scope.Resource) == 0 || len(scope.Actions) == 0 {
+				return "", "", errors.New("resource and actions are required in api-token scope")
+			}
+			apiTokenScopes = append(apiTokenScopes, scope)
+		}
+		scopesJson, err := json.Marshal(apiTokenScopes)
+		if err != nil {
+			return "", "", err
+		}
+		scopes = string(scopesJson)
+	}
+	allowedCidrs := strings.Join(requestAllowedCidrs, ",")
+	if _, err := ParseAllowedCidrs(allowedCidrs); err != nil {
+		return "", "", err
+	}
+	return scopes, allowedCidrs, nil
+}
+
+func setApiTokenRestrictions(apiToken *openapi.ApiToken, apiTokenFromDb *ApiToken) error {
+	scopes, err := ParseApiTokenScopes(apiTokenFromDb.Scopes)
+	if err != nil {
+		return err
+	}
+	if len(scopes) > 0 {
+		apiTokenScopes := make([]openapi.ApiTokenScope, 0, len(scopes))
+		for _, scope := range scopes {
+			apiTokenScope := openapi.ApiTokenScope{}
+			apiTokenScope.SetResource(scope.Resource)
+			apiTokenScope.SetActions(scope.Actions)
+			apiTokenScope.SetObject(scope.Object)
+			apiTokenScopes = append(apiTokenScopes, apiTokenScope)
+		}
+		apiToken.SetScopes(apiTokenScopes)
+	}
+	if len(apiTokenFromDb.AllowedCidrs) > 0 {
+		apiToken.SetAllowedCidrs(strings.Split(apiTokenFromDb.AllowedCidrs, ","))
+	}
+	return nil
+}
+
 func (impl ApiTokenServiceImpl) createApiJwtToken(email string, expireAtInMs int64) (string, error) {
 	secretByteArr, err := impl.apiTokenSecretService.GetApiTokenSecretByteArr()
 	if err != nil {
@@ -328,8 +511,11 @@ func (impl ApiTokenServiceImpl) createApiJwtToken(email string, expireAtInMs int
 		return "", err
 	}
 
+	// jti makes every issued token unique, even for same email and expiry (e.g. on rotation)
 	registeredClaims := jwt.RegisteredClaims{
-		Issuer: middleware.ApiTokenClaimIssuer,
+		Issuer:   middleware.ApiTokenClaimIssuer,
+		IssuedAt: jwt.NewNumericDate(time.Now()),
+		ID:       uuid.New().String(),
 	}
 	if expireAtInMs > 0 {
 		registeredClaims.ExpiresAt = jwt.NewNumericDate(time.Unix(expireAtInMs/1000, 0))
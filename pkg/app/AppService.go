@@ -201,6 +201,7 @@ type AppService interface {
 	CreateGitopsRepo(app *app.App, userId int32) (gitopsRepoName string, chartGitAttr *ChartGitAttribute, err error)
 	GetDeployedManifestByPipelineIdAndCDWorkflowId(appId int, envId int, cdWorkflowId int, ctx context.Context) ([]byte, error)
 	SetPipelineFieldsInOverrideRequest(overrideRequest *bean.ValuesOverrideRequest, pipeline *pipelineConfig.Pipeline)
+	DeployArgocdAppForPipelineOverride(pipelineOverrideId int, userId int32) error
 }
 
 func NewAppService(
@@ -377,7 +378,7 @@ func (impl *AppServiceImpl) createArgoApplicationIfRequired(appId int, envConfig
 			TargetServer:    envModel.Cluster.ServerUrl,
 			Project:         "default",
 			ValuesFile:      impl.getValuesFileForEnv(envModel.Id),
-			RepoPath:        chart.ChartLocation,
+			RepoPath:        GetEnvChartLocation(chart.ChartLocation, chart.GitRepoDirectory, envModel.Name),
 			RepoUrl:         chart.GitRepoUrl,
 		}
 
@@ -441,6 +442,14 @@ func (impl *AppServiceImpl) UpdateDeploymentStatusAndCheckIsSucceeded(app *v1alp
 			impl.logger.Errorw("no git repo found for url", "repoUrl", repoUrl)
 			return isSucceeded, pipelineOverride, fmt.Errorf("no git repo found for url %s", repoUrl)
 		}
+		if len(chart.GitRepoDirectory) > 0 {
+			// repo is shared by apps, app is identified by the directory its environment's chart is in
+			chart, err = impl.chartRepository.FindChartByGitRepoUrlAndPath(repoUrl, app.Spec.Source.Path)
+			if err != nil {
+				impl.logger.Errorw("error in fetching chart", "repoUrl", repoUrl, "path", app.Spec.Source.Path, "err", err)
+				return isSucceeded, pipelineOverride, err
+			}
+		}
 		envId, err := impl.appRepository.FindEnvironmentIdForInstalledApp(chart.AppId)
 		if err != nil {
 			impl.logger.Errorw("error in fetching app", "err", err, "app", chart.AppId)
@@ -679,6 +688,10 @@ func (impl *AppServiceImpl) CheckIfPipelineUpdateEventIsValid(argoAppName, gitHa
 		impl.logger.Errorw("error in getting latest pipelineOverride by appId and envId", "err", err, "appId", pipeline.AppId, "envId", pipeline.EnvironmentId)
 		return isValid, pipeline, cdWfr, pipelineOverride, err
 	}
+	if pipelineOverride.GitHash == "" {
+		//latest release is not committed on the deployed branch yet, e.g. its gitops pull request is not merged, dropping event
+		return isValid, pipeline, cdWfr, pipelineOverride, nil
+	}
 	if gitHash != "" && pipelineOverride.GitHash != gitHash {
 		pipelineOverrideByHash, err := impl.pipelineOverrideRepository.FindByPipelineTriggerGitHash(gitHash)
 		if err != nil {
@@ -1701,8 +1714,24 @@ func (impl *AppServiceImpl) CreateGitopsRepo(app *app.App, userId int32) (gitops
 	if err != nil && pg.ErrNoRows != err {
 		return "", nil, err
 	}
-	gitOpsRepoName := impl.chartTemplateService.GetGitOpsRepoName(app.AppName)
-	chartGitAttr, err = impl.chartTemplateService.CreateGitRepositoryForApp(gitOpsRepoName, chart.ReferenceTemplate, chart.ChartVersion, userId)
+	var gitOpsRepoName, gitRepoDirectory string
+	if len(chart.GitRepoUrl) > 0 {
+		// app keeps the repository it is already committed to, it is moved to a shared repository only by migration
+		gitOpsRepoName = impl.chartTemplateService.GetGitOpsRepoNameFromUrl(chart.GitRepoUrl)
+		gitRepoDirectory = chart.GitRepoDirectory
+	} else {
+		teamName := ""
+		if impl.chartTemplateService.IsGitOpsMonorepoLayout() {
+			appWithTeam, err := impl.appRepository.FindAppAndProjectByAppId(app.Id)
+			if err != nil {
+				impl.logger.Errorw("error in getting app with team", "appId", app.Id, "err", err)
+				return "", nil, err
+			}
+			teamName = appWithTeam.Team.Name
+		}
+		gitOpsRepoName, gitRepoDirectory = impl.chartTemplateService.GetGitOpsRepoNameAndDirectory(app.AppName, teamName)
+	}
+	chartGitAttr, err = impl.chartTemplateService.CreateGitRepositoryForApp(gitOpsRepoName, gitRepoDirectory, chart.ReferenceTemplate, chart.ChartVersion, userId)
 	if err != nil {
 		impl.logger.Errorw("error in pushing chart to git ", "gitOpsRepoName", gitOpsRepoName, "err", err)
 		return "", nil, err
@@ -1737,6 +1766,37 @@ func (impl *AppServiceImpl) DeployArgocdApp(overrideRequest *bean.ValuesOverride
 	return nil
 }
 
+// DeployArgocdAppForPipelineOverride deploys a release whose gitops commit reached the deployed branch after its trigger,
+// as for environments where gitops commits are merged through pull requests
+func (impl *AppServiceImpl) DeployArgocdAppForPipelineOverride(pipelineOverrideId int, userId int32) error {
+	pipelineOverride, err := impl.pipelineOverrideRepository.FindById(pipelineOverrideId)
+	if err != nil {
+		impl.logger.Errorw("error in getting pipeline override", "err", err, "pipelineOverrideId", pipelineOverrideId)
+		return err
+	}
+	envOverride, err := impl.environmentConfigRepository.Get(pipelineOverride.EnvConfigOverrideId)
+	if err != nil {
+		impl.logger.Errorw("error in getting env config override", "err", err, "envConfigOverrideId", pipelineOverride.EnvConfigOverrideId)
+		return err
+	}
+	ctx, err := impl.buildACDContext()
+	if err != nil {
+		return err
+	}
+	overrideRequest := &bean.ValuesOverrideRequest{
+		PipelineId:        pipelineOverride.PipelineId,
+		AppId:             pipelineOverride.Pipeline.AppId,
+		UserId:            userId,
+		DeploymentAppType: pipelineOverride.Pipeline.DeploymentAppType,
+	}
+	valuesOverrideResponse := &ValuesOverrideResponse{
+		Pipeline:         pipelineOverride.Pipeline,
+		EnvOverride:      envOverride,
+		PipelineOverride: pipelineOverride,
+	}
+	return impl.DeployArgocdApp(overrideRequest, valuesOverrideResponse, ctx)
+}
+
 func (impl *AppServiceImpl) DeployApp(overrideRequest *bean.ValuesOverrideRequest, valuesOverrideResponse *ValuesOverrideResponse, triggeredAt time.Time, ctx context.Context) error {
 
 	if IsAcdApp(overrideRequest.DeploymentAppType) {
@@ -1827,6 +1887,10 @@ func (impl *AppServiceImpl) TriggerPipeline(overrideRequest *bean.ValuesOverride
 		_, span := otel.Tracer("orchestrator").Start(ctx, "pipelineOverrideRepository.Update")
 		err = impl.pipelineOverrideRepository.Update(pipelineOverrideUpdateRequest)
 		span.End()
+		if manifestPushResponse.IsPullRequestOpen {
+			// deployment proceeds once the pull request is merged, see DeployArgocdAppForPipelineOverride
+			triggerEvent.PerformDeploymentOnCluster = false
+		}
 	}
 
 	if triggerEvent.PerformDeploymentOnCluster {
@@ -1899,6 +1963,7 @@ func (impl *AppServiceImpl) BuildManifestPushTemplate(overrideRequest *bean.Valu
 		PipelineOverrideId:    valuesOverrideResponse.PipelineOverride.Id,
 		AppName:               overrideRequest.AppName,
 		TargetEnvironmentName: valuesOverrideResponse.EnvOverride.TargetEnvironment,
+		EnvironmentName:       valuesOverrideResponse.EnvOverride.Environment.Name,
 		BuiltChartPath:        builtChartPath,
 		BuiltChartBytes:       manifest,
 		MergedValues:          valuesOverrideResponse.MergedValues,
@@ -1919,8 +1984,9 @@ func (impl *AppServiceImpl) BuildManifestPushTemplate(overrideRequest *bean.Valu
 		manifestPushTemplate.ChartReferenceTemplate = valuesOverrideResponse.EnvOverride.Chart.ReferenceTemplate
 		manifestPushTemplate.ChartName = valuesOverrideResponse.EnvOverride.Chart.ChartName
 		manifestPushTemplate.ChartVersion = valuesOverrideResponse.EnvOverride.Chart.ChartVersion
-		manifestPushTemplate.ChartLocation = valuesOverrideResponse.EnvOverride.Chart.ChartLocation
+		manifestPushTemplate.ChartLocation = GetEnvChartLocation(valuesOverrideResponse.EnvOverride.Chart.ChartLocation, valuesOverrideResponse.EnvOverride.Chart.GitRepoDirectory, valuesOverrideResponse.EnvOverride.Environment.Name)
 		manifestPushTemplate.RepoUrl = valuesOverrideResponse.EnvOverride.Chart.GitRepoUrl
+		manifestPushTemplate.GitOpsPullRequestEnabled = valuesOverrideResponse.EnvOverride.Environment.GitOpsPullRequestEnabled
 	}
 	return manifestPushTemplate, err
 }
@@ -1972,7 +2038,7 @@ func (impl *AppServiceImpl) autoHealChartLocationInChart(ctx context.Context, en
 	}
 
 	// build new chart location
-	newChartLocation := filepath.Join(chartRef.Location, envOverride.Chart.ChartVersion)
+	newChartLocation := filepath.Join(chart.GitRepoDirectory, chartRef.Location, envOverride.Chart.ChartVersion)
 	impl.logger.Infow("new chart location build", "chartId", chartId, "newChartLocation", newChartLocation)
 
 	// update chart in DB
@@ -2553,7 +2619,7 @@ func (impl *AppServiceImpl) mergeAndSave(envOverride *chartConfig.EnvConfigOverr
 			FileName:       fmt.Sprintf("_%d-values.yaml", envOverride.TargetEnvironment),
 			FileContent:    string(merged),
 			ChartName:      envOverride.Chart.ChartName,
-			ChartLocation:  envOverride.Chart.ChartLocation,
+			ChartLocation:  GetEnvChartLocation(envOverride.Chart.ChartLocation, envOverride.Chart.GitRepoDirectory, envOverride.Environment.Name),
 			ChartRepoName:  chartRepoName,
 			ReleaseMessage: fmt.Sprintf("release-%d-env-%d ", override.Id, envOverride.TargetEnvironment),
 			UserName:       userName,
@@ -2687,8 +2753,9 @@ func (impl *AppServiceImpl) updateArgoPipeline(appId int, pipelineName string, e
 
 	if appStatus.Code() == codes.OK {
 		impl.logger.Debugw("argo app exists", "app", argoAppName, "pipeline", pipelineName)
-		if application.Spec.Source.Path != envOverride.Chart.ChartLocation || application.Spec.Source.TargetRevision != "master" {
-			patchReq := v1alpha1.Application{Spec: v1alpha1.ApplicationSpec{Source: v1alpha1.ApplicationSource{Path: envOverride.Chart.ChartLocation, RepoURL: envOverride.Chart.GitRepoUrl, TargetRevision: "master"}}}
+		chartLocation := GetEnvChartLocation(envOverride.Chart.ChartLocation, envOverride.Chart.GitRepoDirectory, envModel.Name)
+		if application.Spec.Source.Path != chartLocation || application.Spec.Source.RepoURL != envOverride.Chart.GitRepoUrl || application.Spec.Source.TargetRevision != "master" {
+			patchReq := v1alpha1.Application{Spec: v1alpha1.ApplicationSpec{Source: v1alpha1.ApplicationSource{Path: chartLocation, RepoURL: envOverride.Chart.GitRepoUrl, TargetRevision: "master"}}}
 			reqbyte, err := json.Marshal(patchReq)
 			if err != nil {
 				impl.logger.Errorw("error in creating patch", "err", err)
@@ -0,0 +1,456 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	application2 "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/devtron-labs/devtron/client/argocdServer/application"
+	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/app/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/util/argo"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sigs.k8s.io/yaml"
+	"time"
+)
+
+type GitOpsDriftResource struct {
+	Group     string `json:"group,omitempty"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+type GitOpsDriftReportDto struct {
+	PipelineId          int                    `json:"pipelineId"`
+	AppId               int                    `json:"appId"`
+	EnvironmentId       int                    `json:"environmentId"`
+	PipelineOverrideId  int                    `json:"pipelineOverrideId"`
+	DevtronCommitHash   string                 `json:"devtronCommitHash"`
+	GitHeadCommitHash   string                 `json:"gitHeadCommitHash,omitempty"`
+	GitHeadCommitAuthor string                 `json:"gitHeadCommitAuthor,omitempty"`
+	GitHeadCommitTime   *time.Time             `json:"gitHeadCommitTime,omitempty"`
+	GitDriftStatus      string                 `json:"gitDriftStatus"`
+	LiveDriftStatus     string                 `json:"liveDriftStatus"`
+	LiveSyncRevision    string                 `json:"liveSyncRevision,omitempty"`
+	OutOfSyncResources  []*GitOpsDriftResource `json:"outOfSyncResources"`
+	Message             string                 `json:"message,omitempty"`
+	DetectedOn          time.Time              `json:"detectedOn"`
+}
+
+type GitOpsDriftService interface {
+	// DetectDriftForAllPipelines checks every gitops pipeline for drift, used by the drift detection cron
+	DetectDriftForAllPipelines()
+	DetectDrift(appId, envId int) (*GitOpsDriftReportDto, error)
+	GetDriftReports(appId int) ([]*GitOpsDriftReportDto, error)
+	GetDriftReport(appId, envId int) (*GitOpsDriftReportDto, error)
+	// ReassertDevtronState commits the values of the last devtron release again and syncs the argo cd application
+	ReassertDevtronState(appId, envId int, userId int32) (*GitOpsDriftReportDto, error)
+}
+
+type GitOpsDriftServiceImpl struct {
+	logger                      *zap.SugaredLogger
+	pipelineRepository          pipelineConfig.PipelineRepository
+	pipelineOverrideRepository  chartConfig.PipelineOverrideRepository
+	environmentConfigRepository chartConfig.EnvConfigOverrideRepository
+	gitOpsDriftReportRepository pipelineConfig.GitOpsDriftReportRepository
+	gitFactory                  *util.GitFactory
+	chartTemplateService        util.ChartTemplateService
+	gitOpsPushService           GitOpsPushService
+	acdClient                   application.ServiceClient
+	argoUserService             argo.ArgoUserService
+}
+
+func NewGitOpsDriftServiceImpl(logger *zap.SugaredLogger, pipelineRepository pipelineConfig.PipelineRepository,
+	pipelineOverrideRepository chartConfig.PipelineOverrideRepository,
+	environmentConfigRepository chartConfig.EnvConfigOverrideRepository,
+	gitOpsDriftReportRepository pipelineConfig.GitOpsDriftReportRepository, gitFactory *util.GitFactory,
+	chartTemplateService util.ChartTemplateService, gitOpsPushService GitOpsPushService,
+	acdClient application.ServiceClient, argoUserService argo.ArgoUserService) *GitOpsDriftServiceImpl {
+	return &GitOpsDriftServiceImpl{
+		logger:                      logger,
+		pipelineRepository:          pipelineRepository,
+		pipelineOverrideRepository:  pipelineOverrideRepository,
+		environmentConfigRepository: environmentConfigRepository,
+		gitOpsDriftReportRepository: gitOpsDriftReportRepository,
+		gitFactory:                  gitFactory,
+		chartTemplateService:        chartTemplateService,
+		gitOpsPushService:           gitOpsPushService,
+		acdClient:                   acdClient,
+		argoUserService:             argoUserService,
+	}
+}
+
+func (impl *GitOpsDriftServiceImpl) DetectDriftForAllPipelines() {
+	pipelines, err := impl.pipelineRepository.FindActiveByAppIdAndEnvironmentIdV2()
+	if err != nil {
+		impl.logger.Errorw("error in getting pipelines for drift detection", "err", err)
+		return
+	}
+	ctx, err := impl.getArgoContext()
+	if err != nil {
+		return
+	}
+	// repositories are cloned once per cycle as apps of a monorepo share them
+	clonedRepos := make(map[string]string)
+	defer func() {
+		for _, clonedDir := range clonedRepos {
+			impl.chartTemplateService.CleanDir(clonedDir)
+		}
+	}()
+	for _, pipeline := range pipelines {
+		if pipeline.DeploymentAppType != util.PIPELINE_DEPLOYMENT_TYPE_ACD || !pipeline.DeploymentAppCreated {
+			continue
+		}
+		_, err = impl.detectDrift(ctx, pipeline, clonedRepos)
+		if _, ok := err.(*util.ApiError); ok {
+			// pipeline has not been released through gitops yet
+			continue
+		} else if err != nil {
+			impl.logger.Errorw("error in detecting gitops drift", "pipelineId", pipeline.Id, "err", err)
+		}
+	}
+}
+
+func (impl *GitOpsDriftServiceImpl) DetectDrift(appId, envId int) (*GitOpsDriftReportDto, error) {
+	pipeline, err := impl.getGitOpsPipeline(appId, envId)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err := impl.getArgoContext()
+	if err != nil {
+		return nil, err
+	}
+	clonedRepos := make(map[string]string)
+	defer func() {
+		for _, clonedDir := range clonedRepos {
+			impl.chartTemplateService.CleanDir(clonedDir)
+		}
+	}()
+	report, err := impl.detectDrift(ctx, pipeline, clonedRepos)
+	if err != nil {
+		return nil, err
+	}
+	return adaptGitOpsDriftReport(report), nil
+}
+
+func (impl *GitOpsDriftServiceImpl) GetDriftReports(appId int) ([]*GitOpsDriftReportDto, error) {
+	reports, err := impl.gitOpsDriftReportRepository.FindByAppId(appId)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*GitOpsDriftReportDto, 0, len(reports))
+	for _, report := range reports {
+		dtos = append(dtos, adaptGitOpsDriftReport(report))
+	}
+	return dtos, nil
+}
+
+func (impl *GitOpsDriftServiceImpl) GetDriftReport(appId, envId int) (*GitOpsDriftReportDto, error) {
+	pipeline, err := impl.getGitOpsPipeline(appId, envId)
+	if err != nil {
+		return nil, err
+	}
+	report, err := impl.gitOpsDriftReportRepository.FindByPipelineId(pipeline.Id)
+	if err == pg.ErrNoRows {
+		return nil, &util.ApiError{HttpStatusCode: 404, UserMessage: "drift has not been checked for this pipeline yet", InternalMessage: err.Error()}
+	} else if err != nil {
+		impl.logger.Errorw("error in getting gitops drift report", "pipelineId", pipeline.Id, "err", err)
+		return nil, err
+	}
+	return adaptGitOpsDriftReport(report), nil
+}
+
+func (impl *GitOpsDriftServiceImpl) ReassertDevtronState(appId, envId int, userId int32) (*GitOpsDriftReportDto, error) {
+	pipeline, err := impl.getGitOpsPipeline(appId, envId)
+	if err != nil {
+		return nil, err
+	}
+	override, err := impl.pipelineOverrideRepository.FindLatestCommittedByPipelineId(pipeline.Id)
+	if err == pg.ErrNoRows {
+		return nil, &util.ApiError{HttpStatusCode: 400, UserMessage: "pipeline has no release committed to gitops repo", InternalMessage: err.Error()}
+	} else if err != nil {
+		impl.logger.Errorw("error in getting latest committed pipeline override", "pipelineId", pipeline.Id, "err", err)
+		return nil, err
+	}
+	ctx, err := impl.getArgoContext()
+	if err != nil {
+		return nil, err
+	}
+	clonedRepos := make(map[string]string)
+	defer func() {
+		for _, clonedDir := range clonedRepos {
+			impl.chartTemplateService.CleanDir(clonedDir)
+		}
+	}()
+	report, err := impl.detectDrift(ctx, pipeline, clonedRepos)
+	if err != nil {
+		return nil, err
+	}
+	if report.GitDriftStatus == pipelineConfig.GITOPS_DRIFT_STATUS_DRIFTED {
+		if pipeline.Environment.GitOpsPullRequestEnabled {
+			return nil, &util.ApiError{
+				HttpStatusCode:  400,
+				UserMessage:     "gitops commits of this environment need a pull request, re-deploy the pipeline to restore devtron's values",
+				InternalMessage: "gitops pull request enabled for environment",
+			}
+		}
+		envOverride, err := impl.environmentConfigRepository.Get(override.EnvConfigOverrideId)
+		if err != nil {
+			impl.logger.Errorw("error in getting env config override", "id", override.EnvConfigOverrideId, "err", err)
+			return nil, err
+		}
+		manifestPushTemplate := &bean.ManifestPushTemplate{
+			RepoUrl:               envOverride.Chart.GitRepoUrl,
+			TargetEnvironmentName: pipeline.EnvironmentId,
+			MergedValues:          override.PipelineMergedValues,
+			ChartName:             envOverride.Chart.ChartName,
+			ChartLocation:         util.GetEnvChartLocation(envOverride.Chart.ChartLocation, envOverride.Chart.GitRepoDirectory, pipeline.Environment.Name),
+			PipelineOverrideId:    override.Id,
+			UserId:                userId,
+		}
+		_, _, err = impl.gitOpsPushService.CommitValuesToGit(manifestPushTemplate, ctx)
+		if err != nil {
+			impl.logger.Errorw("error in committing devtron values to git", "pipelineId", pipeline.Id, "err", err)
+			return nil, err
+		}
+	}
+	argoAppName := pipeline.DeploymentAppName
+	prune := true
+	_, err = impl.acdClient.Sync(ctx, &application2.ApplicationSyncRequest{Name: &argoAppName, Prune: &prune})
+	if err != nil {
+		impl.logger.Errorw("error in syncing argo application", "argoAppName", argoAppName, "err", err)
+		return nil, err
+	}
+	// the repo has changed, clones of this request are stale
+	for repoUrl, clonedDir := range clonedRepos {
+		impl.chartTemplateService.CleanDir(clonedDir)
+		delete(clonedRepos, repoUrl)
+	}
+	report, err = impl.detectDrift(ctx, pipeline, clonedRepos)
+	if err != nil {
+		return nil, err
+	}
+	return adaptGitOpsDriftReport(report), nil
+}
+
+func (impl *GitOpsDriftServiceImpl) getGitOpsPipeline(appId, envId int) (*pipelineConfig.Pipeline, error) {
+	pipelines, err := impl.pipelineRepository.FindActiveByAppIdAndEnvironmentId(appId, envId)
+	if err != nil {
+		impl.logger.Errorw("error in getting pipeline", "appId", appId, "envId", envId, "err", err)
+		return nil, err
+	}
+	if len(pipelines) == 0 {
+		return nil, &util.ApiError{HttpStatusCode: 404, UserMessage: "pipeline not found", InternalMessage: "pipeline not found"}
+	}
+	pipeline := pipelines[0]
+	if pipeline.DeploymentAppType != util.PIPELINE_DEPLOYMENT_TYPE_ACD || !pipeline.DeploymentAppCreated {
+		return nil, &util.ApiError{HttpStatusCode: 400, UserMessage: "pipeline is not deployed through gitops", InternalMessage: "pipeline is not deployed through gitops"}
+	}
+	return pipeline, nil
+}
+
+func (impl *GitOpsDriftServiceImpl) getArgoContext() (context.Context, error) {
+	acdToken, err := impl.argoUserService.GetLatestDevtronArgoCdUserToken()
+	if err != nil {
+		impl.logger.Errorw("error in getting acd token", "err", err)
+		return nil, err
+	}
+	return context.WithValue(context.Background(), "token", acdToken), nil
+}
+
+// detectDrift compares the values of the last devtron release with the gitops repo and the repo with the live state,
+// and saves the result as the pipeline's drift report
+func (impl *GitOpsDriftServiceImpl) detectDrift(ctx context.Context, pipeline *pipelineConfig.Pipeline, clonedRepos map[string]string) (*pipelineConfig.GitOpsDriftReport, error) {
+	override, err := impl.pipelineOverrideRepository.FindLatestCommittedByPipelineId(pipeline.Id)
+	if err == pg.ErrNoRows {
+		impl.logger.Debugw("no committed release for pipeline, skipping drift detection", "pipelineId", pipeline.Id)
+		return nil, &util.ApiError{HttpStatusCode: 400, UserMessage: "pipeline has no release committed to gitops repo", InternalMessage: err.Error()}
+	} else if err != nil {
+		impl.logger.Errorw("error in getting latest committed pipeline override", "pipelineId", pipeline.Id, "err", err)
+		return nil, err
+	}
+	report, err := impl.gitOpsDriftReportRepository.FindByPipelineId(pipeline.Id)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting gitops drift report", "pipelineId", pipeline.Id, "err", err)
+		return nil, err
+	}
+	if err == pg.ErrNoRows {
+		report = &pipelineConfig.GitOpsDriftReport{
+			PipelineId:    pipeline.Id,
+			AppId:         pipeline.AppId,
+			EnvironmentId: pipeline.EnvironmentId,
+			AuditLog:      sql.AuditLog{CreatedOn: time.Now(), CreatedBy: 1},
+		}
+	}
+	report.PipelineOverrideId = override.Id
+	report.DevtronCommitHash = override.GitHash
+	report.GitHeadCommitHash, report.GitHeadCommitAuthor, report.GitHeadCommitTime = "", "", time.Time{}
+	report.LiveSyncRevision, report.OutOfSyncResources = "", "[]"
+	var messages []string
+	gitMessage := impl.detectGitDrift(override, pipeline, report, clonedRepos)
+	if len(gitMessage) > 0 {
+		messages = append(messages, gitMessage)
+	}
+	liveMessage := impl.detectLiveDrift(ctx, pipeline, report)
+	if len(liveMessage) > 0 {
+		messages = append(messages, liveMessage)
+	}
+	report.Message = ""
+	for i, message := range messages {
+		if i > 0 {
+			report.Message += "; "
+		}
+		report.Message += message
+	}
+	report.DetectedOn = time.Now()
+	report.UpdatedOn = time.Now()
+	report.UpdatedBy = 1
+	if report.Id == 0 {
+		err = impl.gitOpsDriftReportRepository.Save(report)
+	} else {
+		err = impl.gitOpsDriftReportRepository.Update(report)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// detectGitDrift sets git drift status of the report and returns a message describing the drift
+func (impl *GitOpsDriftServiceImpl) detectGitDrift(override *chartConfig.PipelineOverride, pipeline *pipelineConfig.Pipeline,
+	report *pipelineConfig.GitOpsDriftReport, clonedRepos map[string]string) string {
+	report.GitDriftStatus = pipelineConfig.GITOPS_DRIFT_STATUS_UNKNOWN
+	envOverride, err := impl.environmentConfigRepository.Get(override.EnvConfigOverrideId)
+	if err != nil {
+		impl.logger.Errorw("error in getting env config override", "id", override.EnvConfigOverrideId, "err", err)
+		return fmt.Sprintf("could not get chart of release: %v", err)
+	}
+	repoUrl := envOverride.Chart.GitRepoUrl
+	clonedDir, ok := clonedRepos[repoUrl]
+	if !ok {
+		gitOpsRepoName := impl.chartTemplateService.GetGitOpsRepoNameFromUrl(repoUrl)
+		clonedDir, err = impl.gitFactory.GitService.Clone(repoUrl, fmt.Sprintf("drift-%s-%s", gitOpsRepoName, impl.chartTemplateService.GetDir()))
+		if err != nil {
+			impl.logger.Errorw("error in cloning gitops repo", "url", repoUrl, "err", err)
+			return fmt.Sprintf("could not clone gitops repo: %v", err)
+		}
+		clonedRepos[repoUrl] = clonedDir
+	}
+	chartLocation := util.GetEnvChartLocation(envOverride.Chart.ChartLocation, envOverride.Chart.GitRepoDirectory, pipeline.Environment.Name)
+	valuesPath := filepath.Join(chartLocation, fmt.Sprintf("_%d-values.yaml", pipeline.EnvironmentId))
+	lastCommit, err := impl.gitFactory.GitService.GetLastCommitForPath(clonedDir, valuesPath)
+	if err != nil {
+		impl.logger.Errorw("error in getting last commit of values file", "path", valuesPath, "err", err)
+		return fmt.Sprintf("could not get last commit of %s: %v", valuesPath, err)
+	}
+	if lastCommit != nil {
+		report.GitHeadCommitHash = lastCommit.CommitHash
+		report.GitHeadCommitAuthor = lastCommit.AuthorName
+		report.GitHeadCommitTime = lastCommit.CommitTime
+	}
+	values, err := os.ReadFile(filepath.Join(clonedDir, valuesPath))
+	if os.IsNotExist(err) {
+		report.GitDriftStatus = pipelineConfig.GITOPS_DRIFT_STATUS_DRIFTED
+		return fmt.Sprintf("%s has been removed from gitops repo", valuesPath)
+	} else if err != nil {
+		return fmt.Sprintf("could not read %s: %v", valuesPath, err)
+	}
+	// a pull request merge commit or a reformatting commit has a different hash but the same values
+	if report.GitHeadCommitHash == override.GitHash || isSameValues(values, []byte(override.PipelineMergedValues)) {
+		report.GitDriftStatus = pipelineConfig.GITOPS_DRIFT_STATUS_IN_SYNC
+		return ""
+	}
+	report.GitDriftStatus = pipelineConfig.GITOPS_DRIFT_STATUS_DRIFTED
+	return fmt.Sprintf("%s was changed outside devtron by %s in commit %s", valuesPath, report.GitHeadCommitAuthor, report.GitHeadCommitHash)
+}
+
+// detectLiveDrift sets live drift status of the report from the argo cd application and returns a message describing the drift
+func (impl *GitOpsDriftServiceImpl) detectLiveDrift(ctx context.Context, pipeline *pipelineConfig.Pipeline, report *pipelineConfig.GitOpsDriftReport) string {
+	report.LiveDriftStatus = pipelineConfig.GITOPS_DRIFT_STATUS_UNKNOWN
+	argoAppName := pipeline.DeploymentAppName
+	argoApplication, err := impl.acdClient.Get(ctx, &application2.ApplicationQuery{Name: &argoAppName})
+	if err != nil {
+		impl.logger.Errorw("error in getting argo application", "argoAppName", argoAppName, "err", err)
+		return fmt.Sprintf("could not get argo cd application: %v", err)
+	}
+	report.LiveSyncRevision = argoApplication.Status.Sync.Revision
+	if argoApplication.Status.OperationState != nil && !argoApplication.Status.OperationState.Phase.Completed() {
+		return "argo cd sync is in progress"
+	}
+	if argoApplication.Status.Sync.Status != v1alpha1.SyncStatusCodeOutOfSync {
+		report.LiveDriftStatus = pipelineConfig.GITOPS_DRIFT_STATUS_IN_SYNC
+		return ""
+	}
+	resources := make([]*GitOpsDriftResource, 0)
+	for _, resource := range argoApplication.Status.Resources {
+		if resource.Status != v1alpha1.SyncStatusCodeOutOfSync {
+			continue
+		}
+		resources = append(resources, &GitOpsDriftResource{
+			Group:     resource.Group,
+			Kind:      resource.Kind,
+			Namespace: resource.Namespace,
+			Name:      resource.Name,
+		})
+	}
+	resourcesJson, err := json.Marshal(resources)
+	if err != nil {
+		impl.logger.Errorw("error in marshalling out of sync resources", "err", err)
+	} else {
+		report.OutOfSyncResources = string(resourcesJson)
+	}
+	report.LiveDriftStatus = pipelineConfig.GITOPS_DRIFT_STATUS_DRIFTED
+	return fmt.Sprintf("%d resources of argo cd application %s are out of sync", len(resources), argoAppName)
+}
+
+// isSameValues compares two values yaml ignoring formatting and key order
+func isSameValues(a, b []byte) bool {
+	aJson, err := yaml.YAMLToJSON(a)
+	if err != nil {
+		return false
+	}
+	bJson, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		return false
+	}
+	var aValues, bValues interface{}
+	if json.Unmarshal(aJson, &aValues) != nil || json.Unmarshal(bJson, &bValues) != nil {
+		return false
+	}
+	return reflect.DeepEqual(aValues, bValues)
+}
+
+func adaptGitOpsDriftReport(report *pipelineConfig.GitOpsDriftReport) *GitOpsDriftReportDto {
+	dto := &GitOpsDriftReportDto{
+		PipelineId:          report.PipelineId,
+		AppId:               report.AppId,
+		EnvironmentId:       report.EnvironmentId,
+		PipelineOverrideId:  report.PipelineOverrideId,
+		DevtronCommitHash:   report.DevtronCommitHash,
+		GitHeadCommitHash:   report.GitHeadCommitHash,
+		GitHeadCommitAuthor: report.GitHeadCommitAuthor,
+		GitDriftStatus:      report.GitDriftStatus,
+		LiveDriftStatus:     report.LiveDriftStatus,
+		LiveSyncRevision:    report.LiveSyncRevision,
+		OutOfSyncResources:  make([]*GitOpsDriftResource, 0),
+		Message:             report.Message,
+		DetectedOn:          report.DetectedOn,
+	}
+	if !report.GitHeadCommitTime.IsZero() {
+		commitTime := report.GitHeadCommitTime
+		dto.GitHeadCommitTime = &commitTime
+	}
+	if len(report.OutOfSyncResources) > 0 {
+		_ = json.Unmarshal([]byte(report.OutOfSyncResources), &dto.OutOfSyncResources)
+	}
+	return dto
+}
@@ -0,0 +1,216 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	application2 "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/devtron-labs/devtron/client/argocdServer/application"
+	app2 "github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	chartService "github.com/devtron-labs/devtron/pkg/chart"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	"github.com/devtron-labs/devtron/util/argo"
+	"go.uber.org/zap"
+	"path/filepath"
+	"time"
+)
+
+const (
+	GitOpsRepoMigrationStatusMigrated = "Migrated"
+	GitOpsRepoMigrationStatusSkipped  = "Skipped"
+	GitOpsRepoMigrationStatusFailed   = "Failed"
+)
+
+type GitOpsMonorepoMigrationRequest struct {
+	AppIds []int `json:"appIds" validate:"min=1"`
+	UserId int32 `json:"-"`
+}
+
+type GitOpsMonorepoMigrationResponse struct {
+	AppId            int    `json:"appId"`
+	AppName          string `json:"appName"`
+	Status           string `json:"status"`
+	Message          string `json:"message,omitempty"`
+	GitRepoUrl       string `json:"gitRepoUrl,omitempty"`
+	GitRepoDirectory string `json:"gitRepoDirectory,omitempty"`
+}
+
+type GitOpsRepoMigrationService interface {
+	// MigrateAppsToMonorepo moves apps which have their own gitops repository into the shared repository of the
+	// configured layout, the app's own repository is kept unchanged
+	MigrateAppsToMonorepo(request *GitOpsMonorepoMigrationRequest) ([]*GitOpsMonorepoMigrationResponse, error)
+}
+
+type GitOpsRepoMigrationServiceImpl struct {
+	logger               *zap.SugaredLogger
+	appRepository        app2.AppRepository
+	chartRepository      chartRepoRepository.ChartRepository
+	pipelineRepository   pipelineConfig.PipelineRepository
+	chartTemplateService util.ChartTemplateService
+	chartService         chartService.ChartService
+	acdClient            application.ServiceClient
+	argoUserService      argo.ArgoUserService
+}
+
+func NewGitOpsRepoMigrationServiceImpl(logger *zap.SugaredLogger, appRepository app2.AppRepository,
+	chartRepository chartRepoRepository.ChartRepository, pipelineRepository pipelineConfig.PipelineRepository,
+	chartTemplateService util.ChartTemplateService, chartService chartService.ChartService,
+	acdClient application.ServiceClient, argoUserService argo.ArgoUserService) *GitOpsRepoMigrationServiceImpl {
+	return &GitOpsRepoMigrationServiceImpl{
+		logger:               logger,
+		appRepository:        appRepository,
+		chartRepository:      chartRepository,
+		pipelineRepository:   pipelineRepository,
+		chartTemplateService: chartTemplateService,
+		chartService:         chartService,
+		acdClient:            acdClient,
+		argoUserService:      argoUserService,
+	}
+}
+
+func (impl *GitOpsRepoMigrationServiceImpl) MigrateAppsToMonorepo(request *GitOpsMonorepoMigrationRequest) ([]*GitOpsMonorepoMigrationResponse, error) {
+	if !impl.chartTemplateService.IsGitOpsMonorepoLayout() {
+		return nil, &util.ApiError{
+			HttpStatusCode:  400,
+			UserMessage:     "gitops repo layout is not a monorepo, set GITOPS_REPO_LAYOUT to MONOREPO or MONOREPO_PER_TEAM",
+			InternalMessage: "gitops repo layout is not a monorepo",
+		}
+	}
+	acdToken, err := impl.argoUserService.GetLatestDevtronArgoCdUserToken()
+	if err != nil {
+		impl.logger.Errorw("error in getting acd token", "err", err)
+		return nil, err
+	}
+	ctx := context.WithValue(context.Background(), "token", acdToken)
+	responses := make([]*GitOpsMonorepoMigrationResponse, 0, len(request.AppIds))
+	for _, appId := range request.AppIds {
+		response := &GitOpsMonorepoMigrationResponse{AppId: appId}
+		err = impl.migrateApp(ctx, appId, request.UserId, response)
+		if err != nil {
+			impl.logger.Errorw("error in migrating app to gitops monorepo", "appId", appId, "err", err)
+			response.Status = GitOpsRepoMigrationStatusFailed
+			response.Message = err.Error()
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+func (impl *GitOpsRepoMigrationServiceImpl) migrateApp(ctx context.Context, appId int, userId int32, response *GitOpsMonorepoMigrationResponse) error {
+	app, err := impl.appRepository.FindAppAndProjectByAppId(appId)
+	if err != nil {
+		return err
+	}
+	response.AppName = app.AppName
+	charts, err := impl.chartRepository.FindActiveChartsByAppId(appId)
+	if err != nil {
+		return err
+	}
+	var latestChart *chartRepoRepository.Chart
+	for _, chart := range charts {
+		if chart.Latest {
+			latestChart = chart
+		}
+	}
+	if latestChart == nil || len(latestChart.GitRepoUrl) == 0 {
+		response.Status = GitOpsRepoMigrationStatusSkipped
+		response.Message = "app is not deployed through gitops"
+		return nil
+	}
+	if len(latestChart.GitRepoDirectory) > 0 {
+		response.Status = GitOpsRepoMigrationStatusSkipped
+		response.Message = "app is already in a shared gitops repository"
+		response.GitRepoUrl, response.GitRepoDirectory = latestChart.GitRepoUrl, latestChart.GitRepoDirectory
+		return nil
+	}
+	gitOpsRepoName, gitRepoDirectory := impl.chartTemplateService.GetGitOpsRepoNameAndDirectory(app.AppName, app.Team.Name)
+	chartGitAttr, err := impl.chartTemplateService.CreateGitRepositoryForApp(gitOpsRepoName, gitRepoDirectory, latestChart.ReferenceTemplate, latestChart.ChartVersion, userId)
+	if err != nil {
+		return err
+	}
+	sourceRepoUrl := latestChart.GitRepoUrl
+	err = impl.chartService.RegisterInArgo(chartGitAttr, ctx)
+	if err != nil {
+		return err
+	}
+	// the latest chart being in the shared repo is what skips the app on a retry, so argo cd applications are patched
+	// first and the latest chart is updated last, a failure in between leaves the app to be migrated again.
+	// applications already patched are skipped on the retry
+	err = impl.updateArgoApplications(ctx, appId, sourceRepoUrl, chartGitAttr.RepoUrl, gitRepoDirectory, userId)
+	if err != nil {
+		return err
+	}
+	for _, chart := range charts {
+		if chart.GitRepoUrl != sourceRepoUrl || chart == latestChart {
+			continue
+		}
+		err = impl.moveChart(chart, chartGitAttr.RepoUrl, gitRepoDirectory, userId)
+		if err != nil {
+			return err
+		}
+	}
+	err = impl.moveChart(latestChart, chartGitAttr.RepoUrl, gitRepoDirectory, userId)
+	if err != nil {
+		return err
+	}
+	response.Status = GitOpsRepoMigrationStatusMigrated
+	response.GitRepoUrl, response.GitRepoDirectory = chartGitAttr.RepoUrl, gitRepoDirectory
+	return nil
+}
+
+func (impl *GitOpsRepoMigrationServiceImpl) moveChart(chart *chartRepoRepository.Chart, gitRepoUrl, gitRepoDirectory string, userId int32) error {
+	chart.GitRepoUrl = gitRepoUrl
+	chart.GitRepoDirectory = gitRepoDirectory
+	chart.ChartLocation = filepath.Join(gitRepoDirectory, chart.ChartLocation)
+	chart.UpdatedOn = time.Now()
+	chart.UpdatedBy = userId
+	return impl.chartRepository.Update(chart)
+}
+
+// updateArgoApplications copies the app's repo into the directory of each deployed environment and points the
+// environment's argo cd application to it, paths in the shared repo are the paths in the app's own repo within the
+// environment's directory. Environments not deployed yet get their chart pushed on the first deployment.
+func (impl *GitOpsRepoMigrationServiceImpl) updateArgoApplications(ctx context.Context, appId int, sourceRepoUrl, targetRepoUrl, gitRepoDirectory string, userId int32) error {
+	pipelines, err := impl.pipelineRepository.FindActiveByAppId(appId)
+	if err != nil {
+		return err
+	}
+	for _, pipeline := range pipelines {
+		if pipeline.DeploymentAppType != util.PIPELINE_DEPLOYMENT_TYPE_ACD || !pipeline.DeploymentAppCreated {
+			continue
+		}
+		argoAppName := pipeline.DeploymentAppName
+		argoApplication, err := impl.acdClient.Get(ctx, &application2.ApplicationQuery{Name: &argoAppName})
+		if err != nil {
+			impl.logger.Errorw("error in getting argo application", "argoAppName", argoAppName, "err", err)
+			return err
+		}
+		if argoApplication.Spec.Source.RepoURL != sourceRepoUrl {
+			continue
+		}
+		envDirectory := filepath.Join(gitRepoDirectory, pipeline.Environment.Name)
+		err = impl.chartTemplateService.CopyGitRepoToDirectory(sourceRepoUrl, targetRepoUrl, envDirectory, userId)
+		if err != nil {
+			return err
+		}
+		patchReq := v1alpha1.Application{Spec: v1alpha1.ApplicationSpec{Source: v1alpha1.ApplicationSource{
+			Path:    filepath.Join(envDirectory, argoApplication.Spec.Source.Path),
+			RepoURL: targetRepoUrl,
+		}}}
+		reqbyte, err := json.Marshal(patchReq)
+		if err != nil {
+			return err
+		}
+		reqString := string(reqbyte)
+		patchType := "merge"
+		_, err = impl.acdClient.Patch(ctx, &application2.ApplicationPatchRequest{Patch: &reqString, Name: &argoAppName, PatchType: &patchType})
+		if err != nil {
+			impl.logger.Errorw("error in patching argo application", "argoAppName", argoAppName, "patch", reqString, "err", err)
+			return fmt.Errorf("error in updating argo application %s: %v", argoAppName, err)
+		}
+	}
+	return nil
+}
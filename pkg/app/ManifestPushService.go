@@ -24,6 +24,7 @@ type ManifestPushService interface {
 
 type GitOpsPushService interface {
 	ManifestPushService
+	CommitValuesToGit(manifestPushTemplate *bean.ManifestPushTemplate, ctx context.Context) (commitHash string, commitTime time.Time, err error)
 }
 
 type GitOpsManifestPushServiceImpl struct {
@@ -33,6 +34,7 @@ type GitOpsManifestPushServiceImpl struct {
 	gitOpsConfigRepository        repository.GitOpsConfigRepository
 	gitFactory                    *GitFactory
 	pipelineStatusTimelineService status2.PipelineStatusTimelineService
+	gitOpsPullRequestRepository   pipelineConfig.GitOpsPullRequestRepository
 }
 
 func NewGitOpsManifestPushServiceImpl(
@@ -42,6 +44,7 @@ func NewGitOpsManifestPushServiceImpl(
 	gitOpsConfigRepository repository.GitOpsConfigRepository,
 	gitFactory *GitFactory,
 	pipelineStatusTimelineService status2.PipelineStatusTimelineService,
+	gitOpsPullRequestRepository pipelineConfig.GitOpsPullRequestRepository,
 ) *GitOpsManifestPushServiceImpl {
 	return &GitOpsManifestPushServiceImpl{
 		logger:                        logger,
@@ -50,6 +53,7 @@ func NewGitOpsManifestPushServiceImpl(
 		gitOpsConfigRepository:        gitOpsConfigRepository,
 		gitFactory:                    gitFactory,
 		pipelineStatusTimelineService: pipelineStatusTimelineService,
+		gitOpsPullRequestRepository:   gitOpsPullRequestRepository,
 	}
 }
 
@@ -69,6 +73,24 @@ func (impl *GitOpsManifestPushServiceImpl) PushChart(manifestPushTemplate *bean.
 		impl.SaveTimelineForError(manifestPushTemplate, err)
 		return manifestPushResponse
 	}
+	if manifestPushTemplate.GitOpsPullRequestEnabled {
+		pullRequest, err := impl.OpenPullRequest(manifestPushTemplate, ctx)
+		if err != nil {
+			impl.logger.Errorw("error in opening gitops pull request", "err", err)
+			manifestPushResponse.Error = err
+			impl.SaveTimelineForError(manifestPushTemplate, err)
+			return manifestPushResponse
+		}
+		// commit reaches the deployed branch on merge, git hash of the release is recorded then
+		manifestPushResponse.IsPullRequestOpen = true
+		manifestPushResponse.PullRequestUrl = pullRequest.Url
+		timeline := getTimelineObject(manifestPushTemplate, pipelineConfig.TIMELINE_STATUS_GIT_PULL_REQUEST_OPEN, fmt.Sprintf("Pull request %s opened, deployment will start once it is merged.", pullRequest.Url))
+		timelineErr := impl.pipelineStatusTimelineService.SaveTimeline(timeline, nil, false)
+		if timelineErr != nil {
+			impl.logger.Errorw("error in saving git pull request timeline", "err", timelineErr, "timeline", timeline)
+		}
+		return manifestPushResponse
+	}
 	manifestPushResponse.CommitHash = commitHash
 	manifestPushResponse.CommitTime = commitTime
 
@@ -81,9 +103,9 @@ func (impl *GitOpsManifestPushServiceImpl) PushChart(manifestPushTemplate *bean.
 
 func (impl *GitOpsManifestPushServiceImpl) PushChartToGitRepo(manifestPushTemplate *bean.ManifestPushTemplate, ctx context.Context) error {
 
-	_, span := otel.Tracer("orchestrator").Start(ctx, "chartTemplateService.GetGitOpsRepoName")
+	_, span := otel.Tracer("orchestrator").Start(ctx, "chartTemplateService.GetGitOpsRepoNameFromUrl")
 	// CHART COMMIT and PUSH STARTS HERE, it will push latest version, if found modified on deployment template and overrides
-	gitOpsRepoName := impl.chartTemplateService.GetGitOpsRepoName(manifestPushTemplate.AppName)
+	gitOpsRepoName := impl.chartTemplateService.GetGitOpsRepoNameFromUrl(manifestPushTemplate.RepoUrl)
 	span.End()
 	_, span = otel.Tracer("orchestrator").Start(ctx, "chartService.CheckChartExists")
 	err := impl.chartService.CheckChartExists(manifestPushTemplate.ChartRefId)
@@ -92,7 +114,7 @@ func (impl *GitOpsManifestPushServiceImpl) PushChartToGitRepo(manifestPushTempla
 		impl.logger.Errorw("err in getting chart info", "err", err)
 		return err
 	}
-	err = impl.chartTemplateService.PushChartToGitRepo(gitOpsRepoName, manifestPushTemplate.ChartReferenceTemplate, manifestPushTemplate.ChartVersion, manifestPushTemplate.BuiltChartPath, manifestPushTemplate.RepoUrl, manifestPushTemplate.UserId)
+	err = impl.chartTemplateService.PushChartToGitRepo(gitOpsRepoName, manifestPushTemplate.ChartLocation, manifestPushTemplate.BuiltChartPath, manifestPushTemplate.RepoUrl, manifestPushTemplate.UserId)
 	if err != nil {
 		impl.logger.Errorw("error in pushing chart to git", "err", err)
 		return err
@@ -118,15 +140,13 @@ func (impl *GitOpsManifestPushServiceImpl) CommitValuesToGit(manifestPushTemplat
 		UserName:       userName,
 		UserEmailId:    userEmailId,
 	}
-	gitOpsConfigBitbucket, err := impl.gitOpsConfigRepository.GetGitOpsConfigByProvider(util.BITBUCKET_PROVIDER)
+	if manifestPushTemplate.GitOpsPullRequestEnabled {
+		chartGitAttr.Branch = getPullRequestBranch(manifestPushTemplate)
+	}
+	gitOpsConfig, err := impl.getGitOpsConfigForCommit()
 	if err != nil {
-		if err == pg.ErrNoRows {
-			gitOpsConfigBitbucket.BitBucketWorkspaceId = ""
-		} else {
-			return commitHash, commitTime, err
-		}
+		return commitHash, commitTime, err
 	}
-	gitOpsConfig := &bean2.GitOpsConfigDto{BitBucketWorkspaceId: gitOpsConfigBitbucket.BitBucketWorkspaceId}
 	_, span = otel.Tracer("orchestrator").Start(ctx, "gitFactory.Client.CommitValues")
 	commitHash, commitTime, err = impl.gitFactory.Client.CommitValues(chartGitAttr, gitOpsConfig)
 	span.End()
@@ -144,6 +164,68 @@ func (impl *GitOpsManifestPushServiceImpl) CommitValuesToGit(manifestPushTemplat
 	return commitHash, commitTime, nil
 }
 
+// OpenPullRequest raises a pull request of the release branch committed by CommitValuesToGit into the deployed branch
+func (impl *GitOpsManifestPushServiceImpl) OpenPullRequest(manifestPushTemplate *bean.ManifestPushTemplate, ctx context.Context) (*util.PullRequestDto, error) {
+	chartRepoName := impl.chartTemplateService.GetGitOpsRepoNameFromUrl(manifestPushTemplate.RepoUrl)
+	gitOpsConfig, err := impl.getGitOpsConfigForCommit()
+	if err != nil {
+		return nil, err
+	}
+	pullRequestConfig := &util.PullRequestConfig{
+		ChartRepoName: chartRepoName,
+		SourceBranch:  getPullRequestBranch(manifestPushTemplate),
+		TargetBranch:  util.GITOPS_DEFAULT_BRANCH,
+		Title:         fmt.Sprintf("Deploy %s to %s", manifestPushTemplate.AppName, manifestPushTemplate.EnvironmentName),
+		Description:   fmt.Sprintf("Release %d of %s for environment %s, raised by Devtron. Deployment starts once this is merged.", manifestPushTemplate.PipelineOverrideId, manifestPushTemplate.AppName, manifestPushTemplate.EnvironmentName),
+	}
+	_, span := otel.Tracer("orchestrator").Start(ctx, "gitFactory.Client.CreatePullRequest")
+	pullRequest, err := impl.gitFactory.Client.CreatePullRequest(pullRequestConfig, gitOpsConfig)
+	span.End()
+	if err != nil {
+		impl.logger.Errorw("error in creating pull request", "err", err, "config", pullRequestConfig)
+		return nil, err
+	}
+	gitOpsPullRequest := &pipelineConfig.GitOpsPullRequest{
+		CdWorkflowRunnerId: manifestPushTemplate.WorkflowRunnerId,
+		PipelineOverrideId: manifestPushTemplate.PipelineOverrideId,
+		GitRepoName:        chartRepoName,
+		PullRequestId:      pullRequest.Id,
+		PullRequestUrl:     pullRequest.Url,
+		SourceBranch:       pullRequestConfig.SourceBranch,
+		TargetBranch:       pullRequestConfig.TargetBranch,
+		Status:             pipelineConfig.GITOPS_PULL_REQUEST_OPEN,
+		AuditLog: sql.AuditLog{
+			CreatedBy: manifestPushTemplate.UserId,
+			CreatedOn: time.Now(),
+			UpdatedBy: manifestPushTemplate.UserId,
+			UpdatedOn: time.Now(),
+		},
+	}
+	err = impl.gitOpsPullRequestRepository.Save(gitOpsPullRequest)
+	if err != nil {
+		return nil, err
+	}
+	return pullRequest, nil
+}
+
+// getGitOpsConfigForCommit returns the provider specific config needed by git clients for commits
+func (impl *GitOpsManifestPushServiceImpl) getGitOpsConfigForCommit() (*bean2.GitOpsConfigDto, error) {
+	gitOpsConfigBitbucket, err := impl.gitOpsConfigRepository.GetGitOpsConfigByProvider(util.BITBUCKET_PROVIDER)
+	if err != nil {
+		if err == pg.ErrNoRows {
+			gitOpsConfigBitbucket.BitBucketWorkspaceId = ""
+		} else {
+			return nil, err
+		}
+	}
+	return &bean2.GitOpsConfigDto{BitBucketWorkspaceId: gitOpsConfigBitbucket.BitBucketWorkspaceId}, nil
+}
+
+// getPullRequestBranch names the release branch after the environment, TargetEnvironmentName holds the environment id
+func getPullRequestBranch(manifestPushTemplate *bean.ManifestPushTemplate) string {
+	return fmt.Sprintf("devtron-release-%d-%s", manifestPushTemplate.PipelineOverrideId, manifestPushTemplate.EnvironmentName)
+}
+
 func (impl *GitOpsManifestPushServiceImpl) SaveTimelineForError(manifestPushTemplate *bean.ManifestPushTemplate, gitCommitErr error) {
 	timeline := getTimelineObject(manifestPushTemplate, pipelineConfig.TIMELINE_STATUS_GIT_COMMIT_FAILED, fmt.Sprintf("Git commit failed - %v", gitCommitErr))
 	timelineErr := impl.pipelineStatusTimelineService.SaveTimeline(timeline, nil, false)
@@ -11,6 +11,7 @@ type ManifestPushTemplate struct {
 	PipelineOverrideId     int
 	AppName                string
 	TargetEnvironmentName  int
+	EnvironmentName        string
 	ChartReferenceTemplate string
 	ChartName              string
 	ChartVersion           string
@@ -19,12 +20,16 @@ type ManifestPushTemplate struct {
 	BuiltChartPath         string
 	BuiltChartBytes        *[]byte
 	MergedValues           string
+	// GitOpsPullRequestEnabled commits values on a new branch and raises a pull request into the deployed branch
+	GitOpsPullRequestEnabled bool
 }
 
 type ManifestPushResponse struct {
-	CommitHash string
-	CommitTime time.Time
-	Error      error
+	CommitHash        string
+	CommitTime        time.Time
+	Error             error
+	IsPullRequestOpen bool
+	PullRequestUrl    string
 }
 
 type HelmRepositoryConfig struct {
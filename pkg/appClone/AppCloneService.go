@@ -146,7 +146,7 @@ func (impl *AppCloneServiceImpl) CloneApp(createReq *bean.CreateAppDTO, context
 			return nil, nil
 		}
 	}
-	app, err := impl.CreateApp(cloneReq, userId)
+	app, err := impl.CreateApp(cloneReq, userId, context)
 	if err != nil {
 		impl.logger.Errorw("error in creating app", "req", cloneReq, "err", err)
 		return nil, err
@@ -182,13 +182,13 @@ func (impl *AppCloneServiceImpl) CloneApp(createReq *bean.CreateAppDTO, context
 			return nil, err
 		}
 	}
-	_, err = impl.CreateGlobalCM(cloneReq.RefAppId, newAppId, userId)
+	_, err = impl.CreateGlobalCM(context, cloneReq.RefAppId, newAppId, userId)
 
 	if err != nil {
 		impl.logger.Errorw("error in creating global cm", "ref", cloneReq.RefAppId, "new", newAppId, "err", err)
 		return nil, err
 	}
-	_, err = impl.CreateGlobalSecret(cloneReq.RefAppId, newAppId, userId)
+	_, err = impl.CreateGlobalSecret(context, cloneReq.RefAppId, newAppId, userId)
 	if err != nil {
 		impl.logger.Errorw("error in creating global secret", "ref", cloneReq.RefAppId, "new", newAppId, "err", err)
 		return nil, err
@@ -227,7 +227,7 @@ func (impl *AppCloneServiceImpl) CloneApp(createReq *bean.CreateAppDTO, context
 	return app, nil
 }
 
-func (impl *AppCloneServiceImpl) CreateApp(cloneReq *CloneRequest, userId int32) (*bean.CreateAppDTO, error) {
+func (impl *AppCloneServiceImpl) CreateApp(cloneReq *CloneRequest, userId int32, ctx context.Context) (*bean.CreateAppDTO, error) {
 	createAppReq := &bean.CreateAppDTO{
 		AppName:     cloneReq.Name,
 		UserId:      userId,
@@ -236,7 +236,7 @@ func (impl *AppCloneServiceImpl) CreateApp(cloneReq *CloneRequest, userId int32)
 		AppType:     cloneReq.AppType,
 		Description: cloneReq.Description,
 	}
-	createRes, err := impl.pipelineBuilder.CreateApp(createAppReq)
+	createRes, err := impl.pipelineBuilder.CreateApp(createAppReq, ctx)
 	return createRes, err
 }
 
@@ -345,7 +345,7 @@ func (impl *AppCloneServiceImpl) CreateAppMetrics(oldAppId, newAppId int, userId
 
 }
 
-func (impl *AppCloneServiceImpl) CreateGlobalCM(oldAppId, newAppId int, userId int32) (*bean3.ConfigDataRequest, error) {
+func (impl *AppCloneServiceImpl) CreateGlobalCM(ctx context.Context, oldAppId, newAppId int, userId int32) (*bean3.ConfigDataRequest, error) {
 	refCM, err := impl.configMapService.CMGlobalFetch(oldAppId)
 	if err != nil {
 		return nil, err
@@ -364,7 +364,7 @@ func (impl *AppCloneServiceImpl) CreateGlobalCM(oldAppId, newAppId int, userId i
 			UserId:        userId,
 			Id:            thisCm.Id,
 		}
-		thisCm, err = impl.configMapService.CMGlobalAddUpdate(newCm)
+		thisCm, err = impl.configMapService.CMGlobalAddUpdate(newCm, ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -408,7 +408,7 @@ func (impl *AppCloneServiceImpl) CreateEnvCm(ctx context.Context, oldAppId, newA
 				UserId:        userId,
 				Id:            thisCm.Id,
 			}
-			thisCm, err = impl.configMapService.CMEnvironmentAddUpdate(newCm)
+			thisCm, err = impl.configMapService.CMEnvironmentAddUpdate(newCm, ctx)
 			if err != nil {
 				return nil, err
 			}
@@ -454,7 +454,7 @@ func (impl *AppCloneServiceImpl) CreateEnvSecret(ctx context.Context, oldAppId,
 				UserId:        userId,
 				Id:            thisCm.Id,
 			}
-			thisCm, err = impl.configMapService.CSEnvironmentAddUpdate(newCm)
+			thisCm, err = impl.configMapService.CSEnvironmentAddUpdate(newCm, ctx)
 			if err != nil {
 				return nil, err
 			}
@@ -550,7 +550,7 @@ func (impl *AppCloneServiceImpl) configDataClone(cfData []*bean3.ConfigData) []*
 	return copiedData
 }
 
-func (impl *AppCloneServiceImpl) CreateGlobalSecret(oldAppId, newAppId int, userId int32) (*bean3.ConfigDataRequest, error) {
+func (impl *AppCloneServiceImpl) CreateGlobalSecret(ctx context.Context, oldAppId, newAppId int, userId int32) (*bean3.ConfigDataRequest, error) {
 
 	refCs, err := impl.configMapService.CSGlobalFetch(oldAppId)
 	if err != nil {
@@ -572,7 +572,7 @@ func (impl *AppCloneServiceImpl) CreateGlobalSecret(oldAppId, newAppId int, user
 			UserId:        userId,
 			Id:            thisCm.Id,
 		}
-		thisCm, err = impl.configMapService.CSGlobalAddUpdate(newCm)
+		thisCm, err = impl.configMapService.CSGlobalAddUpdate(newCm, ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -686,7 +686,7 @@ func (impl *AppCloneServiceImpl) createWfMappings(refWfMappings []appWorkflow.Ap
 			gitMaterialMapping: gitMaterialMapping,
 			refAppName:         refApp.AppName,
 		}
-		ci, err = impl.CreateCiPipeline(cloneCiPipelineRequest)
+		ci, err = impl.CreateCiPipeline(cloneCiPipelineRequest, ctx)
 		if err != nil {
 			impl.logger.Errorw("error in creating ci pipeline, app clone", "err", err)
 			return err
@@ -733,7 +733,7 @@ type cloneCiPipelineRequest struct {
 	refAppName         string
 }
 
-func (impl *AppCloneServiceImpl) CreateCiPipeline(req *cloneCiPipelineRequest) (*bean.CiConfigRequest, error) {
+func (impl *AppCloneServiceImpl) CreateCiPipeline(req *cloneCiPipelineRequest, ctx context.Context) (*bean.CiConfigRequest, error) {
 	refCiConfig, err := impl.pipelineBuilder.GetCiPipeline(req.refAppId)
 	if err != nil {
 		return nil, err
@@ -860,7 +860,7 @@ func (impl *AppCloneServiceImpl) CreateCiPipeline(req *cloneCiPipelineRequest) (
 				}
 			}
 
-			return impl.pipelineBuilder.PatchCiPipeline(ciPatchReq)
+			return impl.pipelineBuilder.PatchCiPipeline(ciPatchReq, ctx)
 		}
 	}
 	return nil, fmt.Errorf("ci pipeline not found ")
@@ -18,6 +18,7 @@
 package batch
 
 import (
+	"context"
 	"fmt"
 	"github.com/devtron-labs/devtron/internal/sql/repository/app"
 	"github.com/devtron-labs/devtron/internal/sql/repository/appWorkflow"
@@ -222,7 +223,7 @@ func executeBuildCreate(impl BuildActionImpl, build *v1.Build) error {
 		AppWorkflowId: workflowId,
 		UserId:        1,
 	}
-	_, err = impl.pipelineBuilder.PatchCiPipeline(&ciRequest)
+	_, err = impl.pipelineBuilder.PatchCiPipeline(&ciRequest, context.Background())
 	if err != nil {
 		return fmt.Errorf("unable to create ci pipeline error %s", err.Error())
 	}
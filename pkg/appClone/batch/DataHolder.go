@@ -18,6 +18,7 @@
 package batch
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/devtron-labs/devtron/internal/sql/repository/app"
@@ -146,21 +147,21 @@ func executeDataHolderClone(impl DataHolderActionImpl, holder *v1.DataHolder, da
 
 	if strings.ToLower(dataType) == v1.ConfigMap {
 		if envDest != nil {
-			if configData, err = impl.configMapService.CMEnvironmentAddUpdate(configData); err != nil {
+			if configData, err = impl.configMapService.CMEnvironmentAddUpdate(configData, context.Background()); err != nil {
 				return err
 			}
 		} else {
-			if configData, err = impl.configMapService.CMGlobalAddUpdate(configData); err != nil {
+			if configData, err = impl.configMapService.CMGlobalAddUpdate(configData, context.Background()); err != nil {
 				return err
 			}
 		}
 	} else {
 		if envDest != nil {
-			if configData, err = impl.configMapService.CSEnvironmentAddUpdate(configData); err != nil {
+			if configData, err = impl.configMapService.CSEnvironmentAddUpdate(configData, context.Background()); err != nil {
 				return err
 			}
 		} else {
-			if configData, err = impl.configMapService.CSGlobalAddUpdate(configData); err != nil {
+			if configData, err = impl.configMapService.CSGlobalAddUpdate(configData, context.Background()); err != nil {
 				return err
 			}
 		}
@@ -300,7 +301,7 @@ func executeDataHolderDelete(impl DataHolderActionImpl, holder *v1.DataHolder, d
 	return nil
 }
 
-func deleteKeys(fetch func() (*bean.ConfigDataRequest, error), save func(request *bean.ConfigDataRequest) (*bean.ConfigDataRequest, error), holder *v1.DataHolder, dataType string) error {
+func deleteKeys(fetch func() (*bean.ConfigDataRequest, error), save func(request *bean.ConfigDataRequest, ctx context.Context) (*bean.ConfigDataRequest, error), holder *v1.DataHolder, dataType string) error {
 	configData, err := fetch()
 	if err != nil {
 		return err
@@ -313,7 +314,7 @@ func deleteKeys(fetch func() (*bean.ConfigDataRequest, error), save func(request
 	} else {
 		return fmt.Errorf("configdata missing for %s", dataType)
 	}
-	_, err = save(configData)
+	_, err = save(configData, context.Background())
 	if err != nil {
 		return err
 	}
@@ -417,7 +418,7 @@ func executeDataHolderCreate(impl DataHolderActionImpl, holder *v1.DataHolder, d
 			if err == nil {
 				configData.Id = d.Id
 			}
-			if configData, err = impl.configMapService.CMEnvironmentAddUpdate(configData); err != nil {
+			if configData, err = impl.configMapService.CMEnvironmentAddUpdate(configData, context.Background()); err != nil {
 				return fmt.Errorf("error `%s` creating %s name %s", err.Error(), dataType, name)
 			}
 		} else {
@@ -425,7 +426,7 @@ func executeDataHolderCreate(impl DataHolderActionImpl, holder *v1.DataHolder, d
 			if err == nil {
 				configData.Id = d.Id
 			}
-			if configData, err = impl.configMapService.CMGlobalAddUpdate(configData); err != nil {
+			if configData, err = impl.configMapService.CMGlobalAddUpdate(configData, context.Background()); err != nil {
 				return fmt.Errorf("error `%s` creating %s name %s", err.Error(), dataType, name)
 			}
 		}
@@ -435,7 +436,7 @@ func executeDataHolderCreate(impl DataHolderActionImpl, holder *v1.DataHolder, d
 			if err == nil {
 				configData.Id = d.Id
 			}
-			if configData, err = impl.configMapService.CSEnvironmentAddUpdate(configData); err != nil {
+			if configData, err = impl.configMapService.CSEnvironmentAddUpdate(configData, context.Background()); err != nil {
 				return fmt.Errorf("error `%s` creating %s name %s", err.Error(), dataType, name)
 			}
 		} else {
@@ -443,7 +444,7 @@ func executeDataHolderCreate(impl DataHolderActionImpl, holder *v1.DataHolder, d
 			if err == nil {
 				configData.Id = d.Id
 			}
-			if configData, err = impl.configMapService.CSGlobalAddUpdate(configData); err != nil {
+			if configData, err = impl.configMapService.CSGlobalAddUpdate(configData, context.Background()); err != nil {
 				return fmt.Errorf("error `%s` creating %s name %s", err.Error(), dataType, name)
 			}
 		}
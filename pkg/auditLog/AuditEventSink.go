@@ -0,0 +1,23 @@
+package auditLog
+
+const (
+	SinkNameSyslog  = "syslog"
+	SinkNameWebhook = "webhook"
+	SinkNameNats    = "nats"
+)
+
+// AuditEventSink delivers audit events to an external system, Send must return an error unless all events are accepted
+// by the sink, events of a failed batch are sent again so sinks should tolerate duplicates using the event id
+type AuditEventSink interface {
+	Name() string
+	Send(events []*AuditEventDto) error
+}
+
+// AuditLogSinkStatus is the delivery state of a sink since startup of this instance
+type AuditLogSinkStatus struct {
+	Name            string `json:"name"`
+	LastEventId     int    `json:"lastEventId"`
+	LastDeliveredOn string `json:"lastDeliveredOn,omitempty"`
+	LastError       string `json:"lastError,omitempty"`
+	LastErrorOn     string `json:"lastErrorOn,omitempty"`
+}
@@ -0,0 +1,28 @@
+package auditLog
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComputeWebhookSignature(t *testing.T) {
+	// echo -n '1700000000.[{"id":1}]' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "c8fda07025d4d53990a5b4dea760bf287c368853edcc08dc53ac0b8d4c811f7a",
+		ComputeWebhookSignature("secret", "1700000000", []byte(`[{"id":1}]`)))
+}
+
+func TestSyslogFormatMessage(t *testing.T) {
+	sink := &SyslogSink{appName: "devtron", hostname: "orchestrator-0"}
+	message, err := sink.formatMessage(&AuditEventDto{
+		Id:           7,
+		Action:       ActionUpdate,
+		ResourceType: ResourceTypeCluster,
+		CreatedOn:    time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC),
+	})
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(message, "<110>1 2023-11-14T22:13:20.000000Z orchestrator-0 devtron - update - {\"id\":7,"), message)
+	assert.Equal(t, "-", toSyslogHeaderValue(" ", 32))
+	assert.Equal(t, "appn", toSyslogHeaderValue("app name", 4))
+}
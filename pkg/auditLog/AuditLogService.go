@@ -0,0 +1,320 @@
+package auditLog
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/pkg/auditLog/repository"
+	repository2 "github.com/devtron-labs/devtron/pkg/user/repository"
+	"go.uber.org/zap"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apiTokenUserEmailPrefix is the email prefix of users backing api-tokens, see apiToken.API_TOKEN_USER_EMAIL_PREFIX
+const apiTokenUserEmailPrefix = "API-TOKEN:"
+
+type AuditLogConfig struct {
+	// WriteBufferSize is the number of audit events queued for writing, a request blocks on writing its event once it is full
+	WriteBufferSize int `env:"AUDIT_LOG_WRITE_BUFFER_SIZE" envDefault:"1000"`
+	// StopTimeoutSecs is the time given to write queued events on shutdown
+	StopTimeoutSecs int `env:"AUDIT_LOG_STOP_TIMEOUT_SECS" envDefault:"10"`
+}
+
+type AuditLogService interface {
+	// RecordEvent is the hook for services to record a mutation of a resource, failures are logged and never fail the caller.
+	// ctx of the http request gets the event the request's source ip and url, see WithRequestInfo
+	RecordEvent(ctx context.Context, request *AuditEventRequest)
+	// RecordRequest records a mutating http request, captured by the audit log middleware
+	RecordRequest(requestInfo *RequestInfo, responseCode int)
+	GetEvents(filter *AuditEventFilter) (*AuditEventListResponse, error)
+	// ExportEvents returns all events matching the filter ignoring its paging, capped at MaxExportSize
+	ExportEvents(filter *AuditEventFilter) ([]*AuditEventDto, error)
+	// Stop writes the queued events, events recorded after stop are written synchronously
+	Stop()
+}
+
+// AuditLogServiceImpl writes events in the background so that recording them does not add db round trips to requests
+type AuditLogServiceImpl struct {
+	logger               *zap.SugaredLogger
+	auditEventRepository repository.AuditEventRepository
+	userRepository       repository2.UserRepository
+	config               *AuditLogConfig
+	stopLock             *sync.RWMutex
+	stopped              bool
+	events               chan *repository.AuditEvent
+	writerDone           chan struct{}
+}
+
+func NewAuditLogServiceImpl(logger *zap.SugaredLogger, auditEventRepository repository.AuditEventRepository,
+	userRepository repository2.UserRepository) (*AuditLogServiceImpl, error) {
+	config := &AuditLogConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing AuditLogConfig from env", "err", err)
+		return nil, err
+	}
+	if config.WriteBufferSize <= 0 {
+		config.WriteBufferSize = 1000
+	}
+	impl := &AuditLogServiceImpl{
+		logger:               logger,
+		auditEventRepository: auditEventRepository,
+		userRepository:       userRepository,
+		config:               config,
+		stopLock:             &sync.RWMutex{},
+		events:               make(chan *repository.AuditEvent, config.WriteBufferSize),
+		writerDone:           make(chan struct{}),
+	}
+	go impl.writeEvents()
+	return impl, nil
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo returns a copy of ctx carrying the http request in which audit events are recorded
+func WithRequestInfo(ctx context.Context, requestInfo *RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, requestInfo)
+}
+
+func getRequestInfo(ctx context.Context) *RequestInfo {
+	if ctx == nil {
+		return nil
+	}
+	requestInfo, _ := ctx.Value(requestInfoKey{}).(*RequestInfo)
+	return requestInfo
+}
+
+func (impl *AuditLogServiceImpl) RecordEvent(ctx context.Context, request *AuditEventRequest) {
+	event := &repository.AuditEvent{
+		Action:       request.Action,
+		ResourceType: request.ResourceType,
+		ResourceId:   request.ResourceId,
+		ResourceName: request.ResourceName,
+		UserId:       request.UserId,
+		CreatedOn:    time.Now(),
+	}
+	if requestInfo := getRequestInfo(ctx); requestInfo != nil {
+		event.SourceIp = requestInfo.SourceIp
+		event.HttpMethod = requestInfo.HttpMethod
+		event.UrlPath = requestInfo.UrlPath
+		if request.UserId == 0 {
+			event.EmailId = requestInfo.EmailId
+		}
+	}
+	// states are serialized here as callers are free to modify them once the event is recorded
+	before, err := toMaskedState(request.Before)
+	if err != nil {
+		impl.logger.Errorw("error in serializing before state of audit event", "request", request, "err", err)
+	}
+	after, err := toMaskedState(request.After)
+	if err != nil {
+		impl.logger.Errorw("error in serializing after state of audit event", "request", request, "err", err)
+	}
+	event.BeforeState = toJsonString(before)
+	event.AfterState = toJsonString(after)
+	if diff := computeDiff(before, after); len(diff) > 0 {
+		event.Diff = toJsonString(diff)
+	}
+	impl.save(event)
+}
+
+func (impl *AuditLogServiceImpl) RecordRequest(requestInfo *RequestInfo, responseCode int) {
+	resourceType, resourceId := getResourceFromUrlPath(requestInfo.UrlPath)
+	event := &repository.AuditEvent{
+		Action:       ActionRequest,
+		ResourceType: resourceType,
+		ResourceId:   resourceId,
+		EmailId:      requestInfo.EmailId,
+		SourceIp:     requestInfo.SourceIp,
+		HttpMethod:   requestInfo.HttpMethod,
+		UrlPath:      requestInfo.UrlPath,
+		ResponseCode: responseCode,
+		CreatedOn:    time.Now(),
+	}
+	impl.save(event)
+}
+
+func (impl *AuditLogServiceImpl) GetEvents(filter *AuditEventFilter) (*AuditEventListResponse, error) {
+	size := filter.Size
+	if size <= 0 {
+		size = DefaultPageSize
+	} else if size > MaxPageSize {
+		size = MaxPageSize
+	}
+	events, totalCount, err := impl.auditEventRepository.FindByFilter(&repository.AuditEventFilter{
+		Action:       filter.Action,
+		ResourceType: filter.ResourceType,
+		ResourceId:   filter.ResourceId,
+		UserId:       filter.UserId,
+		EmailId:      filter.EmailId,
+		ApiTokenId:   filter.ApiTokenId,
+		From:         filter.From,
+		To:           filter.To,
+		Offset:       filter.Offset,
+		Size:         size,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in fetching audit events", "filter", filter, "err", err)
+		return nil, err
+	}
+	response := &AuditEventListResponse{TotalCount: totalCount, Events: make([]*AuditEventDto, 0, len(events))}
+	for _, event := range events {
+		response.Events = append(response.Events, toAuditEventDto(event, impl.logger))
+	}
+	return response, nil
+}
+
+func (impl *AuditLogServiceImpl) ExportEvents(filter *AuditEventFilter) ([]*AuditEventDto, error) {
+	pageFilter := *filter
+	pageFilter.Offset = 0
+	pageFilter.Size = MaxPageSize
+	var events []*AuditEventDto
+	for len(events) < MaxExportSize {
+		page, err := impl.GetEvents(&pageFilter)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, page.Events...)
+		if len(page.Events) < pageFilter.Size {
+			break
+		}
+		pageFilter.Offset += pageFilter.Size
+	}
+	if len(events) > MaxExportSize {
+		events = events[:MaxExportSize]
+	}
+	return events, nil
+}
+
+func toAuditEventDto(event *repository.AuditEvent, logger *zap.SugaredLogger) *AuditEventDto {
+	dto := &AuditEventDto{
+		Id:           event.Id,
+		Action:       event.Action,
+		ResourceType: event.ResourceType,
+		ResourceId:   event.ResourceId,
+		ResourceName: event.ResourceName,
+		UserId:       event.UserId,
+		EmailId:      event.EmailId,
+		ApiTokenId:   event.ApiTokenId,
+		SourceIp:     event.SourceIp,
+		HttpMethod:   event.HttpMethod,
+		UrlPath:      event.UrlPath,
+		ResponseCode: event.ResponseCode,
+		CreatedOn:    event.CreatedOn,
+	}
+	if len(event.Diff) > 0 {
+		err := json.Unmarshal([]byte(event.Diff), &dto.Diff)
+		if err != nil {
+			logger.Warnw("error in parsing diff of audit event", "id", event.Id, "err", err)
+		}
+	}
+	return dto
+}
+
+func (impl *AuditLogServiceImpl) Stop() {
+	impl.stopLock.Lock()
+	if impl.stopped {
+		impl.stopLock.Unlock()
+		return
+	}
+	impl.stopped = true
+	close(impl.events)
+	impl.stopLock.Unlock()
+	select {
+	case <-impl.writerDone:
+	case <-time.After(time.Duration(impl.config.StopTimeoutSecs) * time.Second):
+		impl.logger.Errorw("timed out in writing queued audit events", "pending", len(impl.events))
+	}
+}
+
+// save queues the event for writing, the event is written by the caller once the service is stopped
+func (impl *AuditLogServiceImpl) save(event *repository.AuditEvent) {
+	impl.stopLock.RLock()
+	if !impl.stopped {
+		impl.events <- event
+		impl.stopLock.RUnlock()
+		return
+	}
+	impl.stopLock.RUnlock()
+	impl.write(event)
+}
+
+func (impl *AuditLogServiceImpl) writeEvents() {
+	defer close(impl.writerDone)
+	for event := range impl.events {
+		impl.write(event)
+	}
+}
+
+func (impl *AuditLogServiceImpl) write(event *repository.AuditEvent) {
+	if event.UserId > 0 && len(event.EmailId) == 0 {
+		user, err := impl.userRepository.GetByIdIncludeDeleted(event.UserId)
+		if err != nil {
+			impl.logger.Warnw("error in fetching user for audit event", "userId", event.UserId, "err", err)
+		} else {
+			event.EmailId = user.EmailId
+		}
+	} else if event.UserId == 0 && len(event.EmailId) > 0 {
+		user, err := impl.userRepository.FetchActiveOrDeletedUserByEmail(event.EmailId)
+		if err == nil && user != nil {
+			event.UserId = user.Id
+		}
+	}
+	if len(event.EmailId) > len(apiTokenUserEmailPrefix) && strings.EqualFold(event.EmailId[:len(apiTokenUserEmailPrefix)], apiTokenUserEmailPrefix) {
+		apiTokenId, err := impl.auditEventRepository.FindApiTokenIdByName(event.EmailId[len(apiTokenUserEmailPrefix):])
+		if err != nil {
+			impl.logger.Warnw("error in fetching api token for audit event", "emailId", event.EmailId, "err", err)
+		}
+		event.ApiTokenId = apiTokenId
+	}
+	err := impl.auditEventRepository.Save(event)
+	if err != nil {
+		impl.logger.Errorw("error in saving audit event", "action", event.Action, "resourceType", event.ResourceType,
+			"resourceId", event.ResourceId, "emailId", event.EmailId, "err", err)
+	}
+}
+
+// getResourceFromUrlPath derives the resource of a request from its path, e.g. /orchestrator/cluster/12/... is cluster 12
+func getResourceFromUrlPath(urlPath string) (string, string) {
+	segments := strings.Split(strings.Trim(urlPath, "/"), "/")
+	if len(segments) > 0 && segments[0] == "orchestrator" {
+		segments = segments[1:]
+	}
+	var resourceType, resourceId string
+	for _, segment := range segments {
+		if len(resourceType) == 0 {
+			resourceType = segment
+			continue
+		}
+		if isNumeric(segment) {
+			resourceId = segment
+			break
+		}
+	}
+	return resourceType, resourceId
+}
+
+func isNumeric(value string) bool {
+	if len(value) == 0 {
+		return false
+	}
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func toJsonString(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	valueJson, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(valueJson)
+}
@@ -0,0 +1,61 @@
+package auditLog
+
+import (
+	"context"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auditLog/repository"
+	repository2 "github.com/devtron-labs/devtron/pkg/user/repository"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type auditEventRepositoryStub struct {
+	repository.AuditEventRepository
+	events []*repository.AuditEvent
+}
+
+func (impl *auditEventRepositoryStub) Save(event *repository.AuditEvent) error {
+	impl.events = append(impl.events, event)
+	return nil
+}
+
+type userRepositoryStub struct {
+	repository2.UserRepository
+}
+
+func (impl *userRepositoryStub) FetchActiveOrDeletedUserByEmail(email string) (*repository2.UserModel, error) {
+	return &repository2.UserModel{Id: 2, EmailId: email}, nil
+}
+
+func TestRecordEventWithRequestInfo(t *testing.T) {
+	logger, _ := util.NewSugardLogger()
+	auditEventRepository := &auditEventRepositoryStub{}
+	impl, err := NewAuditLogServiceImpl(logger, auditEventRepository, &userRepositoryStub{})
+	assert.Nil(t, err)
+
+	ctx := WithRequestInfo(context.Background(), &RequestInfo{EmailId: "admin@example.com", SourceIp: "203.0.113.7",
+		HttpMethod: "PUT", UrlPath: "/orchestrator/config/global/cs"})
+	state := map[string]interface{}{"name": "db", "password": "old"}
+	impl.RecordEvent(ctx, &AuditEventRequest{Action: ActionUpdate, ResourceType: ResourceTypeSecret, ResourceId: "1",
+		Before: state, After: map[string]interface{}{"name": "db", "password": "new"}})
+	// the recorded state is serialized before the event is queued
+	state["name"] = "changed"
+	impl.Stop()
+
+	assert.Len(t, auditEventRepository.events, 1)
+	event := auditEventRepository.events[0]
+	assert.Equal(t, "203.0.113.7", event.SourceIp)
+	assert.Equal(t, "PUT", event.HttpMethod)
+	assert.Equal(t, "/orchestrator/config/global/cs", event.UrlPath)
+	assert.Equal(t, "admin@example.com", event.EmailId)
+	assert.Equal(t, int32(2), event.UserId)
+	assert.True(t, strings.Contains(event.BeforeState, `"name":"db"`), event.BeforeState)
+	assert.False(t, strings.Contains(event.BeforeState, "old"), event.BeforeState)
+	assert.False(t, strings.Contains(event.AfterState, "new"), event.AfterState)
+
+	// events recorded after stop are written by the caller
+	impl.RecordEvent(context.Background(), &AuditEventRequest{Action: ActionDelete, ResourceType: ResourceTypeSecret, ResourceId: "1"})
+	assert.Len(t, auditEventRepository.events, 2)
+	assert.Empty(t, auditEventRepository.events[1].SourceIp)
+}
@@ -0,0 +1,221 @@
+package auditLog
+
+import (
+	"github.com/caarlos0/env"
+	pubsub "github.com/devtron-labs/common-lib/pubsub-lib"
+	"github.com/devtron-labs/devtron/pkg/auditLog/repository"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+type AuditLogStreamConfig struct {
+	SyslogAddress               string `env:"AUDIT_LOG_SYSLOG_ADDRESS" envDefault:""`
+	SyslogAppName               string `env:"AUDIT_LOG_SYSLOG_APP_NAME" envDefault:"devtron"`
+	SyslogTlsEnabled            bool   `env:"AUDIT_LOG_SYSLOG_TLS_ENABLED" envDefault:"false"`
+	SyslogTlsCaCert             string `env:"AUDIT_LOG_SYSLOG_TLS_CA_CERT" envDefault:""`
+	SyslogTlsInsecureSkipVerify bool   `env:"AUDIT_LOG_SYSLOG_TLS_INSECURE_SKIP_VERIFY" envDefault:"false"`
+	WebhookUrl                  string `env:"AUDIT_LOG_WEBHOOK_URL" envDefault:""`
+	WebhookSecret               string `env:"AUDIT_LOG_WEBHOOK_SECRET" envDefault:""`
+	NatsSubject                 string `env:"AUDIT_LOG_NATS_SUBJECT" envDefault:""`
+	NatsStreamName              string `env:"AUDIT_LOG_NATS_STREAM_NAME" envDefault:"AUDIT_LOG"`
+	BatchSize                   int    `env:"AUDIT_LOG_STREAM_BATCH_SIZE" envDefault:"100"`
+	PollIntervalSecs            int    `env:"AUDIT_LOG_STREAM_POLL_INTERVAL_SECS" envDefault:"5"`
+	MaxRetryBackoffSecs         int    `env:"AUDIT_LOG_STREAM_MAX_RETRY_BACKOFF_SECS" envDefault:"300"`
+	// StreamExistingEvents delivers events recorded before a sink was first enabled, otherwise a new sink starts at the latest event
+	StreamExistingEvents bool `env:"AUDIT_LOG_STREAM_EXISTING_EVENTS" envDefault:"false"`
+}
+
+type AuditLogStreamService interface {
+	GetSinkStatus() []*AuditLogSinkStatus
+}
+
+// AuditLogStreamServiceImpl forwards audit events to the configured sinks, each sink has its own cursor which is moved
+// only after a batch is accepted by the sink, so events are delivered at least once across restarts and sink outages.
+// Events are paged by their stream sequence, which is assigned after commit, and only the replica holding the sink's
+// advisory lock delivers a batch, so replicas neither skip nor duplicate events.
+type AuditLogStreamServiceImpl struct {
+	logger                         *zap.SugaredLogger
+	auditEventRepository           repository.AuditEventRepository
+	auditEventSinkCursorRepository repository.AuditEventSinkCursorRepository
+	config                         *AuditLogStreamConfig
+	sinks                          []AuditEventSink
+	statusLock                     *sync.RWMutex
+	status                         map[string]*AuditLogSinkStatus
+}
+
+func NewAuditLogStreamServiceImpl(logger *zap.SugaredLogger, auditEventRepository repository.AuditEventRepository,
+	auditEventSinkCursorRepository repository.AuditEventSinkCursorRepository,
+	pubsubClient *pubsub.PubSubClientServiceImpl) (*AuditLogStreamServiceImpl, error) {
+	config := &AuditLogStreamConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing AuditLogStreamConfig from env", "err", err)
+		return nil, err
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.PollIntervalSecs <= 0 {
+		config.PollIntervalSecs = 5
+	}
+	impl := &AuditLogStreamServiceImpl{
+		logger:                         logger,
+		auditEventRepository:           auditEventRepository,
+		auditEventSinkCursorRepository: auditEventSinkCursorRepository,
+		config:                         config,
+		statusLock:                     &sync.RWMutex{},
+		status:                         make(map[string]*AuditLogSinkStatus),
+	}
+	if len(config.SyslogAddress) > 0 {
+		syslogSink, err := NewSyslogSink(config)
+		if err != nil {
+			logger.Errorw("error in creating syslog audit log sink", "err", err)
+			return nil, err
+		}
+		impl.sinks = append(impl.sinks, syslogSink)
+	}
+	if len(config.WebhookUrl) > 0 {
+		impl.sinks = append(impl.sinks, NewWebhookSink(config))
+	}
+	if len(config.NatsSubject) > 0 {
+		impl.sinks = append(impl.sinks, NewNatsSink(config, pubsubClient))
+	}
+	for _, sink := range impl.sinks {
+		impl.status[sink.Name()] = &AuditLogSinkStatus{Name: sink.Name()}
+		go impl.stream(sink)
+	}
+	return impl, nil
+}
+
+func (impl *AuditLogStreamServiceImpl) GetSinkStatus() []*AuditLogSinkStatus {
+	impl.statusLock.RLock()
+	defer impl.statusLock.RUnlock()
+	statuses := make([]*AuditLogSinkStatus, 0, len(impl.sinks))
+	for _, sink := range impl.sinks {
+		status := *impl.status[sink.Name()]
+		statuses = append(statuses, &status)
+	}
+	return statuses
+}
+
+func (impl *AuditLogStreamServiceImpl) stream(sink AuditEventSink) {
+	pollInterval := time.Duration(impl.config.PollIntervalSecs) * time.Second
+	maxBackoff := time.Duration(impl.config.MaxRetryBackoffSecs) * time.Second
+	if maxBackoff < pollInterval {
+		maxBackoff = pollInterval
+	}
+	backoff := pollInterval
+	for {
+		delivered, err := impl.deliverNextBatch(sink)
+		if err != nil {
+			impl.logger.Errorw("error in streaming audit events to sink, retrying", "sink", sink.Name(), "retryAfter", backoff, "err", err)
+			impl.updateStatus(sink.Name(), 0, err)
+			time.Sleep(backoff)
+			backoff = backoff * 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			continue
+		}
+		backoff = pollInterval
+		if delivered < impl.config.BatchSize {
+			time.Sleep(pollInterval)
+		}
+	}
+}
+
+// deliverNextBatch sends the events after the sink's cursor and moves the cursor past them, returns the number of events delivered.
+// The sink's advisory lock is held by the transaction till the cursor is moved, a replica not getting it skips the batch.
+func (impl *AuditLogStreamServiceImpl) deliverNextBatch(sink AuditEventSink) (int, error) {
+	_, err := impl.auditEventRepository.SequenceEvents(impl.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	tx, err := impl.auditEventSinkCursorRepository.GetConnection().Begin()
+	if err != nil {
+		return 0, err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	locked, err := impl.auditEventSinkCursorRepository.TryLockSink(sink.Name(), tx)
+	if err != nil || !locked {
+		return 0, err
+	}
+	cursor, err := impl.getCursor(sink.Name(), tx)
+	if err != nil {
+		return 0, err
+	}
+	events, err := impl.auditEventRepository.FindAfterStreamSeq(cursor.LastStreamSeq, impl.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		// commits the cursor of a new sink
+		return 0, tx.Commit()
+	}
+	dtos := make([]*AuditEventDto, 0, len(events))
+	for _, event := range events {
+		dtos = append(dtos, toAuditEventDto(event, impl.logger))
+	}
+	err = sink.Send(dtos)
+	if err != nil {
+		return 0, err
+	}
+	cursor.LastStreamSeq = events[len(events)-1].StreamSeq
+	cursor.UpdatedOn = time.Now()
+	err = impl.auditEventSinkCursorRepository.Update(cursor, tx)
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		// batch is sent again on the next attempt
+		return 0, err
+	}
+	impl.updateStatus(sink.Name(), events[len(events)-1].Id, nil)
+	return len(events), nil
+}
+
+func (impl *AuditLogStreamServiceImpl) getCursor(sinkName string, tx *pg.Tx) (*repository.AuditEventSinkCursor, error) {
+	cursor, err := impl.auditEventSinkCursorRepository.FindBySinkName(sinkName, tx)
+	if err == nil {
+		return cursor, nil
+	} else if err != pg.ErrNoRows {
+		return nil, err
+	}
+	cursor = &repository.AuditEventSinkCursor{SinkName: sinkName, UpdatedOn: time.Now()}
+	if !impl.config.StreamExistingEvents {
+		// events recorded before the sink was enabled are sequenced first so that the sink starts after them
+		for sequenced := impl.config.BatchSize; sequenced == impl.config.BatchSize; {
+			sequenced, err = impl.auditEventRepository.SequenceEvents(impl.config.BatchSize)
+			if err != nil {
+				return nil, err
+			}
+		}
+		cursor.LastStreamSeq, err = impl.auditEventRepository.FindLatestStreamSeq()
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = impl.auditEventSinkCursorRepository.Save(cursor, tx)
+	if err != nil {
+		return nil, err
+	}
+	impl.logger.Infow("created audit log sink cursor", "sink", sinkName, "lastStreamSeq", cursor.LastStreamSeq)
+	return cursor, nil
+}
+
+func (impl *AuditLogStreamServiceImpl) updateStatus(sinkName string, lastEventId int, err error) {
+	impl.statusLock.Lock()
+	defer impl.statusLock.Unlock()
+	status := impl.status[sinkName]
+	now := time.Now().Format(time.RFC3339)
+	if err != nil {
+		status.LastError = err.Error()
+		status.LastErrorOn = now
+		return
+	}
+	status.LastEventId = lastEventId
+	status.LastDeliveredOn = now
+}
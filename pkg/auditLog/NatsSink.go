@@ -0,0 +1,71 @@
+package auditLog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	pubsub "github.com/devtron-labs/common-lib/pubsub-lib"
+	"github.com/nats-io/nats.go"
+)
+
+// NatsSink publishes every event to a jet stream subject through the shared pubsub client, the event id is used as the
+// message id so that redelivered events are de-duplicated by jet stream
+type NatsSink struct {
+	pubsubClient *pubsub.PubSubClientServiceImpl
+	subject      string
+	streamName   string
+	streamReady  bool
+}
+
+func NewNatsSink(config *AuditLogStreamConfig, pubsubClient *pubsub.PubSubClientServiceImpl) *NatsSink {
+	return &NatsSink{
+		pubsubClient: pubsubClient,
+		subject:      config.NatsSubject,
+		streamName:   config.NatsStreamName,
+	}
+}
+
+func (sink *NatsSink) Name() string {
+	return SinkNameNats
+}
+
+func (sink *NatsSink) Send(events []*AuditEventDto) error {
+	if sink.pubsubClient == nil || sink.pubsubClient.NatsClient == nil || sink.pubsubClient.NatsClient.JetStrCtxt == nil {
+		return fmt.Errorf("nats client is not connected")
+	}
+	jetStrCtxt := sink.pubsubClient.NatsClient.JetStrCtxt
+	if !sink.streamReady {
+		err := sink.ensureStream(jetStrCtxt)
+		if err != nil {
+			return err
+		}
+		sink.streamReady = true
+	}
+	for _, event := range events {
+		eventJson, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		_, err = jetStrCtxt.Publish(sink.subject, eventJson, nats.MsgId(fmt.Sprintf("audit-event-%d", event.Id)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureStream creates the stream for the audit subject, subjects of the shared streams are fixed by the pubsub library
+func (sink *NatsSink) ensureStream(jetStrCtxt nats.JetStreamContext) error {
+	_, err := jetStrCtxt.StreamInfo(sink.streamName)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, nats.ErrStreamNotFound) {
+		return err
+	}
+	_, err = jetStrCtxt.AddStream(&nats.StreamConfig{
+		Name:     sink.streamName,
+		Subjects: []string{sink.subject},
+	})
+	return err
+}
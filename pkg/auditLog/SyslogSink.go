@@ -0,0 +1,129 @@
+package auditLog
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// syslogPriority is facility log audit (13) with severity informational (6)
+	syslogPriority     = 13*8 + 6
+	syslogVersion      = 1
+	syslogNilValue     = "-"
+	syslogDialTimeout  = 10 * time.Second
+	syslogWriteTimeout = 30 * time.Second
+)
+
+// SyslogSink sends events as RFC 5424 messages over tcp or tls, messages are framed with octet counting (RFC 6587)
+type SyslogSink struct {
+	address   string
+	appName   string
+	hostname  string
+	tlsConfig *tls.Config
+	lock      *sync.Mutex
+	conn      net.Conn
+}
+
+func NewSyslogSink(config *AuditLogStreamConfig) (*SyslogSink, error) {
+	hostname, err := os.Hostname()
+	if err != nil || len(hostname) == 0 {
+		hostname = syslogNilValue
+	}
+	sink := &SyslogSink{
+		address:  config.SyslogAddress,
+		appName:  config.SyslogAppName,
+		hostname: hostname,
+		lock:     &sync.Mutex{},
+	}
+	if config.SyslogTlsEnabled {
+		sink.tlsConfig = &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: config.SyslogTlsInsecureSkipVerify,
+		}
+		if len(config.SyslogTlsCaCert) > 0 {
+			certPool := x509.NewCertPool()
+			if !certPool.AppendCertsFromPEM([]byte(config.SyslogTlsCaCert)) {
+				return nil, fmt.Errorf("invalid syslog ca certificate")
+			}
+			sink.tlsConfig.RootCAs = certPool
+		}
+	}
+	return sink, nil
+}
+
+func (sink *SyslogSink) Name() string {
+	return SinkNameSyslog
+}
+
+func (sink *SyslogSink) Send(events []*AuditEventDto) error {
+	sink.lock.Lock()
+	defer sink.lock.Unlock()
+	if sink.conn == nil {
+		conn, err := sink.dial()
+		if err != nil {
+			return err
+		}
+		sink.conn = conn
+	}
+	var frames strings.Builder
+	for _, event := range events {
+		message, err := sink.formatMessage(event)
+		if err != nil {
+			return err
+		}
+		frames.WriteString(fmt.Sprintf("%d %s", len(message), message))
+	}
+	_ = sink.conn.SetWriteDeadline(time.Now().Add(syslogWriteTimeout))
+	_, err := sink.conn.Write([]byte(frames.String()))
+	if err != nil {
+		// connection is re-established on the next batch
+		_ = sink.conn.Close()
+		sink.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (sink *SyslogSink) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: syslogDialTimeout}
+	if sink.tlsConfig != nil {
+		return tls.DialWithDialer(dialer, "tcp", sink.address, sink.tlsConfig)
+	}
+	return dialer.Dial("tcp", sink.address)
+}
+
+// formatMessage formats event as <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG with the event json as MSG
+func (sink *SyslogSink) formatMessage(event *AuditEventDto) (string, error) {
+	eventJson, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("<%d>%d %s %s %s %s %s %s %s", syslogPriority, syslogVersion,
+		event.CreatedOn.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		toSyslogHeaderValue(sink.hostname, 255), toSyslogHeaderValue(sink.appName, 48), syslogNilValue,
+		toSyslogHeaderValue(event.Action, 32), syslogNilValue, string(eventJson)), nil
+}
+
+// toSyslogHeaderValue restricts value to the printable ascii characters allowed in header fields
+func toSyslogHeaderValue(value string, maxLength int) string {
+	var headerValue strings.Builder
+	for _, c := range value {
+		if c > 32 && c < 127 {
+			headerValue.WriteRune(c)
+		}
+	}
+	if headerValue.Len() == 0 {
+		return syslogNilValue
+	}
+	if headerValue.Len() > maxLength {
+		return headerValue.String()[:maxLength]
+	}
+	return headerValue.String()
+}
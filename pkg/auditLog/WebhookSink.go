@@ -0,0 +1,78 @@
+package auditLog
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	WebhookSignatureHeader  = "X-Devtron-Signature"
+	WebhookTimestampHeader  = "X-Devtron-Timestamp"
+	WebhookDeliveryIdHeader = "X-Devtron-Delivery-Id"
+	webhookTimeout          = 30 * time.Second
+)
+
+// WebhookSink posts batches of events as a json array, when a secret is configured the request is signed with
+// hex(HMAC-SHA256(secret, timestamp + "." + body)) in WebhookSignatureHeader as "sha256=<signature>"
+type WebhookSink struct {
+	url        string
+	secret     string
+	httpClient *http.Client
+}
+
+func NewWebhookSink(config *AuditLogStreamConfig) *WebhookSink {
+	return &WebhookSink{
+		url:        config.WebhookUrl,
+		secret:     config.WebhookSecret,
+		httpClient: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+func (sink *WebhookSink) Name() string {
+	return SinkNameWebhook
+}
+
+func (sink *WebhookSink) Send(events []*AuditEventDto) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, sink.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	// same batch is retried with the same delivery id so that receivers can de-duplicate
+	req.Header.Set(WebhookDeliveryIdHeader, fmt.Sprintf("%d-%d", events[0].Id, events[len(events)-1].Id))
+	if len(sink.secret) > 0 {
+		req.Header.Set(WebhookSignatureHeader, "sha256="+ComputeWebhookSignature(sink.secret, timestamp, body))
+	}
+	resp, err := sink.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("audit log webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func ComputeWebhookSignature(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
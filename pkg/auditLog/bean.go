@@ -0,0 +1,92 @@
+package auditLog
+
+import "time"
+
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRotate  = "rotate"
+	ActionRequest = "request"
+
+	ResourceTypeUser               = "user"
+	ResourceTypeApiToken           = "api-token"
+	ResourceTypeCluster            = "cluster"
+	ResourceTypeKubernetesResource = "kubernetes-resource"
+	ResourceTypeApp                = "app"
+	ResourceTypeCiPipeline         = "ci-pipeline"
+	ResourceTypeCdPipeline         = "cd-pipeline"
+	ResourceTypeConfigMap          = "config-map"
+	ResourceTypeSecret             = "secret"
+	ResourceTypeDeploymentTemplate = "deployment-template"
+	ResourceTypeNamespacePolicy    = "namespace-policy"
+	ResourceTypeCvePolicy          = "cve-policy"
+
+	DefaultPageSize = 20
+	MaxPageSize     = 1000
+	// MaxExportSize caps the number of events in a single export
+	MaxExportSize = 50000
+
+	ExportFormatJson = "json"
+	ExportFormatCsv  = "csv"
+)
+
+// AuditEventRequest is recorded by service hooks, Before and After are any json serializable state of the resource
+type AuditEventRequest struct {
+	Action       string
+	ResourceType string
+	ResourceId   string
+	ResourceName string
+	UserId       int32
+	Before       interface{}
+	After        interface{}
+}
+
+// RequestInfo is the http request in which audit events are being recorded, captured by the audit log middleware
+type RequestInfo struct {
+	EmailId    string
+	SourceIp   string
+	HttpMethod string
+	UrlPath    string
+}
+
+type DiffEntry struct {
+	Path   string      `json:"path"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+type AuditEventFilter struct {
+	Action       string    `json:"action"`
+	ResourceType string    `json:"resourceType"`
+	ResourceId   string    `json:"resourceId"`
+	UserId       int32     `json:"userId"`
+	EmailId      string    `json:"emailId"`
+	ApiTokenId   int       `json:"apiTokenId"`
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+	Offset       int       `json:"offset"`
+	Size         int       `json:"size"`
+}
+
+type AuditEventDto struct {
+	Id           int         `json:"id"`
+	Action       string      `json:"action"`
+	ResourceType string      `json:"resourceType"`
+	ResourceId   string      `json:"resourceId,omitempty"`
+	ResourceName string      `json:"resourceName,omitempty"`
+	UserId       int32       `json:"userId,omitempty"`
+	EmailId      string      `json:"emailId,omitempty"`
+	ApiTokenId   int         `json:"apiTokenId,omitempty"`
+	SourceIp     string      `json:"sourceIp,omitempty"`
+	HttpMethod   string      `json:"httpMethod,omitempty"`
+	UrlPath      string      `json:"urlPath,omitempty"`
+	ResponseCode int         `json:"responseCode,omitempty"`
+	Diff         []DiffEntry `json:"diff,omitempty"`
+	CreatedOn    time.Time   `json:"createdOn"`
+}
+
+type AuditEventListResponse struct {
+	TotalCount int              `json:"totalCount"`
+	Events     []*AuditEventDto `json:"events"`
+}
@@ -0,0 +1,110 @@
+package auditLog
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const maskedValue = "*****"
+
+// sensitiveKeySuffixes are masked in recorded states, matched against the attribute name lower-cased and stripped of '_' and '-'
+var sensitiveKeySuffixes = []string{"password", "token", "secret", "certdata", "keydata", "privatekey", "tlskey", "clientkey"}
+
+// toMaskedState converts state into its generic json form with sensitive attributes masked
+func toMaskedState(state interface{}) (interface{}, error) {
+	if state == nil {
+		return nil, nil
+	}
+	stateJson, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	var genericState interface{}
+	err = json.Unmarshal(stateJson, &genericState)
+	if err != nil {
+		return nil, err
+	}
+	return maskSensitive("", genericState), nil
+}
+
+func maskSensitive(key string, value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for k, v := range typedValue {
+			typedValue[k] = maskSensitive(k, v)
+		}
+		return typedValue
+	case []interface{}:
+		for i, v := range typedValue {
+			typedValue[i] = maskSensitive(key, v)
+		}
+		return typedValue
+	}
+	if value != nil && isSensitiveKey(key) {
+		return maskedValue
+	}
+	return value
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+	for _, sensitiveKeySuffix := range sensitiveKeySuffixes {
+		if strings.HasSuffix(key, sensitiveKeySuffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// computeDiff returns the changed attributes between two masked states, attributes are flattened to dotted paths
+func computeDiff(before interface{}, after interface{}) []DiffEntry {
+	beforeAttributes := make(map[string]interface{})
+	afterAttributes := make(map[string]interface{})
+	flatten("", before, beforeAttributes)
+	flatten("", after, afterAttributes)
+	paths := make(map[string]bool)
+	for path := range beforeAttributes {
+		paths[path] = true
+	}
+	for path := range afterAttributes {
+		paths[path] = true
+	}
+	var diff []DiffEntry
+	for path := range paths {
+		beforeValue, afterValue := beforeAttributes[path], afterAttributes[path]
+		if !reflect.DeepEqual(beforeValue, afterValue) {
+			diff = append(diff, DiffEntry{Path: path, Before: beforeValue, After: afterValue})
+		}
+	}
+	sort.Slice(diff, func(i, j int) bool {
+		return diff[i].Path < diff[j].Path
+	})
+	return diff
+}
+
+func flatten(prefix string, value interface{}, attributes map[string]interface{}) {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for k, v := range typedValue {
+			flatten(joinPath(prefix, k), v, attributes)
+		}
+	case []interface{}:
+		for i, v := range typedValue {
+			flatten(joinPath(prefix, fmt.Sprintf("%d", i)), v, attributes)
+		}
+	case nil:
+		// absent and null are treated the same
+	default:
+		attributes[prefix] = typedValue
+	}
+}
+
+func joinPath(prefix string, key string) string {
+	if len(prefix) == 0 {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,38 @@
+package auditLog
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestComputeDiff(t *testing.T) {
+	before, err := toMaskedState(map[string]interface{}{
+		"cluster_name": "prod",
+		"config":       map[string]string{"bearer_token": "old-token"},
+		"labels":       []string{"a", "b"},
+	})
+	assert.Nil(t, err)
+	after, err := toMaskedState(map[string]interface{}{
+		"cluster_name": "production",
+		"config":       map[string]string{"bearer_token": "new-token"},
+		"labels":       []string{"a"},
+		"active":       true,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "*****", after.(map[string]interface{})["config"].(map[string]interface{})["bearer_token"])
+	assert.Equal(t, []DiffEntry{
+		{Path: "active", After: true},
+		{Path: "cluster_name", Before: "prod", After: "production"},
+		{Path: "labels.1", Before: "b"},
+	}, computeDiff(before, after))
+	assert.Empty(t, computeDiff(nil, nil))
+}
+
+func TestGetResourceFromUrlPath(t *testing.T) {
+	resourceType, resourceId := getResourceFromUrlPath("/orchestrator/cluster/12/env")
+	assert.Equal(t, "cluster", resourceType)
+	assert.Equal(t, "12", resourceId)
+	resourceType, resourceId = getResourceFromUrlPath("/orchestrator/app/material")
+	assert.Equal(t, "app", resourceType)
+	assert.Equal(t, "", resourceId)
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type AuditEvent struct {
+	tableName    struct{}  `sql:"audit_event" pg:",discard_unknown_columns"`
+	Id           int       `sql:"id,pk"`
+	Action       string    `sql:"action,notnull"`
+	ResourceType string    `sql:"resource_type,notnull"`
+	ResourceId   string    `sql:"resource_id"`
+	ResourceName string    `sql:"resource_name"`
+	UserId       int32     `sql:"user_id"`
+	EmailId      string    `sql:"email_id"`
+	ApiTokenId   int       `sql:"api_token_id"`
+	SourceIp     string    `sql:"source_ip"`
+	HttpMethod   string    `sql:"http_method"`
+	UrlPath      string    `sql:"url_path"`
+	ResponseCode int       `sql:"response_code"`
+	BeforeState  string    `sql:"before_state"`
+	AfterState   string    `sql:"after_state"`
+	Diff         string    `sql:"diff"`
+	CreatedOn    time.Time `sql:"created_on,notnull"`
+	// StreamSeq is assigned after the event is committed, see SequenceEvents
+	StreamSeq int `sql:"stream_seq"`
+}
+
+type AuditEventFilter struct {
+	Action       string
+	ResourceType string
+	ResourceId   string
+	UserId       int32
+	EmailId      string
+	ApiTokenId   int
+	From         time.Time
+	To           time.Time
+	Offset       int
+	Size         int
+}
+
+const auditEventSequenceLockName = "audit_event_stream_seq"
+
+type AuditEventRepository interface {
+	Save(event *AuditEvent) error
+	FindByFilter(filter *AuditEventFilter) ([]*AuditEvent, int, error)
+	FindApiTokenIdByName(name string) (int, error)
+	// SequenceEvents assigns the stream sequence to committed events not sequenced yet, returns the number of events sequenced
+	SequenceEvents(limit int) (int, error)
+	// FindAfterStreamSeq returns events with stream sequence greater than streamSeq, in stream order
+	FindAfterStreamSeq(streamSeq int, limit int) ([]*AuditEvent, error)
+	FindLatestStreamSeq() (int, error)
+}
+
+type AuditEventRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewAuditEventRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *AuditEventRepositoryImpl {
+	return &AuditEventRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (repo AuditEventRepositoryImpl) Save(event *AuditEvent) error {
+	return repo.dbConnection.Insert(event)
+}
+
+func (repo AuditEventRepositoryImpl) FindByFilter(filter *AuditEventFilter) ([]*AuditEvent, int, error) {
+	var events []*AuditEvent
+	query := repo.dbConnection.Model(&events)
+	if len(filter.Action) > 0 {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if len(filter.ResourceType) > 0 {
+		query = query.Where("resource_type = ?", filter.ResourceType)
+	}
+	if len(filter.ResourceId) > 0 {
+		query = query.Where("resource_id = ?", filter.ResourceId)
+	}
+	if filter.UserId > 0 {
+		query = query.Where("user_id = ?", filter.UserId)
+	}
+	if len(filter.EmailId) > 0 {
+		query = query.Where("email_id ILIKE ?", "%"+filter.EmailId+"%")
+	}
+	if filter.ApiTokenId > 0 {
+		query = query.Where("api_token_id = ?", filter.ApiTokenId)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_on >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_on <= ?", filter.To)
+	}
+	count, err := query.Order("id DESC").
+		Offset(filter.Offset).
+		Limit(filter.Size).
+		SelectAndCount()
+	return events, count, err
+}
+
+// FindApiTokenIdByName is used to resolve the api-token behind an api-token user, api_token is owned by the apiToken package
+func (repo AuditEventRepositoryImpl) FindApiTokenIdByName(name string) (int, error) {
+	var id int
+	_, err := repo.dbConnection.Query(pg.Scan(&id), "SELECT id FROM api_token WHERE name = ?", name)
+	return id, err
+}
+
+// SequenceEvents numbers events in the order their inserts became visible, ids are taken before commit so a
+// concurrent insert can commit after an event with a greater id, while a sequence taken here is always greater than
+// the sequences already visible to readers. Sequencing is serialized across replicas by a transaction advisory lock.
+func (repo AuditEventRepositoryImpl) SequenceEvents(limit int) (int, error) {
+	tx, err := repo.dbConnection.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", auditEventSequenceLockName)
+	if err != nil {
+		return 0, err
+	}
+	result, err := tx.Exec("UPDATE audit_event e SET stream_seq = s.seq"+
+		" FROM (SELECT p.id, nextval('id_seq_audit_event_stream') AS seq"+
+		" FROM (SELECT id FROM audit_event WHERE stream_seq IS NULL ORDER BY id LIMIT ?) p) s"+
+		" WHERE e.id = s.id", limit)
+	if err != nil {
+		return 0, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+func (repo AuditEventRepositoryImpl) FindAfterStreamSeq(streamSeq int, limit int) ([]*AuditEvent, error) {
+	var events []*AuditEvent
+	err := repo.dbConnection.Model(&events).
+		Where("stream_seq > ?", streamSeq).
+		Order("stream_seq ASC").
+		Limit(limit).
+		Select()
+	return events, err
+}
+
+func (repo AuditEventRepositoryImpl) FindLatestStreamSeq() (int, error) {
+	var streamSeq int
+	_, err := repo.dbConnection.Query(pg.Scan(&streamSeq), "SELECT COALESCE(MAX(stream_seq), 0) FROM audit_event")
+	return streamSeq, err
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// AuditEventSinkCursor is the stream sequence of the last audit event delivered to a sink
+type AuditEventSinkCursor struct {
+	tableName     struct{}  `sql:"audit_event_sink_cursor" pg:",discard_unknown_columns"`
+	SinkName      string    `sql:"sink_name,pk"`
+	LastStreamSeq int       `sql:"last_stream_seq,notnull"`
+	UpdatedOn     time.Time `sql:"updated_on,notnull"`
+}
+
+type AuditEventSinkCursorRepository interface {
+	GetConnection() *pg.DB
+	// TryLockSink takes the sink's transaction advisory lock, returns false if another replica holds it
+	TryLockSink(sinkName string, tx *pg.Tx) (bool, error)
+	FindBySinkName(sinkName string, tx *pg.Tx) (*AuditEventSinkCursor, error)
+	Save(cursor *AuditEventSinkCursor, tx *pg.Tx) error
+	Update(cursor *AuditEventSinkCursor, tx *pg.Tx) error
+}
+
+type AuditEventSinkCursorRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewAuditEventSinkCursorRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *AuditEventSinkCursorRepositoryImpl {
+	return &AuditEventSinkCursorRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (repo AuditEventSinkCursorRepositoryImpl) GetConnection() *pg.DB {
+	return repo.dbConnection
+}
+
+func (repo AuditEventSinkCursorRepositoryImpl) TryLockSink(sinkName string, tx *pg.Tx) (bool, error) {
+	var locked bool
+	_, err := tx.QueryOne(pg.Scan(&locked), "SELECT pg_try_advisory_xact_lock(hashtext(?))", "audit_event_sink_cursor:"+sinkName)
+	return locked, err
+}
+
+func (repo AuditEventSinkCursorRepositoryImpl) FindBySinkName(sinkName string, tx *pg.Tx) (*AuditEventSinkCursor, error) {
+	cursor := &AuditEventSinkCursor{}
+	err := tx.Model(cursor).
+		Where("sink_name = ?", sinkName).
+		Select()
+	return cursor, err
+}
+
+func (repo AuditEventSinkCursorRepositoryImpl) Save(cursor *AuditEventSinkCursor, tx *pg.Tx) error {
+	return tx.Insert(cursor)
+}
+
+func (repo AuditEventSinkCursorRepositoryImpl) Update(cursor *AuditEventSinkCursor, tx *pg.Tx) error {
+	return tx.Update(cursor)
+}
@@ -22,6 +22,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
 	"github.com/devtron-labs/devtron/pkg/variables"
 	"github.com/devtron-labs/devtron/pkg/variables/parsers"
@@ -124,6 +125,7 @@ type ChartServiceImpl struct {
 	variableEntityMappingService     variables.VariableEntityMappingService
 	variableTemplateParser           parsers.VariableTemplateParser
 	scopedVariableService            variables.ScopedVariableService
+	auditLogService                  auditLog.AuditLogService
 }
 
 func NewChartServiceImpl(chartRepository chartRepoRepository.ChartRepository,
@@ -147,7 +149,8 @@ func NewChartServiceImpl(chartRepository chartRepoRepository.ChartRepository,
 	deploymentTemplateHistoryService history.DeploymentTemplateHistoryService,
 	variableEntityMappingService variables.VariableEntityMappingService,
 	variableTemplateParser parsers.VariableTemplateParser,
-	scopedVariableService variables.ScopedVariableService) *ChartServiceImpl {
+	scopedVariableService variables.ScopedVariableService,
+	auditLogService auditLog.AuditLogService) *ChartServiceImpl {
 
 	// cache devtron reference charts list
 	devtronChartList, _ := chartRefRepository.FetchAllChartInfoByUploadFlag(false)
@@ -176,6 +179,7 @@ func NewChartServiceImpl(chartRepository chartRepoRepository.ChartRepository,
 		variableEntityMappingService:     variableEntityMappingService,
 		variableTemplateParser:           variableTemplateParser,
 		scopedVariableService:            scopedVariableService,
+		auditLogService:                  auditLogService,
 	}
 }
 
@@ -343,6 +347,7 @@ func (impl ChartServiceImpl) Create(templateRequest TemplateRequest, ctx context
 		return nil, err
 	}
 	gitRepoUrl := ""
+	gitRepoDirectory := ""
 	impl.logger.Debugw("current latest chart in db", "chartId", currentLatestChart.Id)
 	if currentLatestChart.Id > 0 {
 		impl.logger.Debugw("updating env and pipeline config which are currently latest in db", "chartId", currentLatestChart.Id)
@@ -371,6 +376,7 @@ func (impl ChartServiceImpl) Create(templateRequest TemplateRequest, ctx context
 			return nil, err
 		}
 		gitRepoUrl = currentLatestChart.GitRepoUrl
+		gitRepoDirectory = currentLatestChart.GitRepoDirectory
 	}
 	// ENDS
 
@@ -385,7 +391,7 @@ func (impl ChartServiceImpl) Create(templateRequest TemplateRequest, ctx context
 	if err != nil {
 		return nil, err
 	}
-	chartLocation := filepath.Join(templateName, version)
+	chartLocation := filepath.Join(gitRepoDirectory, templateName, version)
 	override, err := templateRequest.ValuesOverride.MarshalJSON()
 	if err != nil {
 		return nil, err
@@ -421,6 +427,7 @@ func (impl ChartServiceImpl) Create(templateRequest TemplateRequest, ctx context
 		Active:                  true,
 		ChartLocation:           chartLocation,
 		GitRepoUrl:              gitRepoUrl,
+		GitRepoDirectory:        gitRepoDirectory,
 		ReferenceTemplate:       templateName,
 		ChartRefId:              templateRequest.ChartRefId,
 		Latest:                  true,
@@ -536,11 +543,13 @@ func (impl ChartServiceImpl) CreateChartFromEnvOverride(templateRequest Template
 	if err != nil && pg.ErrNoRows != err {
 		return nil, err
 	}
-	chartLocation := filepath.Join(templateName, version)
 	gitRepoUrl := ""
+	gitRepoDirectory := ""
 	if currentLatestChart.Id > 0 {
 		gitRepoUrl = currentLatestChart.GitRepoUrl
+		gitRepoDirectory = currentLatestChart.GitRepoDirectory
 	}
+	chartLocation := filepath.Join(gitRepoDirectory, templateName, version)
 	override, err := templateRequest.ValuesOverride.MarshalJSON()
 	if err != nil {
 		return nil, err
@@ -576,6 +585,7 @@ func (impl ChartServiceImpl) CreateChartFromEnvOverride(templateRequest Template
 		Active:                  true,
 		ChartLocation:           chartLocation,
 		GitRepoUrl:              gitRepoUrl,
+		GitRepoDirectory:        gitRepoDirectory,
 		ReferenceTemplate:       templateName,
 		ChartRefId:              templateRequest.ChartRefId,
 		Latest:                  false,
@@ -814,6 +824,7 @@ func (impl ChartServiceImpl) UpdateAppOverride(ctx context.Context, templateRequ
 		impl.logger.Errorw("error in fetching chart config", "id", templateRequest.Id, "err", err)
 		return nil, err
 	}
+	before := newDeploymentTemplateAuditState(template)
 
 	if err != nil {
 		impl.logger.Errorw("chart version parsing", "err", err)
@@ -927,9 +938,34 @@ func (impl ChartServiceImpl) UpdateAppOverride(ctx context.Context, templateRequ
 	if err != nil {
 		return nil, err
 	}
+	if impl.auditLogService != nil {
+		impl.auditLogService.RecordEvent(ctx, &auditLog.AuditEventRequest{
+			Action:       auditLog.ActionUpdate,
+			ResourceType: auditLog.ResourceTypeDeploymentTemplate,
+			ResourceId:   strconv.Itoa(template.Id),
+			UserId:       templateRequest.UserId,
+			Before:       before,
+			After:        newDeploymentTemplateAuditState(template),
+		})
+	}
 	return templateRequest, nil
 }
 
+// deploymentTemplateAuditState is the audited state of an app level deployment template
+type deploymentTemplateAuditState struct {
+	AppId          int             `json:"appId"`
+	ChartRefId     int             `json:"chartRefId"`
+	ValuesOverride json.RawMessage `json:"valuesOverride"`
+}
+
+func newDeploymentTemplateAuditState(template *chartRepoRepository.Chart) *deploymentTemplateAuditState {
+	state := &deploymentTemplateAuditState{AppId: template.AppId, ChartRefId: template.ChartRefId}
+	if len(template.GlobalOverride) > 0 {
+		state.ValuesOverride = json.RawMessage(template.GlobalOverride)
+	}
+	return state
+}
+
 func (impl ChartServiceImpl) handleChartTypeChange(currentLatestChart *chartRepoRepository.Chart, templateRequest *TemplateRequest) (json.RawMessage, error) {
 	var oldChartRef, newChartRef *chartRepoRepository.ChartRef
 	var err error
@@ -21,8 +21,9 @@ type Chart struct {
 	PipelineOverride        string                      `sql:"pipeline_override"` //json format  // pipeline values -> strategy values
 	Status                  models.ChartStatus          `sql:"status"`            //(new , deployment-in-progress, deployed-To-production, error )
 	Active                  bool                        `sql:"active"`
-	GitRepoUrl              string                      `sql:"git_repo_url"`   //git repository where chart is stored
-	ChartLocation           string                      `sql:"chart_location"` //location within git repo where current chart is pointing
+	GitRepoUrl              string                      `sql:"git_repo_url"`       //git repository where chart is stored
+	ChartLocation           string                      `sql:"chart_location"`     //location within git repo where current chart is pointing
+	GitRepoDirectory        string                      `sql:"git_repo_directory"` //directory of the app when git repo is shared by apps, chart location is within it
 	ReferenceTemplate       string                      `sql:"reference_template"`
 	ImageDescriptorTemplate string                      `sql:"image_descriptor_template"`
 	ChartRefId              int                         `sql:"chart_ref_id"`
@@ -53,6 +54,7 @@ type ChartRepository interface {
 	FindPreviousChartByAppId(appId int) (chart *Chart, err error)
 	FindNumberOfAppsWithDeploymentTemplate(appIds []int) (int, error)
 	FindChartByGitRepoUrl(gitRepoUrl string) (*Chart, error)
+	FindChartByGitRepoUrlAndPath(gitRepoUrl string, path string) (*Chart, error)
 }
 
 func NewChartRepository(dbConnection *pg.DB) *ChartRepositoryImpl {
@@ -206,6 +208,21 @@ func (repositoryImpl ChartRepositoryImpl) FindChartByGitRepoUrl(gitRepoUrl strin
 	return &chart, err
 }
 
+// FindChartByGitRepoUrlAndPath finds the chart of an app in a git repo shared by apps, path is any path within the app's directory
+func (repositoryImpl ChartRepositoryImpl) FindChartByGitRepoUrlAndPath(gitRepoUrl string, path string) (*Chart, error) {
+	var chart Chart
+	err := repositoryImpl.dbConnection.Model(&chart).
+		Join("INNER JOIN app ON app.id=app_id").
+		Where("app.active = ?", true).
+		Where("chart.git_repo_url = ?", gitRepoUrl).
+		Where("chart.git_repo_directory <> ''").
+		Where("strpos(?, chart.git_repo_directory || '/') = 1", path).
+		Where("chart.active = ?", true).
+		Limit(1).
+		Select()
+	return &chart, err
+}
+
 func (repositoryImpl ChartRepositoryImpl) FindNumberOfAppsWithDeploymentTemplate(appIds []int) (int, error) {
 	var charts []*Chart
 	count, err := repositoryImpl.dbConnection.
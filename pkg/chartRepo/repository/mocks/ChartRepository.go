@@ -127,6 +127,29 @@ func (_m *ChartRepository) FindChartByGitRepoUrl(gitRepoUrl string) (*chartRepoR
 	return r0, r1
 }
 
+// FindChartByGitRepoUrlAndPath provides a mock function with given fields: gitRepoUrl, path
+func (_m *ChartRepository) FindChartByGitRepoUrlAndPath(gitRepoUrl string, path string) (*chartRepoRepository.Chart, error) {
+	ret := _m.Called(gitRepoUrl, path)
+
+	var r0 *chartRepoRepository.Chart
+	if rf, ok := ret.Get(0).(func(string, string) *chartRepoRepository.Chart); ok {
+		r0 = rf(gitRepoUrl, path)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*chartRepoRepository.Chart)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(gitRepoUrl, path)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindChartRefIdForLatestChartForAppByAppId provides a mock function with given fields: appId
 func (_m *ChartRepository) FindChartRefIdForLatestChartForAppByAppId(appId int) (int, error) {
 	ret := _m.Called(appId)
@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	"github.com/devtron-labs/devtron/pkg/k8s/informer"
 	casbin2 "github.com/devtron-labs/devtron/pkg/user/casbin"
 	repository2 "github.com/devtron-labs/devtron/pkg/user/repository"
@@ -36,6 +37,7 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"strconv"
 	"sync"
 	"time"
 
@@ -87,6 +89,12 @@ type ClusterBean struct {
 	IsVirtualCluster        bool                       `json:"isVirtualCluster"`
 	isClusterNameEmpty      bool                       `json:"-"`
 	ClusterUpdated          bool                       `json:"clusterUpdated"`
+	// TerminalRecordingEnabled records terminal sessions of the cluster in asciicast format
+	TerminalRecordingEnabled bool `json:"terminalRecordingEnabled"`
+	// TerminalIdleTimeoutMins closes terminal sessions without input or output for so long, 0 is unlimited
+	TerminalIdleTimeoutMins int `json:"terminalIdleTimeoutMins"`
+	// TerminalMaxSessionDurationMins closes terminal sessions open for so long, 0 is unlimited
+	TerminalMaxSessionDurationMins int `json:"terminalMaxSessionDurationMins"`
 }
 
 func GetClusterBean(model repository.Cluster) ClusterBean {
@@ -102,6 +110,9 @@ func GetClusterBean(model repository.Cluster) ClusterBean {
 	bean.InsecureSkipTLSVerify = model.InsecureSkipTlsVerify
 	bean.IsVirtualCluster = model.IsVirtualCluster
 	bean.ErrorInConnecting = model.ErrorInConnecting
+	bean.TerminalRecordingEnabled = model.TerminalRecordingEnabled
+	bean.TerminalIdleTimeoutMins = model.TerminalIdleTimeoutMins
+	bean.TerminalMaxSessionDurationMins = model.TerminalMaxSessionDurationMins
 	bean.PrometheusAuth = &PrometheusAuth{
 		UserName:      model.PUserName,
 		Password:      model.PPassword,
@@ -161,13 +172,13 @@ type ClusterService interface {
 	FindAll() ([]*ClusterBean, error)
 	FindAllWithoutConfig() ([]*ClusterBean, error)
 	FindAllActive() ([]ClusterBean, error)
-	DeleteFromDb(bean *ClusterBean, userId int32) error
+	DeleteFromDb(ctx context.Context, bean *ClusterBean, userId int32) error
 
 	FindById(id int) (*ClusterBean, error)
 	FindByIdWithoutConfig(id int) (*ClusterBean, error)
 	FindByIds(id []int) ([]ClusterBean, error)
 	Update(ctx context.Context, bean *ClusterBean, userId int32) (*ClusterBean, error)
-	Delete(bean *ClusterBean, userId int32) error
+	Delete(ctx context.Context, bean *ClusterBean, userId int32) error
 
 	FindAllForAutoComplete() ([]ClusterBean, error)
 	CreateGrafanaDataSource(clusterBean *ClusterBean, env *repository.Environment) (int, error)
@@ -189,13 +200,14 @@ type ClusterServiceImpl struct {
 	userAuthRepository  repository2.UserAuthRepository
 	userRepository      repository2.UserRepository
 	roleGroupRepository repository2.RoleGroupRepository
+	auditLogService     auditLog.AuditLogService
 	*ClusterRbacServiceImpl
 }
 
 func NewClusterServiceImpl(repository repository.ClusterRepository, logger *zap.SugaredLogger,
 	K8sUtil *k8s.K8sUtil, K8sInformerFactory informer.K8sInformerFactory,
 	userAuthRepository repository2.UserAuthRepository, userRepository repository2.UserRepository,
-	roleGroupRepository repository2.RoleGroupRepository) *ClusterServiceImpl {
+	roleGroupRepository repository2.RoleGroupRepository, auditLogService auditLog.AuditLogService) *ClusterServiceImpl {
 	clusterService := &ClusterServiceImpl{
 		clusterRepository:   repository,
 		logger:              logger,
@@ -204,6 +216,7 @@ func NewClusterServiceImpl(repository repository.ClusterRepository, logger *zap.
 		userAuthRepository:  userAuthRepository,
 		userRepository:      userRepository,
 		roleGroupRepository: roleGroupRepository,
+		auditLogService:     auditLogService,
 		ClusterRbacServiceImpl: &ClusterRbacServiceImpl{
 			logger: logger,
 		},
@@ -222,6 +235,9 @@ func (impl *ClusterServiceImpl) ConvertClusterBeanToCluster(clusterBean *Cluster
 	model.Config = clusterBean.Config
 	model.PrometheusEndpoint = clusterBean.PrometheusUrl
 	model.InsecureSkipTlsVerify = clusterBean.InsecureSkipTLSVerify
+	model.TerminalRecordingEnabled = clusterBean.TerminalRecordingEnabled
+	model.TerminalIdleTimeoutMins = clusterBean.TerminalIdleTimeoutMins
+	model.TerminalMaxSessionDurationMins = clusterBean.TerminalMaxSessionDurationMins
 
 	if clusterBean.PrometheusAuth != nil {
 		model.PUserName = clusterBean.PrometheusAuth.UserName
@@ -285,6 +301,9 @@ func (impl *ClusterServiceImpl) Save(parent context.Context, bean *ClusterBean,
 		}
 	}
 	bean.Id = model.Id
+	if err == nil {
+		impl.recordAuditEvent(parent, auditLog.ActionCreate, userId, nil, bean)
+	}
 
 	//on successful creation of new cluster, update informer cache for namespace group by cluster
 	//here sync for ea mode only
@@ -408,6 +427,7 @@ func (impl *ClusterServiceImpl) Update(ctx context.Context, bean *ClusterBean, u
 		impl.logger.Error(err)
 		return nil, err
 	}
+	existingBean := GetClusterBean(*model)
 	existingModel, err := impl.clusterRepository.FindOne(bean.ClusterName)
 	if err != nil && err != pg.ErrNoRows {
 		impl.logger.Error(err)
@@ -458,6 +478,9 @@ func (impl *ClusterServiceImpl) Update(ctx context.Context, bean *ClusterBean, u
 	model.ClusterName = bean.ClusterName
 	model.ServerUrl = bean.ServerUrl
 	model.InsecureSkipTlsVerify = bean.InsecureSkipTLSVerify
+	model.TerminalRecordingEnabled = bean.TerminalRecordingEnabled
+	model.TerminalIdleTimeoutMins = bean.TerminalIdleTimeoutMins
+	model.TerminalMaxSessionDurationMins = bean.TerminalMaxSessionDurationMins
 	model.PrometheusEndpoint = bean.PrometheusUrl
 
 	if bean.PrometheusAuth != nil {
@@ -505,6 +528,7 @@ func (impl *ClusterServiceImpl) Update(ctx context.Context, bean *ClusterBean, u
 		return bean, err
 	}
 	bean.Id = model.Id
+	impl.recordAuditEvent(ctx, auditLog.ActionUpdate, userId, &existingBean, bean)
 
 	//here sync for ea mode only
 	if bean.HasConfigOrUrlChanged && util2.IsBaseStack() {
@@ -564,12 +588,18 @@ func (impl *ClusterServiceImpl) SyncNsInformer(bean *ClusterBean) {
 	impl.K8sInformerFactory.BuildInformer([]*bean2.ClusterInfo{clusterInfo})
 }
 
-func (impl *ClusterServiceImpl) Delete(bean *ClusterBean, userId int32) error {
+func (impl *ClusterServiceImpl) Delete(ctx context.Context, bean *ClusterBean, userId int32) error {
 	model, err := impl.clusterRepository.FindById(bean.Id)
 	if err != nil {
 		return err
 	}
-	return impl.clusterRepository.Delete(model)
+	err = impl.clusterRepository.Delete(model)
+	if err != nil {
+		return err
+	}
+	existingBean := GetClusterBean(*model)
+	impl.recordAuditEvent(ctx, auditLog.ActionDelete, userId, &existingBean, nil)
+	return nil
 }
 
 func (impl *ClusterServiceImpl) FindAllForAutoComplete() ([]ClusterBean, error) {
@@ -620,12 +650,13 @@ func (impl *ClusterServiceImpl) buildInformer() {
 	impl.K8sInformerFactory.BuildInformer(clusterInfo)
 }
 
-func (impl ClusterServiceImpl) DeleteFromDb(bean *ClusterBean, userId int32) error {
+func (impl ClusterServiceImpl) DeleteFromDb(ctx context.Context, bean *ClusterBean, userId int32) error {
 	existingCluster, err := impl.clusterRepository.FindById(bean.Id)
 	if err != nil {
 		impl.logger.Errorw("No matching entry found for delete.", "id", bean.Id)
 		return err
 	}
+	existingBean := GetClusterBean(*existingCluster)
 	deleteReq := existingCluster
 	deleteReq.UpdatedOn = time.Now()
 	deleteReq.UpdatedBy = userId
@@ -634,6 +665,7 @@ func (impl ClusterServiceImpl) DeleteFromDb(bean *ClusterBean, userId int32) err
 		impl.logger.Errorw("error in deleting cluster", "id", bean.Id, "err", err)
 		return err
 	}
+	impl.recordAuditEvent(ctx, auditLog.ActionDelete, userId, &existingBean, nil)
 	k8sClient, err := impl.K8sUtil.GetCoreV1ClientInCluster()
 	if err != nil {
 		impl.logger.Errorw("error in getting in cluster k8s client", "err", err, "clusterName", bean.ClusterName)
@@ -645,6 +677,28 @@ func (impl ClusterServiceImpl) DeleteFromDb(bean *ClusterBean, userId int32) err
 	return nil
 }
 
+func (impl ClusterServiceImpl) recordAuditEvent(ctx context.Context, action string, userId int32, before *ClusterBean, after *ClusterBean) {
+	if impl.auditLogService == nil {
+		return
+	}
+	request := &auditLog.AuditEventRequest{
+		Action:       action,
+		ResourceType: auditLog.ResourceTypeCluster,
+		UserId:       userId,
+	}
+	if before != nil {
+		request.ResourceId = strconv.Itoa(before.Id)
+		request.ResourceName = before.ClusterName
+		request.Before = before
+	}
+	if after != nil {
+		request.ResourceId = strconv.Itoa(after.Id)
+		request.ResourceName = after.ClusterName
+		request.After = after
+	}
+	impl.auditLogService.RecordEvent(ctx, request)
+}
+
 func (impl ClusterServiceImpl) CheckIfConfigIsValid(cluster *ClusterBean) error {
 	clusterConfig, err := cluster.GetClusterConfig()
 	if err != nil {
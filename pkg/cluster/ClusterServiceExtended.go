@@ -6,6 +6,7 @@ import (
 	cluster3 "github.com/argoproj/argo-cd/v2/pkg/apiclient/cluster"
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	repository3 "github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	"github.com/devtron-labs/devtron/pkg/k8s/informer"
 	repository4 "github.com/devtron-labs/devtron/pkg/user/repository"
 	"github.com/devtron-labs/devtron/util/k8s"
@@ -39,7 +40,8 @@ func NewClusterServiceImplExtended(repository repository.ClusterRepository, envi
 	K8sUtil *k8s.K8sUtil,
 	clusterServiceCD cluster2.ServiceClient, K8sInformerFactory informer.K8sInformerFactory,
 	gitOpsRepository repository3.GitOpsConfigRepository, userAuthRepository repository4.UserAuthRepository,
-	userRepository repository4.UserRepository, roleGroupRepository repository4.RoleGroupRepository) *ClusterServiceImplExtended {
+	userRepository repository4.UserRepository, roleGroupRepository repository4.RoleGroupRepository,
+	auditLogService auditLog.AuditLogService) *ClusterServiceImplExtended {
 	clusterServiceExt := &ClusterServiceImplExtended{
 		environmentRepository:  environmentRepository,
 		grafanaClient:          grafanaClient,
@@ -54,6 +56,7 @@ func NewClusterServiceImplExtended(repository repository.ClusterRepository, envi
 			userAuthRepository:  userAuthRepository,
 			userRepository:      userRepository,
 			roleGroupRepository: roleGroupRepository,
+			auditLogService:     auditLogService,
 		},
 	}
 	go clusterServiceExt.buildInformer()
@@ -342,7 +345,7 @@ func (impl *ClusterServiceImplExtended) Save(ctx context.Context, bean *ClusterB
 		_, err = impl.clusterServiceCD.Create(ctx, &cluster3.ClusterCreateRequest{Upsert: true, Cluster: cl})
 		if err != nil {
 			impl.logger.Errorw("service err, Save", "err", err, "payload", cl)
-			err1 := impl.ClusterServiceImpl.Delete(bean, userId) //FIXME nishant call local
+			err1 := impl.ClusterServiceImpl.Delete(ctx, bean, userId) //FIXME nishant call local
 			if err1 != nil {
 				impl.logger.Errorw("service err, Save, delete on rollback", "err", err, "payload", bean)
 				err = &util.ApiError{
@@ -367,12 +370,13 @@ func (impl *ClusterServiceImplExtended) Save(ctx context.Context, bean *ClusterB
 	return clusterBean, nil
 }
 
-func (impl ClusterServiceImplExtended) DeleteFromDb(bean *ClusterBean, userId int32) error {
+func (impl ClusterServiceImplExtended) DeleteFromDb(ctx context.Context, bean *ClusterBean, userId int32) error {
 	existingCluster, err := impl.clusterRepository.FindById(bean.Id)
 	if err != nil {
 		impl.logger.Errorw("No matching entry found for delete.", "id", bean.Id)
 		return err
 	}
+	existingBean := GetClusterBean(*existingCluster)
 	deleteReq := existingCluster
 	deleteReq.UpdatedOn = time.Now()
 	deleteReq.UpdatedBy = userId
@@ -381,6 +385,7 @@ func (impl ClusterServiceImplExtended) DeleteFromDb(bean *ClusterBean, userId in
 		impl.logger.Errorw("error in deleting cluster", "id", bean.Id, "err", err)
 		return err
 	}
+	impl.recordAuditEvent(ctx, auditLog.ActionDelete, userId, &existingBean, nil)
 	k8sClient, err := impl.ClusterServiceImpl.K8sUtil.GetCoreV1ClientInCluster()
 	if err != nil {
 		impl.logger.Errorw("error in creating k8s client set", "err", err, "clusterName", bean.ClusterName)
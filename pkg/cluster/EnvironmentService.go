@@ -22,6 +22,7 @@ import (
 	"fmt"
 	repository2 "github.com/devtron-labs/devtron/internal/sql/repository"
 	"github.com/devtron-labs/devtron/pkg/attributes"
+	bean2 "github.com/devtron-labs/devtron/pkg/environmentPolicy/bean"
 	"github.com/devtron-labs/devtron/pkg/k8s/informer"
 	"github.com/devtron-labs/devtron/pkg/user/bean"
 	util2 "github.com/devtron-labs/devtron/util/k8s"
@@ -52,6 +53,11 @@ type EnvironmentBean struct {
 	AppCount               int      `json:"appCount"`
 	IsVirtualEnvironment   bool     `json:"isVirtualEnvironment"`
 	AllowedDeploymentTypes []string `json:"allowedDeploymentTypes"`
+	GitOpsPrEnabled        bool     `json:"gitOpsPullRequestEnabled"`
+	// NamespacePolicy is applied on the namespace after the environment is saved, it is not returned on reads.
+	// NamespacePolicyError is why the policy could not be saved, the environment is saved regardless
+	NamespacePolicy      *bean2.NamespacePolicy `json:"namespacePolicy,omitempty"`
+	NamespacePolicyError string                 `json:"namespacePolicyError,omitempty"`
 }
 
 type EnvDto struct {
@@ -160,6 +166,7 @@ func (impl EnvironmentServiceImpl) Create(mappings *EnvironmentBean, userId int3
 		Description:           mappings.Description,
 		EnvironmentIdentifier: identifier,
 	}
+	model.GitOpsPullRequestEnabled = mappings.GitOpsPrEnabled
 	model.CreatedBy = userId
 	model.UpdatedBy = userId
 	model.CreatedOn = time.Now()
@@ -207,6 +214,7 @@ func (impl EnvironmentServiceImpl) FindOne(environment string) (*EnvironmentBean
 		Default:               model.Default,
 		EnvironmentIdentifier: model.EnvironmentIdentifier,
 		Description:           model.Description,
+		GitOpsPrEnabled:       model.GitOpsPullRequestEnabled,
 	}
 	return bean, nil
 }
@@ -231,6 +239,7 @@ func (impl EnvironmentServiceImpl) GetAll() ([]EnvironmentBean, error) {
 			EnvironmentIdentifier: model.EnvironmentIdentifier,
 			Description:           model.Description,
 			IsVirtualEnvironment:  model.IsVirtualEnvironment,
+			GitOpsPrEnabled:       model.GitOpsPullRequestEnabled,
 		})
 	}
 	return beans, nil
@@ -255,6 +264,7 @@ func (impl EnvironmentServiceImpl) GetAllActive() ([]EnvironmentBean, error) {
 			EnvironmentIdentifier: model.EnvironmentIdentifier,
 			Description:           model.Description,
 			IsVirtualEnvironment:  model.IsVirtualEnvironment,
+			GitOpsPrEnabled:       model.GitOpsPullRequestEnabled,
 		})
 	}
 	return beans, nil
@@ -277,6 +287,7 @@ func (impl EnvironmentServiceImpl) FindById(id int) (*EnvironmentBean, error) {
 		EnvironmentIdentifier: model.EnvironmentIdentifier,
 		Description:           model.Description,
 		IsVirtualEnvironment:  model.IsVirtualEnvironment,
+		GitOpsPrEnabled:       model.GitOpsPullRequestEnabled,
 	}
 
 	/*clusterBean := &ClusterBean{
@@ -310,6 +321,7 @@ func (impl EnvironmentServiceImpl) Update(mappings *EnvironmentBean, userId int3
 	model.UpdatedBy = userId
 	model.UpdatedOn = time.Now()
 	model.Description = mappings.Description
+	model.GitOpsPullRequestEnabled = mappings.GitOpsPrEnabled
 
 	//namespace create if not exist
 	if len(model.Namespace) > 0 {
@@ -516,6 +528,7 @@ func (impl EnvironmentServiceImpl) FindByIds(ids []*int) ([]*EnvironmentBean, er
 			ClusterId:             model.ClusterId,
 			Description:           model.Description,
 			IsVirtualEnvironment:  model.IsVirtualEnvironment,
+			GitOpsPrEnabled:       model.GitOpsPullRequestEnabled,
 		})
 	}
 	return beans, nil
@@ -83,13 +83,13 @@ func (_m *ClusterService) CreateGrafanaDataSource(clusterBean *cluster.ClusterBe
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: bean, userId
-func (_m *ClusterService) Delete(bean *cluster.ClusterBean, userId int32) error {
-	ret := _m.Called(bean, userId)
+// Delete provides a mock function with given fields: ctx, bean, userId
+func (_m *ClusterService) Delete(ctx context.Context, bean *cluster.ClusterBean, userId int32) error {
+	ret := _m.Called(ctx, bean, userId)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*cluster.ClusterBean, int32) error); ok {
-		r0 = rf(bean, userId)
+	if rf, ok := ret.Get(0).(func(context.Context, *cluster.ClusterBean, int32) error); ok {
+		r0 = rf(ctx, bean, userId)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -97,13 +97,13 @@ func (_m *ClusterService) Delete(bean *cluster.ClusterBean, userId int32) error
 	return r0
 }
 
-// DeleteFromDb provides a mock function with given fields: bean, userId
-func (_m *ClusterService) DeleteFromDb(bean *cluster.ClusterBean, userId int32) error {
-	ret := _m.Called(bean, userId)
+// DeleteFromDb provides a mock function with given fields: ctx, bean, userId
+func (_m *ClusterService) DeleteFromDb(ctx context.Context, bean *cluster.ClusterBean, userId int32) error {
+	ret := _m.Called(ctx, bean, userId)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*cluster.ClusterBean, int32) error); ok {
-		r0 = rf(bean, userId)
+	if rf, ok := ret.Get(0).(func(context.Context, *cluster.ClusterBean, int32) error); ok {
+		r0 = rf(ctx, bean, userId)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -41,6 +41,11 @@ type Cluster struct {
 	ErrorInConnecting      string            `sql:"error_in_connecting"`
 	IsVirtualCluster       bool              `sql:"is_virtual_cluster"`
 	InsecureSkipTlsVerify  bool              `sql:"insecure_skip_tls_verify"`
+	// TerminalRecordingEnabled records every terminal session opened on the cluster
+	TerminalRecordingEnabled bool `sql:"terminal_recording_enabled,notnull"`
+	// TerminalIdleTimeoutMins and TerminalMaxSessionDurationMins close terminal sessions of the cluster, 0 is unlimited
+	TerminalIdleTimeoutMins        int `sql:"terminal_idle_timeout_mins,notnull"`
+	TerminalMaxSessionDurationMins int `sql:"terminal_max_session_duration_mins,notnull"`
 	sql.AuditLog
 }
 
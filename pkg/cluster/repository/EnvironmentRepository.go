@@ -46,6 +46,8 @@ type Environment struct {
 	EnvironmentIdentifier string `sql:"environment_identifier"`
 	Description           string `sql:"description"`
 	IsVirtualEnvironment  bool   `sql:"is_virtual_environment"`
+	// GitOpsPullRequestEnabled raises a pull request for gitops commits, deployment waits until it is merged
+	GitOpsPullRequestEnabled bool `sql:"gitops_pull_request_enabled,notnull"`
 	sql.AuditLog
 }
 
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// TerminalCommandPolicy restricts the commands which can be run from terminal sessions of a cluster, an empty namespace
+// applies the policy to all namespaces of the cluster which have no policy of their own
+type TerminalCommandPolicy struct {
+	tableName       struct{} `sql:"terminal_command_policy" pg:",discard_unknown_columns"`
+	Id              int      `sql:"id,pk"`
+	Name            string   `sql:"name"`
+	ClusterId       int      `sql:"cluster_id"`
+	Namespace       string   `sql:"namespace,notnull"`
+	AllowedCommands string   `sql:"allowed_commands"` //json array of commands, empty allows every command which is not denied
+	DeniedCommands  string   `sql:"denied_commands"`  //json array of commands
+	Active          bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+// TerminalCommandViolation is a command blocked by a TerminalCommandPolicy
+type TerminalCommandViolation struct {
+	tableName     struct{}  `sql:"terminal_command_violation" pg:",discard_unknown_columns"`
+	Id            int       `sql:"id,pk"`
+	PolicyId      int       `sql:"policy_id"`
+	SessionId     string    `sql:"session_id"`
+	ClusterId     int       `sql:"cluster_id"`
+	Namespace     string    `sql:"namespace"`
+	PodName       string    `sql:"pod_name"`
+	ContainerName string    `sql:"container_name"`
+	UserId        int32     `sql:"user_id"`
+	Command       string    `sql:"command"`
+	Reason        string    `sql:"reason"`
+	BlockedOn     time.Time `sql:"blocked_on,type:timestamptz"`
+}
+
+type TerminalCommandViolationFilter struct {
+	ClusterId int
+	UserId    int32
+	Offset    int
+	Size      int
+}
+
+type TerminalCommandPolicyRepository interface {
+	Save(policy *TerminalCommandPolicy) error
+	Update(policy *TerminalCommandPolicy) error
+	FindById(id int) (*TerminalCommandPolicy, error)
+	FindAllActive() ([]*TerminalCommandPolicy, error)
+	FindActiveByClusterId(clusterId int) ([]*TerminalCommandPolicy, error)
+	SaveViolation(violation *TerminalCommandViolation) error
+	FindViolations(filter *TerminalCommandViolationFilter) ([]*TerminalCommandViolation, error)
+}
+
+type TerminalCommandPolicyRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewTerminalCommandPolicyRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *TerminalCommandPolicyRepositoryImpl {
+	return &TerminalCommandPolicyRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *TerminalCommandPolicyRepositoryImpl) Save(policy *TerminalCommandPolicy) error {
+	err := impl.dbConnection.Insert(policy)
+	if err != nil {
+		impl.logger.Errorw("error in saving terminal command policy", "err", err, "clusterId", policy.ClusterId)
+		return err
+	}
+	return nil
+}
+
+func (impl *TerminalCommandPolicyRepositoryImpl) Update(policy *TerminalCommandPolicy) error {
+	err := impl.dbConnection.Update(policy)
+	if err != nil {
+		impl.logger.Errorw("error in updating terminal command policy", "err", err, "id", policy.Id)
+		return err
+	}
+	return nil
+}
+
+func (impl *TerminalCommandPolicyRepositoryImpl) FindById(id int) (*TerminalCommandPolicy, error) {
+	policy := &TerminalCommandPolicy{}
+	err := impl.dbConnection.Model(policy).
+		Where("id = ?", id).
+		Where("active = ?", true).Select()
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (impl *TerminalCommandPolicyRepositoryImpl) FindAllActive() ([]*TerminalCommandPolicy, error) {
+	var policies []*TerminalCommandPolicy
+	err := impl.dbConnection.Model(&policies).
+		Where("active = ?", true).
+		Order("cluster_id ASC", "namespace ASC").Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting terminal command policies", "err", err)
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (impl *TerminalCommandPolicyRepositoryImpl) FindActiveByClusterId(clusterId int) ([]*TerminalCommandPolicy, error) {
+	var policies []*TerminalCommandPolicy
+	err := impl.dbConnection.Model(&policies).
+		Where("cluster_id = ?", clusterId).
+		Where("active = ?", true).Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting terminal command policies", "err", err, "clusterId", clusterId)
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (impl *TerminalCommandPolicyRepositoryImpl) SaveViolation(violation *TerminalCommandViolation) error {
+	err := impl.dbConnection.Insert(violation)
+	if err != nil {
+		impl.logger.Errorw("error in saving terminal command violation", "err", err, "sessionId", violation.SessionId)
+		return err
+	}
+	return nil
+}
+
+func (impl *TerminalCommandPolicyRepositoryImpl) FindViolations(filter *TerminalCommandViolationFilter) ([]*TerminalCommandViolation, error) {
+	var violations []*TerminalCommandViolation
+	query := impl.dbConnection.Model(&violations)
+	if filter.ClusterId > 0 {
+		query = query.Where("cluster_id = ?", filter.ClusterId)
+	}
+	if filter.UserId > 0 {
+		query = query.Where("user_id = ?", filter.UserId)
+	}
+	if filter.Size > 0 {
+		query = query.Offset(filter.Offset).Limit(filter.Size)
+	}
+	err := query.Order("blocked_on DESC").Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting terminal command violations", "err", err, "filter", filter)
+		return nil, err
+	}
+	return violations, nil
+}
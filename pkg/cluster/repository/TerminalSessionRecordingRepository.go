@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type TerminalRecordingStatus = string
+
+const (
+	TERMINAL_RECORDING_STATUS_RECORDING TerminalRecordingStatus = "RECORDING"
+	TERMINAL_RECORDING_STATUS_UPLOADED  TerminalRecordingStatus = "UPLOADED"
+	// TERMINAL_RECORDING_STATUS_FAILED is set when the recording could not be written or uploaded, message has the reason
+	TERMINAL_RECORDING_STATUS_FAILED TerminalRecordingStatus = "FAILED"
+)
+
+// TerminalSessionRecording is a terminal session recorded in asciicast v2 format, the cast file is kept in blob storage
+type TerminalSessionRecording struct {
+	tableName     struct{}                `sql:"terminal_session_recording" pg:",discard_unknown_columns"`
+	Id            int                     `sql:"id,pk"`
+	SessionId     string                  `sql:"session_id"`
+	ClusterId     int                     `sql:"cluster_id"`
+	Namespace     string                  `sql:"namespace"`
+	PodName       string                  `sql:"pod_name"`
+	ContainerName string                  `sql:"container_name"`
+	Shell         string                  `sql:"shell"`
+	UserId        int32                   `sql:"user_id"`
+	AppId         int                     `sql:"app_id"`
+	EnvId         int                     `sql:"env_id"`
+	BlobKey       string                  `sql:"blob_key"`
+	Size          int64                   `sql:"size,notnull"`
+	Status        TerminalRecordingStatus `sql:"status"`
+	Message       string                  `sql:"message"`
+	StartedOn     time.Time               `sql:"started_on,type:timestamptz"`
+	EndedOn       time.Time               `sql:"ended_on,type:timestamptz"`
+	sql.AuditLog
+}
+
+type TerminalSessionRecordingFilter struct {
+	ClusterId int
+	UserId    int32
+	From      time.Time
+	To        time.Time
+	Offset    int
+	Size      int
+}
+
+type TerminalSessionRecordingRepository interface {
+	Save(recording *TerminalSessionRecording) error
+	Update(recording *TerminalSessionRecording) error
+	FindById(id int) (*TerminalSessionRecording, error)
+	FindByFilter(filter *TerminalSessionRecordingFilter) ([]*TerminalSessionRecording, error)
+}
+
+type TerminalSessionRecordingRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewTerminalSessionRecordingRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *TerminalSessionRecordingRepositoryImpl {
+	return &TerminalSessionRecordingRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *TerminalSessionRecordingRepositoryImpl) Save(recording *TerminalSessionRecording) error {
+	err := impl.dbConnection.Insert(recording)
+	if err != nil {
+		impl.logger.Errorw("error in saving terminal session recording", "err", err, "sessionId", recording.SessionId)
+		return err
+	}
+	return nil
+}
+
+func (impl *TerminalSessionRecordingRepositoryImpl) Update(recording *TerminalSessionRecording) error {
+	err := impl.dbConnection.Update(recording)
+	if err != nil {
+		impl.logger.Errorw("error in updating terminal session recording", "err", err, "sessionId", recording.SessionId)
+		return err
+	}
+	return nil
+}
+
+func (impl *TerminalSessionRecordingRepositoryImpl) FindById(id int) (*TerminalSessionRecording, error) {
+	recording := &TerminalSessionRecording{}
+	err := impl.dbConnection.Model(recording).
+		Where("id = ?", id).Select()
+	if err != nil {
+		return nil, err
+	}
+	return recording, nil
+}
+
+func (impl *TerminalSessionRecordingRepositoryImpl) FindByFilter(filter *TerminalSessionRecordingFilter) ([]*TerminalSessionRecording, error) {
+	var recordings []*TerminalSessionRecording
+	query := impl.dbConnection.Model(&recordings)
+	if filter.ClusterId > 0 {
+		query = query.Where("cluster_id = ?", filter.ClusterId)
+	}
+	if filter.UserId > 0 {
+		query = query.Where("user_id = ?", filter.UserId)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("started_on >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("started_on <= ?", filter.To)
+	}
+	if filter.Size > 0 {
+		query = query.Offset(filter.Offset).Limit(filter.Size)
+	}
+	err := query.Order("started_on DESC").Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting terminal session recordings", "err", err, "filter", filter)
+		return nil, err
+	}
+	return recordings, nil
+}
@@ -0,0 +1,405 @@
+package clusterHealth
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"github.com/caarlos0/env/v6"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	"github.com/devtron-labs/devtron/pkg/clusterHealth/bean"
+	"github.com/devtron-labs/devtron/pkg/clusterHealth/repository"
+	"github.com/devtron-labs/devtron/util/k8s"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+	"strings"
+	"sync"
+	"time"
+)
+
+const clusterHealthCheckTimeout = time.Minute
+
+type ClusterHealthConfig struct {
+	CheckEnabled       bool  `env:"CLUSTER_HEALTH_CHECK_ENABLED" envDefault:"true"`
+	CheckIntervalMins  int   `env:"CLUSTER_HEALTH_CHECK_INTERVAL_MINS" envDefault:"5"`
+	RetentionDays      int   `env:"CLUSTER_HEALTH_RETENTION_DAYS" envDefault:"30"`
+	LatencyThresholdMs int64 `env:"CLUSTER_HEALTH_LATENCY_THRESHOLD_MS" envDefault:"2000"`
+	// MaxKubeletVersionSkew is the number of minor versions the kubelets may differ from the api server
+	MaxKubeletVersionSkew       int `env:"CLUSTER_HEALTH_MAX_KUBELET_VERSION_SKEW" envDefault:"2"`
+	CredentialExpiryWarningDays int `env:"CLUSTER_HEALTH_CREDENTIAL_EXPIRY_WARNING_DAYS" envDefault:"14"`
+}
+
+type ClusterHealthService interface {
+	// CheckClusters checks the health of all clusters and saves the results, removing the results older than the retention
+	CheckClusters()
+	GetLatestHealth() ([]*bean.ClusterHealth, error)
+	GetHealthHistory(clusterId int, from, to time.Time) ([]*bean.ClusterHealth, error)
+}
+
+// clusterHealthHooks are the checks and notifications which are only available in the full mode
+type clusterHealthHooks interface {
+	getArgoCdStatus(ctx context.Context, clusterBean *cluster.ClusterBean) string
+	notifyUnhealthy(health *bean.ClusterHealth)
+}
+
+type ClusterHealthServiceImpl struct {
+	logger                  *zap.SugaredLogger
+	clusterService          cluster.ClusterService
+	K8sUtil                 *k8s.K8sUtil
+	clusterHealthRepository repository.ClusterHealthRepository
+	config                  *ClusterHealthConfig
+	hooks                   clusterHealthHooks
+}
+
+func NewClusterHealthServiceImpl(logger *zap.SugaredLogger, clusterService cluster.ClusterService, K8sUtil *k8s.K8sUtil,
+	clusterHealthRepository repository.ClusterHealthRepository) (*ClusterHealthServiceImpl, error) {
+	serviceImpl, err := newClusterHealthServiceImpl(logger, clusterService, K8sUtil, clusterHealthRepository)
+	if err != nil {
+		return nil, err
+	}
+	err = serviceImpl.startHealthCheckCron()
+	if err != nil {
+		return nil, err
+	}
+	return serviceImpl, nil
+}
+
+func newClusterHealthServiceImpl(logger *zap.SugaredLogger, clusterService cluster.ClusterService, K8sUtil *k8s.K8sUtil,
+	clusterHealthRepository repository.ClusterHealthRepository) (*ClusterHealthServiceImpl, error) {
+	config := &ClusterHealthConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing ClusterHealthConfig from env", "err", err)
+		return nil, err
+	}
+	return &ClusterHealthServiceImpl{
+		logger:                  logger,
+		clusterService:          clusterService,
+		K8sUtil:                 K8sUtil,
+		clusterHealthRepository: clusterHealthRepository,
+		config:                  config,
+	}, nil
+}
+
+func (impl *ClusterHealthServiceImpl) startHealthCheckCron() error {
+	if !impl.config.CheckEnabled {
+		return nil
+	}
+	healthCron := cron.New(cron.WithChain())
+	healthCron.Start()
+	_, err := healthCron.AddFunc(fmt.Sprintf("@every %dm", impl.config.CheckIntervalMins), impl.CheckClusters)
+	if err != nil {
+		impl.logger.Errorw("error in adding cluster health check cron", "err", err, "intervalMins", impl.config.CheckIntervalMins)
+		return err
+	}
+	return nil
+}
+
+func (impl *ClusterHealthServiceImpl) CheckClusters() {
+	impl.logger.Debug("starting cluster health checks")
+	defer impl.logger.Debug("stopped cluster health checks")
+	clusters, err := impl.clusterService.FindAll()
+	if err != nil {
+		impl.logger.Errorw("error in getting all clusters", "err", err)
+		return
+	}
+	checkedOn := time.Now()
+	wg := &sync.WaitGroup{}
+	for _, clusterBean := range clusters {
+		if clusterBean.IsVirtualCluster {
+			continue
+		}
+		wg.Add(1)
+		go func(clusterBean *cluster.ClusterBean) {
+			defer wg.Done()
+			impl.checkAndSaveClusterHealth(clusterBean, checkedOn)
+		}(clusterBean)
+	}
+	wg.Wait()
+	err = impl.clusterHealthRepository.DeleteChecksBefore(checkedOn.AddDate(0, 0, -impl.config.RetentionDays))
+	if err != nil {
+		impl.logger.Errorw("error in deleting old cluster health checks", "err", err)
+	}
+}
+
+func (impl *ClusterHealthServiceImpl) checkAndSaveClusterHealth(clusterBean *cluster.ClusterBean, checkedOn time.Time) {
+	health := impl.checkClusterHealth(clusterBean, checkedOn)
+	previousCheck, err := impl.clusterHealthRepository.FindLatestByClusterId(clusterBean.Id)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting previous cluster health check", "err", err, "clusterId", clusterBean.Id)
+		return
+	}
+	check, err := toClusterHealthCheck(health)
+	if err != nil {
+		impl.logger.Errorw("error in converting cluster health", "err", err, "clusterId", clusterBean.Id)
+		return
+	}
+	err = impl.clusterHealthRepository.Save(check)
+	if err != nil {
+		impl.logger.Errorw("error in saving cluster health check", "err", err, "clusterId", clusterBean.Id)
+		return
+	}
+	becameUnhealthy := health.Status == bean.ClusterUnhealthy && (previousCheck == nil || previousCheck.Status != bean.ClusterUnhealthy)
+	if becameUnhealthy && impl.hooks != nil {
+		impl.hooks.notifyUnhealthy(health)
+	}
+}
+
+func (impl *ClusterHealthServiceImpl) checkClusterHealth(clusterBean *cluster.ClusterBean, checkedOn time.Time) *bean.ClusterHealth {
+	ctx, cancel := context.WithTimeout(context.Background(), clusterHealthCheckTimeout)
+	defer cancel()
+	health := &bean.ClusterHealth{ClusterId: clusterBean.Id, ClusterName: clusterBean.ClusterName, CheckedOn: checkedOn}
+	if impl.hooks != nil {
+		health.ArgoCdStatus = impl.hooks.getArgoCdStatus(ctx, clusterBean)
+	}
+	clusterConfig, err := clusterBean.GetClusterConfig()
+	if err != nil {
+		health.Issues = append(health.Issues, fmt.Sprintf("invalid cluster config: %s", err.Error()))
+		evaluateClusterHealth(health, impl.config)
+		return health
+	}
+	health.CredentialExpiresOn = getCredentialExpiry(clusterConfig)
+	restConfig, k8sHttpClient, k8sClientSet, err := impl.K8sUtil.GetK8sConfigAndClients(clusterConfig)
+	if err != nil {
+		health.Issues = append(health.Issues, fmt.Sprintf("api server is not reachable: %s", err.Error()))
+		evaluateClusterHealth(health, impl.config)
+		return health
+	}
+	startedOn := time.Now()
+	response, err := impl.K8sUtil.GetLiveZCall(k8s.LiveZ, k8sClientSet)
+	health.LatencyMs = time.Since(startedOn).Milliseconds()
+	if err != nil || string(response) != "ok" {
+		if err == nil {
+			err = fmt.Errorf("livez responded %s", string(response))
+		}
+		health.Issues = append(health.Issues, fmt.Sprintf("api server is not reachable: %s", err.Error()))
+		evaluateClusterHealth(health, impl.config)
+		return health
+	}
+	health.Reachable = true
+
+	var kubeletVersions []string
+	nodeList, err := impl.K8sUtil.GetNodesList(ctx, k8sClientSet)
+	if err != nil {
+		health.Issues = append(health.Issues, fmt.Sprintf("nodes could not be listed: %s", err.Error()))
+	} else {
+		health.TotalNodes = len(nodeList.Items)
+		for _, node := range nodeList.Items {
+			if !isNodeReady(node) {
+				health.NotReadyNodes++
+			}
+			kubeletVersions = append(kubeletVersions, node.Status.NodeInfo.KubeletVersion)
+		}
+	}
+	serverVersion, err := k8sClientSet.Discovery().ServerVersion()
+	if err != nil {
+		health.Issues = append(health.Issues, fmt.Sprintf("server version could not be fetched: %s", err.Error()))
+	} else {
+		health.ServerVersion = serverVersion.GitVersion
+		health.KubeletVersionSkew = getKubeletVersionSkew(serverVersion.GitVersion, kubeletVersions)
+	}
+	metricsClientSet, err := impl.K8sUtil.GetMetricsClientSet(restConfig, k8sHttpClient)
+	if err == nil {
+		_, err = metricsClientSet.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{Limit: 1})
+	}
+	health.MetricsServerAvailable = err == nil
+	evaluateClusterHealth(health, impl.config)
+	return health
+}
+
+// evaluateClusterHealth adds the issues found against the thresholds of the config and sets the status. Clusters which
+// are not reachable or whose credential has expired are unhealthy, clusters with any other issue are degraded
+func evaluateClusterHealth(health *bean.ClusterHealth, config *ClusterHealthConfig) {
+	unhealthy := !health.Reachable
+	if health.CredentialExpiresOn != nil {
+		if health.CredentialExpiresOn.Before(health.CheckedOn) {
+			unhealthy = true
+			health.Issues = append(health.Issues, fmt.Sprintf("credential expired on %s", health.CredentialExpiresOn.Format(time.RFC3339)))
+		} else if health.CredentialExpiresOn.Before(health.CheckedOn.AddDate(0, 0, config.CredentialExpiryWarningDays)) {
+			health.Issues = append(health.Issues, fmt.Sprintf("credential expires on %s", health.CredentialExpiresOn.Format(time.RFC3339)))
+		}
+	}
+	if health.Reachable {
+		if health.LatencyMs > config.LatencyThresholdMs {
+			health.Issues = append(health.Issues, fmt.Sprintf("api server latency %dms is above %dms", health.LatencyMs, config.LatencyThresholdMs))
+		}
+		if health.NotReadyNodes > 0 {
+			health.Issues = append(health.Issues, fmt.Sprintf("%d of %d nodes are NotReady", health.NotReadyNodes, health.TotalNodes))
+			if health.NotReadyNodes == health.TotalNodes {
+				unhealthy = true
+			}
+		}
+		if health.KubeletVersionSkew > config.MaxKubeletVersionSkew {
+			health.Issues = append(health.Issues, fmt.Sprintf("kubelet versions are %d minor versions away from api server %s", health.KubeletVersionSkew, health.ServerVersion))
+		}
+		if !health.MetricsServerAvailable {
+			health.Issues = append(health.Issues, "metrics server is not available")
+		}
+	}
+	switch health.ArgoCdStatus {
+	case bean.ArgoCdNotRegistered:
+		health.Issues = append(health.Issues, "cluster is not registered in argo cd")
+	case bean.ArgoCdFailed:
+		health.Issues = append(health.Issues, "argo cd cannot connect to the cluster")
+	}
+	if unhealthy {
+		health.Status = bean.ClusterUnhealthy
+	} else if len(health.Issues) > 0 {
+		health.Status = bean.ClusterDegraded
+	} else {
+		health.Status = bean.ClusterHealthy
+	}
+}
+
+func isNodeReady(node corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// getKubeletVersionSkew returns the largest number of minor versions a kubelet is away from the api server
+func getKubeletVersionSkew(serverVersion string, kubeletVersions []string) int {
+	server, err := version.ParseGeneric(serverVersion)
+	if err != nil {
+		return 0
+	}
+	maxSkew := 0
+	for _, kubeletVersion := range kubeletVersions {
+		kubelet, err := version.ParseGeneric(kubeletVersion)
+		if err != nil || kubelet.Major() != server.Major() {
+			continue
+		}
+		skew := int(server.Minor()) - int(kubelet.Minor())
+		if skew < 0 {
+			skew = -skew
+		}
+		if skew > maxSkew {
+			maxSkew = skew
+		}
+	}
+	return maxSkew
+}
+
+// getCredentialExpiry returns the earliest expiry of the client certificate and the bearer token, tokens which are not
+// jwts or do not expire, like legacy service account tokens, are ignored
+func getCredentialExpiry(clusterConfig *k8s.ClusterConfig) *time.Time {
+	var expiresOn *time.Time
+	if block, _ := pem.Decode([]byte(clusterConfig.CertData)); block != nil {
+		if certificate, err := x509.ParseCertificate(block.Bytes); err == nil {
+			notAfter := certificate.NotAfter
+			expiresOn = &notAfter
+		}
+	}
+	if parts := strings.Split(clusterConfig.BearerToken, "."); len(parts) == 3 {
+		claims := struct {
+			Exp int64 `json:"exp"`
+		}{}
+		payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+		if err == nil && json.Unmarshal(payload, &claims) == nil && claims.Exp > 0 {
+			tokenExpiresOn := time.Unix(claims.Exp, 0)
+			if expiresOn == nil || tokenExpiresOn.Before(*expiresOn) {
+				expiresOn = &tokenExpiresOn
+			}
+		}
+	}
+	return expiresOn
+}
+
+func (impl *ClusterHealthServiceImpl) GetLatestHealth() ([]*bean.ClusterHealth, error) {
+	checks, err := impl.clusterHealthRepository.FindLatestForAllClusters()
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := impl.clusterService.FindAllWithoutConfig()
+	if err != nil {
+		impl.logger.Errorw("error in getting all clusters", "err", err)
+		return nil, err
+	}
+	clusterNames := make(map[int]string, len(clusters))
+	for _, clusterBean := range clusters {
+		clusterNames[clusterBean.Id] = clusterBean.ClusterName
+	}
+	healths := make([]*bean.ClusterHealth, 0, len(checks))
+	for _, check := range checks {
+		clusterName, ok := clusterNames[check.ClusterId]
+		if !ok {
+			// deleted cluster
+			continue
+		}
+		health := toClusterHealthBean(check)
+		health.ClusterName = clusterName
+		healths = append(healths, health)
+	}
+	return healths, nil
+}
+
+func (impl *ClusterHealthServiceImpl) GetHealthHistory(clusterId int, from, to time.Time) ([]*bean.ClusterHealth, error) {
+	checks, err := impl.clusterHealthRepository.FindByClusterId(clusterId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	healths := make([]*bean.ClusterHealth, 0, len(checks))
+	for _, check := range checks {
+		healths = append(healths, toClusterHealthBean(check))
+	}
+	return healths, nil
+}
+
+func toClusterHealthCheck(health *bean.ClusterHealth) (*repository.ClusterHealthCheck, error) {
+	issues, err := json.Marshal(health.Issues)
+	if err != nil {
+		return nil, err
+	}
+	check := &repository.ClusterHealthCheck{
+		ClusterId:              health.ClusterId,
+		Status:                 health.Status,
+		Reachable:              health.Reachable,
+		LatencyMs:              health.LatencyMs,
+		ServerVersion:          health.ServerVersion,
+		KubeletVersionSkew:     health.KubeletVersionSkew,
+		MetricsServerAvailable: health.MetricsServerAvailable,
+		TotalNodes:             health.TotalNodes,
+		NotReadyNodes:          health.NotReadyNodes,
+		ArgoCdStatus:           health.ArgoCdStatus,
+		Issues:                 string(issues),
+		CheckedOn:              health.CheckedOn,
+	}
+	if health.CredentialExpiresOn != nil {
+		check.CredentialExpiresOn = *health.CredentialExpiresOn
+	}
+	return check, nil
+}
+
+func toClusterHealthBean(check *repository.ClusterHealthCheck) *bean.ClusterHealth {
+	health := &bean.ClusterHealth{
+		ClusterId:              check.ClusterId,
+		Status:                 check.Status,
+		Reachable:              check.Reachable,
+		LatencyMs:              check.LatencyMs,
+		ServerVersion:          check.ServerVersion,
+		KubeletVersionSkew:     check.KubeletVersionSkew,
+		MetricsServerAvailable: check.MetricsServerAvailable,
+		TotalNodes:             check.TotalNodes,
+		NotReadyNodes:          check.NotReadyNodes,
+		ArgoCdStatus:           check.ArgoCdStatus,
+		Issues:                 make([]string, 0),
+		CheckedOn:              check.CheckedOn,
+	}
+	if !check.CredentialExpiresOn.IsZero() {
+		credentialExpiresOn := check.CredentialExpiresOn
+		health.CredentialExpiresOn = &credentialExpiresOn
+	}
+	if len(check.Issues) > 0 {
+		_ = json.Unmarshal([]byte(check.Issues), &health.Issues)
+	}
+	return health
+}
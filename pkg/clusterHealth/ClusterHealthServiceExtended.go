@@ -0,0 +1,85 @@
+package clusterHealth
+
+import (
+	"context"
+	cluster3 "github.com/argoproj/argo-cd/v2/pkg/apiclient/cluster"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	cluster2 "github.com/devtron-labs/devtron/client/argocdServer/cluster"
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	"github.com/devtron-labs/devtron/pkg/clusterHealth/bean"
+	"github.com/devtron-labs/devtron/pkg/clusterHealth/repository"
+	"github.com/devtron-labs/devtron/util/argo"
+	util "github.com/devtron-labs/devtron/util/event"
+	"github.com/devtron-labs/devtron/util/k8s"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+// ClusterHealthServiceImplExtended extends ClusterHealthServiceImpl with the argo cd registration check and the
+// notification of unhealthy clusters of the full mode
+type ClusterHealthServiceImplExtended struct {
+	clusterServiceCD cluster2.ServiceClient
+	argoUserService  argo.ArgoUserService
+	eventClient      client.EventClient
+	eventFactory     client.EventFactory
+	*ClusterHealthServiceImpl
+}
+
+func NewClusterHealthServiceImplExtended(logger *zap.SugaredLogger, clusterService cluster.ClusterService, K8sUtil *k8s.K8sUtil,
+	clusterHealthRepository repository.ClusterHealthRepository, clusterServiceCD cluster2.ServiceClient,
+	argoUserService argo.ArgoUserService, eventClient client.EventClient, eventFactory client.EventFactory) (*ClusterHealthServiceImplExtended, error) {
+	serviceImpl, err := newClusterHealthServiceImpl(logger, clusterService, K8sUtil, clusterHealthRepository)
+	if err != nil {
+		return nil, err
+	}
+	serviceImplExtended := &ClusterHealthServiceImplExtended{
+		clusterServiceCD:         clusterServiceCD,
+		argoUserService:          argoUserService,
+		eventClient:              eventClient,
+		eventFactory:             eventFactory,
+		ClusterHealthServiceImpl: serviceImpl,
+	}
+	serviceImpl.hooks = serviceImplExtended
+	err = serviceImpl.startHealthCheckCron()
+	if err != nil {
+		return nil, err
+	}
+	return serviceImplExtended, nil
+}
+
+func (impl *ClusterHealthServiceImplExtended) getArgoCdStatus(ctx context.Context, clusterBean *cluster.ClusterBean) string {
+	acdToken, err := impl.argoUserService.GetLatestDevtronArgoCdUserToken()
+	if err != nil {
+		impl.logger.Errorw("error in getting acd token", "err", err)
+		return ""
+	}
+	ctx = context.WithValue(ctx, "token", acdToken)
+	argoCluster, err := impl.clusterServiceCD.Get(ctx, &cluster3.ClusterQuery{Server: clusterBean.ServerUrl})
+	if err != nil {
+		// argo cd responds permission denied for clusters which do not exist
+		if code := status.Code(err); code == codes.NotFound || code == codes.PermissionDenied {
+			return bean.ArgoCdNotRegistered
+		}
+		impl.logger.Errorw("error in getting argo cd cluster", "err", err, "clusterId", clusterBean.Id)
+		return ""
+	}
+	if argoCluster.Info.ConnectionState.Status == v1alpha1.ConnectionStatusFailed {
+		return bean.ArgoCdFailed
+	}
+	return bean.ArgoCdRegistered
+}
+
+func (impl *ClusterHealthServiceImplExtended) notifyUnhealthy(health *bean.ClusterHealth) {
+	event := impl.eventFactory.Build(util.ClusterUnhealthy, nil, 0, nil, util.CLUSTER)
+	event.Payload = &client.Payload{
+		ClusterName:   health.ClusterName,
+		FailureReason: strings.Join(health.Issues, ", "),
+	}
+	_, err := impl.eventClient.WriteNotificationEvent(event)
+	if err != nil {
+		impl.logger.Errorw("error in sending cluster unhealthy event", "err", err, "clusterId", health.ClusterId)
+	}
+}
@@ -0,0 +1,80 @@
+package clusterHealth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/clusterHealth/bean"
+	"github.com/devtron-labs/devtron/util/k8s"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+	"time"
+)
+
+var testHealthConfig = &ClusterHealthConfig{LatencyThresholdMs: 2000, MaxKubeletVersionSkew: 2, CredentialExpiryWarningDays: 14}
+
+func TestEvaluateClusterHealth(t *testing.T) {
+	now := time.Now()
+	health := &bean.ClusterHealth{Reachable: true, LatencyMs: 100, MetricsServerAvailable: true, TotalNodes: 3, CheckedOn: now}
+	evaluateClusterHealth(health, testHealthConfig)
+	assert.Equal(t, bean.ClusterHealthy, health.Status)
+	assert.Empty(t, health.Issues)
+
+	expiresOn := now.AddDate(0, 0, 7)
+	health = &bean.ClusterHealth{Reachable: true, LatencyMs: 100, TotalNodes: 3, NotReadyNodes: 1, KubeletVersionSkew: 3,
+		CredentialExpiresOn: &expiresOn, ArgoCdStatus: bean.ArgoCdNotRegistered, CheckedOn: now}
+	evaluateClusterHealth(health, testHealthConfig)
+	assert.Equal(t, bean.ClusterDegraded, health.Status)
+	assert.Len(t, health.Issues, 5)
+
+	health = &bean.ClusterHealth{Reachable: true, MetricsServerAvailable: true, TotalNodes: 2, NotReadyNodes: 2, CheckedOn: now}
+	evaluateClusterHealth(health, testHealthConfig)
+	assert.Equal(t, bean.ClusterUnhealthy, health.Status)
+
+	expiredOn := now.Add(-time.Hour)
+	health = &bean.ClusterHealth{Reachable: true, MetricsServerAvailable: true, CredentialExpiresOn: &expiredOn, CheckedOn: now}
+	evaluateClusterHealth(health, testHealthConfig)
+	assert.Equal(t, bean.ClusterUnhealthy, health.Status)
+
+	// checks of unreachable clusters are not evaluated
+	health = &bean.ClusterHealth{Issues: []string{"api server is not reachable"}, CheckedOn: now}
+	evaluateClusterHealth(health, testHealthConfig)
+	assert.Equal(t, bean.ClusterUnhealthy, health.Status)
+	assert.Len(t, health.Issues, 1)
+}
+
+func TestGetKubeletVersionSkew(t *testing.T) {
+	assert.Equal(t, 0, getKubeletVersionSkew("v1.27.3-eks-a5565ad", []string{"v1.27.1-eks-2f008fe", "v1.27.3"}))
+	assert.Equal(t, 3, getKubeletVersionSkew("v1.27.3", []string{"v1.26.5", "v1.24.9"}))
+	assert.Equal(t, 1, getKubeletVersionSkew("v1.27.3", []string{"v1.28.0", "invalid"}))
+	assert.Equal(t, 0, getKubeletVersionSkew("", []string{"v1.24.9"}))
+}
+
+func TestGetCredentialExpiry(t *testing.T) {
+	assert.Nil(t, getCredentialExpiry(&k8s.ClusterConfig{BearerToken: "not-a-jwt"}))
+
+	tokenExpiresOn := time.Now().Add(time.Hour).Truncate(time.Second)
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"system:serviceaccount:devtroncd:cd-user","exp":%d}`, tokenExpiresOn.Unix())))
+	token := "eyJhbGciOiJSUzI1NiJ9." + payload + ".signature"
+	expiresOn := getCredentialExpiry(&k8s.ClusterConfig{BearerToken: token})
+	assert.NotNil(t, expiresOn)
+	assert.True(t, tokenExpiresOn.Equal(*expiresOn))
+
+	// the earlier of the certificate and token expiry
+	certificateExpiresOn := time.Now().Add(30 * time.Minute).Truncate(time.Second).UTC()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "admin"}, NotBefore: time.Now(), NotAfter: certificateExpiresOn}
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	certData := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate}))
+	expiresOn = getCredentialExpiry(&k8s.ClusterConfig{BearerToken: token, CertData: certData})
+	assert.NotNil(t, expiresOn)
+	assert.True(t, certificateExpiresOn.Equal(*expiresOn))
+}
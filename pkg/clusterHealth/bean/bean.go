@@ -0,0 +1,34 @@
+package bean
+
+import "time"
+
+const (
+	ClusterHealthy   = "Healthy"
+	ClusterDegraded  = "Degraded"
+	ClusterUnhealthy = "Unhealthy"
+)
+
+const (
+	// ArgoCdRegistered is the status of clusters registered in argo cd whose connection state is successful
+	ArgoCdRegistered    = "Registered"
+	ArgoCdNotRegistered = "NotRegistered"
+	ArgoCdFailed        = "Failed"
+)
+
+type ClusterHealth struct {
+	ClusterId              int        `json:"clusterId"`
+	ClusterName            string     `json:"clusterName,omitempty"`
+	Status                 string     `json:"status"`
+	Reachable              bool       `json:"reachable"`
+	LatencyMs              int64      `json:"latencyMs"`
+	ServerVersion          string     `json:"serverVersion,omitempty"`
+	KubeletVersionSkew     int        `json:"kubeletVersionSkew"`
+	CredentialExpiresOn    *time.Time `json:"credentialExpiresOn,omitempty"`
+	MetricsServerAvailable bool       `json:"metricsServerAvailable"`
+	TotalNodes             int        `json:"totalNodes"`
+	NotReadyNodes          int        `json:"notReadyNodes"`
+	// ArgoCdStatus is empty when argo cd is not used
+	ArgoCdStatus string    `json:"argoCdStatus,omitempty"`
+	Issues       []string  `json:"issues"`
+	CheckedOn    time.Time `json:"checkedOn"`
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// ClusterHealthCheck is the result of one health check of a cluster, Issues is the json array of the problems found
+type ClusterHealthCheck struct {
+	tableName              struct{}  `sql:"cluster_health_check" pg:",discard_unknown_columns"`
+	Id                     int       `sql:"id,pk"`
+	ClusterId              int       `sql:"cluster_id,notnull"`
+	Status                 string    `sql:"status,notnull"`
+	Reachable              bool      `sql:"reachable,notnull"`
+	LatencyMs              int64     `sql:"latency_ms,notnull"`
+	ServerVersion          string    `sql:"server_version"`
+	KubeletVersionSkew     int       `sql:"kubelet_version_skew,notnull"`
+	CredentialExpiresOn    time.Time `sql:"credential_expires_on,type:timestamptz"`
+	MetricsServerAvailable bool      `sql:"metrics_server_available,notnull"`
+	TotalNodes             int       `sql:"total_nodes,notnull"`
+	NotReadyNodes          int       `sql:"not_ready_nodes,notnull"`
+	ArgoCdStatus           string    `sql:"argo_cd_status"`
+	Issues                 string    `sql:"issues"`
+	CheckedOn              time.Time `sql:"checked_on,type:timestamptz"`
+}
+
+type ClusterHealthRepository interface {
+	Save(check *ClusterHealthCheck) error
+	FindLatestForAllClusters() ([]*ClusterHealthCheck, error)
+	FindLatestByClusterId(clusterId int) (*ClusterHealthCheck, error)
+	FindByClusterId(clusterId int, from, to time.Time) ([]*ClusterHealthCheck, error)
+	DeleteChecksBefore(checkedOn time.Time) error
+}
+
+type ClusterHealthRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewClusterHealthRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ClusterHealthRepositoryImpl {
+	return &ClusterHealthRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ClusterHealthRepositoryImpl) Save(check *ClusterHealthCheck) error {
+	return impl.dbConnection.Insert(check)
+}
+
+func (impl *ClusterHealthRepositoryImpl) FindLatestForAllClusters() ([]*ClusterHealthCheck, error) {
+	var checks []*ClusterHealthCheck
+	query := "SELECT DISTINCT ON (cluster_id) * FROM cluster_health_check ORDER BY cluster_id, checked_on DESC;"
+	_, err := impl.dbConnection.Query(&checks, query)
+	if err != nil {
+		impl.logger.Errorw("error in getting latest cluster health checks", "err", err)
+		return nil, err
+	}
+	return checks, nil
+}
+
+func (impl *ClusterHealthRepositoryImpl) FindLatestByClusterId(clusterId int) (*ClusterHealthCheck, error) {
+	check := &ClusterHealthCheck{}
+	err := impl.dbConnection.Model(check).
+		Where("cluster_id = ?", clusterId).
+		Order("checked_on DESC").Limit(1).Select()
+	if err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+func (impl *ClusterHealthRepositoryImpl) FindByClusterId(clusterId int, from, to time.Time) ([]*ClusterHealthCheck, error) {
+	var checks []*ClusterHealthCheck
+	err := impl.dbConnection.Model(&checks).
+		Where("cluster_id = ?", clusterId).
+		Where("checked_on >= ?", from).
+		Where("checked_on <= ?", to).
+		Order("checked_on ASC").Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting cluster health checks", "err", err, "clusterId", clusterId)
+		return nil, err
+	}
+	return checks, nil
+}
+
+func (impl *ClusterHealthRepositoryImpl) DeleteChecksBefore(checkedOn time.Time) error {
+	_, err := impl.dbConnection.Model((*ClusterHealthCheck)(nil)).
+		Where("checked_on < ?", checkedOn).Delete()
+	return err
+}
@@ -96,6 +96,7 @@ func NewUserTerminalAccessServiceImpl(logger *zap.SugaredLogger, terminalAccessR
 		K8sCapacityService:           K8sCapacityService,
 		k8sUtil:                      k8sUtil,
 	}
+	terminalSessionHandler.RegisterListener(accessServiceImpl)
 	podStatusSyncCron.Start()
 	_, err := podStatusSyncCron.AddFunc(fmt.Sprintf("@every %ds", config.TerminalPodStatusSyncTimeInSecs), accessServiceImpl.SyncPodStatus)
 	if err != nil {
@@ -349,6 +350,27 @@ func (impl *UserTerminalAccessServiceImpl) DisconnectTerminalSession(ctx context
 	return err
 }
 
+// OnTerminalSessionTerminated deletes the terminal pod of a session closed for exceeding the limits of its cluster or
+// by an admin
+func (impl *UserTerminalAccessServiceImpl) OnTerminalSessionTerminated(request *terminal.TerminalSessionRequest, clusterId int) {
+	userTerminalAccessId := 0
+	impl.TerminalAccessDataArrayMutex.RLock()
+	for terminalAccessId, accessSessionData := range *impl.TerminalAccessSessionDataMap {
+		if accessSessionData.sessionId == request.SessionId {
+			userTerminalAccessId = terminalAccessId
+			break
+		}
+	}
+	impl.TerminalAccessDataArrayMutex.RUnlock()
+	if userTerminalAccessId == 0 {
+		return
+	}
+	err := impl.DisconnectTerminalSession(context.Background(), userTerminalAccessId)
+	if err != nil {
+		impl.Logger.Errorw("error in deleting terminal pod of terminated session", "err", err, "userTerminalAccessId", userTerminalAccessId, "clusterId", clusterId)
+	}
+}
+
 func getErrorDetailedMessage(err error) string {
 	if errStatus, ok := err.(*k8sErrors.StatusError); ok {
 		return errStatus.Status().Message
@@ -577,6 +599,7 @@ func (impl *UserTerminalAccessServiceImpl) checkAndStartSession(ctx context.Cont
 			Namespace: namespace,
 			PodName:   terminalAccessPodName,
 			ClusterId: clusterId,
+			UserId:    terminalAccessData.UserId,
 		}
 		_, terminalMessage, err := impl.terminalSessionHandler.GetTerminalSession(request)
 		if err != nil {
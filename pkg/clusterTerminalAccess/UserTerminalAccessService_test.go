@@ -173,6 +173,7 @@ func loadUserTerminalAccessService(t *testing.T) (*mocks.TerminalAccessRepositor
 	terminalSessionHandler := mocks2.NewTerminalSessionHandler(t)
 	k8sApplicationService := mocks3.NewK8sApplicationService(t)
 	terminalAccessRepository.On("GetAllRunningUserTerminalData").Return(nil, nil)
+	terminalSessionHandler.On("RegisterListener", mock.Anything).Return()
 	terminalAccessServiceImpl, err := NewUserTerminalAccessServiceImpl(logger, terminalAccessRepository, userTerminalSessionConfig, nil, terminalSessionHandler, nil, nil)
 	assert.Nil(t, err)
 	return terminalAccessRepository, terminalSessionHandler, k8sApplicationService, terminalAccessServiceImpl
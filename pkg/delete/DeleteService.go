@@ -1,6 +1,7 @@
 package delete
 
 import (
+	"context"
 	"fmt"
 	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
 	"github.com/devtron-labs/devtron/pkg/appStore/deployment/repository"
@@ -13,7 +14,7 @@ import (
 )
 
 type DeleteService interface {
-	DeleteCluster(deleteRequest *cluster.ClusterBean, userId int32) error
+	DeleteCluster(ctx context.Context, deleteRequest *cluster.ClusterBean, userId int32) error
 	DeleteEnvironment(deleteRequest *cluster.EnvironmentBean, userId int32) error
 	DeleteTeam(deleteRequest *team.TeamRequest) error
 	DeleteChartRepo(deleteRequest *chartRepo.ChartRepoDto) error
@@ -53,8 +54,8 @@ func NewDeleteServiceImpl(logger *zap.SugaredLogger,
 	}
 }
 
-func (impl DeleteServiceImpl) DeleteCluster(deleteRequest *cluster.ClusterBean, userId int32) error {
-	err := impl.clusterService.DeleteFromDb(deleteRequest, userId)
+func (impl DeleteServiceImpl) DeleteCluster(ctx context.Context, deleteRequest *cluster.ClusterBean, userId int32) error {
+	err := impl.clusterService.DeleteFromDb(ctx, deleteRequest, userId)
 	if err != nil {
 		impl.logger.Errorw("error im deleting cluster", "err", err, "deleteRequest", deleteRequest)
 		return err
@@ -12,7 +12,7 @@ func TestAllClusterAccessProvided(t *testing.T) {
 	appliedClusterIdsCsv := "-1"
 	ignoredClusterIdsCsv := ""
 	clusterId := 2
-	accessProvided := CheckIfImagePullSecretAccessProvided(appliedClusterIdsCsv, ignoredClusterIdsCsv, clusterId)
+	accessProvided := CheckIfImagePullSecretAccessProvided(appliedClusterIdsCsv, ignoredClusterIdsCsv, clusterId, false)
 	assert.True(t, accessProvided)
 }
 
@@ -20,7 +20,7 @@ func TestClusterAccessProvidedInApplied(t *testing.T) {
 	appliedClusterIdsCsv := "1,2,3"
 	ignoredClusterIdsCsv := ""
 	clusterId := 2
-	accessProvided := CheckIfImagePullSecretAccessProvided(appliedClusterIdsCsv, ignoredClusterIdsCsv, clusterId)
+	accessProvided := CheckIfImagePullSecretAccessProvided(appliedClusterIdsCsv, ignoredClusterIdsCsv, clusterId, false)
 	assert.True(t, accessProvided)
 }
 
@@ -28,7 +28,7 @@ func TestClusterAccessNotProvidedInApplied(t *testing.T) {
 	appliedClusterIdsCsv := "1,2,3"
 	ignoredClusterIdsCsv := ""
 	clusterId := 4
-	accessProvided := CheckIfImagePullSecretAccessProvided(appliedClusterIdsCsv, ignoredClusterIdsCsv, clusterId)
+	accessProvided := CheckIfImagePullSecretAccessProvided(appliedClusterIdsCsv, ignoredClusterIdsCsv, clusterId, false)
 	assert.False(t, accessProvided)
 }
 
@@ -36,7 +36,7 @@ func TestNoClusterAccessProvided(t *testing.T) {
 	appliedClusterIdsCsv := ""
 	ignoredClusterIdsCsv := "-1"
 	clusterId := 2
-	accessProvided := CheckIfImagePullSecretAccessProvided(appliedClusterIdsCsv, ignoredClusterIdsCsv, clusterId)
+	accessProvided := CheckIfImagePullSecretAccessProvided(appliedClusterIdsCsv, ignoredClusterIdsCsv, clusterId, false)
 	assert.False(t, accessProvided)
 }
 
@@ -44,7 +44,7 @@ func TestClusterAccessProvidedInIgnored(t *testing.T) {
 	appliedClusterIdsCsv := ""
 	ignoredClusterIdsCsv := "1,2,3"
 	clusterId := 2
-	accessProvided := CheckIfImagePullSecretAccessProvided(appliedClusterIdsCsv, ignoredClusterIdsCsv, clusterId)
+	accessProvided := CheckIfImagePullSecretAccessProvided(appliedClusterIdsCsv, ignoredClusterIdsCsv, clusterId, false)
 	assert.False(t, accessProvided)
 }
 
@@ -52,7 +52,7 @@ func TestClusterAccessNotProvidedInIgnored(t *testing.T) {
 	appliedClusterIdsCsv := ""
 	ignoredClusterIdsCsv := "1,2,3"
 	clusterId := 4
-	accessProvided := CheckIfImagePullSecretAccessProvided(appliedClusterIdsCsv, ignoredClusterIdsCsv, clusterId)
+	accessProvided := CheckIfImagePullSecretAccessProvided(appliedClusterIdsCsv, ignoredClusterIdsCsv, clusterId, false)
 	assert.True(t, accessProvided)
 }
 
@@ -0,0 +1,278 @@
+package dockerRegistry
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	repository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	MediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	MediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	MediaTypeOciManifest        = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeOciIndex           = "application/vnd.oci.image.index.v1+json"
+
+	dockerHubRegistryHost            = "registry-1.docker.io"
+	registryConnectionInsecure       = "insecure"
+	registryConnectionSecureWithCert = "secure-with-cert"
+)
+
+// ErrManifestNotFound is returned when the repository has no manifest for the reference
+var ErrManifestNotFound = errors.New("manifest not found")
+
+// RegistryCredential is the access to a registry over its distribution api
+type RegistryCredential struct {
+	RegistryURL string
+	Username    string
+	Password    string
+	Connection  string
+	Cert        string
+}
+
+// GetRegistryCredential returns the credential of the registry, a token is fetched for ecr registries
+func GetRegistryCredential(store *repository.DockerArtifactStore) (*RegistryCredential, error) {
+	credential := &RegistryCredential{
+		RegistryURL: store.RegistryURL,
+		Username:    store.Username,
+		Password:    store.Password,
+		Connection:  store.Connection,
+		Cert:        store.Cert,
+	}
+	if store.RegistryType == repository.REGISTRYTYPE_ECR {
+		var err error
+		credential.Username, credential.Password, err = CreateCredentialForEcr(store.AWSRegion, store.AWSAccessKeyId, store.AWSSecretAccessKey)
+		if err != nil {
+			return nil, fmt.Errorf("error in getting ecr credentials: %w", err)
+		}
+	}
+	return credential, nil
+}
+
+// Manifest is a manifest read from a repository
+type Manifest struct {
+	MediaType string
+	Digest    string
+	Content   []byte
+}
+
+// RegistryApiClient calls the distribution api of a repository, authenticating on the challenges of the registry with
+// a basic auth or a bearer token of the credential
+type RegistryApiClient struct {
+	httpClient    *http.Client
+	baseUrl       string
+	repository    string
+	credential    *RegistryCredential
+	authorization string
+}
+
+func NewRegistryApiClient(credential *RegistryCredential, repository string, timeout time.Duration) (*RegistryApiClient, error) {
+	registryUrl := credential.RegistryURL
+	if !strings.HasPrefix(registryUrl, "http://") && !strings.HasPrefix(registryUrl, "https://") {
+		registryUrl = "https://" + registryUrl
+	}
+	parsedUrl, err := url.Parse(registryUrl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry url %q: %w", credential.RegistryURL, err)
+	}
+	host := parsedUrl.Host
+	if host == "docker.io" || host == "index.docker.io" {
+		host = dockerHubRegistryHost
+		if !strings.Contains(repository, "/") {
+			repository = "library/" + repository
+		}
+	}
+	tlsConfig := &tls.Config{}
+	if credential.Connection == registryConnectionInsecure {
+		tlsConfig.InsecureSkipVerify = true
+	} else if credential.Connection == registryConnectionSecureWithCert && len(credential.Cert) > 0 {
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM([]byte(credential.Cert)) {
+			return nil, fmt.Errorf("invalid certificate of registry %s", host)
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+	return &RegistryApiClient{
+		httpClient: &http.Client{Timeout: timeout, Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment}},
+		baseUrl:    parsedUrl.Scheme + "://" + host + "/v2/" + repository,
+		repository: repository,
+		credential: credential,
+	}, nil
+}
+
+func (client *RegistryApiClient) Repository() string {
+	return client.repository
+}
+
+// GetManifest reads the manifest of a tag or digest in any of the media types of the docker and OCI image specs
+func (client *RegistryApiClient) GetManifest(reference string) (*Manifest, error) {
+	accept := strings.Join([]string{MediaTypeDockerManifest, MediaTypeOciManifest, MediaTypeDockerManifestList, MediaTypeOciIndex}, ", ")
+	response, body, err := client.do(http.MethodGet, "/manifests/"+reference, nil, map[string]string{"Accept": accept})
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode == http.StatusNotFound {
+		return nil, ErrManifestNotFound
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error in getting manifest %s of %s: %s %s", reference, client.repository, response.Status, string(body))
+	}
+	digest := response.Header.Get("Docker-Content-Digest")
+	if len(digest) == 0 {
+		digest = GetDigest(body)
+	}
+	return &Manifest{
+		MediaType: strings.TrimSpace(strings.Split(response.Header.Get("Content-Type"), ";")[0]),
+		Digest:    digest,
+		Content:   body,
+	}, nil
+}
+
+// PutManifest pushes the manifest with the tag and returns its digest
+func (client *RegistryApiClient) PutManifest(tag string, mediaType string, content []byte) (string, error) {
+	response, body, err := client.do(http.MethodPut, "/manifests/"+tag, content, map[string]string{"Content-Type": mediaType})
+	if err != nil {
+		return "", err
+	}
+	if response.StatusCode != http.StatusCreated && response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error in pushing manifest %s of %s: %s %s", tag, client.repository, response.Status, string(body))
+	}
+	digest := response.Header.Get("Docker-Content-Digest")
+	if len(digest) == 0 {
+		digest = GetDigest(content)
+	}
+	return digest, nil
+}
+
+// DeleteManifest deletes the manifest of a digest, registries which do not allow deletes return an error
+func (client *RegistryApiClient) DeleteManifest(digest string) error {
+	response, body, err := client.do(http.MethodDelete, "/manifests/"+digest, nil, nil)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode == http.StatusNotFound {
+		return ErrManifestNotFound
+	}
+	if response.StatusCode != http.StatusAccepted && response.StatusCode != http.StatusOK {
+		return fmt.Errorf("error in deleting manifest %s of %s: %s %s", digest, client.repository, response.Status, string(body))
+	}
+	return nil
+}
+
+// do sends the request, authenticating once on a challenge of the registry
+func (client *RegistryApiClient) do(method string, path string, content []byte, headers map[string]string) (*http.Response, []byte, error) {
+	response, body, err := client.send(method, path, content, headers)
+	if err != nil || response.StatusCode != http.StatusUnauthorized {
+		return response, body, err
+	}
+	err = client.authenticate(response.Header.Get("WWW-Authenticate"))
+	if err != nil {
+		return nil, nil, err
+	}
+	return client.send(method, path, content, headers)
+}
+
+func (client *RegistryApiClient) send(method string, path string, content []byte, headers map[string]string) (*http.Response, []byte, error) {
+	request, err := http.NewRequest(method, client.baseUrl+path, bytes.NewReader(content))
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	if len(client.authorization) > 0 {
+		request.Header.Set("Authorization", client.authorization)
+	}
+	response, err := client.httpClient.Do(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return response, body, nil
+}
+
+func (client *RegistryApiClient) authenticate(challenge string) error {
+	scheme, params := parseChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		request, _ := http.NewRequest(http.MethodGet, client.baseUrl, nil)
+		request.SetBasicAuth(client.credential.Username, client.credential.Password)
+		client.authorization = request.Header.Get("Authorization")
+		return nil
+	case "bearer":
+		realm := params["realm"]
+		if len(realm) == 0 {
+			return fmt.Errorf("invalid authentication challenge of registry: %s", challenge)
+		}
+		query := url.Values{}
+		if service := params["service"]; len(service) > 0 {
+			query.Set("service", service)
+		}
+		query.Set("scope", fmt.Sprintf("repository:%s:pull,push,delete", client.repository))
+		request, err := http.NewRequest(http.MethodGet, realm+"?"+query.Encode(), nil)
+		if err != nil {
+			return err
+		}
+		if len(client.credential.Username) > 0 || len(client.credential.Password) > 0 {
+			request.SetBasicAuth(client.credential.Username, client.credential.Password)
+		}
+		response, err := client.httpClient.Do(request)
+		if err != nil {
+			return err
+		}
+		defer response.Body.Close()
+		if response.StatusCode != http.StatusOK {
+			return fmt.Errorf("error in getting token of registry: %s", response.Status)
+		}
+		token := struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}{}
+		err = json.NewDecoder(response.Body).Decode(&token)
+		if err != nil {
+			return err
+		}
+		if len(token.Token) == 0 {
+			token.Token = token.AccessToken
+		}
+		client.authorization = "Bearer " + token.Token
+		return nil
+	default:
+		return fmt.Errorf("unsupported authentication challenge of registry: %s", challenge)
+	}
+}
+
+// parseChallenge parses a WWW-Authenticate header like Bearer realm="https://auth.docker.io/token",service="registry.docker.io"
+func parseChallenge(challenge string) (string, map[string]string) {
+	params := make(map[string]string)
+	challenge = strings.TrimSpace(challenge)
+	index := strings.Index(challenge, " ")
+	if index < 0 {
+		return challenge, params
+	}
+	scheme := challenge[:index]
+	for _, param := range strings.Split(challenge[index+1:], ",") {
+		keyValue := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(keyValue) == 2 {
+			params[strings.ToLower(keyValue[0])] = strings.Trim(keyValue[1], "\"")
+		}
+	}
+	return scheme, params
+}
+
+func GetDigest(content []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(content))
+}
@@ -0,0 +1,16 @@
+package dockerRegistry
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io"`)
+	assert.Equal(t, "Bearer", scheme)
+	assert.Equal(t, "https://auth.docker.io/token", params["realm"])
+	assert.Equal(t, "registry.docker.io", params["service"])
+	scheme, _ = parseChallenge(`Basic realm="registry"`)
+	assert.True(t, strings.EqualFold("basic", scheme))
+}
@@ -0,0 +1,803 @@
+package environmentPolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env/v6"
+	util2 "github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	"github.com/devtron-labs/devtron/pkg/environmentPolicy/bean"
+	"github.com/devtron-labs/devtron/pkg/environmentPolicy/repository"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	k8s2 "github.com/devtron-labs/devtron/util/k8s"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const namespacePolicyReconcileTimeout = time.Minute
+
+var (
+	namespaceGvk     = schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}
+	resourceQuotaGvk = schema.GroupVersionKind{Version: "v1", Kind: "ResourceQuota"}
+	limitRangeGvk    = schema.GroupVersionKind{Version: "v1", Kind: "LimitRange"}
+	networkPolicyGvk = schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy"}
+)
+
+type NamespacePolicyConfig struct {
+	ReconcileEnabled      bool `env:"ENV_NAMESPACE_POLICY_RECONCILE_ENABLED" envDefault:"true"`
+	ReconcileIntervalMins int  `env:"ENV_NAMESPACE_POLICY_RECONCILE_INTERVAL_MINS" envDefault:"10"`
+}
+
+type NamespacePolicyService interface {
+	ValidatePolicy(policy *bean.NamespacePolicy) error
+	// SavePolicy saves the policy of the environment and applies it on the namespace of the environment
+	SavePolicy(ctx context.Context, policy *bean.NamespacePolicy, userId int32) (*bean.NamespacePolicyStatus, error)
+	// ReconcileEnvironment re-applies the saved policy of the environment recording the drift found, it returns nil
+	// when the environment has no policy
+	ReconcileEnvironment(environmentId int) (*bean.NamespacePolicyStatus, error)
+	// ReconcileAll re-applies the policies of all the environments recording the drift found
+	ReconcileAll()
+	GetPolicyStatus(environmentId int) (*bean.NamespacePolicyStatus, error)
+	GetDriftedPolicies() ([]*bean.NamespacePolicyStatus, error)
+	// AcknowledgeDrift clears the drift recorded for the environment, drift is kept across reconciliations until then
+	AcknowledgeDrift(environmentId int, userId int32) (*bean.NamespacePolicyStatus, error)
+}
+
+type NamespacePolicyServiceImpl struct {
+	logger                    *zap.SugaredLogger
+	environmentService        cluster.EnvironmentService
+	k8sCommonService          k8s.K8sCommonService
+	K8sUtil                   *k8s2.K8sUtil
+	namespacePolicyRepository repository.NamespacePolicyRepository
+	config                    *NamespacePolicyConfig
+	auditLogService           auditLog.AuditLogService
+}
+
+func NewNamespacePolicyServiceImpl(logger *zap.SugaredLogger, environmentService cluster.EnvironmentService,
+	k8sCommonService k8s.K8sCommonService, K8sUtil *k8s2.K8sUtil,
+	namespacePolicyRepository repository.NamespacePolicyRepository,
+	auditLogService auditLog.AuditLogService) (*NamespacePolicyServiceImpl, error) {
+	config := &NamespacePolicyConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing NamespacePolicyConfig from env", "err", err)
+		return nil, err
+	}
+	serviceImpl := &NamespacePolicyServiceImpl{
+		logger:                    logger,
+		environmentService:        environmentService,
+		k8sCommonService:          k8sCommonService,
+		K8sUtil:                   K8sUtil,
+		namespacePolicyRepository: namespacePolicyRepository,
+		config:                    config,
+		auditLogService:           auditLogService,
+	}
+	err = serviceImpl.startReconcileCron()
+	if err != nil {
+		return nil, err
+	}
+	return serviceImpl, nil
+}
+
+func (impl *NamespacePolicyServiceImpl) startReconcileCron() error {
+	if !impl.config.ReconcileEnabled {
+		return nil
+	}
+	reconcileCron := cron.New(cron.WithChain())
+	reconcileCron.Start()
+	_, err := reconcileCron.AddFunc(fmt.Sprintf("@every %dm", impl.config.ReconcileIntervalMins), impl.ReconcileAll)
+	if err != nil {
+		impl.logger.Errorw("error in adding namespace policy reconcile cron", "err", err, "intervalMins", impl.config.ReconcileIntervalMins)
+		return err
+	}
+	return nil
+}
+
+func (impl *NamespacePolicyServiceImpl) ValidatePolicy(policy *bean.NamespacePolicy) error {
+	var errs []string
+	quantityMaps := map[string]map[string]string{"resourceQuota": policy.ResourceQuota}
+	if policy.LimitRange != nil {
+		quantityMaps["limitRange.default"] = policy.LimitRange.Default
+		quantityMaps["limitRange.defaultRequest"] = policy.LimitRange.DefaultRequest
+		quantityMaps["limitRange.max"] = policy.LimitRange.Max
+		quantityMaps["limitRange.min"] = policy.LimitRange.Min
+	}
+	for field, quantities := range quantityMaps {
+		for name, quantity := range quantities {
+			if _, err := resource.ParseQuantity(quantity); err != nil {
+				errs = append(errs, fmt.Sprintf("invalid quantity %q of %s %s", quantity, field, name))
+			}
+		}
+	}
+	for key, value := range policy.Labels {
+		for _, msg := range append(validation.IsQualifiedName(key), validation.IsValidLabelValue(value)...) {
+			errs = append(errs, fmt.Sprintf("invalid label %s: %s", key, msg))
+		}
+	}
+	for key := range policy.Annotations {
+		for _, msg := range validation.IsQualifiedName(key) {
+			errs = append(errs, fmt.Sprintf("invalid annotation %s: %s", key, msg))
+		}
+	}
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		msg := strings.Join(errs, ", ")
+		return &util2.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: msg, InternalMessage: msg}
+	}
+	return nil
+}
+
+func (impl *NamespacePolicyServiceImpl) SavePolicy(ctx context.Context, policy *bean.NamespacePolicy, userId int32) (*bean.NamespacePolicyStatus, error) {
+	err := impl.ValidatePolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+	environment, err := impl.environmentService.FindById(policy.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in getting environment", "err", err, "environmentId", policy.EnvironmentId)
+		return nil, err
+	}
+	if environment.IsVirtualEnvironment {
+		msg := "namespace policies are not supported for virtual environments"
+		return nil, &util2.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: msg, InternalMessage: msg}
+	}
+	policyJson, err := json.Marshal(policy)
+	if err != nil {
+		impl.logger.Errorw("error in marshaling namespace policy", "err", err, "environmentId", policy.EnvironmentId)
+		return nil, err
+	}
+	model, err := impl.namespacePolicyRepository.FindByEnvironmentId(policy.EnvironmentId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting namespace policy", "err", err, "environmentId", policy.EnvironmentId)
+		return nil, err
+	}
+	var existingPolicy *bean.NamespacePolicy
+	if model != nil {
+		existingPolicy = &bean.NamespacePolicy{}
+		if err := json.Unmarshal([]byte(model.Policy), existingPolicy); err != nil {
+			impl.logger.Warnw("error in unmarshalling saved namespace policy", "err", err, "environmentId", policy.EnvironmentId)
+		}
+	}
+	now := time.Now()
+	if model == nil {
+		model = &repository.EnvironmentNamespacePolicy{
+			EnvironmentId: policy.EnvironmentId,
+			Policy:        string(policyJson),
+			Active:        true,
+			AuditLog:      sql.AuditLog{CreatedOn: now, CreatedBy: userId, UpdatedOn: now, UpdatedBy: userId},
+		}
+		err = impl.namespacePolicyRepository.Save(model)
+	} else {
+		model.Policy = string(policyJson)
+		model.UpdatedOn = now
+		model.UpdatedBy = userId
+		err = impl.namespacePolicyRepository.Update(model)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving namespace policy", "err", err, "environmentId", policy.EnvironmentId)
+		return nil, err
+	}
+	impl.recordAuditEvent(ctx, environment.Environment, policy.EnvironmentId, userId, existingPolicy, policy)
+	// the differences from a changed policy are not drift
+	return impl.reconcile(model, environment, false)
+}
+
+func (impl *NamespacePolicyServiceImpl) ReconcileEnvironment(environmentId int) (*bean.NamespacePolicyStatus, error) {
+	model, err := impl.namespacePolicyRepository.FindByEnvironmentId(environmentId)
+	if err == pg.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in getting namespace policy", "err", err, "environmentId", environmentId)
+		return nil, err
+	}
+	environment, err := impl.environmentService.FindById(environmentId)
+	if err != nil {
+		impl.logger.Errorw("error in getting environment", "err", err, "environmentId", environmentId)
+		return nil, err
+	}
+	return impl.reconcile(model, environment, true)
+}
+
+func (impl *NamespacePolicyServiceImpl) ReconcileAll() {
+	impl.logger.Debug("starting namespace policy reconciliation")
+	defer impl.logger.Debug("stopped namespace policy reconciliation")
+	models, err := impl.namespacePolicyRepository.FindAllActive()
+	if err != nil {
+		return
+	}
+	for _, model := range models {
+		environment, err := impl.environmentService.FindById(model.EnvironmentId)
+		if err == pg.ErrNoRows {
+			// the environment is deleted
+			continue
+		} else if err != nil {
+			impl.logger.Errorw("error in getting environment", "err", err, "environmentId", model.EnvironmentId)
+			continue
+		}
+		_, _ = impl.reconcile(model, environment, true)
+	}
+}
+
+func (impl *NamespacePolicyServiceImpl) GetPolicyStatus(environmentId int) (*bean.NamespacePolicyStatus, error) {
+	model, err := impl.namespacePolicyRepository.FindByEnvironmentId(environmentId)
+	if err != nil {
+		impl.logger.Errorw("error in getting namespace policy", "err", err, "environmentId", environmentId)
+		return nil, err
+	}
+	environment, err := impl.environmentService.FindById(environmentId)
+	if err != nil {
+		impl.logger.Errorw("error in getting environment", "err", err, "environmentId", environmentId)
+		return nil, err
+	}
+	return toNamespacePolicyStatus(model, environment)
+}
+
+func (impl *NamespacePolicyServiceImpl) AcknowledgeDrift(environmentId int, userId int32) (*bean.NamespacePolicyStatus, error) {
+	model, err := impl.namespacePolicyRepository.FindByEnvironmentId(environmentId)
+	if err != nil {
+		impl.logger.Errorw("error in getting namespace policy", "err", err, "environmentId", environmentId)
+		return nil, err
+	}
+	environment, err := impl.environmentService.FindById(environmentId)
+	if err != nil {
+		impl.logger.Errorw("error in getting environment", "err", err, "environmentId", environmentId)
+		return nil, err
+	}
+	model.Drift = ""
+	model.DriftDetectedOn = time.Time{}
+	model.UpdatedOn = time.Now()
+	model.UpdatedBy = userId
+	err = impl.namespacePolicyRepository.Update(model)
+	if err != nil {
+		impl.logger.Errorw("error in acknowledging namespace policy drift", "err", err, "environmentId", environmentId)
+		return nil, err
+	}
+	return toNamespacePolicyStatus(model, environment)
+}
+
+func (impl *NamespacePolicyServiceImpl) GetDriftedPolicies() ([]*bean.NamespacePolicyStatus, error) {
+	models, err := impl.namespacePolicyRepository.FindAllWithDrift()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]*bean.NamespacePolicyStatus, 0, len(models))
+	if len(models) == 0 {
+		return statuses, nil
+	}
+	environmentIds := make([]*int, 0, len(models))
+	for _, model := range models {
+		environmentId := model.EnvironmentId
+		environmentIds = append(environmentIds, &environmentId)
+	}
+	environments, err := impl.environmentService.FindByIds(environmentIds)
+	if err != nil {
+		impl.logger.Errorw("error in getting environments", "err", err, "environmentIds", environmentIds)
+		return nil, err
+	}
+	environmentMap := make(map[int]*cluster.EnvironmentBean, len(environments))
+	for _, environment := range environments {
+		environmentMap[environment.Id] = environment
+	}
+	for _, model := range models {
+		environment, ok := environmentMap[model.EnvironmentId]
+		if !ok {
+			continue
+		}
+		status, err := toNamespacePolicyStatus(model, environment)
+		if err != nil {
+			impl.logger.Errorw("error in converting namespace policy", "err", err, "environmentId", model.EnvironmentId)
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// reconcile applies the policy on the namespace of the environment and saves the result, the drift is only recorded
+// when reportDrift is set and is added to the drift recorded since it was last acknowledged
+func (impl *NamespacePolicyServiceImpl) reconcile(model *repository.EnvironmentNamespacePolicy, environment *cluster.EnvironmentBean, reportDrift bool) (*bean.NamespacePolicyStatus, error) {
+	policy := &bean.NamespacePolicy{}
+	err := json.Unmarshal([]byte(model.Policy), policy)
+	if err != nil {
+		impl.logger.Errorw("error in unmarshalling namespace policy", "err", err, "environmentId", model.EnvironmentId)
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), namespacePolicyReconcileTimeout)
+	defer cancel()
+	drift, reconcileErr := impl.applyPolicy(ctx, environment, policy)
+	now := time.Now()
+	model.LastReconciledOn = now
+	model.ReconcileError = ""
+	if reconcileErr != nil {
+		impl.logger.Errorw("error in applying namespace policy", "err", reconcileErr, "environmentId", model.EnvironmentId)
+		model.ReconcileError = reconcileErr.Error()
+	}
+	if reportDrift && len(drift) > 0 {
+		impl.logger.Infow("reverted drift of namespace from environment policy", "environmentId", model.EnvironmentId, "drift", drift)
+		driftJson, err := mergeDrift(model.Drift, drift)
+		if err != nil {
+			impl.logger.Errorw("error in merging namespace policy drift", "err", err, "environmentId", model.EnvironmentId)
+			return nil, err
+		}
+		if len(model.Drift) == 0 {
+			model.DriftDetectedOn = now
+		}
+		model.Drift = driftJson
+	}
+	err = impl.namespacePolicyRepository.Update(model)
+	if err != nil {
+		impl.logger.Errorw("error in updating namespace policy", "err", err, "environmentId", model.EnvironmentId)
+		return nil, err
+	}
+	return toNamespacePolicyStatus(model, environment)
+}
+
+// applyPolicy brings the namespace and the objects managed by the policy in line with it and returns the differences
+// which were found, objects which failed are skipped and reported in the error
+func (impl *NamespacePolicyServiceImpl) applyPolicy(ctx context.Context, environment *cluster.EnvironmentBean, policy *bean.NamespacePolicy) ([]string, error) {
+	if environment.IsVirtualEnvironment || len(environment.Namespace) == 0 {
+		return nil, fmt.Errorf("environment %s has no namespace", environment.Environment)
+	}
+	restConfig, err, _ := impl.k8sCommonService.GetRestConfigByClusterId(ctx, environment.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	drift, err := impl.applyNamespaceMetadata(ctx, environment, policy)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := getNamespaceObjects(environment.Namespace, policy)
+	if err != nil {
+		return nil, err
+	}
+	var errs []string
+	for _, object := range objects {
+		objectDrift, err := impl.reconcileObject(ctx, environment, restConfig, object)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s %s: %s", object.gvk.Kind, object.name, err.Error()))
+			continue
+		}
+		drift = append(drift, objectDrift...)
+	}
+	if len(errs) > 0 {
+		return drift, fmt.Errorf("error in applying %s", strings.Join(errs, ", "))
+	}
+	return drift, nil
+}
+
+func (impl *NamespacePolicyServiceImpl) applyNamespaceMetadata(ctx context.Context, environment *cluster.EnvironmentBean, policy *bean.NamespacePolicy) ([]string, error) {
+	if len(policy.Labels) == 0 && len(policy.Annotations) == 0 {
+		return nil, nil
+	}
+	namespace, err := impl.getLiveObject(ctx, environment.ClusterId, "", environment.Namespace, namespaceGvk)
+	if err != nil {
+		return nil, err
+	} else if namespace == nil {
+		return nil, fmt.Errorf("namespace %s not found", environment.Namespace)
+	}
+	drift := getNamespaceMetadataDrift(policy, namespace.GetLabels(), namespace.GetAnnotations())
+	if len(drift) == 0 {
+		return nil, nil
+	}
+	namespace.SetLabels(mergeMetadata(namespace.GetLabels(), policy.Labels))
+	namespace.SetAnnotations(mergeMetadata(namespace.GetAnnotations(), policy.Annotations))
+	err = impl.updateObject(ctx, environment.ClusterId, "", namespaceGvk, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return drift, nil
+}
+
+// reconcileObject creates or updates the object when it is missing from or differs in the namespace, and deletes it
+// when the policy no longer defines it
+func (impl *NamespacePolicyServiceImpl) reconcileObject(ctx context.Context, environment *cluster.EnvironmentBean, restConfig *rest.Config, object *namespaceObject) ([]string, error) {
+	live, err := impl.getLiveObject(ctx, environment.ClusterId, environment.Namespace, object.name, object.gvk)
+	if err != nil {
+		return nil, err
+	}
+	if object.desired == nil {
+		if live == nil || live.GetLabels()[bean.ManagedByLabelKey] != bean.ManagedByLabelName {
+			return nil, nil
+		}
+		_, err = impl.k8sCommonService.DeleteResource(ctx, newResourceRequest(environment.ClusterId, environment.Namespace, object.name, object.gvk, ""))
+		return nil, err
+	}
+	desired, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object.desired)
+	if err != nil {
+		return nil, err
+	}
+	if live == nil {
+		manifest, err := json.Marshal(desired)
+		if err != nil {
+			return nil, err
+		}
+		_, err = impl.K8sUtil.CreateResources(ctx, restConfig, string(manifest), object.gvk, environment.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		return []string{fmt.Sprintf("%s %s is missing", object.gvk.Kind, object.name)}, nil
+	}
+	drift, err := object.getDrift(live)
+	if err != nil || len(drift) == 0 {
+		return nil, err
+	}
+	desiredObject := &unstructured.Unstructured{Object: desired}
+	desiredObject.SetResourceVersion(live.GetResourceVersion())
+	err = impl.updateObject(ctx, environment.ClusterId, environment.Namespace, object.gvk, desiredObject)
+	if err != nil {
+		return nil, err
+	}
+	return drift, nil
+}
+
+// getLiveObject returns nil when the object does not exist
+func (impl *NamespacePolicyServiceImpl) getLiveObject(ctx context.Context, clusterId int, namespace, name string, gvk schema.GroupVersionKind) (*unstructured.Unstructured, error) {
+	resp, err := impl.k8sCommonService.GetResource(ctx, newResourceRequest(clusterId, namespace, name, gvk, ""))
+	if k8sErrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &resp.Manifest, nil
+}
+
+func (impl *NamespacePolicyServiceImpl) updateObject(ctx context.Context, clusterId int, namespace string, gvk schema.GroupVersionKind, object *unstructured.Unstructured) error {
+	manifest, err := object.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	_, err = impl.k8sCommonService.UpdateResource(ctx, newResourceRequest(clusterId, namespace, object.GetName(), gvk, string(manifest)))
+	return err
+}
+
+func newResourceRequest(clusterId int, namespace, name string, gvk schema.GroupVersionKind, patch string) *k8s.ResourceRequestBean {
+	return &k8s.ResourceRequestBean{
+		ClusterId: clusterId,
+		K8sRequest: &k8s2.K8sRequestBean{
+			ResourceIdentifier: k8s2.ResourceIdentifier{Name: name, Namespace: namespace, GroupVersionKind: gvk},
+			Patch:              patch,
+		},
+	}
+}
+
+// namespaceObject is an object of the namespace managed by the policy, desired is nil when the policy does not
+// define it
+type namespaceObject struct {
+	gvk      schema.GroupVersionKind
+	name     string
+	desired  runtime.Object
+	getDrift func(live *unstructured.Unstructured) ([]string, error)
+}
+
+func getNamespaceObjects(namespace string, policy *bean.NamespacePolicy) ([]*namespaceObject, error) {
+	resourceQuota, err := buildResourceQuota(namespace, policy)
+	if err != nil {
+		return nil, err
+	}
+	limitRange, err := buildLimitRange(namespace, policy)
+	if err != nil {
+		return nil, err
+	}
+	networkPolicy := buildNetworkPolicy(namespace, policy)
+	resourceQuotaObject := &namespaceObject{gvk: resourceQuotaGvk, name: bean.ResourceQuotaName}
+	if resourceQuota != nil {
+		resourceQuotaObject.desired = resourceQuota
+		resourceQuotaObject.getDrift = func(live *unstructured.Unstructured) ([]string, error) {
+			liveResourceQuota := &corev1.ResourceQuota{}
+			err := runtime.DefaultUnstructuredConverter.FromUnstructured(live.Object, liveResourceQuota)
+			if err != nil {
+				return nil, err
+			}
+			return getResourceListDrift("ResourceQuota hard", resourceQuota.Spec.Hard, liveResourceQuota.Spec.Hard, true), nil
+		}
+	}
+	limitRangeObject := &namespaceObject{gvk: limitRangeGvk, name: bean.LimitRangeName}
+	if limitRange != nil {
+		limitRangeObject.desired = limitRange
+		limitRangeObject.getDrift = func(live *unstructured.Unstructured) ([]string, error) {
+			liveLimitRange := &corev1.LimitRange{}
+			err := runtime.DefaultUnstructuredConverter.FromUnstructured(live.Object, liveLimitRange)
+			if err != nil {
+				return nil, err
+			}
+			return getLimitRangeDrift(limitRange, liveLimitRange), nil
+		}
+	}
+	networkPolicyObject := &namespaceObject{gvk: networkPolicyGvk, name: bean.NetworkPolicyName}
+	if networkPolicy != nil {
+		networkPolicyObject.desired = networkPolicy
+		networkPolicyObject.getDrift = func(live *unstructured.Unstructured) ([]string, error) {
+			liveNetworkPolicy := &networkingv1.NetworkPolicy{}
+			err := runtime.DefaultUnstructuredConverter.FromUnstructured(live.Object, liveNetworkPolicy)
+			if err != nil {
+				return nil, err
+			}
+			return getNetworkPolicyDrift(networkPolicy, liveNetworkPolicy), nil
+		}
+	}
+	return []*namespaceObject{resourceQuotaObject, limitRangeObject, networkPolicyObject}, nil
+}
+
+func getManagedObjectMeta(name, namespace string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: namespace,
+		Labels:    map[string]string{bean.ManagedByLabelKey: bean.ManagedByLabelName},
+	}
+}
+
+func buildResourceQuota(namespace string, policy *bean.NamespacePolicy) (*corev1.ResourceQuota, error) {
+	if len(policy.ResourceQuota) == 0 {
+		return nil, nil
+	}
+	hard, err := toResourceList(policy.ResourceQuota)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ResourceQuota{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: resourceQuotaGvk.Kind},
+		ObjectMeta: getManagedObjectMeta(bean.ResourceQuotaName, namespace),
+		Spec:       corev1.ResourceQuotaSpec{Hard: hard},
+	}, nil
+}
+
+func buildLimitRange(namespace string, policy *bean.NamespacePolicy) (*corev1.LimitRange, error) {
+	limitRange := policy.LimitRange
+	if limitRange == nil || len(limitRange.Default)+len(limitRange.DefaultRequest)+len(limitRange.Max)+len(limitRange.Min) == 0 {
+		return nil, nil
+	}
+	item := corev1.LimitRangeItem{Type: corev1.LimitTypeContainer}
+	var err error
+	if item.Default, err = toResourceList(limitRange.Default); err != nil {
+		return nil, err
+	}
+	if item.DefaultRequest, err = toResourceList(limitRange.DefaultRequest); err != nil {
+		return nil, err
+	}
+	if item.Max, err = toResourceList(limitRange.Max); err != nil {
+		return nil, err
+	}
+	if item.Min, err = toResourceList(limitRange.Min); err != nil {
+		return nil, err
+	}
+	return &corev1.LimitRange{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: limitRangeGvk.Kind},
+		ObjectMeta: getManagedObjectMeta(bean.LimitRangeName, namespace),
+		Spec:       corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{item}},
+	}, nil
+}
+
+func buildNetworkPolicy(namespace string, policy *bean.NamespacePolicy) *networkingv1.NetworkPolicy {
+	if policy.NetworkPolicy == nil || !(policy.NetworkPolicy.DenyIngress || policy.NetworkPolicy.DenyEgress) {
+		return nil
+	}
+	var policyTypes []networkingv1.PolicyType
+	if policy.NetworkPolicy.DenyIngress {
+		policyTypes = append(policyTypes, networkingv1.PolicyTypeIngress)
+	}
+	if policy.NetworkPolicy.DenyEgress {
+		policyTypes = append(policyTypes, networkingv1.PolicyTypeEgress)
+	}
+	return &networkingv1.NetworkPolicy{
+		TypeMeta:   metav1.TypeMeta{APIVersion: networkPolicyGvk.GroupVersion().String(), Kind: networkPolicyGvk.Kind},
+		ObjectMeta: getManagedObjectMeta(bean.NetworkPolicyName, namespace),
+		Spec:       networkingv1.NetworkPolicySpec{PodSelector: metav1.LabelSelector{}, PolicyTypes: policyTypes},
+	}
+}
+
+func toResourceList(quantities map[string]string) (corev1.ResourceList, error) {
+	if len(quantities) == 0 {
+		return nil, nil
+	}
+	resourceList := make(corev1.ResourceList, len(quantities))
+	for name, value := range quantities {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, err
+		}
+		resourceList[corev1.ResourceName(name)] = quantity
+	}
+	return resourceList, nil
+}
+
+// getResourceListDrift compares the quantities of the policy with the live ones, the live list may have more
+// resources unless exact is set
+func getResourceListDrift(field string, desired, live corev1.ResourceList, exact bool) []string {
+	var drift []string
+	for name, quantity := range desired {
+		liveQuantity, ok := live[name]
+		if !ok {
+			drift = append(drift, fmt.Sprintf("%s %s is missing", field, name))
+		} else if quantity.Cmp(liveQuantity) != 0 {
+			drift = append(drift, fmt.Sprintf("%s %s is %s instead of %s", field, name, liveQuantity.String(), quantity.String()))
+		}
+	}
+	if exact {
+		for name := range live {
+			if _, ok := desired[name]; !ok {
+				drift = append(drift, fmt.Sprintf("%s %s is not in the policy", field, name))
+			}
+		}
+	}
+	sort.Strings(drift)
+	return drift
+}
+
+func getLimitRangeDrift(desired, live *corev1.LimitRange) []string {
+	desiredItem := desired.Spec.Limits[0]
+	var liveItem *corev1.LimitRangeItem
+	for i := range live.Spec.Limits {
+		if live.Spec.Limits[i].Type == corev1.LimitTypeContainer {
+			liveItem = &live.Spec.Limits[i]
+			break
+		}
+	}
+	if liveItem == nil || len(live.Spec.Limits) > 1 {
+		return []string{"LimitRange limits are not in the policy"}
+	}
+	var drift []string
+	// the api server defaults the default limits and requests from the max limits, so they are only compared for
+	// the resources of the policy
+	drift = append(drift, getResourceListDrift("LimitRange default", desiredItem.Default, liveItem.Default, false)...)
+	drift = append(drift, getResourceListDrift("LimitRange defaultRequest", desiredItem.DefaultRequest, liveItem.DefaultRequest, false)...)
+	drift = append(drift, getResourceListDrift("LimitRange max", desiredItem.Max, liveItem.Max, true)...)
+	drift = append(drift, getResourceListDrift("LimitRange min", desiredItem.Min, liveItem.Min, true)...)
+	return drift
+}
+
+func getNetworkPolicyDrift(desired, live *networkingv1.NetworkPolicy) []string {
+	var drift []string
+	if len(live.Spec.PodSelector.MatchLabels) > 0 || len(live.Spec.PodSelector.MatchExpressions) > 0 {
+		drift = append(drift, "NetworkPolicy does not select all pods")
+	}
+	livePolicyTypes := make(map[networkingv1.PolicyType]bool, len(live.Spec.PolicyTypes))
+	for _, policyType := range live.Spec.PolicyTypes {
+		livePolicyTypes[policyType] = true
+	}
+	for _, policyType := range desired.Spec.PolicyTypes {
+		if !livePolicyTypes[policyType] {
+			drift = append(drift, fmt.Sprintf("NetworkPolicy does not deny %s", policyType))
+		}
+	}
+	if len(live.Spec.PolicyTypes) != len(desired.Spec.PolicyTypes) {
+		drift = append(drift, "NetworkPolicy policy types are not in the policy")
+	}
+	if livePolicyTypes[networkingv1.PolicyTypeIngress] && len(live.Spec.Ingress) > 0 {
+		drift = append(drift, "NetworkPolicy allows ingress")
+	}
+	if livePolicyTypes[networkingv1.PolicyTypeEgress] && len(live.Spec.Egress) > 0 {
+		drift = append(drift, "NetworkPolicy allows egress")
+	}
+	return drift
+}
+
+func getNamespaceMetadataDrift(policy *bean.NamespacePolicy, liveLabels, liveAnnotations map[string]string) []string {
+	var drift []string
+	for key, value := range policy.Labels {
+		if liveValue, ok := liveLabels[key]; !ok {
+			drift = append(drift, fmt.Sprintf("namespace label %s is missing", key))
+		} else if liveValue != value {
+			drift = append(drift, fmt.Sprintf("namespace label %s is %q instead of %q", key, liveValue, value))
+		}
+	}
+	for key, value := range policy.Annotations {
+		if liveValue, ok := liveAnnotations[key]; !ok {
+			drift = append(drift, fmt.Sprintf("namespace annotation %s is missing", key))
+		} else if liveValue != value {
+			drift = append(drift, fmt.Sprintf("namespace annotation %s is %q instead of %q", key, liveValue, value))
+		}
+	}
+	sort.Strings(drift)
+	return drift
+}
+
+func mergeMetadata(live, policy map[string]string) map[string]string {
+	merged := make(map[string]string, len(live)+len(policy))
+	for key, value := range live {
+		merged[key] = value
+	}
+	for key, value := range policy {
+		merged[key] = value
+	}
+	return merged
+}
+
+// mergeDrift adds the differences which are not recorded yet to the recorded drift json
+func mergeDrift(recordedDrift string, drift []string) (string, error) {
+	var merged []string
+	if len(recordedDrift) > 0 {
+		err := json.Unmarshal([]byte(recordedDrift), &merged)
+		if err != nil {
+			return "", err
+		}
+	}
+	recorded := make(map[string]bool, len(merged))
+	for _, difference := range merged {
+		recorded[difference] = true
+	}
+	for _, difference := range drift {
+		if !recorded[difference] {
+			recorded[difference] = true
+			merged = append(merged, difference)
+		}
+	}
+	driftJson, err := json.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+	return string(driftJson), nil
+}
+
+func toNamespacePolicyStatus(model *repository.EnvironmentNamespacePolicy, environment *cluster.EnvironmentBean) (*bean.NamespacePolicyStatus, error) {
+	policy := &bean.NamespacePolicy{}
+	err := json.Unmarshal([]byte(model.Policy), policy)
+	if err != nil {
+		return nil, err
+	}
+	policy.EnvironmentId = model.EnvironmentId
+	status := &bean.NamespacePolicyStatus{
+		EnvironmentId:         environment.Id,
+		EnvironmentName:       environment.Environment,
+		EnvironmentIdentifier: environment.EnvironmentIdentifier,
+		ClusterId:             environment.ClusterId,
+		Namespace:             environment.Namespace,
+		Policy:                policy,
+		Drift:                 make([]string, 0),
+		ReconcileError:        model.ReconcileError,
+	}
+	if len(model.Drift) > 0 {
+		err = json.Unmarshal([]byte(model.Drift), &status.Drift)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !model.DriftDetectedOn.IsZero() {
+		driftDetectedOn := model.DriftDetectedOn
+		status.DriftDetectedOn = &driftDetectedOn
+	}
+	if !model.LastReconciledOn.IsZero() {
+		lastReconciledOn := model.LastReconciledOn
+		status.LastReconciledOn = &lastReconciledOn
+	}
+	status.InSync = status.LastReconciledOn != nil && len(status.ReconcileError) == 0
+	return status, nil
+}
+
+func (impl *NamespacePolicyServiceImpl) recordAuditEvent(ctx context.Context, environmentName string, environmentId int, userId int32,
+	before *bean.NamespacePolicy, after *bean.NamespacePolicy) {
+	if impl.auditLogService == nil {
+		return
+	}
+	action := auditLog.ActionUpdate
+	if before == nil {
+		action = auditLog.ActionCreate
+	}
+	impl.auditLogService.RecordEvent(ctx, &auditLog.AuditEventRequest{
+		Action:       action,
+		ResourceType: auditLog.ResourceTypeNamespacePolicy,
+		ResourceId:   strconv.Itoa(environmentId),
+		ResourceName: environmentName,
+		UserId:       userId,
+		Before:       before,
+		After:        after,
+	})
+}
@@ -0,0 +1,121 @@
+package environmentPolicy
+
+import (
+	"github.com/devtron-labs/devtron/pkg/environmentPolicy/bean"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"testing"
+)
+
+func toTestUnstructured(t *testing.T, object runtime.Object) *unstructured.Unstructured {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+	assert.NoError(t, err)
+	return &unstructured.Unstructured{Object: content}
+}
+
+func TestValidatePolicy(t *testing.T) {
+	impl := &NamespacePolicyServiceImpl{}
+	policy := &bean.NamespacePolicy{
+		ResourceQuota: map[string]string{"requests.cpu": "4", "pods": "20"},
+		LimitRange:    &bean.LimitRange{Default: map[string]string{"memory": "512Mi"}},
+		Labels:        map[string]string{"team": "payments"},
+		Annotations:   map[string]string{"devtron.ai/owner": "Payments Team"},
+	}
+	assert.NoError(t, impl.ValidatePolicy(policy))
+
+	policy.ResourceQuota["requests.memory"] = "lots"
+	policy.Labels["team"] = "payments team"
+	err := impl.ValidatePolicy(policy)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "requests.memory")
+	assert.Contains(t, err.Error(), "invalid label team")
+}
+
+func TestGetNamespaceObjects(t *testing.T) {
+	policy := &bean.NamespacePolicy{
+		ResourceQuota: map[string]string{"requests.cpu": "1000m", "pods": "20"},
+		LimitRange:    &bean.LimitRange{Max: map[string]string{"memory": "1Gi"}},
+		NetworkPolicy: &bean.DefaultDenyNetworkPolicy{DenyIngress: true},
+	}
+	objects, err := getNamespaceObjects("prod", policy)
+	assert.NoError(t, err)
+	assert.Len(t, objects, 3)
+	for _, object := range objects {
+		assert.NotNil(t, object.desired)
+	}
+
+	// the api server returns canonical quantities
+	liveResourceQuota := objects[0].desired.(*corev1.ResourceQuota).DeepCopy()
+	liveResourceQuota.Spec.Hard[corev1.ResourceRequestsCPU] = resource.MustParse("1")
+	drift, err := objects[0].getDrift(toTestUnstructured(t, liveResourceQuota))
+	assert.NoError(t, err)
+	assert.Empty(t, drift)
+	liveResourceQuota.Spec.Hard[corev1.ResourcePods] = resource.MustParse("50")
+	liveResourceQuota.Spec.Hard[corev1.ResourceServices] = resource.MustParse("5")
+	drift, err = objects[0].getDrift(toTestUnstructured(t, liveResourceQuota))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ResourceQuota hard pods is 50 instead of 20", "ResourceQuota hard services is not in the policy"}, drift)
+
+	// the defaults set from the max limits by the api server are not drift
+	liveLimitRange := objects[1].desired.(*corev1.LimitRange).DeepCopy()
+	liveLimitRange.Spec.Limits[0].Default = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")}
+	drift, err = objects[1].getDrift(toTestUnstructured(t, liveLimitRange))
+	assert.NoError(t, err)
+	assert.Empty(t, drift)
+	liveLimitRange.Spec.Limits[0].Max = nil
+	drift, err = objects[1].getDrift(toTestUnstructured(t, liveLimitRange))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"LimitRange max memory is missing"}, drift)
+
+	liveNetworkPolicy := objects[2].desired.(*networkingv1.NetworkPolicy).DeepCopy()
+	drift, err = objects[2].getDrift(toTestUnstructured(t, liveNetworkPolicy))
+	assert.NoError(t, err)
+	assert.Empty(t, drift)
+	liveNetworkPolicy.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{{}}
+	drift, err = objects[2].getDrift(toTestUnstructured(t, liveNetworkPolicy))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"NetworkPolicy allows ingress"}, drift)
+
+	// objects not defined by the policy are removed from the namespace
+	objects, err = getNamespaceObjects("prod", &bean.NamespacePolicy{NetworkPolicy: &bean.DefaultDenyNetworkPolicy{}})
+	assert.NoError(t, err)
+	for _, object := range objects {
+		assert.Nil(t, object.desired)
+	}
+}
+
+func TestGetNamespaceMetadataDrift(t *testing.T) {
+	policy := &bean.NamespacePolicy{
+		Labels:      map[string]string{"team": "payments", "tier": "prod"},
+		Annotations: map[string]string{"devtron.ai/owner": "payments"},
+	}
+	liveLabels := map[string]string{"team": "checkout", "kubernetes.io/metadata.name": "prod"}
+	drift := getNamespaceMetadataDrift(policy, liveLabels, nil)
+	assert.Equal(t, []string{
+		"namespace annotation devtron.ai/owner is missing",
+		"namespace label team is \"checkout\" instead of \"payments\"",
+		"namespace label tier is missing",
+	}, drift)
+
+	merged := mergeMetadata(liveLabels, policy.Labels)
+	assert.Equal(t, map[string]string{"team": "payments", "tier": "prod", "kubernetes.io/metadata.name": "prod"}, merged)
+	assert.Empty(t, getNamespaceMetadataDrift(policy, merged, policy.Annotations))
+}
+
+func TestMergeDrift(t *testing.T) {
+	driftJson, err := mergeDrift("", []string{"ResourceQuota pods is 30 instead of 20"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `["ResourceQuota pods is 30 instead of 20"]`, driftJson)
+
+	// drift recorded before is kept until it is acknowledged
+	driftJson, err = mergeDrift(driftJson, []string{"NetworkPolicy devtron-default-deny is missing", "ResourceQuota pods is 30 instead of 20"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `["ResourceQuota pods is 30 instead of 20","NetworkPolicy devtron-default-deny is missing"]`, driftJson)
+
+	_, err = mergeDrift("not json", nil)
+	assert.Error(t, err)
+}
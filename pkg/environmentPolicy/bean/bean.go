@@ -0,0 +1,54 @@
+package bean
+
+import "time"
+
+const (
+	ResourceQuotaName  = "devtron-environment-quota"
+	LimitRangeName     = "devtron-environment-limit-range"
+	NetworkPolicyName  = "devtron-default-deny"
+	ManagedByLabelKey  = "app.kubernetes.io/managed-by"
+	ManagedByLabelName = "devtron"
+)
+
+// NamespacePolicy is the template of the namespace level policies of an environment which devtron keeps applied on
+// the namespace of the environment
+type NamespacePolicy struct {
+	EnvironmentId int `json:"environmentId,omitempty"`
+	// ResourceQuota is the hard limits of the namespace, e.g. {"requests.cpu": "4", "pods": "20"}
+	ResourceQuota map[string]string         `json:"resourceQuota,omitempty"`
+	LimitRange    *LimitRange               `json:"limitRange,omitempty"`
+	NetworkPolicy *DefaultDenyNetworkPolicy `json:"networkPolicy,omitempty"`
+	// Labels and Annotations are added to the namespace, removing them from the policy does not remove them from the namespace
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// LimitRange is the limits and the defaults applied to every container of the namespace
+type LimitRange struct {
+	Default        map[string]string `json:"default,omitempty"`
+	DefaultRequest map[string]string `json:"defaultRequest,omitempty"`
+	Max            map[string]string `json:"max,omitempty"`
+	Min            map[string]string `json:"min,omitempty"`
+}
+
+// DefaultDenyNetworkPolicy selects all the pods of the namespace and denies the traffic of the enabled directions
+type DefaultDenyNetworkPolicy struct {
+	DenyIngress bool `json:"denyIngress"`
+	DenyEgress  bool `json:"denyEgress"`
+}
+
+type NamespacePolicyStatus struct {
+	EnvironmentId         int              `json:"environmentId"`
+	EnvironmentName       string           `json:"environmentName"`
+	EnvironmentIdentifier string           `json:"environmentIdentifier"`
+	ClusterId             int              `json:"clusterId"`
+	Namespace             string           `json:"namespace"`
+	Policy                *NamespacePolicy `json:"policy"`
+	InSync                bool             `json:"inSync"`
+	// Drift is the differences found in the namespace from the policy since it was last acknowledged, they are reverted
+	// on reconciliation. DriftDetectedOn is when the first of them was found
+	Drift            []string   `json:"drift"`
+	DriftDetectedOn  *time.Time `json:"driftDetectedOn,omitempty"`
+	ReconcileError   string     `json:"reconcileError,omitempty"`
+	LastReconciledOn *time.Time `json:"lastReconciledOn,omitempty"`
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// EnvironmentNamespacePolicy is the namespace policy of an environment, Policy is the json of the policy template and
+// Drift is the json array of the differences found in the namespace since the drift was last acknowledged
+type EnvironmentNamespacePolicy struct {
+	tableName        struct{}  `sql:"environment_namespace_policy" pg:",discard_unknown_columns"`
+	Id               int       `sql:"id,pk"`
+	EnvironmentId    int       `sql:"environment_id,notnull"`
+	Policy           string    `sql:"policy,notnull"`
+	Drift            string    `sql:"drift"`
+	DriftDetectedOn  time.Time `sql:"drift_detected_on,type:timestamptz"`
+	ReconcileError   string    `sql:"reconcile_error"`
+	LastReconciledOn time.Time `sql:"last_reconciled_on,type:timestamptz"`
+	Active           bool      `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type NamespacePolicyRepository interface {
+	Save(policy *EnvironmentNamespacePolicy) error
+	Update(policy *EnvironmentNamespacePolicy) error
+	FindByEnvironmentId(environmentId int) (*EnvironmentNamespacePolicy, error)
+	FindAllActive() ([]*EnvironmentNamespacePolicy, error)
+	FindAllWithDrift() ([]*EnvironmentNamespacePolicy, error)
+}
+
+type NamespacePolicyRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewNamespacePolicyRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *NamespacePolicyRepositoryImpl {
+	return &NamespacePolicyRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *NamespacePolicyRepositoryImpl) Save(policy *EnvironmentNamespacePolicy) error {
+	return impl.dbConnection.Insert(policy)
+}
+
+func (impl *NamespacePolicyRepositoryImpl) Update(policy *EnvironmentNamespacePolicy) error {
+	return impl.dbConnection.Update(policy)
+}
+
+func (impl *NamespacePolicyRepositoryImpl) FindByEnvironmentId(environmentId int) (*EnvironmentNamespacePolicy, error) {
+	policy := &EnvironmentNamespacePolicy{}
+	err := impl.dbConnection.Model(policy).
+		Where("environment_id = ?", environmentId).
+		Where("active = ?", true).
+		Select()
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (impl *NamespacePolicyRepositoryImpl) FindAllActive() ([]*EnvironmentNamespacePolicy, error) {
+	var policies []*EnvironmentNamespacePolicy
+	err := impl.dbConnection.Model(&policies).
+		Where("active = ?", true).
+		Order("environment_id ASC").Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting environment namespace policies", "err", err)
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (impl *NamespacePolicyRepositoryImpl) FindAllWithDrift() ([]*EnvironmentNamespacePolicy, error) {
+	var policies []*EnvironmentNamespacePolicy
+	err := impl.dbConnection.Model(&policies).
+		Where("active = ?", true).
+		Where("drift IS NOT NULL").
+		Where("drift <> ''").
+		Order("drift_detected_on DESC").Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting drifted environment namespace policies", "err", err)
+		return nil, err
+	}
+	return policies, nil
+}
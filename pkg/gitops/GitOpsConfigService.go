@@ -77,6 +77,7 @@ const (
 	GITLAB_PROVIDER       = "GITLAB"
 	BITBUCKET_PROVIDER    = "BITBUCKET_CLOUD"
 	AZURE_DEVOPS_PROVIDER = "AZURE_DEVOPS"
+	GITEA_PROVIDER        = "GITEA"
 	BITBUCKET_API_HOST    = "https://api.bitbucket.org/2.0/"
 )
 
@@ -126,6 +127,10 @@ func NewGitOpsConfigServiceImpl(Logger *zap.SugaredLogger,
 }
 
 func (impl *GitOpsConfigServiceImpl) ValidateAndCreateGitOpsConfig(config *bean2.GitOpsConfigDto) (DetailedErrorGitOpsConfigResponse, error) {
+	err := impl.validateCommitSigningKey(config)
+	if err != nil {
+		return DetailedErrorGitOpsConfigResponse{}, err
+	}
 	detailedErrorGitOpsConfigResponse := impl.GitOpsValidateDryRun(config)
 	if len(detailedErrorGitOpsConfigResponse.StageErrorMap) == 0 {
 		//create argo-cd user, if not created, here argo-cd integration has to be installed
@@ -152,6 +157,14 @@ func (impl *GitOpsConfigServiceImpl) ValidateAndUpdateGitOpsConfig(config *bean2
 		}
 		config.Token = model.Token
 	}
+	err := impl.fillExistingCommitSigningKey(config)
+	if err != nil {
+		return DetailedErrorGitOpsConfigResponse{}, err
+	}
+	err = impl.validateCommitSigningKey(config)
+	if err != nil {
+		return DetailedErrorGitOpsConfigResponse{}, err
+	}
 	detailedErrorGitOpsConfigResponse := impl.GitOpsValidateDryRun(config)
 	if len(detailedErrorGitOpsConfigResponse.StageErrorMap) == 0 {
 		err := impl.UpdateGitOpsConfig(config)
@@ -208,8 +221,13 @@ func (impl *GitOpsConfigServiceImpl) CreateGitOpsConfig(ctx context.Context, req
 		AzureProject:         request.AzureProjectName,
 		BitBucketWorkspaceId: request.BitBucketWorkspaceId,
 		BitBucketProjectKey:  request.BitBucketProjectKey,
+		GiteaOrgId:           request.GiteaOrgId,
 		AuditLog:             sql.AuditLog{CreatedBy: request.UserId, CreatedOn: time.Now(), UpdatedOn: time.Now(), UpdatedBy: request.UserId},
 	}
+	err = impl.setCommitSigningKey(model, request)
+	if err != nil {
+		return nil, err
+	}
 	model, err = impl.gitOpsRepository.CreateGitOpsConfig(model, tx)
 	if err != nil {
 		impl.logger.Errorw("error in saving gitops config", "data", model, "err", err)
@@ -295,6 +313,13 @@ func (impl *GitOpsConfigServiceImpl) CreateGitOpsConfig(ctx context.Context, req
 	if strings.ToUpper(request.Provider) == BITBUCKET_PROVIDER {
 		request.Host = util.BITBUCKET_CLONE_BASE_URL + request.BitBucketWorkspaceId
 	}
+	if strings.ToUpper(request.Provider) == GITEA_PROVIDER {
+		orgUrl, err := impl.buildGithubOrgUrl(request.Host, request.GiteaOrgId)
+		if err != nil {
+			return nil, err
+		}
+		request.Host = orgUrl
+	}
 	operationComplete := false
 	retryCount := 0
 	for !operationComplete && retryCount < 3 {
@@ -409,6 +434,11 @@ func (impl *GitOpsConfigServiceImpl) UpdateGitOpsConfig(request *bean2.GitOpsCon
 	model.AzureProject = request.AzureProjectName
 	model.BitBucketWorkspaceId = request.BitBucketWorkspaceId
 	model.BitBucketProjectKey = request.BitBucketProjectKey
+	model.GiteaOrgId = request.GiteaOrgId
+	err = impl.setCommitSigningKey(model, request)
+	if err != nil {
+		return err
+	}
 	err = impl.gitOpsRepository.UpdateGitOpsConfig(model, tx)
 	if err != nil {
 		impl.logger.Errorw("error in updating team", "data", model, "err", err)
@@ -496,6 +526,13 @@ func (impl *GitOpsConfigServiceImpl) UpdateGitOpsConfig(request *bean2.GitOpsCon
 	if strings.ToUpper(request.Provider) == BITBUCKET_PROVIDER {
 		request.Host = util.BITBUCKET_CLONE_BASE_URL + request.BitBucketWorkspaceId
 	}
+	if strings.ToUpper(request.Provider) == GITEA_PROVIDER {
+		orgUrl, err := impl.buildGithubOrgUrl(request.Host, request.GiteaOrgId)
+		if err != nil {
+			return err
+		}
+		request.Host = orgUrl
+	}
 	operationComplete := false
 	retryCount := 0
 	for !operationComplete && retryCount < 3 {
@@ -551,6 +588,10 @@ func (impl *GitOpsConfigServiceImpl) GetGitOpsConfigById(id int) (*bean2.GitOpsC
 		AzureProjectName:     model.AzureProject,
 		BitBucketWorkspaceId: model.BitBucketWorkspaceId,
 		BitBucketProjectKey:  model.BitBucketProjectKey,
+		GiteaOrgId:           model.GiteaOrgId,
+		CommitSigningType:    model.CommitSigningType,
+		// signing key is never returned, only whether it is set
+		CommitSigningKeyConfigured: len(model.CommitSigningKey) > 0,
 	}
 
 	return config, err
@@ -565,18 +606,21 @@ func (impl *GitOpsConfigServiceImpl) GetAllGitOpsConfig() ([]*bean2.GitOpsConfig
 	configs := make([]*bean2.GitOpsConfigDto, 0)
 	for _, model := range models {
 		config := &bean2.GitOpsConfigDto{
-			Id:                   model.Id,
-			Provider:             model.Provider,
-			GitHubOrgId:          model.GitHubOrgId,
-			GitLabGroupId:        model.GitLabGroupId,
-			Username:             model.Username,
-			Token:                "",
-			Host:                 model.Host,
-			Active:               model.Active,
-			UserId:               model.CreatedBy,
-			AzureProjectName:     model.AzureProject,
-			BitBucketWorkspaceId: model.BitBucketWorkspaceId,
-			BitBucketProjectKey:  model.BitBucketProjectKey,
+			Id:                         model.Id,
+			Provider:                   model.Provider,
+			GitHubOrgId:                model.GitHubOrgId,
+			GitLabGroupId:              model.GitLabGroupId,
+			Username:                   model.Username,
+			Token:                      "",
+			Host:                       model.Host,
+			Active:                     model.Active,
+			UserId:                     model.CreatedBy,
+			AzureProjectName:           model.AzureProject,
+			BitBucketWorkspaceId:       model.BitBucketWorkspaceId,
+			BitBucketProjectKey:        model.BitBucketProjectKey,
+			GiteaOrgId:                 model.GiteaOrgId,
+			CommitSigningType:          model.CommitSigningType,
+			CommitSigningKeyConfigured: len(model.CommitSigningKey) > 0,
 		}
 		configs = append(configs, config)
 	}
@@ -590,18 +634,21 @@ func (impl *GitOpsConfigServiceImpl) GetGitOpsConfigByProvider(provider string)
 		return nil, err
 	}
 	config := &bean2.GitOpsConfigDto{
-		Id:                   model.Id,
-		Provider:             model.Provider,
-		GitHubOrgId:          model.GitHubOrgId,
-		GitLabGroupId:        model.GitLabGroupId,
-		Username:             model.Username,
-		Token:                model.Token,
-		Host:                 model.Host,
-		Active:               model.Active,
-		UserId:               model.CreatedBy,
-		AzureProjectName:     model.AzureProject,
-		BitBucketWorkspaceId: model.BitBucketWorkspaceId,
-		BitBucketProjectKey:  model.BitBucketProjectKey,
+		Id:                         model.Id,
+		Provider:                   model.Provider,
+		GitHubOrgId:                model.GitHubOrgId,
+		GitLabGroupId:              model.GitLabGroupId,
+		Username:                   model.Username,
+		Token:                      model.Token,
+		Host:                       model.Host,
+		Active:                     model.Active,
+		UserId:                     model.CreatedBy,
+		AzureProjectName:           model.AzureProject,
+		BitBucketWorkspaceId:       model.BitBucketWorkspaceId,
+		BitBucketProjectKey:        model.BitBucketProjectKey,
+		GiteaOrgId:                 model.GiteaOrgId,
+		CommitSigningType:          model.CommitSigningType,
+		CommitSigningKeyConfigured: len(model.CommitSigningKey) > 0,
 	}
 
 	return config, err
@@ -673,19 +720,84 @@ func (impl *GitOpsConfigServiceImpl) GetGitOpsConfigActive() (*bean2.GitOpsConfi
 		return nil, err
 	}
 	config := &bean2.GitOpsConfigDto{
-		Id:                   model.Id,
-		Provider:             model.Provider,
-		GitHubOrgId:          model.GitHubOrgId,
-		GitLabGroupId:        model.GitLabGroupId,
-		Active:               model.Active,
-		UserId:               model.CreatedBy,
-		AzureProjectName:     model.AzureProject,
-		BitBucketWorkspaceId: model.BitBucketWorkspaceId,
-		BitBucketProjectKey:  model.BitBucketProjectKey,
+		Id:                         model.Id,
+		Provider:                   model.Provider,
+		GitHubOrgId:                model.GitHubOrgId,
+		GitLabGroupId:              model.GitLabGroupId,
+		Active:                     model.Active,
+		UserId:                     model.CreatedBy,
+		AzureProjectName:           model.AzureProject,
+		BitBucketWorkspaceId:       model.BitBucketWorkspaceId,
+		BitBucketProjectKey:        model.BitBucketProjectKey,
+		GiteaOrgId:                 model.GiteaOrgId,
+		CommitSigningType:          model.CommitSigningType,
+		CommitSigningKeyConfigured: len(model.CommitSigningKey) > 0,
 	}
 	return config, err
 }
 
+func (impl *GitOpsConfigServiceImpl) validateCommitSigningKey(config *bean2.GitOpsConfigDto) error {
+	config.CommitSigningType = strings.ToUpper(config.CommitSigningType)
+	err := util.ValidateCommitSigningKey(config.CommitSigningType, config.CommitSigningKey, config.CommitSigningKeyPassphrase)
+	if err != nil {
+		impl.logger.Errorw("invalid gitops commit signing key", "signingType", config.CommitSigningType, "err", err)
+		return &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: err.Error(), InternalMessage: err.Error()}
+	}
+	return nil
+}
+
+// fillExistingCommitSigningKey keeps the stored signing key when an update does not send it, the key is never returned
+// to clients so they can not send it back
+func (impl *GitOpsConfigServiceImpl) fillExistingCommitSigningKey(config *bean2.GitOpsConfigDto) error {
+	if len(config.CommitSigningType) == 0 || len(config.CommitSigningKey) > 0 {
+		return nil
+	}
+	model, err := impl.gitOpsRepository.GetGitOpsConfigById(config.Id)
+	if err != nil {
+		impl.logger.Errorw("error in getting gitops config", "id", config.Id, "err", err)
+		return err
+	}
+	if !strings.EqualFold(model.CommitSigningType, config.CommitSigningType) || len(model.CommitSigningKey) == 0 {
+		return nil
+	}
+	config.CommitSigningKey, err = util2.DecryptSecret(model.CommitSigningKey)
+	if err != nil {
+		impl.logger.Errorw("error in decrypting gitops commit signing key", "id", config.Id, "err", err)
+		return err
+	}
+	if len(model.CommitSigningKeyPassphrase) > 0 {
+		config.CommitSigningKeyPassphrase, err = util2.DecryptSecret(model.CommitSigningKeyPassphrase)
+		if err != nil {
+			impl.logger.Errorw("error in decrypting gitops commit signing key passphrase", "id", config.Id, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// setCommitSigningKey stores the signing key of request encrypted in model
+func (impl *GitOpsConfigServiceImpl) setCommitSigningKey(model *repository.GitOpsConfig, request *bean2.GitOpsConfigDto) error {
+	model.CommitSigningType, model.CommitSigningKey, model.CommitSigningKeyPassphrase = "", "", ""
+	if len(request.CommitSigningType) == 0 {
+		return nil
+	}
+	encryptedKey, err := util2.EncryptSecret(request.CommitSigningKey)
+	if err != nil {
+		impl.logger.Errorw("error in encrypting gitops commit signing key", "err", err)
+		return &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: err.Error(), InternalMessage: err.Error()}
+	}
+	model.CommitSigningType = request.CommitSigningType
+	model.CommitSigningKey = encryptedKey
+	if len(request.CommitSigningKeyPassphrase) > 0 {
+		model.CommitSigningKeyPassphrase, err = util2.EncryptSecret(request.CommitSigningKeyPassphrase)
+		if err != nil {
+			impl.logger.Errorw("error in encrypting gitops commit signing key passphrase", "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
 func (impl *GitOpsConfigServiceImpl) GitOpsValidateDryRun(config *bean2.GitOpsConfigDto) DetailedErrorGitOpsConfigResponse {
 	if impl.globalEnvVariables.SkipGitOpsValidation {
 		return DetailedErrorGitOpsConfigResponse{}
@@ -812,6 +924,11 @@ func (impl *GitOpsConfigServiceImpl) extractErrorMessageByProvider(err error, pr
 			errorMessage := fmt.Errorf("%s", *errorResponse.Message)
 			return errorMessage
 		}
+	} else if provider == GITEA_PROVIDER {
+		if errorResponse, ok := err.(*util.GiteaErrorResponse); ok {
+			errorMessage := fmt.Errorf("%s", errorResponse.Message)
+			return errorMessage
+		}
 	}
 	return err
 }
@@ -305,10 +305,11 @@ func initK8sApplicationService(t *testing.T) *K8sApplicationServiceImpl {
 	//Client Service has been removed. Please use application service or common service
 	//k8sClientServiceImpl := application.NewK8sClientServiceImpl(sugaredLogger, clusterRepositoryImpl)
 	v := informer2.NewGlobalMapClusterNamespace()
-	k8sInformerFactoryImpl := informer2.NewK8sInformerFactoryImpl(sugaredLogger, v, runtimeConfig, k8sUtil)
-	clusterServiceImpl := cluster.NewClusterServiceImpl(clusterRepositoryImpl, sugaredLogger, k8sUtil, k8sInformerFactoryImpl, nil, nil, nil)
+	k8sInformerFactoryImpl, _ := informer2.NewK8sInformerFactoryImpl(sugaredLogger, v, runtimeConfig, k8sUtil)
+	clusterServiceImpl := cluster.NewClusterServiceImpl(clusterRepositoryImpl, sugaredLogger, k8sUtil, k8sInformerFactoryImpl, nil, nil, nil, nil)
 	ephemeralContainerService := cluster.NewEphemeralContainerServiceImpl(ephemeralContainerRepository, sugaredLogger)
-	terminalSessionHandlerImpl := terminal.NewTerminalSessionHandlerImpl(nil, clusterServiceImpl, sugaredLogger, k8sUtil, ephemeralContainerService)
+	terminalCommandPolicyService := terminal.NewTerminalCommandPolicyServiceImpl(sugaredLogger, repository.NewTerminalCommandPolicyRepositoryImpl(db, sugaredLogger))
+	terminalSessionHandlerImpl, _ := terminal.NewTerminalSessionHandlerImpl(nil, clusterServiceImpl, sugaredLogger, k8sUtil, ephemeralContainerService, nil, terminalCommandPolicyService)
 	k8sApplicationService, _ := NewK8sApplicationServiceImpl(sugaredLogger, clusterServiceImpl, nil, nil, k8sUtil, nil, nil, nil, terminalSessionHandlerImpl, ephemeralContainerService, ephemeralContainerRepository)
 	return k8sApplicationService
 }
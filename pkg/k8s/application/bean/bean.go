@@ -2,6 +2,7 @@ package bean
 
 import (
 	"github.com/devtron-labs/devtron/util/k8s"
+	"time"
 )
 
 const (
@@ -42,3 +43,42 @@ type RotatePodResourceResponse struct {
 	k8s.ResourceIdentifier
 	ErrorResponse string `json:"errorResponse"`
 }
+
+// ResourceSearchRequest searches the resources of the kinds across the clusters, all clusters the user has access
+// to are searched when ClusterIds is empty. Name and Image are case-insensitive substrings, Status is matched against
+// the status shown by kubectl for pods, e.g. CrashLoopBackOff
+type ResourceSearchRequest struct {
+	Kinds         []string `json:"kinds"`
+	ClusterIds    []int    `json:"clusterIds"`
+	Namespace     string   `json:"namespace"`
+	Name          string   `json:"name"`
+	LabelSelector string   `json:"labelSelector"`
+	Image         string   `json:"image"`
+	Status        string   `json:"status"`
+	Limit         int      `json:"limit"`
+	UserId        int32    `json:"-"`
+	IsSuperAdmin  bool     `json:"-"`
+}
+
+type ResourceSearchResult struct {
+	ClusterId   int               `json:"clusterId"`
+	ClusterName string            `json:"clusterName"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Group       string            `json:"group"`
+	Version     string            `json:"version"`
+	Kind        string            `json:"kind"`
+	Name        string            `json:"name"`
+	Status      string            `json:"status,omitempty"`
+	Images      []string          `json:"images,omitempty"`
+	NodeName    string            `json:"nodeName,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	CreatedOn   time.Time         `json:"createdOn"`
+}
+
+// ResourceSearchResponse contains the matching resources, Warnings lists the clusters and kinds which could not be
+// searched
+type ResourceSearchResponse struct {
+	Results   []*ResourceSearchResult `json:"results"`
+	Truncated bool                    `json:"truncated"`
+	Warnings  []string                `json:"warnings,omitempty"`
+}
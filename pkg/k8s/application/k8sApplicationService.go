@@ -86,7 +86,7 @@ func NewK8sApplicationServiceImpl(Logger *zap.SugaredLogger, clusterService clus
 		Logger.Errorw("error in parsing EphemeralContainerConfig from env", "err", err)
 		return nil, err
 	}
-	return &K8sApplicationServiceImpl{
+	serviceImpl := &K8sApplicationServiceImpl{
 		logger:                       Logger,
 		clusterService:               clusterService,
 		pump:                         pump,
@@ -99,7 +99,9 @@ func NewK8sApplicationServiceImpl(Logger *zap.SugaredLogger, clusterService clus
 		ephemeralContainerService:    ephemeralContainerService,
 		ephemeralContainerRepository: ephemeralContainerRepository,
 		ephemeralContainerConfig:     ephemeralContainerConfig,
-	}, nil
+	}
+	terminalSession.RegisterListener(serviceImpl)
+	return serviceImpl, nil
 }
 
 type EphemeralContainerConfig struct {
@@ -870,6 +872,29 @@ func (impl *K8sApplicationServiceImpl) TerminatePodEphemeralContainer(req cluste
 	return true, nil
 }
 
+// OnTerminalSessionTerminated removes the ephemeral container a session was opened in when the session is closed for
+// exceeding the limits of its cluster or by an admin
+func (impl *K8sApplicationServiceImpl) OnTerminalSessionTerminated(request *terminal.TerminalSessionRequest, clusterId int) {
+	container, err := impl.ephemeralContainerRepository.FindContainerByName(clusterId, request.Namespace, request.PodName, request.ContainerName)
+	if err != nil {
+		impl.logger.Errorw("error in finding ephemeral container of terminated session", "err", err, "clusterId", clusterId, "podName", request.PodName, "containerName", request.ContainerName)
+		return
+	}
+	if container == nil {
+		return
+	}
+	_, err = impl.TerminatePodEphemeralContainer(cluster.EphemeralContainerRequest{
+		BasicData: &cluster.EphemeralContainerBasicData{ContainerName: request.ContainerName},
+		Namespace: request.Namespace,
+		ClusterId: clusterId,
+		PodName:   request.PodName,
+		UserId:    request.UserId,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in terminating ephemeral container of terminated session", "err", err, "clusterId", clusterId, "podName", request.PodName, "containerName", request.ContainerName)
+	}
+}
+
 func (impl *K8sApplicationServiceImpl) GetPodContainersList(clusterId int, namespace, podName string) (*k8s.PodContainerList, error) {
 	_, v1Client, err := impl.k8sCommonService.GetCoreClientByClusterId(clusterId)
 	if err != nil {
@@ -968,7 +993,7 @@ func (impl *K8sApplicationServiceImpl) DeleteResourceWithAudit(ctx context.Conte
 		return nil, err
 	}
 	if request.AppIdentifier != nil {
-		saveAuditLogsErr := impl.K8sResourceHistoryService.SaveHelmAppsResourceHistory(request.AppIdentifier, request.K8sRequest, userId, bean3.Delete)
+		saveAuditLogsErr := impl.K8sResourceHistoryService.SaveHelmAppsResourceHistory(ctx, request.AppIdentifier, request.K8sRequest, userId, bean3.Delete)
 		if saveAuditLogsErr != nil {
 			impl.logger.Errorw("error in saving audit logs for delete resource request", "err", err)
 		}
@@ -0,0 +1,361 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"github.com/caarlos0/env/v6"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	"github.com/devtron-labs/devtron/pkg/k8s/application/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/informer"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	k8s2 "github.com/devtron-labs/devtron/util/k8s"
+	"go.uber.org/zap"
+	appsV1 "k8s.io/api/apps/v1"
+	batchV1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultResourceSearchLimit = 500
+	maxResourceSearchLimit     = 5000
+)
+
+type K8sResourceSearchConfig struct {
+	SyncTimeoutSecs int `env:"K8S_RESOURCE_SEARCH_SYNC_TIMEOUT_SECS" envDefault:"30"`
+}
+
+type K8sResourceSearchService interface {
+	// SearchResources searches the resources of the clusters from the informer caches, only the resources for which
+	// validateResourceAccess allows get are returned
+	SearchResources(ctx context.Context, token string, request *bean.ResourceSearchRequest, validateResourceAccess func(token string, clusterName string, request k8s.ResourceRequestBean, casbinAction string) bool) (*bean.ResourceSearchResponse, error)
+}
+
+type K8sResourceSearchServiceImpl struct {
+	logger             *zap.SugaredLogger
+	clusterService     cluster.ClusterService
+	k8sInformerFactory informer.K8sInformerFactory
+	searchConfig       *K8sResourceSearchConfig
+}
+
+func NewK8sResourceSearchServiceImpl(logger *zap.SugaredLogger, clusterService cluster.ClusterService,
+	k8sInformerFactory informer.K8sInformerFactory) (*K8sResourceSearchServiceImpl, error) {
+	searchConfig := &K8sResourceSearchConfig{}
+	err := env.Parse(searchConfig)
+	if err != nil {
+		logger.Errorw("error in parsing resource search config", "err", err)
+		return nil, err
+	}
+	return &K8sResourceSearchServiceImpl{
+		logger:             logger,
+		clusterService:     clusterService,
+		k8sInformerFactory: k8sInformerFactory,
+		searchConfig:       searchConfig,
+	}, nil
+}
+
+type clusterSearchResult struct {
+	results  []*bean.ResourceSearchResult
+	warnings []string
+}
+
+func (impl *K8sResourceSearchServiceImpl) SearchResources(ctx context.Context, token string, request *bean.ResourceSearchRequest, validateResourceAccess func(token string, clusterName string, request k8s.ResourceRequestBean, casbinAction string) bool) (*bean.ResourceSearchResponse, error) {
+	err := validateResourceSearchRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	selector := labels.Everything()
+	if len(request.LabelSelector) > 0 {
+		selector, err = labels.Parse(request.LabelSelector)
+		if err != nil {
+			return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: err.Error(), UserMessage: fmt.Sprintf("invalid label selector %s", request.LabelSelector)}
+		}
+	}
+	clusters, err := impl.getClustersToSearch(request)
+	if err != nil {
+		return nil, err
+	}
+	response := &bean.ResourceSearchResponse{Results: make([]*bean.ResourceSearchResult, 0)}
+	clusterResults := make([]*clusterSearchResult, len(clusters))
+	wg := &sync.WaitGroup{}
+	for i, clusterBean := range clusters {
+		if clusterBean.IsVirtualCluster {
+			continue
+		}
+		if len(clusterBean.ErrorInConnecting) > 0 {
+			response.Warnings = append(response.Warnings, fmt.Sprintf("cluster %s is not reachable: %s", clusterBean.ClusterName, clusterBean.ErrorInConnecting))
+			continue
+		}
+		wg.Add(1)
+		go func(i int, clusterBean *cluster.ClusterBean) {
+			defer wg.Done()
+			clusterResults[i] = impl.searchCluster(ctx, token, clusterBean, request, selector, validateResourceAccess)
+		}(i, clusterBean)
+	}
+	wg.Wait()
+	for _, clusterResult := range clusterResults {
+		if clusterResult == nil {
+			continue
+		}
+		response.Results = append(response.Results, clusterResult.results...)
+		response.Warnings = append(response.Warnings, clusterResult.warnings...)
+	}
+	sortResourceSearchResults(response.Results)
+	if len(response.Results) > request.Limit {
+		response.Results = response.Results[:request.Limit]
+		response.Truncated = true
+	}
+	return response, nil
+}
+
+func validateResourceSearchRequest(request *bean.ResourceSearchRequest) error {
+	if len(request.Kinds) == 0 {
+		return &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: "at least one kind is required"}
+	}
+	for _, kind := range request.Kinds {
+		if _, ok := informer.GetSearchableResource(kind); !ok {
+			searchableKinds := informer.GetSearchableKinds()
+			sort.Strings(searchableKinds)
+			return &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: fmt.Sprintf("kind %s is not searchable, supported kinds are %s", kind, strings.Join(searchableKinds, ", "))}
+		}
+	}
+	if request.Limit <= 0 {
+		request.Limit = defaultResourceSearchLimit
+	} else if request.Limit > maxResourceSearchLimit {
+		request.Limit = maxResourceSearchLimit
+	}
+	return nil
+}
+
+// getClustersToSearch returns the requested clusters on which the user has a role, clusters without a role are left
+// out before anything is reported about them, including whether they are reachable
+func (impl *K8sResourceSearchServiceImpl) getClustersToSearch(request *bean.ResourceSearchRequest) ([]*cluster.ClusterBean, error) {
+	clusters, err := impl.clusterService.FindAll()
+	if err != nil {
+		impl.logger.Errorw("error in getting clusters", "err", err)
+		return nil, err
+	}
+	var accessibleClusters map[string]bool
+	if !request.IsSuperAdmin {
+		userClusters, err := impl.clusterService.FindAllForClusterByUserId(request.UserId, false)
+		if err != nil {
+			impl.logger.Errorw("error in getting clusters of user", "err", err, "userId", request.UserId)
+			return nil, err
+		}
+		accessibleClusters = make(map[string]bool, len(userClusters))
+		for _, userCluster := range userClusters {
+			accessibleClusters[userCluster.ClusterName] = true
+		}
+	}
+	clusterIdMap := make(map[int]bool, len(request.ClusterIds))
+	for _, clusterId := range request.ClusterIds {
+		clusterIdMap[clusterId] = true
+	}
+	var filteredClusters []*cluster.ClusterBean
+	for _, clusterBean := range clusters {
+		if accessibleClusters != nil && !accessibleClusters[clusterBean.ClusterName] {
+			continue
+		}
+		if len(clusterIdMap) > 0 && !clusterIdMap[clusterBean.Id] {
+			continue
+		}
+		filteredClusters = append(filteredClusters, clusterBean)
+	}
+	return filteredClusters, nil
+}
+
+func (impl *K8sResourceSearchServiceImpl) searchCluster(ctx context.Context, token string, clusterBean *cluster.ClusterBean, request *bean.ResourceSearchRequest,
+	selector labels.Selector, validateResourceAccess func(token string, clusterName string, request k8s.ResourceRequestBean, casbinAction string) bool) *clusterSearchResult {
+	clusterResult := &clusterSearchResult{}
+	clusterConfig, err := clusterBean.GetClusterConfig()
+	if err != nil {
+		clusterResult.warnings = append(clusterResult.warnings, fmt.Sprintf("cluster %s could not be searched: %s", clusterBean.ClusterName, err.Error()))
+		return clusterResult
+	}
+	clusterConfig.ClusterName = clusterBean.ClusterName
+	syncTimeout := time.Duration(impl.searchConfig.SyncTimeoutSecs) * time.Second
+	objects, unsynced, err := impl.k8sInformerFactory.ListSearchableResources(clusterConfig, request.Kinds, request.Namespace, selector, syncTimeout)
+	if err != nil {
+		impl.logger.Errorw("error in listing resources for search", "err", err, "clusterName", clusterBean.ClusterName)
+		clusterResult.warnings = append(clusterResult.warnings, fmt.Sprintf("cluster %s could not be searched: %s", clusterBean.ClusterName, err.Error()))
+		return clusterResult
+	}
+	for _, kind := range unsynced {
+		clusterResult.warnings = append(clusterResult.warnings, fmt.Sprintf("%s of cluster %s are still being loaded, retry shortly", kind, clusterBean.ClusterName))
+	}
+	for kind, list := range objects {
+		gvr, _ := informer.GetSearchableResource(kind)
+		gvk := schema.GroupVersionKind{Group: gvr.Group, Version: gvr.Version, Kind: kind}
+		for _, object := range list {
+			if ctx.Err() != nil {
+				return clusterResult
+			}
+			result, matched := matchResourceSearchObject(request, gvk, object)
+			if !matched {
+				continue
+			}
+			resourceRequest := k8s.ResourceRequestBean{
+				ClusterId: clusterBean.Id,
+				K8sRequest: &k8s2.K8sRequestBean{
+					ResourceIdentifier: k8s2.ResourceIdentifier{Name: result.Name, Namespace: result.Namespace, GroupVersionKind: gvk},
+				},
+			}
+			if !validateResourceAccess(token, clusterBean.ClusterName, resourceRequest, casbin.ActionGet) {
+				continue
+			}
+			result.ClusterId = clusterBean.Id
+			result.ClusterName = clusterBean.ClusterName
+			clusterResult.results = append(clusterResult.results, result)
+		}
+	}
+	return clusterResult
+}
+
+// matchResourceSearchObject applies the name, image and status filters of the request to the object, label and
+// namespace filters are applied while listing from the informer cache
+func matchResourceSearchObject(request *bean.ResourceSearchRequest, gvk schema.GroupVersionKind, object runtime.Object) (*bean.ResourceSearchResult, bool) {
+	objectMeta, err := meta.Accessor(object)
+	if err != nil {
+		return nil, false
+	}
+	if len(request.Name) > 0 && !strings.Contains(strings.ToLower(objectMeta.GetName()), strings.ToLower(request.Name)) {
+		return nil, false
+	}
+	result := &bean.ResourceSearchResult{
+		Namespace: objectMeta.GetNamespace(),
+		Group:     gvk.Group,
+		Version:   gvk.Version,
+		Kind:      gvk.Kind,
+		Name:      objectMeta.GetName(),
+		Labels:    objectMeta.GetLabels(),
+		CreatedOn: objectMeta.GetCreationTimestamp().Time,
+	}
+	if podSpec := getPodSpec(object); podSpec != nil {
+		for _, container := range podSpec.InitContainers {
+			result.Images = append(result.Images, container.Image)
+		}
+		for _, container := range podSpec.Containers {
+			result.Images = append(result.Images, container.Image)
+		}
+		result.NodeName = podSpec.NodeName
+	}
+	if pod, ok := object.(*corev1.Pod); ok {
+		result.Status = getPodStatus(pod)
+	}
+	if len(request.Image) > 0 && !containsImage(result.Images, request.Image) {
+		return nil, false
+	}
+	if len(request.Status) > 0 && !strings.EqualFold(result.Status, request.Status) {
+		return nil, false
+	}
+	return result, true
+}
+
+func getPodSpec(object runtime.Object) *corev1.PodSpec {
+	switch obj := object.(type) {
+	case *corev1.Pod:
+		return &obj.Spec
+	case *appsV1.Deployment:
+		return &obj.Spec.Template.Spec
+	case *appsV1.StatefulSet:
+		return &obj.Spec.Template.Spec
+	case *appsV1.DaemonSet:
+		return &obj.Spec.Template.Spec
+	case *appsV1.ReplicaSet:
+		return &obj.Spec.Template.Spec
+	case *batchV1.Job:
+		return &obj.Spec.Template.Spec
+	case *batchV1.CronJob:
+		return &obj.Spec.JobTemplate.Spec.Template.Spec
+	}
+	return nil
+}
+
+func containsImage(images []string, image string) bool {
+	image = strings.ToLower(image)
+	for _, containerImage := range images {
+		if strings.Contains(strings.ToLower(containerImage), image) {
+			return true
+		}
+	}
+	return false
+}
+
+// getPodStatus returns the status of the pod as shown by kubectl get pods, e.g. CrashLoopBackOff, Init:Error or
+// Terminating
+func getPodStatus(pod *corev1.Pod) string {
+	status := string(pod.Status.Phase)
+	if len(pod.Status.Reason) > 0 {
+		status = pod.Status.Reason
+	}
+	initializing := false
+	for i, containerStatus := range pod.Status.InitContainerStatuses {
+		if containerStatus.State.Terminated != nil && containerStatus.State.Terminated.ExitCode == 0 {
+			continue
+		}
+		initializing = true
+		if containerStatus.State.Terminated != nil {
+			if len(containerStatus.State.Terminated.Reason) > 0 {
+				status = "Init:" + containerStatus.State.Terminated.Reason
+			} else {
+				status = fmt.Sprintf("Init:ExitCode:%d", containerStatus.State.Terminated.ExitCode)
+			}
+		} else if containerStatus.State.Waiting != nil && len(containerStatus.State.Waiting.Reason) > 0 && containerStatus.State.Waiting.Reason != "PodInitializing" {
+			status = "Init:" + containerStatus.State.Waiting.Reason
+		} else {
+			status = fmt.Sprintf("Init:%d/%d", i, len(pod.Spec.InitContainers))
+		}
+		break
+	}
+	if !initializing {
+		hasRunning := false
+		for i := len(pod.Status.ContainerStatuses) - 1; i >= 0; i-- {
+			containerStatus := pod.Status.ContainerStatuses[i]
+			if containerStatus.State.Waiting != nil && len(containerStatus.State.Waiting.Reason) > 0 {
+				status = containerStatus.State.Waiting.Reason
+			} else if containerStatus.State.Terminated != nil && len(containerStatus.State.Terminated.Reason) > 0 {
+				status = containerStatus.State.Terminated.Reason
+			} else if containerStatus.State.Terminated != nil {
+				status = fmt.Sprintf("ExitCode:%d", containerStatus.State.Terminated.ExitCode)
+			} else if containerStatus.Ready && containerStatus.State.Running != nil {
+				hasRunning = true
+			}
+		}
+		if status == "Completed" && hasRunning {
+			status = string(corev1.PodRunning)
+		}
+	}
+	if pod.DeletionTimestamp != nil {
+		if pod.Status.Reason == "NodeLost" {
+			status = "Unknown"
+		} else {
+			status = "Terminating"
+		}
+	}
+	return status
+}
+
+func sortResourceSearchResults(results []*bean.ResourceSearchResult) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].ClusterName != results[j].ClusterName {
+			return results[i].ClusterName < results[j].ClusterName
+		}
+		if results[i].Namespace != results[j].Namespace {
+			return results[i].Namespace < results[j].Namespace
+		}
+		if results[i].Kind != results[j].Kind {
+			return results[i].Kind < results[j].Kind
+		}
+		return results[i].Name < results[j].Name
+	})
+}
@@ -0,0 +1,86 @@
+package application
+
+import (
+	"github.com/devtron-labs/devtron/pkg/k8s/application/bean"
+	"github.com/stretchr/testify/assert"
+	appsV1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"testing"
+	"time"
+)
+
+func TestGetPodStatus(t *testing.T) {
+	crashing := &corev1.Pod{Status: corev1.PodStatus{
+		Phase: corev1.PodRunning,
+		ContainerStatuses: []corev1.ContainerStatus{
+			{Ready: true, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+		},
+	}}
+	assert.Equal(t, "CrashLoopBackOff", getPodStatus(crashing))
+
+	initializing := &corev1.Pod{
+		Spec: corev1.PodSpec{InitContainers: []corev1.Container{{Name: "migrate"}, {Name: "seed"}}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}},
+				{State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			},
+		},
+	}
+	assert.Equal(t, "Init:1/2", getPodStatus(initializing))
+	initializing.Status.InitContainerStatuses[1].State = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}}
+	assert.Equal(t, "Init:Error", getPodStatus(initializing))
+
+	running := &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning}}
+	assert.Equal(t, "Running", getPodStatus(running))
+	running.DeletionTimestamp = &v1.Time{Time: time.Now()}
+	assert.Equal(t, "Terminating", getPodStatus(running))
+}
+
+func TestMatchResourceSearchObject(t *testing.T) {
+	podGvk := schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+	pod := &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: "payments-api-5c8d", Namespace: "prod"},
+		Spec:       corev1.PodSpec{NodeName: "node-1", Containers: []corev1.Container{{Image: "docker.io/devtron/payments:v2"}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{
+			{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+		}},
+	}
+	result, matched := matchResourceSearchObject(&bean.ResourceSearchRequest{Name: "API", Image: "devtron/", Status: "crashloopbackoff"}, podGvk, pod)
+	assert.True(t, matched)
+	assert.Equal(t, "prod", result.Namespace)
+	assert.Equal(t, "node-1", result.NodeName)
+	assert.Equal(t, []string{"docker.io/devtron/payments:v2"}, result.Images)
+
+	_, matched = matchResourceSearchObject(&bean.ResourceSearchRequest{Name: "worker"}, podGvk, pod)
+	assert.False(t, matched)
+	_, matched = matchResourceSearchObject(&bean.ResourceSearchRequest{Image: "quay.io/"}, podGvk, pod)
+	assert.False(t, matched)
+	_, matched = matchResourceSearchObject(&bean.ResourceSearchRequest{Status: "Running"}, podGvk, pod)
+	assert.False(t, matched)
+
+	// images of workloads are matched from the pod template, status only applies to pods
+	deployment := &appsV1.Deployment{ObjectMeta: v1.ObjectMeta{Name: "payments-api", Namespace: "prod"}}
+	deployment.Spec.Template.Spec.Containers = []corev1.Container{{Image: "docker.io/devtron/payments:v2"}}
+	deploymentGvk := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	result, matched = matchResourceSearchObject(&bean.ResourceSearchRequest{Image: "devtron/payments"}, deploymentGvk, deployment)
+	assert.True(t, matched)
+	assert.Equal(t, "apps", result.Group)
+	_, matched = matchResourceSearchObject(&bean.ResourceSearchRequest{Status: "CrashLoopBackOff"}, deploymentGvk, deployment)
+	assert.False(t, matched)
+}
+
+func TestValidateResourceSearchRequest(t *testing.T) {
+	assert.Error(t, validateResourceSearchRequest(&bean.ResourceSearchRequest{}))
+	assert.Error(t, validateResourceSearchRequest(&bean.ResourceSearchRequest{Kinds: []string{"Secret"}}))
+	request := &bean.ResourceSearchRequest{Kinds: []string{"Pod"}, Limit: 100000}
+	assert.NoError(t, validateResourceSearchRequest(request))
+	assert.Equal(t, maxResourceSearchLimit, request.Limit)
+	request.Limit = 0
+	assert.NoError(t, validateResourceSearchRequest(request))
+	assert.Equal(t, defaultResourceSearchLimit, request.Limit)
+}
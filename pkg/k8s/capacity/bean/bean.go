@@ -388,3 +388,136 @@ func MakePodDeleteStatusWithError(message string) PodDeleteStatus {
 		Message: message,
 	}
 }
+
+// CapacityHistoryPoint is the capacity of a cluster or node at the time of a capacity collection
+type CapacityHistoryPoint struct {
+	CapturedOn time.Time             `json:"capturedOn"`
+	NodeCount  int                   `json:"nodeCount,omitempty"`
+	PodCount   int                   `json:"podCount"`
+	Cpu        *ResourceDetailObject `json:"cpu"`
+	Memory     *ResourceDetailObject `json:"memory"`
+}
+
+// RightSizingRecommendation proposes requests and limits for the pods of a workload of a devtron app and environment
+// from the usage of its busiest pod over the lookback window. Deployments and rollouts are reported as the ReplicaSet
+// kind with the name of the deployment, so that all their revisions are one workload.
+type RightSizingRecommendation struct {
+	AppId     int                     `json:"appId"`
+	EnvId     int                     `json:"envId"`
+	ClusterId int                     `json:"clusterId"`
+	Namespace string                  `json:"namespace"`
+	Kind      string                  `json:"kind"`
+	Name      string                  `json:"name"`
+	PodCount  int                     `json:"podCount"`
+	Samples   int                     `json:"samples"`
+	Cpu       *ResourceRecommendation `json:"cpu"`
+	Memory    *ResourceRecommendation `json:"memory"`
+	Summary   string                  `json:"summary"`
+	Message   string                  `json:"message,omitempty"`
+	PatchJson string                  `json:"patchJson,omitempty"` // json patch for the deployment template, same format as bulk edit
+	Trend     []*CapacityHistoryPoint `json:"trend"`
+}
+
+// ResourceRecommendation values are per pod, recommended limit is empty when the pods have no limit
+type ResourceRecommendation struct {
+	Request            string `json:"request"`
+	Limit              string `json:"limit"`
+	UsageP95           string `json:"usageP95"`
+	UsageMax           string `json:"usageMax"`
+	RecommendedRequest string `json:"recommendedRequest,omitempty"`
+	RecommendedLimit   string `json:"recommendedLimit,omitempty"`
+}
+
+const (
+	CostPeriodDaily        = "daily"
+	CostPeriodMonthly      = "monthly"
+	CostGroupByTeam        = "team"
+	CostGroupByApp         = "app"
+	CostGroupByEnvironment = "environment"
+)
+
+// NodeGroupPricing is the hourly price of a node group, cluster id 0 applies to all clusters and an empty node group
+// to the nodes of the cluster whose node group has no price of its own
+type NodeGroupPricing struct {
+	Id                int     `json:"id"`
+	ClusterId         int     `json:"clusterId"`
+	NodeGroup         string  `json:"nodeGroup"`
+	CpuCoreHourPrice  float64 `json:"cpuCoreHourPrice"`
+	MemoryGbHourPrice float64 `json:"memoryGbHourPrice"`
+	GpuHourPrice      float64 `json:"gpuHourPrice"`
+}
+
+// CostReportRow is the cost of a team, app or environment over a day or month, names are empty for the cost of nodes
+// which was not requested by any pod
+type CostReportRow struct {
+	Period     string  `json:"period"`
+	TeamId     int     `json:"teamId,omitempty"`
+	TeamName   string  `json:"teamName,omitempty"`
+	AppId      int     `json:"appId,omitempty"`
+	AppName    string  `json:"appName,omitempty"`
+	EnvId      int     `json:"envId,omitempty"`
+	EnvName    string  `json:"envName,omitempty"`
+	CpuCost    float64 `json:"cpuCost"`
+	MemoryCost float64 `json:"memoryCost"`
+	GpuCost    float64 `json:"gpuCost"`
+	TotalCost  float64 `json:"totalCost"`
+}
+
+const (
+	MaintenanceStatusRunning   = "Running"
+	MaintenanceStatusPaused    = "Paused"
+	MaintenanceStatusCancelled = "Cancelled"
+	MaintenanceStatusSucceeded = "Succeeded"
+	MaintenanceStatusFailed    = "Failed"
+
+	MaintenanceNodeStatusPending        = "Pending"
+	MaintenanceNodeStatusDraining       = "Draining"
+	MaintenanceNodeStatusWaitingForApps = "WaitingForApps"
+	MaintenanceNodeStatusDrained        = "Drained"
+	MaintenanceNodeStatusFailed         = "Failed"
+	MaintenanceNodeStatusSkipped        = "Skipped"
+
+	MaintenanceActionPause  = "pause"
+	MaintenanceActionResume = "resume"
+	MaintenanceActionCancel = "cancel"
+)
+
+// NodeMaintenanceRequest selects the nodes of a cluster by node group, label selector or both. Pods are always evicted
+// so that PodDisruptionBudgets are respected, disableEviction of the drain options is not supported.
+type NodeMaintenanceRequest struct {
+	ClusterId         int              `json:"clusterId"`
+	NodeGroup         string           `json:"nodeGroup"`
+	LabelSelector     string           `json:"labelSelector"`
+	NodeDrainHelper   *NodeDrainHelper `json:"nodeDrainOptions"`
+	MaxRetries        int              `json:"maxRetries"`
+	RetryIntervalSecs int              `json:"retryIntervalSecs"`
+	HealthTimeoutMins int              `json:"healthTimeoutMins"`
+}
+
+type NodeMaintenanceJob struct {
+	Id                int                    `json:"id"`
+	ClusterId         int                    `json:"clusterId"`
+	NodeGroup         string                 `json:"nodeGroup,omitempty"`
+	LabelSelector     string                 `json:"labelSelector,omitempty"`
+	MaxRetries        int                    `json:"maxRetries"`
+	RetryIntervalSecs int                    `json:"retryIntervalSecs"`
+	HealthTimeoutMins int                    `json:"healthTimeoutMins"`
+	Status            string                 `json:"status"`
+	Message           string                 `json:"message,omitempty"`
+	TotalNodes        int                    `json:"totalNodes"`
+	DrainedNodes      int                    `json:"drainedNodes"`
+	StartedOn         *time.Time             `json:"startedOn,omitempty"`
+	FinishedOn        *time.Time             `json:"finishedOn,omitempty"`
+	CreatedBy         int32                  `json:"createdBy"`
+	Nodes             []*NodeMaintenanceNode `json:"nodes,omitempty"`
+}
+
+type NodeMaintenanceNode struct {
+	NodeName   string     `json:"nodeName"`
+	Status     string     `json:"status"`
+	Attempts   int        `json:"attempts"`
+	PodCount   int        `json:"podCount"`
+	Message    string     `json:"message,omitempty"`
+	StartedOn  *time.Time `json:"startedOn,omitempty"`
+	FinishedOn *time.Time `json:"finishedOn,omitempty"`
+}
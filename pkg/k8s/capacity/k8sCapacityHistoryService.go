@@ -0,0 +1,523 @@
+package capacity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env/v6"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/repository"
+	k8s2 "github.com/devtron-labs/devtron/util/k8s"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	resourcehelper "k8s.io/kubectl/pkg/util/resource"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	devtronAppIdLabel       = "appId"
+	devtronEnvIdLabel       = "envId"
+	podTemplateHashLabel    = "pod-template-hash"
+	minRecommendedCpuMilli  = 10
+	minRecommendedMemory    = 32 * bean.Mebibyte
+	capacityCollectTimeout  = 2 * time.Minute
+	defaultLookbackDays     = 14
+	rightSizingUsagePercent = 95
+)
+
+type CapacityHistoryConfig struct {
+	SnapshotEnabled      bool `env:"CAPACITY_SNAPSHOT_ENABLED" envDefault:"true"`
+	SnapshotIntervalMins int  `env:"CAPACITY_SNAPSHOT_INTERVAL_MINS" envDefault:"15"`
+	RetentionDays        int  `env:"CAPACITY_SNAPSHOT_RETENTION_DAYS" envDefault:"30"`
+	// HeadroomPercent is added over the observed usage when recommending requests and limits
+	HeadroomPercent int `env:"RIGHT_SIZING_HEADROOM_PERCENT" envDefault:"20"`
+	MinSamples      int `env:"RIGHT_SIZING_MIN_SAMPLES" envDefault:"24"`
+}
+
+type K8sCapacityHistoryService interface {
+	CollectSnapshots()
+	GetCapacityHistory(clusterId int, nodeName string, from, to time.Time) ([]*bean.CapacityHistoryPoint, error)
+	GetRightSizingRecommendations(appId, envId, lookbackDays int) ([]*bean.RightSizingRecommendation, error)
+}
+
+type K8sCapacityHistoryServiceImpl struct {
+	logger                     *zap.SugaredLogger
+	clusterService             cluster.ClusterService
+	K8sUtil                    *k8s2.K8sUtil
+	capacitySnapshotRepository repository.CapacitySnapshotRepository
+	costAllocationService      K8sCostAllocationService
+	config                     *CapacityHistoryConfig
+}
+
+func NewK8sCapacityHistoryServiceImpl(logger *zap.SugaredLogger, clusterService cluster.ClusterService, K8sUtil *k8s2.K8sUtil,
+	capacitySnapshotRepository repository.CapacitySnapshotRepository, costAllocationService K8sCostAllocationService) (*K8sCapacityHistoryServiceImpl, error) {
+	config := &CapacityHistoryConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing CapacityHistoryConfig from env", "err", err)
+		return nil, err
+	}
+	serviceImpl := &K8sCapacityHistoryServiceImpl{
+		logger:                     logger,
+		clusterService:             clusterService,
+		K8sUtil:                    K8sUtil,
+		capacitySnapshotRepository: capacitySnapshotRepository,
+		costAllocationService:      costAllocationService,
+		config:                     config,
+	}
+	if config.SnapshotEnabled {
+		snapshotCron := cron.New(cron.WithChain())
+		snapshotCron.Start()
+		_, err = snapshotCron.AddFunc(fmt.Sprintf("@every %dm", config.SnapshotIntervalMins), serviceImpl.CollectSnapshots)
+		if err != nil {
+			logger.Errorw("error in adding capacity snapshot cron", "err", err, "intervalMins", config.SnapshotIntervalMins)
+			return nil, err
+		}
+	}
+	return serviceImpl, nil
+}
+
+// CollectSnapshots saves the usage of the nodes and workloads and the cost of the apps of all reachable clusters and
+// removes the snapshots and costs older than their retention
+func (impl *K8sCapacityHistoryServiceImpl) CollectSnapshots() {
+	impl.logger.Debug("starting capacity snapshot collection")
+	defer impl.logger.Debug("stopped capacity snapshot collection")
+	clusters, err := impl.clusterService.FindAll()
+	if err != nil {
+		impl.logger.Errorw("error in getting all clusters", "err", err)
+		return
+	}
+	capturedOn := time.Now()
+	for _, clusterBean := range clusters {
+		if clusterBean.IsVirtualCluster || len(clusterBean.ErrorInConnecting) > 0 {
+			continue
+		}
+		err = impl.collectClusterSnapshots(clusterBean, capturedOn)
+		if err != nil {
+			impl.logger.Errorw("error in collecting capacity snapshot of cluster", "err", err, "clusterId", clusterBean.Id)
+		}
+	}
+	err = impl.capacitySnapshotRepository.DeleteSnapshotsBefore(capturedOn.AddDate(0, 0, -impl.config.RetentionDays))
+	if err != nil {
+		impl.logger.Errorw("error in deleting old capacity snapshots", "err", err)
+	}
+	err = impl.costAllocationService.DeleteExpiredCostAllocations(capturedOn)
+	if err != nil {
+		impl.logger.Errorw("error in deleting old cost allocations", "err", err)
+	}
+}
+
+func (impl *K8sCapacityHistoryServiceImpl) collectClusterSnapshots(clusterBean *cluster.ClusterBean, capturedOn time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), capacityCollectTimeout)
+	defer cancel()
+	clusterConfig, err := clusterBean.GetClusterConfig()
+	if err != nil {
+		return err
+	}
+	restConfig, k8sHttpClient, k8sClientSet, err := impl.K8sUtil.GetK8sConfigAndClients(clusterConfig)
+	if err != nil {
+		return err
+	}
+	metricsClientSet, err := impl.K8sUtil.GetMetricsClientSet(restConfig, k8sHttpClient)
+	if err != nil {
+		return err
+	}
+	nodeList, err := impl.K8sUtil.GetNodesList(ctx, k8sClientSet)
+	if err != nil {
+		return err
+	}
+	podList, err := impl.K8sUtil.GetPodsListForNamespace(ctx, k8sClientSet, bean.NamespaceAll)
+	if err != nil {
+		return err
+	}
+	// cost is apportioned by requests and does not need metrics
+	interval := time.Duration(impl.config.SnapshotIntervalMins) * time.Minute
+	err = impl.costAllocationService.AllocateClusterCost(clusterBean.Id, nodeList.Items, podList.Items, interval, capturedOn)
+	if err != nil {
+		impl.logger.Errorw("error in allocating cost of cluster", "err", err, "clusterId", clusterBean.Id)
+	}
+	// usage is what right-sizing is based on, snapshots without it would only mislead
+	nodeMetricsList, err := impl.K8sUtil.GetNmList(ctx, metricsClientSet)
+	if err != nil {
+		return err
+	}
+	podMetricsList, err := impl.K8sUtil.GetPodMetricsList(ctx, metricsClientSet, bean.NamespaceAll)
+	if err != nil {
+		return err
+	}
+	nodeUsage := make(map[string]corev1.ResourceList)
+	for _, nodeMetrics := range nodeMetricsList.Items {
+		nodeUsage[nodeMetrics.Name] = nodeMetrics.Usage
+	}
+	podUsage := make(map[string]corev1.ResourceList)
+	for _, podMetrics := range podMetricsList.Items {
+		usage := corev1.ResourceList{}
+		for _, container := range podMetrics.Containers {
+			usage = AddTwoResourceList(usage, container.Usage)
+		}
+		podUsage[podMetrics.Namespace+"/"+podMetrics.Name] = usage
+	}
+	nodeSnapshots := make(map[string]*repository.CapacityNodeSnapshot)
+	for _, node := range nodeList.Items {
+		usage := nodeUsage[node.Name]
+		nodeSnapshots[node.Name] = &repository.CapacityNodeSnapshot{
+			ClusterId:         clusterBean.Id,
+			NodeName:          node.Name,
+			CpuAllocatable:    node.Status.Allocatable.Cpu().MilliValue(),
+			CpuUsage:          usage.Cpu().MilliValue(),
+			MemoryAllocatable: node.Status.Allocatable.Memory().Value(),
+			MemoryUsage:       usage.Memory().Value(),
+			CapturedOn:        capturedOn,
+		}
+	}
+	workloadSnapshots := make(map[string]*repository.CapacityWorkloadSnapshot)
+	var workloadKeys []string
+	podsWithUsage := make(map[string]int)
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		requests, limits := resourcehelper.PodRequestsAndLimits(pod)
+		if nodeSnapshot, ok := nodeSnapshots[pod.Spec.NodeName]; ok {
+			nodeSnapshot.PodCount++
+			nodeSnapshot.CpuRequests += requests.Cpu().MilliValue()
+			nodeSnapshot.CpuLimits += limits.Cpu().MilliValue()
+			nodeSnapshot.MemoryRequests += requests.Memory().Value()
+			nodeSnapshot.MemoryLimits += limits.Memory().Value()
+		}
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		kind, name := getPodWorkload(pod)
+		key := fmt.Sprintf("%s/%s/%s", pod.Namespace, kind, name)
+		workloadSnapshot, ok := workloadSnapshots[key]
+		if !ok {
+			appId, _ := strconv.Atoi(pod.Labels[devtronAppIdLabel])
+			envId, _ := strconv.Atoi(pod.Labels[devtronEnvIdLabel])
+			workloadSnapshot = &repository.CapacityWorkloadSnapshot{
+				ClusterId:      clusterBean.Id,
+				Namespace:      pod.Namespace,
+				Kind:           kind,
+				Name:           name,
+				AppId:          appId,
+				EnvId:          envId,
+				ContainerCount: len(pod.Spec.Containers),
+				CapturedOn:     capturedOn,
+			}
+			workloadSnapshots[key] = workloadSnapshot
+			workloadKeys = append(workloadKeys, key)
+		}
+		// summed here and divided by the pod count below
+		workloadSnapshot.PodCount++
+		workloadSnapshot.CpuRequests += requests.Cpu().MilliValue()
+		workloadSnapshot.CpuLimits += limits.Cpu().MilliValue()
+		workloadSnapshot.MemoryRequests += requests.Memory().Value()
+		workloadSnapshot.MemoryLimits += limits.Memory().Value()
+		if usage, ok := podUsage[pod.Namespace+"/"+pod.Name]; ok {
+			podsWithUsage[key]++
+			cpuUsage, memoryUsage := usage.Cpu().MilliValue(), usage.Memory().Value()
+			workloadSnapshot.CpuUsage += cpuUsage
+			workloadSnapshot.MemoryUsage += memoryUsage
+			if cpuUsage > workloadSnapshot.CpuUsageMax {
+				workloadSnapshot.CpuUsageMax = cpuUsage
+			}
+			if memoryUsage > workloadSnapshot.MemoryUsageMax {
+				workloadSnapshot.MemoryUsageMax = memoryUsage
+			}
+		}
+	}
+	nodeSnapshotList := make([]*repository.CapacityNodeSnapshot, 0, len(nodeSnapshots))
+	for _, node := range nodeList.Items {
+		nodeSnapshotList = append(nodeSnapshotList, nodeSnapshots[node.Name])
+	}
+	workloadSnapshotList := make([]*repository.CapacityWorkloadSnapshot, 0, len(workloadKeys))
+	for _, key := range workloadKeys {
+		workloadSnapshot := workloadSnapshots[key]
+		if podsWithUsage[key] == 0 {
+			// pods which just started have no metrics yet
+			continue
+		}
+		podCount := int64(workloadSnapshot.PodCount)
+		workloadSnapshot.CpuRequests /= podCount
+		workloadSnapshot.CpuLimits /= podCount
+		workloadSnapshot.MemoryRequests /= podCount
+		workloadSnapshot.MemoryLimits /= podCount
+		workloadSnapshot.CpuUsage /= int64(podsWithUsage[key])
+		workloadSnapshot.MemoryUsage /= int64(podsWithUsage[key])
+		workloadSnapshotList = append(workloadSnapshotList, workloadSnapshot)
+	}
+	err = impl.capacitySnapshotRepository.SaveNodeSnapshots(nodeSnapshotList)
+	if err != nil {
+		return err
+	}
+	return impl.capacitySnapshotRepository.SaveWorkloadSnapshots(workloadSnapshotList)
+}
+
+// getPodWorkload returns the controller of the pod, pods of a ReplicaSet are grouped under the name of the deployment
+// or rollout which created it so that all revisions count as one workload
+func getPodWorkload(pod *corev1.Pod) (kind string, name string) {
+	owner := v1.GetControllerOf(pod)
+	if owner == nil {
+		return "Pod", pod.Name
+	}
+	if hash := pod.Labels[podTemplateHashLabel]; owner.Kind == "ReplicaSet" && len(hash) > 0 {
+		return owner.Kind, strings.TrimSuffix(owner.Name, "-"+hash)
+	}
+	return owner.Kind, owner.Name
+}
+
+// GetCapacityHistory returns the capacity of the cluster summed over its nodes, or of one node when nodeName is set
+func (impl *K8sCapacityHistoryServiceImpl) GetCapacityHistory(clusterId int, nodeName string, from, to time.Time) ([]*bean.CapacityHistoryPoint, error) {
+	snapshots, err := impl.capacitySnapshotRepository.FindNodeSnapshots(clusterId, nodeName, from, to)
+	if err != nil {
+		return nil, err
+	}
+	points := make([]*bean.CapacityHistoryPoint, 0)
+	var total *repository.CapacityNodeSnapshot
+	nodeCount := 0
+	addPoint := func() {
+		if total != nil {
+			points = append(points, newCapacityHistoryPoint(total, nodeCount))
+		}
+	}
+	for _, snapshot := range snapshots {
+		if total == nil || !total.CapturedOn.Equal(snapshot.CapturedOn) {
+			addPoint()
+			total = &repository.CapacityNodeSnapshot{CapturedOn: snapshot.CapturedOn}
+			nodeCount = 0
+		}
+		nodeCount++
+		total.PodCount += snapshot.PodCount
+		total.CpuAllocatable += snapshot.CpuAllocatable
+		total.CpuRequests += snapshot.CpuRequests
+		total.CpuLimits += snapshot.CpuLimits
+		total.CpuUsage += snapshot.CpuUsage
+		total.MemoryAllocatable += snapshot.MemoryAllocatable
+		total.MemoryRequests += snapshot.MemoryRequests
+		total.MemoryLimits += snapshot.MemoryLimits
+		total.MemoryUsage += snapshot.MemoryUsage
+	}
+	addPoint()
+	return points, nil
+}
+
+func newCapacityHistoryPoint(snapshot *repository.CapacityNodeSnapshot, nodeCount int) *bean.CapacityHistoryPoint {
+	cpuAllocatable := resource.NewMilliQuantity(snapshot.CpuAllocatable, resource.DecimalSI)
+	cpuRequests := resource.NewMilliQuantity(snapshot.CpuRequests, resource.DecimalSI)
+	cpuLimits := resource.NewMilliQuantity(snapshot.CpuLimits, resource.DecimalSI)
+	cpuUsage := resource.NewMilliQuantity(snapshot.CpuUsage, resource.DecimalSI)
+	memoryAllocatable := resource.NewQuantity(snapshot.MemoryAllocatable, resource.BinarySI)
+	memoryRequests := resource.NewQuantity(snapshot.MemoryRequests, resource.BinarySI)
+	memoryLimits := resource.NewQuantity(snapshot.MemoryLimits, resource.BinarySI)
+	memoryUsage := resource.NewQuantity(snapshot.MemoryUsage, resource.BinarySI)
+	return &bean.CapacityHistoryPoint{
+		CapturedOn: snapshot.CapturedOn,
+		NodeCount:  nodeCount,
+		PodCount:   snapshot.PodCount,
+		Cpu: &bean.ResourceDetailObject{
+			Allocatable:       getResourceString(*cpuAllocatable, corev1.ResourceCPU),
+			Request:           getResourceString(*cpuRequests, corev1.ResourceCPU),
+			Limit:             getResourceString(*cpuLimits, corev1.ResourceCPU),
+			Usage:             getResourceString(*cpuUsage, corev1.ResourceCPU),
+			RequestPercentage: convertToPercentage(cpuRequests, cpuAllocatable),
+			LimitPercentage:   convertToPercentage(cpuLimits, cpuAllocatable),
+			UsagePercentage:   convertToPercentage(cpuUsage, cpuAllocatable),
+		},
+		Memory: &bean.ResourceDetailObject{
+			Allocatable:       getResourceString(*memoryAllocatable, corev1.ResourceMemory),
+			Request:           getResourceString(*memoryRequests, corev1.ResourceMemory),
+			Limit:             getResourceString(*memoryLimits, corev1.ResourceMemory),
+			Usage:             getResourceString(*memoryUsage, corev1.ResourceMemory),
+			RequestPercentage: convertToPercentage(memoryRequests, memoryAllocatable),
+			LimitPercentage:   convertToPercentage(memoryLimits, memoryAllocatable),
+			UsagePercentage:   convertToPercentage(memoryUsage, memoryAllocatable),
+		},
+	}
+}
+
+func (impl *K8sCapacityHistoryServiceImpl) GetRightSizingRecommendations(appId, envId, lookbackDays int) ([]*bean.RightSizingRecommendation, error) {
+	if lookbackDays <= 0 {
+		lookbackDays = defaultLookbackDays
+	}
+	snapshots, err := impl.capacitySnapshotRepository.FindWorkloadSnapshotsByAppAndEnv(appId, envId, time.Now().AddDate(0, 0, -lookbackDays))
+	if err != nil {
+		return nil, err
+	}
+	workloadSnapshots := make(map[string][]*repository.CapacityWorkloadSnapshot)
+	var workloadKeys []string
+	for _, snapshot := range snapshots {
+		key := fmt.Sprintf("%d/%s/%s/%s", snapshot.ClusterId, snapshot.Namespace, snapshot.Kind, snapshot.Name)
+		if _, ok := workloadSnapshots[key]; !ok {
+			workloadKeys = append(workloadKeys, key)
+		}
+		workloadSnapshots[key] = append(workloadSnapshots[key], snapshot)
+	}
+	recommendations := make([]*bean.RightSizingRecommendation, 0, len(workloadKeys))
+	for _, key := range workloadKeys {
+		recommendations = append(recommendations, impl.getRightSizingRecommendation(workloadSnapshots[key]))
+	}
+	return recommendations, nil
+}
+
+// getRightSizingRecommendation recommends requests at the p95 usage of the busiest pod and limits at its peak usage,
+// both with headroom. Limits are only recommended for resources which have one today, for cpu the current ratio of
+// limit to request is kept.
+func (impl *K8sCapacityHistoryServiceImpl) getRightSizingRecommendation(snapshots []*repository.CapacityWorkloadSnapshot) *bean.RightSizingRecommendation {
+	latest := snapshots[len(snapshots)-1]
+	recommendation := &bean.RightSizingRecommendation{
+		AppId:     latest.AppId,
+		EnvId:     latest.EnvId,
+		ClusterId: latest.ClusterId,
+		Namespace: latest.Namespace,
+		Kind:      latest.Kind,
+		Name:      latest.Name,
+		PodCount:  latest.PodCount,
+		Samples:   len(snapshots),
+		Trend:     make([]*bean.CapacityHistoryPoint, 0, len(snapshots)),
+	}
+	cpuUsage := make([]int64, 0, len(snapshots))
+	memoryUsage := make([]int64, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		cpuUsage = append(cpuUsage, snapshot.CpuUsageMax)
+		memoryUsage = append(memoryUsage, snapshot.MemoryUsageMax)
+		recommendation.Trend = append(recommendation.Trend, &bean.CapacityHistoryPoint{
+			CapturedOn: snapshot.CapturedOn,
+			PodCount:   snapshot.PodCount,
+			Cpu: &bean.ResourceDetailObject{
+				Request: formatCpu(snapshot.CpuRequests),
+				Limit:   formatCpu(snapshot.CpuLimits),
+				Usage:   formatCpu(snapshot.CpuUsage),
+			},
+			Memory: &bean.ResourceDetailObject{
+				Request: formatMemory(snapshot.MemoryRequests),
+				Limit:   formatMemory(snapshot.MemoryLimits),
+				Usage:   formatMemory(snapshot.MemoryUsage),
+			},
+		})
+	}
+	cpuP95, cpuMax := percentile(cpuUsage, rightSizingUsagePercent), percentile(cpuUsage, 100)
+	memoryP95, memoryMax := percentile(memoryUsage, rightSizingUsagePercent), percentile(memoryUsage, 100)
+	recommendation.Cpu = &bean.ResourceRecommendation{
+		Request:  formatCpu(latest.CpuRequests),
+		Limit:    formatCpu(latest.CpuLimits),
+		UsageP95: formatCpu(cpuP95),
+		UsageMax: formatCpu(cpuMax),
+	}
+	recommendation.Memory = &bean.ResourceRecommendation{
+		Request:  formatMemory(latest.MemoryRequests),
+		Limit:    formatMemory(latest.MemoryLimits),
+		UsageP95: formatMemory(memoryP95),
+		UsageMax: formatMemory(memoryMax),
+	}
+	recommendation.Summary = fmt.Sprintf("requests %s CPU, p95 usage %s CPU; requests %s memory, p95 usage %s memory",
+		formatCpuCores(latest.CpuRequests), formatCpuCores(cpuP95), formatMemory(latest.MemoryRequests), formatMemory(memoryP95))
+	if len(snapshots) < impl.config.MinSamples {
+		recommendation.Message = fmt.Sprintf("%d samples collected, at least %d are needed for a recommendation", len(snapshots), impl.config.MinSamples)
+		return recommendation
+	}
+	cpuRequest := maxInt64(impl.withHeadroom(cpuP95), minRecommendedCpuMilli)
+	memoryRequest := roundUpToMebibyte(maxInt64(impl.withHeadroom(memoryP95), minRecommendedMemory))
+	var cpuLimit, memoryLimit int64
+	if latest.CpuLimits > 0 {
+		cpuLimit = maxInt64(impl.withHeadroom(cpuMax), cpuRequest)
+		if latest.CpuRequests > 0 {
+			cpuLimit = maxInt64(cpuLimit, cpuRequest*latest.CpuLimits/latest.CpuRequests)
+		}
+	}
+	if latest.MemoryLimits > 0 {
+		memoryLimit = roundUpToMebibyte(maxInt64(impl.withHeadroom(memoryMax), memoryRequest))
+	}
+	recommendation.Cpu.RecommendedRequest = formatCpu(cpuRequest)
+	recommendation.Memory.RecommendedRequest = formatMemory(memoryRequest)
+	if cpuLimit > 0 {
+		recommendation.Cpu.RecommendedLimit = formatCpu(cpuLimit)
+	}
+	if memoryLimit > 0 {
+		recommendation.Memory.RecommendedLimit = formatMemory(memoryLimit)
+	}
+	if latest.ContainerCount != 1 {
+		// the resources of the deployment template apply to the main container only
+		recommendation.Message = fmt.Sprintf("pods run %d containers, values are for the whole pod so no deployment template patch is proposed", latest.ContainerCount)
+		return recommendation
+	}
+	patchJson, err := getResourcesPatchJson(recommendation.Cpu, recommendation.Memory)
+	if err != nil {
+		impl.logger.Errorw("error in creating right sizing patch", "err", err, "appId", latest.AppId, "envId", latest.EnvId)
+		return recommendation
+	}
+	recommendation.PatchJson = patchJson
+	return recommendation
+}
+
+type jsonPatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value string `json:"value"`
+}
+
+func getResourcesPatchJson(cpu, memory *bean.ResourceRecommendation) (string, error) {
+	patch := []jsonPatchOperation{
+		{Op: "add", Path: "/resources/requests/cpu", Value: cpu.RecommendedRequest},
+		{Op: "add", Path: "/resources/requests/memory", Value: memory.RecommendedRequest},
+	}
+	if len(cpu.RecommendedLimit) > 0 {
+		patch = append(patch, jsonPatchOperation{Op: "add", Path: "/resources/limits/cpu", Value: cpu.RecommendedLimit})
+	}
+	if len(memory.RecommendedLimit) > 0 {
+		patch = append(patch, jsonPatchOperation{Op: "add", Path: "/resources/limits/memory", Value: memory.RecommendedLimit})
+	}
+	patchJson, err := json.Marshal(patch)
+	if err != nil {
+		return "", err
+	}
+	return string(patchJson), nil
+}
+
+func (impl *K8sCapacityHistoryServiceImpl) withHeadroom(value int64) int64 {
+	return int64(math.Ceil(float64(value) * float64(100+impl.config.HeadroomPercent) / 100))
+}
+
+// percentile uses the nearest rank method, values are sorted in place
+func percentile(values []int64, percent int) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	rank := int(math.Ceil(float64(percent) / 100 * float64(len(values))))
+	if rank < 1 {
+		rank = 1
+	}
+	return values[rank-1]
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func roundUpToMebibyte(value int64) int64 {
+	return (value + bean.Mebibyte - 1) / bean.Mebibyte * bean.Mebibyte
+}
+
+func formatCpu(milliValue int64) string {
+	return fmt.Sprintf("%dm", milliValue)
+}
+
+func formatCpuCores(milliValue int64) string {
+	return strconv.FormatFloat(math.Round(float64(milliValue)/10)/100, 'f', -1, 64)
+}
+
+func formatMemory(value int64) string {
+	return fmt.Sprintf("%dMi", (value+bean.Mebibyte-1)/bean.Mebibyte)
+}
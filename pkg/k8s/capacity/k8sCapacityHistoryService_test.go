@@ -0,0 +1,87 @@
+package capacity
+
+import (
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/repository"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func TestGetPodWorkload(t *testing.T) {
+	controller := true
+	pod := &corev1.Pod{ObjectMeta: v1.ObjectMeta{
+		Name:            "app-7d9f8b-x2k4p",
+		Labels:          map[string]string{podTemplateHashLabel: "7d9f8b"},
+		OwnerReferences: []v1.OwnerReference{{Kind: "ReplicaSet", Name: "app-7d9f8b", Controller: &controller}},
+	}}
+	kind, name := getPodWorkload(pod)
+	assert.Equal(t, "ReplicaSet", kind)
+	assert.Equal(t, "app", name)
+
+	pod.OwnerReferences = []v1.OwnerReference{{Kind: "StatefulSet", Name: "db", Controller: &controller}}
+	kind, name = getPodWorkload(pod)
+	assert.Equal(t, "StatefulSet", kind)
+	assert.Equal(t, "db", name)
+
+	pod.OwnerReferences = nil
+	kind, name = getPodWorkload(pod)
+	assert.Equal(t, "Pod", kind)
+	assert.Equal(t, pod.Name, name)
+}
+
+func TestPercentile(t *testing.T) {
+	values := []int64{10, 1, 9, 2, 8, 3, 7, 4, 6, 5, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}
+	assert.Equal(t, int64(19), percentile(values, 95))
+	assert.Equal(t, int64(20), percentile(values, 100))
+	assert.Equal(t, int64(10), percentile(values, 50))
+	assert.Equal(t, int64(0), percentile(nil, 95))
+}
+
+func TestGetRightSizingRecommendation(t *testing.T) {
+	logger, err := util.NewSugardLogger()
+	assert.Nil(t, err)
+	impl := &K8sCapacityHistoryServiceImpl{
+		logger: logger,
+		config: &CapacityHistoryConfig{HeadroomPercent: 20, MinSamples: 4},
+	}
+	var snapshots []*repository.CapacityWorkloadSnapshot
+	for i := 0; i < 20; i++ {
+		snapshots = append(snapshots, &repository.CapacityWorkloadSnapshot{
+			AppId:          1,
+			EnvId:          2,
+			Kind:           "ReplicaSet",
+			Name:           "app",
+			PodCount:       2,
+			ContainerCount: 1,
+			CpuRequests:    2000,
+			CpuLimits:      4000,
+			CpuUsage:       200,
+			CpuUsageMax:    int64(200 + 10*i),
+			MemoryRequests: 1024 * bean.Mebibyte,
+			MemoryLimits:   2048 * bean.Mebibyte,
+			MemoryUsage:    200 * bean.Mebibyte,
+			MemoryUsageMax: int64(200+i) * bean.Mebibyte,
+			CapturedOn:     time.Now(),
+		})
+	}
+	recommendation := impl.getRightSizingRecommendation(snapshots)
+	assert.Equal(t, 20, recommendation.Samples)
+	assert.Equal(t, "requests 2 CPU, p95 usage 0.38 CPU; requests 1024Mi memory, p95 usage 218Mi memory", recommendation.Summary)
+	// p95 of the busiest pod plus 20%
+	assert.Equal(t, "456m", recommendation.Cpu.RecommendedRequest)
+	assert.Equal(t, "262Mi", recommendation.Memory.RecommendedRequest)
+	// current limit to request ratio of 2 is kept for cpu, memory limit is the peak plus 20%
+	assert.Equal(t, "912m", recommendation.Cpu.RecommendedLimit)
+	assert.Equal(t, "263Mi", recommendation.Memory.RecommendedLimit)
+	assert.Equal(t, `[{"op":"add","path":"/resources/requests/cpu","value":"456m"},{"op":"add","path":"/resources/requests/memory","value":"262Mi"},{"op":"add","path":"/resources/limits/cpu","value":"912m"},{"op":"add","path":"/resources/limits/memory","value":"263Mi"}]`, recommendation.PatchJson)
+	assert.Len(t, recommendation.Trend, 20)
+
+	recommendation = impl.getRightSizingRecommendation(snapshots[:3])
+	assert.Empty(t, recommendation.PatchJson)
+	assert.Empty(t, recommendation.Cpu.RecommendedRequest)
+	assert.NotEmpty(t, recommendation.Message)
+}
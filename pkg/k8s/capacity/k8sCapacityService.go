@@ -320,14 +320,7 @@ func (impl *K8sCapacityServiceImpl) getNodeGroupAndTaints(node *corev1.Node) (st
 }
 
 func (impl *K8sCapacityServiceImpl) getNodeGroup(node *corev1.Node) string {
-	var nodeGroup = ""
-	//different cloud providers have their own node group label
-	for _, label := range bean.NodeGroupLabels {
-		if ng, ok := node.Labels[label]; ok {
-			nodeGroup = ng
-		}
-	}
-	return nodeGroup
+	return getNodeGroup(node)
 }
 
 func (impl *K8sCapacityServiceImpl) getNodeDetail(ctx context.Context, node *corev1.Node, nodeResourceUsage map[string]corev1.ResourceList, podList *corev1.PodList, callForList bool, cluster *cluster.ClusterBean) (*bean.NodeCapacityDetail, error) {
@@ -0,0 +1,469 @@
+package capacity
+
+import (
+	"fmt"
+	"github.com/caarlos0/env/v6"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	repository2 "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	resourcehelper "k8s.io/kubectl/pkg/util/resource"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const gpuResourceName corev1.ResourceName = "nvidia.com/gpu"
+
+type CostAllocationConfig struct {
+	// RetentionDays is kept longer than the capacity snapshots so that monthly reports can be compared over a year
+	RetentionDays int `env:"COST_ALLOCATION_RETENTION_DAYS" envDefault:"400"`
+}
+
+type K8sCostAllocationService interface {
+	// AllocateClusterCost splits the cost of the nodes of a cluster over the last interval between the pods running
+	// on them and saves it per app and environment
+	AllocateClusterCost(clusterId int, nodes []corev1.Node, pods []corev1.Pod, interval time.Duration, capturedOn time.Time) error
+	DeleteExpiredCostAllocations(now time.Time) error
+	GetCostReport(from, to time.Time, period, groupBy string) ([]*bean.CostReportRow, error)
+	GetAllPricing() ([]*bean.NodeGroupPricing, error)
+	SavePricing(pricing *bean.NodeGroupPricing, userId int32) (*bean.NodeGroupPricing, error)
+	UpdatePricing(pricing *bean.NodeGroupPricing, userId int32) (*bean.NodeGroupPricing, error)
+	DeletePricing(id int, userId int32) error
+}
+
+type K8sCostAllocationServiceImpl struct {
+	logger                 *zap.SugaredLogger
+	clusterService         cluster.ClusterService
+	capacityCostRepository repository.CapacityCostRepository
+	appRepository          app.AppRepository
+	environmentRepository  repository2.EnvironmentRepository
+	config                 *CostAllocationConfig
+}
+
+func NewK8sCostAllocationServiceImpl(logger *zap.SugaredLogger, clusterService cluster.ClusterService,
+	capacityCostRepository repository.CapacityCostRepository, appRepository app.AppRepository,
+	environmentRepository repository2.EnvironmentRepository) (*K8sCostAllocationServiceImpl, error) {
+	config := &CostAllocationConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing CostAllocationConfig from env", "err", err)
+		return nil, err
+	}
+	return &K8sCostAllocationServiceImpl{
+		logger:                 logger,
+		clusterService:         clusterService,
+		capacityCostRepository: capacityCostRepository,
+		appRepository:          appRepository,
+		environmentRepository:  environmentRepository,
+		config:                 config,
+	}, nil
+}
+
+type costAllocationKey struct {
+	namespace string
+	appId     int
+	envId     int
+}
+
+func (impl *K8sCostAllocationServiceImpl) AllocateClusterCost(clusterId int, nodes []corev1.Node, pods []corev1.Pod, interval time.Duration, capturedOn time.Time) error {
+	pricing, err := impl.capacityCostRepository.FindAllActivePricing()
+	if err != nil {
+		return err
+	}
+	if len(pricing) == 0 {
+		return nil
+	}
+	allocations := allocateNodeCost(clusterId, nodes, pods, pricing, interval.Hours())
+	if len(allocations) == 0 {
+		return nil
+	}
+	err = impl.setAppAndEnvNames(allocations)
+	if err != nil {
+		return err
+	}
+	for _, allocation := range allocations {
+		allocation.DurationSecs = int(interval.Seconds())
+		allocation.CapturedOn = capturedOn
+	}
+	return impl.capacityCostRepository.SaveCostAllocations(allocations)
+}
+
+// allocateNodeCost splits the cost of each priced node between its pods in proportion to their requests, separately for
+// cpu, memory and gpu. Capacity which is not requested is part of the cost of the pods which requested the rest, a
+// node without any requests of a resource is charged to the unallocated entry with app id 0 and no namespace.
+func allocateNodeCost(clusterId int, nodes []corev1.Node, pods []corev1.Pod, pricing []*repository.NodeGroupPricing, hours float64) []*repository.CostAllocation {
+	podsByNode := make(map[string][]*corev1.Pod)
+	for i := range pods {
+		pod := &pods[i]
+		if len(pod.Spec.NodeName) == 0 || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		podsByNode[pod.Spec.NodeName] = append(podsByNode[pod.Spec.NodeName], pod)
+	}
+	allocations := make(map[costAllocationKey]*repository.CostAllocation)
+	var allocationList []*repository.CostAllocation
+	getAllocation := func(key costAllocationKey) *repository.CostAllocation {
+		allocation, ok := allocations[key]
+		if !ok {
+			allocation = &repository.CostAllocation{
+				ClusterId: clusterId,
+				Namespace: key.namespace,
+				AppId:     key.appId,
+				EnvId:     key.envId,
+			}
+			allocations[key] = allocation
+			allocationList = append(allocationList, allocation)
+		}
+		return allocation
+	}
+	for i := range nodes {
+		node := &nodes[i]
+		price := getNodeGroupPricing(pricing, clusterId, getNodeGroup(node))
+		if price == nil {
+			continue
+		}
+		allocatable := node.Status.Allocatable
+		gpuAllocatable := allocatable[gpuResourceName]
+		cpuCost := float64(allocatable.Cpu().MilliValue()) / 1000 * price.CpuCoreHourPrice * hours
+		memoryCost := float64(allocatable.Memory().Value()) / bean.Gibibyte * price.MemoryGbHourPrice * hours
+		gpuCost := float64(gpuAllocatable.Value()) * price.GpuHourPrice * hours
+
+		nodePods := podsByNode[node.Name]
+		podRequests := make([]corev1.ResourceList, len(nodePods))
+		var cpuRequests, memoryRequests, gpuRequests int64
+		for j, pod := range nodePods {
+			requests, _ := resourcehelper.PodRequestsAndLimits(pod)
+			podRequests[j] = requests
+			gpu := requests[gpuResourceName]
+			cpuRequests += requests.Cpu().MilliValue()
+			memoryRequests += requests.Memory().Value()
+			gpuRequests += gpu.Value()
+		}
+		for j, pod := range nodePods {
+			appId, _ := strconv.Atoi(pod.Labels[devtronAppIdLabel])
+			envId, _ := strconv.Atoi(pod.Labels[devtronEnvIdLabel])
+			allocation := getAllocation(costAllocationKey{namespace: pod.Namespace, appId: appId, envId: envId})
+			requests := podRequests[j]
+			gpu := requests[gpuResourceName]
+			if cpuRequests > 0 {
+				allocation.CpuCost += cpuCost * float64(requests.Cpu().MilliValue()) / float64(cpuRequests)
+			}
+			if memoryRequests > 0 {
+				allocation.MemoryCost += memoryCost * float64(requests.Memory().Value()) / float64(memoryRequests)
+			}
+			if gpuRequests > 0 {
+				allocation.GpuCost += gpuCost * float64(gpu.Value()) / float64(gpuRequests)
+			}
+		}
+		if (cpuRequests == 0 && cpuCost > 0) || (memoryRequests == 0 && memoryCost > 0) || (gpuRequests == 0 && gpuCost > 0) {
+			unallocated := getAllocation(costAllocationKey{})
+			if cpuRequests == 0 {
+				unallocated.CpuCost += cpuCost
+			}
+			if memoryRequests == 0 {
+				unallocated.MemoryCost += memoryCost
+			}
+			if gpuRequests == 0 {
+				unallocated.GpuCost += gpuCost
+			}
+		}
+	}
+	return allocationList
+}
+
+// getNodeGroupPricing prefers the price of the node group in the cluster, then the default of the cluster, then the
+// price of the node group and the default for all clusters
+func getNodeGroupPricing(pricing []*repository.NodeGroupPricing, clusterId int, nodeGroup string) *repository.NodeGroupPricing {
+	candidates := []struct {
+		clusterId int
+		nodeGroup string
+	}{{clusterId, nodeGroup}, {clusterId, ""}, {0, nodeGroup}, {0, ""}}
+	for _, candidate := range candidates {
+		for _, price := range pricing {
+			if price.ClusterId == candidate.clusterId && price.NodeGroup == candidate.nodeGroup {
+				return price
+			}
+		}
+	}
+	return nil
+}
+
+func getNodeGroup(node *corev1.Node) string {
+	var nodeGroup = ""
+	//different cloud providers have their own node group label
+	for _, label := range bean.NodeGroupLabels {
+		if ng, ok := node.Labels[label]; ok {
+			nodeGroup = ng
+		}
+	}
+	return nodeGroup
+}
+
+// setAppAndEnvNames keeps the names of the app, its team and the environment with the cost, so that reports of past
+// months are unchanged by later renames and deletions
+func (impl *K8sCostAllocationServiceImpl) setAppAndEnvNames(allocations []*repository.CostAllocation) error {
+	var appIds []int
+	var envIds []*int
+	seenApps, seenEnvs := make(map[int]bool), make(map[int]bool)
+	for _, allocation := range allocations {
+		if allocation.AppId > 0 && !seenApps[allocation.AppId] {
+			seenApps[allocation.AppId] = true
+			appIds = append(appIds, allocation.AppId)
+		}
+		if allocation.EnvId > 0 && !seenEnvs[allocation.EnvId] {
+			seenEnvs[allocation.EnvId] = true
+			envId := allocation.EnvId
+			envIds = append(envIds, &envId)
+		}
+	}
+	appsById := make(map[int]*app.App)
+	if len(appIds) > 0 {
+		apps, err := impl.appRepository.FindAppAndProjectByIdsIn(appIds)
+		if err != nil {
+			impl.logger.Errorw("error in getting apps for cost allocation", "err", err, "appIds", appIds)
+			return err
+		}
+		for _, devtronApp := range apps {
+			appsById[devtronApp.Id] = devtronApp
+		}
+	}
+	envsById := make(map[int]*repository2.Environment)
+	if len(envIds) > 0 {
+		envs, err := impl.environmentRepository.FindByIds(envIds)
+		if err != nil {
+			impl.logger.Errorw("error in getting environments for cost allocation", "err", err)
+			return err
+		}
+		for _, environment := range envs {
+			envsById[environment.Id] = environment
+		}
+	}
+	for _, allocation := range allocations {
+		if devtronApp, ok := appsById[allocation.AppId]; ok {
+			allocation.AppName = devtronApp.AppName
+			allocation.TeamId = devtronApp.TeamId
+			allocation.TeamName = devtronApp.Team.Name
+		}
+		if environment, ok := envsById[allocation.EnvId]; ok {
+			allocation.EnvName = environment.Name
+		}
+	}
+	return nil
+}
+
+func (impl *K8sCostAllocationServiceImpl) DeleteExpiredCostAllocations(now time.Time) error {
+	return impl.capacityCostRepository.DeleteCostAllocationsBefore(now.AddDate(0, 0, -impl.config.RetentionDays))
+}
+
+func (impl *K8sCostAllocationServiceImpl) GetCostReport(from, to time.Time, period, groupBy string) ([]*bean.CostReportRow, error) {
+	var truncateTo, periodLayout string
+	switch period {
+	case bean.CostPeriodDaily:
+		truncateTo, periodLayout = "day", "2006-01-02"
+	case bean.CostPeriodMonthly:
+		truncateTo, periodLayout = "month", "2006-01"
+	default:
+		return nil, &util.ApiError{
+			HttpStatusCode:  http.StatusBadRequest,
+			InternalMessage: fmt.Sprintf("invalid cost report period %s", period),
+			UserMessage:     "period must be daily or monthly",
+		}
+	}
+	if groupBy != bean.CostGroupByTeam && groupBy != bean.CostGroupByApp && groupBy != bean.CostGroupByEnvironment {
+		return nil, &util.ApiError{
+			HttpStatusCode:  http.StatusBadRequest,
+			InternalMessage: fmt.Sprintf("invalid cost report grouping %s", groupBy),
+			UserMessage:     "groupBy must be team, app or environment",
+		}
+	}
+	summary, err := impl.capacityCostRepository.FindCostSummary(truncateTo, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return groupCostSummary(summary, periodLayout, groupBy), nil
+}
+
+func groupCostSummary(summary []*repository.CostAllocationSummary, periodLayout, groupBy string) []*bean.CostReportRow {
+	type reportKey struct {
+		period string
+		id     int
+	}
+	rowsByKey := make(map[reportKey]*bean.CostReportRow)
+	rows := make([]*bean.CostReportRow, 0)
+	for _, entry := range summary {
+		key := reportKey{period: entry.Period.Format(periodLayout)}
+		switch groupBy {
+		case bean.CostGroupByTeam:
+			key.id = entry.TeamId
+		case bean.CostGroupByApp:
+			key.id = entry.AppId
+		case bean.CostGroupByEnvironment:
+			key.id = entry.EnvId
+		}
+		row, ok := rowsByKey[key]
+		if !ok {
+			row = &bean.CostReportRow{Period: key.period}
+			switch groupBy {
+			case bean.CostGroupByTeam:
+				row.TeamId, row.TeamName = entry.TeamId, entry.TeamName
+			case bean.CostGroupByApp:
+				row.TeamId, row.TeamName = entry.TeamId, entry.TeamName
+				row.AppId, row.AppName = entry.AppId, entry.AppName
+			case bean.CostGroupByEnvironment:
+				row.EnvId, row.EnvName = entry.EnvId, entry.EnvName
+			}
+			rowsByKey[key] = row
+			rows = append(rows, row)
+		}
+		row.CpuCost += entry.CpuCost
+		row.MemoryCost += entry.MemoryCost
+		row.GpuCost += entry.GpuCost
+	}
+	for _, row := range rows {
+		row.CpuCost = roundCost(row.CpuCost)
+		row.MemoryCost = roundCost(row.MemoryCost)
+		row.GpuCost = roundCost(row.GpuCost)
+		row.TotalCost = roundCost(row.CpuCost + row.MemoryCost + row.GpuCost)
+	}
+	return rows
+}
+
+func roundCost(cost float64) float64 {
+	return math.Round(cost*100) / 100
+}
+
+func (impl *K8sCostAllocationServiceImpl) GetAllPricing() ([]*bean.NodeGroupPricing, error) {
+	pricing, err := impl.capacityCostRepository.FindAllActivePricing()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*bean.NodeGroupPricing, 0, len(pricing))
+	for _, price := range pricing {
+		result = append(result, toNodeGroupPricingBean(price))
+	}
+	return result, nil
+}
+
+func (impl *K8sCostAllocationServiceImpl) SavePricing(pricing *bean.NodeGroupPricing, userId int32) (*bean.NodeGroupPricing, error) {
+	err := impl.validatePricing(pricing)
+	if err != nil {
+		return nil, err
+	}
+	model := &repository.NodeGroupPricing{
+		ClusterId:         pricing.ClusterId,
+		NodeGroup:         pricing.NodeGroup,
+		CpuCoreHourPrice:  pricing.CpuCoreHourPrice,
+		MemoryGbHourPrice: pricing.MemoryGbHourPrice,
+		GpuHourPrice:      pricing.GpuHourPrice,
+		Active:            true,
+		AuditLog:          sql.AuditLog{CreatedBy: userId, CreatedOn: time.Now(), UpdatedOn: time.Now(), UpdatedBy: userId},
+	}
+	err = impl.capacityCostRepository.SavePricing(model)
+	if err != nil {
+		return nil, err
+	}
+	return toNodeGroupPricingBean(model), nil
+}
+
+func (impl *K8sCostAllocationServiceImpl) UpdatePricing(pricing *bean.NodeGroupPricing, userId int32) (*bean.NodeGroupPricing, error) {
+	model, err := impl.findPricing(pricing.Id)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.validatePricing(pricing)
+	if err != nil {
+		return nil, err
+	}
+	model.ClusterId = pricing.ClusterId
+	model.NodeGroup = pricing.NodeGroup
+	model.CpuCoreHourPrice = pricing.CpuCoreHourPrice
+	model.MemoryGbHourPrice = pricing.MemoryGbHourPrice
+	model.GpuHourPrice = pricing.GpuHourPrice
+	model.UpdatedOn = time.Now()
+	model.UpdatedBy = userId
+	err = impl.capacityCostRepository.UpdatePricing(model)
+	if err != nil {
+		return nil, err
+	}
+	return toNodeGroupPricingBean(model), nil
+}
+
+func (impl *K8sCostAllocationServiceImpl) DeletePricing(id int, userId int32) error {
+	model, err := impl.findPricing(id)
+	if err != nil {
+		return err
+	}
+	model.Active = false
+	model.UpdatedOn = time.Now()
+	model.UpdatedBy = userId
+	return impl.capacityCostRepository.UpdatePricing(model)
+}
+
+func (impl *K8sCostAllocationServiceImpl) findPricing(id int) (*repository.NodeGroupPricing, error) {
+	model, err := impl.capacityCostRepository.FindPricingById(id)
+	if err == pg.ErrNoRows {
+		return nil, &util.ApiError{
+			HttpStatusCode:  http.StatusNotFound,
+			InternalMessage: fmt.Sprintf("node group pricing %d not found", id),
+			UserMessage:     "node group pricing not found",
+		}
+	} else if err != nil {
+		impl.logger.Errorw("error in getting node group pricing", "err", err, "id", id)
+		return nil, err
+	}
+	return model, nil
+}
+
+func (impl *K8sCostAllocationServiceImpl) validatePricing(pricing *bean.NodeGroupPricing) error {
+	if pricing.CpuCoreHourPrice < 0 || pricing.MemoryGbHourPrice < 0 || pricing.GpuHourPrice < 0 {
+		return &util.ApiError{
+			HttpStatusCode:  http.StatusBadRequest,
+			InternalMessage: "negative node group price",
+			UserMessage:     "prices can not be negative",
+		}
+	}
+	if pricing.ClusterId > 0 {
+		_, err := impl.clusterService.FindById(pricing.ClusterId)
+		if err != nil {
+			impl.logger.Errorw("error in getting cluster for node group pricing", "err", err, "clusterId", pricing.ClusterId)
+			return &util.ApiError{
+				HttpStatusCode:  http.StatusBadRequest,
+				InternalMessage: err.Error(),
+				UserMessage:     fmt.Sprintf("cluster %d not found", pricing.ClusterId),
+			}
+		}
+	}
+	existing, err := impl.capacityCostRepository.FindAllActivePricing()
+	if err != nil {
+		return err
+	}
+	for _, price := range existing {
+		if price.Id != pricing.Id && price.ClusterId == pricing.ClusterId && price.NodeGroup == pricing.NodeGroup {
+			return &util.ApiError{
+				HttpStatusCode:  http.StatusConflict,
+				InternalMessage: fmt.Sprintf("node group pricing %d already exists for cluster %d and node group %q", price.Id, price.ClusterId, price.NodeGroup),
+				UserMessage:     "price for this cluster and node group already exists",
+			}
+		}
+	}
+	return nil
+}
+
+func toNodeGroupPricingBean(model *repository.NodeGroupPricing) *bean.NodeGroupPricing {
+	return &bean.NodeGroupPricing{
+		Id:                model.Id,
+		ClusterId:         model.ClusterId,
+		NodeGroup:         model.NodeGroup,
+		CpuCoreHourPrice:  model.CpuCoreHourPrice,
+		MemoryGbHourPrice: model.MemoryGbHourPrice,
+		GpuHourPrice:      model.GpuHourPrice,
+	}
+}
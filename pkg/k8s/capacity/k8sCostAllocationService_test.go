@@ -0,0 +1,97 @@
+package capacity
+
+import (
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/repository"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func newCostTestPod(name, nodeName, appId, envId, cpu, memory string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{devtronAppIdLabel: appId, devtronEnvIdLabel: envId}},
+		Spec: corev1.PodSpec{NodeName: nodeName, Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(memory)},
+		}}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestAllocateNodeCost(t *testing.T) {
+	nodes := []corev1.Node{
+		{
+			ObjectMeta: v1.ObjectMeta{Name: "node-1", Labels: map[string]string{bean.AWSEKSNodeGroupLabel: "general"}},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("4"), corev1.ResourceMemory: resource.MustParse("16Gi"),
+			}},
+		},
+		{
+			ObjectMeta: v1.ObjectMeta{Name: "node-2", Labels: map[string]string{bean.AWSEKSNodeGroupLabel: "gpu"}},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("8Gi"), gpuResourceName: resource.MustParse("1"),
+			}},
+		},
+	}
+	completed := newCostTestPod("job", "node-1", "3", "1", "1", "1Gi")
+	completed.Status.Phase = corev1.PodSucceeded
+	pods := []corev1.Pod{
+		newCostTestPod("a-1", "node-1", "1", "1", "1", "4Gi"),
+		newCostTestPod("a-2", "node-1", "1", "1", "1", "4Gi"),
+		newCostTestPod("b-1", "node-1", "2", "1", "2", "8Gi"),
+		completed,
+		newCostTestPod("b-2", "node-2", "2", "1", "1", "1Gi"),
+	}
+	pricing := []*repository.NodeGroupPricing{
+		{ClusterId: 0, NodeGroup: "", CpuCoreHourPrice: 0.05, MemoryGbHourPrice: 0.01, GpuHourPrice: 1},
+		{ClusterId: 1, NodeGroup: "gpu", CpuCoreHourPrice: 0.1, MemoryGbHourPrice: 0.02, GpuHourPrice: 2},
+	}
+	allocations := allocateNodeCost(1, nodes, pods, pricing, 2)
+	assert.Len(t, allocations, 3)
+
+	// node-1 is priced by the default, 4 cores and 16Gi for 2 hours split by requests
+	assert.Equal(t, 1, allocations[0].AppId)
+	assert.InDelta(t, 0.2, allocations[0].CpuCost, 1e-9)
+	assert.InDelta(t, 0.16, allocations[0].MemoryCost, 1e-9)
+	assert.Equal(t, 2, allocations[1].AppId)
+	// half of node-1 and all cpu and memory of node-2, which has its own price
+	assert.InDelta(t, 0.2+0.4, allocations[1].CpuCost, 1e-9)
+	assert.InDelta(t, 0.16+0.32, allocations[1].MemoryCost, 1e-9)
+	assert.Zero(t, allocations[1].GpuCost)
+	// the gpu of node-2 is not requested by any pod
+	assert.Equal(t, 0, allocations[2].AppId)
+	assert.Empty(t, allocations[2].Namespace)
+	assert.InDelta(t, 4.0, allocations[2].GpuCost, 1e-9)
+	assert.Zero(t, allocations[2].CpuCost)
+
+	// nodes without a price are not charged
+	allocations = allocateNodeCost(1, nodes, pods, pricing[1:], 2)
+	assert.Len(t, allocations, 2)
+	assert.InDelta(t, 0.4, allocations[0].CpuCost, 1e-9)
+}
+
+func TestGroupCostSummary(t *testing.T) {
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	summary := []*repository.CostAllocationSummary{
+		{Period: day, TeamId: 1, TeamName: "payments", AppId: 1, AppName: "api", EnvId: 1, EnvName: "prod", CpuCost: 1.234, MemoryCost: 1},
+		{Period: day, TeamId: 1, TeamName: "payments", AppId: 2, AppName: "worker", EnvId: 2, EnvName: "staging", CpuCost: 1, GpuCost: 0.5},
+		{Period: day, TeamId: 2, TeamName: "search", AppId: 3, AppName: "indexer", EnvId: 1, EnvName: "prod", MemoryCost: 2},
+	}
+	rows := groupCostSummary(summary, "2006-01", bean.CostGroupByTeam)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, &bean.CostReportRow{Period: "2026-10", TeamId: 1, TeamName: "payments", CpuCost: 2.23, MemoryCost: 1, GpuCost: 0.5, TotalCost: 3.73}, rows[0])
+
+	rows = groupCostSummary(summary, "2006-01-02", bean.CostGroupByEnvironment)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "2026-10-01", rows[0].Period)
+	assert.Equal(t, "prod", rows[0].EnvName)
+	assert.Equal(t, 4.23, rows[0].TotalCost)
+
+	rows = groupCostSummary(summary, "2006-01", bean.CostGroupByApp)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, "worker", rows[1].AppName)
+	assert.Equal(t, "payments", rows[1].TeamName)
+}
@@ -0,0 +1,712 @@
+package capacity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	k8s2 "github.com/devtron-labs/devtron/util/k8s"
+	"github.com/go-pg/pg"
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaintenanceMaxRetries        = 10
+	defaultMaintenanceRetryIntervalSecs = 30
+	defaultMaintenanceHealthTimeoutMins = 10
+	maintenanceHealthCheckInterval      = 10 * time.Second
+	maintenanceHeartbeatInterval        = 30 * time.Second
+	// a running job whose owner missed this many heartbeats is taken to be interrupted
+	maintenanceHeartbeatMissLimit = 3
+)
+
+type K8sNodeMaintenanceService interface {
+	// CreateMaintenanceJob cordons the selected nodes and drains them one at a time in the background
+	CreateMaintenanceJob(ctx context.Context, request *bean.NodeMaintenanceRequest, userId int32) (*bean.NodeMaintenanceJob, error)
+	GetMaintenanceJob(id int) (*bean.NodeMaintenanceJob, error)
+	GetMaintenanceJobs(clusterId int) ([]*bean.NodeMaintenanceJob, error)
+	// UpdateMaintenanceJobStatus pauses, resumes or cancels a job, action is one of bean.MaintenanceActionPause,
+	// bean.MaintenanceActionResume and bean.MaintenanceActionCancel
+	UpdateMaintenanceJobStatus(id int, action string, userId int32) (*bean.NodeMaintenanceJob, error)
+}
+
+// maintenanceBlockedError is returned when a node can not be drained, the job is paused so that an operator can fix
+// the cause and resume it
+type maintenanceBlockedError struct {
+	message string
+}
+
+func (e *maintenanceBlockedError) Error() string {
+	return e.message
+}
+
+// maintenanceJobRun is a job being run by this instance, stopStatus is the status the job is left in once the run
+// stops after its context is cancelled
+type maintenanceJobRun struct {
+	cancel     context.CancelFunc
+	stopStatus string
+	userId     int32
+}
+
+type K8sNodeMaintenanceServiceImpl struct {
+	logger                    *zap.SugaredLogger
+	clusterService            cluster.ClusterService
+	K8sUtil                   *k8s2.K8sUtil
+	nodeMaintenanceRepository repository.NodeMaintenanceRepository
+	runs                      map[int]*maintenanceJobRun
+	runsLock                  *sync.Mutex
+	ownerId                   string
+}
+
+func NewK8sNodeMaintenanceServiceImpl(logger *zap.SugaredLogger, clusterService cluster.ClusterService, K8sUtil *k8s2.K8sUtil,
+	nodeMaintenanceRepository repository.NodeMaintenanceRepository) (*K8sNodeMaintenanceServiceImpl, error) {
+	serviceImpl := &K8sNodeMaintenanceServiceImpl{
+		logger:                    logger,
+		clusterService:            clusterService,
+		K8sUtil:                   K8sUtil,
+		nodeMaintenanceRepository: nodeMaintenanceRepository,
+		runs:                      make(map[int]*maintenanceJobRun),
+		runsLock:                  &sync.Mutex{},
+		ownerId:                   newMaintenanceOwnerId(),
+	}
+	heartbeatCron := cron.New(cron.WithChain())
+	heartbeatCron.Start()
+	_, err := heartbeatCron.AddFunc(fmt.Sprintf("@every %ds", int(maintenanceHeartbeatInterval.Seconds())), serviceImpl.heartbeat)
+	if err != nil {
+		logger.Errorw("error in adding node maintenance heartbeat cron", "err", err)
+		return nil, err
+	}
+	return serviceImpl, nil
+}
+
+// newMaintenanceOwnerId identifies this instance as the owner of the jobs it runs, the hostname is only there to make
+// the owner readable
+func newMaintenanceOwnerId() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "devtron"
+	}
+	return fmt.Sprintf("%s-%s", hostname, uuid.New().String())
+}
+
+// heartbeat keeps the jobs run by this instance alive and pauses the running jobs whose owner stopped sending
+// heartbeats, so that nobody is surprised by a drain continuing after the instance running it comes back
+func (impl *K8sNodeMaintenanceServiceImpl) heartbeat() {
+	impl.runsLock.Lock()
+	jobIds := make([]int, 0, len(impl.runs))
+	for jobId := range impl.runs {
+		jobIds = append(jobIds, jobId)
+	}
+	impl.runsLock.Unlock()
+	err := impl.nodeMaintenanceRepository.UpdateHeartbeat(impl.ownerId, jobIds, time.Now())
+	if err != nil {
+		// not pausing others while this instance can not prove its own jobs alive
+		return
+	}
+	heartbeatBefore := time.Now().Add(-maintenanceHeartbeatMissLimit * maintenanceHeartbeatInterval)
+	pausedJobIds, err := impl.nodeMaintenanceRepository.UpdateStaleJobsStatus(bean.MaintenanceStatusRunning, heartbeatBefore,
+		bean.MaintenanceStatusPaused, "interrupted as the instance running it stopped, resume to continue")
+	if err != nil {
+		return
+	}
+	if len(pausedJobIds) > 0 {
+		impl.logger.Infow("paused node maintenance jobs of stopped instances", "jobIds", pausedJobIds)
+	}
+}
+
+func (impl *K8sNodeMaintenanceServiceImpl) CreateMaintenanceJob(ctx context.Context, request *bean.NodeMaintenanceRequest, userId int32) (*bean.NodeMaintenanceJob, error) {
+	if len(request.NodeGroup) == 0 && len(request.LabelSelector) == 0 {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: "node group or label selector is required", UserMessage: "node group or label selector is required"}
+	}
+	if request.NodeDrainHelper == nil {
+		request.NodeDrainHelper = &bean.NodeDrainHelper{GracePeriodSeconds: -1, IgnoreAllDaemonSets: true}
+	}
+	if request.NodeDrainHelper.DisableEviction {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: "disable eviction is not supported for maintenance",
+			UserMessage: "maintenance always evicts pods so that PodDisruptionBudgets are respected, disableEviction is not supported"}
+	}
+	selector, err := labels.Parse(request.LabelSelector)
+	if err != nil {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: err.Error(), UserMessage: fmt.Sprintf("invalid label selector: %s", err.Error())}
+	}
+	activeJobs, err := impl.nodeMaintenanceRepository.FindJobsByStatus([]string{bean.MaintenanceStatusRunning, bean.MaintenanceStatusPaused})
+	if err != nil {
+		return nil, err
+	}
+	for _, activeJob := range activeJobs {
+		if activeJob.ClusterId == request.ClusterId {
+			return nil, &util.ApiError{HttpStatusCode: http.StatusConflict, InternalMessage: fmt.Sprintf("maintenance job %d is active on cluster", activeJob.Id),
+				UserMessage: fmt.Sprintf("maintenance job %d is still %s on this cluster, cancel it or wait for it to finish", activeJob.Id, strings.ToLower(activeJob.Status))}
+		}
+	}
+	k8sClientSet, err := impl.getK8sClientSet(request.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	nodeList, err := impl.K8sUtil.GetNodesList(ctx, k8sClientSet)
+	if err != nil {
+		impl.logger.Errorw("error in getting node list", "err", err, "clusterId", request.ClusterId)
+		return nil, err
+	}
+	var nodes []*repository.NodeMaintenanceNode
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		if len(request.NodeGroup) > 0 && getNodeGroup(node) != request.NodeGroup {
+			continue
+		}
+		if !selector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		nodes = append(nodes, &repository.NodeMaintenanceNode{NodeName: node.Name, Status: bean.MaintenanceNodeStatusPending})
+	}
+	if len(nodes) == 0 {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: "no nodes match the request", UserMessage: "no nodes match the node group and label selector"}
+	}
+	drainOptions, err := json.Marshal(request.NodeDrainHelper)
+	if err != nil {
+		return nil, err
+	}
+	job := &repository.NodeMaintenanceJob{
+		ClusterId:         request.ClusterId,
+		NodeGroup:         request.NodeGroup,
+		LabelSelector:     request.LabelSelector,
+		DrainOptions:      string(drainOptions),
+		MaxRetries:        request.MaxRetries,
+		RetryIntervalSecs: request.RetryIntervalSecs,
+		HealthTimeoutMins: request.HealthTimeoutMins,
+		Status:            bean.MaintenanceStatusRunning,
+		StartedOn:         time.Now(),
+		OwnerId:           impl.ownerId,
+		HeartbeatOn:       time.Now(),
+		AuditLog:          sql.AuditLog{CreatedBy: userId, CreatedOn: time.Now(), UpdatedOn: time.Now(), UpdatedBy: userId},
+	}
+	if job.MaxRetries <= 0 {
+		job.MaxRetries = defaultMaintenanceMaxRetries
+	}
+	if job.RetryIntervalSecs <= 0 {
+		job.RetryIntervalSecs = defaultMaintenanceRetryIntervalSecs
+	}
+	if job.HealthTimeoutMins <= 0 {
+		job.HealthTimeoutMins = defaultMaintenanceHealthTimeoutMins
+	}
+	err = impl.nodeMaintenanceRepository.SaveJob(job, nodes)
+	if err != nil {
+		return nil, err
+	}
+	impl.startJob(job, userId)
+	return toNodeMaintenanceJobBean(job, nodes), nil
+}
+
+func (impl *K8sNodeMaintenanceServiceImpl) GetMaintenanceJob(id int) (*bean.NodeMaintenanceJob, error) {
+	job, err := impl.findJob(id)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := impl.nodeMaintenanceRepository.FindNodesByJobId(id)
+	if err != nil {
+		return nil, err
+	}
+	return toNodeMaintenanceJobBean(job, nodes), nil
+}
+
+func (impl *K8sNodeMaintenanceServiceImpl) GetMaintenanceJobs(clusterId int) ([]*bean.NodeMaintenanceJob, error) {
+	jobs, err := impl.nodeMaintenanceRepository.FindJobsByClusterId(clusterId)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*bean.NodeMaintenanceJob, 0, len(jobs))
+	for _, job := range jobs {
+		nodes, err := impl.nodeMaintenanceRepository.FindNodesByJobId(job.Id)
+		if err != nil {
+			return nil, err
+		}
+		jobBean := toNodeMaintenanceJobBean(job, nodes)
+		jobBean.Nodes = nil
+		result = append(result, jobBean)
+	}
+	return result, nil
+}
+
+func (impl *K8sNodeMaintenanceServiceImpl) UpdateMaintenanceJobStatus(id int, action string, userId int32) (*bean.NodeMaintenanceJob, error) {
+	job, err := impl.findJob(id)
+	if err != nil {
+		return nil, err
+	}
+	invalidAction := &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: fmt.Sprintf("can not %s %s maintenance job", action, job.Status),
+		UserMessage: fmt.Sprintf("can not %s a job which is %s", action, strings.ToLower(job.Status))}
+	switch action {
+	case bean.MaintenanceActionPause, bean.MaintenanceActionCancel:
+		if job.Status == bean.MaintenanceStatusPaused && action == bean.MaintenanceActionCancel {
+			impl.cancelJob(job, userId)
+			break
+		}
+		if job.Status != bean.MaintenanceStatusRunning {
+			return nil, invalidAction
+		}
+		stopStatus := bean.MaintenanceStatusPaused
+		if action == bean.MaintenanceActionCancel {
+			stopStatus = bean.MaintenanceStatusCancelled
+		}
+		job.Status = stopStatus
+		job.UpdatedOn = time.Now()
+		job.UpdatedBy = userId
+		err = impl.nodeMaintenanceRepository.UpdateJob(job)
+		if err != nil {
+			return nil, err
+		}
+		// the run stops at once when it is on this instance, else before its next node
+		impl.runsLock.Lock()
+		if run, ok := impl.runs[id]; ok {
+			run.stopStatus = stopStatus
+			run.userId = userId
+			run.cancel()
+		}
+		impl.runsLock.Unlock()
+	case bean.MaintenanceActionResume:
+		if job.Status != bean.MaintenanceStatusPaused {
+			return nil, invalidAction
+		}
+		impl.runsLock.Lock()
+		_, stopping := impl.runs[id]
+		impl.runsLock.Unlock()
+		if stopping {
+			return nil, &util.ApiError{HttpStatusCode: http.StatusConflict, InternalMessage: fmt.Sprintf("maintenance job %d is still stopping", id),
+				UserMessage: "the job is still stopping, retry in a few seconds"}
+		}
+		job.Status = bean.MaintenanceStatusRunning
+		job.Message = ""
+		job.OwnerId = impl.ownerId
+		job.UpdatedOn = time.Now()
+		job.UpdatedBy = userId
+		err = impl.nodeMaintenanceRepository.UpdateJob(job)
+		if err != nil {
+			return nil, err
+		}
+		// the heartbeat of the new owner, else the job may be taken as stale before this instance's next heartbeat
+		err = impl.nodeMaintenanceRepository.UpdateHeartbeat(impl.ownerId, []int{id}, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		impl.startJob(job, userId)
+	default:
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: fmt.Sprintf("invalid maintenance action %s", action),
+			UserMessage: "action must be pause, resume or cancel"}
+	}
+	return impl.GetMaintenanceJob(id)
+}
+
+func (impl *K8sNodeMaintenanceServiceImpl) findJob(id int) (*repository.NodeMaintenanceJob, error) {
+	job, err := impl.nodeMaintenanceRepository.FindJobById(id)
+	if err == pg.ErrNoRows {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusNotFound, InternalMessage: fmt.Sprintf("maintenance job %d not found", id), UserMessage: "maintenance job not found"}
+	} else if err != nil {
+		impl.logger.Errorw("error in getting maintenance job", "err", err, "id", id)
+		return nil, err
+	}
+	return job, nil
+}
+
+func (impl *K8sNodeMaintenanceServiceImpl) getK8sClientSet(clusterId int) (*kubernetes.Clientset, error) {
+	clusterBean, err := impl.clusterService.FindById(clusterId)
+	if err != nil {
+		impl.logger.Errorw("error in getting cluster by ID", "err", err, "clusterId", clusterId)
+		return nil, err
+	}
+	clusterConfig, err := clusterBean.GetClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	_, _, k8sClientSet, err := impl.K8sUtil.GetK8sConfigAndClients(clusterConfig)
+	if err != nil {
+		impl.logger.Errorw("error in getting k8s clients", "err", err, "clusterId", clusterId)
+		return nil, err
+	}
+	return k8sClientSet, nil
+}
+
+func (impl *K8sNodeMaintenanceServiceImpl) startJob(job *repository.NodeMaintenanceJob, userId int32) {
+	ctx, cancel := context.WithCancel(context.Background())
+	impl.runsLock.Lock()
+	impl.runs[job.Id] = &maintenanceJobRun{cancel: cancel, userId: userId}
+	impl.runsLock.Unlock()
+	go impl.runJob(ctx, job)
+}
+
+// runJob cordons all nodes of the job which are not drained yet and then drains them one at a time. A node which can
+// not be drained pauses the job, resuming it starts again from that node.
+func (impl *K8sNodeMaintenanceServiceImpl) runJob(ctx context.Context, job *repository.NodeMaintenanceJob) {
+	status, message := bean.MaintenanceStatusSucceeded, ""
+	err := impl.drainJobNodes(ctx, job)
+	impl.runsLock.Lock()
+	run := impl.runs[job.Id]
+	delete(impl.runs, job.Id)
+	impl.runsLock.Unlock()
+	run.cancel()
+	if ctx.Err() != nil {
+		status, message = run.stopStatus, ""
+	} else if _, ok := err.(*maintenanceBlockedError); ok {
+		status, message = bean.MaintenanceStatusPaused, err.Error()
+	} else if err != nil {
+		status, message = bean.MaintenanceStatusFailed, err.Error()
+	}
+	impl.logger.Infow("node maintenance job stopped", "jobId", job.Id, "status", status, "message", message)
+	if status == bean.MaintenanceStatusCancelled {
+		impl.cancelJob(job, run.userId)
+		return
+	}
+	job.Status = status
+	job.Message = message
+	job.UpdatedOn = time.Now()
+	job.UpdatedBy = run.userId
+	if status == bean.MaintenanceStatusSucceeded || status == bean.MaintenanceStatusFailed {
+		job.FinishedOn = time.Now()
+	}
+	_ = impl.nodeMaintenanceRepository.UpdateJob(job)
+}
+
+// cancelJob uncordons the nodes whose drain has not started, drained nodes are left cordoned for their maintenance
+func (impl *K8sNodeMaintenanceServiceImpl) cancelJob(job *repository.NodeMaintenanceJob, userId int32) {
+	job.Status = bean.MaintenanceStatusCancelled
+	job.FinishedOn = time.Now()
+	job.UpdatedOn = time.Now()
+	job.UpdatedBy = userId
+	nodes, err := impl.nodeMaintenanceRepository.FindNodesByJobId(job.Id)
+	if err == nil {
+		var errs []error
+		k8sClientSet, err := impl.getK8sClientSet(job.ClusterId)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, node := range nodes {
+			if node.Status != bean.MaintenanceNodeStatusPending || k8sClientSet == nil {
+				continue
+			}
+			err = impl.setNodeUnschedulable(context.Background(), k8sClientSet, node.NodeName, false)
+			if err != nil && !apierrors.IsNotFound(err) {
+				errs = append(errs, err)
+			}
+		}
+		if len(errs) > 0 {
+			job.Message = fmt.Sprintf("error in uncordoning nodes: %s", utilerrors.NewAggregate(errs).Error())
+		}
+	}
+	_ = impl.nodeMaintenanceRepository.UpdateJob(job)
+}
+
+func (impl *K8sNodeMaintenanceServiceImpl) drainJobNodes(ctx context.Context, job *repository.NodeMaintenanceJob) error {
+	drainHelper := &bean.NodeDrainHelper{}
+	if len(job.DrainOptions) > 0 {
+		err := json.Unmarshal([]byte(job.DrainOptions), drainHelper)
+		if err != nil {
+			return err
+		}
+	}
+	k8sClientSet, err := impl.getK8sClientSet(job.ClusterId)
+	if err != nil {
+		return err
+	}
+	drainHelper.K8sClientSet = k8sClientSet
+	evictionGroupVersion, err := k8s2.CheckEvictionSupport(k8sClientSet)
+	if err != nil {
+		return err
+	}
+	if evictionGroupVersion.Empty() {
+		return fmt.Errorf("cluster does not support eviction, pods can not be drained respecting PodDisruptionBudgets")
+	}
+	nodes, err := impl.nodeMaintenanceRepository.FindNodesByJobId(job.Id)
+	if err != nil {
+		return err
+	}
+	var pendingNodes []*repository.NodeMaintenanceNode
+	for _, node := range nodes {
+		if node.Status == bean.MaintenanceNodeStatusDrained || node.Status == bean.MaintenanceNodeStatusSkipped {
+			continue
+		}
+		err = impl.setNodeUnschedulable(ctx, k8sClientSet, node.NodeName, true)
+		if apierrors.IsNotFound(err) {
+			impl.updateNode(node, bean.MaintenanceNodeStatusSkipped, "node no longer exists")
+			continue
+		} else if err != nil {
+			return err
+		}
+		pendingNodes = append(pendingNodes, node)
+	}
+	for _, node := range pendingNodes {
+		if impl.isStopRequested(ctx, job.Id) {
+			return ctx.Err()
+		}
+		err = impl.drainNode(ctx, job, node, drainHelper, evictionGroupVersion)
+		if err != nil {
+			if ctx.Err() == nil {
+				impl.updateNode(node, bean.MaintenanceNodeStatusFailed, err.Error())
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// isStopRequested also stops runs whose job was paused or cancelled through another instance
+func (impl *K8sNodeMaintenanceServiceImpl) isStopRequested(ctx context.Context, jobId int) bool {
+	if ctx.Err() != nil {
+		return true
+	}
+	job, err := impl.nodeMaintenanceRepository.FindJobById(jobId)
+	if err != nil || job.Status == bean.MaintenanceStatusRunning {
+		return false
+	}
+	impl.runsLock.Lock()
+	if run, ok := impl.runs[jobId]; ok {
+		run.stopStatus = job.Status
+		run.cancel()
+	}
+	impl.runsLock.Unlock()
+	return true
+}
+
+func (impl *K8sNodeMaintenanceServiceImpl) drainNode(ctx context.Context, job *repository.NodeMaintenanceJob, node *repository.NodeMaintenanceNode,
+	drainHelper *bean.NodeDrainHelper, evictionGroupVersion schema.GroupVersion) error {
+	impl.logger.Infow("draining node for maintenance", "jobId", job.Id, "nodeName", node.NodeName)
+	node.StartedOn = time.Now()
+	node.Attempts = 0
+	impl.updateNode(node, bean.MaintenanceNodeStatusDraining, "")
+	list, errs := GetPodsByNodeNameForDeletion(node.NodeName, drainHelper)
+	if len(errs) > 0 {
+		return &maintenanceBlockedError{message: fmt.Sprintf("node %s can not be drained with the drain options: %s", node.NodeName, utilerrors.NewAggregate(errs).Error())}
+	}
+	pods := list.Pods()
+	node.PodCount = len(pods)
+	workloads, err := impl.getDevtronWorkloads(ctx, drainHelper.K8sClientSet, pods)
+	if err != nil {
+		return err
+	}
+	deleteOptions := v1.DeleteOptions{}
+	if drainHelper.GracePeriodSeconds >= 0 {
+		gracePeriodSeconds := int64(drainHelper.GracePeriodSeconds)
+		deleteOptions.GracePeriodSeconds = &gracePeriodSeconds
+	}
+	for {
+		node.Attempts++
+		blocked, err := impl.evictNodePods(pods, drainHelper.K8sClientSet, evictionGroupVersion, deleteOptions)
+		if err != nil {
+			return err
+		}
+		if len(blocked) == 0 {
+			break
+		}
+		message := fmt.Sprintf("eviction of %s blocked by PodDisruptionBudget", strings.Join(getPodNames(blocked), ", "))
+		if node.Attempts > job.MaxRetries {
+			return &maintenanceBlockedError{message: fmt.Sprintf("node %s: %s after %d attempts", node.NodeName, message, node.Attempts)}
+		}
+		impl.updateNode(node, bean.MaintenanceNodeStatusDraining, message)
+		if !sleepWithContext(ctx, time.Duration(job.RetryIntervalSecs)*time.Second) {
+			return ctx.Err()
+		}
+		pods = blocked
+	}
+	impl.updateNode(node, bean.MaintenanceNodeStatusWaitingForApps, "")
+	err = impl.waitForWorkloads(ctx, drainHelper.K8sClientSet, node.NodeName, workloads, time.Duration(job.HealthTimeoutMins)*time.Minute)
+	if err != nil {
+		return err
+	}
+	node.FinishedOn = time.Now()
+	impl.updateNode(node, bean.MaintenanceNodeStatusDrained, "")
+	return nil
+}
+
+// evictNodePods returns the pods whose eviction was refused as it would violate their PodDisruptionBudget
+func (impl *K8sNodeMaintenanceServiceImpl) evictNodePods(pods []corev1.Pod, k8sClientSet *kubernetes.Clientset, evictionGroupVersion schema.GroupVersion,
+	deleteOptions v1.DeleteOptions) ([]corev1.Pod, error) {
+	var blocked []corev1.Pod
+	var errs []error
+	for _, pod := range pods {
+		err := k8s2.EvictPod(pod, k8sClientSet, evictionGroupVersion, deleteOptions)
+		if err == nil || apierrors.IsNotFound(err) {
+			continue
+		} else if apierrors.IsTooManyRequests(err) {
+			blocked = append(blocked, pod)
+		} else {
+			errs = append(errs, fmt.Errorf("error when evicting pods/%q -n %q: %v", pod.Name, pod.Namespace, err))
+		}
+	}
+	return blocked, utilerrors.NewAggregate(errs)
+}
+
+// maintenanceWorkload is a workload of a devtron app with pods on a node being drained, readyPods is the count of its
+// ready pods before the drain
+type maintenanceWorkload struct {
+	namespace string
+	kind      string
+	name      string
+	readyPods int
+}
+
+func (impl *K8sNodeMaintenanceServiceImpl) getDevtronWorkloads(ctx context.Context, k8sClientSet *kubernetes.Clientset, pods []corev1.Pod) ([]*maintenanceWorkload, error) {
+	var workloads []*maintenanceWorkload
+	seen := make(map[string]bool)
+	for i := range pods {
+		pod := &pods[i]
+		if len(pod.Labels[devtronAppIdLabel]) == 0 {
+			continue
+		}
+		kind, name := getPodWorkload(pod)
+		key := fmt.Sprintf("%s/%s/%s", pod.Namespace, kind, name)
+		if kind == "Pod" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		workloads = append(workloads, &maintenanceWorkload{namespace: pod.Namespace, kind: kind, name: name})
+	}
+	for _, workload := range workloads {
+		podList, err := impl.K8sUtil.GetPodsListForNamespace(ctx, k8sClientSet, workload.namespace)
+		if err != nil {
+			return nil, err
+		}
+		workload.readyPods = countReadyWorkloadPods(podList.Items, workload, "")
+	}
+	return workloads, nil
+}
+
+// waitForWorkloads waits till the workloads have as many ready pods outside the drained node as they had before the drain
+func (impl *K8sNodeMaintenanceServiceImpl) waitForWorkloads(ctx context.Context, k8sClientSet *kubernetes.Clientset, nodeName string,
+	workloads []*maintenanceWorkload, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		var unhealthy []string
+		for _, workload := range workloads {
+			podList, err := impl.K8sUtil.GetPodsListForNamespace(ctx, k8sClientSet, workload.namespace)
+			if err != nil {
+				return err
+			}
+			if ready := countReadyWorkloadPods(podList.Items, workload, nodeName); ready < workload.readyPods {
+				unhealthy = append(unhealthy, fmt.Sprintf("%s/%s (%d/%d ready)", workload.namespace, workload.name, ready, workload.readyPods))
+			}
+		}
+		if len(unhealthy) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return &maintenanceBlockedError{message: fmt.Sprintf("node %s: replacement pods of %s not ready after %s", nodeName, strings.Join(unhealthy, ", "), timeout)}
+		}
+		if !sleepWithContext(ctx, maintenanceHealthCheckInterval) {
+			return ctx.Err()
+		}
+	}
+}
+
+// countReadyWorkloadPods counts the ready pods of the workload which are not terminating and not on excludedNode
+func countReadyWorkloadPods(pods []corev1.Pod, workload *maintenanceWorkload, excludedNode string) int {
+	count := 0
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil || (len(excludedNode) > 0 && pod.Spec.NodeName == excludedNode) {
+			continue
+		}
+		if kind, name := getPodWorkload(pod); kind != workload.kind || name != workload.name {
+			continue
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+func (impl *K8sNodeMaintenanceServiceImpl) setNodeUnschedulable(ctx context.Context, k8sClientSet *kubernetes.Clientset, nodeName string, unschedulable bool) error {
+	node, err := impl.K8sUtil.GetNodeByName(ctx, k8sClientSet, nodeName)
+	if err != nil {
+		return err
+	}
+	if node.Spec.Unschedulable == unschedulable {
+		return nil
+	}
+	_, err = k8s2.UpdateNodeUnschedulableProperty(unschedulable, node, k8sClientSet)
+	return err
+}
+
+func (impl *K8sNodeMaintenanceServiceImpl) updateNode(node *repository.NodeMaintenanceNode, status, message string) {
+	node.Status = status
+	node.Message = message
+	_ = impl.nodeMaintenanceRepository.UpdateNode(node)
+}
+
+func sleepWithContext(ctx context.Context, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func getPodNames(pods []corev1.Pod) []string {
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		names = append(names, pod.Namespace+"/"+pod.Name)
+	}
+	return names
+}
+
+func toNodeMaintenanceJobBean(job *repository.NodeMaintenanceJob, nodes []*repository.NodeMaintenanceNode) *bean.NodeMaintenanceJob {
+	jobBean := &bean.NodeMaintenanceJob{
+		Id:                job.Id,
+		ClusterId:         job.ClusterId,
+		NodeGroup:         job.NodeGroup,
+		LabelSelector:     job.LabelSelector,
+		MaxRetries:        job.MaxRetries,
+		RetryIntervalSecs: job.RetryIntervalSecs,
+		HealthTimeoutMins: job.HealthTimeoutMins,
+		Status:            job.Status,
+		Message:           job.Message,
+		TotalNodes:        len(nodes),
+		StartedOn:         timeOrNil(job.StartedOn),
+		FinishedOn:        timeOrNil(job.FinishedOn),
+		CreatedBy:         job.CreatedBy,
+		Nodes:             make([]*bean.NodeMaintenanceNode, 0, len(nodes)),
+	}
+	for _, node := range nodes {
+		if node.Status == bean.MaintenanceNodeStatusDrained {
+			jobBean.DrainedNodes++
+		}
+		jobBean.Nodes = append(jobBean.Nodes, &bean.NodeMaintenanceNode{
+			NodeName:   node.NodeName,
+			Status:     node.Status,
+			Attempts:   node.Attempts,
+			PodCount:   node.PodCount,
+			Message:    node.Message,
+			StartedOn:  timeOrNil(node.StartedOn),
+			FinishedOn: timeOrNil(node.FinishedOn),
+		})
+	}
+	return jobBean
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,64 @@
+package capacity
+
+import (
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/repository"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func newMaintenanceTestPod(name, nodeName string, ready bool) corev1.Pod {
+	controller := true
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:            name,
+			Namespace:       "prod",
+			Labels:          map[string]string{podTemplateHashLabel: "5c8d", devtronAppIdLabel: "1"},
+			OwnerReferences: []v1.OwnerReference{{Kind: "ReplicaSet", Name: "api-5c8d", Controller: &controller}},
+		},
+		Spec:   corev1.PodSpec{NodeName: nodeName},
+		Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}},
+	}
+}
+
+func TestCountReadyWorkloadPods(t *testing.T) {
+	terminating := newMaintenanceTestPod("api-5c8d-3", "node-2", true)
+	terminating.DeletionTimestamp = &v1.Time{Time: time.Now()}
+	other := newMaintenanceTestPod("web-1", "node-2", true)
+	other.OwnerReferences[0].Name = "web-5c8d"
+	pods := []corev1.Pod{
+		newMaintenanceTestPod("api-5c8d-1", "node-1", true),
+		newMaintenanceTestPod("api-5c8d-2", "node-2", true),
+		newMaintenanceTestPod("api-5c8d-4", "node-3", false),
+		terminating,
+		other,
+	}
+	workload := &maintenanceWorkload{namespace: "prod", kind: "ReplicaSet", name: "api"}
+	assert.Equal(t, 2, countReadyWorkloadPods(pods, workload, ""))
+	// pods on the node being drained do not count as replacements
+	assert.Equal(t, 1, countReadyWorkloadPods(pods, workload, "node-1"))
+}
+
+func TestToNodeMaintenanceJobBean(t *testing.T) {
+	job := &repository.NodeMaintenanceJob{Id: 1, ClusterId: 2, NodeGroup: "general", Status: bean.MaintenanceStatusPaused, StartedOn: time.Now()}
+	nodes := []*repository.NodeMaintenanceNode{
+		{NodeName: "node-1", Status: bean.MaintenanceNodeStatusDrained, FinishedOn: time.Now()},
+		{NodeName: "node-2", Status: bean.MaintenanceNodeStatusFailed, Attempts: 11, Message: "eviction of prod/api-1 blocked by PodDisruptionBudget"},
+		{NodeName: "node-3", Status: bean.MaintenanceNodeStatusPending},
+	}
+	jobBean := toNodeMaintenanceJobBean(job, nodes)
+	assert.Equal(t, 3, jobBean.TotalNodes)
+	assert.Equal(t, 1, jobBean.DrainedNodes)
+	assert.NotNil(t, jobBean.StartedOn)
+	assert.Nil(t, jobBean.FinishedOn)
+	assert.Len(t, jobBean.Nodes, 3)
+	assert.NotNil(t, jobBean.Nodes[0].FinishedOn)
+	assert.Nil(t, jobBean.Nodes[2].StartedOn)
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// NodeGroupPricing is the hourly price of the nodes of a node group, a cluster id of 0 applies to all clusters and an
+// empty node group to all node groups of the cluster without a price of their own
+type NodeGroupPricing struct {
+	tableName         struct{} `sql:"capacity_node_group_pricing" pg:",discard_unknown_columns"`
+	Id                int      `sql:"id,pk"`
+	ClusterId         int      `sql:"cluster_id,notnull"`
+	NodeGroup         string   `sql:"node_group,notnull"`
+	CpuCoreHourPrice  float64  `sql:"cpu_core_hour_price,notnull"`
+	MemoryGbHourPrice float64  `sql:"memory_gb_hour_price,notnull"`
+	GpuHourPrice      float64  `sql:"gpu_hour_price,notnull"`
+	Active            bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+// CostAllocation is the cost of the pods of an app and environment in a namespace over the interval ending at
+// CapturedOn. Names are kept as they were at that time so that reports still add up after renames and deletions, cost
+// of nodes without requests is kept with app id 0 and an empty namespace.
+type CostAllocation struct {
+	tableName    struct{}  `sql:"capacity_cost_allocation" pg:",discard_unknown_columns"`
+	Id           int       `sql:"id,pk"`
+	ClusterId    int       `sql:"cluster_id"`
+	Namespace    string    `sql:"namespace,notnull"`
+	TeamId       int       `sql:"team_id,notnull"`
+	TeamName     string    `sql:"team_name,notnull"`
+	AppId        int       `sql:"app_id,notnull"`
+	AppName      string    `sql:"app_name,notnull"`
+	EnvId        int       `sql:"env_id,notnull"`
+	EnvName      string    `sql:"env_name,notnull"`
+	CpuCost      float64   `sql:"cpu_cost,notnull"`
+	MemoryCost   float64   `sql:"memory_cost,notnull"`
+	GpuCost      float64   `sql:"gpu_cost,notnull"`
+	DurationSecs int       `sql:"duration_secs,notnull"`
+	CapturedOn   time.Time `sql:"captured_on,type:timestamptz"`
+}
+
+type CostAllocationSummary struct {
+	Period     time.Time `sql:"period"`
+	TeamId     int       `sql:"team_id"`
+	TeamName   string    `sql:"team_name"`
+	AppId      int       `sql:"app_id"`
+	AppName    string    `sql:"app_name"`
+	EnvId      int       `sql:"env_id"`
+	EnvName    string    `sql:"env_name"`
+	CpuCost    float64   `sql:"cpu_cost"`
+	MemoryCost float64   `sql:"memory_cost"`
+	GpuCost    float64   `sql:"gpu_cost"`
+}
+
+type CapacityCostRepository interface {
+	SavePricing(pricing *NodeGroupPricing) error
+	UpdatePricing(pricing *NodeGroupPricing) error
+	FindPricingById(id int) (*NodeGroupPricing, error)
+	FindAllActivePricing() ([]*NodeGroupPricing, error)
+	SaveCostAllocations(allocations []*CostAllocation) error
+	// FindCostSummary sums the cost per app and environment for each day or month, period is "day" or "month"
+	FindCostSummary(period string, from, to time.Time) ([]*CostAllocationSummary, error)
+	DeleteCostAllocationsBefore(before time.Time) error
+}
+
+type CapacityCostRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewCapacityCostRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *CapacityCostRepositoryImpl {
+	return &CapacityCostRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *CapacityCostRepositoryImpl) SavePricing(pricing *NodeGroupPricing) error {
+	err := impl.dbConnection.Insert(pricing)
+	if err != nil {
+		impl.logger.Errorw("error in saving node group pricing", "err", err, "clusterId", pricing.ClusterId, "nodeGroup", pricing.NodeGroup)
+		return err
+	}
+	return nil
+}
+
+func (impl *CapacityCostRepositoryImpl) UpdatePricing(pricing *NodeGroupPricing) error {
+	err := impl.dbConnection.Update(pricing)
+	if err != nil {
+		impl.logger.Errorw("error in updating node group pricing", "err", err, "id", pricing.Id)
+		return err
+	}
+	return nil
+}
+
+func (impl *CapacityCostRepositoryImpl) FindPricingById(id int) (*NodeGroupPricing, error) {
+	pricing := &NodeGroupPricing{}
+	err := impl.dbConnection.Model(pricing).
+		Where("id = ?", id).
+		Where("active = ?", true).Select()
+	if err != nil {
+		return nil, err
+	}
+	return pricing, nil
+}
+
+func (impl *CapacityCostRepositoryImpl) FindAllActivePricing() ([]*NodeGroupPricing, error) {
+	var pricing []*NodeGroupPricing
+	err := impl.dbConnection.Model(&pricing).
+		Where("active = ?", true).
+		Order("cluster_id ASC", "node_group ASC").Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting node group pricing", "err", err)
+		return nil, err
+	}
+	return pricing, nil
+}
+
+func (impl *CapacityCostRepositoryImpl) SaveCostAllocations(allocations []*CostAllocation) error {
+	if len(allocations) == 0 {
+		return nil
+	}
+	err := impl.dbConnection.Insert(&allocations)
+	if err != nil {
+		impl.logger.Errorw("error in saving cost allocations", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *CapacityCostRepositoryImpl) FindCostSummary(period string, from, to time.Time) ([]*CostAllocationSummary, error) {
+	var summary []*CostAllocationSummary
+	query := "SELECT date_trunc(?, captured_on) AS period, team_id, MAX(team_name) AS team_name, app_id, MAX(app_name) AS app_name," +
+		" env_id, MAX(env_name) AS env_name, SUM(cpu_cost) AS cpu_cost, SUM(memory_cost) AS memory_cost, SUM(gpu_cost) AS gpu_cost" +
+		" FROM capacity_cost_allocation WHERE captured_on >= ? AND captured_on < ?" +
+		" GROUP BY period, team_id, app_id, env_id ORDER BY period, team_id, app_id, env_id;"
+	_, err := impl.dbConnection.Query(&summary, query, period, from, to)
+	if err != nil {
+		impl.logger.Errorw("error in getting cost summary", "err", err, "period", period, "from", from, "to", to)
+		return nil, err
+	}
+	return summary, nil
+}
+
+func (impl *CapacityCostRepositoryImpl) DeleteCostAllocationsBefore(before time.Time) error {
+	var allocation *CostAllocation
+	_, err := impl.dbConnection.Model(allocation).
+		Where("captured_on < ?", before).Delete()
+	if err != nil {
+		impl.logger.Errorw("error in deleting cost allocations", "err", err, "before", before)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// CapacityNodeSnapshot is the resource usage of a node at the time of a capacity collection, cpu is in millicores and
+// memory in bytes
+type CapacityNodeSnapshot struct {
+	tableName         struct{}  `sql:"capacity_node_snapshot" pg:",discard_unknown_columns"`
+	Id                int       `sql:"id,pk"`
+	ClusterId         int       `sql:"cluster_id"`
+	NodeName          string    `sql:"node_name"`
+	PodCount          int       `sql:"pod_count,notnull"`
+	CpuAllocatable    int64     `sql:"cpu_allocatable,notnull"`
+	CpuRequests       int64     `sql:"cpu_requests,notnull"`
+	CpuLimits         int64     `sql:"cpu_limits,notnull"`
+	CpuUsage          int64     `sql:"cpu_usage,notnull"`
+	MemoryAllocatable int64     `sql:"memory_allocatable,notnull"`
+	MemoryRequests    int64     `sql:"memory_requests,notnull"`
+	MemoryLimits      int64     `sql:"memory_limits,notnull"`
+	MemoryUsage       int64     `sql:"memory_usage,notnull"`
+	CapturedOn        time.Time `sql:"captured_on,type:timestamptz"`
+}
+
+// CapacityWorkloadSnapshot is the resource usage of the pods of a workload at the time of a capacity collection.
+// Requests and limits are per pod, usage is the average over the pods and usage max the usage of the busiest pod.
+type CapacityWorkloadSnapshot struct {
+	tableName      struct{}  `sql:"capacity_workload_snapshot" pg:",discard_unknown_columns"`
+	Id             int       `sql:"id,pk"`
+	ClusterId      int       `sql:"cluster_id"`
+	Namespace      string    `sql:"namespace"`
+	Kind           string    `sql:"kind"`
+	Name           string    `sql:"name"`
+	AppId          int       `sql:"app_id,notnull"`
+	EnvId          int       `sql:"env_id,notnull"`
+	PodCount       int       `sql:"pod_count,notnull"`
+	ContainerCount int       `sql:"container_count,notnull"`
+	CpuRequests    int64     `sql:"cpu_requests,notnull"`
+	CpuLimits      int64     `sql:"cpu_limits,notnull"`
+	CpuUsage       int64     `sql:"cpu_usage,notnull"`
+	CpuUsageMax    int64     `sql:"cpu_usage_max,notnull"`
+	MemoryRequests int64     `sql:"memory_requests,notnull"`
+	MemoryLimits   int64     `sql:"memory_limits,notnull"`
+	MemoryUsage    int64     `sql:"memory_usage,notnull"`
+	MemoryUsageMax int64     `sql:"memory_usage_max,notnull"`
+	CapturedOn     time.Time `sql:"captured_on,type:timestamptz"`
+}
+
+type CapacitySnapshotRepository interface {
+	SaveNodeSnapshots(snapshots []*CapacityNodeSnapshot) error
+	SaveWorkloadSnapshots(snapshots []*CapacityWorkloadSnapshot) error
+	FindNodeSnapshots(clusterId int, nodeName string, from, to time.Time) ([]*CapacityNodeSnapshot, error)
+	FindWorkloadSnapshotsByAppAndEnv(appId, envId int, from time.Time) ([]*CapacityWorkloadSnapshot, error)
+	DeleteSnapshotsBefore(before time.Time) error
+}
+
+type CapacitySnapshotRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewCapacitySnapshotRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *CapacitySnapshotRepositoryImpl {
+	return &CapacitySnapshotRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *CapacitySnapshotRepositoryImpl) SaveNodeSnapshots(snapshots []*CapacityNodeSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	err := impl.dbConnection.Insert(&snapshots)
+	if err != nil {
+		impl.logger.Errorw("error in saving capacity node snapshots", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *CapacitySnapshotRepositoryImpl) SaveWorkloadSnapshots(snapshots []*CapacityWorkloadSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	err := impl.dbConnection.Insert(&snapshots)
+	if err != nil {
+		impl.logger.Errorw("error in saving capacity workload snapshots", "err", err)
+		return err
+	}
+	return nil
+}
+
+// FindNodeSnapshots returns the snapshots of all nodes of the cluster when nodeName is empty
+func (impl *CapacitySnapshotRepositoryImpl) FindNodeSnapshots(clusterId int, nodeName string, from, to time.Time) ([]*CapacityNodeSnapshot, error) {
+	var snapshots []*CapacityNodeSnapshot
+	query := impl.dbConnection.Model(&snapshots).
+		Where("cluster_id = ?", clusterId).
+		Where("captured_on >= ?", from).
+		Where("captured_on <= ?", to)
+	if len(nodeName) > 0 {
+		query = query.Where("node_name = ?", nodeName)
+	}
+	err := query.Order("captured_on ASC").Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting capacity node snapshots", "err", err, "clusterId", clusterId, "nodeName", nodeName)
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+func (impl *CapacitySnapshotRepositoryImpl) FindWorkloadSnapshotsByAppAndEnv(appId, envId int, from time.Time) ([]*CapacityWorkloadSnapshot, error) {
+	var snapshots []*CapacityWorkloadSnapshot
+	err := impl.dbConnection.Model(&snapshots).
+		Where("app_id = ?", appId).
+		Where("env_id = ?", envId).
+		Where("captured_on >= ?", from).
+		Order("captured_on ASC").Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting capacity workload snapshots", "err", err, "appId", appId, "envId", envId)
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+func (impl *CapacitySnapshotRepositoryImpl) DeleteSnapshotsBefore(before time.Time) error {
+	var nodeSnapshot *CapacityNodeSnapshot
+	_, err := impl.dbConnection.Model(nodeSnapshot).
+		Where("captured_on < ?", before).Delete()
+	if err != nil {
+		impl.logger.Errorw("error in deleting capacity node snapshots", "err", err, "before", before)
+		return err
+	}
+	var workloadSnapshot *CapacityWorkloadSnapshot
+	_, err = impl.dbConnection.Model(workloadSnapshot).
+		Where("captured_on < ?", before).Delete()
+	if err != nil {
+		impl.logger.Errorw("error in deleting capacity workload snapshots", "err", err, "before", before)
+		return err
+	}
+	return nil
+}
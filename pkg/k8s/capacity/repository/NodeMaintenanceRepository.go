@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// NodeMaintenanceJob cordons and drains the nodes of a node group or label selector of a cluster one at a time,
+// DrainOptions is the json of the drain options of the request. A running job is run by the instance OwnerId, which
+// keeps HeartbeatOn current while the run lasts.
+type NodeMaintenanceJob struct {
+	tableName         struct{}  `sql:"node_maintenance_job" pg:",discard_unknown_columns"`
+	Id                int       `sql:"id,pk"`
+	ClusterId         int       `sql:"cluster_id,notnull"`
+	NodeGroup         string    `sql:"node_group,notnull"`
+	LabelSelector     string    `sql:"label_selector,notnull"`
+	DrainOptions      string    `sql:"drain_options"`
+	MaxRetries        int       `sql:"max_retries,notnull"`
+	RetryIntervalSecs int       `sql:"retry_interval_secs,notnull"`
+	HealthTimeoutMins int       `sql:"health_timeout_mins,notnull"`
+	Status            string    `sql:"status,notnull"`
+	Message           string    `sql:"message"`
+	StartedOn         time.Time `sql:"started_on,type:timestamptz"`
+	FinishedOn        time.Time `sql:"finished_on,type:timestamptz"`
+	OwnerId           string    `sql:"owner_id"`
+	HeartbeatOn       time.Time `sql:"heartbeat_on,type:timestamptz"`
+	sql.AuditLog
+}
+
+type NodeMaintenanceNode struct {
+	tableName  struct{}  `sql:"node_maintenance_node" pg:",discard_unknown_columns"`
+	Id         int       `sql:"id,pk"`
+	JobId      int       `sql:"job_id,notnull"`
+	NodeName   string    `sql:"node_name,notnull"`
+	Status     string    `sql:"status,notnull"`
+	Attempts   int       `sql:"attempts,notnull"`
+	PodCount   int       `sql:"pod_count,notnull"`
+	Message    string    `sql:"message"`
+	StartedOn  time.Time `sql:"started_on,type:timestamptz"`
+	FinishedOn time.Time `sql:"finished_on,type:timestamptz"`
+}
+
+type NodeMaintenanceRepository interface {
+	SaveJob(job *NodeMaintenanceJob, nodes []*NodeMaintenanceNode) error
+	UpdateJob(job *NodeMaintenanceJob) error
+	UpdateNode(node *NodeMaintenanceNode) error
+	FindJobById(id int) (*NodeMaintenanceJob, error)
+	FindJobsByClusterId(clusterId int) ([]*NodeMaintenanceJob, error)
+	FindJobsByStatus(statuses []string) ([]*NodeMaintenanceJob, error)
+	FindNodesByJobId(jobId int) ([]*NodeMaintenanceNode, error)
+	// UpdateHeartbeat marks the jobs being run by the owner as alive
+	UpdateHeartbeat(ownerId string, jobIds []int, heartbeatOn time.Time) error
+	// UpdateStaleJobsStatus moves the jobs in status whose owner has not sent a heartbeat since heartbeatBefore to
+	// newStatus, returns the ids of the jobs moved
+	UpdateStaleJobsStatus(status string, heartbeatBefore time.Time, newStatus string, message string) ([]int, error)
+}
+
+type NodeMaintenanceRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewNodeMaintenanceRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *NodeMaintenanceRepositoryImpl {
+	return &NodeMaintenanceRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) SaveJob(job *NodeMaintenanceJob, nodes []*NodeMaintenanceNode) error {
+	tx, err := impl.dbConnection.Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	err = tx.Insert(job)
+	if err != nil {
+		impl.logger.Errorw("error in saving node maintenance job", "err", err, "clusterId", job.ClusterId)
+		return err
+	}
+	for _, node := range nodes {
+		node.JobId = job.Id
+	}
+	if len(nodes) > 0 {
+		err = tx.Insert(&nodes)
+		if err != nil {
+			impl.logger.Errorw("error in saving node maintenance nodes", "err", err, "jobId", job.Id)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) UpdateJob(job *NodeMaintenanceJob) error {
+	// heartbeat_on is only written by UpdateHeartbeat, a job read before the last heartbeat must not move it back
+	_, err := impl.dbConnection.Model(job).WherePK().ExcludeColumn("heartbeat_on").Update()
+	if err != nil {
+		impl.logger.Errorw("error in updating node maintenance job", "err", err, "id", job.Id)
+		return err
+	}
+	return nil
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) UpdateNode(node *NodeMaintenanceNode) error {
+	err := impl.dbConnection.Update(node)
+	if err != nil {
+		impl.logger.Errorw("error in updating node maintenance node", "err", err, "id", node.Id)
+		return err
+	}
+	return nil
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) FindJobById(id int) (*NodeMaintenanceJob, error) {
+	job := &NodeMaintenanceJob{}
+	err := impl.dbConnection.Model(job).Where("id = ?", id).Select()
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) FindJobsByClusterId(clusterId int) ([]*NodeMaintenanceJob, error) {
+	var jobs []*NodeMaintenanceJob
+	err := impl.dbConnection.Model(&jobs).
+		Where("cluster_id = ?", clusterId).
+		Order("id DESC").Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting node maintenance jobs", "err", err, "clusterId", clusterId)
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) FindJobsByStatus(statuses []string) ([]*NodeMaintenanceJob, error) {
+	var jobs []*NodeMaintenanceJob
+	err := impl.dbConnection.Model(&jobs).
+		Where("status in (?)", pg.In(statuses)).Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting node maintenance jobs", "err", err, "statuses", statuses)
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) FindNodesByJobId(jobId int) ([]*NodeMaintenanceNode, error) {
+	var nodes []*NodeMaintenanceNode
+	err := impl.dbConnection.Model(&nodes).
+		Where("job_id = ?", jobId).
+		Order("id ASC").Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting node maintenance nodes", "err", err, "jobId", jobId)
+		return nil, err
+	}
+	return nodes, nil
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) UpdateHeartbeat(ownerId string, jobIds []int, heartbeatOn time.Time) error {
+	if len(jobIds) == 0 {
+		return nil
+	}
+	_, err := impl.dbConnection.Model((*NodeMaintenanceJob)(nil)).
+		Set("heartbeat_on = ?", heartbeatOn).
+		Where("id in (?)", pg.In(jobIds)).
+		Where("owner_id = ?", ownerId).
+		Update()
+	if err != nil {
+		impl.logger.Errorw("error in updating node maintenance job heartbeat", "err", err, "ownerId", ownerId, "jobIds", jobIds)
+		return err
+	}
+	return nil
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) UpdateStaleJobsStatus(status string, heartbeatBefore time.Time, newStatus string, message string) ([]int, error) {
+	var jobIds []int
+	_, err := impl.dbConnection.Query(pg.Scan(pg.Array(&jobIds)),
+		"WITH updated AS (UPDATE node_maintenance_job SET status = ?, message = ?, updated_on = ?"+
+			" WHERE status = ? AND (heartbeat_on IS NULL OR heartbeat_on < ?) RETURNING id)"+
+			" SELECT COALESCE(array_agg(id), '{}') FROM updated", newStatus, message, time.Now(), status, heartbeatBefore)
+	if err != nil {
+		impl.logger.Errorw("error in updating status of stale node maintenance jobs", "err", err, "status", status)
+		return nil, err
+	}
+	return jobIds, nil
+}
@@ -1,7 +1,13 @@
 package informer
 
 import (
+	"fmt"
+	"github.com/caarlos0/env/v6"
 	"github.com/devtron-labs/devtron/util/k8s"
+	"github.com/robfig/cron/v3"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sync"
 	"time"
 
@@ -13,6 +19,60 @@ import (
 	"k8s.io/client-go/tools/cache"
 )
 
+type searchableResource struct {
+	gvr        schema.GroupVersionResource
+	namespaced bool
+}
+
+// searchableResources are the kinds which can be searched across clusters by kind name
+var searchableResources = map[string]searchableResource{
+	"Pod":                   {gvr: schema.GroupVersionResource{Version: "v1", Resource: "pods"}, namespaced: true},
+	"Service":               {gvr: schema.GroupVersionResource{Version: "v1", Resource: "services"}, namespaced: true},
+	"ConfigMap":             {gvr: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, namespaced: true},
+	"PersistentVolumeClaim": {gvr: schema.GroupVersionResource{Version: "v1", Resource: "persistentvolumeclaims"}, namespaced: true},
+	"Deployment":            {gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, namespaced: true},
+	"StatefulSet":           {gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}, namespaced: true},
+	"DaemonSet":             {gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}, namespaced: true},
+	"ReplicaSet":            {gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}, namespaced: true},
+	"Job":                   {gvr: schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}, namespaced: true},
+	"CronJob":               {gvr: schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}, namespaced: true},
+	"Ingress":               {gvr: schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}, namespaced: true},
+	"Node":                  {gvr: schema.GroupVersionResource{Version: "v1", Resource: "nodes"}},
+	"Namespace":             {gvr: schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}},
+}
+
+// GetSearchableKinds returns the kinds which ListSearchableResources supports
+func GetSearchableKinds() []string {
+	kinds := make([]string, 0, len(searchableResources))
+	for kind := range searchableResources {
+		kinds = append(kinds, kind)
+	}
+	return kinds
+}
+
+// GetSearchableResource returns the group version resource of a kind which ListSearchableResources supports
+func GetSearchableResource(kind string) (schema.GroupVersionResource, bool) {
+	resource, ok := searchableResources[kind]
+	return resource.gvr, ok
+}
+
+// searchInformers are the informers of a cluster used for search, an informer of a kind is only started on the first
+// search for that kind so that clusters and kinds nobody searches are not cached
+type searchInformers struct {
+	factory   kubeinformers.SharedInformerFactory
+	stopper   chan struct{}
+	informers map[string]kubeinformers.GenericInformer
+	// watchers are the names of the watchers whose handlers are registered on the informers
+	watchers map[string]bool
+	lastUsed time.Time
+}
+
+type K8sInformerFactoryConfig struct {
+	// SearchInformerIdleMins is the time after which the search informers of a cluster which is not searched are stopped,
+	// informers with watchers registered keep running
+	SearchInformerIdleMins int `env:"K8S_RESOURCE_SEARCH_INFORMER_IDLE_MINS" envDefault:"30"`
+}
+
 func NewGlobalMapClusterNamespace() map[string]map[string]bool {
 	globalMapClusterNamespace := make(map[string]map[string]bool)
 	return globalMapClusterNamespace
@@ -25,23 +85,49 @@ type K8sInformerFactoryImpl struct {
 	informerStopper           map[string]chan struct{}
 	runtimeConfig             *client.RuntimeConfig
 	k8sUtil                   *k8s.K8sUtil
+	searchInformers           map[string]*searchInformers
+	searchMutex               sync.Mutex
+	config                    *K8sInformerFactoryConfig
 }
 
 type K8sInformerFactory interface {
 	GetLatestNamespaceListGroupByCLuster() map[string]map[string]bool
 	BuildInformer(clusterInfo []*bean.ClusterInfo)
 	CleanNamespaceInformer(clusterName string)
+	// ListSearchableResources lists the objects of the kinds in the cluster from the informer cache, all namespaces are
+	// listed when namespace is empty. Kinds whose cache has not synced within syncTimeout are returned as unsynced.
+	ListSearchableResources(clusterConfig *k8s.ClusterConfig, kinds []string, namespace string, selector labels.Selector, syncTimeout time.Duration) (map[string][]runtime.Object, []string, error)
+	// WatchSearchableResources registers the handler of the watcher on the informers of the kinds in the cluster, the
+	// handler is registered once per watcher and is registered again after the informers are rebuilt for the cluster
+	WatchSearchableResources(clusterConfig *k8s.ClusterConfig, kinds []string, watcherName string, handler cache.ResourceEventHandler) error
 }
 
-func NewK8sInformerFactoryImpl(logger *zap.SugaredLogger, globalMapClusterNamespace map[string]map[string]bool, runtimeConfig *client.RuntimeConfig, k8sUtil *k8s.K8sUtil) *K8sInformerFactoryImpl {
+func NewK8sInformerFactoryImpl(logger *zap.SugaredLogger, globalMapClusterNamespace map[string]map[string]bool, runtimeConfig *client.RuntimeConfig, k8sUtil *k8s.K8sUtil) (*K8sInformerFactoryImpl, error) {
+	config := &K8sInformerFactoryConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing K8sInformerFactoryConfig from env", "err", err)
+		return nil, err
+	}
 	informerFactory := &K8sInformerFactoryImpl{
 		logger:                    logger,
 		globalMapClusterNamespace: globalMapClusterNamespace,
 		runtimeConfig:             runtimeConfig,
 		k8sUtil:                   k8sUtil,
+		config:                    config,
 	}
 	informerFactory.informerStopper = make(map[string]chan struct{})
-	return informerFactory
+	informerFactory.searchInformers = make(map[string]*searchInformers)
+	if config.SearchInformerIdleMins > 0 {
+		idleCron := cron.New(cron.WithChain())
+		idleCron.Start()
+		_, err = idleCron.AddFunc("@every 1m", informerFactory.stopIdleSearchInformers)
+		if err != nil {
+			logger.Errorw("error in adding idle search informer cron", "err", err)
+			return nil, err
+		}
+	}
+	return informerFactory, nil
 }
 
 func (impl *K8sInformerFactoryImpl) GetLatestNamespaceListGroupByCLuster() map[string]map[string]bool {
@@ -130,5 +216,118 @@ func (impl *K8sInformerFactoryImpl) CleanNamespaceInformer(clusterName string) {
 		close(stopper)
 		delete(impl.informerStopper, clusterName)
 	}
+	// search informers are started again with the new config on the next search
+	impl.searchMutex.Lock()
+	if clusterSearchInformers, ok := impl.searchInformers[clusterName]; ok {
+		close(clusterSearchInformers.stopper)
+		delete(impl.searchInformers, clusterName)
+	}
+	impl.searchMutex.Unlock()
 	return
 }
+
+func (impl *K8sInformerFactoryImpl) ListSearchableResources(clusterConfig *k8s.ClusterConfig, kinds []string, namespace string, selector labels.Selector, syncTimeout time.Duration) (map[string][]runtime.Object, []string, error) {
+	informers, err := impl.getSearchInformers(clusterConfig, kinds)
+	if err != nil {
+		return nil, nil, err
+	}
+	deadline := make(chan struct{})
+	timer := time.AfterFunc(syncTimeout, func() { close(deadline) })
+	defer timer.Stop()
+	objects := make(map[string][]runtime.Object)
+	var unsynced []string
+	for _, kind := range kinds {
+		informer := informers[kind]
+		if !cache.WaitForCacheSync(deadline, informer.Informer().HasSynced) {
+			unsynced = append(unsynced, kind)
+			continue
+		}
+		var list []runtime.Object
+		if len(namespace) > 0 && searchableResources[kind].namespaced {
+			list, err = informer.Lister().ByNamespace(namespace).List(selector)
+		} else if len(namespace) == 0 {
+			list, err = informer.Lister().List(selector)
+		}
+		if err != nil {
+			impl.logger.Errorw("error in listing resources from informer", "err", err, "clusterName", clusterConfig.ClusterName, "kind", kind)
+			return nil, nil, err
+		}
+		objects[kind] = list
+	}
+	return objects, unsynced, nil
+}
+
+func (impl *K8sInformerFactoryImpl) WatchSearchableResources(clusterConfig *k8s.ClusterConfig, kinds []string, watcherName string, handler cache.ResourceEventHandler) error {
+	informers, err := impl.getSearchInformers(clusterConfig, kinds)
+	if err != nil {
+		return err
+	}
+	impl.searchMutex.Lock()
+	defer impl.searchMutex.Unlock()
+	clusterSearchInformers, ok := impl.searchInformers[clusterConfig.ClusterName]
+	if !ok || clusterSearchInformers.watchers[watcherName] {
+		return nil
+	}
+	for _, informer := range informers {
+		informer.Informer().AddEventHandler(handler)
+	}
+	clusterSearchInformers.watchers[watcherName] = true
+	return nil
+}
+
+func (impl *K8sInformerFactoryImpl) getSearchInformers(clusterConfig *k8s.ClusterConfig, kinds []string) (map[string]kubeinformers.GenericInformer, error) {
+	impl.searchMutex.Lock()
+	defer impl.searchMutex.Unlock()
+	clusterSearchInformers, ok := impl.searchInformers[clusterConfig.ClusterName]
+	if !ok {
+		_, _, clusterClient, err := impl.k8sUtil.GetK8sConfigAndClients(clusterConfig)
+		if err != nil {
+			impl.logger.Errorw("error in getting k8s clientset", "err", err, "clusterName", clusterConfig.ClusterName)
+			return nil, err
+		}
+		clusterSearchInformers = &searchInformers{
+			factory:   kubeinformers.NewSharedInformerFactoryWithOptions(clusterClient, 10*time.Minute),
+			stopper:   make(chan struct{}),
+			informers: make(map[string]kubeinformers.GenericInformer),
+			watchers:  make(map[string]bool),
+		}
+		impl.searchInformers[clusterConfig.ClusterName] = clusterSearchInformers
+	}
+	clusterSearchInformers.lastUsed = time.Now()
+	informers := make(map[string]kubeinformers.GenericInformer, len(kinds))
+	for _, kind := range kinds {
+		resource, ok := searchableResources[kind]
+		if !ok {
+			return nil, fmt.Errorf("kind %s is not searchable", kind)
+		}
+		informer, ok := clusterSearchInformers.informers[kind]
+		if !ok {
+			var err error
+			informer, err = clusterSearchInformers.factory.ForResource(resource.gvr)
+			if err != nil {
+				return nil, err
+			}
+			clusterSearchInformers.informers[kind] = informer
+		}
+		informers[kind] = informer
+	}
+	// only starts the informers which are not running yet
+	clusterSearchInformers.factory.Start(clusterSearchInformers.stopper)
+	return informers, nil
+}
+
+// stopIdleSearchInformers stops the search informers of the clusters which are not searched within the idle time so
+// that the caches of a cluster searched once are not kept, they are started again on the next search
+func (impl *K8sInformerFactoryImpl) stopIdleSearchInformers() {
+	idleSince := time.Now().Add(-time.Duration(impl.config.SearchInformerIdleMins) * time.Minute)
+	impl.searchMutex.Lock()
+	defer impl.searchMutex.Unlock()
+	for clusterName, clusterSearchInformers := range impl.searchInformers {
+		if len(clusterSearchInformers.watchers) > 0 || clusterSearchInformers.lastUsed.After(idleSince) {
+			continue
+		}
+		close(clusterSearchInformers.stopper)
+		delete(impl.searchInformers, clusterName)
+		impl.logger.Infow("stopped idle search informers", "clusterName", clusterName)
+	}
+}
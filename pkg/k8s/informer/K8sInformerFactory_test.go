@@ -0,0 +1,29 @@
+package informer
+
+import (
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestStopIdleSearchInformers(t *testing.T) {
+	logger, _ := util.NewSugardLogger()
+	impl := &K8sInformerFactoryImpl{
+		logger:          logger,
+		searchInformers: make(map[string]*searchInformers),
+		config:          &K8sInformerFactoryConfig{SearchInformerIdleMins: 30},
+	}
+	idle := &searchInformers{stopper: make(chan struct{}), lastUsed: time.Now().Add(-time.Hour)}
+	watched := &searchInformers{stopper: make(chan struct{}), lastUsed: time.Now().Add(-time.Hour), watchers: map[string]bool{"watcher": true}}
+	recent := &searchInformers{stopper: make(chan struct{}), lastUsed: time.Now()}
+	impl.searchInformers["idle"], impl.searchInformers["watched"], impl.searchInformers["recent"] = idle, watched, recent
+
+	impl.stopIdleSearchInformers()
+
+	assert.NotContains(t, impl.searchInformers, "idle")
+	assert.Contains(t, impl.searchInformers, "watched")
+	assert.Contains(t, impl.searchInformers, "recent")
+	_, open := <-idle.stopper
+	assert.False(t, open)
+}
@@ -0,0 +1,458 @@
+package kubernetesResourceAuditLogs
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env/v6"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	repository2 "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/k8s/informer"
+	"github.com/devtron-labs/devtron/pkg/kubernetesResourceAuditLogs/repository"
+	"github.com/devtron-labs/devtron/util/k8s"
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/cache"
+	"sync"
+	"time"
+)
+
+const (
+	ResourceChangeCreate string = "create"
+	ResourceChangeUpdate string = "update"
+	ResourceChangeDelete string = "delete"
+
+	resourceChangeWatcherName = "resource-change-history"
+	// devtronActionWindow is how long before a change an action performed from devtron is attributed to it
+	devtronActionWindow         = 2 * time.Minute
+	lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+	maxResourceChanges          = 1000
+	// resourceChangeSaveBatchSize is the most changes saved by one insert
+	resourceChangeSaveBatchSize = 100
+)
+
+type K8sResourceChangeConfig struct {
+	WatcherEnabled bool `env:"K8S_CHANGE_WATCHER_ENABLED" envDefault:"false"`
+	// Kinds are the namespaced kinds whose changes are recorded in the namespaces of devtron environments
+	Kinds               []string `env:"K8S_CHANGE_WATCHER_KINDS" envDefault:"Deployment,StatefulSet,DaemonSet,CronJob,Service,Ingress,ConfigMap" envSeparator:","`
+	RefreshIntervalMins int      `env:"K8S_CHANGE_WATCHER_REFRESH_INTERVAL_MINS" envDefault:"5"`
+	RetentionDays       int      `env:"K8S_CHANGE_HISTORY_RETENTION_DAYS" envDefault:"30"`
+	// QueueSize is the number of changes waiting to be saved, changes observed while the queue is full are dropped
+	QueueSize int `env:"K8S_CHANGE_WATCHER_QUEUE_SIZE" envDefault:"10000"`
+}
+
+type ResourceChangeRequest struct {
+	ClusterId int
+	Namespace string
+	Kind      string
+	Name      string
+	From      time.Time
+	To        time.Time
+}
+
+type ResourceChange struct {
+	Id              int             `json:"id"`
+	ClusterId       int             `json:"clusterId"`
+	Namespace       string          `json:"namespace"`
+	Group           string          `json:"group"`
+	Version         string          `json:"version"`
+	Kind            string          `json:"kind"`
+	Name            string          `json:"name"`
+	Action          string          `json:"action"`
+	Diff            json.RawMessage `json:"diff,omitempty"`
+	FieldManager    string          `json:"fieldManager,omitempty"`
+	UserId          int32           `json:"userId,omitempty"`
+	ResourceVersion string          `json:"resourceVersion,omitempty"`
+	ChangedOn       time.Time       `json:"changedOn"`
+}
+
+type K8sResourceChangeService interface {
+	// RefreshWatchers watches the clusters having devtron environments and refreshes the namespaces being recorded
+	RefreshWatchers()
+	GetResourceChanges(request *ResourceChangeRequest, validateResourceAccess func(clusterName string, resourceIdentifier k8s.ResourceIdentifier) bool) ([]*ResourceChange, error)
+}
+
+type K8sResourceChangeServiceImpl struct {
+	logger                       *zap.SugaredLogger
+	clusterService               cluster.ClusterService
+	envRepository                repository2.EnvironmentRepository
+	k8sInformerFactory           informer.K8sInformerFactory
+	k8sResourceChangeRepository  repository.K8sResourceChangeRepository
+	k8sResourceHistoryRepository repository.K8sResourceHistoryRepository
+	config                       *K8sResourceChangeConfig
+	// namespaces are the namespaces of devtron environments by cluster id
+	namespaces     map[int]map[string]bool
+	namespaceMutex sync.RWMutex
+	// changes are saved by saveChanges so that the informer handlers never wait on the database
+	changes chan *repository.K8sResourceChange
+}
+
+func NewK8sResourceChangeServiceImpl(logger *zap.SugaredLogger, clusterService cluster.ClusterService,
+	envRepository repository2.EnvironmentRepository, k8sInformerFactory informer.K8sInformerFactory,
+	k8sResourceChangeRepository repository.K8sResourceChangeRepository,
+	k8sResourceHistoryRepository repository.K8sResourceHistoryRepository) (*K8sResourceChangeServiceImpl, error) {
+	config := &K8sResourceChangeConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing K8sResourceChangeConfig from env", "err", err)
+		return nil, err
+	}
+	for _, kind := range config.Kinds {
+		if _, ok := informer.GetSearchableResource(kind); !ok {
+			return nil, fmt.Errorf("kind %s of K8S_CHANGE_WATCHER_KINDS cannot be watched", kind)
+		}
+	}
+	serviceImpl := &K8sResourceChangeServiceImpl{
+		logger:                       logger,
+		clusterService:               clusterService,
+		envRepository:                envRepository,
+		k8sInformerFactory:           k8sInformerFactory,
+		k8sResourceChangeRepository:  k8sResourceChangeRepository,
+		k8sResourceHistoryRepository: k8sResourceHistoryRepository,
+		config:                       config,
+		namespaces:                   make(map[int]map[string]bool),
+		changes:                      make(chan *repository.K8sResourceChange, config.QueueSize),
+	}
+	if config.WatcherEnabled {
+		go serviceImpl.saveChanges()
+		watcherCron := cron.New(cron.WithChain())
+		watcherCron.Start()
+		_, err = watcherCron.AddFunc(fmt.Sprintf("@every %dm", config.RefreshIntervalMins), serviceImpl.RefreshWatchers)
+		if err != nil {
+			logger.Errorw("error in adding resource change watcher cron", "err", err, "intervalMins", config.RefreshIntervalMins)
+			return nil, err
+		}
+		_, err = watcherCron.AddFunc("@daily", serviceImpl.deleteExpiredChanges)
+		if err != nil {
+			logger.Errorw("error in adding resource change retention cron", "err", err)
+			return nil, err
+		}
+		go serviceImpl.RefreshWatchers()
+	}
+	return serviceImpl, nil
+}
+
+func (impl *K8sResourceChangeServiceImpl) RefreshWatchers() {
+	environments, err := impl.envRepository.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error in getting environments for resource change watcher", "err", err)
+		return
+	}
+	namespaces := make(map[int]map[string]bool)
+	for _, environment := range environments {
+		if environment.IsVirtualEnvironment || len(environment.Namespace) == 0 {
+			continue
+		}
+		if _, ok := namespaces[environment.ClusterId]; !ok {
+			namespaces[environment.ClusterId] = make(map[string]bool)
+		}
+		namespaces[environment.ClusterId][environment.Namespace] = true
+	}
+	impl.namespaceMutex.Lock()
+	impl.namespaces = namespaces
+	impl.namespaceMutex.Unlock()
+
+	clusters, err := impl.clusterService.FindAll()
+	if err != nil {
+		impl.logger.Errorw("error in getting clusters for resource change watcher", "err", err)
+		return
+	}
+	for _, clusterBean := range clusters {
+		if clusterBean.IsVirtualCluster || len(clusterBean.ErrorInConnecting) > 0 || len(namespaces[clusterBean.Id]) == 0 {
+			continue
+		}
+		clusterConfig, err := clusterBean.GetClusterConfig()
+		if err != nil {
+			impl.logger.Errorw("error in getting cluster config", "err", err, "clusterId", clusterBean.Id)
+			continue
+		}
+		clusterConfig.ClusterName = clusterBean.ClusterName
+		err = impl.k8sInformerFactory.WatchSearchableResources(clusterConfig, impl.config.Kinds, resourceChangeWatcherName, impl.getEventHandler(clusterBean.Id))
+		if err != nil {
+			impl.logger.Errorw("error in watching resources of cluster", "err", err, "clusterId", clusterBean.Id)
+		}
+	}
+}
+
+func (impl *K8sResourceChangeServiceImpl) getEventHandler(clusterId int) cache.ResourceEventHandler {
+	// existing objects are replayed as adds when the handler is registered, they are not created by then. creation
+	// timestamps only have second precision
+	registeredOn := time.Now().Truncate(time.Second)
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			object, ok := obj.(runtime.Object)
+			if !ok {
+				return
+			}
+			objectMeta, err := meta.Accessor(object)
+			if err != nil || objectMeta.GetCreationTimestamp().Time.Before(registeredOn) {
+				return
+			}
+			impl.recordChange(clusterId, ResourceChangeCreate, nil, object)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldObject, ok := oldObj.(runtime.Object)
+			if !ok {
+				return
+			}
+			newObject, ok := newObj.(runtime.Object)
+			if !ok {
+				return
+			}
+			impl.recordChange(clusterId, ResourceChangeUpdate, oldObject, newObject)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if deletedState, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = deletedState.Obj
+			}
+			object, ok := obj.(runtime.Object)
+			if !ok {
+				return
+			}
+			impl.recordChange(clusterId, ResourceChangeDelete, object, nil)
+		},
+	}
+}
+
+// recordChange queues the change of the object if it is in the namespace of a devtron environment, updates which only
+// change the status or bookkeeping metadata are ignored
+func (impl *K8sResourceChangeServiceImpl) recordChange(clusterId int, action string, oldObject, newObject runtime.Object) {
+	object := newObject
+	if object == nil {
+		object = oldObject
+	}
+	objectMeta, err := meta.Accessor(object)
+	if err != nil {
+		return
+	}
+	impl.namespaceMutex.RLock()
+	watched := impl.namespaces[clusterId][objectMeta.GetNamespace()]
+	impl.namespaceMutex.RUnlock()
+	if !watched {
+		return
+	}
+	gvk, err := getObjectGroupVersionKind(object)
+	if err != nil {
+		impl.logger.Errorw("error in getting kind of watched object", "err", err, "name", objectMeta.GetName())
+		return
+	}
+	change := &repository.K8sResourceChange{
+		ClusterId:       clusterId,
+		Namespace:       objectMeta.GetNamespace(),
+		Group:           gvk.Group,
+		Version:         gvk.Version,
+		Kind:            gvk.Kind,
+		ResourceName:    objectMeta.GetName(),
+		Action:          action,
+		ResourceUid:     string(objectMeta.GetUID()),
+		ResourceVersion: objectMeta.GetResourceVersion(),
+		ChangedOn:       time.Now(),
+	}
+	switch action {
+	case ResourceChangeCreate:
+		change.Diff, err = getResourceJson(newObject)
+		change.FieldManager = getLatestFieldManager(objectMeta.GetManagedFields())
+	case ResourceChangeUpdate:
+		if oldObjectMeta, err := meta.Accessor(oldObject); err != nil || oldObjectMeta.GetResourceVersion() == objectMeta.GetResourceVersion() {
+			// periodic resync of the informer
+			return
+		}
+		change.Diff, err = getResourceDiff(oldObject, newObject)
+		change.FieldManager = getLatestFieldManager(objectMeta.GetManagedFields())
+	}
+	if err != nil {
+		impl.logger.Errorw("error in getting diff of watched object", "err", err, "kind", gvk.Kind, "name", change.ResourceName)
+		return
+	}
+	if action == ResourceChangeUpdate && len(change.Diff) == 0 {
+		return
+	}
+	select {
+	case impl.changes <- change:
+	default:
+		impl.logger.Errorw("resource change queue is full, dropping change", "kind", gvk.Kind, "name", change.ResourceName, "action", action)
+	}
+}
+
+// saveChanges saves the queued changes in batches of the changes queued by the time the previous batch is saved
+func (impl *K8sResourceChangeServiceImpl) saveChanges() {
+	for change := range impl.changes {
+		changes := []*repository.K8sResourceChange{change}
+	batch:
+		for len(changes) < resourceChangeSaveBatchSize {
+			select {
+			case change = <-impl.changes:
+				changes = append(changes, change)
+			default:
+				break batch
+			}
+		}
+		for _, change := range changes {
+			if change.Action == ResourceChangeDelete {
+				change.UserId = impl.getDevtronActionUserId(change)
+			}
+		}
+		err := impl.k8sResourceChangeRepository.SaveAll(changes)
+		if err != nil {
+			impl.logger.Errorw("error in saving k8s resource changes", "err", err, "count", len(changes))
+		}
+	}
+}
+
+// getDevtronActionUserId returns the user who performed the action on the resource from devtron just before the change
+func (impl *K8sResourceChangeServiceImpl) getDevtronActionUserId(change *repository.K8sResourceChange) int32 {
+	history, err := impl.k8sResourceHistoryRepository.FindLatestByResource(change.Namespace, change.Kind, change.ResourceName, change.Action, change.ChangedOn.Add(-devtronActionWindow))
+	if err != nil {
+		if err != pg.ErrNoRows {
+			impl.logger.Errorw("error in getting devtron action of resource", "err", err, "name", change.ResourceName)
+		}
+		return 0
+	}
+	return history.UpdatedBy
+}
+
+func (impl *K8sResourceChangeServiceImpl) GetResourceChanges(request *ResourceChangeRequest, validateResourceAccess func(clusterName string, resourceIdentifier k8s.ResourceIdentifier) bool) ([]*ResourceChange, error) {
+	clusterBean, err := impl.clusterService.FindById(request.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in getting cluster", "err", err, "clusterId", request.ClusterId)
+		return nil, err
+	}
+	changes, err := impl.k8sResourceChangeRepository.FindByFilter(&repository.K8sResourceChangeFilter{
+		ClusterId:    request.ClusterId,
+		Namespace:    request.Namespace,
+		Kind:         request.Kind,
+		ResourceName: request.Name,
+		From:         request.From,
+		To:           request.To,
+		Limit:        maxResourceChanges,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resourceChanges := make([]*ResourceChange, 0, len(changes))
+	for _, change := range changes {
+		resourceIdentifier := k8s.ResourceIdentifier{
+			Name:             change.ResourceName,
+			Namespace:        change.Namespace,
+			GroupVersionKind: schema.GroupVersionKind{Group: change.Group, Version: change.Version, Kind: change.Kind},
+		}
+		if !validateResourceAccess(clusterBean.ClusterName, resourceIdentifier) {
+			continue
+		}
+		resourceChange := &ResourceChange{
+			Id:              change.Id,
+			ClusterId:       change.ClusterId,
+			Namespace:       change.Namespace,
+			Group:           change.Group,
+			Version:         change.Version,
+			Kind:            change.Kind,
+			Name:            change.ResourceName,
+			Action:          change.Action,
+			FieldManager:    change.FieldManager,
+			UserId:          change.UserId,
+			ResourceVersion: change.ResourceVersion,
+			ChangedOn:       change.ChangedOn,
+		}
+		if len(change.Diff) > 0 {
+			resourceChange.Diff = json.RawMessage(change.Diff)
+		}
+		resourceChanges = append(resourceChanges, resourceChange)
+	}
+	return resourceChanges, nil
+}
+
+func (impl *K8sResourceChangeServiceImpl) deleteExpiredChanges() {
+	err := impl.k8sResourceChangeRepository.DeleteChangesBefore(time.Now().AddDate(0, 0, -impl.config.RetentionDays))
+	if err != nil {
+		impl.logger.Errorw("error in deleting old k8s resource changes", "err", err)
+	}
+}
+
+// getObjectGroupVersionKind returns the kind of the typed objects of the informers, which do not have the type meta set
+func getObjectGroupVersionKind(object runtime.Object) (schema.GroupVersionKind, error) {
+	gvks, _, err := scheme.Scheme.ObjectKinds(object)
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	return gvks[0], nil
+}
+
+// getResourceJson returns the json of the object without its status and metadata other than labels and annotations
+func getResourceJson(object runtime.Object) (string, error) {
+	unstructuredObject, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+	if err != nil {
+		return "", err
+	}
+	resource := make(map[string]interface{}, len(unstructuredObject))
+	for key, value := range unstructuredObject {
+		if key != "status" && key != "apiVersion" && key != "kind" && key != "metadata" {
+			resource[key] = value
+		}
+	}
+	metadata := make(map[string]interface{})
+	if objectMeta, ok := unstructuredObject["metadata"].(map[string]interface{}); ok {
+		if labels, ok := objectMeta["labels"]; ok {
+			metadata["labels"] = labels
+		}
+		if annotations, ok := objectMeta["annotations"].(map[string]interface{}); ok {
+			filteredAnnotations := make(map[string]interface{}, len(annotations))
+			for key, value := range annotations {
+				if key != lastAppliedConfigAnnotation {
+					filteredAnnotations[key] = value
+				}
+			}
+			if len(filteredAnnotations) > 0 {
+				metadata["annotations"] = filteredAnnotations
+			}
+		}
+	}
+	resource["metadata"] = metadata
+	resourceJson, err := json.Marshal(resource)
+	if err != nil {
+		return "", err
+	}
+	return string(resourceJson), nil
+}
+
+// getResourceDiff returns the json merge patch from the old to the new object, empty when only the status or
+// bookkeeping metadata changed
+func getResourceDiff(oldObject, newObject runtime.Object) (string, error) {
+	oldJson, err := getResourceJson(oldObject)
+	if err != nil {
+		return "", err
+	}
+	newJson, err := getResourceJson(newObject)
+	if err != nil {
+		return "", err
+	}
+	patch, err := jsonpatch.CreateMergePatch([]byte(oldJson), []byte(newJson))
+	if err != nil {
+		return "", err
+	}
+	if string(patch) == "{}" {
+		return "", nil
+	}
+	return string(patch), nil
+}
+
+// getLatestFieldManager returns the manager which last updated the object, e.g. kubectl-edit or argocd-controller
+func getLatestFieldManager(managedFields []metav1.ManagedFieldsEntry) string {
+	var fieldManager string
+	var latest time.Time
+	for _, managedField := range managedFields {
+		if managedField.Time == nil || managedField.Subresource == "status" {
+			continue
+		}
+		if managedField.Time.Time.After(latest) || managedField.Time.Time.Equal(latest) {
+			latest = managedField.Time.Time
+			fieldManager = managedField.Manager
+		}
+	}
+	return fieldManager
+}
@@ -0,0 +1,99 @@
+package kubernetesResourceAuditLogs
+
+import (
+	"github.com/devtron-labs/devtron/pkg/kubernetesResourceAuditLogs/repository"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	appsV1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func newChangeTestDeployment(image string, replicas int32) *appsV1.Deployment {
+	deployment := &appsV1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "api",
+			Namespace:       "prod",
+			ResourceVersion: "100",
+			Labels:          map[string]string{"app": "api"},
+			Annotations:     map[string]string{lastAppliedConfigAnnotation: "{}"},
+		},
+		Spec: appsV1.DeploymentSpec{Replicas: &replicas},
+	}
+	deployment.Spec.Template.Spec.Containers = []corev1.Container{{Name: "api", Image: image}}
+	return deployment
+}
+
+func TestGetResourceDiff(t *testing.T) {
+	oldDeployment := newChangeTestDeployment("api:v1", 2)
+	newDeployment := newChangeTestDeployment("api:v1", 2)
+	newDeployment.ResourceVersion = "101"
+	newDeployment.Generation = 2
+	newDeployment.Status.ReadyReplicas = 2
+	newDeployment.Annotations[lastAppliedConfigAnnotation] = `{"spec":{}}`
+	diff, err := getResourceDiff(oldDeployment, newDeployment)
+	assert.NoError(t, err)
+	// status and bookkeeping metadata are not changes
+	assert.Empty(t, diff)
+
+	newDeployment.Spec.Replicas = nil
+	newDeployment.Spec.Template.Spec.Containers[0].Image = "api:v2"
+	newDeployment.Labels["version"] = "v2"
+	diff, err = getResourceDiff(oldDeployment, newDeployment)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"metadata":{"labels":{"version":"v2"}},"spec":{"replicas":null,"template":{"spec":{"containers":[{"name":"api","image":"api:v2","resources":{}}]}}}}`, diff)
+
+	resourceJson, err := getResourceJson(oldDeployment)
+	assert.NoError(t, err)
+	assert.NotContains(t, resourceJson, lastAppliedConfigAnnotation)
+	assert.NotContains(t, resourceJson, "resourceVersion")
+	assert.NotContains(t, resourceJson, "status")
+}
+
+func TestGetLatestFieldManager(t *testing.T) {
+	now := time.Now()
+	managedFields := []metav1.ManagedFieldsEntry{
+		{Manager: "argocd-controller", Operation: metav1.ManagedFieldsOperationApply, Time: &metav1.Time{Time: now.Add(-time.Hour)}},
+		{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, Time: &metav1.Time{Time: now.Add(-time.Minute)}},
+		{Manager: "kube-controller-manager", Operation: metav1.ManagedFieldsOperationUpdate, Subresource: "status", Time: &metav1.Time{Time: now}},
+	}
+	assert.Equal(t, "kubectl-edit", getLatestFieldManager(managedFields))
+	assert.Empty(t, getLatestFieldManager(nil))
+}
+
+func TestGetObjectGroupVersionKind(t *testing.T) {
+	gvk, err := getObjectGroupVersionKind(newChangeTestDeployment("api:v1", 1))
+	assert.NoError(t, err)
+	assert.Equal(t, "apps", gvk.Group)
+	assert.Equal(t, "v1", gvk.Version)
+	assert.Equal(t, "Deployment", gvk.Kind)
+}
+
+func TestRecordChangeQueuesChange(t *testing.T) {
+	impl := &K8sResourceChangeServiceImpl{
+		logger:     zap.NewNop().Sugar(),
+		namespaces: map[int]map[string]bool{1: {"prod": true}},
+		changes:    make(chan *repository.K8sResourceChange, 1),
+	}
+	oldDeployment := newChangeTestDeployment("api:v1", 2)
+	oldDeployment.UID = "3f1c"
+	newDeployment := newChangeTestDeployment("api:v2", 2)
+	newDeployment.UID = "3f1c"
+	newDeployment.ResourceVersion = "101"
+	impl.recordChange(1, ResourceChangeUpdate, oldDeployment, newDeployment)
+	if assert.Len(t, impl.changes, 1) {
+		change := <-impl.changes
+		assert.Equal(t, "3f1c", change.ResourceUid)
+		assert.Equal(t, "101", change.ResourceVersion)
+		assert.Equal(t, ResourceChangeUpdate, change.Action)
+	}
+
+	// changes of namespaces which are not watched are not queued, changes observed while the queue is full are dropped
+	impl.recordChange(2, ResourceChangeDelete, oldDeployment, nil)
+	assert.Len(t, impl.changes, 0)
+	impl.recordChange(1, ResourceChangeDelete, oldDeployment, nil)
+	impl.recordChange(1, ResourceChangeDelete, newDeployment, nil)
+	assert.Len(t, impl.changes, 1)
+}
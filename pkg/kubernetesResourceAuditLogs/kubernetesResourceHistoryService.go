@@ -1,9 +1,12 @@
 package kubernetesResourceAuditLogs
 
 import (
+	"context"
+	"fmt"
 	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
 	client "github.com/devtron-labs/devtron/api/helm-app"
 	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	repository2 "github.com/devtron-labs/devtron/pkg/cluster/repository"
 	"github.com/devtron-labs/devtron/pkg/kubernetesResourceAuditLogs/repository"
 	"github.com/devtron-labs/devtron/pkg/sql"
@@ -19,8 +22,8 @@ const (
 )
 
 type K8sResourceHistoryService interface {
-	SaveArgoCdAppsResourceDeleteHistory(query *application.ApplicationResourceDeleteRequest, appId int, envId int, userId int32) error
-	SaveHelmAppsResourceHistory(appIdentifier *client.AppIdentifier, k8sRequestBean *k8s.K8sRequestBean, userId int32, actionType string) error
+	SaveArgoCdAppsResourceDeleteHistory(ctx context.Context, query *application.ApplicationResourceDeleteRequest, appId int, envId int, userId int32) error
+	SaveHelmAppsResourceHistory(ctx context.Context, appIdentifier *client.AppIdentifier, k8sRequestBean *k8s.K8sRequestBean, userId int32, actionType string) error
 }
 
 type K8sResourceHistoryServiceImpl struct {
@@ -28,19 +31,22 @@ type K8sResourceHistoryServiceImpl struct {
 	K8sResourceHistoryRepository repository.K8sResourceHistoryRepository
 	logger                       *zap.SugaredLogger
 	envRepository                repository2.EnvironmentRepository
+	auditLogService              auditLog.AuditLogService
 }
 
 func Newk8sResourceHistoryServiceImpl(K8sResourceHistoryRepository repository.K8sResourceHistoryRepository,
-	logger *zap.SugaredLogger, appRepository app.AppRepository, envRepository repository2.EnvironmentRepository) *K8sResourceHistoryServiceImpl {
+	logger *zap.SugaredLogger, appRepository app.AppRepository, envRepository repository2.EnvironmentRepository,
+	auditLogService auditLog.AuditLogService) *K8sResourceHistoryServiceImpl {
 	return &K8sResourceHistoryServiceImpl{
 		K8sResourceHistoryRepository: K8sResourceHistoryRepository,
 		logger:                       logger,
 		appRepository:                appRepository,
 		envRepository:                envRepository,
+		auditLogService:              auditLogService,
 	}
 }
 
-func (impl K8sResourceHistoryServiceImpl) SaveArgoCdAppsResourceDeleteHistory(query *application.ApplicationResourceDeleteRequest, appId int, envId int, userId int32) error {
+func (impl K8sResourceHistoryServiceImpl) SaveArgoCdAppsResourceDeleteHistory(ctx context.Context, query *application.ApplicationResourceDeleteRequest, appId int, envId int, userId int32) error {
 
 	k8sResourceHistory := repository.K8sResourceHistory{
 		AppId:        appId,
@@ -64,12 +70,13 @@ func (impl K8sResourceHistoryServiceImpl) SaveArgoCdAppsResourceDeleteHistory(qu
 	if err != nil {
 		return err
 	}
+	impl.recordAuditEvent(ctx, &k8sResourceHistory)
 
 	return nil
 
 }
 
-func (impl K8sResourceHistoryServiceImpl) SaveHelmAppsResourceHistory(appIdentifier *client.AppIdentifier, k8sRequestBean *k8s.K8sRequestBean, userId int32, actionType string) error {
+func (impl K8sResourceHistoryServiceImpl) SaveHelmAppsResourceHistory(ctx context.Context, appIdentifier *client.AppIdentifier, k8sRequestBean *k8s.K8sRequestBean, userId int32, actionType string) error {
 
 	app, err := impl.appRepository.FindActiveByName(appIdentifier.ReleaseName)
 
@@ -93,7 +100,23 @@ func (impl K8sResourceHistoryServiceImpl) SaveHelmAppsResourceHistory(appIdentif
 	}
 
 	err = impl.K8sResourceHistoryRepository.SaveK8sResourceHistory(&k8sResourceHistory)
+	if err == nil {
+		impl.recordAuditEvent(ctx, &k8sResourceHistory)
+	}
 
 	return err
 
 }
+
+// recordAuditEvent mirrors the resource action into the unified audit log, resource is identified as kind/namespace/name
+func (impl K8sResourceHistoryServiceImpl) recordAuditEvent(ctx context.Context, k8sResourceHistory *repository.K8sResourceHistory) {
+	resourceName := fmt.Sprintf("%s/%s/%s", k8sResourceHistory.Kind, k8sResourceHistory.Namespace, k8sResourceHistory.ResourceName)
+	impl.auditLogService.RecordEvent(ctx, &auditLog.AuditEventRequest{
+		Action:       k8sResourceHistory.ActionType,
+		ResourceType: auditLog.ResourceTypeKubernetesResource,
+		ResourceId:   resourceName,
+		ResourceName: resourceName,
+		UserId:       k8sResourceHistory.UpdatedBy,
+		After:        k8sResourceHistory,
+	})
+}
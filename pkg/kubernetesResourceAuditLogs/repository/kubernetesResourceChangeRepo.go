@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// K8sResourceChange is a create, update or delete of a resource observed by the change watcher, Diff is the json
+// merge patch from the previous to the new object for updates and the object for creates. A change is identified by
+// the uid and resource version of the resource and the action, so that it is saved once however many replicas observe it
+type K8sResourceChange struct {
+	tableName       struct{}  `sql:"k8s_resource_change" pg:",discard_unknown_columns"`
+	Id              int       `sql:"id,pk"`
+	ClusterId       int       `sql:"cluster_id,notnull"`
+	Namespace       string    `sql:"namespace,notnull"`
+	Group           string    `sql:"group,notnull"`
+	Version         string    `sql:"version,notnull"`
+	Kind            string    `sql:"kind,notnull"`
+	ResourceName    string    `sql:"resource_name,notnull"`
+	Action          string    `sql:"action,notnull"`
+	Diff            string    `sql:"diff"`
+	FieldManager    string    `sql:"field_manager"`
+	UserId          int32     `sql:"user_id"`
+	ResourceUid     string    `sql:"resource_uid"`
+	ResourceVersion string    `sql:"resource_version"`
+	ChangedOn       time.Time `sql:"changed_on,type:timestamptz"`
+}
+
+type K8sResourceChangeFilter struct {
+	ClusterId    int
+	Namespace    string
+	Kind         string
+	ResourceName string
+	From         time.Time
+	To           time.Time
+	Limit        int
+}
+
+type K8sResourceChangeRepository interface {
+	// SaveAll saves the changes which are not saved yet, changes already saved by another replica are skipped
+	SaveAll(changes []*K8sResourceChange) error
+	FindByFilter(filter *K8sResourceChangeFilter) ([]*K8sResourceChange, error)
+	DeleteChangesBefore(changedOn time.Time) error
+}
+
+type K8sResourceChangeRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewK8sResourceChangeRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *K8sResourceChangeRepositoryImpl {
+	return &K8sResourceChangeRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (repo K8sResourceChangeRepositoryImpl) SaveAll(changes []*K8sResourceChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	_, err := repo.dbConnection.Model(&changes).OnConflict("DO NOTHING").Insert()
+	if err != nil {
+		repo.logger.Errorw("error in saving k8s resource changes", "err", err, "count", len(changes))
+		return err
+	}
+	return nil
+}
+
+func (repo K8sResourceChangeRepositoryImpl) FindByFilter(filter *K8sResourceChangeFilter) ([]*K8sResourceChange, error) {
+	var changes []*K8sResourceChange
+	query := repo.dbConnection.Model(&changes).
+		Where("cluster_id = ?", filter.ClusterId).
+		Where("namespace = ?", filter.Namespace).
+		Where("changed_on >= ?", filter.From).
+		Where("changed_on <= ?", filter.To)
+	if len(filter.Kind) > 0 {
+		query = query.Where("kind = ?", filter.Kind)
+	}
+	if len(filter.ResourceName) > 0 {
+		query = query.Where("resource_name = ?", filter.ResourceName)
+	}
+	err := query.Order("changed_on DESC").Order("id DESC").Limit(filter.Limit).Select()
+	if err != nil {
+		repo.logger.Errorw("error in getting k8s resource changes", "err", err, "clusterId", filter.ClusterId, "namespace", filter.Namespace)
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (repo K8sResourceChangeRepositoryImpl) DeleteChangesBefore(changedOn time.Time) error {
+	_, err := repo.dbConnection.Model((*K8sResourceChange)(nil)).
+		Where("changed_on < ?", changedOn).Delete()
+	return err
+}
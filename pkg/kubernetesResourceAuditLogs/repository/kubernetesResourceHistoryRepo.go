@@ -4,6 +4,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
+	"time"
 )
 
 type K8sResourceHistory struct {
@@ -24,6 +25,8 @@ type K8sResourceHistory struct {
 
 type K8sResourceHistoryRepository interface {
 	SaveK8sResourceHistory(history *K8sResourceHistory) error
+	// FindLatestByResource returns the latest action of the type on the resource performed from devtron after the time
+	FindLatestByResource(namespace, kind, resourceName, actionType string, after time.Time) (*K8sResourceHistory, error)
 }
 
 type K8sResourceHistoryRepositoryImpl struct {
@@ -41,3 +44,18 @@ func NewK8sResourceHistoryRepositoryImpl(dbConnection *pg.DB, logger *zap.Sugare
 func (repo K8sResourceHistoryRepositoryImpl) SaveK8sResourceHistory(k8sResourceHistory *K8sResourceHistory) error {
 	return repo.dbConnection.Insert(k8sResourceHistory)
 }
+
+func (repo K8sResourceHistoryRepositoryImpl) FindLatestByResource(namespace, kind, resourceName, actionType string, after time.Time) (*K8sResourceHistory, error) {
+	k8sResourceHistory := &K8sResourceHistory{}
+	err := repo.dbConnection.Model(k8sResourceHistory).
+		Where("namespace = ?", namespace).
+		Where("kind = ?", kind).
+		Where("resource_name = ?", resourceName).
+		Where("action_type = ?", actionType).
+		Where("updated_on >= ?", after).
+		Order("id DESC").Limit(1).Select()
+	if err != nil {
+		return nil, err
+	}
+	return k8sResourceHistory, nil
+}
@@ -42,6 +42,9 @@ import (
 	"github.com/devtron-labs/devtron/pkg/cluster"
 	repository2 "github.com/devtron-labs/devtron/pkg/cluster/repository"
 	bean2 "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/stepStatus"
+	stepStatusBean "github.com/devtron-labs/devtron/pkg/pipeline/stepStatus/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/testReport"
 	resourceGroup2 "github.com/devtron-labs/devtron/pkg/resourceGroup"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/devtron-labs/devtron/pkg/user"
@@ -116,10 +119,12 @@ type CdHandlerImpl struct {
 	imageTaggingService                    ImageTaggingService
 	k8sUtil                                *k8s.K8sUtil
 	workflowService                        WorkflowService
+	testReportService                      testReport.TestReportService
+	stepStatusService                      stepStatus.StepStatusService
 	config                                 *CdConfig
 }
 
-func NewCdHandlerImpl(Logger *zap.SugaredLogger, userService user.UserService, cdWorkflowRepository pipelineConfig.CdWorkflowRepository, ciLogService CiLogService, ciArtifactRepository repository.CiArtifactRepository, ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository, pipelineRepository pipelineConfig.PipelineRepository, envRepository repository2.EnvironmentRepository, ciWorkflowRepository pipelineConfig.CiWorkflowRepository, helmAppService client.HelmAppService, pipelineOverrideRepository chartConfig.PipelineOverrideRepository, workflowDagExecutor WorkflowDagExecutor, appListingService app.AppListingService, appListingRepository repository.AppListingRepository, pipelineStatusTimelineRepository pipelineConfig.PipelineStatusTimelineRepository, application application.ServiceClient, argoUserService argo.ArgoUserService, deploymentEventHandler app.DeploymentEventHandler, eventClient client2.EventClient, pipelineStatusTimelineResourcesService status.PipelineStatusTimelineResourcesService, pipelineStatusSyncDetailService status.PipelineStatusSyncDetailService, pipelineStatusTimelineService status.PipelineStatusTimelineService, appService app.AppService, appStatusService app_status.AppStatusService, enforcerUtil rbac.EnforcerUtil, installedAppRepository repository3.InstalledAppRepository, installedAppVersionHistoryRepository repository3.InstalledAppVersionHistoryRepository, appRepository app2.AppRepository, resourceGroupService resourceGroup2.ResourceGroupService, imageTaggingService ImageTaggingService, k8sUtil *k8s.K8sUtil, workflowService WorkflowService) *CdHandlerImpl {
+func NewCdHandlerImpl(Logger *zap.SugaredLogger, userService user.UserService, cdWorkflowRepository pipelineConfig.CdWorkflowRepository, ciLogService CiLogService, ciArtifactRepository repository.CiArtifactRepository, ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository, pipelineRepository pipelineConfig.PipelineRepository, envRepository repository2.EnvironmentRepository, ciWorkflowRepository pipelineConfig.CiWorkflowRepository, helmAppService client.HelmAppService, pipelineOverrideRepository chartConfig.PipelineOverrideRepository, workflowDagExecutor WorkflowDagExecutor, appListingService app.AppListingService, appListingRepository repository.AppListingRepository, pipelineStatusTimelineRepository pipelineConfig.PipelineStatusTimelineRepository, application application.ServiceClient, argoUserService argo.ArgoUserService, deploymentEventHandler app.DeploymentEventHandler, eventClient client2.EventClient, pipelineStatusTimelineResourcesService status.PipelineStatusTimelineResourcesService, pipelineStatusSyncDetailService status.PipelineStatusSyncDetailService, pipelineStatusTimelineService status.PipelineStatusTimelineService, appService app.AppService, appStatusService app_status.AppStatusService, enforcerUtil rbac.EnforcerUtil, installedAppRepository repository3.InstalledAppRepository, installedAppVersionHistoryRepository repository3.InstalledAppVersionHistoryRepository, appRepository app2.AppRepository, resourceGroupService resourceGroup2.ResourceGroupService, imageTaggingService ImageTaggingService, k8sUtil *k8s.K8sUtil, workflowService WorkflowService, testReportService testReport.TestReportService, stepStatusService stepStatus.StepStatusService) *CdHandlerImpl {
 	cdh := &CdHandlerImpl{
 		Logger:                                 Logger,
 		userService:                            userService,
@@ -153,6 +158,8 @@ func NewCdHandlerImpl(Logger *zap.SugaredLogger, userService user.UserService, c
 		imageTaggingService:                    imageTaggingService,
 		k8sUtil:                                k8sUtil,
 		workflowService:                        workflowService,
+		testReportService:                      testReportService,
+		stepStatusService:                      stepStatusService,
 	}
 	config, err := GetCdConfig()
 	if err != nil {
@@ -607,6 +614,7 @@ func (impl *CdHandlerImpl) UpdateWorkflow(workflowStatus v1alpha1.WorkflowStatus
 	}
 
 	if impl.stateChanged(status, podStatus, message, workflowStatus.FinishedAt.Time, savedWorkflow) {
+		wasCompleted := isWorkflowCompleted(savedWorkflow.Status)
 		if savedWorkflow.Status != WorkflowCancel {
 			savedWorkflow.Status = status
 		}
@@ -635,10 +643,70 @@ func (impl *CdHandlerImpl) UpdateWorkflow(workflowStatus v1alpha1.WorkflowStatus
 		if string(v1alpha1.NodeError) == savedWorkflow.Status || string(v1alpha1.NodeFailed) == savedWorkflow.Status {
 			impl.Logger.Warnw("cd stage failed for workflow: ", "wfId", savedWorkflow.Id)
 		}
+		if !wasCompleted && isWorkflowCompleted(savedWorkflow.Status) &&
+			(savedWorkflow.WorkflowType == bean.CD_WORKFLOW_TYPE_PRE || savedWorkflow.WorkflowType == bean.CD_WORKFLOW_TYPE_POST) {
+			go impl.ingestStageArtifacts(savedWorkflow.CdWorkflow.PipelineId, savedWorkflow)
+		}
 	}
 	return savedWorkflow.Id, savedWorkflow.Status, nil
 }
 
+func isWorkflowCompleted(status string) bool {
+	return status == string(v1alpha1.NodeSucceeded) || status == string(v1alpha1.NodeFailed) || status == string(v1alpha1.NodeError)
+}
+
+// ingestStageArtifacts saves the results of the test reports found in the artifacts of a pre or post cd stage, the
+// step statuses written in the artifacts are saved for a failed stage whose runner didn't report them
+func (impl *CdHandlerImpl) ingestStageArtifacts(pipelineId int, wfr *pipelineConfig.CdWorkflowRunner) {
+	if !wfr.BlobStorageEnabled {
+		return
+	}
+	artifactsFile, err := impl.DownloadCdWorkflowArtifacts(pipelineId, wfr.Id)
+	if err != nil {
+		impl.Logger.Errorw("error in downloading artifacts of cd stage", "err", err, "pipelineId", pipelineId, "wfrId", wfr.Id)
+		return
+	}
+	defer func() {
+		artifactsFile.Close()
+		err := os.Remove(artifactsFile.Name())
+		if err != nil {
+			impl.Logger.Errorw("error in removing downloaded artifacts of cd stage", "err", err, "file", artifactsFile.Name())
+		}
+	}()
+	if wfr.Status == string(v1alpha1.NodeFailed) || wfr.Status == string(v1alpha1.NodeError) {
+		impl.saveStepStatusesFromArtifacts(artifactsFile.Name(), wfr)
+	}
+	files, err := testReport.ReadReportFilesFromZip(artifactsFile.Name())
+	if err != nil {
+		impl.Logger.Errorw("error in reading test reports from artifacts", "err", err, "pipelineId", pipelineId, "wfrId", wfr.Id)
+		return
+	}
+	_, err = impl.testReportService.IngestReports(string(wfr.WorkflowType), wfr.Id, pipelineId, files)
+	if err != nil {
+		impl.Logger.Errorw("error in ingesting test reports", "err", err, "pipelineId", pipelineId, "wfrId", wfr.Id)
+	}
+}
+
+func (impl *CdHandlerImpl) saveStepStatusesFromArtifacts(artifactsPath string, wfr *pipelineConfig.CdWorkflowRunner) {
+	savedStatuses, err := impl.stepStatusService.GetStepStatuses(string(wfr.WorkflowType), wfr.Id)
+	if err != nil {
+		impl.Logger.Errorw("error in getting step statuses of cd workflow runner", "err", err, "wfrId", wfr.Id)
+		return
+	}
+	if len(savedStatuses) > 0 {
+		return
+	}
+	statuses, err := stepStatus.ReadStepStatusesFromZip(artifactsPath)
+	if err != nil {
+		impl.Logger.Errorw("error in reading step statuses from artifacts", "err", err, "wfrId", wfr.Id)
+		return
+	}
+	err = impl.stepStatusService.SaveStepStatuses(string(wfr.WorkflowType), wfr.Id, statuses)
+	if err != nil {
+		impl.Logger.Errorw("error in saving step statuses of cd workflow runner", "err", err, "wfrId", wfr.Id)
+	}
+}
+
 func (impl *CdHandlerImpl) extractWorkfowStatus(workflowStatus v1alpha1.WorkflowStatus) *WorkflowStatus {
 	workflowName := ""
 	status := string(workflowStatus.Phase)
@@ -1004,6 +1072,13 @@ func (impl *CdHandlerImpl) FetchCdWorkflowDetails(appId int, environmentId int,
 		ArtifactId:           workflow.CiArtifactId,
 		CiPipelineId:         ciWf.CiPipelineId,
 	}
+	if workflow.WorkflowType == stepStatusBean.WorkflowTypePreCd || workflow.WorkflowType == stepStatusBean.WorkflowTypePostCd {
+		workflowResponse.StepStatuses, err = impl.stepStatusService.GetStepStatuses(workflow.WorkflowType, workflow.Id)
+		if err != nil {
+			impl.Logger.Errorw("error in getting step statuses of cd workflow runner", "err", err, "wfrId", workflow.Id)
+			return WorkflowResponse{}, err
+		}
+	}
 	return workflowResponse, nil
 
 }
@@ -30,7 +30,13 @@ import (
 	repository2 "github.com/devtron-labs/devtron/internal/sql/repository/imageTagging"
 	"github.com/devtron-labs/devtron/pkg/cluster"
 	repository3 "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/pipeline/artifactOutput"
+	artifactOutputBean "github.com/devtron-labs/devtron/pkg/pipeline/artifactOutput/bean"
 	bean3 "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/stepStatus"
+	stepStatusBean "github.com/devtron-labs/devtron/pkg/pipeline/stepStatus/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/testReport"
+	testReportBean "github.com/devtron-labs/devtron/pkg/pipeline/testReport/bean"
 	resourceGroup "github.com/devtron-labs/devtron/pkg/resourceGroup"
 	"github.com/devtron-labs/devtron/util/k8s"
 	"github.com/devtron-labs/devtron/util/rbac"
@@ -82,6 +88,9 @@ type CiHandler interface {
 	WriteToCreateTestSuites(pipelineId int, buildId int, triggeredBy int)
 	UpdateCiWorkflowStatusFailure(timeoutForFailureCiBuild int) error
 	FetchCiStatusForTriggerViewForEnvironment(request resourceGroup.ResourceGroupingRequest) ([]*pipelineConfig.CiWorkflowStatus, error)
+	// IngestTestReports saves the results of the test reports found in the artifacts of the workflow, the summary is
+	// nil when it has no test report or the pipeline has no test report config
+	IngestTestReports(pipelineId int, workflowId int) (*testReportBean.TestRunSummary, error)
 }
 
 type CiHandlerImpl struct {
@@ -104,10 +113,13 @@ type CiHandlerImpl struct {
 	resourceGroupService         resourceGroup.ResourceGroupService
 	envRepository                repository3.EnvironmentRepository
 	imageTaggingService          ImageTaggingService
+	testReportService            testReport.TestReportService
+	stepStatusService            stepStatus.StepStatusService
+	artifactOutputService        artifactOutput.ArtifactOutputService
 	config                       *CiConfig
 }
 
-func NewCiHandlerImpl(Logger *zap.SugaredLogger, ciService CiService, ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository, gitSensorClient gitSensor.Client, ciWorkflowRepository pipelineConfig.CiWorkflowRepository, workflowService WorkflowService, ciLogService CiLogService, ciArtifactRepository repository.CiArtifactRepository, userService user.UserService, eventClient client.EventClient, eventFactory client.EventFactory, ciPipelineRepository pipelineConfig.CiPipelineRepository, appListingRepository repository.AppListingRepository, K8sUtil *k8s.K8sUtil, cdPipelineRepository pipelineConfig.PipelineRepository, enforcerUtil rbac.EnforcerUtil, resourceGroupService resourceGroup.ResourceGroupService, envRepository repository3.EnvironmentRepository, imageTaggingService ImageTaggingService) *CiHandlerImpl {
+func NewCiHandlerImpl(Logger *zap.SugaredLogger, ciService CiService, ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository, gitSensorClient gitSensor.Client, ciWorkflowRepository pipelineConfig.CiWorkflowRepository, workflowService WorkflowService, ciLogService CiLogService, ciArtifactRepository repository.CiArtifactRepository, userService user.UserService, eventClient client.EventClient, eventFactory client.EventFactory, ciPipelineRepository pipelineConfig.CiPipelineRepository, appListingRepository repository.AppListingRepository, K8sUtil *k8s.K8sUtil, cdPipelineRepository pipelineConfig.PipelineRepository, enforcerUtil rbac.EnforcerUtil, resourceGroupService resourceGroup.ResourceGroupService, envRepository repository3.EnvironmentRepository, imageTaggingService ImageTaggingService, testReportService testReport.TestReportService, stepStatusService stepStatus.StepStatusService, artifactOutputService artifactOutput.ArtifactOutputService) *CiHandlerImpl {
 	cih := &CiHandlerImpl{
 		Logger:                       Logger,
 		ciService:                    ciService,
@@ -128,6 +140,9 @@ func NewCiHandlerImpl(Logger *zap.SugaredLogger, ciService CiService, ciPipeline
 		resourceGroupService:         resourceGroupService,
 		envRepository:                envRepository,
 		imageTaggingService:          imageTaggingService,
+		testReportService:            testReportService,
+		stepStatusService:            stepStatusService,
+		artifactOutputService:        artifactOutputService,
 	}
 	config, err := GetCiConfig()
 	if err != nil {
@@ -165,6 +180,8 @@ type WorkflowResponse struct {
 	ImageReleaseTags     []*repository2.ImageTag                     `json:"imageReleaseTags"`
 	ImageComment         *repository2.ImageComment                   `json:"imageComment"`
 	PipelineType         string                                      `json:"pipelineType"`
+	StepStatuses         []*stepStatusBean.StepStatus                `json:"stepStatuses,omitempty"`
+	ArtifactOutputs      []*artifactOutputBean.ArtifactOutput        `json:"artifactOutputs,omitempty"`
 }
 
 type GitTriggerInfoResponse struct {
@@ -653,6 +670,18 @@ func (impl *CiHandlerImpl) FetchWorkflowDetails(appId int, pipelineId int, build
 		EnvironmentName:    environmentName,
 		PipelineType:       workflow.CiPipeline.PipelineType,
 	}
+	workflowResponse.StepStatuses, err = impl.stepStatusService.GetStepStatuses(stepStatusBean.WorkflowTypeCi, workflow.Id)
+	if err != nil {
+		impl.Logger.Errorw("error in getting step statuses of ci workflow", "err", err, "workflowId", workflow.Id)
+		return WorkflowResponse{}, err
+	}
+	if ciArtifact.Id > 0 {
+		workflowResponse.ArtifactOutputs, err = impl.artifactOutputService.GetArtifactOutputs(ciArtifact.Id, ciArtifact.ParentCiArtifact)
+		if err != nil {
+			impl.Logger.Errorw("error in getting outputs of ci artifact", "err", err, "ciArtifactId", ciArtifact.Id)
+			return WorkflowResponse{}, err
+		}
+	}
 	return workflowResponse, nil
 }
 
@@ -959,6 +988,12 @@ func (impl *CiHandlerImpl) UpdateWorkflow(workflowStatus v1alpha1.WorkflowStatus
 	}
 	ciArtifactLocation := fmt.Sprintf(ciArtifactLocationFormat, ciWorkflowConfig.LogsBucket, savedWorkflow.Id, savedWorkflow.Id)
 
+	// a build failed by the test pass rate condition keeps its status while the runner completes
+	failedForPassRate := isTestPassRateFailure(savedWorkflow)
+	if failedForPassRate {
+		status = savedWorkflow.Status
+		message = savedWorkflow.Message
+	}
 	if impl.stateChanged(status, podStatus, message, workflowStatus.FinishedAt.Time, savedWorkflow) {
 		if savedWorkflow.Status != WorkflowCancel {
 			savedWorkflow.Status = status
@@ -987,11 +1022,19 @@ func (impl *CiHandlerImpl) UpdateWorkflow(workflowStatus v1alpha1.WorkflowStatus
 			}
 
 			impl.WriteToCreateTestSuites(savedWorkflow.CiPipelineId, workflowId, int(savedWorkflow.TriggeredBy))
+			if !failedForPassRate {
+				// the reports of the failed build were not ingested by the ci success event
+				go impl.IngestTestReports(savedWorkflow.CiPipelineId, workflowId)
+			}
 		}
 	}
 	return savedWorkflow.Id, nil
 }
 
+func isTestPassRateFailure(ciWorkflow *pipelineConfig.CiWorkflow) bool {
+	return ciWorkflow.Status == string(v1alpha1.NodeFailed) && strings.HasPrefix(ciWorkflow.Message, testReportBean.TestPassRateFailurePrefix)
+}
+
 func extractErrorCode(msg string) int {
 	re := regexp.MustCompile(`\d+`)
 	matches := re.FindAllString(msg, -1)
@@ -1418,6 +1461,39 @@ func (impl *CiHandlerImpl) WriteToCreateTestSuites(pipelineId int, buildId int,
 	}
 }
 
+func (impl *CiHandlerImpl) IngestTestReports(pipelineId int, workflowId int) (*testReportBean.TestRunSummary, error) {
+	config, err := impl.testReportService.GetCiPipelineConfig(pipelineId)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, nil
+	}
+	artifactsFile, err := impl.DownloadCiWorkflowArtifacts(pipelineId, workflowId)
+	if err != nil {
+		impl.Logger.Errorw("error in downloading artifacts for test reports", "err", err, "pipelineId", pipelineId, "workflowId", workflowId)
+		return nil, err
+	}
+	defer func() {
+		artifactsFile.Close()
+		err := os.Remove(artifactsFile.Name())
+		if err != nil {
+			impl.Logger.Errorw("error in removing downloaded artifacts of test reports", "err", err, "file", artifactsFile.Name())
+		}
+	}()
+	files, err := testReport.ReadReportFilesFromZip(artifactsFile.Name())
+	if err != nil {
+		impl.Logger.Errorw("error in reading test reports from artifacts", "err", err, "pipelineId", pipelineId, "workflowId", workflowId)
+		return nil, err
+	}
+	summary, err := impl.testReportService.IngestReports(testReportBean.WorkflowTypeCi, workflowId, pipelineId, files)
+	if err != nil {
+		impl.Logger.Errorw("error in ingesting test reports", "err", err, "pipelineId", pipelineId, "workflowId", workflowId)
+		return nil, err
+	}
+	return summary, nil
+}
+
 func (impl *CiHandlerImpl) listFiles(file *zip.File, payload map[string]interface{}) (map[string]interface{}, error) {
 	fileRead, err := file.Open()
 	if err != nil {
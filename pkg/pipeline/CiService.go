@@ -27,6 +27,10 @@ import (
 	"github.com/devtron-labs/devtron/pkg/app"
 	repository1 "github.com/devtron-labs/devtron/pkg/cluster/repository"
 	bean2 "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/buildCache"
+	buildCacheBean "github.com/devtron-labs/devtron/pkg/pipeline/buildCache/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/buildMatrix"
+	buildMatrixBean "github.com/devtron-labs/devtron/pkg/pipeline/buildMatrix/bean"
 	"github.com/devtron-labs/devtron/pkg/pipeline/history"
 	"github.com/devtron-labs/devtron/pkg/pipeline/repository"
 	repository2 "github.com/devtron-labs/devtron/pkg/plugin/repository"
@@ -72,6 +76,8 @@ type CiServiceImpl struct {
 	envRepository                  repository1.EnvironmentRepository
 	appRepository                  appRepository.AppRepository
 	variableSnapshotHistoryService variables.VariableSnapshotHistoryService
+	buildMatrixService             buildMatrix.BuildMatrixService
+	buildCacheService              buildCache.BuildCacheService
 	config                         *CiConfig
 }
 
@@ -84,6 +90,8 @@ func NewCiServiceImpl(Logger *zap.SugaredLogger, workflowService WorkflowService
 	userService user.UserService,
 	ciTemplateService CiTemplateService, appCrudOperationService app.AppCrudOperationService, envRepository repository1.EnvironmentRepository, appRepository appRepository.AppRepository,
 	variableSnapshotHistoryService variables.VariableSnapshotHistoryService,
+	buildMatrixService buildMatrix.BuildMatrixService,
+	buildCacheService buildCache.BuildCacheService,
 ) *CiServiceImpl {
 	cis := &CiServiceImpl{
 		Logger:                         Logger,
@@ -102,6 +110,8 @@ func NewCiServiceImpl(Logger *zap.SugaredLogger, workflowService WorkflowService
 		envRepository:                  envRepository,
 		appRepository:                  appRepository,
 		variableSnapshotHistoryService: variableSnapshotHistoryService,
+		buildMatrixService:             buildMatrixService,
+		buildCacheService:              buildCacheService,
 	}
 	config, err := GetCiConfig()
 	if err != nil {
@@ -187,17 +197,32 @@ func (impl *CiServiceImpl) TriggerCiPipeline(trigger Trigger) (int, error) {
 			UserMessage: "No tasks are configured in this job pipeline",
 		}
 	}
+	if !isJob {
+		matrix, matrixCells, err := impl.buildMatrixService.GetEnabledMatrixCells(pipeline.Id)
+		if err != nil {
+			impl.Logger.Errorw("error in getting build matrix", "err", err, "ciPipelineId", pipeline.Id)
+			return 0, err
+		}
+		if len(matrixCells) > 0 {
+			return impl.triggerBuildMatrix(trigger, pipeline, matrix, matrixCells, ciMaterials, ciWorkflowConfig, ciPipelineScripts, prePostAndRefPluginResponse)
+		}
+	}
 	savedCiWf, err := impl.saveNewWorkflow(pipeline, ciWorkflowConfig, trigger.CommitHashes, trigger.TriggeredBy, trigger.EnvironmentId, isJob)
 	if err != nil {
 		impl.Logger.Errorw("could not save new workflow", "err", err)
 		return 0, err
 	}
 
-	workflowRequest, err := impl.buildWfRequestForCiPipeline(pipeline, trigger, ciMaterials, savedCiWf, ciWorkflowConfig, ciPipelineScripts, preCiSteps, postCiSteps, refPluginsData)
+	workflowRequest, err := impl.buildWfRequestForCiPipeline(pipeline, trigger, ciMaterials, savedCiWf, ciWorkflowConfig, ciPipelineScripts, preCiSteps, postCiSteps, refPluginsData, nil)
 	if err != nil {
 		impl.Logger.Errorw("make workflow req", "err", err)
 		return 0, err
 	}
+	err = impl.applyBuildCache(pipeline, trigger, ciMaterials, workflowRequest, "")
+	if err != nil {
+		impl.Logger.Errorw("error in getting build cache", "err", err, "ciPipelineId", pipeline.Id)
+		return 0, err
+	}
 
 	if impl.config != nil && impl.config.BuildxK8sDriverOptions != "" {
 		err = impl.setBuildxK8sDriverData(workflowRequest)
@@ -231,25 +256,207 @@ func (impl *CiServiceImpl) TriggerCiPipeline(trigger Trigger) (int, error) {
 	impl.Logger.Debugw("ci triggered", " pipeline ", trigger.PipelineId)
 
 	//Save Scoped VariableSnapshot
-	if len(variableSnapshot) > 0 {
-		variableMapBytes, _ := json.Marshal(variableSnapshot)
-		err := impl.variableSnapshotHistoryService.SaveVariableHistoriesForTrigger([]*repository4.VariableSnapshotHistoryBean{{
-			VariableSnapshot: variableMapBytes,
-			HistoryReference: repository4.HistoryReference{
-				HistoryReferenceId:   savedCiWf.Id,
-				HistoryReferenceType: repository4.HistoryReferenceTypeCIWORKFLOW,
-			},
-		}}, trigger.TriggeredBy)
-		if err != nil {
-			impl.Logger.Errorf("Not able to save variable snapshot for CI trigger %s", err)
-		}
-	}
+	impl.saveVariableSnapshot(variableSnapshot, savedCiWf.Id, trigger.TriggeredBy)
 
 	middleware.CiTriggerCounter.WithLabelValues(pipeline.App.AppName, pipeline.Name).Inc()
 	go impl.WriteCITriggerEvent(trigger, pipeline, workflowRequest)
 	return savedCiWf.Id, err
 }
 
+// triggerBuildMatrix fans the trigger out into a ci workflow for every cell of the build matrix of the pipeline, the
+// workflows are saved as one matrix run and the id of the workflow of the first cell is returned
+func (impl *CiServiceImpl) triggerBuildMatrix(trigger Trigger, pipeline *pipelineConfig.CiPipeline, matrix *buildMatrixBean.BuildMatrix,
+	matrixCells []*buildMatrixBean.MatrixCell, ciMaterials []*pipelineConfig.CiPipelineMaterial, ciWorkflowConfig *pipelineConfig.CiWorkflowConfig,
+	ciPipelineScripts []*pipelineConfig.CiPipelineScript, prePostAndRefPluginResponse *bean2.PrePostAndRefPluginStepsResponse) (int, error) {
+	appLabels, err := impl.appCrudOperationService.GetLabelsByAppId(pipeline.AppId)
+	if err != nil {
+		return 0, err
+	}
+	// the workflows saved before an error are failed with it, nothing of the run is submitted then
+	var savedCiWfs []*pipelineConfig.CiWorkflow
+	var workflowRequests []*WorkflowRequest
+	baseImageTag := ""
+	for i, matrixCell := range matrixCells {
+		savedCiWf, err := impl.saveNewWorkflow(pipeline, ciWorkflowConfig, trigger.CommitHashes, trigger.TriggeredBy, trigger.EnvironmentId, false)
+		if err != nil {
+			impl.Logger.Errorw("could not save new workflow", "err", err)
+			impl.markCiWorkflowsFailed(savedCiWfs, err)
+			return 0, err
+		}
+		savedCiWfs = append(savedCiWfs, savedCiWf)
+		workflowRequest, err := impl.buildWfRequestForCiPipeline(pipeline, trigger, ciMaterials, savedCiWf, ciWorkflowConfig, ciPipelineScripts,
+			prePostAndRefPluginResponse.PreStageSteps, prePostAndRefPluginResponse.PostStageSteps, prePostAndRefPluginResponse.RefPluginData, matrixCell)
+		if err != nil {
+			impl.Logger.Errorw("make workflow req", "err", err, "cell", matrixCell.Key)
+			impl.markCiWorkflowsFailed(savedCiWfs, err)
+			return 0, err
+		}
+		err = impl.applyBuildCache(pipeline, trigger, ciMaterials, workflowRequest, matrixCell.Key)
+		if err != nil {
+			impl.Logger.Errorw("error in getting build cache", "err", err, "ciPipelineId", pipeline.Id)
+			impl.markCiWorkflowsFailed(savedCiWfs, err)
+			return 0, err
+		}
+		if impl.config != nil && impl.config.BuildxK8sDriverOptions != "" {
+			err = impl.setBuildxK8sDriverData(workflowRequest)
+			if err != nil {
+				impl.Logger.Errorw("error in setBuildxK8sDriverData", "BUILDX_K8S_DRIVER_OPTIONS", impl.config.BuildxK8sDriverOptions, "err", err)
+				impl.markCiWorkflowsFailed(savedCiWfs, err)
+				return 0, err
+			}
+		}
+		// the cells share the tag of the first workflow, suffixed with their key
+		if i == 0 {
+			baseImageTag = workflowRequest.DockerImageTag
+			if baseImageTag == "" {
+				baseImageTag = fmt.Sprintf("%d-%d", pipeline.Id, savedCiWf.Id)
+			}
+		}
+		workflowRequest.DockerImageTag = buildMatrix.GetCellImageTag(baseImageTag, matrixCell.Key)
+		savedCiWf.LogLocation = fmt.Sprintf("%s/%s/main.log", impl.config.GetDefaultBuildLogsKeyPrefix(), workflowRequest.WorkflowNamePrefix)
+		err = impl.updateCiWorkflow(workflowRequest, savedCiWf)
+		if err != nil {
+			impl.Logger.Errorw("error in updating ci workflow", "err", err, "ciWorkflowId", savedCiWf.Id)
+			impl.markCiWorkflowsFailed(savedCiWfs, err)
+			return 0, err
+		}
+		workflowRequest.AppId = pipeline.AppId
+		workflowRequest.AppLabels = appLabels
+		workflowRequest.Type = bean2.CI_WORKFLOW_PIPELINE_TYPE
+		workflowRequests = append(workflowRequests, workflowRequest)
+	}
+
+	matrixRun := &buildMatrixBean.MatrixRun{
+		CiPipelineId: pipeline.Id,
+		OutputType:   matrix.OutputType,
+		TriggeredBy:  trigger.TriggeredBy,
+		StartedOn:    time.Now(),
+	}
+	firstRequest := workflowRequests[0]
+	if len(firstRequest.DockerRepository) > 0 {
+		registryHost := strings.TrimPrefix(strings.TrimPrefix(firstRequest.DockerRegistryURL, "https://"), "http://")
+		matrixRun.Image = fmt.Sprintf("%s/%s:%s", strings.TrimSuffix(registryHost, "/"), firstRequest.DockerRepository, baseImageTag)
+	}
+	for i, matrixCell := range matrixCells {
+		matrixRun.Cells = append(matrixRun.Cells, &buildMatrixBean.MatrixRunCell{MatrixCell: *matrixCell, CiWorkflowId: savedCiWfs[i].Id})
+	}
+	err = impl.buildMatrixService.SaveRun(matrixRun, firstRequest.DockerRegistryId)
+	if err != nil {
+		impl.Logger.Errorw("error in saving build matrix run", "err", err, "ciPipelineId", pipeline.Id)
+		impl.markCiWorkflowsFailed(savedCiWfs, err)
+		return 0, err
+	}
+
+	submitted := 0
+	for i, workflowRequest := range workflowRequests {
+		savedCiWf := savedCiWfs[i]
+		err = impl.executeCiPipeline(workflowRequest)
+		if err != nil {
+			// the other cells keep running, the run fails with this cell
+			impl.Logger.Errorw("workflow error", "err", err, "ciWorkflowId", savedCiWf.Id)
+			impl.markCiWorkflowsFailed([]*pipelineConfig.CiWorkflow{savedCiWf}, err)
+			continue
+		}
+		submitted++
+		impl.saveVariableSnapshot(prePostAndRefPluginResponse.VariableSnapshot, savedCiWf.Id, trigger.TriggeredBy)
+		middleware.CiTriggerCounter.WithLabelValues(pipeline.App.AppName, pipeline.Name).Inc()
+		go impl.WriteCITriggerEvent(trigger, pipeline, workflowRequest)
+	}
+	if submitted == 0 {
+		return 0, err
+	}
+	impl.Logger.Debugw("ci build matrix triggered", "pipeline", trigger.PipelineId, "matrixRunId", matrixRun.Id)
+	return savedCiWfs[0].Id, nil
+}
+
+// markCiWorkflowsFailed fails the saved workflows of a build matrix run which will not be submitted
+func (impl *CiServiceImpl) markCiWorkflowsFailed(ciWorkflows []*pipelineConfig.CiWorkflow, err error) {
+	for _, ciWorkflow := range ciWorkflows {
+		ciWorkflow.Status = pipelineConfig.WorkflowFailed
+		ciWorkflow.Message = err.Error()
+		ciWorkflow.FinishedOn = time.Now()
+		updateErr := impl.ciWorkflowRepository.UpdateWorkFlow(ciWorkflow)
+		if updateErr != nil {
+			impl.Logger.Errorw("error in updating ci workflow", "err", updateErr, "ciWorkflowId", ciWorkflow.Id)
+		}
+	}
+}
+
+// applyBuildCache makes a docker build import and export the registry cache of the pipeline, a registry cache replaces
+// the cache tarball of the pipeline in blob storage
+func (impl *CiServiceImpl) applyBuildCache(pipeline *pipelineConfig.CiPipeline, trigger Trigger, ciMaterials []*pipelineConfig.CiPipelineMaterial,
+	workflowRequest *WorkflowRequest, cellKey string) error {
+	if workflowRequest.CiBuildConfig == nil || workflowRequest.CiBuildConfig.DockerBuildConfig == nil {
+		return nil
+	}
+	branch, fallbackBranch := getBuildBranches(ciMaterials, trigger.CommitHashes)
+	refs, err := impl.buildCacheService.GetBuildCacheRefs(&buildCacheBean.BuildCacheRequest{
+		AppId:            pipeline.AppId,
+		CiPipelineId:     pipeline.Id,
+		CiWorkflowId:     workflowRequest.WorkflowId,
+		Branch:           branch,
+		FallbackBranch:   fallbackBranch,
+		CellKey:          cellKey,
+		DockerRegistryId: workflowRequest.DockerRegistryId,
+		RegistryType:     workflowRequest.DockerRegistryType,
+		RegistryURL:      workflowRequest.DockerRegistryURL,
+		DockerRepository: workflowRequest.DockerRepository,
+	})
+	if err != nil || refs == nil {
+		return err
+	}
+	dockerBuildConfig := workflowRequest.CiBuildConfig.DockerBuildConfig
+	dockerBuildConfig.UseBuildx = true
+	dockerBuildConfig.BuildxCacheFrom = refs.CacheFrom
+	dockerBuildConfig.BuildxCacheTo = refs.CacheTo
+	workflowRequest.IgnoreDockerCachePush = true
+	workflowRequest.IgnoreDockerCachePull = true
+	return nil
+}
+
+// getBuildBranches returns the branch built from the first git material, and for a pull request its target branch
+func getBuildBranches(ciMaterials []*pipelineConfig.CiPipelineMaterial, commitHashes map[int]bean.GitCommit) (string, string) {
+	for _, ciMaterial := range ciMaterials {
+		if ciMaterial == nil || ciMaterial.GitMaterial == nil || !ciMaterial.GitMaterial.Active {
+			continue
+		}
+		if ciMaterial.Type == pipelineConfig.SOURCE_TYPE_BRANCH_FIXED {
+			return ciMaterial.Value, ""
+		}
+		if webhookData := commitHashes[ciMaterial.Id].WebhookData; ciMaterial.Type == pipelineConfig.SOURCE_TYPE_WEBHOOK && webhookData != nil {
+			return webhookData.Data[bean.WEBHOOK_SELECTOR_SOURCE_CHECKOUT_NAME], webhookData.Data[bean.WEBHOOK_SELECTOR_TARGET_CHECKOUT_NAME]
+		}
+		return "", ""
+	}
+	return "", ""
+}
+
+// applyMatrixCell overrides the docker build of the pipeline with the platform, args and dockerfile of the cell, a
+// matrix is only saved enabled for docker builds
+func applyMatrixCell(ciBuildConfigBean *bean2.CiBuildConfigBean, matrixCell *buildMatrixBean.MatrixCell) {
+	dockerBuildConfig := ciBuildConfigBean.DockerBuildConfig
+	if dockerBuildConfig == nil {
+		return
+	}
+	if len(matrixCell.TargetPlatform) > 0 {
+		dockerBuildConfig.TargetPlatform = matrixCell.TargetPlatform
+		dockerBuildConfig.UseBuildx = true
+	}
+	if len(matrixCell.BuildArgs) > 0 {
+		args := make(map[string]string, len(dockerBuildConfig.Args)+len(matrixCell.BuildArgs))
+		for key, value := range dockerBuildConfig.Args {
+			args[key] = value
+		}
+		for key, value := range matrixCell.BuildArgs {
+			args[key] = value
+		}
+		dockerBuildConfig.Args = args
+	}
+	if len(matrixCell.DockerfilePath) > 0 {
+		dockerBuildConfig.DockerfilePath = matrixCell.DockerfilePath
+	}
+}
+
 func (impl *CiServiceImpl) setBuildxK8sDriverData(workflowRequest *WorkflowRequest) error {
 	ciBuildConfig := workflowRequest.CiBuildConfig
 	if ciBuildConfig != nil {
@@ -269,6 +476,22 @@ func (impl *CiServiceImpl) setBuildxK8sDriverData(workflowRequest *WorkflowReque
 	return nil
 }
 
+func (impl *CiServiceImpl) saveVariableSnapshot(variableSnapshot map[string]string, ciWorkflowId int, userId int32) {
+	if len(variableSnapshot) > 0 {
+		variableMapBytes, _ := json.Marshal(variableSnapshot)
+		err := impl.variableSnapshotHistoryService.SaveVariableHistoriesForTrigger([]*repository4.VariableSnapshotHistoryBean{{
+			VariableSnapshot: variableMapBytes,
+			HistoryReference: repository4.HistoryReference{
+				HistoryReferenceId:   ciWorkflowId,
+				HistoryReferenceType: repository4.HistoryReferenceTypeCIWORKFLOW,
+			},
+		}}, userId)
+		if err != nil {
+			impl.Logger.Errorf("Not able to save variable snapshot for CI trigger %s", err)
+		}
+	}
+}
+
 func (impl *CiServiceImpl) getEnvironmentForJob(pipeline *pipelineConfig.CiPipeline, trigger Trigger) (*repository1.Environment, bool, error) {
 	app, err := impl.appRepository.FindById(pipeline.AppId)
 	if err != nil {
@@ -422,7 +645,8 @@ func (impl *CiServiceImpl) buildDefaultArtifactLocation(ciWorkflowConfig *pipeli
 func (impl *CiServiceImpl) buildWfRequestForCiPipeline(pipeline *pipelineConfig.CiPipeline, trigger Trigger,
 	ciMaterials []*pipelineConfig.CiPipelineMaterial, savedWf *pipelineConfig.CiWorkflow,
 	ciWorkflowConfig *pipelineConfig.CiWorkflowConfig, ciPipelineScripts []*pipelineConfig.CiPipelineScript,
-	preCiSteps []*bean2.StepObject, postCiSteps []*bean2.StepObject, refPluginsData []*bean2.RefPluginObject) (*WorkflowRequest, error) {
+	preCiSteps []*bean2.StepObject, postCiSteps []*bean2.StepObject, refPluginsData []*bean2.RefPluginObject,
+	matrixCell *buildMatrixBean.MatrixCell) (*WorkflowRequest, error) {
 	var ciProjectDetails []bean2.CiProjectDetails
 	commitHashes := trigger.CommitHashes
 	for _, ciMaterial := range ciMaterials {
@@ -563,6 +787,9 @@ func (impl *CiServiceImpl) buildWfRequestForCiPipeline(pipeline *pipelineConfig.
 		impl.Logger.Errorw("error occurred while overriding ci build config", "oldArgs", oldArgs, "ciLevelArgs", ciLevelArgs, "error", err)
 		return nil, errors.New("error while parsing ci build config")
 	}
+	if matrixCell != nil {
+		applyMatrixCell(ciBuildConfigBean, matrixCell)
+	}
 	buildContextCheckoutPath, err := impl.ciPipelineMaterialRepository.GetCheckoutPath(ciBuildConfigBean.BuildContextGitMaterialId)
 	if err != nil && err != pg.ErrNoRows {
 		impl.Logger.Errorw("error occurred while getting checkout path from git material", "gitMaterialId", ciBuildConfigBean.BuildContextGitMaterialId, "error", err)
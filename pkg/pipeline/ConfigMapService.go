@@ -18,12 +18,14 @@
 package pipeline
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/devtron-labs/devtron/internal/sql/repository/app"
 	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
 	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
 	repository2 "github.com/devtron-labs/devtron/pkg/cluster/repository"
 	"github.com/devtron-labs/devtron/pkg/commonService"
@@ -35,6 +37,7 @@ import (
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
 	"regexp"
+	"strconv"
 	"time"
 )
 
@@ -53,22 +56,22 @@ type SecretsList struct {
 }
 
 type ConfigMapService interface {
-	CMGlobalAddUpdate(configMapRequest *bean.ConfigDataRequest) (*bean.ConfigDataRequest, error)
+	CMGlobalAddUpdate(configMapRequest *bean.ConfigDataRequest, ctx context.Context) (*bean.ConfigDataRequest, error)
 	CMGlobalFetch(appId int) (*bean.ConfigDataRequest, error)
-	CMEnvironmentAddUpdate(configMapRequest *bean.ConfigDataRequest) (*bean.ConfigDataRequest, error)
+	CMEnvironmentAddUpdate(configMapRequest *bean.ConfigDataRequest, ctx context.Context) (*bean.ConfigDataRequest, error)
 	CMEnvironmentFetch(appId int, envId int) (*bean.ConfigDataRequest, error)
 	CMGlobalFetchForEdit(name string, id int) (*bean.ConfigDataRequest, error)
 	CMEnvironmentFetchForEdit(name string, id int, appId int, envId int) (*bean.ConfigDataRequest, error)
 
-	CSGlobalAddUpdate(configMapRequest *bean.ConfigDataRequest) (*bean.ConfigDataRequest, error)
+	CSGlobalAddUpdate(configMapRequest *bean.ConfigDataRequest, ctx context.Context) (*bean.ConfigDataRequest, error)
 	CSGlobalFetch(appId int) (*bean.ConfigDataRequest, error)
-	CSEnvironmentAddUpdate(configMapRequest *bean.ConfigDataRequest) (*bean.ConfigDataRequest, error)
+	CSEnvironmentAddUpdate(configMapRequest *bean.ConfigDataRequest, ctx context.Context) (*bean.ConfigDataRequest, error)
 	CSEnvironmentFetch(appId int, envId int) (*bean.ConfigDataRequest, error)
 
-	CMGlobalDelete(name string, id int, userId int32) (bool, error)
-	CMEnvironmentDelete(name string, id int, userId int32) (bool, error)
-	CSGlobalDelete(name string, id int, userId int32) (bool, error)
-	CSEnvironmentDelete(name string, id int, userId int32) (bool, error)
+	CMGlobalDelete(name string, id int, userId int32, ctx context.Context) (bool, error)
+	CMEnvironmentDelete(name string, id int, userId int32, ctx context.Context) (bool, error)
+	CSGlobalDelete(name string, id int, userId int32, ctx context.Context) (bool, error)
+	CSEnvironmentDelete(name string, id int, userId int32, ctx context.Context) (bool, error)
 
 	CMGlobalDeleteByAppId(name string, appId int, userId int32) (bool, error)
 	CMEnvironmentDeleteByAppIdAndEnvId(name string, appId int, envId int, userId int32) (bool, error)
@@ -98,6 +101,7 @@ type ConfigMapServiceImpl struct {
 	appRepository               app.AppRepository
 	configMapHistoryService     history2.ConfigMapHistoryService
 	environmentRepository       repository2.EnvironmentRepository
+	auditLogService             auditLog.AuditLogService
 }
 
 func NewConfigMapServiceImpl(chartRepository chartRepoRepository.ChartRepository,
@@ -107,7 +111,8 @@ func NewConfigMapServiceImpl(chartRepository chartRepoRepository.ChartRepository
 	pipelineConfigRepository chartConfig.PipelineConfigRepository,
 	configMapRepository chartConfig.ConfigMapRepository, environmentConfigRepository chartConfig.EnvConfigOverrideRepository,
 	commonService commonService.CommonService, appRepository app.AppRepository,
-	configMapHistoryService history2.ConfigMapHistoryService, environmentRepository repository2.EnvironmentRepository) *ConfigMapServiceImpl {
+	configMapHistoryService history2.ConfigMapHistoryService, environmentRepository repository2.EnvironmentRepository,
+	auditLogService auditLog.AuditLogService) *ConfigMapServiceImpl {
 	return &ConfigMapServiceImpl{
 		chartRepository:             chartRepository,
 		logger:                      logger,
@@ -120,10 +125,11 @@ func NewConfigMapServiceImpl(chartRepository chartRepoRepository.ChartRepository
 		appRepository:               appRepository,
 		configMapHistoryService:     configMapHistoryService,
 		environmentRepository:       environmentRepository,
+		auditLogService:             auditLogService,
 	}
 }
 
-func (impl ConfigMapServiceImpl) CMGlobalAddUpdate(configMapRequest *bean.ConfigDataRequest) (*bean.ConfigDataRequest, error) {
+func (impl ConfigMapServiceImpl) CMGlobalAddUpdate(configMapRequest *bean.ConfigDataRequest, ctx context.Context) (*bean.ConfigDataRequest, error) {
 	if len(configMapRequest.ConfigData) != 1 {
 		return nil, fmt.Errorf("invalid request multiple config found for add or update")
 	}
@@ -134,6 +140,7 @@ func (impl ConfigMapServiceImpl) CMGlobalAddUpdate(configMapRequest *bean.Config
 		return configMapRequest, err
 	}
 	var model *chartConfig.ConfigMapAppModel
+	var existingConfig *bean.ConfigData
 	if configMapRequest.Id > 0 {
 		model, err = impl.configMapRepository.GetByIdAppLevel(configMapRequest.Id)
 		if err != nil {
@@ -151,6 +158,8 @@ func (impl ConfigMapServiceImpl) CMGlobalAddUpdate(configMapRequest *bean.Config
 		}
 		for _, item := range configsList.ConfigData {
 			if item.Name == configData.Name {
+				existing := *item
+				existingConfig = &existing
 				item.Data = configData.Data
 				item.MountPath = configData.MountPath
 				item.Type = configData.Type
@@ -212,6 +221,7 @@ func (impl ConfigMapServiceImpl) CMGlobalAddUpdate(configMapRequest *bean.Config
 		impl.logger.Errorw("error in creating entry for configmap history", "err", err)
 		return nil, err
 	}
+	impl.recordAuditEvent(ctx, auditLog.ResourceTypeConfigMap, model.Id, configMapRequest.UserId, existingConfig, configData)
 	return configMapRequest, nil
 }
 
@@ -250,7 +260,7 @@ func (impl ConfigMapServiceImpl) CMGlobalFetch(appId int) (*bean.ConfigDataReque
 	return configDataRequest, nil
 }
 
-func (impl ConfigMapServiceImpl) CMEnvironmentAddUpdate(configMapRequest *bean.ConfigDataRequest) (*bean.ConfigDataRequest, error) {
+func (impl ConfigMapServiceImpl) CMEnvironmentAddUpdate(configMapRequest *bean.ConfigDataRequest, ctx context.Context) (*bean.ConfigDataRequest, error) {
 
 	if len(configMapRequest.ConfigData) != 1 {
 		return nil, fmt.Errorf("invalid request multiple config found for add or update")
@@ -262,6 +272,7 @@ func (impl ConfigMapServiceImpl) CMEnvironmentAddUpdate(configMapRequest *bean.C
 		return configMapRequest, err
 	}
 	var model *chartConfig.ConfigMapEnvModel
+	var existingConfig *bean.ConfigData
 	if configMapRequest.Id > 0 {
 		model, err = impl.configMapRepository.GetByIdEnvLevel(configMapRequest.Id)
 	} else if configMapRequest.AppId > 0 && configMapRequest.EnvironmentId > 0 {
@@ -283,6 +294,8 @@ func (impl ConfigMapServiceImpl) CMEnvironmentAddUpdate(configMapRequest *bean.C
 		}
 		for _, item := range configsList.ConfigData {
 			if item.Name == configData.Name {
+				existing := *item
+				existingConfig = &existing
 				item.Data = configData.Data
 				item.MountPath = configData.MountPath
 				item.Type = configData.Type
@@ -343,6 +356,7 @@ func (impl ConfigMapServiceImpl) CMEnvironmentAddUpdate(configMapRequest *bean.C
 		impl.logger.Errorw("error in creating entry for CM/CS history in bulk update", "err", err)
 		return nil, err
 	}
+	impl.recordAuditEvent(ctx, auditLog.ResourceTypeConfigMap, model.Id, configMapRequest.UserId, existingConfig, configData)
 	return configMapRequest, nil
 }
 
@@ -488,7 +502,7 @@ func processCmCsEnvLevel(configData []*bean.ConfigData) {
 
 // ---------------------------------------------------------------------------------------------
 
-func (impl ConfigMapServiceImpl) CSGlobalAddUpdate(configMapRequest *bean.ConfigDataRequest) (*bean.ConfigDataRequest, error) {
+func (impl ConfigMapServiceImpl) CSGlobalAddUpdate(configMapRequest *bean.ConfigDataRequest, ctx context.Context) (*bean.ConfigDataRequest, error) {
 	if len(configMapRequest.ConfigData) != 1 {
 		return nil, fmt.Errorf("invalid request multiple config found for add or update")
 	}
@@ -505,6 +519,7 @@ func (impl ConfigMapServiceImpl) CSGlobalAddUpdate(configMapRequest *bean.Config
 		return configMapRequest, err
 	}
 	var model *chartConfig.ConfigMapAppModel
+	var existingConfig *bean.ConfigData
 	if configMapRequest.Id > 0 {
 		model, err = impl.configMapRepository.GetByIdAppLevel(configMapRequest.Id)
 		if err != nil {
@@ -522,6 +537,8 @@ func (impl ConfigMapServiceImpl) CSGlobalAddUpdate(configMapRequest *bean.Config
 		}
 		for _, item := range secretsList.ConfigData {
 			if item.Name == configData.Name {
+				existing := *item
+				existingConfig = &existing
 				found = true
 				item.Data = configData.Data
 				item.MountPath = configData.MountPath
@@ -586,6 +603,7 @@ func (impl ConfigMapServiceImpl) CSGlobalAddUpdate(configMapRequest *bean.Config
 		impl.logger.Errorw("error in creating entry for secret history", "err", err)
 		return nil, err
 	}
+	impl.recordAuditEvent(ctx, auditLog.ResourceTypeSecret, model.Id, configMapRequest.UserId, existingConfig, configData)
 	return configMapRequest, nil
 }
 
@@ -676,7 +694,7 @@ func (impl ConfigMapServiceImpl) CSGlobalFetch(appId int) (*bean.ConfigDataReque
 	return configDataRequest, nil
 }
 
-func (impl ConfigMapServiceImpl) CSEnvironmentAddUpdate(configMapRequest *bean.ConfigDataRequest) (*bean.ConfigDataRequest, error) {
+func (impl ConfigMapServiceImpl) CSEnvironmentAddUpdate(configMapRequest *bean.ConfigDataRequest, ctx context.Context) (*bean.ConfigDataRequest, error) {
 	if len(configMapRequest.ConfigData) != 1 {
 		return nil, fmt.Errorf("invalid request multiple config found for add or update")
 	}
@@ -694,6 +712,7 @@ func (impl ConfigMapServiceImpl) CSEnvironmentAddUpdate(configMapRequest *bean.C
 		return configMapRequest, err
 	}
 	var model *chartConfig.ConfigMapEnvModel
+	var existingConfig *bean.ConfigData
 	if configMapRequest.Id > 0 {
 		model, err = impl.configMapRepository.GetByIdEnvLevel(configMapRequest.Id)
 	} else if configMapRequest.AppId > 0 && configMapRequest.EnvironmentId > 0 {
@@ -715,6 +734,8 @@ func (impl ConfigMapServiceImpl) CSEnvironmentAddUpdate(configMapRequest *bean.C
 		}
 		for _, item := range configsList.ConfigData {
 			if item.Name == configData.Name {
+				existing := *item
+				existingConfig = &existing
 				item.Data = configData.Data
 				item.MountPath = configData.MountPath
 				item.Type = configData.Type
@@ -778,6 +799,7 @@ func (impl ConfigMapServiceImpl) CSEnvironmentAddUpdate(configMapRequest *bean.C
 		impl.logger.Errorw("error in creating entry for CM/CS history in bulk update", "err", err)
 		return nil, err
 	}
+	impl.recordAuditEvent(ctx, auditLog.ResourceTypeSecret, model.Id, configMapRequest.UserId, existingConfig, configData)
 	return configMapRequest, nil
 }
 
@@ -982,7 +1004,7 @@ func (impl ConfigMapServiceImpl) CSEnvironmentFetch(appId int, envId int) (*bean
 	return configDataRequest, nil
 }
 
-func (impl ConfigMapServiceImpl) CMGlobalDelete(name string, id int, userId int32) (bool, error) {
+func (impl ConfigMapServiceImpl) CMGlobalDelete(name string, id int, userId int32, ctx context.Context) (bool, error) {
 
 	model, err := impl.configMapRepository.GetByIdAppLevel(id)
 	if err != nil {
@@ -991,6 +1013,7 @@ func (impl ConfigMapServiceImpl) CMGlobalDelete(name string, id int, userId int3
 	}
 	configsList := &ConfigsList{}
 	found := false
+	var deletedConfig *bean.ConfigData
 	var configs []*bean.ConfigData
 	if len(model.ConfigMapData) > 0 {
 		err = json.Unmarshal([]byte(model.ConfigMapData), configsList)
@@ -1001,6 +1024,7 @@ func (impl ConfigMapServiceImpl) CMGlobalDelete(name string, id int, userId int3
 	for _, item := range configsList.ConfigData {
 		if item.Name == name {
 			found = true
+			deletedConfig = item
 		} else {
 			configs = append(configs, item)
 		}
@@ -1026,6 +1050,7 @@ func (impl ConfigMapServiceImpl) CMGlobalDelete(name string, id int, userId int3
 			impl.logger.Errorw("error in creating entry for configmap history", "err", err)
 			return false, err
 		}
+		impl.recordAuditEvent(ctx, auditLog.ResourceTypeConfigMap, model.Id, userId, deletedConfig, nil)
 	} else {
 		impl.logger.Debugw("no config map found for delete with this name", "name", name)
 
@@ -1034,7 +1059,7 @@ func (impl ConfigMapServiceImpl) CMGlobalDelete(name string, id int, userId int3
 	return true, nil
 }
 
-func (impl ConfigMapServiceImpl) CMEnvironmentDelete(name string, id int, userId int32) (bool, error) {
+func (impl ConfigMapServiceImpl) CMEnvironmentDelete(name string, id int, userId int32, ctx context.Context) (bool, error) {
 
 	model, err := impl.configMapRepository.GetByIdEnvLevel(id)
 	if err != nil {
@@ -1043,6 +1068,7 @@ func (impl ConfigMapServiceImpl) CMEnvironmentDelete(name string, id int, userId
 	}
 	configsList := &ConfigsList{}
 	found := false
+	var deletedConfig *bean.ConfigData
 	var configs []*bean.ConfigData
 	if len(model.ConfigMapData) > 0 {
 		err = json.Unmarshal([]byte(model.ConfigMapData), configsList)
@@ -1053,6 +1079,7 @@ func (impl ConfigMapServiceImpl) CMEnvironmentDelete(name string, id int, userId
 	for _, item := range configsList.ConfigData {
 		if item.Name == name {
 			found = true
+			deletedConfig = item
 		} else {
 			configs = append(configs, item)
 		}
@@ -1078,6 +1105,7 @@ func (impl ConfigMapServiceImpl) CMEnvironmentDelete(name string, id int, userId
 			impl.logger.Errorw("error in creating entry for configmap env history", "err", err)
 			return false, err
 		}
+		impl.recordAuditEvent(ctx, auditLog.ResourceTypeConfigMap, model.Id, userId, deletedConfig, nil)
 	} else {
 		impl.logger.Debugw("no config map found for delete with this name", "name", name)
 	}
@@ -1085,7 +1113,7 @@ func (impl ConfigMapServiceImpl) CMEnvironmentDelete(name string, id int, userId
 	return true, nil
 }
 
-func (impl ConfigMapServiceImpl) CSGlobalDelete(name string, id int, userId int32) (bool, error) {
+func (impl ConfigMapServiceImpl) CSGlobalDelete(name string, id int, userId int32, ctx context.Context) (bool, error) {
 
 	model, err := impl.configMapRepository.GetByIdAppLevel(id)
 	if err != nil {
@@ -1094,6 +1122,7 @@ func (impl ConfigMapServiceImpl) CSGlobalDelete(name string, id int, userId int3
 	}
 	configsList := &SecretsList{}
 	found := false
+	var deletedConfig *bean.ConfigData
 	var configs []*bean.ConfigData
 	if len(model.SecretData) > 0 {
 		err = json.Unmarshal([]byte(model.SecretData), configsList)
@@ -1104,6 +1133,7 @@ func (impl ConfigMapServiceImpl) CSGlobalDelete(name string, id int, userId int3
 	for _, item := range configsList.ConfigData {
 		if item.Name == name {
 			found = true
+			deletedConfig = item
 		} else {
 			configs = append(configs, item)
 		}
@@ -1129,6 +1159,7 @@ func (impl ConfigMapServiceImpl) CSGlobalDelete(name string, id int, userId int3
 			impl.logger.Errorw("error in creating entry for secret history", "err", err)
 			return false, err
 		}
+		impl.recordAuditEvent(ctx, auditLog.ResourceTypeSecret, model.Id, userId, deletedConfig, nil)
 	} else {
 		impl.logger.Debugw("no config map found for delete with this name", "name", name)
 
@@ -1137,7 +1168,7 @@ func (impl ConfigMapServiceImpl) CSGlobalDelete(name string, id int, userId int3
 	return true, nil
 }
 
-func (impl ConfigMapServiceImpl) CSEnvironmentDelete(name string, id int, userId int32) (bool, error) {
+func (impl ConfigMapServiceImpl) CSEnvironmentDelete(name string, id int, userId int32, ctx context.Context) (bool, error) {
 
 	model, err := impl.configMapRepository.GetByIdEnvLevel(id)
 	if err != nil {
@@ -1146,6 +1177,7 @@ func (impl ConfigMapServiceImpl) CSEnvironmentDelete(name string, id int, userId
 	}
 	configsList := &SecretsList{}
 	found := false
+	var deletedConfig *bean.ConfigData
 	var configs []*bean.ConfigData
 	if len(model.SecretData) > 0 {
 		err = json.Unmarshal([]byte(model.SecretData), configsList)
@@ -1156,6 +1188,7 @@ func (impl ConfigMapServiceImpl) CSEnvironmentDelete(name string, id int, userId
 	for _, item := range configsList.ConfigData {
 		if item.Name == name {
 			found = true
+			deletedConfig = item
 		} else {
 			configs = append(configs, item)
 		}
@@ -1181,6 +1214,7 @@ func (impl ConfigMapServiceImpl) CSEnvironmentDelete(name string, id int, userId
 			impl.logger.Errorw("error in creating entry for secret env history", "err", err)
 			return false, err
 		}
+		impl.recordAuditEvent(ctx, auditLog.ResourceTypeSecret, model.Id, userId, deletedConfig, nil)
 	} else {
 		impl.logger.Debugw("no config map found for delete with this name", "name", name)
 	}
@@ -1893,3 +1927,58 @@ func (impl ConfigMapServiceImpl) ConfigSecretEnvironmentClone(appId int, cloneAp
 
 	return jobEnvOverrideResponse, nil
 }
+
+// recordAuditEvent records a change of a single config map or secret, action is derived from the states present
+func (impl ConfigMapServiceImpl) recordAuditEvent(ctx context.Context, resourceType string, resourceId int, userId int32, before *bean.ConfigData, after *bean.ConfigData) {
+	if impl.auditLogService == nil {
+		return
+	}
+	request := &auditLog.AuditEventRequest{
+		Action:       auditLog.ActionUpdate,
+		ResourceType: resourceType,
+		ResourceId:   strconv.Itoa(resourceId),
+		UserId:       userId,
+	}
+	if before == nil {
+		request.Action = auditLog.ActionCreate
+	} else {
+		request.ResourceName = before.Name
+		request.Before = toConfigAuditState(resourceType, before)
+	}
+	if after == nil {
+		request.Action = auditLog.ActionDelete
+	} else {
+		request.ResourceName = after.Name
+		request.After = toConfigAuditState(resourceType, after)
+	}
+	impl.auditLogService.RecordEvent(ctx, request)
+}
+
+// toConfigAuditState masks secret values and secret store specs, keys are kept so that added and removed keys show up in the diff
+func toConfigAuditState(resourceType string, configData *bean.ConfigData) *bean.ConfigData {
+	if resourceType != auditLog.ResourceTypeSecret {
+		return configData
+	}
+	state := *configData
+	state.Data = maskSecretValues(configData.Data)
+	state.DefaultData = maskSecretValues(configData.DefaultData)
+	state.ESOSecretData.SecretStore = maskSecretValues(configData.ESOSecretData.SecretStore)
+	state.DefaultESOSecretData.SecretStore = maskSecretValues(configData.DefaultESOSecretData.SecretStore)
+	return &state
+}
+
+func maskSecretValues(data json.RawMessage) json.RawMessage {
+	if len(data) == 0 {
+		return data
+	}
+	values := make(map[string]interface{})
+	if err := json.Unmarshal(data, &values); err == nil {
+		for key := range values {
+			values[key] = "*****"
+		}
+		if maskedData, err := json.Marshal(values); err == nil {
+			return maskedData
+		}
+	}
+	return json.RawMessage(`"*****"`)
+}
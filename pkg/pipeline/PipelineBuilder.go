@@ -21,6 +21,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	"github.com/devtron-labs/devtron/pkg/variables"
 	"github.com/devtron-labs/devtron/pkg/variables/parsers"
 	repository6 "github.com/devtron-labs/devtron/pkg/variables/repository"
@@ -109,9 +110,9 @@ type PipelineBuilder interface {
 	//function related to application and project
 
 	//CreateApp : Delegating the request to ciCdPipelineOrchestrator for application creation.
-	CreateApp(request *bean.CreateAppDTO) (*bean.CreateAppDTO, error)
+	CreateApp(request *bean.CreateAppDTO, ctx context.Context) (*bean.CreateAppDTO, error)
 	//DeleteApp : Delegating the request to ciCdPipelineOrchestrator for application deletion.
-	DeleteApp(appId int, userId int32) error
+	DeleteApp(appId int, userId int32, ctx context.Context) error
 	//GetApp : Gets Application along with Git materials for given appId.
 	GetApp(appId int) (application *bean.CreateAppDTO, err error)
 	//FindByIds : Find applications by given IDs, delegating the request to the appRepository.
@@ -141,7 +142,7 @@ type PipelineBuilder interface {
 	UpdateCiTemplate(updateRequest *bean.CiConfigRequest) (*bean.CiConfigRequest, error)
 	//PatchCiPipeline : Handle CI pipeline patch requests, making necessary changes to the configuration and returning the updated version.
 	//Performs Create ,Update and Delete operation.
-	PatchCiPipeline(request *bean.CiPatchRequest) (ciConfig *bean.CiConfigRequest, err error)
+	PatchCiPipeline(request *bean.CiPatchRequest, ctx context.Context) (ciConfig *bean.CiConfigRequest, err error)
 	//CreateCiPipeline : Create a CI pipeline based on the provided configuration request.
 	CreateCiPipeline(createRequest *bean.CiConfigRequest) (*bean.PipelineCreateResponse, error)
 	//GetCiPipelineMin : lists minimum detail of ciPipelines for given appId and envIds
@@ -315,6 +316,7 @@ type PipelineBuilderImpl struct {
 	imageTaggingService                             ImageTaggingService
 	variableEntityMappingService                    variables.VariableEntityMappingService
 	variableTemplateParser                          parsers.VariableTemplateParser
+	auditLogService                                 auditLog.AuditLogService
 }
 
 func NewPipelineBuilderImpl(logger *zap.SugaredLogger,
@@ -371,7 +373,8 @@ func NewPipelineBuilderImpl(logger *zap.SugaredLogger,
 	attributesRepository repository.AttributesRepository,
 	imageTaggingService ImageTaggingService,
 	variableEntityMappingService variables.VariableEntityMappingService,
-	variableTemplateParser parsers.VariableTemplateParser) *PipelineBuilderImpl {
+	variableTemplateParser parsers.VariableTemplateParser,
+	auditLogService auditLog.AuditLogService) *PipelineBuilderImpl {
 
 	securityConfig := &SecurityConfig{}
 	err := env.Parse(securityConfig)
@@ -443,6 +446,7 @@ func NewPipelineBuilderImpl(logger *zap.SugaredLogger,
 		imageTaggingService:                             imageTaggingService,
 		variableEntityMappingService:                    variableEntityMappingService,
 		variableTemplateParser:                          variableTemplateParser,
+		auditLogService:                                 auditLogService,
 	}
 }
 
@@ -464,20 +468,48 @@ func formatDate(t time.Time, layout string) string {
 	return t.Format(layout)
 }
 
-func (impl *PipelineBuilderImpl) CreateApp(request *bean.CreateAppDTO) (*bean.CreateAppDTO, error) {
+func (impl *PipelineBuilderImpl) CreateApp(request *bean.CreateAppDTO, ctx context.Context) (*bean.CreateAppDTO, error) {
 	impl.logger.Debugw("app create request received", "req", request)
 
 	res, err := impl.ciCdPipelineOrchestrator.CreateApp(request)
 	if err != nil {
 		impl.logger.Errorw("error in saving create app req", "req", request, "err", err)
+		return res, err
 	}
+	impl.recordAuditEvent(ctx, auditLog.ActionCreate, auditLog.ResourceTypeApp, res.Id, res.AppName, request.UserId, nil, res)
 	return res, err
 }
 
-func (impl *PipelineBuilderImpl) DeleteApp(appId int, userId int32) error {
+func (impl *PipelineBuilderImpl) DeleteApp(appId int, userId int32, ctx context.Context) error {
 	impl.logger.Debugw("app delete request received", "app", appId)
-	err := impl.ciCdPipelineOrchestrator.DeleteApp(appId, userId)
-	return err
+	existingApp, err := impl.GetApp(appId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching app for delete", "appId", appId, "err", err)
+		return err
+	}
+	err = impl.ciCdPipelineOrchestrator.DeleteApp(appId, userId)
+	if err != nil {
+		return err
+	}
+	impl.recordAuditEvent(ctx, auditLog.ActionDelete, auditLog.ResourceTypeApp, appId, existingApp.AppName, userId, existingApp, nil)
+	return nil
+}
+
+// recordAuditEvent records a change of an app or its pipelines in the audit log
+func (impl *PipelineBuilderImpl) recordAuditEvent(ctx context.Context, action string, resourceType string, resourceId int, resourceName string,
+	userId int32, before interface{}, after interface{}) {
+	if impl.auditLogService == nil {
+		return
+	}
+	impl.auditLogService.RecordEvent(ctx, &auditLog.AuditEventRequest{
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceId:   strconv.Itoa(resourceId),
+		ResourceName: resourceName,
+		UserId:       userId,
+		Before:       before,
+		After:        after,
+	})
 }
 
 func (impl *PipelineBuilderImpl) CreateMaterialsForApp(request *bean.CreateMaterialDTO) (*bean.CreateMaterialDTO, error) {
@@ -1589,7 +1621,7 @@ func (impl *PipelineBuilderImpl) addpipelineToTemplate(createRequest *bean.CiCon
 	return createRequest, err
 }
 
-func (impl *PipelineBuilderImpl) PatchCiPipeline(request *bean.CiPatchRequest) (ciConfig *bean.CiConfigRequest, err error) {
+func (impl *PipelineBuilderImpl) PatchCiPipeline(request *bean.CiPatchRequest, ctx context.Context) (ciConfig *bean.CiConfigRequest, err error) {
 	ciConfig, err = impl.getCiTemplateVariables(request.AppId)
 	if err != nil {
 		impl.logger.Errorw("err in fetching template for pipeline patch, ", "err", err, "appId", request.AppId)
@@ -1617,14 +1649,28 @@ func (impl *PipelineBuilderImpl) PatchCiPipeline(request *bean.CiPatchRequest) (
 			impl.logger.Errorw("error in adding pipeline to template", "ciConf", ciConfig, "err", err)
 			return nil, err
 		}
+		for _, ciPipeline := range res.CiPipelines {
+			impl.recordAuditEvent(ctx, auditLog.ActionCreate, auditLog.ResourceTypeCiPipeline, ciPipeline.Id, ciPipeline.Name, request.UserId, nil, ciPipeline)
+		}
 		return res, nil
 	case bean.UPDATE_SOURCE:
-		return impl.patchCiPipelineUpdateSource(ciConfig, request.CiPipeline)
+		existingCiPipeline, err := impl.GetCiPipelineById(request.CiPipeline.Id)
+		if err != nil {
+			impl.logger.Errorw("error in fetching ci pipeline for update", "ciPipelineId", request.CiPipeline.Id, "err", err)
+			return nil, err
+		}
+		res, err := impl.patchCiPipelineUpdateSource(ciConfig, request.CiPipeline)
+		if err != nil {
+			return nil, err
+		}
+		impl.recordAuditEvent(ctx, auditLog.ActionUpdate, auditLog.ResourceTypeCiPipeline, request.CiPipeline.Id, existingCiPipeline.Name, request.UserId, existingCiPipeline, request.CiPipeline)
+		return res, nil
 	case bean.DELETE:
 		pipeline, err := impl.DeleteCiPipeline(request)
 		if err != nil {
 			return nil, err
 		}
+		impl.recordAuditEvent(ctx, auditLog.ActionDelete, auditLog.ResourceTypeCiPipeline, pipeline.Id, pipeline.Name, request.UserId, pipeline, nil)
 		ciConfig.CiPipelines = []*bean.CiPipeline{pipeline}
 		return ciConfig, nil
 	default:
@@ -2386,6 +2432,7 @@ func (impl *PipelineBuilderImpl) DeleteCdPipeline(pipeline *pipelineConfig.Pipel
 		impl.logger.Errorw("error in committing db transaction", "err", err)
 		return deleteResponse, err
 	}
+	impl.recordAuditEvent(ctx, auditLog.ActionDelete, auditLog.ResourceTypeCdPipeline, pipeline.Id, pipeline.Name, userId, cdPipelinePluginDeleteReq, nil)
 	deleteResponse.DeleteInitiated = true
 	return deleteResponse, nil
 }
@@ -3340,6 +3387,8 @@ func (impl *PipelineBuilderImpl) createCdPipeline(ctx context.Context, app *app2
 	}
 
 	impl.logger.Debugw("pipeline created with GitMaterialId ", "id", pipelineId, "pipeline", pipeline)
+	pipeline.Id = pipelineId
+	impl.recordAuditEvent(ctx, auditLog.ActionCreate, auditLog.ResourceTypeCdPipeline, pipelineId, pipeline.Name, userId, nil, pipeline)
 	return pipelineId, nil
 }
 
@@ -3376,6 +3425,11 @@ func (impl *PipelineBuilderImpl) updateCdPipeline(ctx context.Context, pipeline
 		}
 		return err
 	}
+	existingPipeline, err := impl.GetCdPipelineById(pipeline.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cd pipeline for update", "pipelineId", pipeline.Id, "err", err)
+		return err
+	}
 	dbConnection := impl.pipelineRepository.GetConnection()
 	tx, err := dbConnection.Begin()
 	if err != nil {
@@ -3469,6 +3523,7 @@ func (impl *PipelineBuilderImpl) updateCdPipeline(ctx context.Context, pipeline
 	if err != nil {
 		return err
 	}
+	impl.recordAuditEvent(ctx, auditLog.ActionUpdate, auditLog.ResourceTypeCdPipeline, pipeline.Id, existingPipeline.Name, userID, existingPipeline, pipeline)
 	return nil
 }
 
@@ -4470,6 +4525,7 @@ func (impl *PipelineBuilderImpl) updateGitRepoUrlInCharts(appId int, chartGitAtt
 		if len(ch.GitRepoUrl) == 0 {
 			ch.GitRepoUrl = chartGitAttribute.RepoUrl
 			ch.ChartLocation = chartGitAttribute.ChartLocation
+			ch.GitRepoDirectory = chartGitAttribute.GitRepoDirectory
 			ch.UpdatedOn = time.Now()
 			ch.UpdatedBy = userId
 			err = impl.chartRepository.Update(ch)
@@ -7,6 +7,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
 	"github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	"github.com/devtron-labs/devtron/pkg/pipeline/stageStep"
 	repository2 "github.com/devtron-labs/devtron/pkg/plugin/repository"
 	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
 	"github.com/devtron-labs/devtron/pkg/sql"
@@ -183,10 +184,11 @@ func (impl *PipelineStageServiceImpl) GetCdPipelineStageDataDeepCopyForPipelineI
 
 func (impl *PipelineStageServiceImpl) BuildPipelineStageDataDeepCopy(pipelineStage *repository.PipelineStage) (*bean.PipelineStageDto, error) {
 	stageData := &bean.PipelineStageDto{
-		Id:          pipelineStage.Id,
-		Name:        pipelineStage.Name,
-		Description: pipelineStage.Description,
-		Type:        pipelineStage.Type,
+		Id:            pipelineStage.Id,
+		Name:          pipelineStage.Name,
+		Description:   pipelineStage.Description,
+		Type:          pipelineStage.Type,
+		ExecutionMode: pipelineStage.ExecutionMode,
 	}
 	if pipelineStage.Type == repository.PIPELINE_STAGE_TYPE_PRE_CD || pipelineStage.Type == repository.PIPELINE_STAGE_TYPE_POST_CD {
 		pipeline, err := impl.pipelineRepository.FindById(pipelineStage.CdPipelineId)
@@ -218,6 +220,11 @@ func (impl *PipelineStageServiceImpl) BuildPipelineStageDataDeepCopy(pipelineSta
 			OutputDirectoryPath:      step.OutputDirectoryPath,
 			StepType:                 step.StepType,
 			TriggerIfParentStageFail: step.TriggerIfParentStageFail,
+			DependsOn:                step.DependsOnStepIndexes,
+			MaxRetries:               step.MaxRetries,
+			RetryBackoffSeconds:      step.RetryBackoffSeconds,
+			TimeoutSeconds:           step.TimeoutSeconds,
+			ContinueOnFailure:        step.ContinueOnFailure,
 		}
 		if step.StepType == repository.PIPELINE_STEP_TYPE_INLINE {
 			inlineStepDetail, err := impl.BuildInlineStepDataDeepCopy(step)
@@ -405,10 +412,11 @@ func (impl *PipelineStageServiceImpl) GetCiPipelineStageData(ciPipelineId int) (
 
 func (impl *PipelineStageServiceImpl) BuildCiStageData(ciStage *repository.PipelineStage) (*bean.PipelineStageDto, error) {
 	stageData := &bean.PipelineStageDto{
-		Id:          ciStage.Id,
-		Name:        ciStage.Name,
-		Description: ciStage.Description,
-		Type:        ciStage.Type,
+		Id:            ciStage.Id,
+		Name:          ciStage.Name,
+		Description:   ciStage.Description,
+		Type:          ciStage.Type,
+		ExecutionMode: ciStage.ExecutionMode,
 	}
 	//getting all steps in this stage
 	steps, err := impl.pipelineStageRepository.GetAllStepsByStageId(ciStage.Id)
@@ -426,6 +434,11 @@ func (impl *PipelineStageServiceImpl) BuildCiStageData(ciStage *repository.Pipel
 			OutputDirectoryPath:      step.OutputDirectoryPath,
 			StepType:                 step.StepType,
 			TriggerIfParentStageFail: step.TriggerIfParentStageFail,
+			DependsOn:                step.DependsOnStepIndexes,
+			MaxRetries:               step.MaxRetries,
+			RetryBackoffSeconds:      step.RetryBackoffSeconds,
+			TimeoutSeconds:           step.TimeoutSeconds,
+			ContinueOnFailure:        step.ContinueOnFailure,
 		}
 		if step.StepType == repository.PIPELINE_STEP_TYPE_INLINE {
 			inlineStepDetail, err := impl.BuildInlineStepData(step)
@@ -586,6 +599,16 @@ func (impl *PipelineStageServiceImpl) BuildVariableAndConditionDataByStepId(step
 
 // CreatePipelineStage and related methods starts
 func (impl *PipelineStageServiceImpl) CreatePipelineStage(stageReq *bean.PipelineStageDto, stageType repository.PipelineStageType, pipelineId int, userId int32) error {
+	err := stageStep.ValidateStageStepDependencies(stageReq, stageType)
+	if err != nil {
+		impl.logger.Errorw("invalid step dependencies in pipeline stage", "err", err, "stageType", stageType, "pipelineId", pipelineId)
+		return err
+	}
+	err = stageStep.ValidateStageStepRetryPolicies(stageReq)
+	if err != nil {
+		impl.logger.Errorw("invalid step retry policies in pipeline stage", "err", err, "stageType", stageType, "pipelineId", pipelineId)
+		return err
+	}
 	dbConnection := impl.pipelineRepository.GetConnection()
 	tx, err := dbConnection.Begin()
 	if err != nil {
@@ -594,10 +617,11 @@ func (impl *PipelineStageServiceImpl) CreatePipelineStage(stageReq *bean.Pipelin
 	// Rollback tx on error.
 	defer tx.Rollback()
 	stage := &repository.PipelineStage{
-		Name:        stageReq.Name,
-		Description: stageReq.Description,
-		Type:        stageType,
-		Deleted:     false,
+		Name:          stageReq.Name,
+		Description:   stageReq.Description,
+		Type:          stageType,
+		ExecutionMode: stageReq.ExecutionMode,
+		Deleted:       false,
 		AuditLog: sql.AuditLog{
 			CreatedOn: time.Now(),
 			CreatedBy: userId,
@@ -666,15 +690,16 @@ func (impl *PipelineStageServiceImpl) CreateStageSteps(steps []*bean.PipelineSta
 				return err
 			}
 			inlineStep := &repository.PipelineStageStep{
-				PipelineStageId:     stageId,
-				Name:                step.Name,
-				Description:         step.Description,
-				Index:               step.Index,
-				StepType:            step.StepType,
-				ScriptId:            scriptEntryId,
-				OutputDirectoryPath: step.OutputDirectoryPath,
-				DependentOnStep:     dependentOnStep,
-				Deleted:             false,
+				PipelineStageId:      stageId,
+				Name:                 step.Name,
+				Description:          step.Description,
+				Index:                step.Index,
+				StepType:             step.StepType,
+				ScriptId:             scriptEntryId,
+				OutputDirectoryPath:  step.OutputDirectoryPath,
+				DependentOnStep:      dependentOnStep,
+				DependsOnStepIndexes: step.DependsOn,
+				Deleted:              false,
 				AuditLog: sql.AuditLog{
 					CreatedOn: time.Now(),
 					CreatedBy: userId,
@@ -682,6 +707,10 @@ func (impl *PipelineStageServiceImpl) CreateStageSteps(steps []*bean.PipelineSta
 					UpdatedBy: userId,
 				},
 				TriggerIfParentStageFail: step.TriggerIfParentStageFail,
+				MaxRetries:               step.MaxRetries,
+				RetryBackoffSeconds:      step.RetryBackoffSeconds,
+				TimeoutSeconds:           step.TimeoutSeconds,
+				ContinueOnFailure:        step.ContinueOnFailure,
 			}
 			inlineStep, err = impl.pipelineStageRepository.CreatePipelineStageStep(inlineStep, tx)
 			if err != nil {
@@ -695,15 +724,16 @@ func (impl *PipelineStageServiceImpl) CreateStageSteps(steps []*bean.PipelineSta
 		} else if step.StepType == repository.PIPELINE_STEP_TYPE_REF_PLUGIN {
 			refPluginStepDetail := step.RefPluginStepDetail
 			refPluginStep := &repository.PipelineStageStep{
-				PipelineStageId:     stageId,
-				Name:                step.Name,
-				Description:         step.Description,
-				Index:               step.Index,
-				StepType:            step.StepType,
-				RefPluginId:         refPluginStepDetail.PluginId,
-				OutputDirectoryPath: step.OutputDirectoryPath,
-				DependentOnStep:     dependentOnStep,
-				Deleted:             false,
+				PipelineStageId:      stageId,
+				Name:                 step.Name,
+				Description:          step.Description,
+				Index:                step.Index,
+				StepType:             step.StepType,
+				RefPluginId:          refPluginStepDetail.PluginId,
+				OutputDirectoryPath:  step.OutputDirectoryPath,
+				DependentOnStep:      dependentOnStep,
+				DependsOnStepIndexes: step.DependsOn,
+				Deleted:              false,
 				AuditLog: sql.AuditLog{
 					CreatedOn: time.Now(),
 					CreatedBy: userId,
@@ -711,6 +741,10 @@ func (impl *PipelineStageServiceImpl) CreateStageSteps(steps []*bean.PipelineSta
 					UpdatedBy: userId,
 				},
 				TriggerIfParentStageFail: step.TriggerIfParentStageFail,
+				MaxRetries:               step.MaxRetries,
+				RetryBackoffSeconds:      step.RetryBackoffSeconds,
+				TimeoutSeconds:           step.TimeoutSeconds,
+				ContinueOnFailure:        step.ContinueOnFailure,
 			}
 			refPluginStep, err := impl.pipelineStageRepository.CreatePipelineStageStep(refPluginStep, tx)
 			if err != nil {
@@ -936,8 +970,17 @@ func (impl *PipelineStageServiceImpl) CreateConditionsEntryInDb(stepId int, cond
 
 // UpdatePipelineStage and related methods starts
 func (impl *PipelineStageServiceImpl) UpdatePipelineStage(stageReq *bean.PipelineStageDto, stageType repository.PipelineStageType, pipelineId int, userId int32) error {
+	err := stageStep.ValidateStageStepDependencies(stageReq, stageType)
+	if err != nil {
+		impl.logger.Errorw("invalid step dependencies in pipeline stage", "err", err, "stageType", stageType, "pipelineId", pipelineId)
+		return err
+	}
+	err = stageStep.ValidateStageStepRetryPolicies(stageReq)
+	if err != nil {
+		impl.logger.Errorw("invalid step retry policies in pipeline stage", "err", err, "stageType", stageType, "pipelineId", pipelineId)
+		return err
+	}
 	var stageOld *repository.PipelineStage
-	var err error
 	if stageType == repository.PIPELINE_STAGE_TYPE_PRE_CI || stageType == repository.PIPELINE_STAGE_TYPE_POST_CI {
 		//getting stage by stageType and ciPipelineId
 		stageOld, err = impl.pipelineStageRepository.GetCiStageByCiPipelineIdAndStageType(pipelineId, stageType)
@@ -975,6 +1018,7 @@ func (impl *PipelineStageServiceImpl) UpdatePipelineStage(stageReq *bean.Pipelin
 		stageUpdateReq := stageOld
 		stageUpdateReq.Name = stageReq.Name
 		stageUpdateReq.Description = stageReq.Description
+		stageUpdateReq.ExecutionMode = stageReq.ExecutionMode
 		stageUpdateReq.UpdatedBy = userId
 		stageUpdateReq.UpdatedOn = time.Now()
 		_, err = impl.pipelineStageRepository.UpdatePipelineStage(stageUpdateReq)
@@ -1126,15 +1170,16 @@ func (impl *PipelineStageServiceImpl) UpdateStageSteps(steps []*bean.PipelineSta
 			return err
 		}
 		stepUpdateReq := &repository.PipelineStageStep{
-			Id:                  step.Id,
-			PipelineStageId:     stageId,
-			Name:                step.Name,
-			Description:         step.Description,
-			Index:               step.Index,
-			StepType:            step.StepType,
-			OutputDirectoryPath: step.OutputDirectoryPath,
-			DependentOnStep:     dependentOnStep,
-			Deleted:             false,
+			Id:                   step.Id,
+			PipelineStageId:      stageId,
+			Name:                 step.Name,
+			Description:          step.Description,
+			Index:                step.Index,
+			StepType:             step.StepType,
+			OutputDirectoryPath:  step.OutputDirectoryPath,
+			DependentOnStep:      dependentOnStep,
+			DependsOnStepIndexes: step.DependsOn,
+			Deleted:              false,
 			AuditLog: sql.AuditLog{
 				CreatedOn: savedStep.CreatedOn,
 				CreatedBy: savedStep.CreatedBy,
@@ -1142,6 +1187,10 @@ func (impl *PipelineStageServiceImpl) UpdateStageSteps(steps []*bean.PipelineSta
 				UpdatedBy: userId,
 			},
 			TriggerIfParentStageFail: step.TriggerIfParentStageFail,
+			MaxRetries:               step.MaxRetries,
+			RetryBackoffSeconds:      step.RetryBackoffSeconds,
+			TimeoutSeconds:           step.TimeoutSeconds,
+			ContinueOnFailure:        step.ContinueOnFailure,
 		}
 		var inputVariables []*bean.StepVariableDto
 		var outputVariables []*bean.StepVariableDto
@@ -1720,6 +1769,13 @@ func (impl *PipelineStageServiceImpl) BuildPipelineStageDataForWfRequest(pipelin
 		impl.logger.Errorw("error in getting all steps by stageId", "err", err, "stageId", pipelineStage.Id)
 		return nil, nil, err
 	}
+	var stepIndexes []int
+	declaredDependencies := make(map[int][]int)
+	for _, step := range steps {
+		stepIndexes = append(stepIndexes, step.Index)
+		declaredDependencies[step.Index] = step.DependsOnStepIndexes
+	}
+	dependencies := stageStep.GetStepDependencies(pipelineStage.ExecutionMode, stepIndexes, declaredDependencies)
 	var stepsData []*bean.StepObject
 	var refPluginIds []int
 	for _, step := range steps {
@@ -1728,6 +1784,7 @@ func (impl *PipelineStageServiceImpl) BuildPipelineStageDataForWfRequest(pipelin
 			impl.logger.Errorw("error in getting pipeline step data for WF request", "err", err)
 			return nil, nil, err
 		}
+		stepData.DependsOn = dependencies[step.Index]
 		if step.StepType == repository.PIPELINE_STEP_TYPE_REF_PLUGIN {
 			refPluginIds = append(refPluginIds, stepData.RefPluginId)
 		}
@@ -1814,6 +1871,10 @@ func (impl *PipelineStageServiceImpl) BuildPipelineStepDataForWfRequest(step *re
 		StepType:                 string(step.StepType),
 		ArtifactPaths:            step.OutputDirectoryPath,
 		TriggerIfParentStageFail: step.TriggerIfParentStageFail,
+		MaxRetries:               step.MaxRetries,
+		RetryBackoffSeconds:      step.RetryBackoffSeconds,
+		TimeoutSeconds:           step.TimeoutSeconds,
+		ContinueOnFailure:        step.ContinueOnFailure,
 	}
 	if step.StepType == repository.PIPELINE_STEP_TYPE_INLINE {
 		//get script and mapping data
@@ -28,6 +28,13 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	util2 "github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/app"
+	"github.com/devtron-labs/devtron/pkg/pipeline/artifactOutput"
+	artifactOutputBean "github.com/devtron-labs/devtron/pkg/pipeline/artifactOutput/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/buildMatrix"
+	"github.com/devtron-labs/devtron/pkg/pipeline/stepStatus"
+	stepStatusBean "github.com/devtron-labs/devtron/pkg/pipeline/stepStatus/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/testReport"
+	testReportBean "github.com/devtron-labs/devtron/pkg/pipeline/testReport/bean"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/devtron-labs/devtron/util/event"
 	"github.com/go-pg/pg"
@@ -39,15 +46,17 @@ import (
 )
 
 type CiArtifactWebhookRequest struct {
-	Image              string          `json:"image"`
-	ImageDigest        string          `json:"imageDigest"`
-	MaterialInfo       json.RawMessage `json:"materialInfo"`
-	DataSource         string          `json:"dataSource"`
-	PipelineName       string          `json:"pipelineName"`
-	WorkflowId         *int            `json:"workflowId"`
-	UserId             int32           `json:"userId"`
-	IsArtifactUploaded bool            `json:"isArtifactUploaded"`
-	FailureReason      string          `json:"failureReason"`
+	Image              string                               `json:"image"`
+	ImageDigest        string                               `json:"imageDigest"`
+	MaterialInfo       json.RawMessage                      `json:"materialInfo"`
+	DataSource         string                               `json:"dataSource"`
+	PipelineName       string                               `json:"pipelineName"`
+	WorkflowId         *int                                 `json:"workflowId"`
+	UserId             int32                                `json:"userId"`
+	IsArtifactUploaded bool                                 `json:"isArtifactUploaded"`
+	FailureReason      string                               `json:"failureReason"`
+	StepStatuses       []*stepStatusBean.StepStatus         `json:"stepStatuses,omitempty"`
+	ArtifactOutputs    []*artifactOutputBean.ArtifactOutput `json:"artifactOutputs,omitempty"`
 }
 
 type WebhookService interface {
@@ -58,16 +67,20 @@ type WebhookService interface {
 }
 
 type WebhookServiceImpl struct {
-	ciArtifactRepository repository.CiArtifactRepository
-	ciConfig             *CiConfig
-	logger               *zap.SugaredLogger
-	ciPipelineRepository pipelineConfig.CiPipelineRepository
-	ciWorkflowRepository pipelineConfig.CiWorkflowRepository
-	appService           app.AppService
-	eventClient          client.EventClient
-	eventFactory         client.EventFactory
-	workflowDagExecutor  WorkflowDagExecutor
-	ciHandler            CiHandler
+	ciArtifactRepository  repository.CiArtifactRepository
+	ciConfig              *CiConfig
+	logger                *zap.SugaredLogger
+	ciPipelineRepository  pipelineConfig.CiPipelineRepository
+	ciWorkflowRepository  pipelineConfig.CiWorkflowRepository
+	appService            app.AppService
+	eventClient           client.EventClient
+	eventFactory          client.EventFactory
+	workflowDagExecutor   WorkflowDagExecutor
+	ciHandler             CiHandler
+	testReportService     testReport.TestReportService
+	buildMatrixService    buildMatrix.BuildMatrixService
+	stepStatusService     stepStatus.StepStatusService
+	artifactOutputService artifactOutput.ArtifactOutputService
 }
 
 func NewWebhookServiceImpl(
@@ -77,17 +90,25 @@ func NewWebhookServiceImpl(
 	appService app.AppService, eventClient client.EventClient,
 	eventFactory client.EventFactory,
 	ciWorkflowRepository pipelineConfig.CiWorkflowRepository,
-	workflowDagExecutor WorkflowDagExecutor, ciHandler CiHandler) *WebhookServiceImpl {
+	workflowDagExecutor WorkflowDagExecutor, ciHandler CiHandler,
+	testReportService testReport.TestReportService,
+	buildMatrixService buildMatrix.BuildMatrixService,
+	stepStatusService stepStatus.StepStatusService,
+	artifactOutputService artifactOutput.ArtifactOutputService) *WebhookServiceImpl {
 	webhookHandler := &WebhookServiceImpl{
-		ciArtifactRepository: ciArtifactRepository,
-		logger:               logger,
-		ciPipelineRepository: ciPipelineRepository,
-		appService:           appService,
-		eventClient:          eventClient,
-		eventFactory:         eventFactory,
-		ciWorkflowRepository: ciWorkflowRepository,
-		workflowDagExecutor:  workflowDagExecutor,
-		ciHandler:            ciHandler,
+		ciArtifactRepository:  ciArtifactRepository,
+		logger:                logger,
+		ciPipelineRepository:  ciPipelineRepository,
+		appService:            appService,
+		eventClient:           eventClient,
+		eventFactory:          eventFactory,
+		ciWorkflowRepository:  ciWorkflowRepository,
+		workflowDagExecutor:   workflowDagExecutor,
+		ciHandler:             ciHandler,
+		testReportService:     testReportService,
+		buildMatrixService:    buildMatrixService,
+		stepStatusService:     stepStatusService,
+		artifactOutputService: artifactOutputService,
 	}
 	config, err := GetCiConfig()
 	if err != nil {
@@ -125,6 +146,62 @@ func (impl WebhookServiceImpl) AuthenticateExternalCiWebhook(apiKey string) (int
 	return id, nil
 }
 
+// saveStepStatuses saves the step statuses reported by the runner, they are not needed to complete the workflow
+func (impl WebhookServiceImpl) saveStepStatuses(workflowId int, statuses []*stepStatusBean.StepStatus) {
+	err := impl.stepStatusService.SaveStepStatuses(stepStatusBean.WorkflowTypeCi, workflowId, statuses)
+	if err != nil {
+		impl.logger.Errorw("error in saving step statuses of ci workflow", "err", err, "workflowId", workflowId)
+	}
+}
+
+// saveArtifactOutputs attaches the step outputs reported by the ci to its artifact, pre/post cd steps referring to an
+// output which is not attached fail when they are triggered. Files are attached only from the artifact location of the
+// ci workflow, an artifact without a workflow gets none.
+func (impl WebhookServiceImpl) saveArtifactOutputs(ciArtifactId int, ciWorkflowId *int, outputs []*artifactOutputBean.ArtifactOutput) {
+	fileKeyPrefix := ""
+	if ciWorkflowId != nil {
+		fileKeyPrefix = fmt.Sprintf("%s/%d/", impl.ciConfig.GetDefaultArtifactKeyPrefix(), *ciWorkflowId)
+	}
+	err := impl.artifactOutputService.SaveArtifactOutputs(ciArtifactId, fileKeyPrefix, outputs)
+	if err != nil {
+		impl.logger.Errorw("error in saving ci artifact outputs", "err", err, "ciArtifactId", ciArtifactId)
+	}
+}
+
+// evaluateTestReports ingests the test reports of the build and returns the reason to fail it for the test pass rate
+// condition of the pipeline, reports which can not be ingested within testReportBean.PassRateEvaluationTimeout do not
+// fail the build
+func (impl WebhookServiceImpl) evaluateTestReports(ciPipelineId int, workflowId int, isArtifactUploaded bool) string {
+	if !isArtifactUploaded {
+		return ""
+	}
+	config, err := impl.testReportService.GetCiPipelineConfig(ciPipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in getting test report config", "err", err, "ciPipelineId", ciPipelineId)
+		return ""
+	}
+	if config == nil {
+		return ""
+	}
+	if !config.Enabled {
+		go impl.ciHandler.IngestTestReports(ciPipelineId, workflowId)
+		return ""
+	}
+	summaries := make(chan *testReportBean.TestRunSummary, 1)
+	go func() {
+		// the errors are logged by the ingestion, there is no summary to evaluate then
+		summary, _ := impl.ciHandler.IngestTestReports(ciPipelineId, workflowId)
+		summaries <- summary
+	}()
+	select {
+	case summary := <-summaries:
+		return testReport.EvaluatePassRate(config, summary)
+	case <-time.After(testReportBean.PassRateEvaluationTimeout):
+		impl.logger.Warnw("test reports not ingested in time to evaluate the pass rate", "ciPipelineId", ciPipelineId, "workflowId", workflowId)
+		return ""
+	}
+}
+
 func (impl WebhookServiceImpl) HandleCiStepFailedEvent(ciPipelineId int, request *CiArtifactWebhookRequest) (err error) {
 
 	savedWorkflow, err := impl.ciWorkflowRepository.FindById(*request.WorkflowId)
@@ -132,6 +209,7 @@ func (impl WebhookServiceImpl) HandleCiStepFailedEvent(ciPipelineId int, request
 		impl.logger.Errorw("cannot get saved wf", "wf ID: ", *request.WorkflowId, "err", err)
 		return err
 	}
+	impl.saveStepStatuses(savedWorkflow.Id, request.StepStatuses)
 
 	pipeline, err := impl.ciPipelineRepository.FindByCiAndAppDetailsById(ciPipelineId)
 	if err != nil {
@@ -151,13 +229,38 @@ func (impl WebhookServiceImpl) HandleCiSuccessEvent(ciPipelineId int, request *C
 			impl.logger.Errorw("cannot get saved wf", "err", err)
 			return 0, err
 		}
+		impl.saveStepStatuses(savedWorkflow.Id, request.StepStatuses)
 		savedWorkflow.Status = string(v1alpha1.NodeSucceeded)
+		failureMessage := impl.evaluateTestReports(ciPipelineId, savedWorkflow.Id, request.IsArtifactUploaded)
+		if len(failureMessage) > 0 {
+			savedWorkflow.Status = string(v1alpha1.NodeFailed)
+			savedWorkflow.Message = failureMessage
+		}
 		impl.logger.Debugw("updating workflow ", "savedWorkflow", savedWorkflow)
 		err = impl.ciWorkflowRepository.UpdateWorkFlow(savedWorkflow)
 		if err != nil {
 			impl.logger.Errorw("update wf failed for id ", "err", err)
 			return 0, err
 		}
+		if len(failureMessage) > 0 {
+			// the image of the failed build is not saved so that it can not be deployed
+			impl.logger.Infow("ci failed for test pass rate", "wfId", savedWorkflow.Id, "message", failureMessage)
+			return 0, fmt.Errorf("ci failed: %s", failureMessage)
+		}
+		cellCompletion, err := impl.buildMatrixService.HandleCellSuccess(savedWorkflow.Id, request.Image, request.ImageDigest)
+		if err != nil {
+			impl.logger.Errorw("error in handling build matrix cell", "wfId", savedWorkflow.Id, "err", err)
+			return 0, err
+		}
+		if cellCompletion.IsMatrixCell && !cellCompletion.SaveArtifact {
+			// the manifest list of the run is saved with its last cell
+			impl.logger.Infow("build matrix cell completed", "wfId", savedWorkflow.Id)
+			return 0, nil
+		}
+		if len(cellCompletion.Image) > 0 {
+			request.Image = cellCompletion.Image
+			request.ImageDigest = cellCompletion.ImageDigest
+		}
 	}
 
 	pipeline, err := impl.ciPipelineRepository.FindByCiAndAppDetailsById(ciPipelineId)
@@ -206,6 +309,7 @@ func (impl WebhookServiceImpl) HandleCiSuccessEvent(ciPipelineId int, request *C
 		impl.logger.Errorw("error in saving material", "err", err)
 		return 0, err
 	}
+	impl.saveArtifactOutputs(artifact.Id, request.WorkflowId, request.ArtifactOutputs)
 
 	childrenCi, err := impl.ciPipelineRepository.FindByParentCiPipelineId(ciPipelineId)
 	if err != nil && !util2.IsErrNoRows(err) {
@@ -332,6 +436,8 @@ func (impl WebhookServiceImpl) HandleExternalCiWebhook(externalCiId int, request
 		impl.logger.Errorw("error in saving material", "err", err)
 		return 0, err
 	}
+	// the workflow of an external ci is not one of ours, so none of its files are attached
+	impl.saveArtifactOutputs(artifact.Id, nil, request.ArtifactOutputs)
 
 	hasAnyTriggered, err := impl.workflowDagExecutor.HandleWebhookExternalCiEvent(artifact, request.UserId, externalCiId, auth)
 	if err != nil {
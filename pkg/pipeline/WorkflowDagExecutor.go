@@ -26,8 +26,11 @@ import (
 	gitSensorClient "github.com/devtron-labs/devtron/client/gitSensor"
 	"github.com/devtron-labs/devtron/pkg/app/status"
 	"github.com/devtron-labs/devtron/pkg/k8s"
+	"github.com/devtron-labs/devtron/pkg/pipeline/artifactOutput"
 	bean3 "github.com/devtron-labs/devtron/pkg/pipeline/bean"
 	repository4 "github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	"github.com/devtron-labs/devtron/pkg/pipeline/stepStatus"
+	stepStatusBean "github.com/devtron-labs/devtron/pkg/pipeline/stepStatus/bean"
 	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
 	"github.com/devtron-labs/devtron/pkg/variables"
 	repository5 "github.com/devtron-labs/devtron/pkg/variables/repository"
@@ -118,6 +121,8 @@ type WorkflowDagExecutorImpl struct {
 	config                        *CdConfig
 
 	variableSnapshotHistoryService variables.VariableSnapshotHistoryService
+	stepStatusService              stepStatus.StepStatusService
+	artifactOutputService          artifactOutput.ArtifactOutputService
 }
 
 const (
@@ -167,6 +172,8 @@ type CdStageCompleteEvent struct {
 	ArtifactLocation string                       `json:"artifactLocation"`
 	PipelineName     string                       `json:"pipelineName"`
 	CiArtifactDTO    pipelineConfig.CiArtifactDTO `json:"ciArtifactDTO"`
+	StepStatuses     []*stepStatusBean.StepStatus `json:"stepStatuses,omitempty"`
+	FailureReason    string                       `json:"failureReason,omitempty"`
 }
 
 type GitMetadata struct {
@@ -211,6 +218,8 @@ func NewWorkflowDagExecutorImpl(Logger *zap.SugaredLogger, pipelineRepository pi
 	appLabelRepository pipelineConfig.AppLabelRepository, gitSensorGrpcClient gitSensorClient.Client,
 	pipelineStageService PipelineStageService, k8sCommonService k8s.K8sCommonService,
 	variableSnapshotHistoryService variables.VariableSnapshotHistoryService,
+	stepStatusService stepStatus.StepStatusService,
+	artifactOutputService artifactOutput.ArtifactOutputService,
 ) *WorkflowDagExecutorImpl {
 	wde := &WorkflowDagExecutorImpl{logger: Logger,
 		pipelineRepository:             pipelineRepository,
@@ -245,6 +254,8 @@ func NewWorkflowDagExecutorImpl(Logger *zap.SugaredLogger, pipelineRepository pi
 		k8sCommonService:               k8sCommonService,
 		pipelineStageService:           pipelineStageService,
 		variableSnapshotHistoryService: variableSnapshotHistoryService,
+		stepStatusService:              stepStatusService,
+		artifactOutputService:          artifactOutputService,
 	}
 	config, err := GetCdConfig()
 	if err != nil {
@@ -282,6 +293,15 @@ func (impl *WorkflowDagExecutorImpl) Subscribe() error {
 			impl.logger.Errorw("could not get wf runner", "err", err)
 			return
 		}
+		err = impl.stepStatusService.SaveStepStatuses(string(wf.WorkflowType), wf.Id, cdStageCompleteEvent.StepStatuses)
+		if err != nil {
+			impl.logger.Errorw("error in saving step statuses of cd workflow runner", "err", err, "wfrId", wf.Id)
+		}
+		if cdStageCompleteEvent.FailureReason != "" {
+			// the runner reports a failed stage only for its step statuses, the stage is failed by the status update
+			impl.logger.Infow("received cd stage failed event for workflow runner", "wfrId", wf.Id, "failureReason", cdStageCompleteEvent.FailureReason)
+			return
+		}
 		if wf.WorkflowType == bean.CD_WORKFLOW_TYPE_PRE {
 			impl.logger.Debugw("received pre stage success event for workflow runner ", "wfId", strconv.Itoa(wf.Id))
 			err = impl.HandlePreStageSuccessEvent(cdStageCompleteEvent)
@@ -1157,6 +1177,12 @@ func (impl *WorkflowDagExecutorImpl) buildWFRequest(runner *pipelineConfig.CdWor
 		setExtraEnvVariableInDeployStep(postDeploySteps, extraEnvVariables, webhookAndCiData)
 		cdStageWorkflowRequest.PrePostDeploySteps = postDeploySteps
 	}
+	//input variables referring to outputs of pre/post ci steps get the outputs attached to the artifact
+	cdStageWorkflowRequest.CiArtifactOutputFiles, err = impl.artifactOutputService.ResolveCdStepInputs(artifact.Id, artifact.ParentCiArtifact, cdStageWorkflowRequest.PrePostDeploySteps)
+	if err != nil {
+		impl.logger.Errorw("error in resolving step inputs from ci artifact outputs", "err", err, "cdPipelineId", cdPipeline.Id, "artifactId", artifact.Id)
+		return nil, err
+	}
 	cdStageWorkflowRequest.BlobStorageConfigured = runner.BlobStorageEnabled
 	switch cdStageWorkflowRequest.CloudProvider {
 	case BLOB_STORAGE_S3:
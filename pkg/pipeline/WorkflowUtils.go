@@ -13,6 +13,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	bean3 "github.com/devtron-labs/devtron/pkg/bean"
 	repository2 "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	artifactOutputBean "github.com/devtron-labs/devtron/pkg/pipeline/artifactOutput/bean"
 	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
 	"github.com/devtron-labs/devtron/util"
 	v12 "k8s.io/api/core/v1"
@@ -350,22 +351,23 @@ type WorkflowRequest struct {
 	ImageRetryCount            int                               `json:"imageRetryCount"`
 	ImageRetryInterval         int                               `json:"imageRetryInterval"`
 	// Data from CD Workflow service
-	WorkflowRunnerId         int                                 `json:"workflowRunnerId"`
-	CdPipelineId             int                                 `json:"cdPipelineId"`
-	StageYaml                string                              `json:"stageYaml"`
-	ArtifactLocation         string                              `json:"artifactLocation"`
-	CiArtifactDTO            CiArtifactDTO                       `json:"ciArtifactDTO"`
-	CdImage                  string                              `json:"cdImage"`
-	StageType                string                              `json:"stageType"`
-	CdCacheLocation          string                              `json:"cdCacheLocation"`
-	CdCacheRegion            string                              `json:"cdCacheRegion"`
-	WorkflowPrefixForLog     string                              `json:"workflowPrefixForLog"`
-	DeploymentTriggeredBy    string                              `json:"deploymentTriggeredBy,omitempty"`
-	DeploymentTriggerTime    time.Time                           `json:"deploymentTriggerTime,omitempty"`
-	DeploymentReleaseCounter int                                 `json:"deploymentReleaseCounter,omitempty"`
-	WorkflowExecutor         pipelineConfig.WorkflowExecutorType `json:"workflowExecutor"`
-	PrePostDeploySteps       []*bean.StepObject                  `json:"prePostDeploySteps"`
-	CiArtifactLastFetch      time.Time                           `json:"ciArtifactLastFetch"`
+	WorkflowRunnerId         int                                  `json:"workflowRunnerId"`
+	CdPipelineId             int                                  `json:"cdPipelineId"`
+	StageYaml                string                               `json:"stageYaml"`
+	ArtifactLocation         string                               `json:"artifactLocation"`
+	CiArtifactDTO            CiArtifactDTO                        `json:"ciArtifactDTO"`
+	CdImage                  string                               `json:"cdImage"`
+	StageType                string                               `json:"stageType"`
+	CdCacheLocation          string                               `json:"cdCacheLocation"`
+	CdCacheRegion            string                               `json:"cdCacheRegion"`
+	WorkflowPrefixForLog     string                               `json:"workflowPrefixForLog"`
+	DeploymentTriggeredBy    string                               `json:"deploymentTriggeredBy,omitempty"`
+	DeploymentTriggerTime    time.Time                            `json:"deploymentTriggerTime,omitempty"`
+	DeploymentReleaseCounter int                                  `json:"deploymentReleaseCounter,omitempty"`
+	WorkflowExecutor         pipelineConfig.WorkflowExecutorType  `json:"workflowExecutor"`
+	PrePostDeploySteps       []*bean.StepObject                   `json:"prePostDeploySteps"`
+	CiArtifactOutputFiles    []*artifactOutputBean.ArtifactOutput `json:"ciArtifactOutputFiles,omitempty"` //to be downloaded before the steps start
+	CiArtifactLastFetch      time.Time                            `json:"ciArtifactLastFetch"`
 	Type                     bean.WorkflowPipelineType
 	Pipeline                 *pipelineConfig.Pipeline
 	Env                      *repository2.Environment
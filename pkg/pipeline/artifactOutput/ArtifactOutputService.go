@@ -0,0 +1,167 @@
+package artifactOutput
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/pipeline/artifactOutput/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/artifactOutput/repository"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	pipelineStageRepository "github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	"go.uber.org/zap"
+	"path"
+	"strings"
+	"time"
+)
+
+type ArtifactOutputService interface {
+	// SaveArtifactOutputs replaces the outputs of the artifact with the ones reported by the ci, nothing is saved when
+	// the ci reported none. Files are saved only when their key is under fileKeyPrefix, the artifact location of the ci
+	// workflow, so no file outside it is handed to a cd runner.
+	SaveArtifactOutputs(ciArtifactId int, fileKeyPrefix string, outputs []*bean.ArtifactOutput) error
+	// GetArtifactOutputs returns the outputs of the artifact, an artifact of a linked ci has the outputs of the
+	// artifact it is linked to
+	GetArtifactOutputs(ciArtifactId int, parentCiArtifactId int) ([]*bean.ArtifactOutput, error)
+	// ResolveCdStepInputs sets the input variables of pre/post cd steps which refer to an output of a pre/post ci step
+	// to the output attached to the artifact, and returns the files of the artifact for the runner to download
+	ResolveCdStepInputs(ciArtifactId int, parentCiArtifactId int, steps []*pipelineBean.StepObject) ([]*bean.ArtifactOutput, error)
+}
+
+type ArtifactOutputServiceImpl struct {
+	logger                   *zap.SugaredLogger
+	artifactOutputRepository repository.ArtifactOutputRepository
+}
+
+func NewArtifactOutputServiceImpl(logger *zap.SugaredLogger, artifactOutputRepository repository.ArtifactOutputRepository) *ArtifactOutputServiceImpl {
+	return &ArtifactOutputServiceImpl{
+		logger:                   logger,
+		artifactOutputRepository: artifactOutputRepository,
+	}
+}
+
+func (impl *ArtifactOutputServiceImpl) SaveArtifactOutputs(ciArtifactId int, fileKeyPrefix string, outputs []*bean.ArtifactOutput) error {
+	if len(outputs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	models := make([]*repository.CiArtifactOutput, 0, len(outputs))
+	for _, output := range outputs {
+		if !isValidArtifactOutput(output, fileKeyPrefix) {
+			impl.logger.Warnw("ignoring invalid ci artifact output", "ciArtifactId", ciArtifactId, "output", output)
+			continue
+		}
+		models = append(models, &repository.CiArtifactOutput{
+			CiArtifactId: ciArtifactId,
+			StageType:    output.StageType,
+			StepIndex:    output.StepIndex,
+			StepName:     output.StepName,
+			Name:         output.Name,
+			OutputType:   output.OutputType,
+			Format:       output.Format,
+			Value:        output.Value,
+			CreatedOn:    now,
+		})
+	}
+	err := impl.artifactOutputRepository.SaveArtifactOutputs(ciArtifactId, models)
+	if err != nil {
+		impl.logger.Errorw("error in saving ci artifact outputs", "err", err, "ciArtifactId", ciArtifactId)
+		return err
+	}
+	return nil
+}
+
+func (impl *ArtifactOutputServiceImpl) GetArtifactOutputs(ciArtifactId int, parentCiArtifactId int) ([]*bean.ArtifactOutput, error) {
+	if parentCiArtifactId > 0 {
+		ciArtifactId = parentCiArtifactId
+	}
+	models, err := impl.artifactOutputRepository.FindByCiArtifactId(ciArtifactId)
+	if err != nil {
+		impl.logger.Errorw("error in getting ci artifact outputs", "err", err, "ciArtifactId", ciArtifactId)
+		return nil, err
+	}
+	outputs := make([]*bean.ArtifactOutput, 0, len(models))
+	for _, model := range models {
+		outputs = append(outputs, &bean.ArtifactOutput{
+			StageType:  model.StageType,
+			StepIndex:  model.StepIndex,
+			StepName:   model.StepName,
+			Name:       model.Name,
+			OutputType: model.OutputType,
+			Format:     model.Format,
+			Value:      model.Value,
+		})
+	}
+	return outputs, nil
+}
+
+func (impl *ArtifactOutputServiceImpl) ResolveCdStepInputs(ciArtifactId int, parentCiArtifactId int, steps []*pipelineBean.StepObject) ([]*bean.ArtifactOutput, error) {
+	if len(steps) == 0 {
+		return nil, nil
+	}
+	outputs, err := impl.GetArtifactOutputs(ciArtifactId, parentCiArtifactId)
+	if err != nil {
+		return nil, err
+	}
+	err = resolveStepInputs(steps, outputs)
+	if err != nil {
+		impl.logger.Errorw("error in resolving cd step inputs from ci artifact outputs", "err", err, "ciArtifactId", ciArtifactId)
+		return nil, err
+	}
+	var files []*bean.ArtifactOutput
+	for _, output := range outputs {
+		if output.OutputType == bean.OutputTypeFile {
+			files = append(files, output)
+		}
+	}
+	return files, nil
+}
+
+// resolveStepInputs turns the input variables referring to a ci step into values, a variable whose output is not
+// attached to the artifact keeps its own value and fails the stage when it has none
+func resolveStepInputs(steps []*pipelineBean.StepObject, outputs []*bean.ArtifactOutput) error {
+	outputValues := make(map[string]string)
+	for _, output := range outputs {
+		outputValues[getOutputKey(output.StageType, output.StepIndex, output.Name)] = output.Value
+	}
+	for _, step := range steps {
+		for _, variable := range step.InputVars {
+			var stageType pipelineStageRepository.PipelineStageType
+			if variable.VariableType == pipelineBean.VARIABLE_TYPE_REF_PRE_CI {
+				stageType = pipelineStageRepository.PIPELINE_STAGE_TYPE_PRE_CI
+			} else if variable.VariableType == pipelineBean.VARIABLE_TYPE_REF_POST_CI {
+				stageType = pipelineStageRepository.PIPELINE_STAGE_TYPE_POST_CI
+			} else {
+				continue
+			}
+			value, ok := outputValues[getOutputKey(string(stageType), variable.ReferenceVariableStepIndex, variable.ReferenceVariableName)]
+			if !ok && len(variable.Value) == 0 {
+				return fmt.Errorf("input variable %q of step %q refers to output %q of step index %d of %s stage which is not attached to the artifact",
+					variable.Name, step.Name, variable.ReferenceVariableName, variable.ReferenceVariableStepIndex, stageType)
+			}
+			if ok {
+				variable.Value = value
+			}
+			variable.VariableType = pipelineBean.VARIABLE_TYPE_VALUE
+			variable.ReferenceVariableName = ""
+			variable.ReferenceVariableStepIndex = 0
+		}
+	}
+	return nil
+}
+
+func getOutputKey(stageType string, stepIndex int, name string) string {
+	return fmt.Sprintf("%s/%d/%s", stageType, stepIndex, name)
+}
+
+// isValidArtifactOutput checks the output reported by the ci, the key of a file must be under fileKeyPrefix without
+// any relative element which could leave it
+func isValidArtifactOutput(output *bean.ArtifactOutput, fileKeyPrefix string) bool {
+	if output == nil || len(output.Name) == 0 {
+		return false
+	}
+	if output.StageType != string(pipelineStageRepository.PIPELINE_STAGE_TYPE_PRE_CI) && output.StageType != string(pipelineStageRepository.PIPELINE_STAGE_TYPE_POST_CI) {
+		return false
+	}
+	if output.OutputType == bean.OutputTypeFile {
+		return len(fileKeyPrefix) > 0 && path.Clean(output.Value) == output.Value && strings.HasPrefix(output.Value, fileKeyPrefix)
+	}
+	return output.OutputType == bean.OutputTypeVariable
+}
@@ -0,0 +1,74 @@
+package artifactOutput
+
+import (
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/pipeline/artifactOutput/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/artifactOutput/repository"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type artifactOutputRepositoryStub struct {
+	repository.ArtifactOutputRepository
+	saved map[int][]*repository.CiArtifactOutput
+}
+
+func (stub *artifactOutputRepositoryStub) SaveArtifactOutputs(ciArtifactId int, outputs []*repository.CiArtifactOutput) error {
+	stub.saved[ciArtifactId] = outputs
+	return nil
+}
+
+func (stub *artifactOutputRepositoryStub) FindByCiArtifactId(ciArtifactId int) ([]*repository.CiArtifactOutput, error) {
+	return stub.saved[ciArtifactId], nil
+}
+
+func getCdTestStep(inputVars ...*pipelineBean.VariableObject) *pipelineBean.StepObject {
+	return &pipelineBean.StepObject{Name: "coverage-gate", Index: 1, StepType: "INLINE", InputVars: inputVars}
+}
+
+func TestResolveCdStepInputs(t *testing.T) {
+	artifactOutputRepository := &artifactOutputRepositoryStub{saved: make(map[int][]*repository.CiArtifactOutput)}
+	logger, _ := util.NewSugardLogger()
+	service := NewArtifactOutputServiceImpl(logger, artifactOutputRepository)
+
+	err := service.SaveArtifactOutputs(10, "ci-artifacts/10/", []*bean.ArtifactOutput{
+		{StageType: "POST_CI", StepIndex: 2, StepName: "test", Name: "COVERAGE", OutputType: bean.OutputTypeVariable, Format: "NUMBER", Value: "82.5"},
+		{StageType: "POST_CI", StepIndex: 3, StepName: "sbom", Name: "SBOM", OutputType: bean.OutputTypeFile, Value: "ci-artifacts/10/sbom.json"},
+		// files of another workflow are not attached
+		{StageType: "POST_CI", StepIndex: 3, Name: "IGNORED", OutputType: bean.OutputTypeFile, Value: "ci-artifacts/9/sbom.json"},
+		{StageType: "POST_CI", StepIndex: 3, Name: "IGNORED", OutputType: bean.OutputTypeFile, Value: "ci-artifacts/10/../9/sbom.json"},
+		{StageType: "POST_CI", StepIndex: 3, Name: "IGNORED", OutputType: bean.OutputTypeFile, Value: "ci-artifacts/100/sbom.json"},
+		{StageType: "PRE_CD", StepIndex: 1, Name: "IGNORED", OutputType: bean.OutputTypeVariable},
+		{StageType: "POST_CI", StepIndex: 1, Name: "IGNORED", OutputType: "DIRECTORY"},
+		nil,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, artifactOutputRepository.saved[10], 2)
+
+	coverage := &pipelineBean.VariableObject{Name: "COVERAGE", Format: "NUMBER", VariableType: pipelineBean.VARIABLE_TYPE_REF_POST_CI, ReferenceVariableName: "COVERAGE", ReferenceVariableStepIndex: 2}
+	sbom := &pipelineBean.VariableObject{Name: "SBOM_KEY", Format: "STRING", VariableType: pipelineBean.VARIABLE_TYPE_REF_POST_CI, ReferenceVariableName: "SBOM", ReferenceVariableStepIndex: 3}
+	threshold := &pipelineBean.VariableObject{Name: "THRESHOLD", Format: "NUMBER", VariableType: pipelineBean.VARIABLE_TYPE_VALUE, Value: "70"}
+	// an artifact of a linked ci has the outputs of the artifact it is linked to
+	files, err := service.ResolveCdStepInputs(11, 10, []*pipelineBean.StepObject{getCdTestStep(coverage, sbom, threshold)})
+	assert.NoError(t, err)
+	assert.Equal(t, "82.5", coverage.Value)
+	assert.Equal(t, pipelineBean.VariableType(pipelineBean.VARIABLE_TYPE_VALUE), coverage.VariableType)
+	assert.Empty(t, coverage.ReferenceVariableName)
+	assert.Equal(t, "ci-artifacts/10/sbom.json", sbom.Value)
+	assert.Equal(t, "70", threshold.Value)
+	if assert.Len(t, files, 1) {
+		assert.Equal(t, "SBOM", files[0].Name)
+	}
+
+	t.Run("output not attached", func(t *testing.T) {
+		missing := &pipelineBean.VariableObject{Name: "LINT_SCORE", VariableType: pipelineBean.VARIABLE_TYPE_REF_PRE_CI, ReferenceVariableName: "SCORE", ReferenceVariableStepIndex: 1}
+		_, err := service.ResolveCdStepInputs(10, 0, []*pipelineBean.StepObject{getCdTestStep(missing)})
+		assert.EqualError(t, err, `input variable "LINT_SCORE" of step "coverage-gate" refers to output "SCORE" of step index 1 of PRE_CI stage which is not attached to the artifact`)
+
+		missing.Value = "0"
+		_, err = service.ResolveCdStepInputs(10, 0, []*pipelineBean.StepObject{getCdTestStep(missing)})
+		assert.NoError(t, err)
+		assert.Equal(t, "0", missing.Value)
+	})
+}
@@ -0,0 +1,18 @@
+package bean
+
+const (
+	OutputTypeVariable = "VARIABLE"
+	OutputTypeFile     = "FILE"
+)
+
+// ArtifactOutput is an output variable or file of a pre/post ci step attached to the artifact built by the ci. Value of
+// a file is its key in the blob storage, the runner of a pre/post cd stage downloads the files before its steps start.
+type ArtifactOutput struct {
+	StageType  string `json:"stageType"` //PRE_CI or POST_CI
+	StepIndex  int    `json:"stepIndex"`
+	StepName   string `json:"stepName,omitempty"`
+	Name       string `json:"name"`
+	OutputType string `json:"outputType"`       //VARIABLE or FILE
+	Format     string `json:"format,omitempty"` //STRING, NUMBER, BOOL, DATE of a variable
+	Value      string `json:"value"`
+}
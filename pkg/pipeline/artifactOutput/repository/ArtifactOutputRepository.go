@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type CiArtifactOutput struct {
+	tableName    struct{}  `sql:"ci_artifact_output" pg:",discard_unknown_columns"`
+	Id           int       `sql:"id,pk"`
+	CiArtifactId int       `sql:"ci_artifact_id,notnull"`
+	StageType    string    `sql:"stage_type,notnull"`
+	StepIndex    int       `sql:"step_index,notnull"`
+	StepName     string    `sql:"step_name"`
+	Name         string    `sql:"name,notnull"`
+	OutputType   string    `sql:"output_type,notnull"`
+	Format       string    `sql:"format"`
+	Value        string    `sql:"value"`
+	CreatedOn    time.Time `sql:"created_on,type:timestamptz"`
+}
+
+type ArtifactOutputRepository interface {
+	// SaveArtifactOutputs replaces the saved outputs of the artifact
+	SaveArtifactOutputs(ciArtifactId int, outputs []*CiArtifactOutput) error
+	FindByCiArtifactId(ciArtifactId int) ([]*CiArtifactOutput, error)
+}
+
+type ArtifactOutputRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewArtifactOutputRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ArtifactOutputRepositoryImpl {
+	return &ArtifactOutputRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ArtifactOutputRepositoryImpl) SaveArtifactOutputs(ciArtifactId int, outputs []*CiArtifactOutput) error {
+	tx, err := impl.dbConnection.Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	_, err = tx.Model((*CiArtifactOutput)(nil)).
+		Where("ci_artifact_id = ?", ciArtifactId).
+		Delete()
+	if err != nil {
+		impl.logger.Errorw("error in deleting ci artifact outputs", "err", err, "ciArtifactId", ciArtifactId)
+		return err
+	}
+	if len(outputs) > 0 {
+		err = tx.Insert(&outputs)
+		if err != nil {
+			impl.logger.Errorw("error in saving ci artifact outputs", "err", err, "ciArtifactId", ciArtifactId)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (impl *ArtifactOutputRepositoryImpl) FindByCiArtifactId(ciArtifactId int) ([]*CiArtifactOutput, error) {
+	var outputs []*CiArtifactOutput
+	err := impl.dbConnection.Model(&outputs).
+		Where("ci_artifact_id = ?", ciArtifactId).
+		Order("id ASC").Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting ci artifact outputs", "err", err, "ciArtifactId", ciArtifactId)
+		return nil, err
+	}
+	return outputs, nil
+}
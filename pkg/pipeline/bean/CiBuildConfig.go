@@ -40,6 +40,9 @@ type DockerBuildConfig struct {
 	UseBuildx              bool                `json:"useBuildx"`
 	BuildxProvenanceMode   string              `json:"buildxProvenanceMode"`
 	BuildxK8sDriverOptions []map[string]string `json:"buildxK8SDriverOptions,omitempty"`
+	// BuildxCacheFrom and BuildxCacheTo are the --cache-from and --cache-to values of a build using a registry cache
+	BuildxCacheFrom []string `json:"buildxCacheFrom,omitempty"`
+	BuildxCacheTo   string   `json:"buildxCacheTo,omitempty"`
 }
 
 type BuildPackConfig struct {
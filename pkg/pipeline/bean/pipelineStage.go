@@ -13,6 +13,8 @@ type PipelineStageDto struct {
 	Type        repository.PipelineStageType `json:"type,omitempty" validate:"omitempty,oneof=PRE_CI POST_CI PRE_CD POST_CD"`
 	Steps       []*PipelineStageStepDto      `json:"steps"`
 	TriggerType pipelineConfig.TriggerType   `json:"triggerType,omitempty"`
+	// ExecutionMode SEQUENTIAL runs the steps in index order, DAG starts each step once the steps it depends on are done
+	ExecutionMode repository.PipelineStageExecutionMode `json:"executionMode,omitempty" validate:"omitempty,oneof=SEQUENTIAL DAG"`
 }
 
 type PipelineStageStepDto struct {
@@ -25,6 +27,13 @@ type PipelineStageStepDto struct {
 	InlineStepDetail         *InlineStepDetailDto        `json:"inlineStepDetail"`
 	RefPluginStepDetail      *RefPluginStepDetailDto     `json:"pluginRefStepDetail"`
 	TriggerIfParentStageFail bool                        `json:"triggerIfParentStageFail"`
+	DependsOn                []int                       `json:"dependsOn,omitempty"` //indexes of the steps to be done before this step, only for DAG execution mode
+	// MaxRetries reruns a failed or timed out step, waiting RetryBackoffSeconds before the first retry and twice as
+	// long before each next one. TimeoutSeconds limits each attempt, the workflow timeout still applies to the stage.
+	MaxRetries          int  `json:"maxRetries,omitempty"`
+	RetryBackoffSeconds int  `json:"retryBackoffSeconds,omitempty"`
+	TimeoutSeconds      int  `json:"timeoutSeconds,omitempty"`
+	ContinueOnFailure   bool `json:"continueOnFailure,omitempty"` //the stage goes on when the step fails after its retries
 }
 
 type InlineStepDetailDto struct {
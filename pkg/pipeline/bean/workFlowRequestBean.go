@@ -57,6 +57,11 @@ type StepObject struct {
 	ExtraVolumeMounts        []*MountPath       `json:"extraVolumeMounts"` // filePathMapping
 	ArtifactPaths            []string           `json:"artifactPaths"`
 	TriggerIfParentStageFail bool               `json:"triggerIfParentStageFail"`
+	DependsOn                []int              `json:"dependsOn,omitempty"` //indexes of the steps after which the step starts, steps without it start with the stage
+	MaxRetries               int                `json:"maxRetries,omitempty"`
+	RetryBackoffSeconds      int                `json:"retryBackoffSeconds,omitempty"` //before the first retry, doubled for each next retry
+	TimeoutSeconds           int                `json:"timeoutSeconds,omitempty"`      //of each attempt
+	ContinueOnFailure        bool               `json:"continueOnFailure,omitempty"`
 }
 
 type VariableObject struct {
@@ -0,0 +1,359 @@
+package buildCache
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/dockerRegistry"
+	"github.com/devtron-labs/devtron/pkg/pipeline/buildCache/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/buildCache/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	cacheTagPrefix    = "buildcache"
+	maxImageTagLength = 128
+	registryTimeout   = time.Minute
+)
+
+var (
+	invalidTagCharRegex = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+	repositoryRegex     = regexp.MustCompile(`^[a-z0-9]+([._/-][a-z0-9]+)*$`)
+)
+
+type BuildCacheService interface {
+	// GetConfig returns the blob storage cache of the pipeline when no cache was configured for it
+	GetConfig(ciPipelineId int) (*bean.BuildCacheConfig, error)
+	SaveConfig(config *bean.BuildCacheConfig, userId int32) (*bean.BuildCacheConfig, error)
+	// GetBuildCacheRefs returns the registry cache of a build and records its use, nil when the pipeline does not use a
+	// registry cache
+	GetBuildCacheRefs(request *bean.BuildCacheRequest) (*bean.BuildCacheRefs, error)
+	// GetCaches returns the registry caches used by the pipeline with their state in the registry
+	GetCaches(ciPipelineId int, appId int) ([]*bean.BuildCache, error)
+	// PurgeCaches deletes a registry cache used by the pipeline, all of them when cacheId is 0
+	PurgeCaches(ciPipelineId int, appId int, cacheId int) (*bean.PurgeResult, error)
+}
+
+type BuildCacheServiceImpl struct {
+	logger                        *zap.SugaredLogger
+	buildCacheRepository          repository.BuildCacheRepository
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository
+}
+
+func NewBuildCacheServiceImpl(logger *zap.SugaredLogger, buildCacheRepository repository.BuildCacheRepository,
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository) *BuildCacheServiceImpl {
+	return &BuildCacheServiceImpl{
+		logger:                        logger,
+		buildCacheRepository:          buildCacheRepository,
+		dockerArtifactStoreRepository: dockerArtifactStoreRepository,
+	}
+}
+
+func (impl *BuildCacheServiceImpl) GetConfig(ciPipelineId int) (*bean.BuildCacheConfig, error) {
+	model, err := impl.buildCacheRepository.FindConfigByCiPipelineId(ciPipelineId)
+	if err == pg.ErrNoRows {
+		return &bean.BuildCacheConfig{
+			CiPipelineId: ciPipelineId,
+			Backend:      bean.BackendBlobStorage,
+			Scope:        bean.ScopePipeline,
+			Mode:         bean.ModeMax,
+		}, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in getting build cache config", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	return &bean.BuildCacheConfig{
+		CiPipelineId:    model.CiPipelineId,
+		Backend:         model.Backend,
+		Scope:           model.Scope,
+		Mode:            model.Mode,
+		CacheRepository: model.CacheRepository,
+	}, nil
+}
+
+func (impl *BuildCacheServiceImpl) SaveConfig(config *bean.BuildCacheConfig, userId int32) (*bean.BuildCacheConfig, error) {
+	if len(config.CacheRepository) > 0 && !repositoryRegex.MatchString(config.CacheRepository) {
+		message := fmt.Sprintf("invalid cache repository %q", config.CacheRepository)
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, Code: "400", UserMessage: message, InternalMessage: message}
+	}
+	model, err := impl.buildCacheRepository.FindConfigByCiPipelineId(config.CiPipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting build cache config", "err", err, "ciPipelineId", config.CiPipelineId)
+		return nil, err
+	}
+	if err == pg.ErrNoRows {
+		model = &repository.CiPipelineBuildCacheConfig{
+			CiPipelineId:    config.CiPipelineId,
+			Backend:         config.Backend,
+			Scope:           config.Scope,
+			Mode:            config.Mode,
+			CacheRepository: config.CacheRepository,
+			AuditLog:        sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId, UpdatedOn: time.Now(), UpdatedBy: userId},
+		}
+		err = impl.buildCacheRepository.SaveConfig(model)
+	} else {
+		model.Backend = config.Backend
+		model.Scope = config.Scope
+		model.Mode = config.Mode
+		model.CacheRepository = config.CacheRepository
+		model.UpdatedOn = time.Now()
+		model.UpdatedBy = userId
+		err = impl.buildCacheRepository.UpdateConfig(model)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving build cache config", "err", err, "ciPipelineId", config.CiPipelineId)
+		return nil, err
+	}
+	return config, nil
+}
+
+func (impl *BuildCacheServiceImpl) GetBuildCacheRefs(request *bean.BuildCacheRequest) (*bean.BuildCacheRefs, error) {
+	config, err := impl.GetConfig(request.CiPipelineId)
+	if err != nil {
+		return nil, err
+	}
+	if config.Backend != bean.BackendRegistry || len(request.DockerRegistryId) == 0 || len(request.DockerRepository) == 0 {
+		return nil, nil
+	}
+	cacheRepository := request.DockerRepository
+	if len(config.CacheRepository) > 0 {
+		cacheRepository = config.CacheRepository
+	}
+	registryHost := getRegistryHost(request.RegistryURL)
+	tags := getCacheTags(config.Scope, request)
+	refs := getCacheRefs(registryHost, cacheRepository, tags, config.Mode, request.RegistryType)
+
+	// the cache is used before it is exported, its first use records it so that it can be inspected and purged
+	cacheRef := fmt.Sprintf("%s/%s:%s", registryHost, cacheRepository, tags[0])
+	cache, err := impl.buildCacheRepository.FindCacheByRef(cacheRef)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting build cache", "err", err, "cacheRef", cacheRef)
+		return nil, err
+	}
+	if err == pg.ErrNoRows {
+		err = impl.buildCacheRepository.SaveCache(&repository.CiBuildCache{
+			AppId:            request.AppId,
+			CiPipelineId:     request.CiPipelineId,
+			Scope:            config.Scope,
+			DockerRegistryId: request.DockerRegistryId,
+			Repository:       cacheRepository,
+			Tag:              tags[0],
+			CacheRef:         cacheRef,
+			LastCiWorkflowId: request.CiWorkflowId,
+			LastUsedOn:       time.Now(),
+			CreatedOn:        time.Now(),
+		})
+	} else {
+		cache.CiPipelineId = request.CiPipelineId
+		cache.LastCiWorkflowId = request.CiWorkflowId
+		cache.LastUsedOn = time.Now()
+		err = impl.buildCacheRepository.UpdateCache(cache)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving build cache", "err", err, "cacheRef", cacheRef)
+		return nil, err
+	}
+	return refs, nil
+}
+
+func (impl *BuildCacheServiceImpl) GetCaches(ciPipelineId int, appId int) ([]*bean.BuildCache, error) {
+	caches, err := impl.buildCacheRepository.FindCachesOfCiPipeline(ciPipelineId, appId)
+	if err != nil {
+		return nil, err
+	}
+	clients := make(map[string]*dockerRegistry.RegistryApiClient)
+	result := make([]*bean.BuildCache, 0, len(caches))
+	for _, cache := range caches {
+		buildCache := &bean.BuildCache{
+			Id:               cache.Id,
+			CiPipelineId:     cache.CiPipelineId,
+			Scope:            cache.Scope,
+			DockerRegistryId: cache.DockerRegistryId,
+			CacheRef:         cache.CacheRef,
+			LastCiWorkflowId: cache.LastCiWorkflowId,
+			LastUsedOn:       cache.LastUsedOn,
+		}
+		result = append(result, buildCache)
+		client, err := impl.getRegistryClient(clients, cache)
+		if err != nil {
+			buildCache.Error = err.Error()
+			continue
+		}
+		manifest, err := client.GetManifest(cache.Tag)
+		if err == dockerRegistry.ErrManifestNotFound {
+			continue
+		} else if err != nil {
+			buildCache.Error = err.Error()
+			continue
+		}
+		buildCache.Exists = true
+		buildCache.SizeBytes = getCacheSize(manifest.Content)
+	}
+	return result, nil
+}
+
+func (impl *BuildCacheServiceImpl) PurgeCaches(ciPipelineId int, appId int, cacheId int) (*bean.PurgeResult, error) {
+	caches, err := impl.buildCacheRepository.FindCachesOfCiPipeline(ciPipelineId, appId)
+	if err != nil {
+		return nil, err
+	}
+	result := &bean.PurgeResult{Purged: []string{}, Failed: make(map[string]string)}
+	found := false
+	clients := make(map[string]*dockerRegistry.RegistryApiClient)
+	for _, cache := range caches {
+		if cacheId > 0 && cache.Id != cacheId {
+			continue
+		}
+		found = true
+		err = impl.purgeCache(clients, cache)
+		if err != nil {
+			impl.logger.Errorw("error in purging build cache", "err", err, "cacheRef", cache.CacheRef)
+			result.Failed[cache.CacheRef] = err.Error()
+			continue
+		}
+		err = impl.buildCacheRepository.DeleteCache(cache.Id)
+		if err != nil {
+			impl.logger.Errorw("error in deleting build cache", "err", err, "cacheRef", cache.CacheRef)
+			return nil, err
+		}
+		result.Purged = append(result.Purged, cache.CacheRef)
+	}
+	if cacheId > 0 && !found {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusNotFound, Code: "404", UserMessage: "build cache not found"}
+	}
+	return result, nil
+}
+
+// purgeCache deletes the manifest of the cache from the registry, a cache which was never exported is gone already
+func (impl *BuildCacheServiceImpl) purgeCache(clients map[string]*dockerRegistry.RegistryApiClient, cache *repository.CiBuildCache) error {
+	client, err := impl.getRegistryClient(clients, cache)
+	if err != nil {
+		return err
+	}
+	manifest, err := client.GetManifest(cache.Tag)
+	if err == dockerRegistry.ErrManifestNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	err = client.DeleteManifest(manifest.Digest)
+	if err == dockerRegistry.ErrManifestNotFound {
+		return nil
+	}
+	return err
+}
+
+func (impl *BuildCacheServiceImpl) getRegistryClient(clients map[string]*dockerRegistry.RegistryApiClient, cache *repository.CiBuildCache) (*dockerRegistry.RegistryApiClient, error) {
+	key := cache.DockerRegistryId + "/" + cache.Repository
+	if client, ok := clients[key]; ok {
+		return client, nil
+	}
+	store, err := impl.dockerArtifactStoreRepository.FindOne(cache.DockerRegistryId)
+	if err != nil {
+		return nil, fmt.Errorf("error in getting registry %s: %w", cache.DockerRegistryId, err)
+	}
+	credential, err := dockerRegistry.GetRegistryCredential(store)
+	if err != nil {
+		return nil, err
+	}
+	client, err := dockerRegistry.NewRegistryApiClient(credential, cache.Repository, registryTimeout)
+	if err != nil {
+		return nil, err
+	}
+	clients[key] = client
+	return client, nil
+}
+
+// getCacheTags returns the tag exported by the build followed by the tags it imports from, a build of a new branch
+// starts from the cache of its fallback branch
+func getCacheTags(scope string, request *bean.BuildCacheRequest) []string {
+	var tags []string
+	switch scope {
+	case bean.ScopeApp:
+		// apps can share a cache repository
+		tags = []string{fmt.Sprintf("%s-app%d", cacheTagPrefix, request.AppId)}
+	case bean.ScopeBranch:
+		if len(request.Branch) > 0 {
+			tags = append(tags, fmt.Sprintf("%s-ci%d-%s", cacheTagPrefix, request.CiPipelineId, request.Branch))
+			if len(request.FallbackBranch) > 0 && request.FallbackBranch != request.Branch {
+				tags = append(tags, fmt.Sprintf("%s-ci%d-%s", cacheTagPrefix, request.CiPipelineId, request.FallbackBranch))
+			}
+			break
+		}
+		fallthrough
+	default:
+		tags = []string{fmt.Sprintf("%s-ci%d", cacheTagPrefix, request.CiPipelineId)}
+	}
+	for i, tag := range tags {
+		// the builds of a matrix do not share their layers
+		if len(request.CellKey) > 0 {
+			tag = tag + "-" + request.CellKey
+		}
+		tags[i] = getValidTag(tag)
+	}
+	return tags
+}
+
+// getValidTag replaces the characters not allowed in a tag, a tag over the length limit is shortened with a hash
+func getValidTag(tag string) string {
+	tag = invalidTagCharRegex.ReplaceAllString(tag, "-")
+	if len(tag) > maxImageTagLength {
+		tag = fmt.Sprintf("%s-%x", tag[:maxImageTagLength-9], sha256.Sum256([]byte(tag)))[:maxImageTagLength]
+	}
+	return tag
+}
+
+func getCacheRefs(registryHost string, cacheRepository string, tags []string, mode string, registryType string) *bean.BuildCacheRefs {
+	refs := &bean.BuildCacheRefs{}
+	for _, tag := range tags {
+		refs.CacheFrom = append(refs.CacheFrom, fmt.Sprintf("type=registry,ref=%s/%s:%s", registryHost, cacheRepository, tag))
+	}
+	refs.CacheTo = fmt.Sprintf("type=registry,ref=%s/%s:%s,mode=%s", registryHost, cacheRepository, tags[0], mode)
+	if registryType == dockerRegistryRepository.REGISTRYTYPE_ECR {
+		// ecr does not accept the cache manifest list of buildkit
+		refs.CacheTo += ",image-manifest=true,oci-mediatypes=true"
+	}
+	return refs
+}
+
+func getRegistryHost(registryURL string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(registryURL, "https://"), "http://")
+	return strings.TrimSuffix(host, "/")
+}
+
+// getCacheSize sums the sizes of the blobs of a cache, exported as an image manifest or as an index of its layers
+func getCacheSize(content []byte) int64 {
+	manifest := struct {
+		Config *struct {
+			Size int64 `json:"size"`
+		} `json:"config"`
+		Layers []struct {
+			Size int64 `json:"size"`
+		} `json:"layers"`
+		Manifests []struct {
+			Size int64 `json:"size"`
+		} `json:"manifests"`
+	}{}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return 0
+	}
+	var size int64
+	if manifest.Config != nil {
+		size += manifest.Config.Size
+	}
+	for _, layer := range manifest.Layers {
+		size += layer.Size
+	}
+	for _, blob := range manifest.Manifests {
+		size += blob.Size
+	}
+	return size
+}
@@ -0,0 +1,118 @@
+package buildCache
+
+import (
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/dockerRegistry"
+	"github.com/devtron-labs/devtron/pkg/pipeline/buildCache/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/buildCache/repository"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetCacheTags(t *testing.T) {
+	request := &bean.BuildCacheRequest{AppId: 3, CiPipelineId: 7, Branch: "feature/search", FallbackBranch: "main"}
+	assert.Equal(t, []string{"buildcache-app3"}, getCacheTags(bean.ScopeApp, request))
+	assert.Equal(t, []string{"buildcache-ci7"}, getCacheTags(bean.ScopePipeline, request))
+	assert.Equal(t, []string{"buildcache-ci7-feature-search", "buildcache-ci7-main"}, getCacheTags(bean.ScopeBranch, request))
+
+	// a build without a branch uses the cache of the pipeline
+	assert.Equal(t, []string{"buildcache-ci7"}, getCacheTags(bean.ScopeBranch, &bean.BuildCacheRequest{CiPipelineId: 7}))
+	assert.Equal(t, []string{"buildcache-ci7-linux-arm64"}, getCacheTags(bean.ScopePipeline, &bean.BuildCacheRequest{CiPipelineId: 7, CellKey: "linux-arm64"}))
+
+	tags := getCacheTags(bean.ScopeBranch, &bean.BuildCacheRequest{CiPipelineId: 7, Branch: strings.Repeat("b", 200)})
+	assert.Len(t, tags[0], 128)
+	assert.NotEqual(t, tags[0], getCacheTags(bean.ScopeBranch, &bean.BuildCacheRequest{CiPipelineId: 7, Branch: strings.Repeat("b", 201)})[0])
+}
+
+func TestGetCacheRefs(t *testing.T) {
+	refs := getCacheRefs("registry.example.com", "shop/cart", []string{"buildcache-ci7-dev", "buildcache-ci7-main"}, bean.ModeMax, dockerRegistryRepository.REGISTRYTYPE_OTHER)
+	assert.Equal(t, []string{"type=registry,ref=registry.example.com/shop/cart:buildcache-ci7-dev", "type=registry,ref=registry.example.com/shop/cart:buildcache-ci7-main"}, refs.CacheFrom)
+	assert.Equal(t, "type=registry,ref=registry.example.com/shop/cart:buildcache-ci7-dev,mode=max", refs.CacheTo)
+
+	refs = getCacheRefs("123.dkr.ecr.us-east-1.amazonaws.com", "cart", []string{"buildcache"}, bean.ModeMin, dockerRegistryRepository.REGISTRYTYPE_ECR)
+	assert.Equal(t, "type=registry,ref=123.dkr.ecr.us-east-1.amazonaws.com/cart:buildcache,mode=min,image-manifest=true,oci-mediatypes=true", refs.CacheTo)
+	assert.Equal(t, "registry.example.com", getRegistryHost("https://registry.example.com/"))
+}
+
+func TestGetCacheSize(t *testing.T) {
+	index := `{"schemaVersion":2,"manifests":[{"size":100},{"size":250}]}`
+	imageManifest := `{"schemaVersion":2,"config":{"size":10},"layers":[{"size":100},{"size":250}]}`
+	assert.Equal(t, int64(350), getCacheSize([]byte(index)))
+	assert.Equal(t, int64(360), getCacheSize([]byte(imageManifest)))
+	assert.Equal(t, int64(0), getCacheSize([]byte("not json")))
+}
+
+type buildCacheRepositoryStub struct {
+	repository.BuildCacheRepository
+	caches  []*repository.CiBuildCache
+	deleted []int
+}
+
+func (stub *buildCacheRepositoryStub) FindCachesOfCiPipeline(ciPipelineId int, appId int) ([]*repository.CiBuildCache, error) {
+	return stub.caches, nil
+}
+
+func (stub *buildCacheRepositoryStub) DeleteCache(id int) error {
+	stub.deleted = append(stub.deleted, id)
+	return nil
+}
+
+type dockerArtifactStoreRepositoryStub struct {
+	dockerRegistryRepository.DockerArtifactStoreRepository
+	store *dockerRegistryRepository.DockerArtifactStore
+}
+
+func (stub *dockerArtifactStoreRepositoryStub) FindOne(storeId string) (*dockerRegistryRepository.DockerArtifactStore, error) {
+	return stub.store, nil
+}
+
+func TestPurgeCaches(t *testing.T) {
+	cacheManifest := []byte(`{"schemaVersion":2,"manifests":[{"size":100}]}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/shop/cart/manifests/buildcache-ci7":
+			w.Header().Set("Content-Type", dockerRegistry.MediaTypeOciIndex)
+			_, _ = w.Write(cacheManifest)
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/shop/cart/manifests/buildcache-ci7-readonly":
+			w.Header().Set("Content-Type", dockerRegistry.MediaTypeOciIndex)
+			w.Header().Set("Docker-Content-Digest", "sha256:readonly")
+			_, _ = w.Write(cacheManifest)
+		case r.Method == http.MethodDelete && r.URL.Path == "/v2/shop/cart/manifests/"+dockerRegistry.GetDigest(cacheManifest):
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cacheRepository := &buildCacheRepositoryStub{caches: []*repository.CiBuildCache{
+		{Id: 1, DockerRegistryId: "registry", Repository: "shop/cart", Tag: "buildcache-ci7", CacheRef: "cart:buildcache-ci7"},
+		{Id: 2, DockerRegistryId: "registry", Repository: "shop/cart", Tag: "buildcache-ci7-new", CacheRef: "cart:buildcache-ci7-new"},
+		{Id: 3, DockerRegistryId: "registry", Repository: "shop/cart", Tag: "buildcache-ci7-readonly", CacheRef: "cart:buildcache-ci7-readonly"},
+	}}
+	logger, _ := util.NewSugardLogger()
+	service := NewBuildCacheServiceImpl(logger, cacheRepository,
+		&dockerArtifactStoreRepositoryStub{store: &dockerRegistryRepository.DockerArtifactStore{RegistryURL: server.URL}})
+
+	caches, err := service.GetCaches(7, 1)
+	assert.NoError(t, err)
+	assert.True(t, caches[0].Exists)
+	assert.Equal(t, int64(100), caches[0].SizeBytes)
+	assert.False(t, caches[1].Exists)
+
+	result, err := service.PurgeCaches(7, 1, 0)
+	assert.NoError(t, err)
+	// a cache which was never exported is purged, a registry refusing the delete keeps it
+	assert.Equal(t, []string{"cart:buildcache-ci7", "cart:buildcache-ci7-new"}, result.Purged)
+	assert.Contains(t, result.Failed, "cart:buildcache-ci7-readonly")
+	assert.Equal(t, []int{1, 2}, cacheRepository.deleted)
+
+	_, err = service.PurgeCaches(7, 1, 42)
+	assert.Error(t, err)
+}
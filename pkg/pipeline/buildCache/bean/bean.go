@@ -0,0 +1,74 @@
+package bean
+
+import "time"
+
+const (
+	// BackendBlobStorage keeps the cache of the pipeline as a tarball in blob storage, or on the pvc of the ci node
+	BackendBlobStorage = "BLOB_STORAGE"
+	// BackendRegistry exports the buildkit cache to the registry of the pipeline so that builds on any node can use it
+	BackendRegistry = "REGISTRY"
+)
+
+const (
+	ScopeBranch   = "BRANCH"
+	ScopePipeline = "PIPELINE"
+	ScopeApp      = "APP"
+)
+
+const (
+	// ModeMin exports the layers of the final image only, ModeMax exports the layers of every stage of the build
+	ModeMin = "min"
+	ModeMax = "max"
+)
+
+// BuildCacheConfig is the cache of the docker builds of a ci pipeline. A registry cache is stored in the repository of
+// the pipeline, or in CacheRepository of the same registry, under a tag of its scope.
+type BuildCacheConfig struct {
+	CiPipelineId    int    `json:"ciPipelineId"`
+	Backend         string `json:"backend" validate:"oneof=BLOB_STORAGE REGISTRY"`
+	Scope           string `json:"scope" validate:"oneof=BRANCH PIPELINE APP"`
+	Mode            string `json:"mode" validate:"oneof=min max"`
+	CacheRepository string `json:"cacheRepository,omitempty"`
+}
+
+// BuildCacheRequest is a build of a pipeline, the cache of which is to be resolved. FallbackBranch is the target branch
+// of a pull request, CellKey the key of the build matrix cell of the build.
+type BuildCacheRequest struct {
+	AppId            int
+	CiPipelineId     int
+	CiWorkflowId     int
+	Branch           string
+	FallbackBranch   string
+	CellKey          string
+	DockerRegistryId string
+	RegistryType     string
+	RegistryURL      string
+	DockerRepository string
+}
+
+// BuildCacheRefs are the buildx cache-from and cache-to values of a build
+type BuildCacheRefs struct {
+	CacheFrom []string
+	CacheTo   string
+}
+
+// BuildCache is a cache exported to a registry by the builds of a pipeline, or of all the pipelines of the app for the
+// app scope
+type BuildCache struct {
+	Id               int       `json:"id"`
+	CiPipelineId     int       `json:"ciPipelineId"`
+	Scope            string    `json:"scope"`
+	DockerRegistryId string    `json:"dockerRegistryId"`
+	CacheRef         string    `json:"cacheRef"`
+	LastCiWorkflowId int       `json:"lastCiWorkflowId"`
+	LastUsedOn       time.Time `json:"lastUsedOn"`
+	// Exists is false when the cache was not exported yet or was removed from the registry
+	Exists    bool   `json:"exists"`
+	SizeBytes int64  `json:"sizeBytes"`
+	Error     string `json:"error,omitempty"`
+}
+
+type PurgeResult struct {
+	Purged []string          `json:"purged"`
+	Failed map[string]string `json:"failed,omitempty"`
+}
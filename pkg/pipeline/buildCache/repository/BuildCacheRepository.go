@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/pipeline/buildCache/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	"go.uber.org/zap"
+	"time"
+)
+
+type CiPipelineBuildCacheConfig struct {
+	tableName       struct{} `sql:"ci_pipeline_build_cache_config" pg:",discard_unknown_columns"`
+	Id              int      `sql:"id,pk"`
+	CiPipelineId    int      `sql:"ci_pipeline_id,notnull"`
+	Backend         string   `sql:"backend,notnull"`
+	Scope           string   `sql:"scope,notnull"`
+	Mode            string   `sql:"mode,notnull"`
+	CacheRepository string   `sql:"cache_repository"`
+	sql.AuditLog
+}
+
+type CiBuildCache struct {
+	tableName        struct{}  `sql:"ci_build_cache" pg:",discard_unknown_columns"`
+	Id               int       `sql:"id,pk"`
+	AppId            int       `sql:"app_id,notnull"`
+	CiPipelineId     int       `sql:"ci_pipeline_id,notnull"`
+	Scope            string    `sql:"scope,notnull"`
+	DockerRegistryId string    `sql:"docker_registry_id,notnull"`
+	Repository       string    `sql:"repository,notnull"`
+	Tag              string    `sql:"tag,notnull"`
+	CacheRef         string    `sql:"cache_ref,notnull"`
+	LastCiWorkflowId int       `sql:"last_ci_workflow_id"`
+	LastUsedOn       time.Time `sql:"last_used_on,type:timestamptz"`
+	CreatedOn        time.Time `sql:"created_on,type:timestamptz"`
+}
+
+type BuildCacheRepository interface {
+	FindConfigByCiPipelineId(ciPipelineId int) (*CiPipelineBuildCacheConfig, error)
+	SaveConfig(config *CiPipelineBuildCacheConfig) error
+	UpdateConfig(config *CiPipelineBuildCacheConfig) error
+	FindCacheByRef(cacheRef string) (*CiBuildCache, error)
+	SaveCache(cache *CiBuildCache) error
+	UpdateCache(cache *CiBuildCache) error
+	// FindCachesOfCiPipeline returns the caches of the pipeline and the caches shared by the pipelines of its app
+	FindCachesOfCiPipeline(ciPipelineId int, appId int) ([]*CiBuildCache, error)
+	DeleteCache(id int) error
+}
+
+type BuildCacheRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewBuildCacheRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *BuildCacheRepositoryImpl {
+	return &BuildCacheRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *BuildCacheRepositoryImpl) FindConfigByCiPipelineId(ciPipelineId int) (*CiPipelineBuildCacheConfig, error) {
+	config := &CiPipelineBuildCacheConfig{}
+	err := impl.dbConnection.Model(config).
+		Where("ci_pipeline_id = ?", ciPipelineId).
+		Select()
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (impl *BuildCacheRepositoryImpl) SaveConfig(config *CiPipelineBuildCacheConfig) error {
+	return impl.dbConnection.Insert(config)
+}
+
+func (impl *BuildCacheRepositoryImpl) UpdateConfig(config *CiPipelineBuildCacheConfig) error {
+	return impl.dbConnection.Update(config)
+}
+
+func (impl *BuildCacheRepositoryImpl) FindCacheByRef(cacheRef string) (*CiBuildCache, error) {
+	cache := &CiBuildCache{}
+	err := impl.dbConnection.Model(cache).
+		Where("cache_ref = ?", cacheRef).
+		Select()
+	if err != nil {
+		return nil, err
+	}
+	return cache, nil
+}
+
+func (impl *BuildCacheRepositoryImpl) SaveCache(cache *CiBuildCache) error {
+	return impl.dbConnection.Insert(cache)
+}
+
+func (impl *BuildCacheRepositoryImpl) UpdateCache(cache *CiBuildCache) error {
+	return impl.dbConnection.Update(cache)
+}
+
+func (impl *BuildCacheRepositoryImpl) FindCachesOfCiPipeline(ciPipelineId int, appId int) ([]*CiBuildCache, error) {
+	var caches []*CiBuildCache
+	err := impl.dbConnection.Model(&caches).
+		WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			q = q.WhereOr("ci_pipeline_id = ? AND scope <> ?", ciPipelineId, bean.ScopeApp).
+				WhereOr("app_id = ? AND scope = ?", appId, bean.ScopeApp)
+			return q, nil
+		}).
+		Order("last_used_on DESC").
+		Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting build caches", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	return caches, nil
+}
+
+func (impl *BuildCacheRepositoryImpl) DeleteCache(id int) error {
+	_, err := impl.dbConnection.Model((*CiBuildCache)(nil)).
+		Where("id = ?", id).
+		Delete()
+	return err
+}
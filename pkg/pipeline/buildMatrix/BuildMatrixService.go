@@ -0,0 +1,537 @@
+package buildMatrix
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/dockerRegistry"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/buildMatrix/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/buildMatrix/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	MaxMatrixCells     = 16
+	DefaultRunPageSize = 20
+	maxImageTagLength  = 128
+	// workflowCancelled is the status of a ci workflow aborted by the user
+	workflowCancelled = "CANCELLED"
+)
+
+var platformRegex = regexp.MustCompile(`^[a-z0-9]+/[a-z0-9_]+(/[a-z0-9]+)?$`)
+
+type BuildMatrixService interface {
+	// GetMatrix returns nil when no matrix was configured for the pipeline
+	GetMatrix(ciPipelineId int) (*bean.BuildMatrix, error)
+	// SaveMatrix saves the matrix of the pipeline, a matrix can only be enabled for a pipeline building a dockerfile
+	SaveMatrix(matrix *bean.BuildMatrix, userId int32) (*bean.BuildMatrix, error)
+	// GetEnabledMatrixCells returns the cells of the matrix of the pipeline, no cells when it has no enabled matrix
+	GetEnabledMatrixCells(ciPipelineId int) (*bean.BuildMatrix, []*bean.MatrixCell, error)
+	// SaveRun saves a run of the cells of the matrix, each cell with the ci workflow building it
+	SaveRun(run *bean.MatrixRun, dockerRegistryId string) error
+	// HandleCellSuccess saves the image built by a ci workflow if it is a matrix cell and tells which artifact to save,
+	// the manifest list of a run is created once the last of its cells is built
+	HandleCellSuccess(ciWorkflowId int, image string, imageDigest string) (*bean.CellCompletion, error)
+	GetRun(ciPipelineId int, runId int) (*bean.MatrixRun, error)
+	GetRuns(ciPipelineId int, offset int, size int) ([]*bean.MatrixRun, error)
+}
+
+type BuildMatrixServiceImpl struct {
+	logger                        *zap.SugaredLogger
+	buildMatrixRepository         repository.BuildMatrixRepository
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository
+	manifestListClient            ManifestListClient
+	ciPipelineRepository          pipelineConfig.CiPipelineRepository
+	ciTemplateRepository          pipelineConfig.CiTemplateRepository
+	ciTemplateOverrideRepository  pipelineConfig.CiTemplateOverrideRepository
+}
+
+func NewBuildMatrixServiceImpl(logger *zap.SugaredLogger, buildMatrixRepository repository.BuildMatrixRepository,
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository, manifestListClient ManifestListClient,
+	ciPipelineRepository pipelineConfig.CiPipelineRepository, ciTemplateRepository pipelineConfig.CiTemplateRepository,
+	ciTemplateOverrideRepository pipelineConfig.CiTemplateOverrideRepository) *BuildMatrixServiceImpl {
+	return &BuildMatrixServiceImpl{
+		logger:                        logger,
+		buildMatrixRepository:         buildMatrixRepository,
+		dockerArtifactStoreRepository: dockerArtifactStoreRepository,
+		manifestListClient:            manifestListClient,
+		ciPipelineRepository:          ciPipelineRepository,
+		ciTemplateRepository:          ciTemplateRepository,
+		ciTemplateOverrideRepository:  ciTemplateOverrideRepository,
+	}
+}
+
+func (impl *BuildMatrixServiceImpl) GetMatrix(ciPipelineId int) (*bean.BuildMatrix, error) {
+	model, err := impl.buildMatrixRepository.FindMatrixByCiPipelineId(ciPipelineId)
+	if err == pg.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in getting build matrix", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	matrix := &bean.BuildMatrix{}
+	err = json.Unmarshal([]byte(model.Matrix), matrix)
+	if err != nil {
+		impl.logger.Errorw("error in reading build matrix", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	matrix.CiPipelineId = model.CiPipelineId
+	matrix.OutputType = model.OutputType
+	matrix.Enabled = model.Enabled
+	return matrix, nil
+}
+
+func (impl *BuildMatrixServiceImpl) SaveMatrix(matrix *bean.BuildMatrix, userId int32) (*bean.BuildMatrix, error) {
+	err := ValidateMatrix(matrix)
+	if err != nil {
+		return nil, err
+	}
+	if matrix.Enabled {
+		err = impl.validateDockerBuild(matrix.CiPipelineId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	content, err := json.Marshal(matrix)
+	if err != nil {
+		return nil, err
+	}
+	model, err := impl.buildMatrixRepository.FindMatrixByCiPipelineId(matrix.CiPipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting build matrix", "err", err, "ciPipelineId", matrix.CiPipelineId)
+		return nil, err
+	}
+	if err == pg.ErrNoRows {
+		model = &repository.CiPipelineBuildMatrix{
+			CiPipelineId: matrix.CiPipelineId,
+			Matrix:       string(content),
+			OutputType:   matrix.OutputType,
+			Enabled:      matrix.Enabled,
+			AuditLog:     sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId, UpdatedOn: time.Now(), UpdatedBy: userId},
+		}
+		err = impl.buildMatrixRepository.SaveMatrix(model)
+	} else {
+		model.Matrix = string(content)
+		model.OutputType = matrix.OutputType
+		model.Enabled = matrix.Enabled
+		model.UpdatedOn = time.Now()
+		model.UpdatedBy = userId
+		err = impl.buildMatrixRepository.UpdateMatrix(model)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving build matrix", "err", err, "ciPipelineId", matrix.CiPipelineId)
+		return nil, err
+	}
+	return matrix, nil
+}
+
+// validateDockerBuild fails when the pipeline, or the app when the pipeline does not override its build, does not
+// build a dockerfile, the cells of a matrix override the docker build
+func (impl *BuildMatrixServiceImpl) validateDockerBuild(ciPipelineId int) error {
+	ciPipeline, err := impl.ciPipelineRepository.FindById(ciPipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in getting ci pipeline", "err", err, "ciPipelineId", ciPipelineId)
+		return err
+	}
+	var ciBuildConfig *pipelineConfig.CiBuildConfig
+	if ciPipeline.IsDockerConfigOverridden {
+		templateOverride, err := impl.ciTemplateOverrideRepository.FindByCiPipelineId(ciPipelineId)
+		if err != nil {
+			return err
+		}
+		ciBuildConfig = templateOverride.CiBuildConfig
+	} else {
+		ciTemplate, err := impl.ciTemplateRepository.FindByAppId(ciPipeline.AppId)
+		if err != nil {
+			impl.logger.Errorw("error in getting ci template", "err", err, "appId", ciPipeline.AppId)
+			return err
+		}
+		ciBuildConfig = ciTemplate.CiBuildConfig
+	}
+	if !isDockerBuild(ciBuildConfig) {
+		return &util.ApiError{
+			HttpStatusCode:  http.StatusBadRequest,
+			InternalMessage: fmt.Sprintf("ci pipeline %d does not build a dockerfile", ciPipelineId),
+			UserMessage:     "build matrix is only supported for docker builds",
+		}
+	}
+	return nil
+}
+
+func (impl *BuildMatrixServiceImpl) GetEnabledMatrixCells(ciPipelineId int) (*bean.BuildMatrix, []*bean.MatrixCell, error) {
+	matrix, err := impl.GetMatrix(ciPipelineId)
+	if err != nil || matrix == nil || !matrix.Enabled {
+		return matrix, nil, err
+	}
+	return matrix, GetMatrixCells(matrix), nil
+}
+
+func (impl *BuildMatrixServiceImpl) SaveRun(run *bean.MatrixRun, dockerRegistryId string) error {
+	manifestStatus := bean.ManifestStatusNotRequired
+	if run.OutputType == bean.OutputTypeManifestList {
+		manifestStatus = bean.ManifestStatusPending
+	}
+	model := &repository.CiMatrixRun{
+		CiPipelineId:     run.CiPipelineId,
+		OutputType:       run.OutputType,
+		DockerRegistryId: dockerRegistryId,
+		Image:            run.Image,
+		ManifestStatus:   manifestStatus,
+		TriggeredBy:      run.TriggeredBy,
+		StartedOn:        run.StartedOn,
+	}
+	var cells []*repository.CiMatrixRunCell
+	for _, cell := range run.Cells {
+		buildArgs, err := json.Marshal(cell.BuildArgs)
+		if err != nil {
+			return err
+		}
+		cells = append(cells, &repository.CiMatrixRunCell{
+			CiWorkflowId:   cell.CiWorkflowId,
+			CellKey:        cell.Key,
+			TargetPlatform: cell.TargetPlatform,
+			BuildArgs:      string(buildArgs),
+			DockerfilePath: cell.DockerfilePath,
+		})
+	}
+	err := impl.buildMatrixRepository.SaveRun(model, cells)
+	if err != nil {
+		impl.logger.Errorw("error in saving matrix run", "err", err, "ciPipelineId", run.CiPipelineId)
+		return err
+	}
+	run.Id = model.Id
+	run.ManifestStatus = manifestStatus
+	return nil
+}
+
+func (impl *BuildMatrixServiceImpl) HandleCellSuccess(ciWorkflowId int, image string, imageDigest string) (*bean.CellCompletion, error) {
+	cell, err := impl.buildMatrixRepository.FindCellByCiWorkflowId(ciWorkflowId)
+	if err == pg.ErrNoRows {
+		return &bean.CellCompletion{IsMatrixCell: false, SaveArtifact: true}, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in getting matrix run cell", "err", err, "ciWorkflowId", ciWorkflowId)
+		return nil, err
+	}
+	cell.Image = image
+	cell.ImageDigest = imageDigest
+	err = impl.buildMatrixRepository.UpdateCell(cell)
+	if err != nil {
+		impl.logger.Errorw("error in updating matrix run cell", "err", err, "ciWorkflowId", ciWorkflowId)
+		return nil, err
+	}
+	run, err := impl.buildMatrixRepository.FindRunById(cell.MatrixRunId)
+	if err != nil {
+		impl.logger.Errorw("error in getting matrix run", "err", err, "runId", cell.MatrixRunId)
+		return nil, err
+	}
+	if run.OutputType != bean.OutputTypeManifestList {
+		return &bean.CellCompletion{IsMatrixCell: true, SaveArtifact: true}, nil
+	}
+	cells, err := impl.buildMatrixRepository.FindCellsWithStatusByRunIds([]int{run.Id})
+	if err != nil {
+		return nil, err
+	}
+	for _, runCell := range cells {
+		if len(runCell.Image) == 0 {
+			// the list is created by the last cell to be built
+			return &bean.CellCompletion{IsMatrixCell: true}, nil
+		}
+	}
+	marked, err := impl.buildMatrixRepository.MarkRunManifestCreating(run.Id)
+	if err != nil {
+		impl.logger.Errorw("error in marking manifest list creation", "err", err, "runId", run.Id)
+		return nil, err
+	}
+	if !marked {
+		return &bean.CellCompletion{IsMatrixCell: true}, nil
+	}
+	digest, err := impl.createManifestList(run, cells)
+	if err != nil {
+		impl.logger.Errorw("error in creating manifest list", "err", err, "runId", run.Id)
+		run.ManifestStatus = bean.ManifestStatusFailed
+		run.ManifestMessage = err.Error()
+	} else {
+		run.ManifestStatus = bean.ManifestStatusCreated
+		run.ImageDigest = digest
+	}
+	updateErr := impl.buildMatrixRepository.UpdateRun(run)
+	if updateErr != nil {
+		impl.logger.Errorw("error in updating matrix run", "err", updateErr, "runId", run.Id)
+		return nil, updateErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &bean.CellCompletion{IsMatrixCell: true, SaveArtifact: true, Image: run.Image, ImageDigest: run.ImageDigest}, nil
+}
+
+func (impl *BuildMatrixServiceImpl) createManifestList(run *repository.CiMatrixRun, cells []*repository.CiMatrixRunCellWithStatus) (string, error) {
+	store, err := impl.dockerArtifactStoreRepository.FindOne(run.DockerRegistryId)
+	if err != nil {
+		return "", fmt.Errorf("error in getting registry %s: %w", run.DockerRegistryId, err)
+	}
+	credential, err := dockerRegistry.GetRegistryCredential(store)
+	if err != nil {
+		return "", err
+	}
+	repositoryName, tag := splitImage(run.Image)
+	var images []*PlatformImage
+	for _, cell := range cells {
+		reference := cell.ImageDigest
+		if len(reference) == 0 {
+			_, reference = splitImage(cell.Image)
+		}
+		images = append(images, &PlatformImage{Platform: cell.TargetPlatform, Reference: reference})
+	}
+	return impl.manifestListClient.CreateManifestList(credential, repositoryName, tag, images)
+}
+
+func (impl *BuildMatrixServiceImpl) GetRun(ciPipelineId int, runId int) (*bean.MatrixRun, error) {
+	run, err := impl.buildMatrixRepository.FindRunById(runId)
+	if err == pg.ErrNoRows || (err == nil && run.CiPipelineId != ciPipelineId) {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusNotFound, Code: "404", UserMessage: "matrix run not found"}
+	} else if err != nil {
+		impl.logger.Errorw("error in getting matrix run", "err", err, "runId", runId)
+		return nil, err
+	}
+	runs, err := impl.getRunsWithCells([]*repository.CiMatrixRun{run})
+	if err != nil {
+		return nil, err
+	}
+	return runs[0], nil
+}
+
+func (impl *BuildMatrixServiceImpl) GetRuns(ciPipelineId int, offset int, size int) ([]*bean.MatrixRun, error) {
+	if size <= 0 {
+		size = DefaultRunPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	runs, err := impl.buildMatrixRepository.FindRunsByCiPipelineId(ciPipelineId, offset, size)
+	if err != nil {
+		return nil, err
+	}
+	return impl.getRunsWithCells(runs)
+}
+
+func (impl *BuildMatrixServiceImpl) getRunsWithCells(runs []*repository.CiMatrixRun) ([]*bean.MatrixRun, error) {
+	runIds := make([]int, 0, len(runs))
+	for _, run := range runs {
+		runIds = append(runIds, run.Id)
+	}
+	cells, err := impl.buildMatrixRepository.FindCellsWithStatusByRunIds(runIds)
+	if err != nil {
+		return nil, err
+	}
+	runCells := make(map[int][]*bean.MatrixRunCell)
+	for _, cell := range cells {
+		var buildArgs map[string]string
+		if len(cell.BuildArgs) > 0 {
+			err = json.Unmarshal([]byte(cell.BuildArgs), &buildArgs)
+			if err != nil {
+				impl.logger.Errorw("error in reading build args of matrix run cell", "err", err, "ciWorkflowId", cell.CiWorkflowId)
+			}
+		}
+		runCells[cell.MatrixRunId] = append(runCells[cell.MatrixRunId], &bean.MatrixRunCell{
+			MatrixCell: bean.MatrixCell{
+				Key:            cell.CellKey,
+				TargetPlatform: cell.TargetPlatform,
+				BuildArgs:      buildArgs,
+				DockerfilePath: cell.DockerfilePath,
+			},
+			CiWorkflowId: cell.CiWorkflowId,
+			Status:       cell.Status,
+			PodStatus:    cell.PodStatus,
+			Message:      cell.Message,
+			Image:        cell.Image,
+			ImageDigest:  cell.ImageDigest,
+			StartedOn:    cell.StartedOn,
+			FinishedOn:   cell.FinishedOn,
+		})
+	}
+	matrixRuns := make([]*bean.MatrixRun, 0, len(runs))
+	for _, run := range runs {
+		matrixRuns = append(matrixRuns, &bean.MatrixRun{
+			Id:              run.Id,
+			CiPipelineId:    run.CiPipelineId,
+			Status:          getRunStatus(runCells[run.Id], run.OutputType, run.ManifestStatus),
+			OutputType:      run.OutputType,
+			Image:           run.Image,
+			ImageDigest:     run.ImageDigest,
+			ManifestStatus:  run.ManifestStatus,
+			ManifestMessage: run.ManifestMessage,
+			TriggeredBy:     run.TriggeredBy,
+			StartedOn:       run.StartedOn,
+			Cells:           runCells[run.Id],
+		})
+	}
+	return matrixRuns, nil
+}
+
+// ValidateMatrix checks the values of every dimension of the matrix and the number of its cells
+func ValidateMatrix(matrix *bean.BuildMatrix) error {
+	var messages []string
+	platforms := make(map[string]bool)
+	for _, platform := range matrix.TargetPlatforms {
+		if !platformRegex.MatchString(platform) {
+			messages = append(messages, fmt.Sprintf("invalid target platform %q, expected os/arch[/variant]", platform))
+		} else if platforms[platform] {
+			messages = append(messages, fmt.Sprintf("duplicate target platform %q", platform))
+		}
+		platforms[platform] = true
+	}
+	dockerfilePaths := make(map[string]bool)
+	for _, dockerfilePath := range matrix.DockerfilePaths {
+		if len(strings.TrimSpace(dockerfilePath)) == 0 {
+			messages = append(messages, "dockerfile path can not be empty")
+		} else if dockerfilePaths[dockerfilePath] {
+			messages = append(messages, fmt.Sprintf("duplicate dockerfile path %q", dockerfilePath))
+		}
+		dockerfilePaths[dockerfilePath] = true
+	}
+	if matrix.OutputType == bean.OutputTypeManifestList {
+		if len(matrix.TargetPlatforms) == 0 {
+			messages = append(messages, "a manifest list needs target platforms")
+		}
+		if len(matrix.BuildArgs) > 1 || len(matrix.DockerfilePaths) > 1 {
+			messages = append(messages, "a manifest list can only vary the target platform, use multiple artifacts to vary build args or dockerfile paths")
+		}
+	}
+	cells := getDimensionSize(len(matrix.TargetPlatforms)) * getDimensionSize(len(matrix.BuildArgs)) * getDimensionSize(len(matrix.DockerfilePaths))
+	if matrix.Enabled && cells < 2 {
+		messages = append(messages, "an enabled matrix needs at least 2 builds")
+	} else if cells > MaxMatrixCells {
+		messages = append(messages, fmt.Sprintf("matrix has %d builds, at most %d are allowed", cells, MaxMatrixCells))
+	}
+	if len(messages) > 0 {
+		message := strings.Join(messages, ", ")
+		return &util.ApiError{HttpStatusCode: http.StatusBadRequest, Code: "400", UserMessage: message, InternalMessage: message}
+	}
+	return nil
+}
+
+func getDimensionSize(size int) int {
+	if size == 0 {
+		return 1
+	}
+	return size
+}
+
+// GetMatrixCells returns the cartesian product of the dimensions of the matrix, an empty dimension keeps the config of
+// the pipeline. The key of a cell names its platform and, when they vary, the index of its build args and dockerfile.
+func GetMatrixCells(matrix *bean.BuildMatrix) []*bean.MatrixCell {
+	platforms := matrix.TargetPlatforms
+	if len(platforms) == 0 {
+		platforms = []string{""}
+	}
+	buildArgs := matrix.BuildArgs
+	if len(buildArgs) == 0 {
+		buildArgs = []map[string]string{nil}
+	}
+	dockerfilePaths := matrix.DockerfilePaths
+	if len(dockerfilePaths) == 0 {
+		dockerfilePaths = []string{""}
+	}
+	var cells []*bean.MatrixCell
+	for _, platform := range platforms {
+		for argsIndex, args := range buildArgs {
+			for dockerfileIndex, dockerfilePath := range dockerfilePaths {
+				var keyParts []string
+				if len(platform) > 0 {
+					keyParts = append(keyParts, strings.ReplaceAll(platform, "/", "-"))
+				}
+				if len(buildArgs) > 1 {
+					keyParts = append(keyParts, fmt.Sprintf("args%d", argsIndex))
+				}
+				if len(dockerfilePaths) > 1 {
+					keyParts = append(keyParts, fmt.Sprintf("df%d", dockerfileIndex))
+				}
+				cells = append(cells, &bean.MatrixCell{
+					Key:            strings.Join(keyParts, "-"),
+					TargetPlatform: platform,
+					BuildArgs:      args,
+					DockerfilePath: dockerfilePath,
+				})
+			}
+		}
+	}
+	return cells
+}
+
+// GetCellImageTag suffixes the tag of the build of the pipeline with the key of the cell, within the limit of a tag
+func GetCellImageTag(baseTag string, key string) string {
+	tag := baseTag + "-" + key
+	if len(tag) > maxImageTagLength {
+		tag = tag[:maxImageTagLength]
+	}
+	return tag
+}
+
+// getRunStatus is running until every cell is done, a manifest list run then succeeds with its list
+func getRunStatus(cells []*bean.MatrixRunCell, outputType string, manifestStatus string) string {
+	succeeded := true
+	for _, cell := range cells {
+		if !isTerminalStatus(cell.Status) {
+			return bean.MatrixRunRunning
+		}
+		if cell.Status != pipelineConfig.WorkflowSucceeded {
+			succeeded = false
+		}
+	}
+	if !succeeded {
+		return bean.MatrixRunFailed
+	}
+	if outputType == bean.OutputTypeManifestList {
+		switch manifestStatus {
+		case bean.ManifestStatusCreated:
+			return bean.MatrixRunSucceeded
+		case bean.ManifestStatusFailed:
+			return bean.MatrixRunFailed
+		default:
+			return bean.MatrixRunRunning
+		}
+	}
+	return bean.MatrixRunSucceeded
+}
+
+func isTerminalStatus(status string) bool {
+	switch status {
+	case pipelineConfig.WorkflowSucceeded, pipelineConfig.WorkflowFailed, pipelineConfig.WorkflowAborted,
+		pipelineConfig.WorkflowTimedOut, string(v1alpha1.NodeError), workflowCancelled:
+		return true
+	}
+	return false
+}
+
+// splitImage splits an image like registry/repo:tag into the repository in the registry and the tag
+func splitImage(image string) (string, string) {
+	tag := ""
+	if index := strings.LastIndex(image, ":"); index > strings.LastIndex(image, "/") {
+		tag = image[index+1:]
+		image = image[:index]
+	}
+	if index := strings.Index(image, "/"); index >= 0 {
+		image = image[index+1:]
+	}
+	return image, tag
+}
+
+// isDockerBuild tells if the build config builds a dockerfile, templates without a build config build their dockerfile
+func isDockerBuild(ciBuildConfig *pipelineConfig.CiBuildConfig) bool {
+	if ciBuildConfig == nil {
+		return true
+	}
+	buildType := pipelineBean.CiBuildType(ciBuildConfig.Type)
+	return buildType == pipelineBean.SELF_DOCKERFILE_BUILD_TYPE || buildType == pipelineBean.MANAGED_DOCKERFILE_BUILD_TYPE
+}
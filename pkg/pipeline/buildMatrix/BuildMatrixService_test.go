@@ -0,0 +1,92 @@
+package buildMatrix
+
+import (
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/buildMatrix/bean"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestGetMatrixCells(t *testing.T) {
+	matrix := &bean.BuildMatrix{
+		TargetPlatforms: []string{"linux/amd64", "linux/arm64"},
+		BuildArgs:       []map[string]string{{"GO_VERSION": "1.20"}, {"GO_VERSION": "1.21"}},
+		DockerfilePaths: []string{"Dockerfile"},
+	}
+	cells := GetMatrixCells(matrix)
+	assert.Len(t, cells, 4)
+	assert.Equal(t, "linux-amd64-args0", cells[0].Key)
+	assert.Equal(t, "linux/amd64", cells[0].TargetPlatform)
+	assert.Equal(t, "1.20", cells[0].BuildArgs["GO_VERSION"])
+	assert.Equal(t, "Dockerfile", cells[0].DockerfilePath)
+	assert.Equal(t, "linux-arm64-args1", cells[3].Key)
+
+	// an empty dimension keeps the config of the pipeline
+	cells = GetMatrixCells(&bean.BuildMatrix{DockerfilePaths: []string{"Dockerfile", "Dockerfile.alpine"}})
+	assert.Len(t, cells, 2)
+	assert.Equal(t, "df1", cells[1].Key)
+	assert.Empty(t, cells[1].TargetPlatform)
+	assert.Nil(t, cells[1].BuildArgs)
+}
+
+func TestValidateMatrix(t *testing.T) {
+	valid := &bean.BuildMatrix{TargetPlatforms: []string{"linux/amd64", "linux/arm/v7"}, OutputType: bean.OutputTypeManifestList, Enabled: true}
+	assert.NoError(t, ValidateMatrix(valid))
+
+	invalid := []*bean.BuildMatrix{
+		{TargetPlatforms: []string{"linux-amd64", "linux/arm64"}, OutputType: bean.OutputTypeMultipleArtifacts, Enabled: true},
+		{TargetPlatforms: []string{"linux/amd64", "linux/amd64"}, OutputType: bean.OutputTypeMultipleArtifacts, Enabled: true},
+		{TargetPlatforms: []string{"linux/amd64"}, OutputType: bean.OutputTypeMultipleArtifacts, Enabled: true},
+		{DockerfilePaths: []string{"Dockerfile", " "}, OutputType: bean.OutputTypeMultipleArtifacts, Enabled: true},
+		{DockerfilePaths: []string{"Dockerfile", "Dockerfile.alpine"}, OutputType: bean.OutputTypeManifestList, Enabled: true},
+		{TargetPlatforms: []string{"linux/amd64", "linux/arm64"}, BuildArgs: []map[string]string{{"A": "1"}, {"A": "2"}}, OutputType: bean.OutputTypeManifestList, Enabled: true},
+		{TargetPlatforms: []string{"linux/amd64", "linux/arm64", "linux/arm/v7", "linux/s390x", "linux/ppc64le"},
+			DockerfilePaths: []string{"a", "b", "c", "d"}, OutputType: bean.OutputTypeMultipleArtifacts},
+	}
+	for _, matrix := range invalid {
+		assert.Error(t, ValidateMatrix(matrix), "%+v", matrix)
+	}
+	// a disabled matrix can be saved incomplete
+	assert.NoError(t, ValidateMatrix(&bean.BuildMatrix{OutputType: bean.OutputTypeMultipleArtifacts}))
+}
+
+func TestGetCellImageTag(t *testing.T) {
+	assert.Equal(t, "a1b2c3d4-7-42-linux-arm64", GetCellImageTag("a1b2c3d4-7-42", "linux-arm64"))
+	assert.Len(t, GetCellImageTag(strings.Repeat("a", 127), "linux-arm64"), 128)
+}
+
+func TestGetRunStatus(t *testing.T) {
+	cellsWithStatus := func(statuses ...string) []*bean.MatrixRunCell {
+		var cells []*bean.MatrixRunCell
+		for _, status := range statuses {
+			cells = append(cells, &bean.MatrixRunCell{Status: status})
+		}
+		return cells
+	}
+	assert.Equal(t, bean.MatrixRunRunning, getRunStatus(cellsWithStatus("Succeeded", "Running"), bean.OutputTypeMultipleArtifacts, bean.ManifestStatusNotRequired))
+	assert.Equal(t, bean.MatrixRunRunning, getRunStatus(cellsWithStatus("Failed", "Starting"), bean.OutputTypeMultipleArtifacts, bean.ManifestStatusNotRequired))
+	assert.Equal(t, bean.MatrixRunFailed, getRunStatus(cellsWithStatus("Succeeded", "CANCELLED"), bean.OutputTypeMultipleArtifacts, bean.ManifestStatusNotRequired))
+	assert.Equal(t, bean.MatrixRunSucceeded, getRunStatus(cellsWithStatus("Succeeded", "Succeeded"), bean.OutputTypeMultipleArtifacts, bean.ManifestStatusNotRequired))
+	assert.Equal(t, bean.MatrixRunRunning, getRunStatus(cellsWithStatus("Succeeded", "Succeeded"), bean.OutputTypeManifestList, bean.ManifestStatusCreating))
+	assert.Equal(t, bean.MatrixRunSucceeded, getRunStatus(cellsWithStatus("Succeeded", "Succeeded"), bean.OutputTypeManifestList, bean.ManifestStatusCreated))
+	assert.Equal(t, bean.MatrixRunFailed, getRunStatus(cellsWithStatus("Succeeded", "Succeeded"), bean.OutputTypeManifestList, bean.ManifestStatusFailed))
+}
+
+func TestSplitImage(t *testing.T) {
+	repository, tag := splitImage("123456789.dkr.ecr.us-east-1.amazonaws.com/shop/cart:a1b2-7-42")
+	assert.Equal(t, "shop/cart", repository)
+	assert.Equal(t, "a1b2-7-42", tag)
+	repository, tag = splitImage("localhost:5000/cart:v1")
+	assert.Equal(t, "cart", repository)
+	assert.Equal(t, "v1", tag)
+}
+
+func TestIsDockerBuild(t *testing.T) {
+	assert.True(t, isDockerBuild(nil))
+	assert.True(t, isDockerBuild(&pipelineConfig.CiBuildConfig{Type: string(pipelineBean.SELF_DOCKERFILE_BUILD_TYPE)}))
+	assert.True(t, isDockerBuild(&pipelineConfig.CiBuildConfig{Type: string(pipelineBean.MANAGED_DOCKERFILE_BUILD_TYPE)}))
+	assert.False(t, isDockerBuild(&pipelineConfig.CiBuildConfig{Type: string(pipelineBean.BUILDPACK_BUILD_TYPE)}))
+	assert.False(t, isDockerBuild(&pipelineConfig.CiBuildConfig{Type: string(pipelineBean.SKIP_BUILD_TYPE)}))
+}
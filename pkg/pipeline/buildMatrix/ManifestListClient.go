@@ -0,0 +1,117 @@
+package buildMatrix
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/dockerRegistry"
+	"strings"
+	"time"
+)
+
+// PlatformImage is an image built for a platform, referenced by digest or tag in the repository of the list
+type PlatformImage struct {
+	Platform  string
+	Reference string
+}
+
+type ManifestListClient interface {
+	// CreateManifestList pushes a manifest list, or an OCI index when the images are OCI manifests, of the images with
+	// the tag and returns its digest
+	CreateManifestList(credential *dockerRegistry.RegistryCredential, repository string, tag string, images []*PlatformImage) (string, error)
+}
+
+type ManifestListClientImpl struct {
+	timeout time.Duration
+}
+
+func NewManifestListClientImpl() *ManifestListClientImpl {
+	return &ManifestListClientImpl{timeout: 2 * time.Minute}
+}
+
+type manifestDescriptor struct {
+	MediaType string            `json:"mediaType"`
+	Size      int               `json:"size"`
+	Digest    string            `json:"digest"`
+	Platform  *manifestPlatform `json:"platform,omitempty"`
+}
+
+type manifestPlatform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+type manifestList struct {
+	SchemaVersion int                   `json:"schemaVersion"`
+	MediaType     string                `json:"mediaType"`
+	Manifests     []*manifestDescriptor `json:"manifests"`
+}
+
+func (impl *ManifestListClientImpl) CreateManifestList(credential *dockerRegistry.RegistryCredential, repository string, tag string, images []*PlatformImage) (string, error) {
+	client, err := dockerRegistry.NewRegistryApiClient(credential, repository, impl.timeout)
+	if err != nil {
+		return "", err
+	}
+	list := &manifestList{SchemaVersion: 2, MediaType: dockerRegistry.MediaTypeDockerManifestList}
+	for _, image := range images {
+		platform, err := parsePlatform(image.Platform)
+		if err != nil {
+			return "", err
+		}
+		descriptors, err := getPlatformManifests(client, image.Reference, platform)
+		if err != nil {
+			return "", err
+		}
+		for _, descriptor := range descriptors {
+			if descriptor.MediaType == dockerRegistry.MediaTypeOciManifest {
+				list.MediaType = dockerRegistry.MediaTypeOciIndex
+			}
+			list.Manifests = append(list.Manifests, descriptor)
+		}
+	}
+	content, err := json.Marshal(list)
+	if err != nil {
+		return "", err
+	}
+	return client.PutManifest(tag, list.MediaType, content)
+}
+
+func parsePlatform(platform string) (*manifestPlatform, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("invalid platform %q", platform)
+	}
+	manifestPlatform := &manifestPlatform{OS: parts[0], Architecture: parts[1]}
+	if len(parts) == 3 {
+		manifestPlatform.Variant = parts[2]
+	}
+	return manifestPlatform, nil
+}
+
+// getPlatformManifests returns the descriptor of the manifest of the reference, for an index the descriptors of its
+// manifests of the platform which leaves out the attestations added by buildx
+func getPlatformManifests(client *dockerRegistry.RegistryApiClient, reference string, platform *manifestPlatform) ([]*manifestDescriptor, error) {
+	manifest, err := client.GetManifest(reference)
+	if err != nil {
+		return nil, fmt.Errorf("error in getting manifest %s of %s: %w", reference, client.Repository(), err)
+	}
+	if manifest.MediaType != dockerRegistry.MediaTypeDockerManifestList && manifest.MediaType != dockerRegistry.MediaTypeOciIndex {
+		return []*manifestDescriptor{{MediaType: manifest.MediaType, Size: len(manifest.Content), Digest: manifest.Digest, Platform: platform}}, nil
+	}
+	index := &manifestList{}
+	err = json.Unmarshal(manifest.Content, index)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest list %s of %s: %w", reference, client.Repository(), err)
+	}
+	var descriptors []*manifestDescriptor
+	for _, descriptor := range index.Manifests {
+		if descriptor.Platform != nil && descriptor.Platform.OS == platform.OS && descriptor.Platform.Architecture == platform.Architecture &&
+			(len(platform.Variant) == 0 || descriptor.Platform.Variant == platform.Variant) {
+			descriptors = append(descriptors, descriptor)
+		}
+	}
+	if len(descriptors) == 0 {
+		return nil, fmt.Errorf("manifest list %s of %s has no manifest for %s/%s", reference, client.Repository(), platform.OS, platform.Architecture)
+	}
+	return descriptors, nil
+}
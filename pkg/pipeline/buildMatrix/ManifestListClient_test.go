@@ -0,0 +1,73 @@
+package buildMatrix
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/dockerRegistry"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCreateManifestList(t *testing.T) {
+	var pushed *manifestList
+	var pushedMediaType string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			username, password, _ := r.BasicAuth()
+			assert.Equal(t, "ci", username)
+			assert.Equal(t, "secret", password)
+			assert.Equal(t, "repository:shop/cart:pull,push,delete", r.URL.Query().Get("scope"))
+			_, _ = w.Write([]byte(`{"token":"registry-token"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer registry-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/shop/cart/manifests/v1-linux-amd64":
+			w.Header().Set("Content-Type", dockerRegistry.MediaTypeDockerManifest)
+			w.Header().Set("Docker-Content-Digest", "sha256:amd64")
+			_, _ = w.Write([]byte(`{"schemaVersion":2}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/shop/cart/manifests/sha256:arm64index":
+			// buildx pushes an index with an attestation manifest next to the image
+			w.Header().Set("Content-Type", dockerRegistry.MediaTypeOciIndex)
+			_, _ = w.Write([]byte(`{"schemaVersion":2,"manifests":[` +
+				`{"mediaType":"application/vnd.oci.image.manifest.v1+json","size":500,"digest":"sha256:arm64","platform":{"architecture":"arm64","os":"linux"}},` +
+				`{"mediaType":"application/vnd.oci.image.manifest.v1+json","size":300,"digest":"sha256:attestation","platform":{"architecture":"unknown","os":"unknown"}}]}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/v2/shop/cart/manifests/v1":
+			pushedMediaType = r.Header.Get("Content-Type")
+			body, _ := ioutil.ReadAll(r.Body)
+			pushed = &manifestList{}
+			_ = json.Unmarshal(body, pushed)
+			w.Header().Set("Docker-Content-Digest", "sha256:list")
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &ManifestListClientImpl{timeout: 10 * time.Second}
+	credential := &dockerRegistry.RegistryCredential{RegistryURL: server.URL, Username: "ci", Password: "secret"}
+	digest, err := client.CreateManifestList(credential, "shop/cart", "v1", []*PlatformImage{
+		{Platform: "linux/amd64", Reference: "v1-linux-amd64"},
+		{Platform: "linux/arm64", Reference: "sha256:arm64index"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "sha256:list", digest)
+	assert.Equal(t, dockerRegistry.MediaTypeOciIndex, pushedMediaType)
+	assert.Len(t, pushed.Manifests, 2)
+	assert.Equal(t, "sha256:amd64", pushed.Manifests[0].Digest)
+	assert.Equal(t, "amd64", pushed.Manifests[0].Platform.Architecture)
+	assert.Equal(t, "sha256:arm64", pushed.Manifests[1].Digest)
+
+	_, err = client.CreateManifestList(credential, "shop/cart", "v1", []*PlatformImage{{Platform: "linux/s390x", Reference: "sha256:arm64index"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package bean
+
+import "time"
+
+const (
+	// OutputTypeMultipleArtifacts saves the image of every cell of a matrix run as an artifact of the pipeline
+	OutputTypeMultipleArtifacts = "MULTIPLE_ARTIFACTS"
+	// OutputTypeManifestList saves one artifact, a manifest list of the images of the cells built for each platform
+	OutputTypeManifestList = "MANIFEST_LIST"
+)
+
+const (
+	ManifestStatusNotRequired = "NOT_REQUIRED"
+	ManifestStatusPending     = "PENDING"
+	ManifestStatusCreating    = "CREATING"
+	ManifestStatusCreated     = "CREATED"
+	ManifestStatusFailed      = "FAILED"
+)
+
+const (
+	MatrixRunRunning   = "Running"
+	MatrixRunSucceeded = "Succeeded"
+	MatrixRunFailed    = "Failed"
+)
+
+// BuildMatrix fans a trigger of a ci pipeline out into a build for every combination of its target platforms, build
+// args and dockerfile paths, an empty dimension keeps the build config of the pipeline
+type BuildMatrix struct {
+	CiPipelineId    int                 `json:"ciPipelineId"`
+	TargetPlatforms []string            `json:"targetPlatforms,omitempty"`
+	BuildArgs       []map[string]string `json:"buildArgs,omitempty"`
+	DockerfilePaths []string            `json:"dockerfilePaths,omitempty"`
+	OutputType      string              `json:"outputType" validate:"oneof=MULTIPLE_ARTIFACTS MANIFEST_LIST"`
+	Enabled         bool                `json:"enabled"`
+}
+
+// MatrixCell is one combination of a build matrix, BuildArgs are merged over the args of the pipeline. Key identifies
+// the cell in its run and suffixes the image tag of its build.
+type MatrixCell struct {
+	Key            string            `json:"key"`
+	TargetPlatform string            `json:"targetPlatform,omitempty"`
+	BuildArgs      map[string]string `json:"buildArgs,omitempty"`
+	DockerfilePath string            `json:"dockerfilePath,omitempty"`
+}
+
+type MatrixRun struct {
+	Id              int              `json:"id"`
+	CiPipelineId    int              `json:"ciPipelineId"`
+	Status          string           `json:"status"`
+	OutputType      string           `json:"outputType"`
+	Image           string           `json:"image,omitempty"`
+	ImageDigest     string           `json:"imageDigest,omitempty"`
+	ManifestStatus  string           `json:"manifestStatus"`
+	ManifestMessage string           `json:"manifestMessage,omitempty"`
+	TriggeredBy     int32            `json:"triggeredBy"`
+	StartedOn       time.Time        `json:"startedOn"`
+	Cells           []*MatrixRunCell `json:"cells"`
+}
+
+// MatrixRunCell is a cell of a matrix run with the status of its ci workflow, the logs of which are those of the
+// workflow
+type MatrixRunCell struct {
+	MatrixCell
+	CiWorkflowId int       `json:"ciWorkflowId"`
+	Status       string    `json:"status"`
+	PodStatus    string    `json:"podStatus"`
+	Message      string    `json:"message"`
+	Image        string    `json:"image,omitempty"`
+	ImageDigest  string    `json:"imageDigest,omitempty"`
+	StartedOn    time.Time `json:"startedOn"`
+	FinishedOn   time.Time `json:"finishedOn"`
+}
+
+// CellCompletion tells what to save for the successful build of a ci workflow. Workflows which are not matrix cells and
+// cells of runs with multiple artifacts save their own image, the last cell of a manifest list run saves the list.
+type CellCompletion struct {
+	IsMatrixCell bool
+	SaveArtifact bool
+	Image        string
+	ImageDigest  string
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/pipeline/buildMatrix/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type CiPipelineBuildMatrix struct {
+	tableName    struct{} `sql:"ci_pipeline_build_matrix" pg:",discard_unknown_columns"`
+	Id           int      `sql:"id,pk"`
+	CiPipelineId int      `sql:"ci_pipeline_id,notnull"`
+	Matrix       string   `sql:"matrix,notnull"`
+	OutputType   string   `sql:"output_type,notnull"`
+	Enabled      bool     `sql:"enabled,notnull"`
+	sql.AuditLog
+}
+
+type CiMatrixRun struct {
+	tableName        struct{}  `sql:"ci_matrix_run" pg:",discard_unknown_columns"`
+	Id               int       `sql:"id,pk"`
+	CiPipelineId     int       `sql:"ci_pipeline_id,notnull"`
+	OutputType       string    `sql:"output_type,notnull"`
+	DockerRegistryId string    `sql:"docker_registry_id"`
+	Image            string    `sql:"image"`
+	ImageDigest      string    `sql:"image_digest"`
+	ManifestStatus   string    `sql:"manifest_status,notnull"`
+	ManifestMessage  string    `sql:"manifest_message"`
+	TriggeredBy      int32     `sql:"triggered_by,notnull"`
+	StartedOn        time.Time `sql:"started_on,type:timestamptz"`
+}
+
+type CiMatrixRunCell struct {
+	tableName      struct{} `sql:"ci_matrix_run_cell" pg:",discard_unknown_columns"`
+	Id             int      `sql:"id,pk"`
+	MatrixRunId    int      `sql:"matrix_run_id,notnull"`
+	CiWorkflowId   int      `sql:"ci_workflow_id,notnull"`
+	CellKey        string   `sql:"cell_key,notnull"`
+	TargetPlatform string   `sql:"target_platform"`
+	BuildArgs      string   `sql:"build_args"`
+	DockerfilePath string   `sql:"dockerfile_path"`
+	Image          string   `sql:"image"`
+	ImageDigest    string   `sql:"image_digest"`
+}
+
+// CiMatrixRunCellWithStatus is a cell with the state of its ci workflow
+type CiMatrixRunCellWithStatus struct {
+	CiMatrixRunCell
+	Status     string    `sql:"status"`
+	PodStatus  string    `sql:"pod_status"`
+	Message    string    `sql:"message"`
+	StartedOn  time.Time `sql:"started_on"`
+	FinishedOn time.Time `sql:"finished_on"`
+}
+
+type BuildMatrixRepository interface {
+	FindMatrixByCiPipelineId(ciPipelineId int) (*CiPipelineBuildMatrix, error)
+	SaveMatrix(matrix *CiPipelineBuildMatrix) error
+	UpdateMatrix(matrix *CiPipelineBuildMatrix) error
+	SaveRun(run *CiMatrixRun, cells []*CiMatrixRunCell) error
+	UpdateRun(run *CiMatrixRun) error
+	// MarkRunManifestCreating moves the manifest of the run from pending to creating, it returns false when another
+	// cell of the run did it already
+	MarkRunManifestCreating(runId int) (bool, error)
+	FindRunById(runId int) (*CiMatrixRun, error)
+	FindRunsByCiPipelineId(ciPipelineId int, offset int, size int) ([]*CiMatrixRun, error)
+	UpdateCell(cell *CiMatrixRunCell) error
+	FindCellByCiWorkflowId(ciWorkflowId int) (*CiMatrixRunCell, error)
+	FindCellsWithStatusByRunIds(runIds []int) ([]*CiMatrixRunCellWithStatus, error)
+}
+
+type BuildMatrixRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewBuildMatrixRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *BuildMatrixRepositoryImpl {
+	return &BuildMatrixRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *BuildMatrixRepositoryImpl) FindMatrixByCiPipelineId(ciPipelineId int) (*CiPipelineBuildMatrix, error) {
+	matrix := &CiPipelineBuildMatrix{}
+	err := impl.dbConnection.Model(matrix).
+		Where("ci_pipeline_id = ?", ciPipelineId).
+		Select()
+	if err != nil {
+		return nil, err
+	}
+	return matrix, nil
+}
+
+func (impl *BuildMatrixRepositoryImpl) SaveMatrix(matrix *CiPipelineBuildMatrix) error {
+	return impl.dbConnection.Insert(matrix)
+}
+
+func (impl *BuildMatrixRepositoryImpl) UpdateMatrix(matrix *CiPipelineBuildMatrix) error {
+	return impl.dbConnection.Update(matrix)
+}
+
+func (impl *BuildMatrixRepositoryImpl) SaveRun(run *CiMatrixRun, cells []*CiMatrixRunCell) error {
+	tx, err := impl.dbConnection.Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	err = tx.Insert(run)
+	if err != nil {
+		impl.logger.Errorw("error in saving matrix run", "err", err, "ciPipelineId", run.CiPipelineId)
+		return err
+	}
+	for _, cell := range cells {
+		cell.MatrixRunId = run.Id
+	}
+	err = tx.Insert(&cells)
+	if err != nil {
+		impl.logger.Errorw("error in saving matrix run cells", "err", err, "runId", run.Id)
+		return err
+	}
+	return tx.Commit()
+}
+
+func (impl *BuildMatrixRepositoryImpl) UpdateRun(run *CiMatrixRun) error {
+	return impl.dbConnection.Update(run)
+}
+
+func (impl *BuildMatrixRepositoryImpl) MarkRunManifestCreating(runId int) (bool, error) {
+	result, err := impl.dbConnection.Model((*CiMatrixRun)(nil)).
+		Set("manifest_status = ?", bean.ManifestStatusCreating).
+		Where("id = ?", runId).
+		Where("manifest_status = ?", bean.ManifestStatusPending).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (impl *BuildMatrixRepositoryImpl) FindRunById(runId int) (*CiMatrixRun, error) {
+	run := &CiMatrixRun{}
+	err := impl.dbConnection.Model(run).
+		Where("id = ?", runId).
+		Select()
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+func (impl *BuildMatrixRepositoryImpl) FindRunsByCiPipelineId(ciPipelineId int, offset int, size int) ([]*CiMatrixRun, error) {
+	var runs []*CiMatrixRun
+	err := impl.dbConnection.Model(&runs).
+		Where("ci_pipeline_id = ?", ciPipelineId).
+		Order("id DESC").
+		Offset(offset).Limit(size).
+		Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting matrix runs", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	return runs, nil
+}
+
+func (impl *BuildMatrixRepositoryImpl) UpdateCell(cell *CiMatrixRunCell) error {
+	return impl.dbConnection.Update(cell)
+}
+
+func (impl *BuildMatrixRepositoryImpl) FindCellByCiWorkflowId(ciWorkflowId int) (*CiMatrixRunCell, error) {
+	cell := &CiMatrixRunCell{}
+	err := impl.dbConnection.Model(cell).
+		Where("ci_workflow_id = ?", ciWorkflowId).
+		Select()
+	if err != nil {
+		return nil, err
+	}
+	return cell, nil
+}
+
+func (impl *BuildMatrixRepositoryImpl) FindCellsWithStatusByRunIds(runIds []int) ([]*CiMatrixRunCellWithStatus, error) {
+	var cells []*CiMatrixRunCellWithStatus
+	if len(runIds) == 0 {
+		return cells, nil
+	}
+	query := "SELECT c.*, cw.status, cw.pod_status, cw.message, cw.started_on, cw.finished_on FROM ci_matrix_run_cell c" +
+		" INNER JOIN ci_workflow cw ON cw.id = c.ci_workflow_id" +
+		" WHERE c.matrix_run_id IN (?) ORDER BY c.id ASC;"
+	_, err := impl.dbConnection.Query(&cells, query, pg.In(runIds))
+	if err != nil {
+		impl.logger.Errorw("error in getting matrix run cells", "err", err, "runIds", runIds)
+		return nil, err
+	}
+	return cells, nil
+}
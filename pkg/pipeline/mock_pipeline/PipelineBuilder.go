@@ -72,18 +72,18 @@ func (mr *MockPipelineBuilderMockRecorder) ChangePipelineDeploymentType(ctx, req
 }
 
 // CreateApp mocks base method.
-func (m *MockPipelineBuilder) CreateApp(request *bean0.CreateAppDTO) (*bean0.CreateAppDTO, error) {
+func (m *MockPipelineBuilder) CreateApp(request *bean0.CreateAppDTO, ctx context.Context) (*bean0.CreateAppDTO, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateApp", request)
+	ret := m.ctrl.Call(m, "CreateApp", request, ctx)
 	ret0, _ := ret[0].(*bean0.CreateAppDTO)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateApp indicates an expected call of CreateApp.
-func (mr *MockPipelineBuilderMockRecorder) CreateApp(request interface{}) *gomock.Call {
+func (mr *MockPipelineBuilderMockRecorder) CreateApp(request, ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApp", reflect.TypeOf((*MockPipelineBuilder)(nil).CreateApp), request)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApp", reflect.TypeOf((*MockPipelineBuilder)(nil).CreateApp), request, ctx)
 }
 
 // CreateCdPipelines mocks base method.
@@ -146,17 +146,17 @@ func (mr *MockPipelineBuilderMockRecorder) DeleteACDAppCdPipelineWithNonCascade(
 }
 
 // DeleteApp mocks base method.
-func (m *MockPipelineBuilder) DeleteApp(appId int, userId int32) error {
+func (m *MockPipelineBuilder) DeleteApp(appId int, userId int32, ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteApp", appId, userId)
+	ret := m.ctrl.Call(m, "DeleteApp", appId, userId, ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteApp indicates an expected call of DeleteApp.
-func (mr *MockPipelineBuilderMockRecorder) DeleteApp(appId, userId interface{}) *gomock.Call {
+func (mr *MockPipelineBuilderMockRecorder) DeleteApp(appId, userId, ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApp", reflect.TypeOf((*MockPipelineBuilder)(nil).DeleteApp), appId, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApp", reflect.TypeOf((*MockPipelineBuilder)(nil).DeleteApp), appId, userId, ctx)
 }
 
 // DeleteCdPipeline mocks base method.
@@ -801,18 +801,18 @@ func (mr *MockPipelineBuilderMockRecorder) PatchCiMaterialSource(ciPipeline, use
 }
 
 // PatchCiPipeline mocks base method.
-func (m *MockPipelineBuilder) PatchCiPipeline(request *bean0.CiPatchRequest) (*bean0.CiConfigRequest, error) {
+func (m *MockPipelineBuilder) PatchCiPipeline(request *bean0.CiPatchRequest, ctx context.Context) (*bean0.CiConfigRequest, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PatchCiPipeline", request)
+	ret := m.ctrl.Call(m, "PatchCiPipeline", request, ctx)
 	ret0, _ := ret[0].(*bean0.CiConfigRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // PatchCiPipeline indicates an expected call of PatchCiPipeline.
-func (mr *MockPipelineBuilderMockRecorder) PatchCiPipeline(request interface{}) *gomock.Call {
+func (mr *MockPipelineBuilderMockRecorder) PatchCiPipeline(request, ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchCiPipeline", reflect.TypeOf((*MockPipelineBuilder)(nil).PatchCiPipeline), request)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchCiPipeline", reflect.TypeOf((*MockPipelineBuilder)(nil).PatchCiPipeline), request, ctx)
 }
 
 // PatchRegexCiPipeline mocks base method.
@@ -9,6 +9,7 @@ import (
 )
 
 type PipelineStageType string
+type PipelineStageExecutionMode string
 type PipelineStepType string
 type PipelineStageStepVariableType string
 type PipelineStageStepVariableValueType string
@@ -20,6 +21,8 @@ const (
 	PIPELINE_STAGE_TYPE_POST_CI                      PipelineStageType                   = "POST_CI"
 	PIPELINE_STAGE_TYPE_PRE_CD                       PipelineStageType                   = "PRE_CD"
 	PIPELINE_STAGE_TYPE_POST_CD                      PipelineStageType                   = "POST_CD"
+	PIPELINE_STAGE_EXECUTION_MODE_SEQUENTIAL         PipelineStageExecutionMode          = "SEQUENTIAL"
+	PIPELINE_STAGE_EXECUTION_MODE_DAG                PipelineStageExecutionMode          = "DAG"
 	PIPELINE_STEP_TYPE_INLINE                        PipelineStepType                    = "INLINE"
 	PIPELINE_STEP_TYPE_REF_PLUGIN                    PipelineStepType                    = "REF_PLUGIN"
 	PIPELINE_STAGE_STEP_VARIABLE_TYPE_INPUT          PipelineStageStepVariableType       = "INPUT"
@@ -46,6 +49,8 @@ type PipelineStage struct {
 	Deleted      bool              `sql:"deleted, notnull"`
 	CiPipelineId int               `sql:"ci_pipeline_id"`
 	CdPipelineId int               `sql:"cd_pipeline_id"`
+	// ExecutionMode is empty for the stages saved before steps could run in parallel, which run in index order
+	ExecutionMode PipelineStageExecutionMode `sql:"execution_mode"`
 	sql.AuditLog
 }
 
@@ -61,8 +66,13 @@ type PipelineStageStep struct {
 	RefPluginId              int              `sql:"ref_plugin_id"` //id of plugin used as reference
 	OutputDirectoryPath      []string         `sql:"output_directory_path" pg:",array"`
 	DependentOnStep          string           `sql:"dependent_on_step"`
+	DependsOnStepIndexes     []int            `sql:"depends_on_step_indexes" pg:",array"` //only for stages with DAG execution mode
 	Deleted                  bool             `sql:"deleted,notnull"`
 	TriggerIfParentStageFail bool             `sql:"trigger_if_parent_stage_fail"`
+	MaxRetries               int              `sql:"max_retries,notnull"`
+	RetryBackoffSeconds      int              `sql:"retry_backoff_seconds,notnull"`
+	TimeoutSeconds           int              `sql:"timeout_seconds,notnull"`
+	ContinueOnFailure        bool             `sql:"continue_on_failure,notnull"`
 	sql.AuditLog
 }
 
@@ -0,0 +1,136 @@
+package stageStep
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ValidateStageStepDependencies checks that the steps of a DAG stage don't depend on each other in a cycle, and that
+// a step of any stage only refers to the output variables of the steps which are done before it starts
+func ValidateStageStepDependencies(stageReq *bean.PipelineStageDto, stageType repository.PipelineStageType) error {
+	if stageReq == nil {
+		return nil
+	}
+	stepNames := make(map[int]string)
+	declaredDependencies := make(map[int][]int)
+	var stepIndexes []int
+	for _, step := range stageReq.Steps {
+		if stageReq.ExecutionMode != repository.PIPELINE_STAGE_EXECUTION_MODE_DAG && len(step.DependsOn) > 0 {
+			return newStageStepValidationError(fmt.Sprintf("step %q can declare dependencies only in a stage with %s execution mode", step.Name, repository.PIPELINE_STAGE_EXECUTION_MODE_DAG))
+		}
+		if _, ok := stepNames[step.Index]; ok && stageReq.ExecutionMode == repository.PIPELINE_STAGE_EXECUTION_MODE_DAG {
+			return newStageStepValidationError(fmt.Sprintf("steps %q and %q have the same index %d", stepNames[step.Index], step.Name, step.Index))
+		}
+		stepNames[step.Index] = step.Name
+		declaredDependencies[step.Index] = step.DependsOn
+		stepIndexes = append(stepIndexes, step.Index)
+	}
+	if stageReq.ExecutionMode == repository.PIPELINE_STAGE_EXECUTION_MODE_DAG {
+		graph := make(map[int][]int)
+		for _, step := range stageReq.Steps {
+			if _, ok := graph[step.Index]; !ok {
+				graph[step.Index] = nil
+			}
+			for _, dependency := range step.DependsOn {
+				if _, ok := stepNames[dependency]; !ok || dependency == step.Index {
+					return newStageStepValidationError(fmt.Sprintf("step %q depends on invalid step index %d", step.Name, dependency))
+				}
+				graph[dependency] = append(graph[dependency], step.Index)
+			}
+		}
+		sorted := util.TopoSort(graph)
+		if len(sorted) < len(graph) {
+			sortedIndexes := make(map[int]bool)
+			for _, index := range sorted {
+				sortedIndexes[index] = true
+			}
+			var cycleSteps []string
+			for _, index := range stepIndexes {
+				if !sortedIndexes[index] {
+					cycleSteps = append(cycleSteps, stepNames[index])
+				}
+			}
+			return newStageStepValidationError(fmt.Sprintf("steps %s can not be ordered as their dependencies form a cycle", strings.Join(cycleSteps, ", ")))
+		}
+	}
+	ancestors := getStepAncestors(GetStepDependencies(stageReq.ExecutionMode, stepIndexes, declaredDependencies))
+	for _, step := range stageReq.Steps {
+		var inputVariables []*bean.StepVariableDto
+		if step.InlineStepDetail != nil {
+			inputVariables = step.InlineStepDetail.InputVariables
+		} else if step.RefPluginStepDetail != nil {
+			inputVariables = step.RefPluginStepDetail.InputVariables
+		}
+		for _, variable := range inputVariables {
+			if variable.ValueType != repository.PIPELINE_STAGE_STEP_VARIABLE_VALUE_TYPE_PREVIOUS {
+				continue
+			}
+			if len(variable.ReferenceVariableStage) > 0 && variable.ReferenceVariableStage != stageType {
+				//refers to a step of another stage, which is done before this stage starts
+				continue
+			}
+			if !ancestors[step.Index][variable.PreviousStepIndex] {
+				return newStageStepValidationError(fmt.Sprintf("input variable %q of step %q refers to step index %d which is not done before the step starts", variable.Name, step.Name, variable.PreviousStepIndex))
+			}
+		}
+	}
+	return nil
+}
+
+// GetStepDependencies returns the indexes of the steps after which each step starts, steps of a sequential stage
+// start after the step before them in index order
+func GetStepDependencies(executionMode repository.PipelineStageExecutionMode, stepIndexes []int, declaredDependencies map[int][]int) map[int][]int {
+	dependencies := make(map[int][]int)
+	if executionMode == repository.PIPELINE_STAGE_EXECUTION_MODE_DAG {
+		for _, index := range stepIndexes {
+			dependencies[index] = declaredDependencies[index]
+		}
+		return dependencies
+	}
+	sortedIndexes := make([]int, len(stepIndexes))
+	copy(sortedIndexes, stepIndexes)
+	sort.Ints(sortedIndexes)
+	for i, index := range sortedIndexes {
+		if i > 0 && sortedIndexes[i-1] != index {
+			dependencies[index] = []int{sortedIndexes[i-1]}
+		}
+	}
+	return dependencies
+}
+
+// getStepAncestors returns the indexes of the steps done before each step starts, dependencies must have no cycle
+func getStepAncestors(dependencies map[int][]int) map[int]map[int]bool {
+	ancestors := make(map[int]map[int]bool)
+	var collect func(index int) map[int]bool
+	collect = func(index int) map[int]bool {
+		if stepAncestors, ok := ancestors[index]; ok {
+			return stepAncestors
+		}
+		stepAncestors := make(map[int]bool)
+		for _, dependency := range dependencies[index] {
+			stepAncestors[dependency] = true
+			for ancestor := range collect(dependency) {
+				stepAncestors[ancestor] = true
+			}
+		}
+		ancestors[index] = stepAncestors
+		return stepAncestors
+	}
+	for index := range dependencies {
+		collect(index)
+	}
+	return ancestors
+}
+
+func newStageStepValidationError(message string) *util.ApiError {
+	return &util.ApiError{
+		HttpStatusCode:  http.StatusBadRequest,
+		UserMessage:     message,
+		InternalMessage: message,
+	}
+}
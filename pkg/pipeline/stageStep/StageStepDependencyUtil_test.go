@@ -0,0 +1,92 @@
+package stageStep
+
+import (
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func getDagTestStage(dependsOn map[int][]int, references map[int]int) *bean.PipelineStageDto {
+	stage := &bean.PipelineStageDto{ExecutionMode: repository.PIPELINE_STAGE_EXECUTION_MODE_DAG}
+	for index, name := range []string{"checkout", "lint", "unit-test", "sast", "report"} {
+		step := &bean.PipelineStageStepDto{
+			Name:             name,
+			Index:            index + 1,
+			StepType:         repository.PIPELINE_STEP_TYPE_INLINE,
+			DependsOn:        dependsOn[index+1],
+			InlineStepDetail: &bean.InlineStepDetailDto{},
+		}
+		if previousStepIndex, ok := references[index+1]; ok {
+			step.InlineStepDetail.InputVariables = []*bean.StepVariableDto{{
+				Name:                   "RESULT",
+				ValueType:              repository.PIPELINE_STAGE_STEP_VARIABLE_VALUE_TYPE_PREVIOUS,
+				PreviousStepIndex:      previousStepIndex,
+				ReferenceVariableName:  "RESULT",
+				ReferenceVariableStage: repository.PIPELINE_STAGE_TYPE_PRE_CI,
+			}}
+		}
+		stage.Steps = append(stage.Steps, step)
+	}
+	return stage
+}
+
+func assertBadRequest(t *testing.T, err error, message string) {
+	apiError, ok := err.(*util.ApiError)
+	if assert.True(t, ok, "expected api error, got %v", err) {
+		assert.Equal(t, http.StatusBadRequest, apiError.HttpStatusCode)
+		assert.Contains(t, apiError.UserMessage, message)
+	}
+}
+
+func TestValidateStageStepDependencies(t *testing.T) {
+	// lint, unit-test and sast run in parallel after checkout, report after all of them
+	dependsOn := map[int][]int{2: {1}, 3: {1}, 4: {1}, 5: {2, 3, 4}}
+	err := ValidateStageStepDependencies(getDagTestStage(dependsOn, map[int]int{5: 4, 3: 1}), repository.PIPELINE_STAGE_TYPE_PRE_CI)
+	assert.NoError(t, err)
+
+	t.Run("cycle", func(t *testing.T) {
+		err := ValidateStageStepDependencies(getDagTestStage(map[int][]int{2: {1, 4}, 3: {2}, 4: {3}, 5: {4}}, nil), repository.PIPELINE_STAGE_TYPE_PRE_CI)
+		assertBadRequest(t, err, "steps lint, unit-test, sast, report can not be ordered as their dependencies form a cycle")
+	})
+	t.Run("invalid step index", func(t *testing.T) {
+		err := ValidateStageStepDependencies(getDagTestStage(map[int][]int{2: {6}}, nil), repository.PIPELINE_STAGE_TYPE_PRE_CI)
+		assertBadRequest(t, err, "invalid step index 6")
+		err = ValidateStageStepDependencies(getDagTestStage(map[int][]int{2: {2}}, nil), repository.PIPELINE_STAGE_TYPE_PRE_CI)
+		assertBadRequest(t, err, "invalid step index 2")
+	})
+	t.Run("reference to a parallel step", func(t *testing.T) {
+		err := ValidateStageStepDependencies(getDagTestStage(dependsOn, map[int]int{4: 3}), repository.PIPELINE_STAGE_TYPE_PRE_CI)
+		assertBadRequest(t, err, `input variable "RESULT" of step "sast" refers to step index 3`)
+	})
+	t.Run("reference to another stage", func(t *testing.T) {
+		err := ValidateStageStepDependencies(getDagTestStage(dependsOn, map[int]int{4: 3}), repository.PIPELINE_STAGE_TYPE_POST_CI)
+		assert.NoError(t, err)
+	})
+	t.Run("sequential stage", func(t *testing.T) {
+		stage := getDagTestStage(nil, map[int]int{5: 4})
+		stage.ExecutionMode = ""
+		assert.NoError(t, ValidateStageStepDependencies(stage, repository.PIPELINE_STAGE_TYPE_PRE_CI))
+		stage = getDagTestStage(nil, map[int]int{4: 5})
+		stage.ExecutionMode = repository.PIPELINE_STAGE_EXECUTION_MODE_SEQUENTIAL
+		assertBadRequest(t, ValidateStageStepDependencies(stage, repository.PIPELINE_STAGE_TYPE_PRE_CI), "refers to step index 5")
+		stage = getDagTestStage(map[int][]int{2: {1}}, nil)
+		stage.ExecutionMode = repository.PIPELINE_STAGE_EXECUTION_MODE_SEQUENTIAL
+		assertBadRequest(t, ValidateStageStepDependencies(stage, repository.PIPELINE_STAGE_TYPE_PRE_CI), "can declare dependencies only")
+	})
+}
+
+func TestGetStepDependencies(t *testing.T) {
+	dependencies := GetStepDependencies("", []int{3, 1, 2}, map[int][]int{3: {1}})
+	assert.Equal(t, map[int][]int{2: {1}, 3: {2}}, dependencies)
+
+	dependencies = GetStepDependencies(repository.PIPELINE_STAGE_EXECUTION_MODE_DAG, []int{1, 2, 3}, map[int][]int{3: {1}})
+	assert.Nil(t, dependencies[2])
+	assert.Equal(t, []int{1}, dependencies[3])
+
+	ancestors := getStepAncestors(map[int][]int{1: nil, 2: {1}, 3: {1}, 4: {2, 3}})
+	assert.Equal(t, map[int]bool{1: true, 2: true, 3: true}, ancestors[4])
+	assert.Empty(t, ancestors[1])
+}
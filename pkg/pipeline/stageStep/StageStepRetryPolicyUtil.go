@@ -0,0 +1,30 @@
+package stageStep
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+)
+
+const (
+	MaxStageStepRetries             = 10
+	MaxStageStepRetryBackoffSeconds = 3600
+)
+
+// ValidateStageStepRetryPolicies checks the retries, backoff and timeout of the steps of a stage
+func ValidateStageStepRetryPolicies(stageReq *bean.PipelineStageDto) error {
+	if stageReq == nil {
+		return nil
+	}
+	for _, step := range stageReq.Steps {
+		if step.MaxRetries < 0 || step.MaxRetries > MaxStageStepRetries {
+			return newStageStepValidationError(fmt.Sprintf("max retries of step %q must be between 0 and %d", step.Name, MaxStageStepRetries))
+		}
+		if step.RetryBackoffSeconds < 0 || step.RetryBackoffSeconds > MaxStageStepRetryBackoffSeconds {
+			return newStageStepValidationError(fmt.Sprintf("retry backoff of step %q must be between 0 and %d seconds", step.Name, MaxStageStepRetryBackoffSeconds))
+		}
+		if step.TimeoutSeconds < 0 {
+			return newStageStepValidationError(fmt.Sprintf("timeout of step %q can not be negative", step.Name))
+		}
+	}
+	return nil
+}
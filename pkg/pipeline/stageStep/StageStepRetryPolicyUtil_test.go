@@ -0,0 +1,24 @@
+package stageStep
+
+import (
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidateStageStepRetryPolicies(t *testing.T) {
+	stage := &bean.PipelineStageDto{Steps: []*bean.PipelineStageStepDto{
+		{Name: "integration-test", Index: 1, MaxRetries: 3, RetryBackoffSeconds: 30, TimeoutSeconds: 900, ContinueOnFailure: true},
+		{Name: "lint", Index: 2},
+	}}
+	assert.NoError(t, ValidateStageStepRetryPolicies(stage))
+
+	stage.Steps[0].MaxRetries = MaxStageStepRetries + 1
+	assertBadRequest(t, ValidateStageStepRetryPolicies(stage), `max retries of step "integration-test"`)
+	stage.Steps[0].MaxRetries = 3
+	stage.Steps[0].RetryBackoffSeconds = -1
+	assertBadRequest(t, ValidateStageStepRetryPolicies(stage), `retry backoff of step "integration-test"`)
+	stage.Steps[0].RetryBackoffSeconds = 30
+	stage.Steps[1].TimeoutSeconds = -5
+	assertBadRequest(t, ValidateStageStepRetryPolicies(stage), `timeout of step "lint"`)
+}
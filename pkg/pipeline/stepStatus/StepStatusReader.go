@@ -0,0 +1,57 @@
+package stepStatus
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/pkg/pipeline/stepStatus/bean"
+	"io"
+	"io/ioutil"
+	"path"
+)
+
+// maxStepStatusesFileSize is the largest step statuses file of the artifacts which is read
+const maxStepStatusesFileSize = 1 << 20
+
+var errStepStatusesFileTooLarge = errors.New("step statuses file is too large")
+
+// ReadStepStatusesFromZip returns the step statuses the runner wrote in the artifacts zip, none are returned when the
+// zip has no step statuses file
+func ReadStepStatusesFromZip(zipPath string) ([]*bean.StepStatus, error) {
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() || path.Base(file.Name) != bean.StepStatusesFileName {
+			continue
+		}
+		return readStepStatuses(file)
+	}
+	return nil, nil
+}
+
+func readStepStatuses(file *zip.File) ([]*bean.StepStatus, error) {
+	if file.UncompressedSize64 > maxStepStatusesFileSize {
+		return nil, errStepStatusesFileTooLarge
+	}
+	fileReader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer fileReader.Close()
+	content, err := ioutil.ReadAll(io.LimitReader(fileReader, maxStepStatusesFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxStepStatusesFileSize {
+		return nil, errStepStatusesFileTooLarge
+	}
+	var statuses []*bean.StepStatus
+	err = json.Unmarshal(content, &statuses)
+	if err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
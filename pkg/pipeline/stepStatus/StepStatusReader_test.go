@@ -0,0 +1,46 @@
+package stepStatus
+
+import (
+	"archive/zip"
+	"github.com/devtron-labs/devtron/pkg/pipeline/stepStatus/bean"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func writeZip(t *testing.T, files map[string]string) string {
+	zipFile, err := ioutil.TempFile("", "artifacts-*.zip")
+	assert.NoError(t, err)
+	defer zipFile.Close()
+	writer := zip.NewWriter(zipFile)
+	for name, content := range files {
+		fileWriter, err := writer.Create(name)
+		assert.NoError(t, err)
+		_, err = fileWriter.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+	return zipFile.Name()
+}
+
+func TestReadStepStatusesFromZip(t *testing.T) {
+	zipPath := writeZip(t, map[string]string{
+		"reports/junit.xml":                "<testsuite/>",
+		"out/" + bean.StepStatusesFileName: `[{"stageType":"PRE_CD","stepIndex":1,"stepName":"migrate","status":"FAILED","attempts":2}]`,
+	})
+	defer os.Remove(zipPath)
+	statuses, err := ReadStepStatusesFromZip(zipPath)
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 1)
+	assert.Equal(t, "migrate", statuses[0].StepName)
+	assert.Equal(t, bean.StepStatusFailed, statuses[0].Status)
+	assert.Equal(t, 2, statuses[0].Attempts)
+
+	// artifacts of a runner which wrote no step statuses
+	zipPath = writeZip(t, map[string]string{"reports/junit.xml": "<testsuite/>"})
+	defer os.Remove(zipPath)
+	statuses, err = ReadStepStatusesFromZip(zipPath)
+	assert.NoError(t, err)
+	assert.Empty(t, statuses)
+}
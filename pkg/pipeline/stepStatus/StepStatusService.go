@@ -0,0 +1,84 @@
+package stepStatus
+
+import (
+	"github.com/devtron-labs/devtron/pkg/pipeline/stepStatus/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/stepStatus/repository"
+	"go.uber.org/zap"
+	"time"
+)
+
+type StepStatusService interface {
+	// SaveStepStatuses replaces the step statuses of the workflow with the ones reported by the runner, nothing is
+	// saved when the runner reported none
+	SaveStepStatuses(workflowType string, workflowId int, statuses []*bean.StepStatus) error
+	// GetStepStatuses returns the step statuses of the workflow in the order the runner reported them
+	GetStepStatuses(workflowType string, workflowId int) ([]*bean.StepStatus, error)
+}
+
+type StepStatusServiceImpl struct {
+	logger               *zap.SugaredLogger
+	stepStatusRepository repository.StepStatusRepository
+}
+
+func NewStepStatusServiceImpl(logger *zap.SugaredLogger, stepStatusRepository repository.StepStatusRepository) *StepStatusServiceImpl {
+	return &StepStatusServiceImpl{
+		logger:               logger,
+		stepStatusRepository: stepStatusRepository,
+	}
+}
+
+func (impl *StepStatusServiceImpl) SaveStepStatuses(workflowType string, workflowId int, statuses []*bean.StepStatus) error {
+	if len(statuses) == 0 {
+		return nil
+	}
+	now := time.Now()
+	models := make([]*repository.WorkflowStepStatus, 0, len(statuses))
+	for _, status := range statuses {
+		if status == nil {
+			continue
+		}
+		models = append(models, &repository.WorkflowStepStatus{
+			WorkflowType:      workflowType,
+			WorkflowId:        workflowId,
+			StageType:         status.StageType,
+			StepIndex:         status.StepIndex,
+			StepName:          status.StepName,
+			Status:            status.Status,
+			Attempts:          status.Attempts,
+			ContinueOnFailure: status.ContinueOnFailure,
+			StartedOn:         status.StartedOn,
+			FinishedOn:        status.FinishedOn,
+			Message:           status.Message,
+			CreatedOn:         now,
+		})
+	}
+	err := impl.stepStatusRepository.SaveWorkflowStepStatuses(workflowType, workflowId, models)
+	if err != nil {
+		impl.logger.Errorw("error in saving step statuses", "err", err, "workflowType", workflowType, "workflowId", workflowId)
+		return err
+	}
+	return nil
+}
+
+func (impl *StepStatusServiceImpl) GetStepStatuses(workflowType string, workflowId int) ([]*bean.StepStatus, error) {
+	models, err := impl.stepStatusRepository.FindByWorkflow(workflowType, workflowId)
+	if err != nil {
+		impl.logger.Errorw("error in getting step statuses", "err", err, "workflowType", workflowType, "workflowId", workflowId)
+		return nil, err
+	}
+	statuses := make([]*bean.StepStatus, 0, len(models))
+	for _, model := range models {
+		statuses = append(statuses, &bean.StepStatus{
+			StageType:         model.StageType,
+			StepIndex:         model.StepIndex,
+			StepName:          model.StepName,
+			Status:            model.Status,
+			Attempts:          model.Attempts,
+			ContinueOnFailure: model.ContinueOnFailure,
+			StartedOn:         model.StartedOn,
+			FinishedOn:        model.FinishedOn,
+			Message:           model.Message,
+		})
+	}
+	return statuses, nil
+}
@@ -0,0 +1,51 @@
+package stepStatus
+
+import (
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/pipeline/stepStatus/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/stepStatus/repository"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type stepStatusRepositoryStub struct {
+	repository.StepStatusRepository
+	saveCount int
+	saved     []*repository.WorkflowStepStatus
+}
+
+func (stub *stepStatusRepositoryStub) SaveWorkflowStepStatuses(workflowType string, workflowId int, statuses []*repository.WorkflowStepStatus) error {
+	stub.saveCount++
+	stub.saved = statuses
+	return nil
+}
+
+func (stub *stepStatusRepositoryStub) FindByWorkflow(workflowType string, workflowId int) ([]*repository.WorkflowStepStatus, error) {
+	return stub.saved, nil
+}
+
+func TestSaveStepStatuses(t *testing.T) {
+	stepStatusRepository := &stepStatusRepositoryStub{}
+	logger, _ := util.NewSugardLogger()
+	service := NewStepStatusServiceImpl(logger, stepStatusRepository)
+
+	// a runner which reported no step status keeps the saved ones
+	assert.NoError(t, service.SaveStepStatuses(bean.WorkflowTypeCi, 12, nil))
+	assert.Equal(t, 0, stepStatusRepository.saveCount)
+
+	err := service.SaveStepStatuses(bean.WorkflowTypeCi, 12, []*bean.StepStatus{
+		{StageType: "PRE_CI", StepIndex: 1, StepName: "lint", Status: bean.StepStatusFailed, Attempts: 3, ContinueOnFailure: true},
+		nil,
+		{StageType: "PRE_CI", StepIndex: 2, StepName: "unit-test", Status: bean.StepStatusTimedOut, Attempts: 1},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, stepStatusRepository.saved, 2)
+	assert.Equal(t, 12, stepStatusRepository.saved[0].WorkflowId)
+
+	statuses, err := service.GetStepStatuses(bean.WorkflowTypeCi, 12)
+	assert.NoError(t, err)
+	assert.Equal(t, "lint", statuses[0].StepName)
+	assert.Equal(t, 3, statuses[0].Attempts)
+	assert.True(t, statuses[0].ContinueOnFailure)
+	assert.Equal(t, bean.StepStatusTimedOut, statuses[1].Status)
+}
@@ -0,0 +1,34 @@
+package bean
+
+import "time"
+
+const (
+	WorkflowTypeCi     = "CI"
+	WorkflowTypePreCd  = "PRE"
+	WorkflowTypePostCd = "POST"
+)
+
+const (
+	StepStatusSucceeded = "SUCCEEDED"
+	StepStatusFailed    = "FAILED"
+	StepStatusTimedOut  = "TIMED_OUT"
+	StepStatusSkipped   = "SKIPPED"
+)
+
+// StepStatusesFileName is the file of the uploaded pre/post cd stage artifacts in which the runner also writes the
+// step statuses, it is read when the stage failed without reporting them
+const StepStatusesFileName = "step-statuses.json"
+
+// StepStatus is the result of a pre/post stage step as reported by the runner. Attempts counts the first run and its
+// retries, a failed step with ContinueOnFailure didn't fail its stage.
+type StepStatus struct {
+	StageType         string    `json:"stageType"`
+	StepIndex         int       `json:"stepIndex"`
+	StepName          string    `json:"stepName"`
+	Status            string    `json:"status"`
+	Attempts          int       `json:"attempts"`
+	ContinueOnFailure bool      `json:"continueOnFailure,omitempty"`
+	StartedOn         time.Time `json:"startedOn"`
+	FinishedOn        time.Time `json:"finishedOn"`
+	Message           string    `json:"message,omitempty"`
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// WorkflowStepStatus is the result of a step of a ci workflow or a pre/post cd workflow runner, WorkflowId is of the ci
+// or the cd side according to WorkflowType
+type WorkflowStepStatus struct {
+	tableName         struct{}  `sql:"workflow_step_status" pg:",discard_unknown_columns"`
+	Id                int       `sql:"id,pk"`
+	WorkflowType      string    `sql:"workflow_type,notnull"`
+	WorkflowId        int       `sql:"workflow_id,notnull"`
+	StageType         string    `sql:"stage_type,notnull"`
+	StepIndex         int       `sql:"step_index,notnull"`
+	StepName          string    `sql:"step_name"`
+	Status            string    `sql:"status,notnull"`
+	Attempts          int       `sql:"attempts,notnull"`
+	ContinueOnFailure bool      `sql:"continue_on_failure,notnull"`
+	StartedOn         time.Time `sql:"started_on,type:timestamptz"`
+	FinishedOn        time.Time `sql:"finished_on,type:timestamptz"`
+	Message           string    `sql:"message"`
+	CreatedOn         time.Time `sql:"created_on,type:timestamptz"`
+}
+
+type StepStatusRepository interface {
+	// SaveWorkflowStepStatuses replaces the saved step statuses of the workflow
+	SaveWorkflowStepStatuses(workflowType string, workflowId int, statuses []*WorkflowStepStatus) error
+	FindByWorkflow(workflowType string, workflowId int) ([]*WorkflowStepStatus, error)
+}
+
+type StepStatusRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewStepStatusRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *StepStatusRepositoryImpl {
+	return &StepStatusRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *StepStatusRepositoryImpl) SaveWorkflowStepStatuses(workflowType string, workflowId int, statuses []*WorkflowStepStatus) error {
+	tx, err := impl.dbConnection.Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	_, err = tx.Model((*WorkflowStepStatus)(nil)).
+		Where("workflow_type = ?", workflowType).
+		Where("workflow_id = ?", workflowId).
+		Delete()
+	if err != nil {
+		impl.logger.Errorw("error in deleting workflow step statuses", "err", err, "workflowType", workflowType, "workflowId", workflowId)
+		return err
+	}
+	if len(statuses) > 0 {
+		err = tx.Insert(&statuses)
+		if err != nil {
+			impl.logger.Errorw("error in saving workflow step statuses", "err", err, "workflowType", workflowType, "workflowId", workflowId)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (impl *StepStatusRepositoryImpl) FindByWorkflow(workflowType string, workflowId int) ([]*WorkflowStepStatus, error) {
+	var statuses []*WorkflowStepStatus
+	err := impl.dbConnection.Model(&statuses).
+		Where("workflow_type = ?", workflowType).
+		Where("workflow_id = ?", workflowId).
+		Order("id ASC").Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting workflow step statuses", "err", err, "workflowType", workflowType, "workflowId", workflowId)
+		return nil, err
+	}
+	return statuses, nil
+}
@@ -0,0 +1,348 @@
+package testReport
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/pipeline/testReport/bean"
+	"io"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxFailureMessageLength = 1024
+	maxFailureDetailsLength = 8192
+	// maxReportFileSize is the largest xml file of the artifacts read as a test report
+	maxReportFileSize = 32 << 20
+)
+
+var errReportFileTooLarge = errors.New("report file is too large")
+
+// ReadReportFilesFromZip returns the xml files of the artifacts zip of a workflow by their path in it, files larger
+// than maxReportFileSize are skipped
+func ReadReportFilesFromZip(zipPath string) (map[string][]byte, error) {
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	files := make(map[string][]byte)
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() || !strings.HasSuffix(strings.ToLower(file.Name), ".xml") {
+			continue
+		}
+		content, err := readZipFile(file, maxReportFileSize)
+		if err == errReportFileTooLarge {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		files[file.Name] = content
+	}
+	return files, nil
+}
+
+// readZipFile reads the file up to maxSize bytes, the size in the zip header is not trusted
+func readZipFile(file *zip.File, maxSize int64) ([]byte, error) {
+	if file.UncompressedSize64 > uint64(maxSize) {
+		return nil, errReportFileTooLarge
+	}
+	fileReader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer fileReader.Close()
+	content, err := ioutil.ReadAll(io.LimitReader(fileReader, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > maxSize {
+		return nil, errReportFileTooLarge
+	}
+	return content, nil
+}
+
+// ParseTestReport parses a JUnit (including the xUnit and surefire variants), TestNG or xUnit.net v2 report, it
+// returns no suites for xml documents which are not test reports
+func ParseTestReport(fileName string, content []byte) ([]*bean.TestSuite, error) {
+	root := getRootElement(content)
+	var suites []*bean.TestSuite
+	var err error
+	switch root {
+	case "testsuites":
+		report := &junitTestSuites{}
+		err = xml.Unmarshal(content, report)
+		for i := range report.Suites {
+			suites = append(suites, report.Suites[i].toTestSuites("")...)
+		}
+	case "testsuite":
+		report := &junitTestSuite{}
+		err = xml.Unmarshal(content, report)
+		suites = report.toTestSuites("")
+	case "testng-results":
+		report := &testNgResults{}
+		err = xml.Unmarshal(content, report)
+		suites = report.toTestSuites()
+	case "assemblies":
+		report := &xunitAssemblies{}
+		err = xml.Unmarshal(content, report)
+		suites = report.toTestSuites()
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s report %s: %w", root, fileName, err)
+	}
+	for _, suite := range suites {
+		if len(suite.Name) == 0 {
+			suite.Name = strings.TrimSuffix(path.Base(fileName), path.Ext(fileName))
+		}
+	}
+	return suites, nil
+}
+
+// getRootElement returns the name of the root element of the document, empty when it is not xml
+func getRootElement(content []byte) string {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		if element, ok := token.(xml.StartElement); ok {
+			return element.Name.Local
+		}
+	}
+}
+
+type junitTestSuites struct {
+	Suites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name   string           `xml:"name,attr"`
+	Time   string           `xml:"time,attr"`
+	Suites []junitTestSuite `xml:"testsuite"`
+	Cases  []junitTestCase  `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failures  []junitResult `xml:"failure"`
+	Errors    []junitResult `xml:"error"`
+	Skipped   *junitResult  `xml:"skipped"`
+}
+
+type junitResult struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// toTestSuites flattens the nested suites, naming them by their path
+func (suite *junitTestSuite) toTestSuites(parentName string) []*bean.TestSuite {
+	name := suite.Name
+	if len(parentName) > 0 && len(name) > 0 {
+		name = parentName + "/" + name
+	} else if len(name) == 0 {
+		name = parentName
+	}
+	var suites []*bean.TestSuite
+	if len(suite.Cases) > 0 {
+		testSuite := &bean.TestSuite{Name: name}
+		for _, junitCase := range suite.Cases {
+			testCase := &bean.TestCase{
+				ClassName:  junitCase.ClassName,
+				Name:       junitCase.Name,
+				Status:     bean.TestCasePassed,
+				DurationMs: parseSeconds(junitCase.Time),
+			}
+			if len(junitCase.Errors) > 0 {
+				testCase.Status = bean.TestCaseErrored
+				setFailure(testCase, junitCase.Errors[0].Type, junitCase.Errors[0].Message, junitCase.Errors[0].Text)
+			} else if len(junitCase.Failures) > 0 {
+				testCase.Status = bean.TestCaseFailed
+				setFailure(testCase, junitCase.Failures[0].Type, junitCase.Failures[0].Message, junitCase.Failures[0].Text)
+			} else if junitCase.Skipped != nil {
+				testCase.Status = bean.TestCaseSkipped
+			}
+			testSuite.Cases = append(testSuite.Cases, testCase)
+		}
+		updateSuiteCounts(testSuite, parseSeconds(suite.Time))
+		suites = append(suites, testSuite)
+	}
+	for i := range suite.Suites {
+		suites = append(suites, suite.Suites[i].toTestSuites(name)...)
+	}
+	return suites
+}
+
+type testNgResults struct {
+	Suites []struct {
+		Name  string `xml:"name,attr"`
+		Tests []struct {
+			Name       string `xml:"name,attr"`
+			DurationMs int64  `xml:"duration-ms,attr"`
+			Classes    []struct {
+				Name    string         `xml:"name,attr"`
+				Methods []testNgMethod `xml:"test-method"`
+			} `xml:"class"`
+		} `xml:"test"`
+	} `xml:"suite"`
+}
+
+type testNgMethod struct {
+	Name       string `xml:"name,attr"`
+	Status     string `xml:"status,attr"`
+	DurationMs int64  `xml:"duration-ms,attr"`
+	IsConfig   bool   `xml:"is-config,attr"`
+	Exception  *struct {
+		Class      string `xml:"class,attr"`
+		Message    string `xml:"message"`
+		StackTrace string `xml:"full-stacktrace"`
+	} `xml:"exception"`
+}
+
+// toTestSuites maps every test of the TestNG suites to a suite, skipping the configuration methods
+func (results *testNgResults) toTestSuites() []*bean.TestSuite {
+	var suites []*bean.TestSuite
+	for _, testNgSuite := range results.Suites {
+		for _, test := range testNgSuite.Tests {
+			testSuite := &bean.TestSuite{Name: testNgSuite.Name + "/" + test.Name}
+			for _, class := range test.Classes {
+				for _, method := range class.Methods {
+					if method.IsConfig {
+						continue
+					}
+					testCase := &bean.TestCase{ClassName: class.Name, Name: method.Name, Status: bean.TestCasePassed, DurationMs: method.DurationMs}
+					switch strings.ToUpper(method.Status) {
+					case "FAIL":
+						testCase.Status = bean.TestCaseFailed
+						if method.Exception != nil {
+							setFailure(testCase, method.Exception.Class, method.Exception.Message, method.Exception.StackTrace)
+						}
+					case "SKIP":
+						testCase.Status = bean.TestCaseSkipped
+					}
+					testSuite.Cases = append(testSuite.Cases, testCase)
+				}
+			}
+			if len(testSuite.Cases) > 0 {
+				updateSuiteCounts(testSuite, test.DurationMs)
+				suites = append(suites, testSuite)
+			}
+		}
+	}
+	return suites
+}
+
+type xunitAssemblies struct {
+	Assemblies []struct {
+		Name        string `xml:"name,attr"`
+		Time        string `xml:"time,attr"`
+		Collections []struct {
+			Tests []struct {
+				Name    string `xml:"name,attr"`
+				Type    string `xml:"type,attr"`
+				Method  string `xml:"method,attr"`
+				Time    string `xml:"time,attr"`
+				Result  string `xml:"result,attr"`
+				Failure *struct {
+					ExceptionType string `xml:"exception-type,attr"`
+					Message       string `xml:"message"`
+					StackTrace    string `xml:"stack-trace"`
+				} `xml:"failure"`
+			} `xml:"test"`
+		} `xml:"collection"`
+	} `xml:"assembly"`
+}
+
+// toTestSuites maps every xUnit.net assembly to a suite
+func (assemblies *xunitAssemblies) toTestSuites() []*bean.TestSuite {
+	var suites []*bean.TestSuite
+	for _, assembly := range assemblies.Assemblies {
+		testSuite := &bean.TestSuite{Name: path.Base(strings.ReplaceAll(assembly.Name, "\\", "/"))}
+		for _, collection := range assembly.Collections {
+			for _, test := range collection.Tests {
+				name := test.Method
+				if len(name) == 0 {
+					name = test.Name
+				}
+				testCase := &bean.TestCase{ClassName: test.Type, Name: name, Status: bean.TestCasePassed, DurationMs: parseSeconds(test.Time)}
+				switch strings.ToLower(test.Result) {
+				case "fail":
+					testCase.Status = bean.TestCaseFailed
+					if test.Failure != nil {
+						setFailure(testCase, test.Failure.ExceptionType, test.Failure.Message, test.Failure.StackTrace)
+					}
+				case "skip", "notrun":
+					testCase.Status = bean.TestCaseSkipped
+				}
+				testSuite.Cases = append(testSuite.Cases, testCase)
+			}
+		}
+		if len(testSuite.Cases) > 0 {
+			updateSuiteCounts(testSuite, parseSeconds(assembly.Time))
+			suites = append(suites, testSuite)
+		}
+	}
+	return suites
+}
+
+// updateSuiteCounts counts the cases of the suite by their status, the duration is the sum of the cases when the
+// report has none for the suite
+func updateSuiteCounts(suite *bean.TestSuite, durationMs int64) {
+	var casesDurationMs int64
+	for _, testCase := range suite.Cases {
+		suite.Tests++
+		casesDurationMs += testCase.DurationMs
+		switch testCase.Status {
+		case bean.TestCasePassed:
+			suite.Passed++
+		case bean.TestCaseFailed:
+			suite.Failures++
+		case bean.TestCaseErrored:
+			suite.Errors++
+		case bean.TestCaseSkipped:
+			suite.Skipped++
+		}
+	}
+	suite.DurationMs = durationMs
+	if durationMs == 0 {
+		suite.DurationMs = casesDurationMs
+	}
+}
+
+func setFailure(testCase *bean.TestCase, failureType, message, details string) {
+	message = strings.TrimSpace(message)
+	details = strings.TrimSpace(details)
+	if len(message) == 0 && len(details) > 0 {
+		message = strings.SplitN(details, "\n", 2)[0]
+	}
+	testCase.FailureType = failureType
+	testCase.FailureMessage = truncate(message, maxFailureMessageLength)
+	testCase.FailureDetails = truncate(details, maxFailureDetailsLength)
+}
+
+func truncate(value string, maxLength int) string {
+	if len(value) > maxLength {
+		return value[:maxLength]
+	}
+	return value
+}
+
+// parseSeconds converts the durations of the reports in seconds, which may have thousands separators, to milliseconds
+func parseSeconds(value string) int64 {
+	seconds, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(value), ",", ""), 64)
+	if err != nil {
+		return 0
+	}
+	return int64(seconds * 1000)
+}
@@ -0,0 +1,137 @@
+package testReport
+
+import (
+	"archive/zip"
+	"bytes"
+	"github.com/devtron-labs/devtron/pkg/pipeline/testReport/bean"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestParseJUnitReport(t *testing.T) {
+	report := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="api" time="1,234.5">
+    <testcase classname="api.UserTest" name="create" time="0.5"/>
+    <testcase classname="api.UserTest" name="delete" time="0.25">
+      <failure message="expected 204" type="AssertionError">stack</failure>
+    </testcase>
+    <testcase classname="api.UserTest" name="update" time="0">
+      <skipped/>
+    </testcase>
+    <testsuite name="db">
+      <testcase classname="api.db.PoolTest" name="connect" time="2">
+        <error type="IOException">connection refused
+	at Pool.connect</error>
+      </testcase>
+    </testsuite>
+  </testsuite>
+</testsuites>`
+	suites, err := ParseTestReport("reports/TEST-api.xml", []byte(report))
+	assert.NoError(t, err)
+	assert.Len(t, suites, 2)
+
+	assert.Equal(t, "api", suites[0].Name)
+	assert.Equal(t, 3, suites[0].Tests)
+	assert.Equal(t, 1, suites[0].Passed)
+	assert.Equal(t, 1, suites[0].Failures)
+	assert.Equal(t, 1, suites[0].Skipped)
+	assert.Equal(t, int64(1234500), suites[0].DurationMs)
+	assert.Equal(t, bean.TestCaseFailed, suites[0].Cases[1].Status)
+	assert.Equal(t, "expected 204", suites[0].Cases[1].FailureMessage)
+	assert.Equal(t, bean.TestCaseSkipped, suites[0].Cases[2].Status)
+
+	// nested suites are named by their path, the duration falls back to the sum of the cases
+	assert.Equal(t, "api/db", suites[1].Name)
+	assert.Equal(t, int64(2000), suites[1].DurationMs)
+	assert.Equal(t, bean.TestCaseErrored, suites[1].Cases[0].Status)
+	assert.Equal(t, "connection refused", suites[1].Cases[0].FailureMessage)
+
+	// a single suite without a name is named by its file
+	suites, err = ParseTestReport("TEST-single.xml", []byte(`<testsuite><testcase name="ok"/></testsuite>`))
+	assert.NoError(t, err)
+	assert.Len(t, suites, 1)
+	assert.Equal(t, "TEST-single", suites[0].Name)
+}
+
+func TestParseTestNgAndXUnitReports(t *testing.T) {
+	testNgReport := `<testng-results>
+  <suite name="regression">
+    <test name="checkout" duration-ms="900">
+      <class name="shop.CheckoutTest">
+        <test-method name="setUp" status="PASS" is-config="true" duration-ms="10"/>
+        <test-method name="pay" status="PASS" duration-ms="400"/>
+        <test-method name="refund" status="FAIL" duration-ms="500">
+          <exception class="java.lang.AssertionError">
+            <message>refund not issued</message>
+            <full-stacktrace>java.lang.AssertionError: refund not issued</full-stacktrace>
+          </exception>
+        </test-method>
+      </class>
+    </test>
+  </suite>
+</testng-results>`
+	suites, err := ParseTestReport("testng-results.xml", []byte(testNgReport))
+	assert.NoError(t, err)
+	assert.Len(t, suites, 1)
+	assert.Equal(t, "regression/checkout", suites[0].Name)
+	assert.Equal(t, 2, suites[0].Tests)
+	assert.Equal(t, 1, suites[0].Failures)
+	assert.Equal(t, "java.lang.AssertionError", suites[0].Cases[1].FailureType)
+	assert.Equal(t, "refund not issued", suites[0].Cases[1].FailureMessage)
+
+	xunitReport := `<assemblies>
+  <assembly name="C:\build\Shop.Tests.dll" time="1.5">
+    <collection>
+      <test name="Shop.Tests.CartTest.Add" type="Shop.Tests.CartTest" method="Add" time="0.5" result="Pass"/>
+      <test name="Shop.Tests.CartTest.Remove" type="Shop.Tests.CartTest" method="Remove" time="1" result="Fail">
+        <failure exception-type="Xunit.Sdk.EqualException"><message>Assert.Equal() Failure</message></failure>
+      </test>
+      <test name="Shop.Tests.CartTest.Clear" type="Shop.Tests.CartTest" method="Clear" time="0" result="Skip"/>
+    </collection>
+  </assembly>
+</assemblies>`
+	suites, err = ParseTestReport("xunit.xml", []byte(xunitReport))
+	assert.NoError(t, err)
+	assert.Len(t, suites, 1)
+	assert.Equal(t, "Shop.Tests.dll", suites[0].Name)
+	assert.Equal(t, 1, suites[0].Passed)
+	assert.Equal(t, 1, suites[0].Failures)
+	assert.Equal(t, 1, suites[0].Skipped)
+	assert.Equal(t, int64(1500), suites[0].DurationMs)
+
+	// other xml documents of the artifacts are not test reports
+	suites, err = ParseTestReport("pom.xml", []byte(`<project><modelVersion>4.0.0</modelVersion></project>`))
+	assert.NoError(t, err)
+	assert.Empty(t, suites)
+	_, err = ParseTestReport("broken.xml", []byte(`<testsuite><testcase name="a" time="1">`))
+	assert.Error(t, err)
+}
+
+func TestReadZipFile(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+	for name, content := range map[string]string{"report.xml": "<testsuite/>", "large.xml": "<testsuite>" + strings.Repeat(" ", 64) + "</testsuite>"} {
+		fileWriter, err := writer.Create(name)
+		assert.NoError(t, err)
+		_, err = fileWriter.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+	reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	assert.NoError(t, err)
+	for _, file := range reader.File {
+		content, err := readZipFile(file, 32)
+		if file.Name == "large.xml" {
+			assert.Equal(t, errReportFileTooLarge, err)
+			// the size in the header is not trusted
+			file.UncompressedSize64 = 0
+			_, err = readZipFile(file, 32)
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, "<testsuite/>", string(content))
+	}
+}
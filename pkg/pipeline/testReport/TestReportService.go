@@ -0,0 +1,334 @@
+package testReport
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/pipeline/testReport/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/testReport/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"sort"
+	"time"
+)
+
+const (
+	DefaultAnalysisRuns  = 20
+	MaxAnalysisRuns      = 100
+	DefaultSlowTestLimit = 10
+)
+
+type TestReportService interface {
+	// IngestReports parses the report files of a workflow and replaces its saved results, files which are not test
+	// reports are ignored
+	IngestReports(workflowType string, workflowId int, pipelineId int, files map[string][]byte) (*bean.TestRunSummary, error)
+	GetRunReport(workflowType string, workflowId int) (*bean.TestRunReport, error)
+	GetFlakyTests(workflowType string, pipelineId int, runs int) ([]*bean.FlakyTest, error)
+	GetSlowestTests(workflowType string, pipelineId int, runs int, limit int) ([]*bean.SlowTest, error)
+	// GetCiPipelineConfig returns nil when the pass rate condition was never configured for the pipeline
+	GetCiPipelineConfig(ciPipelineId int) (*bean.TestReportConfig, error)
+	SaveCiPipelineConfig(config *bean.TestReportConfig, userId int32) (*bean.TestReportConfig, error)
+}
+
+type TestReportServiceImpl struct {
+	logger               *zap.SugaredLogger
+	testReportRepository repository.TestReportRepository
+}
+
+func NewTestReportServiceImpl(logger *zap.SugaredLogger, testReportRepository repository.TestReportRepository) *TestReportServiceImpl {
+	return &TestReportServiceImpl{
+		logger:               logger,
+		testReportRepository: testReportRepository,
+	}
+}
+
+func (impl *TestReportServiceImpl) IngestReports(workflowType string, workflowId int, pipelineId int, files map[string][]byte) (*bean.TestRunSummary, error) {
+	// the files are parsed in the order of their names to keep the suites in a stable order
+	fileNames := make([]string, 0, len(files))
+	for fileName := range files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	var suites []*bean.TestSuite
+	for _, fileName := range fileNames {
+		fileSuites, err := ParseTestReport(fileName, files[fileName])
+		if err != nil {
+			// a broken report should not drop the results of the others
+			impl.logger.Warnw("skipping invalid test report", "err", err, "workflowType", workflowType, "workflowId", workflowId)
+			continue
+		}
+		suites = append(suites, fileSuites...)
+	}
+	if len(suites) == 0 {
+		return nil, nil
+	}
+	now := time.Now()
+	models := make([]*repository.TestReportSuite, 0, len(suites))
+	for _, suite := range suites {
+		model := &repository.TestReportSuite{
+			WorkflowType: workflowType,
+			WorkflowId:   workflowId,
+			PipelineId:   pipelineId,
+			Name:         suite.Name,
+			Tests:        suite.Tests,
+			Passed:       suite.Passed,
+			Failures:     suite.Failures,
+			Errors:       suite.Errors,
+			Skipped:      suite.Skipped,
+			DurationMs:   suite.DurationMs,
+			CreatedOn:    now,
+		}
+		for _, testCase := range suite.Cases {
+			model.Cases = append(model.Cases, &repository.TestReportCase{
+				WorkflowType:   workflowType,
+				WorkflowId:     workflowId,
+				PipelineId:     pipelineId,
+				ClassName:      testCase.ClassName,
+				Name:           testCase.Name,
+				Status:         testCase.Status,
+				DurationMs:     testCase.DurationMs,
+				FailureType:    testCase.FailureType,
+				FailureMessage: testCase.FailureMessage,
+				FailureDetails: testCase.FailureDetails,
+				CreatedOn:      now,
+			})
+		}
+		models = append(models, model)
+	}
+	err := impl.testReportRepository.SaveWorkflowSuites(workflowType, workflowId, models)
+	if err != nil {
+		return nil, err
+	}
+	return getRunSummary(suites), nil
+}
+
+func (impl *TestReportServiceImpl) GetRunReport(workflowType string, workflowId int) (*bean.TestRunReport, error) {
+	suites, err := impl.testReportRepository.FindSuitesByWorkflow(workflowType, workflowId)
+	if err != nil {
+		return nil, err
+	}
+	cases, err := impl.testReportRepository.FindCasesByWorkflow(workflowType, workflowId)
+	if err != nil {
+		return nil, err
+	}
+	casesBySuite := make(map[int][]*bean.TestCase)
+	for _, testCase := range cases {
+		casesBySuite[testCase.SuiteId] = append(casesBySuite[testCase.SuiteId], &bean.TestCase{
+			ClassName:      testCase.ClassName,
+			Name:           testCase.Name,
+			Status:         testCase.Status,
+			DurationMs:     testCase.DurationMs,
+			FailureType:    testCase.FailureType,
+			FailureMessage: testCase.FailureMessage,
+			FailureDetails: testCase.FailureDetails,
+		})
+	}
+	report := &bean.TestRunReport{WorkflowType: workflowType, WorkflowId: workflowId, Suites: []*bean.TestSuite{}}
+	for _, suite := range suites {
+		report.PipelineId = suite.PipelineId
+		report.Suites = append(report.Suites, &bean.TestSuite{
+			Name:       suite.Name,
+			Tests:      suite.Tests,
+			Passed:     suite.Passed,
+			Failures:   suite.Failures,
+			Errors:     suite.Errors,
+			Skipped:    suite.Skipped,
+			DurationMs: suite.DurationMs,
+			Cases:      casesBySuite[suite.Id],
+		})
+	}
+	report.Summary = getRunSummary(report.Suites)
+	return report, nil
+}
+
+func (impl *TestReportServiceImpl) GetFlakyTests(workflowType string, pipelineId int, runs int) ([]*bean.FlakyTest, error) {
+	cases, err := impl.testReportRepository.FindCasesOfRecentRuns(workflowType, pipelineId, getAnalysisRuns(runs))
+	if err != nil {
+		return nil, err
+	}
+	return getFlakyTests(cases), nil
+}
+
+func (impl *TestReportServiceImpl) GetSlowestTests(workflowType string, pipelineId int, runs int, limit int) ([]*bean.SlowTest, error) {
+	cases, err := impl.testReportRepository.FindCasesOfRecentRuns(workflowType, pipelineId, getAnalysisRuns(runs))
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultSlowTestLimit
+	}
+	return getSlowestTests(cases, limit), nil
+}
+
+func (impl *TestReportServiceImpl) GetCiPipelineConfig(ciPipelineId int) (*bean.TestReportConfig, error) {
+	config, err := impl.testReportRepository.FindConfigByCiPipelineId(ciPipelineId)
+	if err == pg.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in getting test report config", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	return &bean.TestReportConfig{CiPipelineId: config.CiPipelineId, MinPassRate: config.MinPassRate, Enabled: config.Enabled}, nil
+}
+
+func (impl *TestReportServiceImpl) SaveCiPipelineConfig(config *bean.TestReportConfig, userId int32) (*bean.TestReportConfig, error) {
+	model, err := impl.testReportRepository.FindConfigByCiPipelineId(config.CiPipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting test report config", "err", err, "ciPipelineId", config.CiPipelineId)
+		return nil, err
+	}
+	now := time.Now()
+	if err == pg.ErrNoRows {
+		model = &repository.CiPipelineTestReportConfig{
+			CiPipelineId: config.CiPipelineId,
+			AuditLog:     sql.AuditLog{CreatedOn: now, CreatedBy: userId},
+		}
+	}
+	model.MinPassRate = config.MinPassRate
+	model.Enabled = config.Enabled
+	model.UpdatedOn = now
+	model.UpdatedBy = userId
+	if model.Id == 0 {
+		err = impl.testReportRepository.SaveConfig(model)
+	} else {
+		err = impl.testReportRepository.UpdateConfig(model)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving test report config", "err", err, "ciPipelineId", config.CiPipelineId)
+		return nil, err
+	}
+	return config, nil
+}
+
+// EvaluatePassRate returns the reason to fail the build for the pass rate condition of the pipeline, empty when the
+// condition is disabled, holds or no test was run
+func EvaluatePassRate(config *bean.TestReportConfig, summary *bean.TestRunSummary) string {
+	if config == nil || !config.Enabled || summary == nil || summary.Total-summary.Skipped == 0 {
+		return ""
+	}
+	if summary.PassRate >= config.MinPassRate {
+		return ""
+	}
+	return fmt.Sprintf("%s %.2f%% is below the minimum %.2f%%", bean.TestPassRateFailurePrefix, summary.PassRate, config.MinPassRate)
+}
+
+func getAnalysisRuns(runs int) int {
+	if runs <= 0 {
+		return DefaultAnalysisRuns
+	} else if runs > MaxAnalysisRuns {
+		return MaxAnalysisRuns
+	}
+	return runs
+}
+
+func getRunSummary(suites []*bean.TestSuite) *bean.TestRunSummary {
+	summary := &bean.TestRunSummary{}
+	for _, suite := range suites {
+		summary.Total += suite.Tests
+		summary.Passed += suite.Passed
+		summary.Failed += suite.Failures
+		summary.Errored += suite.Errors
+		summary.Skipped += suite.Skipped
+		summary.DurationMs += suite.DurationMs
+	}
+	if executed := summary.Total - summary.Skipped; executed > 0 {
+		summary.PassRate = float64(summary.Passed) * 100 / float64(executed)
+	}
+	return summary
+}
+
+type testKey struct {
+	className string
+	name      string
+}
+
+// getFlakyTests finds the tests which both passed and failed over the runs, the cases are expected in the order of
+// their workflows. Skipped results are ignored and the tests flipping most often come first.
+func getFlakyTests(cases []*repository.TestReportCase) []*bean.FlakyTest {
+	type testHistory struct {
+		flakyTest  *bean.FlakyTest
+		passes     int
+		lastFailed bool
+		lastRun    int
+	}
+	histories := make(map[testKey]*testHistory)
+	var keys []testKey
+	for _, testCase := range cases {
+		if testCase.Status == bean.TestCaseSkipped {
+			continue
+		}
+		key := testKey{className: testCase.ClassName, name: testCase.Name}
+		history, ok := histories[key]
+		if !ok {
+			history = &testHistory{flakyTest: &bean.FlakyTest{ClassName: testCase.ClassName, Name: testCase.Name}}
+			histories[key] = history
+			keys = append(keys, key)
+		}
+		failed := testCase.Status == bean.TestCaseFailed || testCase.Status == bean.TestCaseErrored
+		flakyTest := history.flakyTest
+		if flakyTest.Runs > 0 && history.lastRun != testCase.WorkflowId && failed != history.lastFailed {
+			flakyTest.Flips++
+		}
+		flakyTest.Runs++
+		if failed {
+			flakyTest.Failures++
+			flakyTest.LastFailedOn = testCase.CreatedOn
+		} else {
+			history.passes++
+		}
+		history.lastFailed = failed
+		history.lastRun = testCase.WorkflowId
+	}
+	flakyTests := make([]*bean.FlakyTest, 0)
+	for _, key := range keys {
+		history := histories[key]
+		if history.passes == 0 || history.flakyTest.Failures == 0 {
+			continue
+		}
+		history.flakyTest.FailureRate = float64(history.flakyTest.Failures) * 100 / float64(history.flakyTest.Runs)
+		flakyTests = append(flakyTests, history.flakyTest)
+	}
+	sort.SliceStable(flakyTests, func(i, j int) bool {
+		if flakyTests[i].Flips != flakyTests[j].Flips {
+			return flakyTests[i].Flips > flakyTests[j].Flips
+		}
+		return flakyTests[i].Failures > flakyTests[j].Failures
+	})
+	return flakyTests
+}
+
+// getSlowestTests returns the tests with the highest average duration over the runs, skipped results are ignored
+func getSlowestTests(cases []*repository.TestReportCase, limit int) []*bean.SlowTest {
+	totals := make(map[testKey]int64)
+	slowTestByKey := make(map[testKey]*bean.SlowTest)
+	var slowTests []*bean.SlowTest
+	for _, testCase := range cases {
+		if testCase.Status == bean.TestCaseSkipped {
+			continue
+		}
+		key := testKey{className: testCase.ClassName, name: testCase.Name}
+		slowTest, ok := slowTestByKey[key]
+		if !ok {
+			slowTest = &bean.SlowTest{ClassName: testCase.ClassName, Name: testCase.Name}
+			slowTestByKey[key] = slowTest
+			slowTests = append(slowTests, slowTest)
+		}
+		slowTest.Runs++
+		totals[key] += testCase.DurationMs
+		if testCase.DurationMs > slowTest.MaxDurationMs {
+			slowTest.MaxDurationMs = testCase.DurationMs
+		}
+	}
+	for key, slowTest := range slowTestByKey {
+		slowTest.AvgDurationMs = totals[key] / int64(slowTest.Runs)
+	}
+	sort.SliceStable(slowTests, func(i, j int) bool {
+		return slowTests[i].AvgDurationMs > slowTests[j].AvgDurationMs
+	})
+	if len(slowTests) > limit {
+		slowTests = slowTests[:limit]
+	}
+	if slowTests == nil {
+		slowTests = make([]*bean.SlowTest, 0)
+	}
+	return slowTests
+}
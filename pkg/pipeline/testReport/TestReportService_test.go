@@ -0,0 +1,71 @@
+package testReport
+
+import (
+	"github.com/devtron-labs/devtron/pkg/pipeline/testReport/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/testReport/repository"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func testCaseOfRun(workflowId int, name string, status string, durationMs int64) *repository.TestReportCase {
+	return &repository.TestReportCase{WorkflowId: workflowId, ClassName: "shop.CartTest", Name: name, Status: status, DurationMs: durationMs}
+}
+
+func TestGetFlakyTests(t *testing.T) {
+	cases := []*repository.TestReportCase{
+		testCaseOfRun(1, "add", bean.TestCasePassed, 100),
+		testCaseOfRun(1, "remove", bean.TestCaseFailed, 100),
+		testCaseOfRun(1, "clear", bean.TestCaseFailed, 100),
+		testCaseOfRun(2, "add", bean.TestCaseFailed, 100),
+		testCaseOfRun(2, "remove", bean.TestCaseFailed, 100),
+		testCaseOfRun(2, "clear", bean.TestCaseSkipped, 100),
+		testCaseOfRun(3, "add", bean.TestCasePassed, 100),
+		testCaseOfRun(3, "remove", bean.TestCaseFailed, 100),
+		testCaseOfRun(3, "clear", bean.TestCasePassed, 100),
+	}
+	flakyTests := getFlakyTests(cases)
+	assert.Len(t, flakyTests, 2)
+	assert.Equal(t, "add", flakyTests[0].Name)
+	assert.Equal(t, 2, flakyTests[0].Flips)
+	assert.Equal(t, 3, flakyTests[0].Runs)
+	assert.Equal(t, 1, flakyTests[0].Failures)
+	// the skipped run of clear is not counted
+	assert.Equal(t, "clear", flakyTests[1].Name)
+	assert.Equal(t, 1, flakyTests[1].Flips)
+	assert.Equal(t, 2, flakyTests[1].Runs)
+	assert.Equal(t, float64(50), flakyTests[1].FailureRate)
+}
+
+func TestGetSlowestTests(t *testing.T) {
+	cases := []*repository.TestReportCase{
+		testCaseOfRun(1, "add", bean.TestCasePassed, 100),
+		testCaseOfRun(1, "remove", bean.TestCasePassed, 300),
+		testCaseOfRun(1, "clear", bean.TestCaseSkipped, 0),
+		testCaseOfRun(2, "add", bean.TestCasePassed, 900),
+		testCaseOfRun(2, "remove", bean.TestCasePassed, 300),
+	}
+	slowTests := getSlowestTests(cases, 1)
+	assert.Len(t, slowTests, 1)
+	assert.Equal(t, "add", slowTests[0].Name)
+	assert.Equal(t, int64(500), slowTests[0].AvgDurationMs)
+	assert.Equal(t, int64(900), slowTests[0].MaxDurationMs)
+	assert.Equal(t, 2, slowTests[0].Runs)
+	assert.NotNil(t, getSlowestTests(nil, 10))
+}
+
+func TestEvaluatePassRate(t *testing.T) {
+	summary := getRunSummary([]*bean.TestSuite{{Tests: 10, Passed: 7, Failures: 1, Skipped: 2}})
+	assert.Equal(t, 87.5, summary.PassRate)
+
+	config := &bean.TestReportConfig{MinPassRate: 90, Enabled: true}
+	assert.Equal(t, "test pass rate 87.50% is below the minimum 90.00%", EvaluatePassRate(config, summary))
+	config.MinPassRate = 87.5
+	assert.Empty(t, EvaluatePassRate(config, summary))
+	config.MinPassRate = 100
+	config.Enabled = false
+	assert.Empty(t, EvaluatePassRate(config, summary))
+	// builds without tests run are not failed
+	config.Enabled = true
+	assert.Empty(t, EvaluatePassRate(config, getRunSummary([]*bean.TestSuite{{Tests: 2, Skipped: 2}})))
+	assert.Empty(t, EvaluatePassRate(config, nil))
+}
@@ -0,0 +1,93 @@
+package bean
+
+import "time"
+
+const (
+	WorkflowTypeCi     = "CI"
+	WorkflowTypePreCd  = "PRE"
+	WorkflowTypePostCd = "POST"
+)
+
+const (
+	TestCasePassed  = "PASSED"
+	TestCaseFailed  = "FAILED"
+	TestCaseErrored = "ERRORED"
+	TestCaseSkipped = "SKIPPED"
+)
+
+// TestPassRateFailurePrefix starts the message of the ci workflows failed for a pass rate below the minimum
+const TestPassRateFailurePrefix = "test pass rate"
+
+// PassRateEvaluationTimeout bounds the ingestion of the test reports the ci success webhook waits for to evaluate the
+// pass rate, the build is not failed when the reports are not ingested by then
+const PassRateEvaluationTimeout = 30 * time.Second
+
+type TestSuite struct {
+	Name       string      `json:"name"`
+	Tests      int         `json:"tests"`
+	Passed     int         `json:"passed"`
+	Failures   int         `json:"failures"`
+	Errors     int         `json:"errors"`
+	Skipped    int         `json:"skipped"`
+	DurationMs int64       `json:"durationMs"`
+	Cases      []*TestCase `json:"cases,omitempty"`
+}
+
+type TestCase struct {
+	ClassName      string `json:"className"`
+	Name           string `json:"name"`
+	Status         string `json:"status"`
+	DurationMs     int64  `json:"durationMs"`
+	FailureType    string `json:"failureType,omitempty"`
+	FailureMessage string `json:"failureMessage,omitempty"`
+	FailureDetails string `json:"failureDetails,omitempty"`
+}
+
+// TestRunSummary is the result of all the test reports of a workflow, PassRate is the percentage of the tests which
+// were run and passed
+type TestRunSummary struct {
+	Total      int     `json:"total"`
+	Passed     int     `json:"passed"`
+	Failed     int     `json:"failed"`
+	Errored    int     `json:"errored"`
+	Skipped    int     `json:"skipped"`
+	DurationMs int64   `json:"durationMs"`
+	PassRate   float64 `json:"passRate"`
+}
+
+type TestRunReport struct {
+	WorkflowType string          `json:"workflowType"`
+	WorkflowId   int             `json:"workflowId"`
+	PipelineId   int             `json:"pipelineId"`
+	Summary      *TestRunSummary `json:"summary"`
+	Suites       []*TestSuite    `json:"suites"`
+}
+
+// FlakyTest is a test which both passed and failed over the recent runs of a pipeline, Flips is the number of times
+// its result changed between consecutive runs
+type FlakyTest struct {
+	ClassName    string    `json:"className"`
+	Name         string    `json:"name"`
+	Runs         int       `json:"runs"`
+	Failures     int       `json:"failures"`
+	Flips        int       `json:"flips"`
+	FailureRate  float64   `json:"failureRate"`
+	LastFailedOn time.Time `json:"lastFailedOn"`
+}
+
+type SlowTest struct {
+	ClassName     string `json:"className"`
+	Name          string `json:"name"`
+	Runs          int    `json:"runs"`
+	AvgDurationMs int64  `json:"avgDurationMs"`
+	MaxDurationMs int64  `json:"maxDurationMs"`
+}
+
+// TestReportConfig is the post ci condition of a ci pipeline failing the build when the pass rate of its tests is
+// below MinPassRate. The test reports of a ci pipeline are only ingested when it has a config, the condition is
+// evaluated synchronously in the ci success webhook for up to PassRateEvaluationTimeout
+type TestReportConfig struct {
+	CiPipelineId int     `json:"ciPipelineId"`
+	MinPassRate  float64 `json:"minPassRate" validate:"min=0,max=100"`
+	Enabled      bool    `json:"enabled"`
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// TestReportSuite is a test suite of the reports of a ci workflow or a pre/post cd workflow runner, WorkflowId and
+// PipelineId are of the ci or the cd side according to WorkflowType
+type TestReportSuite struct {
+	tableName    struct{}          `sql:"test_report_suite" pg:",discard_unknown_columns"`
+	Id           int               `sql:"id,pk"`
+	WorkflowType string            `sql:"workflow_type,notnull"`
+	WorkflowId   int               `sql:"workflow_id,notnull"`
+	PipelineId   int               `sql:"pipeline_id,notnull"`
+	Name         string            `sql:"name,notnull"`
+	Tests        int               `sql:"tests,notnull"`
+	Passed       int               `sql:"passed,notnull"`
+	Failures     int               `sql:"failures,notnull"`
+	Errors       int               `sql:"errors,notnull"`
+	Skipped      int               `sql:"skipped,notnull"`
+	DurationMs   int64             `sql:"duration_ms,notnull"`
+	CreatedOn    time.Time         `sql:"created_on,type:timestamptz"`
+	Cases        []*TestReportCase `sql:"-"`
+}
+
+type TestReportCase struct {
+	tableName      struct{}  `sql:"test_report_case" pg:",discard_unknown_columns"`
+	Id             int       `sql:"id,pk"`
+	SuiteId        int       `sql:"suite_id,notnull"`
+	WorkflowType   string    `sql:"workflow_type,notnull"`
+	WorkflowId     int       `sql:"workflow_id,notnull"`
+	PipelineId     int       `sql:"pipeline_id,notnull"`
+	ClassName      string    `sql:"class_name"`
+	Name           string    `sql:"name,notnull"`
+	Status         string    `sql:"status,notnull"`
+	DurationMs     int64     `sql:"duration_ms,notnull"`
+	FailureType    string    `sql:"failure_type"`
+	FailureMessage string    `sql:"failure_message"`
+	FailureDetails string    `sql:"failure_details"`
+	CreatedOn      time.Time `sql:"created_on,type:timestamptz"`
+}
+
+type CiPipelineTestReportConfig struct {
+	tableName    struct{} `sql:"ci_pipeline_test_report_config" pg:",discard_unknown_columns"`
+	Id           int      `sql:"id,pk"`
+	CiPipelineId int      `sql:"ci_pipeline_id,notnull"`
+	MinPassRate  float64  `sql:"min_pass_rate,notnull"`
+	Enabled      bool     `sql:"enabled,notnull"`
+	sql.AuditLog
+}
+
+type TestReportRepository interface {
+	// SaveWorkflowSuites replaces the saved suites and cases of the workflow
+	SaveWorkflowSuites(workflowType string, workflowId int, suites []*TestReportSuite) error
+	FindSuitesByWorkflow(workflowType string, workflowId int) ([]*TestReportSuite, error)
+	FindCasesByWorkflow(workflowType string, workflowId int) ([]*TestReportCase, error)
+	// FindCasesOfRecentRuns returns the cases of the latest runs of the pipeline which have test reports
+	FindCasesOfRecentRuns(workflowType string, pipelineId int, runs int) ([]*TestReportCase, error)
+	FindConfigByCiPipelineId(ciPipelineId int) (*CiPipelineTestReportConfig, error)
+	SaveConfig(config *CiPipelineTestReportConfig) error
+	UpdateConfig(config *CiPipelineTestReportConfig) error
+}
+
+type TestReportRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewTestReportRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *TestReportRepositoryImpl {
+	return &TestReportRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *TestReportRepositoryImpl) SaveWorkflowSuites(workflowType string, workflowId int, suites []*TestReportSuite) error {
+	tx, err := impl.dbConnection.Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	// the cases are deleted along with their suites
+	_, err = tx.Model((*TestReportSuite)(nil)).
+		Where("workflow_type = ?", workflowType).
+		Where("workflow_id = ?", workflowId).
+		Delete()
+	if err != nil {
+		impl.logger.Errorw("error in deleting test report suites", "err", err, "workflowType", workflowType, "workflowId", workflowId)
+		return err
+	}
+	for _, suite := range suites {
+		err = tx.Insert(suite)
+		if err != nil {
+			impl.logger.Errorw("error in saving test report suite", "err", err, "workflowType", workflowType, "workflowId", workflowId)
+			return err
+		}
+		if len(suite.Cases) == 0 {
+			continue
+		}
+		for _, testCase := range suite.Cases {
+			testCase.SuiteId = suite.Id
+		}
+		err = tx.Insert(&suite.Cases)
+		if err != nil {
+			impl.logger.Errorw("error in saving test report cases", "err", err, "workflowType", workflowType, "workflowId", workflowId)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (impl *TestReportRepositoryImpl) FindSuitesByWorkflow(workflowType string, workflowId int) ([]*TestReportSuite, error) {
+	var suites []*TestReportSuite
+	err := impl.dbConnection.Model(&suites).
+		Where("workflow_type = ?", workflowType).
+		Where("workflow_id = ?", workflowId).
+		Order("id ASC").Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting test report suites", "err", err, "workflowType", workflowType, "workflowId", workflowId)
+		return nil, err
+	}
+	return suites, nil
+}
+
+func (impl *TestReportRepositoryImpl) FindCasesByWorkflow(workflowType string, workflowId int) ([]*TestReportCase, error) {
+	var cases []*TestReportCase
+	err := impl.dbConnection.Model(&cases).
+		Where("workflow_type = ?", workflowType).
+		Where("workflow_id = ?", workflowId).
+		Order("id ASC").Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting test report cases", "err", err, "workflowType", workflowType, "workflowId", workflowId)
+		return nil, err
+	}
+	return cases, nil
+}
+
+func (impl *TestReportRepositoryImpl) FindCasesOfRecentRuns(workflowType string, pipelineId int, runs int) ([]*TestReportCase, error) {
+	var cases []*TestReportCase
+	query := "SELECT * FROM test_report_case WHERE workflow_type = ? AND pipeline_id = ? AND workflow_id IN " +
+		"(SELECT DISTINCT workflow_id FROM test_report_suite WHERE workflow_type = ? AND pipeline_id = ? ORDER BY workflow_id DESC LIMIT ?) " +
+		"ORDER BY workflow_id ASC, id ASC;"
+	_, err := impl.dbConnection.Query(&cases, query, workflowType, pipelineId, workflowType, pipelineId, runs)
+	if err != nil {
+		impl.logger.Errorw("error in getting test report cases of recent runs", "err", err, "workflowType", workflowType, "pipelineId", pipelineId)
+		return nil, err
+	}
+	return cases, nil
+}
+
+func (impl *TestReportRepositoryImpl) FindConfigByCiPipelineId(ciPipelineId int) (*CiPipelineTestReportConfig, error) {
+	config := &CiPipelineTestReportConfig{}
+	err := impl.dbConnection.Model(config).
+		Where("ci_pipeline_id = ?", ciPipelineId).
+		Select()
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (impl *TestReportRepositoryImpl) SaveConfig(config *CiPipelineTestReportConfig) error {
+	return impl.dbConnection.Insert(config)
+}
+
+func (impl *TestReportRepositoryImpl) UpdateConfig(config *CiPipelineTestReportConfig) error {
+	return impl.dbConnection.Update(config)
+}
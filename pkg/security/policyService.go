@@ -19,12 +19,15 @@ package security
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	repository1 "github.com/devtron-labs/devtron/internal/sql/repository/app"
 	"github.com/devtron-labs/devtron/internal/sql/repository/helper"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -40,9 +43,9 @@ import (
 )
 
 type PolicyService interface {
-	SavePolicy(request bean.CreateVulnerabilityPolicyRequest, userId int32) (*bean.IdVulnerabilityPolicyResult, error)
-	UpdatePolicy(updatePolicyParams bean.UpdatePolicyParams, userId int32) (*bean.IdVulnerabilityPolicyResult, error)
-	DeletePolicy(id int, userId int32) (*bean.IdVulnerabilityPolicyResult, error)
+	SavePolicy(ctx context.Context, request bean.CreateVulnerabilityPolicyRequest, userId int32) (*bean.IdVulnerabilityPolicyResult, error)
+	UpdatePolicy(ctx context.Context, updatePolicyParams bean.UpdatePolicyParams, userId int32) (*bean.IdVulnerabilityPolicyResult, error)
+	DeletePolicy(ctx context.Context, id int, userId int32) (*bean.IdVulnerabilityPolicyResult, error)
 	GetPolicies(policyLevel security.PolicyLevel, clusterId, environmentId, appId int) (*bean.GetVulnerabilityPolicyResult, error)
 	GetBlockedCVEList(cves []*security.CveStore, clusterId, envId, appId int, isAppstore bool) ([]*security.CveStore, error)
 	VerifyImage(verifyImageRequest *VerifyImageRequest) (map[string][]*VerifyImageResponse, error)
@@ -67,6 +70,7 @@ type PolicyServiceImpl struct {
 	scanHistoryRepository         security.ImageScanHistoryRepository
 	cveStoreRepository            security.CveStoreRepository
 	ciTemplateRepository          pipelineConfig.CiTemplateRepository
+	auditLogService               auditLog.AuditLogService
 }
 
 func NewPolicyServiceImpl(environmentService cluster.EnvironmentService,
@@ -81,7 +85,7 @@ func NewPolicyServiceImpl(environmentService cluster.EnvironmentService,
 	imageScanObjectMetaRepository security.ImageScanObjectMetaRepository, client *http.Client,
 	ciArtifactRepository repository.CiArtifactRepository, ciConfig *pipeline.CiCdConfig,
 	scanHistoryRepository security.ImageScanHistoryRepository, cveStoreRepository security.CveStoreRepository,
-	ciTemplateRepository pipelineConfig.CiTemplateRepository) *PolicyServiceImpl {
+	ciTemplateRepository pipelineConfig.CiTemplateRepository, auditLogService auditLog.AuditLogService) *PolicyServiceImpl {
 	return &PolicyServiceImpl{
 		environmentService:            environmentService,
 		logger:                        logger,
@@ -99,6 +103,7 @@ func NewPolicyServiceImpl(environmentService cluster.EnvironmentService,
 		scanHistoryRepository:         scanHistoryRepository,
 		cveStoreRepository:            cveStoreRepository,
 		ciTemplateRepository:          ciTemplateRepository,
+		auditLogService:               auditLogService,
 	}
 }
 
@@ -428,7 +433,7 @@ func (impl *PolicyServiceImpl) parsePolicyAction(action string) (security.Policy
 	return policyAction, nil
 }
 
-func (impl *PolicyServiceImpl) SavePolicy(request bean.CreateVulnerabilityPolicyRequest, userId int32) (*bean.IdVulnerabilityPolicyResult, error) {
+func (impl *PolicyServiceImpl) SavePolicy(ctx context.Context, request bean.CreateVulnerabilityPolicyRequest, userId int32) (*bean.IdVulnerabilityPolicyResult, error) {
 	isGlobal := false
 	if request.ClusterId == 0 && request.EnvId == 0 && request.AppId == 0 {
 		isGlobal = true
@@ -475,6 +480,7 @@ func (impl *PolicyServiceImpl) SavePolicy(request bean.CreateVulnerabilityPolicy
 		impl.logger.Errorw("error in saving policy", "err", err)
 		return nil, fmt.Errorf("error in saving policy")
 	}
+	impl.recordAuditEvent(ctx, auditLog.ActionCreate, policy.Id, userId, nil, newCvePolicyAuditState(policy))
 	return &bean.IdVulnerabilityPolicyResult{Id: policy.Id}, nil
 }
 
@@ -482,19 +488,20 @@ func (impl *PolicyServiceImpl) SavePolicy(request bean.CreateVulnerabilityPolicy
 1. policy id
 2. action
 */
-func (impl *PolicyServiceImpl) UpdatePolicy(updatePolicyParams bean.UpdatePolicyParams, userId int32) (*bean.IdVulnerabilityPolicyResult, error) {
+func (impl *PolicyServiceImpl) UpdatePolicy(ctx context.Context, updatePolicyParams bean.UpdatePolicyParams, userId int32) (*bean.IdVulnerabilityPolicyResult, error) {
 	policyAction, err := impl.parsePolicyAction(updatePolicyParams.Action)
 	if err != nil {
 		return nil, err
 	}
 	if policyAction == security.Inherit {
-		return impl.DeletePolicy(updatePolicyParams.Id, userId)
+		return impl.DeletePolicy(ctx, updatePolicyParams.Id, userId)
 	} else {
 		policy, err := impl.cvePolicyRepository.GetById(updatePolicyParams.Id)
 		if err != nil {
 			impl.logger.Errorw("error in fetching policy ", "id", updatePolicyParams.Id)
 			return nil, err
 		}
+		before := newCvePolicyAuditState(policy)
 		policy.Action = policyAction
 		policy.UpdatedOn = time.Now()
 		policy.UpdatedBy = userId
@@ -502,6 +509,7 @@ func (impl *PolicyServiceImpl) UpdatePolicy(updatePolicyParams bean.UpdatePolicy
 		if err != nil {
 			return nil, err
 		} else {
+			impl.recordAuditEvent(ctx, auditLog.ActionUpdate, policy.Id, userId, before, newCvePolicyAuditState(policy))
 			return &bean.IdVulnerabilityPolicyResult{Id: policy.Id}, nil
 		}
 	}
@@ -511,7 +519,7 @@ func (impl *PolicyServiceImpl) UpdatePolicy(updatePolicyParams bean.UpdatePolicy
 input : policyId
 output: id
 */
-func (impl *PolicyServiceImpl) DeletePolicy(id int, userId int32) (*bean.IdVulnerabilityPolicyResult, error) {
+func (impl *PolicyServiceImpl) DeletePolicy(ctx context.Context, id int, userId int32) (*bean.IdVulnerabilityPolicyResult, error) {
 	policy, err := impl.cvePolicyRepository.GetById(id)
 	if err != nil {
 		impl.logger.Errorw("error in fetching policy ", "id", id)
@@ -527,6 +535,7 @@ func (impl *PolicyServiceImpl) DeletePolicy(id int, userId int32) (*bean.IdVulne
 	if err != nil {
 		return nil, err
 	} else {
+		impl.recordAuditEvent(ctx, auditLog.ActionDelete, policy.Id, userId, newCvePolicyAuditState(policy), nil)
 		return &bean.IdVulnerabilityPolicyResult{Id: policy.Id}, nil
 	}
 }
@@ -718,3 +727,43 @@ func (impl *PolicyServiceImpl) GetCvePolicy(id int, userId int32) (*security.Cve
 	}
 	return policy, nil
 }
+
+// cvePolicyAuditState is the audited state of a cve policy
+type cvePolicyAuditState struct {
+	Global        bool   `json:"global"`
+	ClusterId     int    `json:"clusterId,omitempty"`
+	EnvironmentId int    `json:"environmentId,omitempty"`
+	AppId         int    `json:"appId,omitempty"`
+	CveId         string `json:"cveId,omitempty"`
+	Action        string `json:"action"`
+	Severity      string `json:"severity,omitempty"`
+}
+
+func newCvePolicyAuditState(policy *security.CvePolicy) *cvePolicyAuditState {
+	state := &cvePolicyAuditState{
+		Global:        policy.Global,
+		ClusterId:     policy.ClusterId,
+		EnvironmentId: policy.EnvironmentId,
+		AppId:         policy.AppId,
+		CveId:         policy.CVEStoreId,
+		Action:        policy.Action.String(),
+	}
+	if policy.Severity != nil {
+		state.Severity = policy.Severity.String()
+	}
+	return state
+}
+
+func (impl *PolicyServiceImpl) recordAuditEvent(ctx context.Context, action string, policyId int, userId int32, before *cvePolicyAuditState, after *cvePolicyAuditState) {
+	if impl.auditLogService == nil {
+		return
+	}
+	impl.auditLogService.RecordEvent(ctx, &auditLog.AuditEventRequest{
+		Action:       action,
+		ResourceType: auditLog.ResourceTypeCvePolicy,
+		ResourceId:   strconv.Itoa(policyId),
+		UserId:       userId,
+		Before:       before,
+		After:        after,
+	})
+}
@@ -0,0 +1,279 @@
+package terminal
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type TerminalCommandPolicyDto struct {
+	Id              int      `json:"id"`
+	Name            string   `json:"name" validate:"required"`
+	ClusterId       int      `json:"clusterId" validate:"required"`
+	Namespace       string   `json:"namespace"`
+	AllowedCommands []string `json:"allowedCommands"`
+	DeniedCommands  []string `json:"deniedCommands"`
+	UserId          int32    `json:"-"`
+}
+
+type TerminalCommandViolationDto struct {
+	Id            int       `json:"id"`
+	PolicyId      int       `json:"policyId"`
+	SessionId     string    `json:"sessionId"`
+	ClusterId     int       `json:"clusterId"`
+	Namespace     string    `json:"namespace"`
+	PodName       string    `json:"podName"`
+	ContainerName string    `json:"containerName"`
+	UserId        int32     `json:"userId"`
+	Command       string    `json:"command"`
+	Reason        string    `json:"reason"`
+	BlockedOn     time.Time `json:"blockedOn"`
+}
+
+type TerminalCommandPolicyService interface {
+	CreatePolicy(request *TerminalCommandPolicyDto) (*TerminalCommandPolicyDto, error)
+	UpdatePolicy(request *TerminalCommandPolicyDto) (*TerminalCommandPolicyDto, error)
+	DeletePolicy(id int, userId int32) error
+	GetPolicy(id int) (*TerminalCommandPolicyDto, error)
+	GetPolicies() ([]*TerminalCommandPolicyDto, error)
+	GetViolations(filter *repository.TerminalCommandViolationFilter) ([]*TerminalCommandViolationDto, error)
+	// NewCommandGuard returns the guard of the policy for the namespace of the session, or of the cluster when the
+	// namespace has no policy, nil when neither has one
+	NewCommandGuard(req *TerminalSessionRequest, clusterId int) (*TerminalCommandGuard, error)
+}
+
+type TerminalCommandPolicyServiceImpl struct {
+	logger                          *zap.SugaredLogger
+	terminalCommandPolicyRepository repository.TerminalCommandPolicyRepository
+}
+
+func NewTerminalCommandPolicyServiceImpl(logger *zap.SugaredLogger,
+	terminalCommandPolicyRepository repository.TerminalCommandPolicyRepository) *TerminalCommandPolicyServiceImpl {
+	return &TerminalCommandPolicyServiceImpl{
+		logger:                          logger,
+		terminalCommandPolicyRepository: terminalCommandPolicyRepository,
+	}
+}
+
+func (impl *TerminalCommandPolicyServiceImpl) CreatePolicy(request *TerminalCommandPolicyDto) (*TerminalCommandPolicyDto, error) {
+	err := impl.validatePolicy(request)
+	if err != nil {
+		return nil, err
+	}
+	policy := &repository.TerminalCommandPolicy{
+		Active:   true,
+		AuditLog: sql.AuditLog{CreatedBy: request.UserId, CreatedOn: time.Now(), UpdatedOn: time.Now(), UpdatedBy: request.UserId},
+	}
+	err = adaptTerminalCommandPolicyDto(request, policy)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.terminalCommandPolicyRepository.Save(policy)
+	if err != nil {
+		return nil, err
+	}
+	return adaptTerminalCommandPolicy(policy)
+}
+
+func (impl *TerminalCommandPolicyServiceImpl) UpdatePolicy(request *TerminalCommandPolicyDto) (*TerminalCommandPolicyDto, error) {
+	policy, err := impl.terminalCommandPolicyRepository.FindById(request.Id)
+	if err != nil {
+		impl.logger.Errorw("error in getting terminal command policy", "err", err, "id", request.Id)
+		return nil, err
+	}
+	err = impl.validatePolicy(request)
+	if err != nil {
+		return nil, err
+	}
+	err = adaptTerminalCommandPolicyDto(request, policy)
+	if err != nil {
+		return nil, err
+	}
+	policy.UpdatedOn = time.Now()
+	policy.UpdatedBy = request.UserId
+	err = impl.terminalCommandPolicyRepository.Update(policy)
+	if err != nil {
+		return nil, err
+	}
+	return adaptTerminalCommandPolicy(policy)
+}
+
+func (impl *TerminalCommandPolicyServiceImpl) DeletePolicy(id int, userId int32) error {
+	policy, err := impl.terminalCommandPolicyRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in getting terminal command policy", "err", err, "id", id)
+		return err
+	}
+	policy.Active = false
+	policy.UpdatedOn = time.Now()
+	policy.UpdatedBy = userId
+	return impl.terminalCommandPolicyRepository.Update(policy)
+}
+
+func (impl *TerminalCommandPolicyServiceImpl) GetPolicy(id int) (*TerminalCommandPolicyDto, error) {
+	policy, err := impl.terminalCommandPolicyRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in getting terminal command policy", "err", err, "id", id)
+		return nil, err
+	}
+	return adaptTerminalCommandPolicy(policy)
+}
+
+func (impl *TerminalCommandPolicyServiceImpl) GetPolicies() ([]*TerminalCommandPolicyDto, error) {
+	policies, err := impl.terminalCommandPolicyRepository.FindAllActive()
+	if err != nil {
+		return nil, err
+	}
+	policyDtos := make([]*TerminalCommandPolicyDto, 0, len(policies))
+	for _, policy := range policies {
+		policyDto, err := adaptTerminalCommandPolicy(policy)
+		if err != nil {
+			impl.logger.Errorw("error in reading terminal command policy", "err", err, "id", policy.Id)
+			return nil, err
+		}
+		policyDtos = append(policyDtos, policyDto)
+	}
+	return policyDtos, nil
+}
+
+func (impl *TerminalCommandPolicyServiceImpl) GetViolations(filter *repository.TerminalCommandViolationFilter) ([]*TerminalCommandViolationDto, error) {
+	violations, err := impl.terminalCommandPolicyRepository.FindViolations(filter)
+	if err != nil {
+		return nil, err
+	}
+	violationDtos := make([]*TerminalCommandViolationDto, 0, len(violations))
+	for _, violation := range violations {
+		violationDtos = append(violationDtos, &TerminalCommandViolationDto{
+			Id:            violation.Id,
+			PolicyId:      violation.PolicyId,
+			SessionId:     violation.SessionId,
+			ClusterId:     violation.ClusterId,
+			Namespace:     violation.Namespace,
+			PodName:       violation.PodName,
+			ContainerName: violation.ContainerName,
+			UserId:        violation.UserId,
+			Command:       violation.Command,
+			Reason:        violation.Reason,
+			BlockedOn:     violation.BlockedOn,
+		})
+	}
+	return violationDtos, nil
+}
+
+func (impl *TerminalCommandPolicyServiceImpl) NewCommandGuard(req *TerminalSessionRequest, clusterId int) (*TerminalCommandGuard, error) {
+	policies, err := impl.terminalCommandPolicyRepository.FindActiveByClusterId(clusterId)
+	if err != nil {
+		return nil, err
+	}
+	var policy *repository.TerminalCommandPolicy
+	for _, p := range policies {
+		if p.Namespace == req.Namespace {
+			policy = p
+			break
+		} else if len(p.Namespace) == 0 {
+			policy = p
+		}
+	}
+	if policy == nil {
+		return nil, nil
+	}
+	policyDto, err := adaptTerminalCommandPolicy(policy)
+	if err != nil {
+		impl.logger.Errorw("error in reading terminal command policy", "err", err, "id", policy.Id)
+		return nil, err
+	}
+	onViolation := func(command string, reason string) {
+		violation := &repository.TerminalCommandViolation{
+			PolicyId:      policy.Id,
+			SessionId:     req.SessionId,
+			ClusterId:     clusterId,
+			Namespace:     req.Namespace,
+			PodName:       req.PodName,
+			ContainerName: req.ContainerName,
+			UserId:        req.UserId,
+			Command:       command,
+			Reason:        reason,
+			BlockedOn:     time.Now(),
+		}
+		go impl.terminalCommandPolicyRepository.SaveViolation(violation)
+	}
+	return NewTerminalCommandGuard(policyDto.AllowedCommands, policyDto.DeniedCommands, onViolation), nil
+}
+
+func (impl *TerminalCommandPolicyServiceImpl) validatePolicy(request *TerminalCommandPolicyDto) error {
+	if len(trimCommands(request.AllowedCommands)) == 0 && len(trimCommands(request.DeniedCommands)) == 0 {
+		return &util.ApiError{
+			HttpStatusCode:  http.StatusBadRequest,
+			InternalMessage: "terminal command policy has no allowed or denied commands",
+			UserMessage:     "terminal command policy needs allowed or denied commands",
+		}
+	}
+	policies, err := impl.terminalCommandPolicyRepository.FindActiveByClusterId(request.ClusterId)
+	if err != nil && err != pg.ErrNoRows {
+		return err
+	}
+	for _, policy := range policies {
+		if policy.Id != request.Id && policy.Namespace == request.Namespace {
+			return &util.ApiError{
+				HttpStatusCode:  http.StatusConflict,
+				InternalMessage: fmt.Sprintf("terminal command policy %d already exists for the namespace", policy.Id),
+				UserMessage:     fmt.Sprintf("policy %s already applies to this cluster and namespace", policy.Name),
+			}
+		}
+	}
+	return nil
+}
+
+func trimCommands(commands []string) []string {
+	var trimmed []string
+	for _, command := range commands {
+		if command = strings.Join(strings.Fields(command), " "); len(command) > 0 {
+			trimmed = append(trimmed, command)
+		}
+	}
+	return trimmed
+}
+
+func adaptTerminalCommandPolicyDto(request *TerminalCommandPolicyDto, policy *repository.TerminalCommandPolicy) error {
+	allowedCommands, err := json.Marshal(trimCommands(request.AllowedCommands))
+	if err != nil {
+		return err
+	}
+	deniedCommands, err := json.Marshal(trimCommands(request.DeniedCommands))
+	if err != nil {
+		return err
+	}
+	policy.Name = request.Name
+	policy.ClusterId = request.ClusterId
+	policy.Namespace = request.Namespace
+	policy.AllowedCommands = string(allowedCommands)
+	policy.DeniedCommands = string(deniedCommands)
+	return nil
+}
+
+func adaptTerminalCommandPolicy(policy *repository.TerminalCommandPolicy) (*TerminalCommandPolicyDto, error) {
+	policyDto := &TerminalCommandPolicyDto{
+		Id:        policy.Id,
+		Name:      policy.Name,
+		ClusterId: policy.ClusterId,
+		Namespace: policy.Namespace,
+	}
+	if len(policy.AllowedCommands) > 0 {
+		if err := json.Unmarshal([]byte(policy.AllowedCommands), &policyDto.AllowedCommands); err != nil {
+			return nil, err
+		}
+	}
+	if len(policy.DeniedCommands) > 0 {
+		if err := json.Unmarshal([]byte(policy.DeniedCommands), &policyDto.DeniedCommands); err != nil {
+			return nil, err
+		}
+	}
+	return policyDto, nil
+}
@@ -0,0 +1,259 @@
+package terminal
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	blob_storage "github.com/devtron-labs/common-lib/blob-storage"
+	"github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.uber.org/zap"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// TerminalRecordingConfig reads the blob storage used for ci logs, recordings are kept in the ci log bucket under KeyPrefix
+type TerminalRecordingConfig struct {
+	KeyPrefix                     string                       `env:"TERMINAL_RECORDING_KEY_PREFIX" envDefault:"terminal-recordings"`
+	LocalPath                     string                       `env:"BASE_LOG_LOCATION_PATH" envDefault:"/home/devtron/"`
+	CloudProvider                 blob_storage.BlobStorageType `env:"BLOB_STORAGE_PROVIDER" envDefault:"S3"`
+	BlobStorageEnabled            bool                         `env:"BLOB_STORAGE_ENABLED" envDefault:"false"`
+	BlobStorageS3AccessKey        string                       `env:"BLOB_STORAGE_S3_ACCESS_KEY"`
+	BlobStorageS3SecretKey        string                       `env:"BLOB_STORAGE_S3_SECRET_KEY"`
+	BlobStorageS3Endpoint         string                       `env:"BLOB_STORAGE_S3_ENDPOINT"`
+	BlobStorageS3EndpointInsecure bool                         `env:"BLOB_STORAGE_S3_ENDPOINT_INSECURE" envDefault:"false"`
+	BlobStorageS3BucketVersioned  bool                         `env:"BLOB_STORAGE_S3_BUCKET_VERSIONED" envDefault:"true"`
+	BlobStorageGcpCredentialJson  string                       `env:"BLOB_STORAGE_GCP_CREDENTIALS_JSON"`
+	AzureAccountName              string                       `env:"AZURE_ACCOUNT_NAME"`
+	AzureAccountKey               string                       `env:"AZURE_ACCOUNT_KEY"`
+	AzureBlobContainerCiLog       string                       `env:"AZURE_BLOB_CONTAINER_CI_LOG"`
+	BucketName                    string                       `env:"DEFAULT_BUILD_LOGS_BUCKET" envDefault:"devtron-pro-ci-logs"`
+	BucketRegion                  string                       `env:"DEFAULT_CD_LOGS_BUCKET_REGION" envDefault:"us-east-2"`
+}
+
+type TerminalSessionRecordingDto struct {
+	Id            int       `json:"id"`
+	SessionId     string    `json:"sessionId"`
+	ClusterId     int       `json:"clusterId"`
+	Namespace     string    `json:"namespace"`
+	PodName       string    `json:"podName"`
+	ContainerName string    `json:"containerName"`
+	Shell         string    `json:"shell"`
+	UserId        int32     `json:"userId"`
+	AppId         int       `json:"appId,omitempty"`
+	EnvId         int       `json:"envId,omitempty"`
+	Size          int64     `json:"size"`
+	Status        string    `json:"status"`
+	Message       string    `json:"message,omitempty"`
+	StartedOn     time.Time `json:"startedOn"`
+	EndedOn       time.Time `json:"endedOn,omitempty"`
+}
+
+type TerminalSessionRecordingService interface {
+	// StartRecording saves the recording entry of the session and returns the recorder to hook into the session
+	StartRecording(req *TerminalSessionRequest, clusterId int) (*TerminalSessionRecorder, error)
+	// FinishRecording closes the recorder and uploads the cast file to blob storage
+	FinishRecording(recorder *TerminalSessionRecorder)
+	GetRecordings(filter *repository.TerminalSessionRecordingFilter) ([]*TerminalSessionRecordingDto, error)
+	GetRecording(id int) (*TerminalSessionRecordingDto, error)
+	// DownloadRecording downloads the cast file of an uploaded recording, the returned func removes the local copy
+	DownloadRecording(id int) (*os.File, func() error, error)
+}
+
+type TerminalSessionRecordingServiceImpl struct {
+	logger                             *zap.SugaredLogger
+	terminalSessionRecordingRepository repository.TerminalSessionRecordingRepository
+	config                             *TerminalRecordingConfig
+}
+
+func NewTerminalSessionRecordingServiceImpl(logger *zap.SugaredLogger,
+	terminalSessionRecordingRepository repository.TerminalSessionRecordingRepository) (*TerminalSessionRecordingServiceImpl, error) {
+	config := &TerminalRecordingConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing terminal recording config", "err", err)
+		return nil, err
+	}
+	return &TerminalSessionRecordingServiceImpl{
+		logger:                             logger,
+		terminalSessionRecordingRepository: terminalSessionRecordingRepository,
+		config:                             config,
+	}, nil
+}
+
+func (impl *TerminalSessionRecordingServiceImpl) StartRecording(req *TerminalSessionRequest, clusterId int) (*TerminalSessionRecorder, error) {
+	if !impl.config.BlobStorageEnabled {
+		return nil, fmt.Errorf("terminal recording is enabled for the cluster but blob storage is not configured")
+	}
+	localDir := filepath.Join(impl.config.LocalPath, impl.config.KeyPrefix)
+	err := os.MkdirAll(localDir, os.ModePerm)
+	if err != nil {
+		impl.logger.Errorw("error in creating terminal recording dir", "err", err, "dir", localDir)
+		return nil, err
+	}
+	title := fmt.Sprintf("user %d on %s/%s/%s", req.UserId, req.Namespace, req.PodName, req.ContainerName)
+	recorder, err := NewTerminalSessionRecorder(filepath.Join(localDir, req.SessionId+".cast"), title, req.Shell)
+	if err != nil {
+		impl.logger.Errorw("error in creating terminal session recorder", "err", err, "sessionId", req.SessionId)
+		return nil, err
+	}
+	recording := &repository.TerminalSessionRecording{
+		SessionId:     req.SessionId,
+		ClusterId:     clusterId,
+		Namespace:     req.Namespace,
+		PodName:       req.PodName,
+		ContainerName: req.ContainerName,
+		Shell:         req.Shell,
+		UserId:        req.UserId,
+		AppId:         req.AppId,
+		EnvId:         req.EnvironmentId,
+		BlobKey:       path.Join(impl.config.KeyPrefix, fmt.Sprint(clusterId), req.SessionId+".cast"),
+		Status:        repository.TERMINAL_RECORDING_STATUS_RECORDING,
+		StartedOn:     recorder.startedOn,
+		AuditLog:      sql.AuditLog{CreatedBy: req.UserId, CreatedOn: time.Now(), UpdatedOn: time.Now(), UpdatedBy: req.UserId},
+	}
+	err = impl.terminalSessionRecordingRepository.Save(recording)
+	if err != nil {
+		recorder.Close()
+		os.Remove(recorder.filePath)
+		return nil, err
+	}
+	recorder.recordingId = recording.Id
+	return recorder, nil
+}
+
+func (impl *TerminalSessionRecordingServiceImpl) FinishRecording(recorder *TerminalSessionRecorder) {
+	defer os.Remove(recorder.filePath)
+	recording, err := impl.terminalSessionRecordingRepository.FindById(recorder.recordingId)
+	if err != nil {
+		impl.logger.Errorw("error in getting terminal session recording", "err", err, "recordingId", recorder.recordingId)
+		recorder.Close()
+		return
+	}
+	recording.EndedOn = time.Now()
+	recording.Status = repository.TERMINAL_RECORDING_STATUS_UPLOADED
+	recording.Size, err = recorder.Close()
+	if err == nil {
+		err = impl.upload(recorder.filePath, recording.BlobKey)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving terminal session recording", "err", err, "sessionId", recording.SessionId)
+		recording.Status = repository.TERMINAL_RECORDING_STATUS_FAILED
+		recording.Message = err.Error()
+	}
+	recording.UpdatedOn = time.Now()
+	err = impl.terminalSessionRecordingRepository.Update(recording)
+	if err != nil {
+		impl.logger.Errorw("error in updating terminal session recording status", "err", err, "sessionId", recording.SessionId)
+	}
+}
+
+func (impl *TerminalSessionRecordingServiceImpl) upload(filePath, blobKey string) error {
+	request := impl.getBlobStorageRequest()
+	request.SourceKey = filePath
+	request.DestinationKey = blobKey
+	return blob_storage.NewBlobStorageServiceImpl(impl.logger).UploadToBlobWithSession(request)
+}
+
+func (impl *TerminalSessionRecordingServiceImpl) getBlobStorageRequest() *blob_storage.BlobStorageRequest {
+	storageType := impl.config.CloudProvider
+	if storageType == blob_storage.BLOB_STORAGE_MINIO {
+		// minio is reached through its s3 endpoint
+		storageType = blob_storage.BLOB_STORAGE_S3
+	}
+	return &blob_storage.BlobStorageRequest{
+		StorageType: storageType,
+		AwsS3BaseConfig: &blob_storage.AwsS3BaseConfig{
+			AccessKey:         impl.config.BlobStorageS3AccessKey,
+			Passkey:           impl.config.BlobStorageS3SecretKey,
+			EndpointUrl:       impl.config.BlobStorageS3Endpoint,
+			IsInSecure:        impl.config.BlobStorageS3EndpointInsecure,
+			BucketName:        impl.config.BucketName,
+			Region:            impl.config.BucketRegion,
+			VersioningEnabled: impl.config.BlobStorageS3BucketVersioned,
+		},
+		AzureBlobBaseConfig: &blob_storage.AzureBlobBaseConfig{
+			Enabled:           storageType == blob_storage.BLOB_STORAGE_AZURE,
+			AccountName:       impl.config.AzureAccountName,
+			AccountKey:        impl.config.AzureAccountKey,
+			BlobContainerName: impl.config.AzureBlobContainerCiLog,
+		},
+		GcpBlobBaseConfig: &blob_storage.GcpBlobBaseConfig{
+			BucketName:             impl.config.BucketName,
+			CredentialFileJsonData: impl.config.BlobStorageGcpCredentialJson,
+		},
+	}
+}
+
+func (impl *TerminalSessionRecordingServiceImpl) GetRecordings(filter *repository.TerminalSessionRecordingFilter) ([]*TerminalSessionRecordingDto, error) {
+	recordings, err := impl.terminalSessionRecordingRepository.FindByFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	recordingDtos := make([]*TerminalSessionRecordingDto, 0, len(recordings))
+	for _, recording := range recordings {
+		recordingDtos = append(recordingDtos, adaptTerminalSessionRecording(recording))
+	}
+	return recordingDtos, nil
+}
+
+func (impl *TerminalSessionRecordingServiceImpl) GetRecording(id int) (*TerminalSessionRecordingDto, error) {
+	recording, err := impl.terminalSessionRecordingRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in getting terminal session recording", "err", err, "id", id)
+		return nil, err
+	}
+	return adaptTerminalSessionRecording(recording), nil
+}
+
+func (impl *TerminalSessionRecordingServiceImpl) DownloadRecording(id int) (*os.File, func() error, error) {
+	recording, err := impl.terminalSessionRecordingRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in getting terminal session recording", "err", err, "id", id)
+		return nil, nil, err
+	}
+	if recording.Status != repository.TERMINAL_RECORDING_STATUS_UPLOADED {
+		return nil, nil, fmt.Errorf("terminal session recording is not available, status: %s", recording.Status)
+	}
+	tempFile := filepath.Clean(filepath.Join(impl.config.LocalPath, fmt.Sprintf("%s-%d.cast", recording.SessionId, time.Now().UnixNano())))
+	request := impl.getBlobStorageRequest()
+	request.SourceKey = recording.BlobKey
+	request.DestinationKey = tempFile
+	_, _, err = blob_storage.NewBlobStorageServiceImpl(impl.logger).Get(request)
+	if err != nil {
+		impl.logger.Errorw("error in downloading terminal session recording", "err", err, "id", id)
+		os.Remove(tempFile)
+		return nil, nil, err
+	}
+	file, err := os.Open(tempFile)
+	if err != nil {
+		os.Remove(tempFile)
+		return nil, nil, err
+	}
+	cleanUpFunc := func() error {
+		file.Close()
+		return os.Remove(tempFile)
+	}
+	return file, cleanUpFunc, nil
+}
+
+func adaptTerminalSessionRecording(recording *repository.TerminalSessionRecording) *TerminalSessionRecordingDto {
+	return &TerminalSessionRecordingDto{
+		Id:            recording.Id,
+		SessionId:     recording.SessionId,
+		ClusterId:     recording.ClusterId,
+		Namespace:     recording.Namespace,
+		PodName:       recording.PodName,
+		ContainerName: recording.ContainerName,
+		Shell:         recording.Shell,
+		UserId:        recording.UserId,
+		AppId:         recording.AppId,
+		EnvId:         recording.EnvId,
+		Size:          recording.Size,
+		Status:        recording.Status,
+		Message:       recording.Message,
+		StartedOn:     recording.StartedOn,
+		EndedOn:       recording.EndedOn,
+	}
+}
@@ -40,6 +40,22 @@ func (_m *TerminalSessionHandler) Close(sessionId string, statusCode uint32, msg
 	_m.Called(sessionId, statusCode, msg)
 }
 
+// GetActiveSessions provides a mock function with given fields:
+func (_m *TerminalSessionHandler) GetActiveSessions() []*terminal.TerminalSessionDetail {
+	ret := _m.Called()
+
+	var r0 []*terminal.TerminalSessionDetail
+	if rf, ok := ret.Get(0).(func() []*terminal.TerminalSessionDetail); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*terminal.TerminalSessionDetail)
+		}
+	}
+
+	return r0
+}
+
 // GetTerminalSession provides a mock function with given fields: req
 func (_m *TerminalSessionHandler) GetTerminalSession(req *terminal.TerminalSessionRequest) (int, *terminal.TerminalMessage, error) {
 	ret := _m.Called(req)
@@ -70,6 +86,11 @@ func (_m *TerminalSessionHandler) GetTerminalSession(req *terminal.TerminalSessi
 	return r0, r1, r2
 }
 
+// RegisterListener provides a mock function with given fields: listener
+func (_m *TerminalSessionHandler) RegisterListener(listener terminal.TerminalSessionListener) {
+	_m.Called(listener)
+}
+
 // RunCmdInRemotePod provides a mock function with given fields: req, cmds
 func (_m *TerminalSessionHandler) RunCmdInRemotePod(req *terminal.TerminalSessionRequest, cmds []string) (*bytes.Buffer, *bytes.Buffer, error) {
 	ret := _m.Called(req, cmds)
@@ -102,6 +123,20 @@ func (_m *TerminalSessionHandler) RunCmdInRemotePod(req *terminal.TerminalSessio
 	return r0, r1, r2
 }
 
+// TerminateSession provides a mock function with given fields: sessionId, reason
+func (_m *TerminalSessionHandler) TerminateSession(sessionId string, reason string) error {
+	ret := _m.Called(sessionId, reason)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(sessionId, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ValidateSession provides a mock function with given fields: sessionId
 func (_m *TerminalSessionHandler) ValidateSession(sessionId string) bool {
 	ret := _m.Called(sessionId)
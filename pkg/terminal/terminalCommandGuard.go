@@ -0,0 +1,360 @@
+package terminal
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	keyEnter          = '\r'
+	keyNewLine        = '\n'
+	keyInterrupt      = '\x03'
+	keyEndOfFile      = '\x04'
+	keyBackspace      = '\b'
+	keyDelete         = '\x7f'
+	keyClearScreen    = '\x0c'
+	keyKillLine       = '\x15'
+	keyKillWord       = '\x17'
+	bracketedPasteOn  = "\x1b[200~"
+	bracketedPasteOff = "\x1b[201~"
+)
+
+// commandSeparators splits a command line into the commands it runs, redirections like 2>&1 are dropped first so
+// that they are not taken as a background operator
+var commandSeparators = strings.NewReplacer(
+	">&", ">", "&>", ">",
+	"&&", "\n", "||", "\n", "$(", "\n", "`", "\n",
+	";", "\n", "|", "\n", "&", "\n", "(", "\n", ")", "\n",
+)
+
+// commandWrappers run the command passed to them, the wrapped command is checked instead of the wrapper
+var commandWrappers = map[string]bool{
+	"sudo": true, "env": true, "command": true, "exec": true, "nohup": true, "time": true, "nice": true, "xargs": true,
+	"watch": true, "timeout": true, "busybox": true,
+}
+
+// shellInterpreters run commands passed as an argument or read from stdin, these commands are not seen by the guard
+var shellInterpreters = map[string]bool{"sh": true, "bash": true, "zsh": true, "dash": true, "ksh": true, "ash": true}
+
+// shellReservedWords may come before the command of a segment, as in "if true; then rm x; fi", they are dropped and
+// the command after them is checked
+var shellReservedWords = map[string]bool{
+	"{": true, "}": true, "!": true, "if": true, "then": true, "else": true, "elif": true, "fi": true, "while": true,
+	"until": true, "do": true, "done": true, "esac": true,
+}
+
+// shellLoopWords start a segment which runs no command, the commands of the loop or case come after do or a pattern
+var shellLoopWords = map[string]bool{"for": true, "select": true, "case": true}
+
+// functionDefinition matches "name()" which defines a function that may later run any command under its own name
+var functionDefinition = regexp.MustCompile(`(^|[\s;&|{}()])[\w.:-]+\s*\(\s*\)`)
+
+// TerminalCommandGuard enforces a command policy on the stdin of a terminal session. Keystrokes are tracked to rebuild
+// the command line and the line is checked when enter is pressed, a blocked line is cancelled with ctrl-c instead of
+// being run. Lines edited through completion, history or cursor movement can not be rebuilt and are blocked, as are
+// lines typed into full screen programs, so policies should only allow non interactive commands.
+type TerminalCommandGuard struct {
+	allowedCommands [][]string
+	deniedCommands  [][]string
+	line            []rune
+	untracked       bool
+	onViolation     func(command string, reason string)
+}
+
+func NewTerminalCommandGuard(allowedCommands, deniedCommands []string, onViolation func(command string, reason string)) *TerminalCommandGuard {
+	return &TerminalCommandGuard{
+		allowedCommands: tokenizeCommands(allowedCommands),
+		deniedCommands:  tokenizeCommands(deniedCommands),
+		onViolation:     onViolation,
+	}
+}
+
+func tokenizeCommands(commands []string) [][]string {
+	var tokenized [][]string
+	for _, command := range commands {
+		if tokens := strings.Fields(command); len(tokens) > 0 {
+			tokenized = append(tokenized, tokens)
+		}
+	}
+	return tokenized
+}
+
+// Filter returns the input to forward to the process, when a line is blocked the rest of the input is dropped and the
+// returned message says why
+func (g *TerminalCommandGuard) Filter(data string) (forward string, blockedMessage string) {
+	if g == nil {
+		return data, ""
+	}
+	var forwarded strings.Builder
+	for i := 0; i < len(data); {
+		// pasted text is wrapped in these markers by shells which enable bracketed paste, the text itself is tracked
+		if marker := bracketedPasteMarker(data[i:]); len(marker) > 0 {
+			forwarded.WriteString(marker)
+			i += len(marker)
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(data[i:])
+		if (r == keyEnter || r == keyNewLine) && endsWithLineContinuation(g.line) {
+			// the shell joins the next line to this one, so the line is checked once the command is complete
+			g.line = g.line[:len(g.line)-1]
+		} else if r == keyEnter || r == keyNewLine {
+			command, untracked := string(g.line), g.untracked
+			g.line, g.untracked = nil, false
+			if reason := g.check(command, untracked); reason != "" {
+				if untracked {
+					command = ""
+				}
+				if g.onViolation != nil {
+					g.onViolation(command, reason)
+				}
+				forwarded.WriteRune(keyInterrupt)
+				return forwarded.String(), fmt.Sprintf("command blocked by terminal policy: %s", reason)
+			}
+		} else {
+			g.track(r)
+		}
+		forwarded.WriteString(data[i : i+size])
+		i += size
+	}
+	return forwarded.String(), ""
+}
+
+// endsWithLineContinuation reports whether the line ends with a backslash which is not itself escaped
+func endsWithLineContinuation(line []rune) bool {
+	backslashes := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		backslashes++
+	}
+	return backslashes%2 == 1
+}
+
+func bracketedPasteMarker(data string) string {
+	if strings.HasPrefix(data, bracketedPasteOn) {
+		return bracketedPasteOn
+	}
+	if strings.HasPrefix(data, bracketedPasteOff) {
+		return bracketedPasteOff
+	}
+	return ""
+}
+
+func (g *TerminalCommandGuard) track(r rune) {
+	switch r {
+	case keyInterrupt, keyKillLine:
+		g.line, g.untracked = nil, false
+	case keyBackspace, keyDelete:
+		if len(g.line) > 0 {
+			g.line = g.line[:len(g.line)-1]
+		}
+	case keyKillWord:
+		line := strings.TrimRightFunc(string(g.line), unicode.IsSpace)
+		if i := strings.LastIndexFunc(line, unicode.IsSpace); i >= 0 {
+			g.line = []rune(line[:i+1])
+		} else {
+			g.line = nil
+		}
+	case keyEndOfFile, keyClearScreen:
+	default:
+		if unicode.IsPrint(r) {
+			g.line = append(g.line, r)
+		} else {
+			// tab completion, escape sequences of arrow keys and other line editing keys change the line in ways which can not be followed
+			g.untracked = true
+		}
+	}
+}
+
+// check returns why the command line is not allowed, or empty if it is
+func (g *TerminalCommandGuard) check(line string, untracked bool) string {
+	if untracked {
+		return "commands edited with completion, history or cursor keys can not be checked, type the command in full"
+	}
+	if len(g.deniedCommands) > 0 && functionDefinition.MatchString(line) {
+		return "function definitions can not be checked"
+	}
+	for i, segment := range strings.Split(commandSeparators.Replace(line), "\n") {
+		command := commandTokens(segment)
+		if len(command) == 0 {
+			continue
+		}
+		for _, denied := range g.deniedCommands {
+			if matchesDeniedCommand(command, denied) {
+				return fmt.Sprintf("%s is denied", strings.Join(denied, " "))
+			}
+		}
+		if len(g.deniedCommands) > 0 {
+			if reason := uncheckedCommandReason(command, i == 0); reason != "" {
+				return reason
+			}
+		}
+		if len(g.allowedCommands) == 0 {
+			continue
+		}
+		allowed := false
+		for _, allowedCommand := range g.allowedCommands {
+			if matchesAllowedCommand(command, allowedCommand) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Sprintf("%s is not allowed", command[0])
+		}
+	}
+	return ""
+}
+
+// uncheckedCommandReason returns why the command runs other commands which can not be checked against a deny list, or
+// empty if it does not. a shell which is not the first command of the line may be reading commands from a pipe
+func uncheckedCommandReason(command []string, first bool) string {
+	if strings.Contains(command[0], "$") {
+		return "commands named by variables can not be checked, type the command name in full"
+	}
+	if command[0] != "[" && command[0] != "[[" && strings.ContainsAny(command[0], "*?[{") {
+		return "commands named by a pattern can not be checked, type the command name in full"
+	}
+	if command[0] == "eval" {
+		return "commands run through eval can not be checked"
+	}
+	if command[0] == "alias" || command[0] == "function" {
+		return fmt.Sprintf("%s definitions can not be checked", command[0])
+	}
+	if !shellInterpreters[command[0]] {
+		return ""
+	}
+	if !first {
+		return fmt.Sprintf("commands piped to %s can not be checked", command[0])
+	}
+	for _, arg := range command[1:] {
+		if arg == "--" || !strings.HasPrefix(arg, "-") {
+			break
+		}
+		if !strings.HasPrefix(arg, "--") && strings.ContainsRune(arg[1:], 'c') {
+			return fmt.Sprintf("commands run through %s -c can not be checked", command[0])
+		}
+	}
+	return ""
+}
+
+// shellWords splits a segment into words the way a shell does, quotes are removed and escaped characters are taken
+// literally so that 'rm', r""m and \rm are all rm. an unterminated quote runs to the end of the segment
+func shellWords(segment string) []string {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	runes := []rune(segment)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else if r == '\\' && i+1 < len(runes) && strings.ContainsRune("$`\"\\", runes[i+1]) {
+				i++
+				word.WriteRune(runes[i])
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			inWord = true
+			if i+1 < len(runes) {
+				i++
+				word.WriteRune(runes[i])
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+// commandTokens returns the command run by a segment without variable assignments, reserved words and wrappers like
+// sudo, the command itself is reduced to its base name
+func commandTokens(segment string) []string {
+	tokens := shellWords(segment)
+	for len(tokens) > 0 {
+		token := tokens[0]
+		if shellReservedWords[token] {
+			tokens = tokens[1:]
+			continue
+		}
+		if shellLoopWords[token] {
+			return nil
+		}
+		if strings.Contains(token, "=") && !strings.HasPrefix(token, "-") && !strings.HasPrefix(token, "=") {
+			tokens = tokens[1:]
+			continue
+		}
+		if commandWrappers[path.Base(token)] {
+			wrapper := path.Base(token)
+			tokens = tokens[1:]
+			for len(tokens) > 0 && strings.HasPrefix(tokens[0], "-") {
+				tokens = tokens[1:]
+			}
+			if wrapper == "timeout" && len(tokens) > 0 {
+				tokens = tokens[1:]
+			}
+			continue
+		}
+		break
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+	return append([]string{path.Base(tokens[0])}, tokens[1:]...)
+}
+
+// matchesAllowedCommand allows the command when it starts with all tokens of allowed, so "kubectl get" does not allow
+// "kubectl delete"
+func matchesAllowedCommand(command, allowed []string) bool {
+	if len(command) < len(allowed) {
+		return false
+	}
+	for i, token := range allowed {
+		if command[i] != token {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesDeniedCommand denies the command when its name matches and the other tokens of denied appear in order among
+// its arguments, so "kubectl delete" also denies "kubectl -n prod delete pod"
+func matchesDeniedCommand(command, denied []string) bool {
+	if command[0] != denied[0] {
+		return false
+	}
+	next := 1
+	for _, arg := range command[1:] {
+		if next == len(denied) {
+			break
+		}
+		if arg == denied[next] {
+			next++
+		}
+	}
+	return next == len(denied)
+}
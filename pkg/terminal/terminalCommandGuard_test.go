@@ -0,0 +1,99 @@
+package terminal
+
+import (
+	"testing"
+)
+
+func TestTerminalCommandGuard(t *testing.T) {
+	allowed := []string{"ls", "cat", "curl", "kubectl get"}
+	denied := []string{"rm", "kubectl delete"}
+	tests := []struct {
+		name        string
+		allowed     []string
+		input       string
+		wantForward string
+		wantBlocked bool
+	}{
+		{name: "allowed command", allowed: allowed, input: "ls -la\r", wantForward: "ls -la\r"},
+		{name: "allowed command with path", allowed: allowed, input: "/bin/cat /etc/hosts\r", wantForward: "/bin/cat /etc/hosts\r"},
+		{name: "allowed sub command", allowed: allowed, input: "kubectl get pods\r", wantForward: "kubectl get pods\r"},
+		{name: "command not in allowed list", allowed: allowed, input: "vi file\r", wantForward: "vi file\x03", wantBlocked: true},
+		{name: "denied command", input: "rm -rf /tmp\r", wantForward: "rm -rf /tmp\x03", wantBlocked: true},
+		{name: "denied command after allowed command", allowed: allowed, input: "ls; rm file\r", wantForward: "ls; rm file\x03", wantBlocked: true},
+		{name: "denied command in pipe", allowed: allowed, input: "ls | sudo rm\r", wantForward: "ls | sudo rm\x03", wantBlocked: true},
+		{name: "denied command in substitution", allowed: allowed, input: "cat $(rm x)\r", wantForward: "cat $(rm x)\x03", wantBlocked: true},
+		{name: "denied sub command with flags", input: "kubectl -n prod delete pod x\r", wantForward: "kubectl -n prod delete pod x\x03", wantBlocked: true},
+		{name: "allowed list does not allow other sub commands", allowed: allowed, input: "kubectl apply -f x\r", wantForward: "kubectl apply -f x\x03", wantBlocked: true},
+		{name: "redirection is not a separator", allowed: allowed, input: "curl localhost 2>&1\r", wantForward: "curl localhost 2>&1\r"},
+		{name: "backspace edits the line", allowed: allowed, input: "rx\x7f\x7fls\r", wantForward: "rx\x7f\x7fls\r"},
+		{name: "ctrl-u clears the line", allowed: allowed, input: "rm\x15ls\r", wantForward: "rm\x15ls\r"},
+		{name: "tab completion can not be checked", allowed: allowed, input: "ls fi\t\r", wantForward: "ls fi\t\x03", wantBlocked: true},
+		{name: "history can not be checked", input: "\x1b[A\r", wantForward: "\x1b[A\x03", wantBlocked: true},
+		{name: "bracketed paste is checked", allowed: allowed, input: "\x1b[200~rm x\x1b[201~\r", wantForward: "\x1b[200~rm x\x1b[201~\x03", wantBlocked: true},
+		{name: "input after a blocked line is dropped", input: "rm x\rls\r", wantForward: "rm x\x03", wantBlocked: true},
+		{name: "empty line", allowed: allowed, input: "\r", wantForward: "\r"},
+		{name: "escaped denied command", input: "\\rm -rf x\r", wantForward: "\\rm -rf x\x03", wantBlocked: true},
+		{name: "quoted denied command", input: "'rm' -rf x\r", wantForward: "'rm' -rf x\x03", wantBlocked: true},
+		{name: "denied command split by empty quotes", input: "r\"\"m x\r", wantForward: "r\"\"m x\x03", wantBlocked: true},
+		{name: "escaped character in denied command", input: "k\\ubectl delete pod\r", wantForward: "k\\ubectl delete pod\x03", wantBlocked: true},
+		{name: "quoted denied sub command", input: "kubectl \"delete\" pod\r", wantForward: "kubectl \"delete\" pod\x03", wantBlocked: true},
+		{name: "denied command through sh -c", input: "sh -c 'kubectl delete pod'\r", wantForward: "sh -c 'kubectl delete pod'\x03", wantBlocked: true},
+		{name: "denied command through combined shell flags", input: "bash -lc ls\r", wantForward: "bash -lc ls\x03", wantBlocked: true},
+		{name: "commands piped to a shell", input: "echo rm x | sh\r", wantForward: "echo rm x | sh\x03", wantBlocked: true},
+		{name: "denied command through eval", input: "eval 'r''m' x\r", wantForward: "eval 'r''m' x\x03", wantBlocked: true},
+		{name: "command named by a variable", input: "X=rm; $X x\r", wantForward: "X=rm; $X x\x03", wantBlocked: true},
+		{name: "quoted arguments are allowed", allowed: allowed, input: "cat 'my file' \"other file\"\r", wantForward: "cat 'my file' \"other file\"\r"},
+		{name: "interactive shell", input: "bash\r", wantForward: "bash\r"},
+		{name: "denied command in a group", input: "{ rm -rf /; }\r", wantForward: "{ rm -rf /; }\x03", wantBlocked: true},
+		{name: "denied command in if", input: "if true; then rm -rf /; fi\r", wantForward: "if true; then rm -rf /; fi\x03", wantBlocked: true},
+		{name: "negated denied command", input: "! rm -rf /\r", wantForward: "! rm -rf /\x03", wantBlocked: true},
+		{name: "denied command in a loop", input: "for i in 1; do rm -rf /; done\r", wantForward: "for i in 1; do rm -rf /; done\x03", wantBlocked: true},
+		{name: "allowed commands in a loop", allowed: allowed, input: "for f in a b; do cat $f; done\r", wantForward: "for f in a b; do cat $f; done\r"},
+		{name: "denied command split by a line continuation", input: "r\\\rm -rf /\r", wantForward: "r\\\rm -rf /\x03", wantBlocked: true},
+		{name: "escaped backslash is not a line continuation", allowed: allowed, input: "ls \\\\\r", wantForward: "ls \\\\\r"},
+		{name: "command named by a pattern", input: "/bin/r[m] -rf /\r", wantForward: "/bin/r[m] -rf /\x03", wantBlocked: true},
+		{name: "command named by a brace expansion", input: "{rm,-rf} /\r", wantForward: "{rm,-rf} /\x03", wantBlocked: true},
+		{name: "test command is not a pattern", input: "[ -f x ] && cat x\r", wantForward: "[ -f x ] && cat x\r"},
+		{name: "alias definition", input: "alias x=rm\r", wantForward: "alias x=rm\x03", wantBlocked: true},
+		{name: "function definition", input: "x() { ls; }\r", wantForward: "x() { ls; }\x03", wantBlocked: true},
+		{name: "function keyword", input: "function x { ls; }\r", wantForward: "function x { ls; }\x03", wantBlocked: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var violations []string
+			guard := NewTerminalCommandGuard(tt.allowed, denied, func(command string, reason string) {
+				violations = append(violations, reason)
+			})
+			forward, blockedMessage := guard.Filter(tt.input)
+			if forward != tt.wantForward {
+				t.Errorf("Filter() forward = %q, want %q", forward, tt.wantForward)
+			}
+			if (len(blockedMessage) > 0) != tt.wantBlocked || (len(violations) > 0) != tt.wantBlocked {
+				t.Errorf("Filter() blockedMessage = %q, violations = %v, wantBlocked %v", blockedMessage, violations, tt.wantBlocked)
+			}
+		})
+	}
+}
+
+func TestTerminalCommandGuardKeystrokes(t *testing.T) {
+	guard := NewTerminalCommandGuard(nil, []string{"rm"}, nil)
+	for _, key := range []string{"r", "m", " ", "x"} {
+		if forward, blockedMessage := guard.Filter(key); forward != key || len(blockedMessage) > 0 {
+			t.Fatalf("Filter(%q) = %q, %q", key, forward, blockedMessage)
+		}
+	}
+	if _, blockedMessage := guard.Filter("\r"); len(blockedMessage) == 0 {
+		t.Errorf("command typed key by key should be blocked")
+	}
+	if forward, blockedMessage := guard.Filter("\r"); forward != "\r" || len(blockedMessage) > 0 {
+		t.Errorf("line should be reset after a blocked command, got %q, %q", forward, blockedMessage)
+	}
+}
+
+func TestTerminalCommandGuardWithoutDenyList(t *testing.T) {
+	// shells are only checked for commands they run when there is a deny list, an allowed shell may run anything
+	guard := NewTerminalCommandGuard([]string{"sh"}, nil, nil)
+	if forward, blockedMessage := guard.Filter("sh -c ls\r"); forward != "sh -c ls\r" || len(blockedMessage) > 0 {
+		t.Errorf("Filter() = %q, %q", forward, blockedMessage)
+	}
+}
@@ -19,10 +19,12 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"github.com/caarlos0/env/v6"
 	"github.com/devtron-labs/devtron/pkg/cluster"
 	"github.com/devtron-labs/devtron/pkg/cluster/repository"
 	"github.com/devtron-labs/devtron/util/k8s"
 	errors1 "github.com/juju/errors"
+	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
 	"io"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -55,6 +57,9 @@ type TerminalSession struct {
 	sockJSSession sockjs.Session
 	sizeChan      chan remotecommand.TerminalSize
 	doneChan      chan struct{}
+	recorder      *TerminalSessionRecorder
+	commandGuard  *TerminalCommandGuard
+	limits        *terminalSessionLimits
 }
 
 // TerminalMessage is the messaging protocol between ShellController and TerminalSession.
@@ -96,10 +101,19 @@ func (t TerminalSession) Read(p []byte) (int, error) {
 		return copy(p, END_OF_TRANSMISSION), err
 	}
 
+	t.limits.recordActivity()
 	switch msg.Op {
 	case "stdin":
-		return copy(p, msg.Data), nil
+		t.recorder.RecordInput(msg.Data)
+		data, blockedMessage := t.commandGuard.Filter(msg.Data)
+		if len(blockedMessage) > 0 {
+			if err := t.Toast(blockedMessage); err != nil {
+				log.Println(err)
+			}
+		}
+		return copy(p, data), nil
 	case "resize":
+		t.recorder.RecordResize(msg.Cols, msg.Rows)
 		t.sizeChan <- remotecommand.TerminalSize{Width: msg.Cols, Height: msg.Rows}
 		return 0, nil
 	default:
@@ -121,6 +135,8 @@ func (t TerminalSession) Write(p []byte) (int, error) {
 	if err = t.sockJSSession.Send(string(msg)); err != nil {
 		return 0, err
 	}
+	t.recorder.RecordOutput(string(p))
+	t.limits.recordActivity()
 	return len(p), nil
 }
 
@@ -360,6 +376,9 @@ type TerminalSessionHandler interface {
 	ValidateShell(req *TerminalSessionRequest) (bool, error)
 	AutoSelectShell(req *TerminalSessionRequest) (string, error)
 	RunCmdInRemotePod(req *TerminalSessionRequest, cmds []string) (*bytes.Buffer, *bytes.Buffer, error)
+	GetActiveSessions() []*TerminalSessionDetail
+	TerminateSession(sessionId string, reason string) error
+	RegisterListener(listener TerminalSessionListener)
 }
 
 type TerminalSessionHandlerImpl struct {
@@ -368,17 +387,42 @@ type TerminalSessionHandlerImpl struct {
 	logger                    *zap.SugaredLogger
 	k8sUtil                   *k8s.K8sUtil
 	ephemeralContainerService cluster.EphemeralContainerService
+	recordingService          TerminalSessionRecordingService
+	commandPolicyService      TerminalCommandPolicyService
+	sessionConfig             *TerminalSessionConfig
+	sessionLimitCron          *cron.Cron
+	listeners                 []TerminalSessionListener
+	listenersLock             sync.RWMutex
 }
 
 func NewTerminalSessionHandlerImpl(environmentService cluster.EnvironmentService, clusterService cluster.ClusterService,
-	logger *zap.SugaredLogger, k8sUtil *k8s.K8sUtil, ephemeralContainerService cluster.EphemeralContainerService) *TerminalSessionHandlerImpl {
-	return &TerminalSessionHandlerImpl{
+	logger *zap.SugaredLogger, k8sUtil *k8s.K8sUtil, ephemeralContainerService cluster.EphemeralContainerService,
+	recordingService TerminalSessionRecordingService, commandPolicyService TerminalCommandPolicyService) (*TerminalSessionHandlerImpl, error) {
+	sessionConfig := &TerminalSessionConfig{}
+	err := env.Parse(sessionConfig)
+	if err != nil {
+		logger.Errorw("error in parsing TerminalSessionConfig from env", "err", err)
+		return nil, err
+	}
+	sessionLimitCron := cron.New(cron.WithChain())
+	handlerImpl := &TerminalSessionHandlerImpl{
 		environmentService:        environmentService,
 		clusterService:            clusterService,
 		logger:                    logger,
 		k8sUtil:                   k8sUtil,
 		ephemeralContainerService: ephemeralContainerService,
+		recordingService:          recordingService,
+		commandPolicyService:      commandPolicyService,
+		sessionConfig:             sessionConfig,
+		sessionLimitCron:          sessionLimitCron,
 	}
+	sessionLimitCron.Start()
+	_, err = sessionLimitCron.AddFunc(fmt.Sprintf("@every %ds", sessionConfig.LimitCheckIntervalSecs), handlerImpl.EnforceSessionLimits)
+	if err != nil {
+		logger.Errorw("error occurred while starting terminal session limit cron", "err", err, "intervalSecs", sessionConfig.LimitCheckIntervalSecs)
+		return nil, err
+	}
+	return handlerImpl, nil
 }
 
 func (impl *TerminalSessionHandlerImpl) Close(sessionId string, statusCode uint32, msg string) {
@@ -409,12 +453,32 @@ func (impl *TerminalSessionHandlerImpl) GetTerminalSession(req *TerminalSessionR
 		return statusCode, nil, err
 	}
 	req.SessionId = sessionID
+	clusterBean, err := impl.getClusterBean(req)
+	if err != nil {
+		return http.StatusInternalServerError, nil, err
+	}
+	commandGuard, err := impl.commandPolicyService.NewCommandGuard(req, clusterBean.Id)
+	if err != nil {
+		impl.logger.Errorw("error in getting terminal command policy", "err", err, "clusterId", clusterBean.Id, "namespace", req.Namespace)
+		return http.StatusInternalServerError, nil, err
+	}
+	var recorder *TerminalSessionRecorder
+	if clusterBean.TerminalRecordingEnabled {
+		recorder, err = impl.recordingService.StartRecording(req, clusterBean.Id)
+		if err != nil {
+			impl.logger.Errorw("error in starting terminal session recording", "err", err, "clusterId", clusterBean.Id)
+			return http.StatusInternalServerError, nil, err
+		}
+	}
 	terminalSessions.Set(sessionID, TerminalSession{
-		id:       sessionID,
-		bound:    make(chan error),
-		sizeChan: make(chan remotecommand.TerminalSize),
+		id:           sessionID,
+		bound:        make(chan error),
+		sizeChan:     make(chan remotecommand.TerminalSize),
+		recorder:     recorder,
+		commandGuard: commandGuard,
+		limits:       newTerminalSessionLimits(req, clusterBean.Id, clusterBean.TerminalIdleTimeoutMins, clusterBean.TerminalMaxSessionDurationMins),
 	})
-	config, client, err := impl.getClientConfig(req)
+	config, client, err := impl.getClientConfigForCluster(clusterBean)
 
 	go func() {
 		err := impl.saveEphemeralContainerTerminalAccessAudit(req)
@@ -425,30 +489,50 @@ func (impl *TerminalSessionHandlerImpl) GetTerminalSession(req *TerminalSessionR
 
 	if err != nil {
 		impl.logger.Errorw("error in fetching config", "err", err)
+		if recorder != nil {
+			impl.recordingService.FinishRecording(recorder)
+		}
 		return http.StatusInternalServerError, nil, err
 	}
-	go WaitForTerminal(client, config, req)
+	go func() {
+		WaitForTerminal(client, config, req)
+		if recorder != nil {
+			impl.recordingService.FinishRecording(recorder)
+		}
+	}()
 	return http.StatusOK, &TerminalMessage{SessionID: sessionID}, nil
 }
 
 func (impl *TerminalSessionHandlerImpl) getClientConfig(req *TerminalSessionRequest) (*rest.Config, *kubernetes.Clientset, error) {
+	clusterBean, err := impl.getClusterBean(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	return impl.getClientConfigForCluster(clusterBean)
+}
+
+func (impl *TerminalSessionHandlerImpl) getClusterBean(req *TerminalSessionRequest) (*cluster.ClusterBean, error) {
 	var clusterBean *cluster.ClusterBean
 	var err error
 	if req.ClusterId != 0 {
 		clusterBean, err = impl.clusterService.FindById(req.ClusterId)
 		if err != nil {
 			impl.logger.Errorw("error in fetching cluster detail", "envId", req.EnvironmentId, "err", err)
-			return nil, nil, err
+			return nil, err
 		}
 	} else if req.EnvironmentId != 0 {
 		clusterBean, err = impl.environmentService.FindClusterByEnvId(req.EnvironmentId)
 		if err != nil {
 			impl.logger.Errorw("error in fetching cluster detail", "envId", req.EnvironmentId, "err", err)
-			return nil, nil, err
+			return nil, err
 		}
 	} else {
-		return nil, nil, fmt.Errorf("not able to find cluster-config")
+		return nil, fmt.Errorf("not able to find cluster-config")
 	}
+	return clusterBean, nil
+}
+
+func (impl *TerminalSessionHandlerImpl) getClientConfigForCluster(clusterBean *cluster.ClusterBean) (*rest.Config, *kubernetes.Clientset, error) {
 	config, err := clusterBean.GetClusterConfig()
 	if err != nil {
 		impl.logger.Errorw("error in config", "err", err)
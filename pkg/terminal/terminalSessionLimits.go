@@ -0,0 +1,212 @@
+package terminal
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+type TerminalSessionConfig struct {
+	LimitCheckIntervalSecs int `env:"TERMINAL_SESSION_LIMIT_CHECK_INTERVAL_SECS" envDefault:"30"`
+	// TimeoutWarningMins is how long before an idle timeout or the max duration the user is warned
+	TimeoutWarningMins int `env:"TERMINAL_SESSION_TIMEOUT_WARNING_MINS" envDefault:"2"`
+}
+
+// TerminalSessionListener is notified when devtron closes a terminal session for exceeding the limits of its cluster
+// or on an admin's request, so that the pod or ephemeral container opened for the session can be cleaned up
+type TerminalSessionListener interface {
+	OnTerminalSessionTerminated(request *TerminalSessionRequest, clusterId int)
+}
+
+type TerminalSessionDetail struct {
+	SessionId       string    `json:"sessionId"`
+	ClusterId       int       `json:"clusterId"`
+	Namespace       string    `json:"namespace"`
+	PodName         string    `json:"podName"`
+	ContainerName   string    `json:"containerName"`
+	Shell           string    `json:"shell"`
+	UserId          int32     `json:"userId"`
+	AppId           int       `json:"appId,omitempty"`
+	EnvironmentId   int       `json:"environmentId,omitempty"`
+	StartedOn       time.Time `json:"startedOn"`
+	LastActivityOn  time.Time `json:"lastActivityOn"`
+	IdleTimeoutMins int       `json:"idleTimeoutMins"`
+	MaxDurationMins int       `json:"maxDurationMins"`
+}
+
+// terminalSessionLimits tracks the activity of a session against the idle timeout and max duration of its cluster,
+// a limit of 0 is unlimited
+type terminalSessionLimits struct {
+	lock           sync.Mutex
+	request        *TerminalSessionRequest
+	clusterId      int
+	idleTimeout    time.Duration
+	maxDuration    time.Duration
+	startedOn      time.Time
+	lastActivityOn time.Time
+	idleWarned     bool
+	durationWarned bool
+}
+
+func newTerminalSessionLimits(request *TerminalSessionRequest, clusterId int, idleTimeoutMins, maxDurationMins int) *terminalSessionLimits {
+	now := time.Now()
+	return &terminalSessionLimits{
+		request:        request,
+		clusterId:      clusterId,
+		idleTimeout:    time.Duration(idleTimeoutMins) * time.Minute,
+		maxDuration:    time.Duration(maxDurationMins) * time.Minute,
+		startedOn:      now,
+		lastActivityOn: now,
+	}
+}
+
+// recordActivity is a no-op on nil limits, sessions are bound to the handler before their limits can be set
+func (l *terminalSessionLimits) recordActivity() {
+	if l == nil {
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.lastActivityOn = time.Now()
+	l.idleWarned = false
+}
+
+// check returns the warning to show to the user once a limit is near, and the reason to close the session once a
+// limit is exceeded
+func (l *terminalSessionLimits) check(now time.Time, warningBefore time.Duration) (warning string, exceeded string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	idleFor := now.Sub(l.lastActivityOn)
+	openFor := now.Sub(l.startedOn)
+	if l.maxDuration > 0 && openFor >= l.maxDuration {
+		return "", fmt.Sprintf("session closed as it reached the maximum duration of %d minutes", int(l.maxDuration.Minutes()))
+	}
+	if l.idleTimeout > 0 && idleFor >= l.idleTimeout {
+		return "", fmt.Sprintf("session closed after %d minutes of inactivity", int(l.idleTimeout.Minutes()))
+	}
+	if l.maxDuration > 0 && !l.durationWarned && openFor >= l.maxDuration-warningBefore {
+		l.durationWarned = true
+		return fmt.Sprintf("session will be closed in %s as it reaches the maximum duration of %d minutes",
+			(l.maxDuration - openFor).Round(time.Second), int(l.maxDuration.Minutes())), ""
+	}
+	if l.idleTimeout > 0 && !l.idleWarned && idleFor >= l.idleTimeout-warningBefore {
+		l.idleWarned = true
+		return fmt.Sprintf("session will be closed in %s due to inactivity, press any key to keep it open",
+			(l.idleTimeout - idleFor).Round(time.Second)), ""
+	}
+	return "", ""
+}
+
+func (l *terminalSessionLimits) detail(sessionId string) *TerminalSessionDetail {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return &TerminalSessionDetail{
+		SessionId:       sessionId,
+		ClusterId:       l.clusterId,
+		Namespace:       l.request.Namespace,
+		PodName:         l.request.PodName,
+		ContainerName:   l.request.ContainerName,
+		Shell:           l.request.Shell,
+		UserId:          l.request.UserId,
+		AppId:           l.request.AppId,
+		EnvironmentId:   l.request.EnvironmentId,
+		StartedOn:       l.startedOn,
+		LastActivityOn:  l.lastActivityOn,
+		IdleTimeoutMins: int(l.idleTimeout.Minutes()),
+		MaxDurationMins: int(l.maxDuration.Minutes()),
+	}
+}
+
+// EnforceSessionLimits runs periodically and warns the users of sessions which are close to their idle timeout or max
+// duration, and closes the sessions which exceeded them
+func (impl *TerminalSessionHandlerImpl) EnforceSessionLimits() {
+	warningBefore := time.Duration(impl.sessionConfig.TimeoutWarningMins) * time.Minute
+	now := time.Now()
+	warnings := make(map[string]string)
+	exceeded := make(map[string]string)
+	terminalSessions.Lock.RLock()
+	for sessionId, session := range terminalSessions.Sessions {
+		if session.sockJSSession == nil || session.limits == nil {
+			continue
+		}
+		warning, reason := session.limits.check(now, warningBefore)
+		if len(reason) > 0 {
+			exceeded[sessionId] = reason
+		} else if len(warning) > 0 {
+			warnings[sessionId] = warning
+		}
+	}
+	terminalSessions.Lock.RUnlock()
+	for sessionId, warning := range warnings {
+		if err := terminalSessions.Get(sessionId).Toast(warning); err != nil {
+			impl.logger.Errorw("error in sending terminal session limit warning", "err", err, "sessionId", sessionId)
+		}
+	}
+	for sessionId, reason := range exceeded {
+		impl.logger.Infow("closing terminal session which exceeded its limits", "sessionId", sessionId, "reason", reason)
+		impl.terminateSession(sessionId, reason)
+	}
+}
+
+func (impl *TerminalSessionHandlerImpl) GetActiveSessions() []*TerminalSessionDetail {
+	terminalSessions.Lock.RLock()
+	sessions := make([]*TerminalSessionDetail, 0, len(terminalSessions.Sessions))
+	for sessionId, session := range terminalSessions.Sessions {
+		if session.sockJSSession == nil || session.limits == nil {
+			continue
+		}
+		sessions = append(sessions, session.limits.detail(sessionId))
+	}
+	terminalSessions.Lock.RUnlock()
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].StartedOn.Before(sessions[j].StartedOn)
+	})
+	return sessions
+}
+
+func (impl *TerminalSessionHandlerImpl) TerminateSession(sessionId string, reason string) error {
+	session := terminalSessions.Get(sessionId)
+	if session.sockJSSession == nil {
+		return &util.ApiError{
+			HttpStatusCode:  http.StatusNotFound,
+			InternalMessage: fmt.Sprintf("terminal session %s is not active", sessionId),
+			UserMessage:     "terminal session not found",
+		}
+	}
+	if len(reason) == 0 {
+		reason = "session closed by admin"
+	}
+	impl.terminateSession(sessionId, reason)
+	return nil
+}
+
+func (impl *TerminalSessionHandlerImpl) RegisterListener(listener TerminalSessionListener) {
+	impl.listenersLock.Lock()
+	defer impl.listenersLock.Unlock()
+	impl.listeners = append(impl.listeners, listener)
+}
+
+// terminateSession closes the connection of the session and lets the listeners remove the pod or ephemeral container
+// which was opened for it
+func (impl *TerminalSessionHandlerImpl) terminateSession(sessionId string, reason string) {
+	session := terminalSessions.Get(sessionId)
+	if session.sockJSSession == nil {
+		return
+	}
+	if err := session.Toast(reason); err != nil {
+		impl.logger.Errorw("error in sending terminal session close reason", "err", err, "sessionId", sessionId)
+	}
+	terminalSessions.Close(sessionId, 2, reason)
+	if session.limits == nil {
+		return
+	}
+	impl.listenersLock.RLock()
+	listeners := impl.listeners
+	impl.listenersLock.RUnlock()
+	for _, listener := range listeners {
+		go listener.OnTerminalSessionTerminated(session.limits.request, session.limits.clusterId)
+	}
+}
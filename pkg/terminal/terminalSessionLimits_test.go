@@ -0,0 +1,55 @@
+package terminal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTerminalSessionLimitsCheck(t *testing.T) {
+	warningBefore := 2 * time.Minute
+	tests := []struct {
+		name            string
+		idleTimeoutMins int
+		maxDurationMins int
+		idleFor         time.Duration
+		openFor         time.Duration
+		wantWarning     bool
+		wantExceeded    bool
+	}{
+		{name: "no limits", idleFor: 10 * time.Hour, openFor: 10 * time.Hour},
+		{name: "active session", idleTimeoutMins: 10, maxDurationMins: 60, idleFor: time.Minute, openFor: 30 * time.Minute},
+		{name: "idle warning", idleTimeoutMins: 10, idleFor: 9 * time.Minute, openFor: 9 * time.Minute, wantWarning: true},
+		{name: "idle timeout", idleTimeoutMins: 10, idleFor: 10 * time.Minute, openFor: 10 * time.Minute, wantExceeded: true},
+		{name: "duration warning", maxDurationMins: 60, idleFor: time.Second, openFor: 59 * time.Minute, wantWarning: true},
+		{name: "max duration", idleTimeoutMins: 10, maxDurationMins: 60, idleFor: time.Second, openFor: 61 * time.Minute, wantExceeded: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			limits := newTerminalSessionLimits(&TerminalSessionRequest{}, 1, tt.idleTimeoutMins, tt.maxDurationMins)
+			limits.startedOn = now.Add(-tt.openFor)
+			limits.lastActivityOn = now.Add(-tt.idleFor)
+			warning, exceeded := limits.check(now, warningBefore)
+			if (len(warning) > 0) != tt.wantWarning || (len(exceeded) > 0) != tt.wantExceeded {
+				t.Errorf("check() warning = %q, exceeded = %q, want warning %v, exceeded %v", warning, exceeded, tt.wantWarning, tt.wantExceeded)
+			}
+		})
+	}
+}
+
+func TestTerminalSessionLimitsWarnOnce(t *testing.T) {
+	now := time.Now()
+	limits := newTerminalSessionLimits(&TerminalSessionRequest{}, 1, 10, 0)
+	limits.lastActivityOn = now.Add(-9 * time.Minute)
+	if warning, _ := limits.check(now, 2*time.Minute); len(warning) == 0 {
+		t.Fatalf("idle session should be warned")
+	}
+	if warning, _ := limits.check(now, 2*time.Minute); len(warning) > 0 {
+		t.Errorf("idle session should be warned once, got %q", warning)
+	}
+	limits.recordActivity()
+	limits.lastActivityOn = now.Add(-9 * time.Minute)
+	if warning, _ := limits.check(now, 2*time.Minute); len(warning) == 0 {
+		t.Errorf("session should be warned again after new activity")
+	}
+}
@@ -0,0 +1,118 @@
+package terminal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	asciicastVersion       = 2
+	asciicastEventInput    = "i"
+	asciicastEventOutput   = "o"
+	asciicastEventResize   = "r"
+	asciicastDefaultWidth  = 80
+	asciicastDefaultHeight = 24
+)
+
+// asciicastHeader is the first line of an asciicast v2 file, https://docs.asciinema.org/manual/asciicast/v2/
+type asciicastHeader struct {
+	Version   int               `json:"version"`
+	Width     uint16            `json:"width"`
+	Height    uint16            `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// TerminalSessionRecorder writes the stdin, stdout and resize events of a terminal session to a local asciicast file,
+// events are written as [seconds since start, event type, data]
+type TerminalSessionRecorder struct {
+	recordingId int
+	filePath    string
+	file        *os.File
+	startedOn   time.Time
+	lock        sync.Mutex
+	closed      bool
+	err         error
+}
+
+func NewTerminalSessionRecorder(filePath string, title string, shell string) (*TerminalSessionRecorder, error) {
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	startedOn := time.Now()
+	header, err := json.Marshal(&asciicastHeader{
+		Version:   asciicastVersion,
+		Width:     asciicastDefaultWidth,
+		Height:    asciicastDefaultHeight,
+		Timestamp: startedOn.Unix(),
+		Title:     title,
+		Env:       map[string]string{"SHELL": shell, "TERM": "xterm"},
+	})
+	if err == nil {
+		_, err = file.Write(append(header, '\n'))
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(filePath)
+		return nil, err
+	}
+	return &TerminalSessionRecorder{
+		filePath:  filePath,
+		file:      file,
+		startedOn: startedOn,
+	}, nil
+}
+
+func (r *TerminalSessionRecorder) RecordInput(data string) {
+	r.record(asciicastEventInput, data)
+}
+
+func (r *TerminalSessionRecorder) RecordOutput(data string) {
+	r.record(asciicastEventOutput, data)
+}
+
+func (r *TerminalSessionRecorder) RecordResize(cols, rows uint16) {
+	r.record(asciicastEventResize, fmt.Sprintf("%dx%d", cols, rows))
+}
+
+// record is a no-op on a nil recorder so that sessions of clusters without recording need no checks, the first write
+// error is kept and returned by Close instead of breaking the session
+func (r *TerminalSessionRecorder) record(eventType string, data string) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.closed || r.err != nil {
+		return
+	}
+	event, err := json.Marshal([]interface{}{time.Since(r.startedOn).Seconds(), eventType, data})
+	if err == nil {
+		_, err = r.file.Write(append(event, '\n'))
+	}
+	r.err = err
+}
+
+// Close stops recording and returns the size of the cast file
+func (r *TerminalSessionRecorder) Close() (int64, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.closed {
+		return 0, fmt.Errorf("terminal session recorder is already closed")
+	}
+	r.closed = true
+	fileInfo, statErr := r.file.Stat()
+	closeErr := r.file.Close()
+	if r.err != nil {
+		return 0, r.err
+	}
+	if statErr != nil {
+		return 0, statErr
+	}
+	return fileInfo.Size(), closeErr
+}
@@ -0,0 +1,66 @@
+package terminal
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTerminalSessionRecorder(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "session.cast")
+	recorder, err := NewTerminalSessionRecorder(filePath, "user 2 on default/pod/main", "bash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder.RecordResize(120, 40)
+	recorder.RecordInput("ls\r")
+	recorder.RecordOutput("file.txt\r\n")
+	size, err := recorder.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder.RecordOutput("after close")
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	fileInfo, _ := file.Stat()
+	if fileInfo.Size() != size {
+		t.Errorf("Close() size = %d, want %d", size, fileInfo.Size())
+	}
+	scanner := bufio.NewScanner(file)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want header and 3 events: %v", len(lines), lines)
+	}
+	header := &asciicastHeader{}
+	if err = json.Unmarshal([]byte(lines[0]), header); err != nil {
+		t.Fatal(err)
+	}
+	if header.Version != 2 || header.Env["SHELL"] != "bash" || header.Title != "user 2 on default/pod/main" {
+		t.Errorf("unexpected header %s", lines[0])
+	}
+	wantEvents := [][2]string{{"r", "120x40"}, {"i", "ls\r"}, {"o", "file.txt\r\n"}}
+	for i, want := range wantEvents {
+		var event []interface{}
+		if err = json.Unmarshal([]byte(lines[i+1]), &event); err != nil {
+			t.Fatal(err)
+		}
+		if len(event) != 3 || event[1] != want[0] || event[2] != want[1] {
+			t.Errorf("event %d = %v, want %v", i, event, want)
+		}
+		if _, ok := event[0].(float64); !ok {
+			t.Errorf("event %d has no time offset: %v", i, event)
+		}
+	}
+
+	var nilRecorder *TerminalSessionRecorder
+	nilRecorder.RecordOutput("no recording")
+}
@@ -364,6 +364,20 @@ func (impl UserAuthServiceImpl) HandleDexCallback(w http.ResponseWriter, r *http
 	http.Redirect(w, r, "/", http.StatusFound)
 }
 
+const scimPathPrefix = "/orchestrator/scim/v2"
+
+// ScimTokenHandler sets the bearer token which identity providers send in the Authorization header of scim requests
+// as the token header, so that the auth middleware verifies it like the token of any other api request
+func ScimTokenHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader := r.Header.Get("Authorization")
+		if strings.HasPrefix(r.URL.Path, scimPathPrefix) && len(r.Header.Get("token")) == 0 && strings.HasPrefix(authHeader, "Bearer ") {
+			r.Header.Set("token", strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer ")))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func WhitelistChecker(url string) bool {
 	urls := []string{
 		"/health",
@@ -25,6 +25,7 @@ import (
 	"github.com/devtron-labs/devtron/api/bean"
 	"github.com/devtron-labs/devtron/internal/constants"
 	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	bean2 "github.com/devtron-labs/devtron/pkg/user/bean"
 	casbin2 "github.com/devtron-labs/devtron/pkg/user/casbin"
@@ -35,6 +36,7 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -46,16 +48,16 @@ const (
 )
 
 type UserService interface {
-	CreateUser(userInfo *bean.UserInfo, token string, managerAuth func(resource, token string, object string) bool) ([]*bean.UserInfo, error)
+	CreateUser(ctx context.Context, userInfo *bean.UserInfo, token string, managerAuth func(resource, token string, object string) bool) ([]*bean.UserInfo, error)
 	SelfRegisterUserIfNotExists(userInfo *bean.UserInfo) ([]*bean.UserInfo, error)
-	UpdateUser(userInfo *bean.UserInfo, token string, managerAuth func(resource, token string, object string) bool) (*bean.UserInfo, bool, bool, []string, error)
+	UpdateUser(ctx context.Context, userInfo *bean.UserInfo, token string, managerAuth func(resource, token string, object string) bool) (*bean.UserInfo, bool, bool, []string, error)
 	GetById(id int32) (*bean.UserInfo, error)
 	GetAll() ([]bean.UserInfo, error)
 	GetAllDetailedUsers() ([]bean.UserInfo, error)
 	GetEmailFromToken(token string) (string, error)
 	GetLoggedInUser(r *http.Request) (int32, error)
 	GetByIds(ids []int32) ([]bean.UserInfo, error)
-	DeleteUser(userInfo *bean.UserInfo) (bool, error)
+	DeleteUser(ctx context.Context, userInfo *bean.UserInfo) (bool, error)
 	CheckUserRoles(id int32) ([]string, error)
 	SyncOrchestratorToCasbin() (bool, error)
 	GetUserByToken(context context.Context, token string) (int32, string, error)
@@ -79,13 +81,17 @@ type UserServiceImpl struct {
 	sessionManager2     *middleware.SessionManager
 	userCommonService   UserCommonService
 	userAuditService    UserAuditService
+	auditLogService     auditLog.AuditLogService
+	// tokenScopeProvider is used to leave out super-admin from the roles of scoped api-tokens
+	tokenScopeProvider casbin2.ApiTokenScopeProvider
 }
 
 func NewUserServiceImpl(userAuthRepository repository2.UserAuthRepository,
 	logger *zap.SugaredLogger,
 	userRepository repository2.UserRepository,
 	userGroupRepository repository2.RoleGroupRepository,
-	sessionManager2 *middleware.SessionManager, userCommonService UserCommonService, userAuditService UserAuditService) *UserServiceImpl {
+	sessionManager2 *middleware.SessionManager, userCommonService UserCommonService, userAuditService UserAuditService,
+	auditLogService auditLog.AuditLogService, apiTokenScopeProvider casbin2.ApiTokenScopeProvider) *UserServiceImpl {
 	serviceImpl := &UserServiceImpl{
 		userReqState:        make(map[int32]bool),
 		userAuthRepository:  userAuthRepository,
@@ -95,6 +101,8 @@ func NewUserServiceImpl(userAuthRepository repository2.UserAuthRepository,
 		sessionManager2:     sessionManager2,
 		userCommonService:   userCommonService,
 		userAuditService:    userAuditService,
+		auditLogService:     auditLogService,
+		tokenScopeProvider:  apiTokenScopeProvider,
 	}
 	cStore = sessions.NewCookieStore(randKey())
 	return serviceImpl
@@ -266,7 +274,7 @@ func (impl *UserServiceImpl) saveUser(userInfo *bean.UserInfo, emailId string) (
 	return userInfo, nil
 }
 
-func (impl *UserServiceImpl) CreateUser(userInfo *bean.UserInfo, token string, managerAuth func(resource, token string, object string) bool) ([]*bean.UserInfo, error) {
+func (impl *UserServiceImpl) CreateUser(ctx context.Context, userInfo *bean.UserInfo, token string, managerAuth func(resource, token string, object string) bool) ([]*bean.UserInfo, error) {
 
 	var pass []string
 	var userResponse []*bean.UserInfo
@@ -280,7 +288,7 @@ func (impl *UserServiceImpl) CreateUser(userInfo *bean.UserInfo, token string, m
 
 		//if found, update it with new roles
 		if dbUser != nil && dbUser.Id > 0 {
-			userInfo, err = impl.updateUserIfExists(userInfo, dbUser, emailId, token, managerAuth)
+			userInfo, err = impl.updateUserIfExists(ctx, userInfo, dbUser, emailId, token, managerAuth)
 			if err != nil {
 				impl.logger.Errorw("error while create user if exists in db", "error", err)
 				return nil, err
@@ -299,13 +307,26 @@ func (impl *UserServiceImpl) CreateUser(userInfo *bean.UserInfo, token string, m
 		pass = append(pass, emailId)
 		userInfo.EmailId = emailId
 		userInfo.Exist = dbUser.Active
-		userResponse = append(userResponse, &bean.UserInfo{Id: userInfo.Id, EmailId: emailId, Groups: userInfo.Groups, RoleFilters: userInfo.RoleFilters, SuperAdmin: userInfo.SuperAdmin})
+		action := auditLog.ActionCreate
+		if dbUser != nil && dbUser.Id > 0 {
+			action = auditLog.ActionUpdate
+		}
+		createdUser := &bean.UserInfo{Id: userInfo.Id, EmailId: emailId, Groups: userInfo.Groups, RoleFilters: userInfo.RoleFilters, SuperAdmin: userInfo.SuperAdmin}
+		userResponse = append(userResponse, createdUser)
+		impl.auditLogService.RecordEvent(ctx, &auditLog.AuditEventRequest{
+			Action:       action,
+			ResourceType: auditLog.ResourceTypeUser,
+			ResourceId:   strconv.Itoa(int(createdUser.Id)),
+			ResourceName: emailId,
+			UserId:       userInfo.UserId,
+			After:        createdUser,
+		})
 	}
 
 	return userResponse, nil
 }
 
-func (impl *UserServiceImpl) updateUserIfExists(userInfo *bean.UserInfo, dbUser *repository2.UserModel, emailId string,
+func (impl *UserServiceImpl) updateUserIfExists(ctx context.Context, userInfo *bean.UserInfo, dbUser *repository2.UserModel, emailId string,
 	token string, managerAuth func(resource, token, object string) bool) (*bean.UserInfo, error) {
 	updateUserInfo, err := impl.GetById(dbUser.Id)
 	if err != nil && err != pg.ErrNoRows {
@@ -321,7 +342,7 @@ func (impl *UserServiceImpl) updateUserIfExists(userInfo *bean.UserInfo, dbUser
 	updateUserInfo.Groups = impl.mergeGroups(updateUserInfo.Groups, userInfo.Groups)
 	updateUserInfo.UserId = userInfo.UserId
 	updateUserInfo.EmailId = emailId // override case sensitivity
-	updateUserInfo, _, _, _, err = impl.UpdateUser(updateUserInfo, token, managerAuth)
+	updateUserInfo, _, _, _, err = impl.UpdateUser(ctx, updateUserInfo, token, managerAuth)
 	if err != nil {
 		impl.logger.Errorw("error while update user", "error", err)
 		return nil, err
@@ -657,7 +678,7 @@ func (impl *UserServiceImpl) mergeGroups(oldGroups []string, newGroups []string)
 	return groups
 }
 
-func (impl *UserServiceImpl) UpdateUser(userInfo *bean.UserInfo, token string, managerAuth func(resource, token string, object string) bool) (*bean.UserInfo, bool, bool, []string, error) {
+func (impl *UserServiceImpl) UpdateUser(ctx context.Context, userInfo *bean.UserInfo, token string, managerAuth func(resource, token string, object string) bool) (*bean.UserInfo, bool, bool, []string, error) {
 	//checking if request for same user is being processed
 	isLocked := impl.getUserReqLockStateById(userInfo.Id)
 	if isLocked {
@@ -681,6 +702,11 @@ func (impl *UserServiceImpl) UpdateUser(userInfo *bean.UserInfo, token string, m
 			}
 		}()
 	}
+	// state before update for audit log
+	existingUserInfo, err := impl.GetById(userInfo.Id)
+	if err != nil {
+		return nil, false, false, nil, err
+	}
 	//validating if action user is not admin and trying to update user who has super admin polices, return 403
 	isUserSuperAdmin, err := impl.IsSuperAdmin(int(userInfo.Id))
 	if err != nil {
@@ -865,6 +891,15 @@ func (impl *UserServiceImpl) UpdateUser(userInfo *bean.UserInfo, token string, m
 	}
 	//loading policy for syncing orchestrator to casbin with newly added policies
 	casbin2.LoadPolicy()
+	impl.auditLogService.RecordEvent(ctx, &auditLog.AuditEventRequest{
+		Action:       auditLog.ActionUpdate,
+		ResourceType: auditLog.ResourceTypeUser,
+		ResourceId:   strconv.Itoa(int(userInfo.Id)),
+		ResourceName: userInfo.EmailId,
+		UserId:       userInfo.UserId,
+		Before:       existingUserInfo,
+		After:        &bean.UserInfo{Id: userInfo.Id, EmailId: userInfo.EmailId, Groups: userInfo.Groups, RoleFilters: userInfo.RoleFilters, SuperAdmin: userInfo.SuperAdmin},
+	})
 	return userInfo, rolesChanged, groupsModified, restrictedGroups, nil
 }
 
@@ -1237,7 +1272,7 @@ func (impl *UserServiceImpl) GetByIds(ids []int32) ([]bean.UserInfo, error) {
 	return beans, nil
 }
 
-func (impl *UserServiceImpl) DeleteUser(bean *bean.UserInfo) (bool, error) {
+func (impl *UserServiceImpl) DeleteUser(ctx context.Context, bean *bean.UserInfo) (bool, error) {
 
 	dbConnection := impl.roleGroupRepository.GetConnection()
 	tx, err := dbConnection.Begin()
@@ -1252,6 +1287,11 @@ func (impl *UserServiceImpl) DeleteUser(bean *bean.UserInfo) (bool, error) {
 		impl.logger.Errorw("error while fetching user from db", "error", err)
 		return false, err
 	}
+	// state before delete for audit log
+	existingUserInfo, err := impl.GetById(bean.Id)
+	if err != nil {
+		return false, err
+	}
 	urm, err := impl.userAuthRepository.GetUserRoleMappingByUserId(bean.Id)
 	if err != nil {
 		impl.logger.Errorw("error while fetching user from db", "error", err)
@@ -1287,6 +1327,14 @@ func (impl *UserServiceImpl) DeleteUser(bean *bean.UserInfo) (bool, error) {
 			impl.logger.Warnw("unable to delete role:", "user", model.EmailId, "role", item)
 		}
 	}
+	impl.auditLogService.RecordEvent(ctx, &auditLog.AuditEventRequest{
+		Action:       auditLog.ActionDelete,
+		ResourceType: auditLog.ResourceTypeUser,
+		ResourceId:   strconv.Itoa(int(model.Id)),
+		ResourceName: model.EmailId,
+		UserId:       bean.UserId,
+		Before:       existingUserInfo,
+	})
 
 	return true, nil
 }
@@ -1303,7 +1351,18 @@ func (impl *UserServiceImpl) CheckUserRoles(id int32) ([]string, error) {
 		impl.logger.Errorw("No Roles Found for user", "id", model.Id)
 		return nil, err
 	}
-
+	// a scoped api-token is never super-admin, it only gets what its scopes allow on top of the roles of its user
+	if impl.tokenScopeProvider != nil {
+		if _, scoped := impl.tokenScopeProvider.GetApiTokenScopes(model.EmailId); scoped {
+			scopedGroups := make([]string, 0, len(groups))
+			for _, group := range groups {
+				if group != bean.SUPERADMIN {
+					scopedGroups = append(scopedGroups, group)
+				}
+			}
+			groups = scopedGroups
+		}
+	}
 	return groups, nil
 }
 
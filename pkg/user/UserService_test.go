@@ -187,6 +187,8 @@ func TestUserUpdateService(t *testing.T) {
 			roleGroupRepositoryMocked,
 			nil,
 			nil,
+			nil,
+			nil,
 			nil)
 
 		token := ""
@@ -0,0 +1,47 @@
+package casbin
+
+import "strings"
+
+// ApiTokenScope restricts what an api-token can do on top of the casbin roles of its user.
+// Resource and actions are matched exactly (or "*"), object is matched using MatchKeyByPart
+type ApiTokenScope struct {
+	Resource string   `json:"resource"`
+	Actions  []string `json:"actions"`
+	Object   string   `json:"object"`
+}
+
+// ApiTokenScopeProvider returns the scopes of the api-token user identified by emailId.
+// scoped is false for non api-token users and for api-tokens created without scopes, in which case the user roles apply as is
+type ApiTokenScopeProvider interface {
+	GetApiTokenScopes(emailId string) (scopes []*ApiTokenScope, scoped bool)
+}
+
+func (scope *ApiTokenScope) Allows(resource string, action string, resourceItem string) bool {
+	if scope.Resource != "*" && !strings.EqualFold(scope.Resource, resource) {
+		return false
+	}
+	actionAllowed := false
+	for _, scopeAction := range scope.Actions {
+		if scopeAction == "*" || strings.EqualFold(scopeAction, action) {
+			actionAllowed = true
+			break
+		}
+	}
+	if !actionAllowed {
+		return false
+	}
+	object := scope.Object
+	if len(object) == 0 {
+		object = "*"
+	}
+	return MatchKeyByPart(strings.ToLower(resourceItem), strings.ToLower(object))
+}
+
+func isAllowedByApiTokenScopes(scopes []*ApiTokenScope, resource string, action string, resourceItem string) bool {
+	for _, scope := range scopes {
+		if scope.Allows(resource, action, resourceItem) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+package casbin
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestApiTokenScopes(t *testing.T) {
+	scopes := []*ApiTokenScope{
+		{Resource: ResourceApplications, Actions: []string{ActionTrigger}, Object: "payments/*"},
+		{Resource: ResourceEnvironment, Actions: []string{ActionTrigger}, Object: "staging/checkout"},
+		{Resource: "*", Actions: []string{ActionGet}},
+	}
+	assert.True(t, isAllowedByApiTokenScopes(scopes, ResourceApplications, ActionTrigger, "payments/checkout"))
+	assert.True(t, isAllowedByApiTokenScopes(scopes, ResourceEnvironment, ActionTrigger, "Staging/Checkout"))
+	assert.True(t, isAllowedByApiTokenScopes(scopes, ResourceCluster, ActionGet, "prod-cluster"))
+	assert.False(t, isAllowedByApiTokenScopes(scopes, ResourceEnvironment, ActionTrigger, "prod/checkout"))
+	assert.False(t, isAllowedByApiTokenScopes(scopes, ResourceApplications, ActionUpdate, "payments/checkout"))
+	assert.False(t, isAllowedByApiTokenScopes(nil, ResourceApplications, ActionGet, "payments/checkout"))
+}
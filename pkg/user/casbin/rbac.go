@@ -50,12 +50,14 @@ type Enforcer interface {
 func NewEnforcerImpl(
 	enforcer *casbin.SyncedEnforcer,
 	sessionManager *middleware.SessionManager,
-	logger *zap.SugaredLogger) *EnforcerImpl {
+	logger *zap.SugaredLogger,
+	apiTokenScopeProvider ApiTokenScopeProvider) *EnforcerImpl {
 	lock := make(map[string]*CacheData)
 	batchRequestLock := make(map[string]*sync.Mutex)
 	enforcerConfig := getConfig()
 	enf := &EnforcerImpl{lockCacheData: lock, enforcerRWLock: &sync.RWMutex{}, batchRequestLock: batchRequestLock, enforcerConfig: enforcerConfig,
-		Cache: getEnforcerCache(logger, enforcerConfig), SyncedEnforcer: enforcer, logger: logger, SessionManager: sessionManager}
+		Cache: getEnforcerCache(logger, enforcerConfig), SyncedEnforcer: enforcer, logger: logger, SessionManager: sessionManager,
+		apiTokenScopeProvider: apiTokenScopeProvider}
 	setEnforcerImpl(enf)
 	return enf
 }
@@ -103,9 +105,10 @@ type EnforcerImpl struct {
 	*cache.Cache
 	*casbin.SyncedEnforcer
 	*middleware.SessionManager
-	logger         *zap.SugaredLogger
-	enforcerConfig *EnforcerConfig
-	enforcerRWLock *sync.RWMutex
+	logger                *zap.SugaredLogger
+	enforcerConfig        *EnforcerConfig
+	enforcerRWLock        *sync.RWMutex
+	apiTokenScopeProvider ApiTokenScopeProvider
 }
 
 // Enforce is a wrapper around casbin.Enforce to additionally enforce a default role and a custom
@@ -115,6 +118,9 @@ func (e *EnforcerImpl) Enforce(token string, resource string, action string, res
 }
 
 func (e *EnforcerImpl) EnforceByEmail(emailId string, resource string, action string, resourceItem string) bool {
+	if scopes, scoped := e.apiTokenScopeProvider.GetApiTokenScopes(emailId); scoped && !isAllowedByApiTokenScopes(scopes, resource, action, resourceItem) {
+		return false
+	}
 	allowed := e.enforceByEmail(emailId, resource, action, resourceItem)
 	return allowed
 }
@@ -220,6 +226,14 @@ func (e *EnforcerImpl) EnforceByEmailInBatch(emailId string, resource string, ac
 		"action", action, "totalElapsedTime", totalTimeGap, "maxTimegap", maxTimegap, "minTimegap",
 		minTimegap, "avgTimegap", avgTimegap, "size", len(vals), "batchSize", batchSize, "cached", e.Cache != nil && dataCached)
 
+	if scopes, scoped := e.apiTokenScopeProvider.GetApiTokenScopes(emailId); scoped {
+		// result is shared with the cache, scopes are applied on a copy
+		scopedResult := make(map[string]bool, len(result))
+		for resourceItem, allowed := range result {
+			scopedResult[resourceItem] = allowed && isAllowedByApiTokenScopes(scopes, resource, action, resourceItem)
+		}
+		return scopedResult
+	}
 	return result
 }
 
@@ -52,33 +52,33 @@ func (mr *MockUserServiceMockRecorder) CheckUserRoles(id interface{}) *gomock.Ca
 }
 
 // CreateUser mocks base method.
-func (m *MockUserService) CreateUser(userInfo *bean.UserInfo, token string, managerAuth func(string, string, string) bool) ([]*bean.UserInfo, error) {
+func (m *MockUserService) CreateUser(ctx context.Context, userInfo *bean.UserInfo, token string, managerAuth func(string, string, string) bool) ([]*bean.UserInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", userInfo, token, managerAuth)
+	ret := m.ctrl.Call(m, "CreateUser", ctx, userInfo, token, managerAuth)
 	ret0, _ := ret[0].([]*bean.UserInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateUser indicates an expected call of CreateUser.
-func (mr *MockUserServiceMockRecorder) CreateUser(userInfo, token, managerAuth interface{}) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) CreateUser(ctx, userInfo, token, managerAuth interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserService)(nil).CreateUser), userInfo, token, managerAuth)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserService)(nil).CreateUser), ctx, userInfo, token, managerAuth)
 }
 
 // DeleteUser mocks base method.
-func (m *MockUserService) DeleteUser(userInfo *bean.UserInfo) (bool, error) {
+func (m *MockUserService) DeleteUser(ctx context.Context, userInfo *bean.UserInfo) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", userInfo)
+	ret := m.ctrl.Call(m, "DeleteUser", ctx, userInfo)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeleteUser indicates an expected call of DeleteUser.
-func (mr *MockUserServiceMockRecorder) DeleteUser(userInfo interface{}) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) DeleteUser(ctx, userInfo interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserService)(nil).DeleteUser), userInfo)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserService)(nil).DeleteUser), ctx, userInfo)
 }
 
 // GetAll mocks base method.
@@ -289,9 +289,9 @@ func (mr *MockUserServiceMockRecorder) UpdateTriggerPolicyForTerminalAccess() *g
 }
 
 // UpdateUser mocks base method.
-func (m *MockUserService) UpdateUser(userInfo *bean.UserInfo, token string, managerAuth func(string, string, string) bool) (*bean.UserInfo, bool, bool, []string, error) {
+func (m *MockUserService) UpdateUser(ctx context.Context, userInfo *bean.UserInfo, token string, managerAuth func(string, string, string) bool) (*bean.UserInfo, bool, bool, []string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateUser", userInfo, token, managerAuth)
+	ret := m.ctrl.Call(m, "UpdateUser", ctx, userInfo, token, managerAuth)
 	ret0, _ := ret[0].(*bean.UserInfo)
 	ret1, _ := ret[1].(bool)
 	ret2, _ := ret[2].(bool)
@@ -301,9 +301,9 @@ func (m *MockUserService) UpdateUser(userInfo *bean.UserInfo, token string, mana
 }
 
 // UpdateUser indicates an expected call of UpdateUser.
-func (mr *MockUserServiceMockRecorder) UpdateUser(userInfo, token, managerAuth interface{}) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) UpdateUser(ctx, userInfo, token, managerAuth interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserService)(nil).UpdateUser), userInfo, token, managerAuth)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserService)(nil).UpdateUser), ctx, userInfo, token, managerAuth)
 }
 
 // UserExists mocks base method.
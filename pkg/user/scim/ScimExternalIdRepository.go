@@ -0,0 +1,103 @@
+package scim
+
+import (
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	repository2 "github.com/devtron-labs/devtron/pkg/user/repository"
+	"github.com/go-pg/pg"
+)
+
+// ScimExternalIdMapping keeps the identifier assigned by the identity provider for a provisioned user or role group
+type ScimExternalIdMapping struct {
+	tableName    struct{} `sql:"scim_external_id_mapping" pg:",discard_unknown_columns"`
+	Id           int      `sql:"id,pk"`
+	ResourceType string   `sql:"resource_type,notnull"`
+	ResourceId   int32    `sql:"resource_id,notnull"`
+	ExternalId   string   `sql:"external_id,notnull"`
+	sql.AuditLog
+}
+
+type ScimExternalIdRepository interface {
+	Save(mapping *ScimExternalIdMapping) error
+	Update(mapping *ScimExternalIdMapping) error
+	FindByResource(resourceType string, resourceId int32) (*ScimExternalIdMapping, error)
+	FindAllByResourceType(resourceType string) ([]*ScimExternalIdMapping, error)
+	FindAllByResourceIds(resourceType string, resourceIds []int32) ([]*ScimExternalIdMapping, error)
+	FindUsers(condition string, params []interface{}, offset int, limit int) ([]repository2.UserModel, int, error)
+	DeleteByResource(resourceType string, resourceId int32) error
+}
+
+type ScimExternalIdRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewScimExternalIdRepositoryImpl(dbConnection *pg.DB) *ScimExternalIdRepositoryImpl {
+	return &ScimExternalIdRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl ScimExternalIdRepositoryImpl) Save(mapping *ScimExternalIdMapping) error {
+	return impl.dbConnection.Insert(mapping)
+}
+
+func (impl ScimExternalIdRepositoryImpl) Update(mapping *ScimExternalIdMapping) error {
+	return impl.dbConnection.Update(mapping)
+}
+
+func (impl ScimExternalIdRepositoryImpl) FindByResource(resourceType string, resourceId int32) (*ScimExternalIdMapping, error) {
+	mapping := &ScimExternalIdMapping{}
+	err := impl.dbConnection.Model(mapping).
+		Where("resource_type = ?", resourceType).
+		Where("resource_id = ?", resourceId).
+		Limit(1).
+		Select()
+	return mapping, err
+}
+
+func (impl ScimExternalIdRepositoryImpl) FindAllByResourceType(resourceType string) ([]*ScimExternalIdMapping, error) {
+	var mappings []*ScimExternalIdMapping
+	err := impl.dbConnection.Model(&mappings).
+		Where("resource_type = ?", resourceType).
+		Select()
+	return mappings, err
+}
+
+func (impl ScimExternalIdRepositoryImpl) FindAllByResourceIds(resourceType string, resourceIds []int32) ([]*ScimExternalIdMapping, error) {
+	var mappings []*ScimExternalIdMapping
+	if len(resourceIds) == 0 {
+		return mappings, nil
+	}
+	err := impl.dbConnection.Model(&mappings).
+		Where("resource_type = ?", resourceType).
+		Where("resource_id in (?)", pg.In(resourceIds)).
+		Select()
+	return mappings, err
+}
+
+// FindUsers returns a page of the users matching the condition along with the count of all matching users. the
+// condition is on the users table u and the external id mapping m of the user, api token users are left out and
+// deactivated users are included as identity providers reconcile them by filtering on active
+func (impl ScimExternalIdRepositoryImpl) FindUsers(condition string, params []interface{}, offset int, limit int) ([]repository2.UserModel, int, error) {
+	from := " FROM users u LEFT JOIN scim_external_id_mapping m ON m.resource_type = ? AND m.resource_id = u.id" +
+		" WHERE (u.user_type IS NULL OR u.user_type != ?) AND " + condition
+	params = append([]interface{}{ResourceTypeUser, bean.USER_TYPE_API_TOKEN}, params...)
+	var total int
+	_, err := impl.dbConnection.QueryOne(pg.Scan(&total), "SELECT COUNT(*)"+from, params...)
+	if err != nil {
+		return nil, 0, err
+	}
+	var users []repository2.UserModel
+	if total == 0 || limit == 0 {
+		return users, total, nil
+	}
+	query := "SELECT u.*" + from + " ORDER BY u.id OFFSET ? LIMIT ?"
+	_, err = impl.dbConnection.Query(&users, query, append(params, offset, limit)...)
+	return users, total, err
+}
+
+func (impl ScimExternalIdRepositoryImpl) DeleteByResource(resourceType string, resourceId int32) error {
+	_, err := impl.dbConnection.Model(&ScimExternalIdMapping{}).
+		Where("resource_type = ?", resourceType).
+		Where("resource_id = ?", resourceId).
+		Delete()
+	return err
+}
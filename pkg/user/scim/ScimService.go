@@ -0,0 +1,894 @@
+package scim
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/pkg/clusterTerminalAccess"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/pkg/user"
+	casbin2 "github.com/devtron-labs/devtron/pkg/user/casbin"
+	repository2 "github.com/devtron-labs/devtron/pkg/user/repository"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type ScimService interface {
+	ListUsers(request *ListRequest) (*ListResponse, error)
+	GetUser(id int32) (*User, error)
+	CreateUser(ctx context.Context, scimUser *User, actionUserId int32, token string, managerAuth func(resource, token, object string) bool) (*User, error)
+	ReplaceUser(ctx context.Context, id int32, scimUser *User, actionUserId int32, token string, managerAuth func(resource, token, object string) bool) (*User, error)
+	PatchUser(ctx context.Context, id int32, patch *PatchRequest, actionUserId int32, token string, managerAuth func(resource, token, object string) bool) (*User, error)
+	DeleteUser(ctx context.Context, id int32, actionUserId int32) error
+
+	ListGroups(request *ListRequest) (*ListResponse, error)
+	GetGroup(id int32, excludeMembers bool) (*Group, error)
+	CreateGroup(ctx context.Context, scimGroup *Group, actionUserId int32, token string, managerAuth func(resource, token, object string) bool) (*Group, error)
+	ReplaceGroup(ctx context.Context, id int32, scimGroup *Group, actionUserId int32, token string, managerAuth func(resource, token, object string) bool) (*Group, error)
+	PatchGroup(ctx context.Context, id int32, patch *PatchRequest, actionUserId int32, token string, managerAuth func(resource, token, object string) bool) (*Group, error)
+	DeleteGroup(id int32, actionUserId int32) error
+
+	GetServiceProviderConfig() *ServiceProviderConfig
+}
+
+type ScimServiceImpl struct {
+	logger                    *zap.SugaredLogger
+	userService               user.UserService
+	roleGroupService          user.RoleGroupService
+	userRepository            repository2.UserRepository
+	roleGroupRepository       repository2.RoleGroupRepository
+	scimExternalIdRepository  ScimExternalIdRepository
+	userTerminalAccessService clusterTerminalAccess.UserTerminalAccessService
+}
+
+func NewScimServiceImpl(logger *zap.SugaredLogger, userService user.UserService, roleGroupService user.RoleGroupService,
+	userRepository repository2.UserRepository, roleGroupRepository repository2.RoleGroupRepository,
+	scimExternalIdRepository ScimExternalIdRepository,
+	userTerminalAccessService clusterTerminalAccess.UserTerminalAccessService) *ScimServiceImpl {
+	return &ScimServiceImpl{
+		logger:                    logger,
+		userService:               userService,
+		roleGroupService:          roleGroupService,
+		userRepository:            userRepository,
+		roleGroupRepository:       roleGroupRepository,
+		scimExternalIdRepository:  scimExternalIdRepository,
+		userTerminalAccessService: userTerminalAccessService,
+	}
+}
+
+// userColumns are the sql expressions of the filterable user attributes for the query of FindUsers
+var userColumns = map[string]string{
+	"id":           "CAST(u.id AS TEXT)",
+	"username":     "u.email_id",
+	"displayname":  "u.email_id",
+	"emails":       "u.email_id",
+	"emails.value": "u.email_id",
+	"externalid":   "m.external_id",
+	"active":       "CAST(u.active AS TEXT)",
+}
+
+func (impl *ScimServiceImpl) ListUsers(request *ListRequest) (*ListResponse, error) {
+	filter, err := ParseFilter(request.Filter)
+	if err != nil {
+		return nil, err
+	}
+	condition, params := "TRUE", []interface{}(nil)
+	if filter != nil {
+		condition, params = filter.ToSql(userColumns)
+	}
+	offset := request.StartIndex - 1
+	if offset < 0 {
+		offset = 0
+	}
+	users, total, err := impl.scimExternalIdRepository.FindUsers(condition, params, offset, pageSize(request))
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching users for scim listing", "filter", request.Filter, "err", err)
+		return nil, err
+	}
+	userIds := make([]int32, 0, len(users))
+	for _, model := range users {
+		userIds = append(userIds, model.Id)
+	}
+	mappings, err := impl.scimExternalIdRepository.FindAllByResourceIds(ResourceTypeUser, userIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching scim external ids of users", "userIds", userIds, "err", err)
+		return nil, err
+	}
+	externalIds := make(map[int32]string, len(mappings))
+	for _, mapping := range mappings {
+		externalIds[mapping.ResourceId] = mapping.ExternalId
+	}
+	resources := make([]*User, 0, len(users))
+	for _, model := range users {
+		scimUser, err := impl.buildScimUser(&model, externalIds[model.Id])
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, scimUser)
+	}
+	return buildListResponse(total, request, len(resources), resources), nil
+}
+
+func (impl *ScimServiceImpl) GetUser(id int32) (*User, error) {
+	model, err := impl.getUserModel(id)
+	if err != nil {
+		return nil, err
+	}
+	externalId, err := impl.getExternalId(ResourceTypeUser, id)
+	if err != nil {
+		return nil, err
+	}
+	return impl.buildScimUser(model, externalId)
+}
+
+func (impl *ScimServiceImpl) CreateUser(ctx context.Context, scimUser *User, actionUserId int32, token string, managerAuth func(resource, token, object string) bool) (*User, error) {
+	emailId := strings.TrimSpace(scimUser.UserName)
+	if len(emailId) == 0 {
+		return nil, NewError(http.StatusBadRequest, ScimTypeInvalidValue, "userName is required")
+	}
+	if strings.Contains(emailId, ",") {
+		return nil, NewError(http.StatusBadRequest, ScimTypeInvalidValue, "userName '%s' must not contain comma", emailId)
+	}
+	existing, err := impl.userRepository.FetchActiveOrDeletedUserByEmail(emailId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching user by email", "emailId", emailId, "err", err)
+		return nil, err
+	}
+	if existing != nil && existing.Id > 0 && existing.Active {
+		return nil, NewError(http.StatusConflict, ScimTypeUniqueness, "user with userName '%s' already exists", emailId)
+	}
+	groupNames, err := impl.getGroupNamesForRefs(scimUser.Groups)
+	if err != nil {
+		return nil, err
+	}
+	userInfo := &bean.UserInfo{
+		EmailId: emailId,
+		UserId:  actionUserId,
+		Groups:  groupNames,
+	}
+	createdUsers, err := impl.userService.CreateUser(ctx, userInfo, token, managerAuth)
+	if err != nil {
+		impl.logger.Errorw("error while creating user from scim request", "emailId", emailId, "err", err)
+		return nil, err
+	}
+	if len(createdUsers) != 1 {
+		return nil, fmt.Errorf("expected one user to be created for '%s', found %d", emailId, len(createdUsers))
+	}
+	userId := createdUsers[0].Id
+	err = impl.saveExternalId(ResourceTypeUser, userId, scimUser.ExternalId, actionUserId)
+	if err != nil {
+		return nil, err
+	}
+	if !scimUser.IsActive() {
+		err = impl.deactivateUser(ctx, userId, actionUserId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return impl.GetUser(userId)
+}
+
+func (impl *ScimServiceImpl) ReplaceUser(ctx context.Context, id int32, scimUser *User, actionUserId int32, token string, managerAuth func(resource, token, object string) bool) (*User, error) {
+	model, err := impl.getUserModel(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(scimUser.UserName) > 0 && !strings.EqualFold(strings.TrimSpace(scimUser.UserName), model.EmailId) {
+		return nil, NewError(http.StatusBadRequest, ScimTypeMutability, "userName of user %d cannot be changed", id)
+	}
+	err = impl.saveExternalId(ResourceTypeUser, id, scimUser.ExternalId, actionUserId)
+	if err != nil {
+		return nil, err
+	}
+	if model.Active && !scimUser.IsActive() {
+		err = impl.deactivateUser(ctx, id, actionUserId)
+	} else if !model.Active && scimUser.IsActive() {
+		err = impl.reactivateUser(ctx, model, actionUserId, token, managerAuth)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return impl.GetUser(id)
+}
+
+func (impl *ScimServiceImpl) PatchUser(ctx context.Context, id int32, patch *PatchRequest, actionUserId int32, token string, managerAuth func(resource, token, object string) bool) (*User, error) {
+	scimUser, err := impl.GetUser(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, operation := range patch.Operations {
+		err = applyUserPatchOperation(scimUser, operation)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return impl.ReplaceUser(ctx, id, scimUser, actionUserId, token, managerAuth)
+}
+
+func (impl *ScimServiceImpl) DeleteUser(ctx context.Context, id int32, actionUserId int32) error {
+	model, err := impl.getUserModel(id)
+	if err != nil {
+		return err
+	}
+	if model.Active {
+		err = impl.deactivateUser(ctx, id, actionUserId)
+		if err != nil {
+			return err
+		}
+	}
+	err = impl.scimExternalIdRepository.DeleteByResource(ResourceTypeUser, id)
+	if err != nil {
+		impl.logger.Errorw("error while deleting scim external id of user", "userId", id, "err", err)
+		return err
+	}
+	return nil
+}
+
+// deactivateUser inactivates the user, which also removes its casbin policies, and disconnects all of its terminal sessions
+func (impl *ScimServiceImpl) deactivateUser(ctx context.Context, id int32, actionUserId int32) error {
+	impl.logger.Infow("deactivating user on scim request", "userId", id, "actionUserId", actionUserId)
+	success, err := impl.userService.DeleteUser(ctx, &bean.UserInfo{Id: id, UserId: actionUserId})
+	if err != nil {
+		impl.logger.Errorw("error while deactivating user", "userId", id, "err", err)
+		return err
+	}
+	if !success {
+		return fmt.Errorf("could not deactivate user %d", id)
+	}
+	impl.userTerminalAccessService.DisconnectAllSessionsForUser(ctx, id)
+	return nil
+}
+
+func (impl *ScimServiceImpl) reactivateUser(ctx context.Context, model *repository2.UserModel, actionUserId int32, token string, managerAuth func(resource, token, object string) bool) error {
+	impl.logger.Infow("reactivating user on scim request", "userId", model.Id, "actionUserId", actionUserId)
+	_, err := impl.userService.CreateUser(ctx, &bean.UserInfo{EmailId: model.EmailId, UserId: actionUserId}, token, managerAuth)
+	if err != nil {
+		impl.logger.Errorw("error while reactivating user", "userId", model.Id, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *ScimServiceImpl) ListGroups(request *ListRequest) (*ListResponse, error) {
+	filter, err := ParseFilter(request.Filter)
+	if err != nil {
+		return nil, err
+	}
+	roleGroups, err := impl.roleGroupRepository.GetAllRoleGroup()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching role groups for scim listing", "err", err)
+		return nil, err
+	}
+	externalIds, err := impl.getExternalIdsByResourceType(ResourceTypeGroup)
+	if err != nil {
+		return nil, err
+	}
+	excludeMembers := isAttributeExcluded(request.ExcludedAttributes, "members")
+	// members are only resolved when the filter or the response needs them
+	withMembers := !excludeMembers || strings.Contains(strings.ToLower(request.Filter), "members")
+	sort.Slice(roleGroups, func(i, j int) bool { return roleGroups[i].Id < roleGroups[j].Id })
+	var matched []*Group
+	for _, roleGroup := range roleGroups {
+		scimGroup, err := impl.buildScimGroup(roleGroup, externalIds[roleGroup.Id], !withMembers)
+		if err != nil {
+			return nil, err
+		}
+		if filter == nil || filter.Matches(groupAttributes(scimGroup)) {
+			if excludeMembers {
+				scimGroup.Members = nil
+			}
+			matched = append(matched, scimGroup)
+		}
+	}
+	start, end := pageBounds(len(matched), request)
+	resources := make([]*Group, 0)
+	resources = append(resources, matched[start:end]...)
+	return buildListResponse(len(matched), request, len(resources), resources), nil
+}
+
+func (impl *ScimServiceImpl) GetGroup(id int32, excludeMembers bool) (*Group, error) {
+	roleGroup, err := impl.getRoleGroupModel(id)
+	if err != nil {
+		return nil, err
+	}
+	externalId, err := impl.getExternalId(ResourceTypeGroup, id)
+	if err != nil {
+		return nil, err
+	}
+	return impl.buildScimGroup(roleGroup, externalId, excludeMembers)
+}
+
+func (impl *ScimServiceImpl) CreateGroup(ctx context.Context, scimGroup *Group, actionUserId int32, token string, managerAuth func(resource, token, object string) bool) (*Group, error) {
+	name := strings.TrimSpace(scimGroup.DisplayName)
+	if len(name) == 0 {
+		return nil, NewError(http.StatusBadRequest, ScimTypeInvalidValue, "displayName is required")
+	}
+	existing, err := impl.roleGroupRepository.GetRoleGroupByName(name)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching role group by name", "name", name, "err", err)
+		return nil, err
+	}
+	if existing != nil && existing.Id > 0 {
+		return nil, NewError(http.StatusConflict, ScimTypeUniqueness, "group with displayName '%s' already exists", name)
+	}
+	roleGroup, err := impl.roleGroupService.CreateRoleGroup(&bean.RoleGroup{
+		Name:        name,
+		RoleFilters: make([]bean.RoleFilter, 0),
+		UserId:      actionUserId,
+	})
+	if err != nil {
+		impl.logger.Errorw("error while creating role group from scim request", "name", name, "err", err)
+		return nil, err
+	}
+	err = impl.saveExternalId(ResourceTypeGroup, roleGroup.Id, scimGroup.ExternalId, actionUserId)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range scimGroup.Members {
+		err = impl.updateGroupMembership(ctx, member.Value, roleGroup.Name, true, actionUserId, token, managerAuth)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return impl.GetGroup(roleGroup.Id, false)
+}
+
+func (impl *ScimServiceImpl) ReplaceGroup(ctx context.Context, id int32, scimGroup *Group, actionUserId int32, token string, managerAuth func(resource, token, object string) bool) (*Group, error) {
+	current, err := impl.GetGroup(id, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(scimGroup.DisplayName) > 0 && strings.TrimSpace(scimGroup.DisplayName) != current.DisplayName {
+		return nil, NewError(http.StatusBadRequest, ScimTypeMutability, "displayName of group %d cannot be changed", id)
+	}
+	err = impl.saveExternalId(ResourceTypeGroup, id, scimGroup.ExternalId, actionUserId)
+	if err != nil {
+		return nil, err
+	}
+	currentMembers := make(map[string]bool)
+	for _, member := range current.Members {
+		currentMembers[member.Value] = true
+	}
+	requestedMembers := make(map[string]bool)
+	for _, member := range scimGroup.Members {
+		requestedMembers[member.Value] = true
+		if !currentMembers[member.Value] {
+			err = impl.updateGroupMembership(ctx, member.Value, current.DisplayName, true, actionUserId, token, managerAuth)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, member := range current.Members {
+		if !requestedMembers[member.Value] {
+			err = impl.updateGroupMembership(ctx, member.Value, current.DisplayName, false, actionUserId, token, managerAuth)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return impl.GetGroup(id, false)
+}
+
+func (impl *ScimServiceImpl) PatchGroup(ctx context.Context, id int32, patch *PatchRequest, actionUserId int32, token string, managerAuth func(resource, token, object string) bool) (*Group, error) {
+	scimGroup, err := impl.GetGroup(id, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, operation := range patch.Operations {
+		err = applyGroupPatchOperation(scimGroup, operation)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return impl.ReplaceGroup(ctx, id, scimGroup, actionUserId, token, managerAuth)
+}
+
+func (impl *ScimServiceImpl) DeleteGroup(id int32, actionUserId int32) error {
+	_, err := impl.getRoleGroupModel(id)
+	if err != nil {
+		return err
+	}
+	_, err = impl.roleGroupService.DeleteRoleGroup(&bean.RoleGroup{Id: id, UserId: actionUserId})
+	if err != nil {
+		impl.logger.Errorw("error while deleting role group on scim request", "roleGroupId", id, "err", err)
+		return err
+	}
+	err = impl.scimExternalIdRepository.DeleteByResource(ResourceTypeGroup, id)
+	if err != nil {
+		impl.logger.Errorw("error while deleting scim external id of group", "roleGroupId", id, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *ScimServiceImpl) GetServiceProviderConfig() *ServiceProviderConfig {
+	return &ServiceProviderConfig{
+		Schemas: []string{ServiceProviderConfigSchema},
+		Patch:   Supported{Supported: true},
+		Bulk:    BulkSupported{Supported: false},
+		Filter:  FilterSupported{Supported: true, MaxResults: MaxPageSize},
+		AuthenticationSchemes: []AuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "OAuth Bearer Token",
+			Description: "Authentication using a Devtron API token with super-admin access",
+		}},
+	}
+}
+
+// updateGroupMembership adds or removes the role group for the user identified by SCIM member value
+func (impl *ScimServiceImpl) updateGroupMembership(ctx context.Context, memberValue string, groupName string, add bool, actionUserId int32, token string, managerAuth func(resource, token, object string) bool) error {
+	userId, err := parseResourceId(memberValue)
+	if err != nil {
+		return NewError(http.StatusBadRequest, ScimTypeInvalidValue, "invalid member value '%s'", memberValue)
+	}
+	userInfo, err := impl.userService.GetById(userId)
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return NewError(http.StatusBadRequest, ScimTypeInvalidValue, "member %s not found", memberValue)
+		}
+		impl.logger.Errorw("error while fetching user for group membership update", "userId", userId, "err", err)
+		return err
+	}
+	var groups []string
+	alreadyMember := false
+	for _, group := range userInfo.Groups {
+		if group == groupName {
+			alreadyMember = true
+			if !add {
+				continue
+			}
+		}
+		groups = append(groups, group)
+	}
+	if add == alreadyMember {
+		return nil
+	}
+	if add {
+		groups = append(groups, groupName)
+	}
+	userInfo.Groups = groups
+	userInfo.UserId = actionUserId
+	_, _, _, _, err = impl.userService.UpdateUser(ctx, userInfo, token, managerAuth)
+	if err != nil {
+		impl.logger.Errorw("error while updating group membership of user", "userId", userId, "group", groupName, "add", add, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *ScimServiceImpl) buildScimUser(model *repository2.UserModel, externalId string) (*User, error) {
+	active := model.Active
+	scimUser := &User{
+		Schemas:     []string{UserSchema},
+		Id:          strconv.Itoa(int(model.Id)),
+		ExternalId:  externalId,
+		UserName:    model.EmailId,
+		DisplayName: model.EmailId,
+		Emails:      []Email{{Value: model.EmailId, Type: "work", Primary: true}},
+		Active:      &active,
+		Groups:      make([]GroupRef, 0),
+		Meta:        &Meta{ResourceType: ResourceTypeUser, Location: fmt.Sprintf("Users/%d", model.Id)},
+	}
+	if !active {
+		return scimUser, nil
+	}
+	userInfo, err := impl.userService.GetById(model.Id)
+	if err != nil {
+		impl.logger.Errorw("error while fetching user details", "userId", model.Id, "err", err)
+		return nil, err
+	}
+	if len(userInfo.Groups) == 0 {
+		return scimUser, nil
+	}
+	roleGroups, err := impl.roleGroupRepository.GetRoleGroupListByNames(userInfo.Groups)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching role groups of user", "userId", model.Id, "err", err)
+		return nil, err
+	}
+	for _, roleGroup := range roleGroups {
+		scimUser.Groups = append(scimUser.Groups, GroupRef{Value: strconv.Itoa(int(roleGroup.Id)), Display: roleGroup.Name})
+	}
+	return scimUser, nil
+}
+
+func (impl *ScimServiceImpl) buildScimGroup(roleGroup *repository2.RoleGroup, externalId string, excludeMembers bool) (*Group, error) {
+	scimGroup := &Group{
+		Schemas:     []string{GroupSchema},
+		Id:          strconv.Itoa(int(roleGroup.Id)),
+		ExternalId:  externalId,
+		DisplayName: roleGroup.Name,
+		Members:     make([]Member, 0),
+		Meta:        &Meta{ResourceType: ResourceTypeGroup, Location: fmt.Sprintf("Groups/%d", roleGroup.Id)},
+	}
+	if excludeMembers {
+		return scimGroup, nil
+	}
+	emailIds, err := casbin2.GetUserByRole(roleGroup.CasbinName)
+	if err != nil {
+		impl.logger.Errorw("error while fetching users of role group", "casbinName", roleGroup.CasbinName, "err", err)
+		return nil, err
+	}
+	for _, emailId := range emailIds {
+		userInfo, err := impl.userRepository.FetchActiveUserByEmail(emailId)
+		if err != nil || userInfo.Id == 0 {
+			impl.logger.Warnw("member of role group not found as active user", "emailId", emailId, "err", err)
+			continue
+		}
+		scimGroup.Members = append(scimGroup.Members, Member{Value: strconv.Itoa(int(userInfo.Id)), Display: userInfo.EmailId})
+	}
+	sort.Slice(scimGroup.Members, func(i, j int) bool { return scimGroup.Members[i].Value < scimGroup.Members[j].Value })
+	return scimGroup, nil
+}
+
+func (impl *ScimServiceImpl) getUserModel(id int32) (*repository2.UserModel, error) {
+	model, err := impl.userRepository.GetByIdIncludeDeleted(id)
+	if err == pg.ErrNoRows || (err == nil && model.UserType == bean.USER_TYPE_API_TOKEN) {
+		return nil, NewNotFoundError(ResourceTypeUser, strconv.Itoa(int(id)))
+	} else if err != nil {
+		impl.logger.Errorw("error while fetching user", "userId", id, "err", err)
+		return nil, err
+	}
+	return model, nil
+}
+
+func (impl *ScimServiceImpl) getRoleGroupModel(id int32) (*repository2.RoleGroup, error) {
+	roleGroup, err := impl.roleGroupRepository.GetRoleGroupById(id)
+	if err == pg.ErrNoRows {
+		return nil, NewNotFoundError(ResourceTypeGroup, strconv.Itoa(int(id)))
+	} else if err != nil {
+		impl.logger.Errorw("error while fetching role group", "roleGroupId", id, "err", err)
+		return nil, err
+	}
+	return roleGroup, nil
+}
+
+func (impl *ScimServiceImpl) getGroupNamesForRefs(refs []GroupRef) ([]string, error) {
+	var groupNames []string
+	for _, ref := range refs {
+		id, err := parseResourceId(ref.Value)
+		if err != nil {
+			return nil, NewError(http.StatusBadRequest, ScimTypeInvalidValue, "invalid group value '%s'", ref.Value)
+		}
+		roleGroup, err := impl.getRoleGroupModel(id)
+		if err != nil {
+			return nil, err
+		}
+		groupNames = append(groupNames, roleGroup.Name)
+	}
+	return groupNames, nil
+}
+
+func (impl *ScimServiceImpl) getExternalId(resourceType string, resourceId int32) (string, error) {
+	mapping, err := impl.scimExternalIdRepository.FindByResource(resourceType, resourceId)
+	if err == pg.ErrNoRows {
+		return "", nil
+	} else if err != nil {
+		impl.logger.Errorw("error while fetching scim external id", "resourceType", resourceType, "resourceId", resourceId, "err", err)
+		return "", err
+	}
+	return mapping.ExternalId, nil
+}
+
+func (impl *ScimServiceImpl) getExternalIdsByResourceType(resourceType string) (map[int32]string, error) {
+	mappings, err := impl.scimExternalIdRepository.FindAllByResourceType(resourceType)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching scim external ids", "resourceType", resourceType, "err", err)
+		return nil, err
+	}
+	externalIds := make(map[int32]string, len(mappings))
+	for _, mapping := range mappings {
+		externalIds[mapping.ResourceId] = mapping.ExternalId
+	}
+	return externalIds, nil
+}
+
+func (impl *ScimServiceImpl) saveExternalId(resourceType string, resourceId int32, externalId string, actionUserId int32) error {
+	if len(externalId) == 0 {
+		return nil
+	}
+	mapping, err := impl.scimExternalIdRepository.FindByResource(resourceType, resourceId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching scim external id", "resourceType", resourceType, "resourceId", resourceId, "err", err)
+		return err
+	}
+	if err == nil && mapping.ExternalId == externalId {
+		return nil
+	}
+	if err == pg.ErrNoRows {
+		mapping = &ScimExternalIdMapping{
+			ResourceType: resourceType,
+			ResourceId:   resourceId,
+			AuditLog:     sql.AuditLog{CreatedOn: time.Now(), CreatedBy: actionUserId},
+		}
+	}
+	mapping.ExternalId = externalId
+	mapping.UpdatedOn = time.Now()
+	mapping.UpdatedBy = actionUserId
+	if mapping.Id > 0 {
+		err = impl.scimExternalIdRepository.Update(mapping)
+	} else {
+		err = impl.scimExternalIdRepository.Save(mapping)
+	}
+	if err != nil {
+		impl.logger.Errorw("error while saving scim external id", "resourceType", resourceType, "resourceId", resourceId, "err", err)
+		return err
+	}
+	return nil
+}
+
+func applyUserPatchOperation(scimUser *User, operation *PatchOperation) error {
+	op := strings.ToLower(operation.Op)
+	if op != PatchOpAdd && op != PatchOpReplace && op != PatchOpRemove {
+		return NewError(http.StatusBadRequest, ScimTypeInvalidValue, "unsupported patch operation '%s'", operation.Op)
+	}
+	if len(operation.Path) == 0 {
+		values, ok := operation.Value.(map[string]interface{})
+		if !ok {
+			return NewError(http.StatusBadRequest, ScimTypeInvalidValue, "patch operation without path must have an object value")
+		}
+		for path, value := range values {
+			err := applyUserAttribute(scimUser, op, path, value)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return applyUserAttribute(scimUser, op, operation.Path, operation.Value)
+}
+
+func applyUserAttribute(scimUser *User, op string, path string, value interface{}) error {
+	switch strings.ToLower(path) {
+	case "active":
+		if op == PatchOpRemove {
+			return NewError(http.StatusBadRequest, ScimTypeMutability, "active cannot be removed")
+		}
+		active, err := parseBoolValue(value)
+		if err != nil {
+			return err
+		}
+		scimUser.Active = &active
+	case "externalid":
+		if op == PatchOpRemove {
+			scimUser.ExternalId = ""
+			return nil
+		}
+		externalId, ok := value.(string)
+		if !ok {
+			return NewError(http.StatusBadRequest, ScimTypeInvalidValue, "externalId must be a string")
+		}
+		scimUser.ExternalId = externalId
+	case "username":
+		userName, ok := value.(string)
+		if !ok || op == PatchOpRemove {
+			return NewError(http.StatusBadRequest, ScimTypeMutability, "userName cannot be removed")
+		}
+		scimUser.UserName = userName
+	default:
+		// attributes like name, displayName, title are not stored by devtron and are ignored
+	}
+	return nil
+}
+
+func applyGroupPatchOperation(scimGroup *Group, operation *PatchOperation) error {
+	op := strings.ToLower(operation.Op)
+	path := strings.TrimSpace(operation.Path)
+	if len(path) == 0 {
+		values, ok := operation.Value.(map[string]interface{})
+		if !ok {
+			return NewError(http.StatusBadRequest, ScimTypeInvalidValue, "patch operation without path must have an object value")
+		}
+		for attribute, value := range values {
+			err := applyGroupPatchOperation(scimGroup, &PatchOperation{Op: op, Path: attribute, Value: value})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	lowerPath := strings.ToLower(path)
+	switch {
+	case lowerPath == "displayname":
+		displayName, ok := operation.Value.(string)
+		if !ok || op == PatchOpRemove {
+			return NewError(http.StatusBadRequest, ScimTypeMutability, "displayName cannot be removed")
+		}
+		scimGroup.DisplayName = displayName
+	case lowerPath == "externalid":
+		if op == PatchOpRemove {
+			scimGroup.ExternalId = ""
+			return nil
+		}
+		externalId, ok := operation.Value.(string)
+		if !ok {
+			return NewError(http.StatusBadRequest, ScimTypeInvalidValue, "externalId must be a string")
+		}
+		scimGroup.ExternalId = externalId
+	case lowerPath == "members":
+		members, err := parseMembers(operation.Value)
+		if err != nil {
+			return err
+		}
+		switch op {
+		case PatchOpAdd:
+			scimGroup.Members = mergeMembers(scimGroup.Members, members)
+		case PatchOpReplace:
+			scimGroup.Members = members
+		case PatchOpRemove:
+			if len(members) == 0 {
+				scimGroup.Members = make([]Member, 0)
+			} else {
+				scimGroup.Members = removeMembers(scimGroup.Members, members)
+			}
+		default:
+			return NewError(http.StatusBadRequest, ScimTypeInvalidValue, "unsupported patch operation '%s'", operation.Op)
+		}
+	case strings.HasPrefix(lowerPath, "members["):
+		// value path as sent by most identity providers, e.g. members[value eq "12"]
+		if op != PatchOpRemove {
+			return NewError(http.StatusBadRequest, ScimTypeInvalidPath, "only remove is supported for path '%s'", path)
+		}
+		end := strings.LastIndex(path, "]")
+		if end < 0 {
+			return NewError(http.StatusBadRequest, ScimTypeInvalidPath, "invalid path '%s'", path)
+		}
+		filter, err := ParseFilter(path[len("members["):end])
+		if err != nil {
+			return err
+		}
+		var remaining []Member
+		for _, member := range scimGroup.Members {
+			if !filter.Matches(Attributes{"value": {member.Value}, "display": {member.Display}}) {
+				remaining = append(remaining, member)
+			}
+		}
+		scimGroup.Members = remaining
+	default:
+		return NewError(http.StatusBadRequest, ScimTypeInvalidPath, "unsupported path '%s'", path)
+	}
+	return nil
+}
+
+func parseMembers(value interface{}) ([]Member, error) {
+	if value == nil {
+		return nil, nil
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		items = []interface{}{value}
+	}
+	var members []Member
+	for _, item := range items {
+		memberMap, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, NewError(http.StatusBadRequest, ScimTypeInvalidValue, "invalid member value")
+		}
+		memberValue, ok := memberMap["value"].(string)
+		if !ok || len(memberValue) == 0 {
+			return nil, NewError(http.StatusBadRequest, ScimTypeInvalidValue, "member value is required")
+		}
+		display, _ := memberMap["display"].(string)
+		members = append(members, Member{Value: memberValue, Display: display})
+	}
+	return members, nil
+}
+
+func mergeMembers(existing []Member, added []Member) []Member {
+	present := make(map[string]bool)
+	for _, member := range existing {
+		present[member.Value] = true
+	}
+	for _, member := range added {
+		if !present[member.Value] {
+			existing = append(existing, member)
+			present[member.Value] = true
+		}
+	}
+	return existing
+}
+
+func removeMembers(existing []Member, removed []Member) []Member {
+	toRemove := make(map[string]bool)
+	for _, member := range removed {
+		toRemove[member.Value] = true
+	}
+	var remaining []Member
+	for _, member := range existing {
+		if !toRemove[member.Value] {
+			remaining = append(remaining, member)
+		}
+	}
+	return remaining
+}
+
+func parseBoolValue(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		// some identity providers (e.g. Azure AD) send booleans as strings
+		parsed, err := strconv.ParseBool(strings.ToLower(v))
+		if err != nil {
+			return false, NewError(http.StatusBadRequest, ScimTypeInvalidValue, "invalid boolean value '%s'", v)
+		}
+		return parsed, nil
+	}
+	return false, NewError(http.StatusBadRequest, ScimTypeInvalidValue, "invalid boolean value '%v'", value)
+}
+
+func parseResourceId(value string) (int32, error) {
+	id, err := strconv.ParseInt(strings.TrimSpace(value), 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return int32(id), nil
+}
+
+func groupAttributes(scimGroup *Group) Attributes {
+	attributes := Attributes{
+		"id":          {scimGroup.Id},
+		"displayname": {scimGroup.DisplayName},
+		"externalid":  {scimGroup.ExternalId},
+	}
+	for _, member := range scimGroup.Members {
+		attributes["members"] = append(attributes["members"], member.Value)
+		attributes["members.value"] = append(attributes["members.value"], member.Value)
+	}
+	return attributes
+}
+
+func isAttributeExcluded(excludedAttributes string, attribute string) bool {
+	for _, excluded := range strings.Split(excludedAttributes, ",") {
+		if strings.EqualFold(strings.TrimSpace(excluded), attribute) {
+			return true
+		}
+	}
+	return false
+}
+
+// pageBounds converts the 1-based SCIM startIndex and count into slice bounds
+func pageBounds(total int, request *ListRequest) (int, int) {
+	start := request.StartIndex - 1
+	if start < 0 {
+		start = 0
+	}
+	if start > total {
+		start = total
+	}
+	end := start + pageSize(request)
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+func pageSize(request *ListRequest) int {
+	if request.Count <= 0 {
+		return 0
+	}
+	if request.Count > MaxPageSize {
+		return MaxPageSize
+	}
+	return request.Count
+}
+
+func buildListResponse(total int, request *ListRequest, itemsPerPage int, resources interface{}) *ListResponse {
+	startIndex := request.StartIndex
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	return &ListResponse{
+		Schemas:      []string{ListResponseSchema},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: itemsPerPage,
+		Resources:    resources,
+	}
+}
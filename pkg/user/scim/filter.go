@@ -0,0 +1,315 @@
+package scim
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Attributes holds the filterable attributes of a resource, keyed by lower-cased attribute path (e.g. "username", "emails.value")
+type Attributes map[string][]string
+
+// Filter is a parsed SCIM filter expression (RFC 7644 section 3.4.2.2)
+type Filter interface {
+	Matches(attributes Attributes) bool
+	// ToSql returns a where condition with its params, columns maps the lower-cased attribute paths to text sql
+	// expressions. attributes without a column are treated as empty like they are in Matches
+	ToSql(columns map[string]string) (string, []interface{})
+}
+
+const (
+	filterOpEq = "eq"
+	filterOpNe = "ne"
+	filterOpCo = "co"
+	filterOpSw = "sw"
+	filterOpEw = "ew"
+	filterOpPr = "pr"
+	filterOpGt = "gt"
+	filterOpGe = "ge"
+	filterOpLt = "lt"
+	filterOpLe = "le"
+)
+
+type attributeFilter struct {
+	path  string
+	op    string
+	value string
+}
+
+type logicalFilter struct {
+	and   bool
+	left  Filter
+	right Filter
+}
+
+type notFilter struct {
+	filter Filter
+}
+
+func (f *attributeFilter) Matches(attributes Attributes) bool {
+	values := attributes[f.path]
+	if f.op == filterOpPr {
+		for _, value := range values {
+			if len(value) > 0 {
+				return true
+			}
+		}
+		return false
+	}
+	if f.op == filterOpNe {
+		for _, value := range values {
+			if strings.EqualFold(value, f.value) {
+				return false
+			}
+		}
+		return true
+	}
+	expected := strings.ToLower(f.value)
+	for _, value := range values {
+		actual := strings.ToLower(value)
+		var matched bool
+		switch f.op {
+		case filterOpEq:
+			matched = actual == expected
+		case filterOpCo:
+			matched = strings.Contains(actual, expected)
+		case filterOpSw:
+			matched = strings.HasPrefix(actual, expected)
+		case filterOpEw:
+			matched = strings.HasSuffix(actual, expected)
+		case filterOpGt:
+			matched = actual > expected
+		case filterOpGe:
+			matched = actual >= expected
+		case filterOpLt:
+			matched = actual < expected
+		case filterOpLe:
+			matched = actual <= expected
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *attributeFilter) ToSql(columns map[string]string) (string, []interface{}) {
+	column, ok := columns[f.path]
+	if !ok {
+		if f.op == filterOpNe {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	}
+	if f.op == filterOpPr {
+		return fmt.Sprintf("COALESCE(%s, '') <> ''", column), nil
+	}
+	// byte order comparison like strings are compared in Matches
+	value := fmt.Sprintf(`LOWER(COALESCE(%s, '')) COLLATE "C"`, column)
+	switch f.op {
+	case filterOpNe:
+		return value + " <> LOWER(?)", []interface{}{f.value}
+	case filterOpCo:
+		return fmt.Sprintf("STRPOS(%s, LOWER(?)) > 0", value), []interface{}{f.value}
+	case filterOpSw:
+		return fmt.Sprintf("STRPOS(%s, LOWER(?)) = 1", value), []interface{}{f.value}
+	case filterOpEw:
+		return fmt.Sprintf("RIGHT(%s, LENGTH(?)) = LOWER(?)", value), []interface{}{f.value, f.value}
+	case filterOpGt:
+		return value + " > LOWER(?)", []interface{}{f.value}
+	case filterOpGe:
+		return value + " >= LOWER(?)", []interface{}{f.value}
+	case filterOpLt:
+		return value + " < LOWER(?)", []interface{}{f.value}
+	case filterOpLe:
+		return value + " <= LOWER(?)", []interface{}{f.value}
+	default:
+		return value + " = LOWER(?)", []interface{}{f.value}
+	}
+}
+
+func (f *logicalFilter) Matches(attributes Attributes) bool {
+	if f.and {
+		return f.left.Matches(attributes) && f.right.Matches(attributes)
+	}
+	return f.left.Matches(attributes) || f.right.Matches(attributes)
+}
+
+func (f *logicalFilter) ToSql(columns map[string]string) (string, []interface{}) {
+	left, leftParams := f.left.ToSql(columns)
+	right, rightParams := f.right.ToSql(columns)
+	operator := "OR"
+	if f.and {
+		operator = "AND"
+	}
+	return fmt.Sprintf("(%s %s %s)", left, operator, right), append(leftParams, rightParams...)
+}
+
+func (f *notFilter) Matches(attributes Attributes) bool {
+	return !f.filter.Matches(attributes)
+}
+
+func (f *notFilter) ToSql(columns map[string]string) (string, []interface{}) {
+	condition, params := f.filter.ToSql(columns)
+	return fmt.Sprintf("NOT (%s)", condition), params
+}
+
+// ParseFilter parses a SCIM filter, an empty filter returns nil which callers should treat as match-all
+func ParseFilter(filter string) (Filter, error) {
+	if len(strings.TrimSpace(filter)) == 0 {
+		return nil, nil
+	}
+	tokens, err := tokenizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	parser := &filterParser{tokens: tokens}
+	parsed, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if parser.pos != len(tokens) {
+		return nil, invalidFilterError("unexpected token '%s'", tokens[parser.pos].value)
+	}
+	return parsed, nil
+}
+
+type filterToken struct {
+	value  string
+	quoted bool
+}
+
+func tokenizeFilter(filter string) ([]filterToken, error) {
+	var tokens []filterToken
+	runes := []rune(filter)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, filterToken{value: string(c)})
+			i++
+		case c == '"':
+			var sb strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					sb.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, invalidFilterError("unterminated string in filter")
+			}
+			tokens = append(tokens, filterToken{value: sb.String(), quoted: true})
+		default:
+			start := i
+			for i < len(runes) && runes[i] != ' ' && runes[i] != '\t' && runes[i] != '(' && runes[i] != ')' {
+				i++
+			}
+			tokens = append(tokens, filterToken{value: string(runes[start:i])})
+		}
+	}
+	return tokens, nil
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peekKeyword(keyword string) bool {
+	return p.pos < len(p.tokens) && !p.tokens[p.pos].quoted && strings.EqualFold(p.tokens[p.pos].value, keyword)
+}
+
+func (p *filterParser) parseOr() (Filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("or") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalFilter{and: false, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (Filter, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("and") {
+		p.pos++
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalFilter{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseTerm() (Filter, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, invalidFilterError("unexpected end of filter")
+	}
+	if p.peekKeyword("not") {
+		p.pos++
+		inner, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		return &notFilter{filter: inner}, nil
+	}
+	if p.peekKeyword("(") {
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peekKeyword(")") {
+			return nil, invalidFilterError("missing closing parenthesis")
+		}
+		p.pos++
+		return inner, nil
+	}
+	path := strings.ToLower(p.tokens[p.pos].value)
+	p.pos++
+	if p.pos >= len(p.tokens) {
+		return nil, invalidFilterError("missing operator after '%s'", path)
+	}
+	op := strings.ToLower(p.tokens[p.pos].value)
+	p.pos++
+	switch op {
+	case filterOpPr:
+		return &attributeFilter{path: path, op: op}, nil
+	case filterOpEq, filterOpNe, filterOpCo, filterOpSw, filterOpEw, filterOpGt, filterOpGe, filterOpLt, filterOpLe:
+		if p.pos >= len(p.tokens) {
+			return nil, invalidFilterError("missing value for '%s %s'", path, op)
+		}
+		value := p.tokens[p.pos].value
+		p.pos++
+		return &attributeFilter{path: path, op: op, value: value}, nil
+	default:
+		return nil, invalidFilterError("unsupported operator '%s'", op)
+	}
+}
+
+func invalidFilterError(detail string, args ...interface{}) *Error {
+	return NewError(http.StatusBadRequest, ScimTypeInvalidFilter, detail, args...)
+}
@@ -0,0 +1,106 @@
+package scim
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+	user := Attributes{
+		"username":     {"Jane.Doe@example.com"},
+		"emails.value": {"Jane.Doe@example.com"},
+		"externalid":   {"00u1abc"},
+		"active":       {"true"},
+	}
+	tests := []struct {
+		name    string
+		filter  string
+		matches bool
+		wantErr bool
+	}{
+		{name: "eq is case insensitive", filter: `userName eq "jane.doe@example.com"`, matches: true},
+		{name: "eq mismatch", filter: `userName eq "john@example.com"`, matches: false},
+		{name: "starts with", filter: `emails.value sw "jane"`, matches: true},
+		{name: "contains", filter: `userName co "@example"`, matches: true},
+		{name: "present", filter: `externalId pr`, matches: true},
+		{name: "missing attribute not present", filter: `title pr`, matches: false},
+		{name: "and", filter: `externalId eq "00u1abc" and active eq true`, matches: true},
+		{name: "or with not", filter: `not (active eq true) or userName ew "example.com"`, matches: true},
+		{name: "ne", filter: `userName ne "jane.doe@example.com"`, matches: false},
+		{name: "escaped quote", filter: `userName eq "a\"b"`, matches: false},
+		{name: "unsupported operator", filter: `userName like "x"`, wantErr: true},
+		{name: "unterminated string", filter: `userName eq "x`, wantErr: true},
+		{name: "missing parenthesis", filter: `(userName eq "x"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := ParseFilter(tt.filter)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.matches, filter.Matches(user))
+		})
+	}
+}
+
+func TestParseEmptyFilter(t *testing.T) {
+	filter, err := ParseFilter("  ")
+	assert.Nil(t, err)
+	assert.Nil(t, filter)
+}
+
+func TestFilterToSql(t *testing.T) {
+	tests := []struct {
+		filter    string
+		condition string
+		params    []interface{}
+	}{
+		{filter: `userName eq "Jane"`, condition: `LOWER(COALESCE(u.email_id, '')) COLLATE "C" = LOWER(?)`, params: []interface{}{"Jane"}},
+		{filter: `externalId pr`, condition: `COALESCE(m.external_id, '') <> ''`},
+		{filter: `userName ew "@example.com"`, condition: `RIGHT(LOWER(COALESCE(u.email_id, '')) COLLATE "C", LENGTH(?)) = LOWER(?)`, params: []interface{}{"@example.com", "@example.com"}},
+		{filter: `title pr or not (title eq "x")`, condition: `(FALSE OR NOT (FALSE))`},
+		{filter: `title ne "x" and active eq true`, condition: `(TRUE AND LOWER(COALESCE(CAST(u.active AS TEXT), '')) COLLATE "C" = LOWER(?))`, params: []interface{}{"true"}},
+	}
+	columns := map[string]string{"username": "u.email_id", "externalid": "m.external_id", "active": "CAST(u.active AS TEXT)"}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			filter, err := ParseFilter(tt.filter)
+			assert.Nil(t, err)
+			condition, params := filter.ToSql(columns)
+			assert.Equal(t, tt.condition, condition)
+			assert.Equal(t, tt.params, params)
+		})
+	}
+}
+
+func TestApplyGroupPatchOperation(t *testing.T) {
+	group := &Group{DisplayName: "developers", Members: []Member{{Value: "1"}, {Value: "2"}}}
+	err := applyGroupPatchOperation(group, &PatchOperation{Op: "add", Path: "members", Value: []interface{}{map[string]interface{}{"value": "3"}, map[string]interface{}{"value": "1"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, []Member{{Value: "1"}, {Value: "2"}, {Value: "3"}}, group.Members)
+
+	err = applyGroupPatchOperation(group, &PatchOperation{Op: "Remove", Path: `members[value eq "2"]`})
+	assert.Nil(t, err)
+	assert.Equal(t, []Member{{Value: "1"}, {Value: "3"}}, group.Members)
+
+	err = applyGroupPatchOperation(group, &PatchOperation{Op: "replace", Value: map[string]interface{}{"members": []interface{}{map[string]interface{}{"value": "5"}}}})
+	assert.Nil(t, err)
+	assert.Equal(t, []Member{{Value: "5"}}, group.Members)
+}
+
+func TestApplyUserPatchOperation(t *testing.T) {
+	active := true
+	user := &User{UserName: "jane@example.com", Active: &active}
+	err := applyUserPatchOperation(user, &PatchOperation{Op: "Replace", Value: map[string]interface{}{"active": "False"}})
+	assert.Nil(t, err)
+	assert.False(t, user.IsActive())
+
+	err = applyUserPatchOperation(user, &PatchOperation{Op: "replace", Path: "active", Value: true})
+	assert.Nil(t, err)
+	assert.True(t, user.IsActive())
+
+	err = applyUserPatchOperation(user, &PatchOperation{Op: "move", Path: "active", Value: true})
+	assert.NotNil(t, err)
+}
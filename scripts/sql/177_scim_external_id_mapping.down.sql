@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS public.scim_external_id_mapping;
+
+DROP SEQUENCE IF EXISTS id_seq_scim_external_id_mapping;
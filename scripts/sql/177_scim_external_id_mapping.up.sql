@@ -0,0 +1,15 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_scim_external_id_mapping;
+
+CREATE TABLE IF NOT EXISTS public.scim_external_id_mapping
+(
+    "id"            integer NOT NULL DEFAULT nextval('id_seq_scim_external_id_mapping'::regclass),
+    "resource_type" varchar(50)  NOT NULL,
+    "resource_id"   integer      NOT NULL,
+    "external_id"   varchar(250) NOT NULL,
+    "created_on"    timestamptz  NOT NULL,
+    "created_by"    int4         NOT NULL,
+    "updated_on"    timestamptz  NOT NULL,
+    "updated_by"    int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    UNIQUE ("resource_type", "resource_id")
+);
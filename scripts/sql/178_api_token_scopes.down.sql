@@ -0,0 +1,7 @@
+ALTER TABLE api_token
+    DROP COLUMN IF EXISTS scopes,
+    DROP COLUMN IF EXISTS allowed_cidrs,
+    DROP COLUMN IF EXISTS last_used_at,
+    DROP COLUMN IF EXISTS last_used_by_ip,
+    DROP COLUMN IF EXISTS previous_token,
+    DROP COLUMN IF EXISTS previous_token_expire_at_in_ms;
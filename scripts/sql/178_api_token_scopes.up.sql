@@ -0,0 +1,7 @@
+ALTER TABLE api_token
+    ADD COLUMN IF NOT EXISTS scopes                         text,
+    ADD COLUMN IF NOT EXISTS allowed_cidrs                  text,
+    ADD COLUMN IF NOT EXISTS last_used_at                   timestamptz,
+    ADD COLUMN IF NOT EXISTS last_used_by_ip                varchar(256),
+    ADD COLUMN IF NOT EXISTS previous_token                 text,
+    ADD COLUMN IF NOT EXISTS previous_token_expire_at_in_ms bigint;
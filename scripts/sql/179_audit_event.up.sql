@@ -0,0 +1,26 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_audit_event;
+
+CREATE TABLE IF NOT EXISTS public.audit_event
+(
+    "id"            integer      NOT NULL DEFAULT nextval('id_seq_audit_event'::regclass),
+    "action"        varchar(50)  NOT NULL,
+    "resource_type" varchar(100) NOT NULL,
+    "resource_id"   varchar(250),
+    "resource_name" varchar(250),
+    "user_id"       integer,
+    "email_id"      varchar(250),
+    "api_token_id"  integer,
+    "source_ip"     varchar(256),
+    "http_method"   varchar(10),
+    "url_path"      text,
+    "response_code" integer,
+    "before_state"  text,
+    "after_state"   text,
+    "diff"          text,
+    "created_on"    timestamptz  NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS audit_event_created_on_idx ON public.audit_event (created_on);
+CREATE INDEX IF NOT EXISTS audit_event_resource_idx ON public.audit_event (resource_type, resource_id);
+CREATE INDEX IF NOT EXISTS audit_event_user_id_idx ON public.audit_event (user_id);
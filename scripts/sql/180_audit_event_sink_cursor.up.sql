@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS public.audit_event_sink_cursor
+(
+    "sink_name"     varchar(100) NOT NULL,
+    "last_event_id" integer      NOT NULL,
+    "updated_on"    timestamptz  NOT NULL,
+    PRIMARY KEY ("sink_name")
+);
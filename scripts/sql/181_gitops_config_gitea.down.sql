@@ -0,0 +1 @@
+ALTER TABLE gitops_config DROP COLUMN IF EXISTS gitea_org_id;
@@ -0,0 +1 @@
+ALTER TABLE gitops_config ADD COLUMN IF NOT EXISTS gitea_org_id varchar(250);
@@ -0,0 +1,26 @@
+ALTER TABLE "public"."environment" ADD COLUMN IF NOT EXISTS "gitops_pull_request_enabled" bool NOT NULL DEFAULT false;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_gitops_pull_request;
+
+CREATE TABLE IF NOT EXISTS public.gitops_pull_request
+(
+    "id"                    integer      NOT NULL DEFAULT nextval('id_seq_gitops_pull_request'::regclass),
+    "cd_workflow_runner_id" integer      NOT NULL,
+    "pipeline_override_id"  integer      NOT NULL,
+    "git_repo_name"         varchar(250) NOT NULL,
+    "pull_request_id"       varchar(100) NOT NULL,
+    "pull_request_url"      text,
+    "source_branch"         varchar(250) NOT NULL,
+    "target_branch"         varchar(250) NOT NULL,
+    "status"                varchar(50)  NOT NULL,
+    "merge_commit_hash"     varchar(100),
+    "created_on"            timestamptz  NOT NULL,
+    "created_by"            integer      NOT NULL,
+    "updated_on"            timestamptz  NOT NULL,
+    "updated_by"            integer      NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT gitops_pull_request_cd_workflow_runner_id_fkey FOREIGN KEY ("cd_workflow_runner_id") REFERENCES "public"."cd_workflow_runner" ("id"),
+    CONSTRAINT gitops_pull_request_pipeline_override_id_fkey FOREIGN KEY ("pipeline_override_id") REFERENCES "public"."pipeline_config_override" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS gitops_pull_request_status_idx ON public.gitops_pull_request (status);
@@ -0,0 +1 @@
+ALTER TABLE charts DROP COLUMN IF EXISTS git_repo_directory;
@@ -0,0 +1 @@
+ALTER TABLE charts ADD COLUMN IF NOT EXISTS git_repo_directory VARCHAR(250);
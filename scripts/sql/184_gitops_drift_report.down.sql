@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS public.gitops_drift_report;
+
+DROP SEQUENCE IF EXISTS id_seq_gitops_drift_report;
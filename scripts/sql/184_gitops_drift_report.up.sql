@@ -0,0 +1,30 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_gitops_drift_report;
+
+CREATE TABLE IF NOT EXISTS public.gitops_drift_report
+(
+    "id"                     integer      NOT NULL DEFAULT nextval('id_seq_gitops_drift_report'::regclass),
+    "pipeline_id"            integer      NOT NULL,
+    "app_id"                 integer      NOT NULL,
+    "environment_id"         integer      NOT NULL,
+    "pipeline_override_id"   integer,
+    "devtron_commit_hash"    varchar(100),
+    "git_head_commit_hash"   varchar(100),
+    "git_head_commit_author" varchar(250),
+    "git_head_commit_time"   timestamptz,
+    "git_drift_status"       varchar(50)  NOT NULL,
+    "live_drift_status"      varchar(50)  NOT NULL,
+    "live_sync_revision"     varchar(100),
+    "out_of_sync_resources"  text,
+    "message"                text,
+    "detected_on"            timestamptz  NOT NULL,
+    "created_on"             timestamptz  NOT NULL,
+    "created_by"             integer      NOT NULL,
+    "updated_on"             timestamptz  NOT NULL,
+    "updated_by"             integer      NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT gitops_drift_report_pipeline_id_fkey FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS gitops_drift_report_pipeline_id_idx ON public.gitops_drift_report (pipeline_id);
+
+CREATE INDEX IF NOT EXISTS gitops_drift_report_app_id_idx ON public.gitops_drift_report (app_id);
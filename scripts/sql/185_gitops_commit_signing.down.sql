@@ -0,0 +1,3 @@
+ALTER TABLE gitops_config DROP COLUMN IF EXISTS commit_signing_type;
+ALTER TABLE gitops_config DROP COLUMN IF EXISTS commit_signing_key;
+ALTER TABLE gitops_config DROP COLUMN IF EXISTS commit_signing_key_passphrase;
@@ -0,0 +1,3 @@
+ALTER TABLE gitops_config ADD COLUMN IF NOT EXISTS commit_signing_type varchar(10);
+ALTER TABLE gitops_config ADD COLUMN IF NOT EXISTS commit_signing_key text;
+ALTER TABLE gitops_config ADD COLUMN IF NOT EXISTS commit_signing_key_passphrase text;
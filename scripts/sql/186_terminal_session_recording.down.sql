@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS public.terminal_session_recording;
+
+DROP SEQUENCE IF EXISTS id_seq_terminal_session_recording;
+
+ALTER TABLE cluster DROP COLUMN IF EXISTS terminal_recording_enabled;
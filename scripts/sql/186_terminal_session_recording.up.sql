@@ -0,0 +1,33 @@
+ALTER TABLE cluster ADD COLUMN IF NOT EXISTS terminal_recording_enabled bool NOT NULL DEFAULT false;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_terminal_session_recording;
+
+CREATE TABLE IF NOT EXISTS public.terminal_session_recording
+(
+    "id"             integer      NOT NULL DEFAULT nextval('id_seq_terminal_session_recording'::regclass),
+    "session_id"     varchar(100) NOT NULL,
+    "cluster_id"     integer      NOT NULL,
+    "namespace"      varchar(250) NOT NULL,
+    "pod_name"       varchar(250) NOT NULL,
+    "container_name" varchar(250),
+    "shell"          varchar(50),
+    "user_id"        integer      NOT NULL,
+    "app_id"         integer,
+    "env_id"         integer,
+    "blob_key"       text,
+    "size"           bigint       NOT NULL DEFAULT 0,
+    "status"         varchar(50)  NOT NULL,
+    "message"        text,
+    "started_on"     timestamptz  NOT NULL,
+    "ended_on"       timestamptz,
+    "created_on"     timestamptz  NOT NULL,
+    "created_by"     integer      NOT NULL,
+    "updated_on"     timestamptz  NOT NULL,
+    "updated_by"     integer      NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT terminal_session_recording_cluster_id_fkey FOREIGN KEY ("cluster_id") REFERENCES "public"."cluster" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS terminal_session_recording_session_id_idx ON public.terminal_session_recording (session_id);
+
+CREATE INDEX IF NOT EXISTS terminal_session_recording_cluster_id_idx ON public.terminal_session_recording (cluster_id, started_on);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS public.terminal_command_violation;
+
+DROP SEQUENCE IF EXISTS id_seq_terminal_command_violation;
+
+DROP TABLE IF EXISTS public.terminal_command_policy;
+
+DROP SEQUENCE IF EXISTS id_seq_terminal_command_policy;
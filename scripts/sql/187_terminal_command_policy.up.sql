@@ -0,0 +1,41 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_terminal_command_policy;
+
+CREATE TABLE IF NOT EXISTS public.terminal_command_policy
+(
+    "id"               integer      NOT NULL DEFAULT nextval('id_seq_terminal_command_policy'::regclass),
+    "name"             varchar(250) NOT NULL,
+    "cluster_id"       integer      NOT NULL,
+    "namespace"        varchar(250) NOT NULL DEFAULT '',
+    "allowed_commands" text,
+    "denied_commands"  text,
+    "active"           bool         NOT NULL,
+    "created_on"       timestamptz  NOT NULL,
+    "created_by"       integer      NOT NULL,
+    "updated_on"       timestamptz  NOT NULL,
+    "updated_by"       integer      NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT terminal_command_policy_cluster_id_fkey FOREIGN KEY ("cluster_id") REFERENCES "public"."cluster" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS terminal_command_policy_cluster_namespace_idx ON public.terminal_command_policy (cluster_id, namespace) WHERE active = true;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_terminal_command_violation;
+
+CREATE TABLE IF NOT EXISTS public.terminal_command_violation
+(
+    "id"             integer      NOT NULL DEFAULT nextval('id_seq_terminal_command_violation'::regclass),
+    "policy_id"      integer      NOT NULL,
+    "session_id"     varchar(100) NOT NULL,
+    "cluster_id"     integer      NOT NULL,
+    "namespace"      varchar(250) NOT NULL,
+    "pod_name"       varchar(250) NOT NULL,
+    "container_name" varchar(250),
+    "user_id"        integer      NOT NULL,
+    "command"        text         NOT NULL,
+    "reason"         text         NOT NULL,
+    "blocked_on"     timestamptz  NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT terminal_command_violation_policy_id_fkey FOREIGN KEY ("policy_id") REFERENCES "public"."terminal_command_policy" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS terminal_command_violation_cluster_id_idx ON public.terminal_command_violation (cluster_id, blocked_on);
@@ -0,0 +1,3 @@
+ALTER TABLE cluster DROP COLUMN IF EXISTS terminal_idle_timeout_mins;
+
+ALTER TABLE cluster DROP COLUMN IF EXISTS terminal_max_session_duration_mins;
@@ -0,0 +1,3 @@
+ALTER TABLE cluster ADD COLUMN IF NOT EXISTS terminal_idle_timeout_mins integer NOT NULL DEFAULT 0;
+
+ALTER TABLE cluster ADD COLUMN IF NOT EXISTS terminal_max_session_duration_mins integer NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS public.capacity_workload_snapshot;
+DROP SEQUENCE IF EXISTS id_seq_capacity_workload_snapshot;
+DROP TABLE IF EXISTS public.capacity_node_snapshot;
+DROP SEQUENCE IF EXISTS id_seq_capacity_node_snapshot;
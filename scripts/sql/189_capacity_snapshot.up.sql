@@ -0,0 +1,50 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_capacity_node_snapshot;
+
+CREATE TABLE IF NOT EXISTS public.capacity_node_snapshot
+(
+    "id"                 integer      NOT NULL DEFAULT nextval('id_seq_capacity_node_snapshot'::regclass),
+    "cluster_id"         integer      NOT NULL,
+    "node_name"          varchar(250) NOT NULL,
+    "pod_count"          integer      NOT NULL,
+    "cpu_allocatable"    bigint       NOT NULL,
+    "cpu_requests"       bigint       NOT NULL,
+    "cpu_limits"         bigint       NOT NULL,
+    "cpu_usage"          bigint       NOT NULL,
+    "memory_allocatable" bigint       NOT NULL,
+    "memory_requests"    bigint       NOT NULL,
+    "memory_limits"      bigint       NOT NULL,
+    "memory_usage"       bigint       NOT NULL,
+    "captured_on"        timestamptz  NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS capacity_node_snapshot_cluster_id_idx ON public.capacity_node_snapshot (cluster_id, captured_on);
+CREATE INDEX IF NOT EXISTS capacity_node_snapshot_captured_on_idx ON public.capacity_node_snapshot (captured_on);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_capacity_workload_snapshot;
+
+CREATE TABLE IF NOT EXISTS public.capacity_workload_snapshot
+(
+    "id"               integer      NOT NULL DEFAULT nextval('id_seq_capacity_workload_snapshot'::regclass),
+    "cluster_id"       integer      NOT NULL,
+    "namespace"        varchar(250) NOT NULL,
+    "kind"             varchar(100) NOT NULL,
+    "name"             varchar(250) NOT NULL,
+    "app_id"           integer      NOT NULL DEFAULT 0,
+    "env_id"           integer      NOT NULL DEFAULT 0,
+    "pod_count"        integer      NOT NULL,
+    "container_count"  integer      NOT NULL,
+    "cpu_requests"     bigint       NOT NULL,
+    "cpu_limits"       bigint       NOT NULL,
+    "cpu_usage"        bigint       NOT NULL,
+    "cpu_usage_max"    bigint       NOT NULL,
+    "memory_requests"  bigint       NOT NULL,
+    "memory_limits"    bigint       NOT NULL,
+    "memory_usage"     bigint       NOT NULL,
+    "memory_usage_max" bigint       NOT NULL,
+    "captured_on"      timestamptz  NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS capacity_workload_snapshot_app_env_idx ON public.capacity_workload_snapshot (app_id, env_id, captured_on);
+CREATE INDEX IF NOT EXISTS capacity_workload_snapshot_captured_on_idx ON public.capacity_workload_snapshot (captured_on);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS public.capacity_cost_allocation;
+DROP SEQUENCE IF EXISTS id_seq_capacity_cost_allocation;
+DROP TABLE IF EXISTS public.capacity_node_group_pricing;
+DROP SEQUENCE IF EXISTS id_seq_capacity_node_group_pricing;
@@ -0,0 +1,42 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_capacity_node_group_pricing;
+
+CREATE TABLE IF NOT EXISTS public.capacity_node_group_pricing
+(
+    "id"                   integer          NOT NULL DEFAULT nextval('id_seq_capacity_node_group_pricing'::regclass),
+    "cluster_id"           integer          NOT NULL DEFAULT 0,
+    "node_group"           varchar(250)     NOT NULL DEFAULT '',
+    "cpu_core_hour_price"  double precision NOT NULL,
+    "memory_gb_hour_price" double precision NOT NULL,
+    "gpu_hour_price"       double precision NOT NULL DEFAULT 0,
+    "active"               bool             NOT NULL,
+    "created_on"           timestamptz      NOT NULL,
+    "created_by"           integer          NOT NULL,
+    "updated_on"           timestamptz      NOT NULL,
+    "updated_by"           integer          NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS capacity_node_group_pricing_cluster_node_group_idx ON public.capacity_node_group_pricing (cluster_id, node_group) WHERE active = true;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_capacity_cost_allocation;
+
+CREATE TABLE IF NOT EXISTS public.capacity_cost_allocation
+(
+    "id"            integer          NOT NULL DEFAULT nextval('id_seq_capacity_cost_allocation'::regclass),
+    "cluster_id"    integer          NOT NULL,
+    "namespace"     varchar(250)     NOT NULL,
+    "team_id"       integer          NOT NULL DEFAULT 0,
+    "team_name"     varchar(250)     NOT NULL DEFAULT '',
+    "app_id"        integer          NOT NULL DEFAULT 0,
+    "app_name"      varchar(250)     NOT NULL DEFAULT '',
+    "env_id"        integer          NOT NULL DEFAULT 0,
+    "env_name"      varchar(250)     NOT NULL DEFAULT '',
+    "cpu_cost"      double precision NOT NULL,
+    "memory_cost"   double precision NOT NULL,
+    "gpu_cost"      double precision NOT NULL,
+    "duration_secs" integer          NOT NULL,
+    "captured_on"   timestamptz      NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS capacity_cost_allocation_captured_on_idx ON public.capacity_cost_allocation (captured_on);
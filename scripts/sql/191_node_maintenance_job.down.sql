@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS public.node_maintenance_node;
+DROP SEQUENCE IF EXISTS id_seq_node_maintenance_node;
+DROP TABLE IF EXISTS public.node_maintenance_job;
+DROP SEQUENCE IF EXISTS id_seq_node_maintenance_job;
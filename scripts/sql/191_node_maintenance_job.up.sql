@@ -0,0 +1,43 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_node_maintenance_job;
+
+CREATE TABLE IF NOT EXISTS public.node_maintenance_job
+(
+    "id"                  integer      NOT NULL DEFAULT nextval('id_seq_node_maintenance_job'::regclass),
+    "cluster_id"          integer      NOT NULL,
+    "node_group"          varchar(250) NOT NULL DEFAULT '',
+    "label_selector"      text         NOT NULL DEFAULT '',
+    "drain_options"       text,
+    "max_retries"         integer      NOT NULL,
+    "retry_interval_secs" integer      NOT NULL,
+    "health_timeout_mins" integer      NOT NULL,
+    "status"              varchar(50)  NOT NULL,
+    "message"             text,
+    "started_on"          timestamptz,
+    "finished_on"         timestamptz,
+    "created_on"          timestamptz  NOT NULL,
+    "created_by"          integer      NOT NULL,
+    "updated_on"          timestamptz  NOT NULL,
+    "updated_by"          integer      NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS node_maintenance_job_cluster_id_idx ON public.node_maintenance_job (cluster_id);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_node_maintenance_node;
+
+CREATE TABLE IF NOT EXISTS public.node_maintenance_node
+(
+    "id"          integer      NOT NULL DEFAULT nextval('id_seq_node_maintenance_node'::regclass),
+    "job_id"      integer      NOT NULL,
+    "node_name"   varchar(250) NOT NULL,
+    "status"      varchar(50)  NOT NULL,
+    "attempts"    integer      NOT NULL DEFAULT 0,
+    "pod_count"   integer      NOT NULL DEFAULT 0,
+    "message"     text,
+    "started_on"  timestamptz,
+    "finished_on" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT node_maintenance_node_job_id_fkey FOREIGN KEY ("job_id") REFERENCES "public"."node_maintenance_job" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS node_maintenance_node_job_id_idx ON public.node_maintenance_node (job_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS public.k8s_resource_change;
+DROP SEQUENCE IF EXISTS id_seq_k8s_resource_change;
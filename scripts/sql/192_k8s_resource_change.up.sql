@@ -0,0 +1,21 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_k8s_resource_change;
+
+CREATE TABLE IF NOT EXISTS public.k8s_resource_change
+(
+    "id"               integer      NOT NULL DEFAULT nextval('id_seq_k8s_resource_change'::regclass),
+    "cluster_id"       integer      NOT NULL,
+    "namespace"        varchar(250) NOT NULL,
+    "group"            varchar(250) NOT NULL DEFAULT '',
+    "version"          varchar(50)  NOT NULL,
+    "kind"             varchar(100) NOT NULL,
+    "resource_name"    varchar(250) NOT NULL,
+    "action"           varchar(20)  NOT NULL,
+    "diff"             text,
+    "field_manager"    varchar(250),
+    "user_id"          integer,
+    "resource_version" varchar(50),
+    "changed_on"       timestamptz  NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS k8s_resource_change_namespace_changed_on_idx ON public.k8s_resource_change (cluster_id, namespace, changed_on);
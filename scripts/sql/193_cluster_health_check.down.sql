@@ -0,0 +1,6 @@
+delete from "public"."notification_templates" where event_type_id=5;
+delete from notifier_event_log where event_type_id=5;
+delete from public.event where event_type='CLUSTER_UNHEALTHY';
+
+DROP TABLE IF EXISTS public.cluster_health_check;
+DROP SEQUENCE IF EXISTS id_seq_cluster_health_check;
@@ -0,0 +1,44 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_cluster_health_check;
+
+CREATE TABLE IF NOT EXISTS public.cluster_health_check
+(
+    "id"                       integer      NOT NULL DEFAULT nextval('id_seq_cluster_health_check'::regclass),
+    "cluster_id"               integer      NOT NULL,
+    "status"                   varchar(50)  NOT NULL,
+    "reachable"                bool         NOT NULL,
+    "latency_ms"               bigint       NOT NULL DEFAULT 0,
+    "server_version"           varchar(100),
+    "kubelet_version_skew"     integer      NOT NULL DEFAULT 0,
+    "credential_expires_on"    timestamptz,
+    "metrics_server_available" bool         NOT NULL DEFAULT false,
+    "total_nodes"              integer      NOT NULL DEFAULT 0,
+    "not_ready_nodes"          integer      NOT NULL DEFAULT 0,
+    "argo_cd_status"           varchar(50),
+    "issues"                   text,
+    "checked_on"               timestamptz  NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS cluster_health_check_cluster_id_checked_on_idx ON public.cluster_health_check (cluster_id, checked_on);
+
+INSERT INTO public.event (id, event_type, description) VALUES (5, 'CLUSTER_UNHEALTHY', '');
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('slack', 'CLUSTER', 5, 'Cluster unhealthy slack template', '{
+    "text": ":red_circle: Cluster {{clusterName}} is unhealthy",
+    "blocks": [{
+        "type": "section",
+        "text": {
+            "type": "mrkdwn",
+            "text": ":red_circle: *Cluster {{clusterName}} is unhealthy*\n{{eventTime}}\n\n{{failureReason}}"
+        }
+    }]
+}');
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('smtp', 'CLUSTER', 5, 'Cluster unhealthy smtp template', '{"from": "{{fromEmail}}",
+"to": "{{toEmail}}","subject": "Cluster {{clusterName}} is unhealthy","html": "<h2>Cluster {{clusterName}} is unhealthy</h2><span>{{eventTime}}</span><br><br><span>{{failureReason}}</span>"}');
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('ses', 'CLUSTER', 5, 'Cluster unhealthy ses template', '{"from": "{{fromEmail}}",
+"to": "{{toEmail}}","subject": "Cluster {{clusterName}} is unhealthy","html": "<h2>Cluster {{clusterName}} is unhealthy</h2><span>{{eventTime}}</span><br><br><span>{{failureReason}}</span>"}');
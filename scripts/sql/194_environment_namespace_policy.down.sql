@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS public.environment_namespace_policy;
+DROP SEQUENCE IF EXISTS id_seq_environment_namespace_policy;
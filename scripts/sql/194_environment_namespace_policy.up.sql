@@ -0,0 +1,21 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_environment_namespace_policy;
+
+CREATE TABLE IF NOT EXISTS public.environment_namespace_policy
+(
+    "id"                 integer     NOT NULL DEFAULT nextval('id_seq_environment_namespace_policy'::regclass),
+    "environment_id"     integer     NOT NULL,
+    "policy"             text        NOT NULL,
+    "drift"              text,
+    "drift_detected_on"  timestamptz,
+    "reconcile_error"    text,
+    "last_reconciled_on" timestamptz,
+    "active"             bool        NOT NULL,
+    "created_on"         timestamptz NOT NULL,
+    "created_by"         integer     NOT NULL,
+    "updated_on"         timestamptz NOT NULL,
+    "updated_by"         integer     NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT environment_namespace_policy_environment_id_fkey FOREIGN KEY ("environment_id") REFERENCES "public"."environment" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS environment_namespace_policy_environment_id_idx ON public.environment_namespace_policy (environment_id) WHERE active = true;
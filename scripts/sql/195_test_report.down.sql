@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS public.ci_pipeline_test_report_config;
+DROP SEQUENCE IF EXISTS id_seq_ci_pipeline_test_report_config;
+DROP TABLE IF EXISTS public.test_report_case;
+DROP SEQUENCE IF EXISTS id_seq_test_report_case;
+DROP TABLE IF EXISTS public.test_report_suite;
+DROP SEQUENCE IF EXISTS id_seq_test_report_suite;
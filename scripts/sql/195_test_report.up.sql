@@ -0,0 +1,63 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_test_report_suite;
+
+CREATE TABLE IF NOT EXISTS public.test_report_suite
+(
+    "id"            integer      NOT NULL DEFAULT nextval('id_seq_test_report_suite'::regclass),
+    "workflow_type" varchar(20)  NOT NULL,
+    "workflow_id"   integer      NOT NULL,
+    "pipeline_id"   integer      NOT NULL,
+    "name"          text         NOT NULL,
+    "tests"         integer      NOT NULL DEFAULT 0,
+    "passed"        integer      NOT NULL DEFAULT 0,
+    "failures"      integer      NOT NULL DEFAULT 0,
+    "errors"        integer      NOT NULL DEFAULT 0,
+    "skipped"       integer      NOT NULL DEFAULT 0,
+    "duration_ms"   bigint       NOT NULL DEFAULT 0,
+    "created_on"    timestamptz  NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS test_report_suite_workflow_idx ON public.test_report_suite (workflow_type, workflow_id);
+CREATE INDEX IF NOT EXISTS test_report_suite_pipeline_idx ON public.test_report_suite (workflow_type, pipeline_id, workflow_id);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_test_report_case;
+
+CREATE TABLE IF NOT EXISTS public.test_report_case
+(
+    "id"              integer      NOT NULL DEFAULT nextval('id_seq_test_report_case'::regclass),
+    "suite_id"        integer      NOT NULL,
+    "workflow_type"   varchar(20)  NOT NULL,
+    "workflow_id"     integer      NOT NULL,
+    "pipeline_id"     integer      NOT NULL,
+    "class_name"      text,
+    "name"            text         NOT NULL,
+    "status"          varchar(20)  NOT NULL,
+    "duration_ms"     bigint       NOT NULL DEFAULT 0,
+    "failure_type"    text,
+    "failure_message" text,
+    "failure_details" text,
+    "created_on"      timestamptz  NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT test_report_case_suite_id_fkey FOREIGN KEY ("suite_id") REFERENCES "public"."test_report_suite" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS test_report_case_suite_id_idx ON public.test_report_case (suite_id);
+CREATE INDEX IF NOT EXISTS test_report_case_pipeline_idx ON public.test_report_case (workflow_type, pipeline_id, workflow_id);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_pipeline_test_report_config;
+
+CREATE TABLE IF NOT EXISTS public.ci_pipeline_test_report_config
+(
+    "id"             integer          NOT NULL DEFAULT nextval('id_seq_ci_pipeline_test_report_config'::regclass),
+    "ci_pipeline_id" integer          NOT NULL,
+    "min_pass_rate"  double precision NOT NULL DEFAULT 0,
+    "enabled"        bool             NOT NULL DEFAULT false,
+    "created_on"     timestamptz      NOT NULL,
+    "created_by"     integer          NOT NULL,
+    "updated_on"     timestamptz      NOT NULL,
+    "updated_by"     integer          NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT ci_pipeline_test_report_config_ci_pipeline_id_fkey FOREIGN KEY ("ci_pipeline_id") REFERENCES "public"."ci_pipeline" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ci_pipeline_test_report_config_ci_pipeline_id_idx ON public.ci_pipeline_test_report_config (ci_pipeline_id);
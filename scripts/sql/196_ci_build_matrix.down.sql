@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS public.ci_matrix_run_cell;
+DROP SEQUENCE IF EXISTS id_seq_ci_matrix_run_cell;
+DROP TABLE IF EXISTS public.ci_matrix_run;
+DROP SEQUENCE IF EXISTS id_seq_ci_matrix_run;
+DROP TABLE IF EXISTS public.ci_pipeline_build_matrix;
+DROP SEQUENCE IF EXISTS id_seq_ci_pipeline_build_matrix;
@@ -0,0 +1,59 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_pipeline_build_matrix;
+
+CREATE TABLE IF NOT EXISTS public.ci_pipeline_build_matrix
+(
+    "id"             integer     NOT NULL DEFAULT nextval('id_seq_ci_pipeline_build_matrix'::regclass),
+    "ci_pipeline_id" integer     NOT NULL,
+    "matrix"         text        NOT NULL,
+    "output_type"    varchar(50) NOT NULL,
+    "enabled"        bool        NOT NULL DEFAULT false,
+    "created_on"     timestamptz NOT NULL,
+    "created_by"     integer     NOT NULL,
+    "updated_on"     timestamptz NOT NULL,
+    "updated_by"     integer     NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT ci_pipeline_build_matrix_ci_pipeline_id_fkey FOREIGN KEY ("ci_pipeline_id") REFERENCES "public"."ci_pipeline" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ci_pipeline_build_matrix_ci_pipeline_id_idx ON public.ci_pipeline_build_matrix (ci_pipeline_id);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_matrix_run;
+
+CREATE TABLE IF NOT EXISTS public.ci_matrix_run
+(
+    "id"                 integer      NOT NULL DEFAULT nextval('id_seq_ci_matrix_run'::regclass),
+    "ci_pipeline_id"     integer      NOT NULL,
+    "output_type"        varchar(50)  NOT NULL,
+    "docker_registry_id" varchar(250),
+    "image"              text,
+    "image_digest"       varchar(250),
+    "manifest_status"    varchar(50)  NOT NULL,
+    "manifest_message"   text,
+    "triggered_by"       integer      NOT NULL,
+    "started_on"         timestamptz  NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT ci_matrix_run_ci_pipeline_id_fkey FOREIGN KEY ("ci_pipeline_id") REFERENCES "public"."ci_pipeline" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS ci_matrix_run_ci_pipeline_id_idx ON public.ci_matrix_run (ci_pipeline_id, id);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_matrix_run_cell;
+
+CREATE TABLE IF NOT EXISTS public.ci_matrix_run_cell
+(
+    "id"              integer      NOT NULL DEFAULT nextval('id_seq_ci_matrix_run_cell'::regclass),
+    "matrix_run_id"   integer      NOT NULL,
+    "ci_workflow_id"  integer      NOT NULL,
+    "cell_key"        varchar(128) NOT NULL,
+    "target_platform" varchar(100),
+    "build_args"      text,
+    "dockerfile_path" text,
+    "image"           text,
+    "image_digest"    varchar(250),
+    PRIMARY KEY ("id"),
+    CONSTRAINT ci_matrix_run_cell_matrix_run_id_fkey FOREIGN KEY ("matrix_run_id") REFERENCES "public"."ci_matrix_run" ("id"),
+    CONSTRAINT ci_matrix_run_cell_ci_workflow_id_fkey FOREIGN KEY ("ci_workflow_id") REFERENCES "public"."ci_workflow" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS ci_matrix_run_cell_matrix_run_id_idx ON public.ci_matrix_run_cell (matrix_run_id);
+CREATE UNIQUE INDEX IF NOT EXISTS ci_matrix_run_cell_ci_workflow_id_idx ON public.ci_matrix_run_cell (ci_workflow_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS public.ci_build_cache;
+DROP SEQUENCE IF EXISTS id_seq_ci_build_cache;
+DROP TABLE IF EXISTS public.ci_pipeline_build_cache_config;
+DROP SEQUENCE IF EXISTS id_seq_ci_pipeline_build_cache_config;
@@ -0,0 +1,41 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_pipeline_build_cache_config;
+
+CREATE TABLE IF NOT EXISTS public.ci_pipeline_build_cache_config
+(
+    "id"               integer     NOT NULL DEFAULT nextval('id_seq_ci_pipeline_build_cache_config'::regclass),
+    "ci_pipeline_id"   integer     NOT NULL,
+    "backend"          varchar(50) NOT NULL,
+    "scope"            varchar(50) NOT NULL,
+    "mode"             varchar(10) NOT NULL,
+    "cache_repository" text,
+    "created_on"       timestamptz NOT NULL,
+    "created_by"       integer     NOT NULL,
+    "updated_on"       timestamptz NOT NULL,
+    "updated_by"       integer     NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT ci_pipeline_build_cache_config_ci_pipeline_id_fkey FOREIGN KEY ("ci_pipeline_id") REFERENCES "public"."ci_pipeline" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ci_pipeline_build_cache_config_ci_pipeline_id_idx ON public.ci_pipeline_build_cache_config (ci_pipeline_id);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_build_cache;
+
+CREATE TABLE IF NOT EXISTS public.ci_build_cache
+(
+    "id"                  integer      NOT NULL DEFAULT nextval('id_seq_ci_build_cache'::regclass),
+    "app_id"              integer      NOT NULL,
+    "ci_pipeline_id"      integer      NOT NULL,
+    "scope"               varchar(50)  NOT NULL,
+    "docker_registry_id"  varchar(250) NOT NULL,
+    "repository"          text         NOT NULL,
+    "tag"                 varchar(128) NOT NULL,
+    "cache_ref"           text         NOT NULL,
+    "last_ci_workflow_id" integer,
+    "last_used_on"        timestamptz  NOT NULL,
+    "created_on"          timestamptz  NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT ci_build_cache_ci_pipeline_id_fkey FOREIGN KEY ("ci_pipeline_id") REFERENCES "public"."ci_pipeline" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ci_build_cache_cache_ref_idx ON public.ci_build_cache (cache_ref);
+CREATE INDEX IF NOT EXISTS ci_build_cache_app_id_idx ON public.ci_build_cache (app_id);
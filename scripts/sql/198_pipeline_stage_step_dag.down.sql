@@ -0,0 +1,2 @@
+ALTER TABLE pipeline_stage_step DROP COLUMN IF EXISTS depends_on_step_indexes;
+ALTER TABLE pipeline_stage DROP COLUMN IF EXISTS execution_mode;
@@ -0,0 +1,2 @@
+ALTER TABLE pipeline_stage ADD COLUMN IF NOT EXISTS execution_mode varchar(20);
+ALTER TABLE pipeline_stage_step ADD COLUMN IF NOT EXISTS depends_on_step_indexes integer[];
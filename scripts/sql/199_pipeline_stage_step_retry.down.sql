@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS public.workflow_step_status;
+DROP SEQUENCE IF EXISTS id_seq_workflow_step_status;
+
+ALTER TABLE pipeline_stage_step DROP COLUMN IF EXISTS continue_on_failure;
+ALTER TABLE pipeline_stage_step DROP COLUMN IF EXISTS timeout_seconds;
+ALTER TABLE pipeline_stage_step DROP COLUMN IF EXISTS retry_backoff_seconds;
+ALTER TABLE pipeline_stage_step DROP COLUMN IF EXISTS max_retries;
@@ -0,0 +1,26 @@
+ALTER TABLE pipeline_stage_step ADD COLUMN IF NOT EXISTS max_retries integer NOT NULL DEFAULT 0;
+ALTER TABLE pipeline_stage_step ADD COLUMN IF NOT EXISTS retry_backoff_seconds integer NOT NULL DEFAULT 0;
+ALTER TABLE pipeline_stage_step ADD COLUMN IF NOT EXISTS timeout_seconds integer NOT NULL DEFAULT 0;
+ALTER TABLE pipeline_stage_step ADD COLUMN IF NOT EXISTS continue_on_failure bool NOT NULL DEFAULT false;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_workflow_step_status;
+
+CREATE TABLE IF NOT EXISTS public.workflow_step_status
+(
+    "id"                  integer      NOT NULL DEFAULT nextval('id_seq_workflow_step_status'::regclass),
+    "workflow_type"       varchar(20)  NOT NULL,
+    "workflow_id"         integer      NOT NULL,
+    "stage_type"          varchar(20)  NOT NULL,
+    "step_index"          integer      NOT NULL,
+    "step_name"           text,
+    "status"              varchar(20)  NOT NULL,
+    "attempts"            integer      NOT NULL DEFAULT 1,
+    "continue_on_failure" bool         NOT NULL DEFAULT false,
+    "started_on"          timestamptz,
+    "finished_on"         timestamptz,
+    "message"             text,
+    "created_on"          timestamptz  NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS workflow_step_status_workflow_idx ON public.workflow_step_status (workflow_type, workflow_id);
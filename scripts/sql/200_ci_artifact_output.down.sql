@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS public.ci_artifact_output;
+DROP SEQUENCE IF EXISTS id_seq_ci_artifact_output;
@@ -0,0 +1,18 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_artifact_output;
+
+CREATE TABLE IF NOT EXISTS public.ci_artifact_output
+(
+    "id"             integer      NOT NULL DEFAULT nextval('id_seq_ci_artifact_output'::regclass),
+    "ci_artifact_id" integer      NOT NULL,
+    "stage_type"     varchar(20)  NOT NULL,
+    "step_index"     integer      NOT NULL,
+    "step_name"      text,
+    "name"           varchar(250) NOT NULL,
+    "output_type"    varchar(20)  NOT NULL,
+    "format"         varchar(20),
+    "value"          text,
+    "created_on"     timestamptz  NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS ci_artifact_output_ci_artifact_id_idx ON public.ci_artifact_output (ci_artifact_id);
@@ -0,0 +1,7 @@
+ALTER TABLE public.audit_event_sink_cursor ALTER COLUMN "last_stream_seq" TYPE integer;
+ALTER TABLE public.audit_event_sink_cursor RENAME COLUMN "last_stream_seq" TO "last_event_id";
+
+DROP INDEX IF EXISTS public.audit_event_unsequenced_idx;
+DROP INDEX IF EXISTS public.audit_event_stream_seq_idx;
+ALTER TABLE public.audit_event DROP COLUMN IF EXISTS "stream_seq";
+DROP SEQUENCE IF EXISTS public.id_seq_audit_event_stream;
@@ -0,0 +1,13 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_audit_event_stream;
+
+ALTER TABLE public.audit_event ADD COLUMN IF NOT EXISTS "stream_seq" bigint;
+
+-- events recorded so far are sequenced in id order so the existing sink cursors stay valid
+UPDATE public.audit_event SET stream_seq = id WHERE stream_seq IS NULL;
+SELECT setval('id_seq_audit_event_stream', COALESCE((SELECT MAX(id) FROM public.audit_event), 0) + 1, false);
+
+CREATE UNIQUE INDEX IF NOT EXISTS audit_event_stream_seq_idx ON public.audit_event (stream_seq);
+CREATE INDEX IF NOT EXISTS audit_event_unsequenced_idx ON public.audit_event (id) WHERE stream_seq IS NULL;
+
+ALTER TABLE public.audit_event_sink_cursor RENAME COLUMN "last_event_id" TO "last_stream_seq";
+ALTER TABLE public.audit_event_sink_cursor ALTER COLUMN "last_stream_seq" TYPE bigint;
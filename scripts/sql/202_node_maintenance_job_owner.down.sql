@@ -0,0 +1,2 @@
+ALTER TABLE public.node_maintenance_job DROP COLUMN IF EXISTS "heartbeat_on";
+ALTER TABLE public.node_maintenance_job DROP COLUMN IF EXISTS "owner_id";
@@ -0,0 +1,2 @@
+ALTER TABLE public.node_maintenance_job ADD COLUMN IF NOT EXISTS "owner_id" varchar(250);
+ALTER TABLE public.node_maintenance_job ADD COLUMN IF NOT EXISTS "heartbeat_on" timestamptz;
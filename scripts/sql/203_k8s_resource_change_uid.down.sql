@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS public.k8s_resource_change_uid_version_action_idx;
+ALTER TABLE public.k8s_resource_change DROP COLUMN IF EXISTS "resource_uid";
@@ -0,0 +1,4 @@
+ALTER TABLE public.k8s_resource_change ADD COLUMN IF NOT EXISTS "resource_uid" varchar(50);
+
+-- every replica watching a cluster observes the same change, only the first one to save it is kept
+CREATE UNIQUE INDEX IF NOT EXISTS k8s_resource_change_uid_version_action_idx ON public.k8s_resource_change (cluster_id, resource_uid, resource_version, action);
@@ -0,0 +1,88 @@
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"github.com/caarlos0/env"
+	"io"
+)
+
+type SecretEncryptionConfig struct {
+	// SecretEncryptionKey encrypts secrets stored in db, changing it makes the stored secrets unreadable
+	SecretEncryptionKey string `env:"SECRET_ENCRYPTION_KEY" envDefault:""`
+}
+
+func getSecretEncryptionKey() ([]byte, error) {
+	cfg := &SecretEncryptionConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.SecretEncryptionKey) == 0 {
+		return nil, fmt.Errorf("SECRET_ENCRYPTION_KEY is not set, it is required to store secrets")
+	}
+	key := sha256.Sum256([]byte(cfg.SecretEncryptionKey))
+	return key[:], nil
+}
+
+// EncryptSecret encrypts plainText with AES-GCM using SECRET_ENCRYPTION_KEY, the nonce is prefixed to the base64 encoded result
+func EncryptSecret(plainText string) (string, error) {
+	key, err := getSecretEncryptionKey()
+	if err != nil {
+		return "", err
+	}
+	return encryptWithKey(key, plainText)
+}
+
+// DecryptSecret decrypts a value encrypted by EncryptSecret
+func DecryptSecret(cipherText string) (string, error) {
+	key, err := getSecretEncryptionKey()
+	if err != nil {
+		return "", err
+	}
+	return decryptWithKey(key, cipherText)
+}
+
+func encryptWithKey(key []byte, plainText string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plainText), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func decryptWithKey(key []byte, cipherText string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(cipherText)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("encrypted secret is malformed")
+	}
+	plainText, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plainText), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,29 @@
+package util
+
+import (
+	"os"
+	"testing"
+)
+
+func TestEncryptSecret(t *testing.T) {
+	os.Setenv("SECRET_ENCRYPTION_KEY", "test-key")
+	defer os.Unsetenv("SECRET_ENCRYPTION_KEY")
+	encrypted, err := EncryptSecret("signing-key")
+	if err != nil {
+		t.Fatalf("EncryptSecret() error = %v", err)
+	}
+	if encrypted == "signing-key" {
+		t.Errorf("EncryptSecret() returned the plain text")
+	}
+	decrypted, err := DecryptSecret(encrypted)
+	if err != nil {
+		t.Fatalf("DecryptSecret() error = %v", err)
+	}
+	if decrypted != "signing-key" {
+		t.Errorf("DecryptSecret() got = %v, want %v", decrypted, "signing-key")
+	}
+	os.Setenv("SECRET_ENCRYPTION_KEY", "other-key")
+	if _, err = DecryptSecret(encrypted); err == nil {
+		t.Errorf("DecryptSecret() with a different key should fail")
+	}
+}
@@ -9,6 +9,9 @@ import (
 type GlobalEnvVariables struct {
 	GitOpsRepoPrefix     string `env:"GITOPS_REPO_PREFIX" envDefault:""`
 	SkipGitOpsValidation bool   `env:"SKIP_GITOPS_VALIDATION" envDefault:"false"`
+	// GitOpsRepoLayout is one of REPO_PER_APP, MONOREPO or MONOREPO_PER_TEAM, it decides the repository new apps are committed to
+	GitOpsRepoLayout   string `env:"GITOPS_REPO_LAYOUT" envDefault:"REPO_PER_APP"`
+	GitOpsMonorepoName string `env:"GITOPS_MONOREPO_NAME" envDefault:"devtron-gitops"`
 }
 
 func GetGlobalEnvVariables() (*GlobalEnvVariables, error) {
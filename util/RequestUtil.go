@@ -18,7 +18,9 @@
 package util
 
 import (
+	"net"
 	"net/http"
+	"strings"
 )
 
 const xForwardedForHeaderName = "X-Forwarded-For"
@@ -32,3 +34,31 @@ func GetClientIP(r *http.Request) string {
 	}
 	return r.RemoteAddr
 }
+
+// GetClientIPBehindTrustedProxies returns the ip of the client which can be relied upon for access checks.
+// X-Forwarded-For is set by the client and can be forged, so only the hops appended by the trusted proxies
+// in front of the server are considered: with n trusted proxies the client is the n-th entry from the right.
+// With no trusted proxies the address of the connection is used.
+func GetClientIPBehindTrustedProxies(r *http.Request, trustedProxyCount int) string {
+	clientIp := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(clientIp); err == nil {
+		clientIp = host
+	}
+	if trustedProxyCount <= 0 {
+		return clientIp
+	}
+	var hops []string
+	for _, xForwardedFor := range r.Header.Values(xForwardedForHeaderName) {
+		for _, hop := range strings.Split(xForwardedFor, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+	if len(hops) == 0 {
+		return clientIp
+	}
+	if len(hops) < trustedProxyCount {
+		// request has passed through fewer proxies than configured, the leftmost hop is added by a trusted proxy
+		return hops[0]
+	}
+	return hops[len(hops)-trustedProxyCount]
+}
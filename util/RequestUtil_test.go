@@ -0,0 +1,35 @@
+package util
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetClientIPBehindTrustedProxies(t *testing.T) {
+	tests := []struct {
+		name              string
+		xForwardedFor     []string
+		trustedProxyCount int
+		want              string
+	}{
+		{name: "no proxy ignores forged header", xForwardedFor: []string{"10.0.0.1"}, want: "192.168.1.5"},
+		{name: "no proxy without header", want: "192.168.1.5"},
+		{name: "one proxy takes rightmost hop", xForwardedFor: []string{"10.0.0.1, 203.0.113.7"}, trustedProxyCount: 1, want: "203.0.113.7"},
+		{name: "two proxies skip inner hop", xForwardedFor: []string{"10.0.0.1, 203.0.113.7, 172.16.0.2"}, trustedProxyCount: 2, want: "203.0.113.7"},
+		{name: "multiple headers are one chain", xForwardedFor: []string{"10.0.0.1", "203.0.113.7"}, trustedProxyCount: 1, want: "203.0.113.7"},
+		{name: "fewer hops than proxies", xForwardedFor: []string{"203.0.113.7"}, trustedProxyCount: 2, want: "203.0.113.7"},
+		{name: "proxy configured without header", trustedProxyCount: 1, want: "192.168.1.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/orchestrator/app", nil)
+			r.RemoteAddr = "192.168.1.5:43210"
+			for _, xForwardedFor := range tt.xForwardedFor {
+				r.Header.Add(xForwardedForHeaderName, xForwardedFor)
+			}
+			if got := GetClientIPBehindTrustedProxies(r, tt.trustedProxyCount); got != tt.want {
+				t.Errorf("GetClientIPBehindTrustedProxies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
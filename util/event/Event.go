@@ -22,11 +22,13 @@ type EventType int
 const Trigger EventType = 1
 const Success EventType = 2
 const Fail EventType = 3
+const ClusterUnhealthy EventType = 5
 
 type PipelineType string
 
 const CI PipelineType = "CI"
 const CD PipelineType = "CD"
+const CLUSTER PipelineType = "CLUSTER"
 
 type Level string
 
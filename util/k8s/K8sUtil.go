@@ -944,6 +944,14 @@ func (impl K8sUtil) GetNmByName(ctx context.Context, metricsClientSet *metrics.C
 	}
 	return nodeMetrics, err
 }
+func (impl K8sUtil) GetPodMetricsList(ctx context.Context, metricsClientSet *metrics.Clientset, namespace string) (*v1beta1.PodMetricsList, error) {
+	podMetricsList, err := metricsClientSet.MetricsV1beta1().PodMetricses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		impl.logger.Errorw("error in getting pod metrics", "err", err, "namespace", namespace)
+		return nil, err
+	}
+	return podMetricsList, err
+}
 func (impl K8sUtil) GetMetricsClientSet(restConfig *rest.Config, k8sHttpClient *http.Client) (*metrics.Clientset, error) {
 	metricsClientSet, err := metrics.NewForConfigAndClient(restConfig, k8sHttpClient)
 	if err != nil {